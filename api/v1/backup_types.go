@@ -17,7 +17,10 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 )
 
 // BackupPhase is the phase of the backup
@@ -43,10 +46,253 @@ const (
 	BackupPhaseWalArchivingFailing = "walArchivingFailing"
 )
 
+// BackupWALCheckPhase is the phase of the WAL check of a backup
+type BackupWALCheckPhase string
+
+const (
+	// BackupWALCheckPhaseRunning means that the WAL check is running
+	BackupWALCheckPhaseRunning = "running"
+
+	// BackupWALCheckPhasePassed means that the WAL files where the backup
+	// started and ended can be retrieved from the object store
+	BackupWALCheckPhasePassed = "passed"
+
+	// BackupWALCheckPhaseFailed means that the WAL files where the backup
+	// started and ended can't be retrieved from the object store
+	BackupWALCheckPhaseFailed = "failed"
+)
+
 // BackupSpec defines the desired state of Backup
 type BackupSpec struct {
 	// The cluster to backup
 	Cluster LocalObjectReference `json:"cluster,omitempty"`
+
+	// When true, once the backup is completed the instance manager
+	// checks that the WAL files where it started and ended can be
+	// retrieved from the object store, storing the result in the
+	// `walCheck` section of the status. Defaults to `false`
+	// +optional
+	WALCheck bool `json:"walCheck,omitempty"`
+
+	// The hooks executed in the instance taking the backup
+	// before and after the backup
+	// +optional
+	Hooks *BackupHooks `json:"hooks,omitempty"`
+
+	// The method used to take the backup: it can be `barmanObjectStore`
+	// (default), using the object store configured in the cluster,
+	// `plugin`, delegating the backup to a plugin, or `physical`, storing
+	// a tarball of the data directory in a persistent volume
+	// +kubebuilder:validation:Enum=barmanObjectStore;plugin;physical
+	// +kubebuilder:default:=barmanObjectStore
+	// +optional
+	Method BackupMethod `json:"method,omitempty"`
+
+	// The plugin taking the backup, required by the `plugin` method
+	// +optional
+	PluginConfiguration *PluginConfiguration `json:"pluginConfiguration,omitempty"`
+
+	// Where the tarball of the backup is stored, required
+	// by the `physical` method
+	// +optional
+	Physical *PhysicalBackupConfiguration `json:"physical,omitempty"`
+}
+
+// PhysicalBackupConfiguration is the configuration of a backup
+// taken with the `physical` method
+type PhysicalBackupConfiguration struct {
+	// The name of the persistent volume claim, in the namespace of
+	// the cluster, where the tarball of the backup is written
+	VolumeClaimName string `json:"volumeClaimName"`
+
+	// When true, an immediate checkpoint is requested when the
+	// backup starts, instead of a spread one. Defaults to `false`
+	// +optional
+	Fast bool `json:"fast,omitempty"`
+}
+
+// BackupMethod is the method used to take a backup
+type BackupMethod string
+
+const (
+	// BackupMethodBarmanObjectStore means that the backup is taken with
+	// barman-cloud, using the object store configured in the cluster
+	BackupMethodBarmanObjectStore = BackupMethod("barmanObjectStore")
+
+	// BackupMethodPlugin means that the backup is taken by a plugin
+	BackupMethodPlugin = BackupMethod("plugin")
+
+	// BackupMethodPhysical means that the backup is a tarball of the data
+	// directory, streamed by the instance manager to a Job writing it
+	// in a persistent volume
+	BackupMethodPhysical = BackupMethod("physical")
+)
+
+// GetMethod gets the method used to take the backup
+func (spec BackupSpec) GetMethod() BackupMethod {
+	if spec.Method == "" {
+		return BackupMethodBarmanObjectStore
+	}
+
+	return spec.Method
+}
+
+// BackupHookFailurePolicy is the action taken when a hook fails
+type BackupHookFailurePolicy string
+
+const (
+	// BackupHookFailurePolicyFail means that the backup fails
+	// when a pre-backup hook fails
+	BackupHookFailurePolicyFail = BackupHookFailurePolicy("fail")
+
+	// BackupHookFailurePolicyIgnore means that the backup is taken
+	// even when a pre-backup hook fails
+	BackupHookFailurePolicyIgnore = BackupHookFailurePolicy("ignore")
+)
+
+// defaultBackupHookTimeout is the timeout of a backup hook
+// not specifying it
+const defaultBackupHookTimeout = 5 * time.Minute
+
+// BackupHooks contains the hooks executed before and after a backup
+type BackupHooks struct {
+	// The hooks executed, in order, before starting the backup
+	// +optional
+	PreBackup []BackupHook `json:"preBackup,omitempty"`
+
+	// The hooks executed, in order, after the backup is
+	// terminated, either successfully or not
+	// +optional
+	PostBackup []BackupHook `json:"postBackup,omitempty"`
+
+	// The action taken when a pre-backup hook fails: `fail` (default)
+	// marks the backup as failed without taking it, `ignore` takes
+	// the backup anyway. The failures of the post-backup hooks are
+	// always reported as events without changing the backup result
+	// +kubebuilder:validation:Enum=fail;ignore
+	// +kubebuilder:default:=fail
+	// +optional
+	FailurePolicy BackupHookFailurePolicy `json:"failurePolicy,omitempty"`
+}
+
+// BackupHook is a SQL statement or a command executed in the
+// instance taking the backup. Exactly one of them must be specified
+type BackupHook struct {
+	// The SQL statement executed as the superuser
+	// +optional
+	SQL string `json:"sql,omitempty"`
+
+	// The database where the SQL statement is executed,
+	// defaults to `postgres`
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// The command executed in the PostgreSQL container
+	// +optional
+	Command []string `json:"command,omitempty"`
+
+	// The maximum duration of the hook, defaults to 5 minutes
+	// +optional
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// GetDatabase gets the database where the SQL statement of the hook is executed
+func (hook BackupHook) GetDatabase() string {
+	if hook.Database == "" {
+		return "postgres"
+	}
+	return hook.Database
+}
+
+// GetTimeout gets the maximum duration of the hook
+func (hook BackupHook) GetTimeout() time.Duration {
+	if hook.Timeout == nil || hook.Timeout.Duration <= 0 {
+		return defaultBackupHookTimeout
+	}
+	return hook.Timeout.Duration
+}
+
+// IsEmpty checks if no hook is defined
+func (hooks *BackupHooks) IsEmpty() bool {
+	return hooks == nil || (len(hooks.PreBackup) == 0 && len(hooks.PostBackup) == 0)
+}
+
+// IsFailureIgnored checks if the backup should be taken even
+// when a pre-backup hook fails
+func (hooks *BackupHooks) IsFailureIgnored() bool {
+	return hooks.FailurePolicy == BackupHookFailurePolicyIgnore
+}
+
+// validate validates the backup hooks
+func (hooks *BackupHooks) validate(path *field.Path) field.ErrorList {
+	var result field.ErrorList
+	if hooks == nil {
+		return result
+	}
+
+	validateList := func(listPath *field.Path, list []BackupHook) {
+		for idx, hook := range list {
+			hasSQL := hook.SQL != ""
+			hasCommand := len(hook.Command) > 0
+			if hasSQL == hasCommand {
+				result = append(result, field.Invalid(
+					listPath.Index(idx), hook,
+					"exactly one of sql and command must be specified"))
+			}
+			if hasCommand && hook.Database != "" {
+				result = append(result, field.Invalid(
+					listPath.Index(idx).Child("database"), hook.Database,
+					"the database can only be specified for SQL hooks"))
+			}
+		}
+	}
+	validateList(path.Child("preBackup"), hooks.PreBackup)
+	validateList(path.Child("postBackup"), hooks.PostBackup)
+
+	return result
+}
+
+// validateBackupMethod validates the method used to take a backup
+// and the configuration of the plugin or of the volume it needs
+func validateBackupMethod(
+	method BackupMethod,
+	pluginConfiguration *PluginConfiguration,
+	physical *PhysicalBackupConfiguration,
+	path *field.Path,
+) field.ErrorList {
+	var result field.ErrorList
+
+	switch {
+	case method == BackupMethodPlugin && pluginConfiguration == nil:
+		result = append(result, field.Required(
+			path.Child("pluginConfiguration"),
+			"the plugin configuration is required by the plugin method"))
+	case method != BackupMethodPlugin && pluginConfiguration != nil:
+		result = append(result, field.Invalid(
+			path.Child("pluginConfiguration"), pluginConfiguration.Name,
+			"the plugin configuration can only be used with the plugin method"))
+	case pluginConfiguration != nil && pluginConfiguration.Name == "":
+		result = append(result, field.Required(
+			path.Child("pluginConfiguration", "name"),
+			"the name of the plugin is required"))
+	}
+
+	switch {
+	case method == BackupMethodPhysical && physical == nil:
+		result = append(result, field.Required(
+			path.Child("physical"),
+			"the physical configuration is required by the physical method"))
+	case method != BackupMethodPhysical && physical != nil:
+		result = append(result, field.Invalid(
+			path.Child("physical"), physical.VolumeClaimName,
+			"the physical configuration can only be used with the physical method"))
+	case physical != nil && physical.VolumeClaimName == "":
+		result = append(result, field.Required(
+			path.Child("physical", "volumeClaimName"),
+			"the name of the persistent volume claim is required"))
+	}
+
+	return result
 }
 
 // BackupStatus defines the observed state of Backup
@@ -108,8 +354,91 @@ type BackupStatus struct {
 	// The backup command output in case of error
 	CommandError string `json:"commandError,omitempty"`
 
+	// The result of the WAL check of the backup, if requested
+	// +optional
+	WALCheck *BackupWALCheckStatus `json:"walCheck,omitempty"`
+
+	// The copies of the backup uploaded in the additional
+	// object stores of the cluster
+	// +optional
+	AdditionalBackups []AdditionalBackupStatus `json:"additionalBackups,omitempty"`
+
 	// Information to identify the instance where the backup has been taken from
 	InstanceID *InstanceID `json:"instanceID,omitempty"`
+
+	// The progress of the upload of the base backup, refreshed while
+	// the backup is running
+	// +optional
+	Progress *BackupProgress `json:"progress,omitempty"`
+}
+
+// BackupProgress describes the progress of the upload of a base backup.
+// The values are estimated from the data read by barman-cloud-backup and
+// from the size of the data directory when the backup started
+type BackupProgress struct {
+	// The number of bytes read from the data directory so far
+	BytesCopied int64 `json:"bytesCopied"`
+
+	// The estimated size of the backup, given by the size of
+	// the data directory, excluding the WAL files
+	// +optional
+	TotalBytes int64 `json:"totalBytes,omitempty"`
+
+	// The estimated completion percentage, which reaches 100
+	// only when the backup is completed
+	// +optional
+	Percentage int32 `json:"percentage,omitempty"`
+
+	// The file of the data directory being read
+	// +optional
+	CurrentFile string `json:"currentFile,omitempty"`
+
+	// The number of bytes read per second since the previous update
+	// or, when the backup is completed, on average
+	// +optional
+	BytesPerSecond int64 `json:"bytesPerSecond,omitempty"`
+
+	// When the progress has been updated, stored as a date in RFC3339 format
+	// +optional
+	LastUpdateTime string `json:"lastUpdateTime,omitempty"`
+}
+
+// BackupWALCheckStatus contains the result of the WAL check of a
+// backup, which is passed when the WAL files where the backup started
+// and ended can be retrieved from the object store
+type BackupWALCheckStatus struct {
+	// The phase of the WAL check
+	Phase BackupWALCheckPhase `json:"phase,omitempty"`
+
+	// When the WAL check was started
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// When the WAL check was terminated
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+
+	// The detected error, if the WAL check failed
+	Error string `json:"error,omitempty"`
+}
+
+// AdditionalBackupStatus describes the copy of a backup
+// uploaded in an additional object store
+type AdditionalBackupStatus struct {
+	// The name of the additional object store
+	Name string `json:"name"`
+
+	// The path where the backup is stored
+	DestinationPath string `json:"destinationPath"`
+
+	// The server name in the object store
+	ServerName string `json:"serverName,omitempty"`
+
+	// The ID of the Barman backup
+	BackupID string `json:"backupId,omitempty"`
+
+	// The error raised uploading the backup to the object store. The
+	// failures of the additional object stores don't fail the backup
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // InstanceID contains the information to identify an instance
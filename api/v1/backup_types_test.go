@@ -0,0 +1,159 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backup hooks", func() {
+	path := field.NewPath("spec", "hooks")
+
+	It("accepts missing hooks", func() {
+		var hooks *BackupHooks
+		Expect(hooks.validate(path)).To(BeEmpty())
+	})
+
+	It("accepts SQL and command hooks", func() {
+		hooks := &BackupHooks{
+			PreBackup:  []BackupHook{{SQL: "SELECT 1", Database: "app"}},
+			PostBackup: []BackupHook{{Command: []string{"sync"}}},
+		}
+		Expect(hooks.validate(path)).To(BeEmpty())
+	})
+
+	It("complains when a hook has neither SQL nor a command, or both", func() {
+		hooks := &BackupHooks{
+			PreBackup:  []BackupHook{{}},
+			PostBackup: []BackupHook{{SQL: "SELECT 1", Command: []string{"sync"}}},
+		}
+		Expect(hooks.validate(path)).To(HaveLen(2))
+	})
+
+	It("complains when a command hook specifies a database", func() {
+		hooks := &BackupHooks{
+			PreBackup: []BackupHook{{Command: []string{"sync"}, Database: "app"}},
+		}
+		Expect(hooks.validate(path)).To(HaveLen(1))
+	})
+
+	It("applies the defaults", func() {
+		hook := BackupHook{SQL: "SELECT 1"}
+		Expect(hook.GetDatabase()).To(Equal("postgres"))
+		Expect(hook.GetTimeout()).To(Equal(defaultBackupHookTimeout))
+
+		hook.Timeout = &metav1.Duration{Duration: time.Minute}
+		Expect(hook.GetTimeout()).To(Equal(time.Minute))
+	})
+
+	It("fails the backup on hook failures by default", func() {
+		Expect((&BackupHooks{}).IsFailureIgnored()).To(BeFalse())
+		Expect((&BackupHooks{FailurePolicy: BackupHookFailurePolicyIgnore}).IsFailureIgnored()).To(BeTrue())
+	})
+})
+
+var _ = Describe("Backup hooks permission", func() {
+	path := field.NewPath("spec", "hooks")
+	hooks := &BackupHooks{PreBackup: []BackupHook{{SQL: "SELECT 1"}}}
+
+	useCluster := func(allowHooks bool) {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).To(Succeed())
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec:       ClusterSpec{Backup: &BackupConfiguration{AllowHooks: allowHooks}},
+		}
+
+		previousReader := backupHooksClusterReader
+		backupHooksClusterReader = fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build()
+		DeferCleanup(func() {
+			backupHooksClusterReader = previousReader
+		})
+	}
+
+	It("accepts backups without hooks", func() {
+		useCluster(false)
+		Expect(validateHooksAllowed("default", "cluster-example", nil, path)).To(BeEmpty())
+		Expect(validateHooksAllowed("default", "cluster-example", &BackupHooks{}, path)).To(BeEmpty())
+	})
+
+	It("accepts the hooks when the cluster allows them", func() {
+		useCluster(true)
+		Expect(validateHooksAllowed("default", "cluster-example", hooks, path)).To(BeEmpty())
+	})
+
+	It("refuses the hooks when the cluster doesn't allow them", func() {
+		useCluster(false)
+		result := validateHooksAllowed("default", "cluster-example", hooks, path)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Type).To(Equal(field.ErrorTypeForbidden))
+	})
+
+	It("refuses the hooks when the cluster doesn't exist", func() {
+		useCluster(true)
+		Expect(validateHooksAllowed("default", "another-cluster", hooks, path)).To(HaveLen(1))
+	})
+
+	It("checks the hooks of an updated backup only when they change", func() {
+		useCluster(false)
+		backup := &Backup{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup", Namespace: "default"},
+			Spec:       BackupSpec{Cluster: LocalObjectReference{Name: "cluster-example"}, Hooks: hooks},
+		}
+		Expect(backup.ValidateCreate()).ToNot(Succeed())
+		Expect(backup.ValidateUpdate(backup.DeepCopy())).To(Succeed())
+	})
+})
+
+var _ = Describe("Backup method", func() {
+	path := field.NewPath("spec")
+
+	It("uses the object store by default", func() {
+		Expect(BackupSpec{}.GetMethod()).To(Equal(BackupMethodBarmanObjectStore))
+		Expect(BackupSpec{Method: BackupMethodPlugin}.GetMethod()).To(Equal(BackupMethodPlugin))
+	})
+
+	It("requires the plugin configuration with the plugin method", func() {
+		Expect(validateBackupMethod(BackupMethodPlugin, nil, nil, path)).To(HaveLen(1))
+		Expect(validateBackupMethod(BackupMethodPlugin, &PluginConfiguration{}, nil, path)).To(HaveLen(1))
+		Expect(validateBackupMethod(BackupMethodPlugin, &PluginConfiguration{Name: "backup"}, nil, path)).
+			To(BeEmpty())
+	})
+
+	It("complains about a plugin configuration without the plugin method", func() {
+		Expect(validateBackupMethod("", &PluginConfiguration{Name: "backup"}, nil, path)).To(HaveLen(1))
+		Expect(validateBackupMethod("", nil, nil, path)).To(BeEmpty())
+	})
+
+	It("requires the volume claim with the physical method", func() {
+		Expect(validateBackupMethod(BackupMethodPhysical, nil, nil, path)).To(HaveLen(1))
+		Expect(validateBackupMethod(BackupMethodPhysical, nil, &PhysicalBackupConfiguration{}, path)).
+			To(HaveLen(1))
+		Expect(validateBackupMethod(BackupMethodPhysical, nil,
+			&PhysicalBackupConfiguration{VolumeClaimName: "backups"}, path)).To(BeEmpty())
+		Expect(validateBackupMethod("", nil,
+			&PhysicalBackupConfiguration{VolumeClaimName: "backups"}, path)).To(HaveLen(1))
+	})
+})
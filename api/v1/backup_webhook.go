@@ -17,8 +17,18 @@ limitations under the License.
 package v1
 
 import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -27,8 +37,17 @@ import (
 // backupLog is for logging in this package.
 var backupLog = log.WithName("backup-resource").WithValues("version", "v1")
 
+// backupHooksClusterReader is used by the Backup and ScheduledBackup
+// webhooks to check if the cluster allows running the backup hooks
+var backupHooksClusterReader client.Reader
+
+// backupHooksClusterTimeout is the timeout used by the webhooks
+// to read the cluster running the backup hooks
+const backupHooksClusterTimeout = 10 * time.Second
+
 // SetupWebhookWithManager setup the webhook inside the controller manager
 func (r *Backup) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	backupHooksClusterReader = mgr.GetAPIReader()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -51,13 +70,32 @@ var _ webhook.Validator = &Backup{}
 // ValidateCreate implements webhook.Validator so a webhook will be registered for the type
 func (r *Backup) ValidateCreate() error {
 	backupLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
-	return nil
+	return r.validate(nil)
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *Backup) ValidateUpdate(old runtime.Object) error {
 	backupLog.Info("validate update", "name", r.Name, "namespace", r.Namespace)
-	return nil
+	return r.validate(old.(*Backup).Spec.Hooks)
+}
+
+// validate validates the backup specification, checking that the cluster
+// allows the hooks unless they are the same as the old ones
+func (r *Backup) validate(oldHooks *BackupHooks) error {
+	allErrs := r.Spec.Hooks.validate(field.NewPath("spec", "hooks"))
+	if oldHooks == nil || !equality.Semantic.DeepEqual(r.Spec.Hooks, oldHooks) {
+		allErrs = append(allErrs,
+			validateHooksAllowed(r.Namespace, r.Spec.Cluster.Name, r.Spec.Hooks, field.NewPath("spec", "hooks"))...)
+	}
+	allErrs = append(allErrs,
+		validateBackupMethod(r.Spec.Method, r.Spec.PluginConfiguration, r.Spec.Physical, field.NewPath("spec"))...)
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: "Backup"},
+		r.Name, allErrs)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
@@ -65,3 +103,31 @@ func (r *Backup) ValidateDelete() error {
 	backupLog.Info("validate delete", "name", r.Name, "namespace", r.Namespace)
 	return nil
 }
+
+// validateHooksAllowed checks that the cluster running the backup allows
+// the hooks, as they run SQL statements as the superuser and commands in
+// the PostgreSQL container, and only the owner of the cluster can enable them
+func validateHooksAllowed(namespace, clusterName string, hooks *BackupHooks, path *field.Path) field.ErrorList {
+	if hooks.IsEmpty() {
+		return nil
+	}
+
+	var cluster Cluster
+	if backupHooksClusterReader != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), backupHooksClusterTimeout)
+		defer cancel()
+
+		err := backupHooksClusterReader.Get(ctx, types.NamespacedName{Namespace: namespace, Name: clusterName}, &cluster)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return field.ErrorList{field.InternalError(path, err)}
+		}
+	}
+
+	if !cluster.Spec.Backup.AreHooksAllowed() {
+		return field.ErrorList{field.Forbidden(path,
+			fmt.Sprintf("the cluster %q doesn't allow backup hooks, they can be enabled "+
+				"by the owner of the cluster with spec.backup.allowHooks", clusterName))}
+	}
+
+	return nil
+}
@@ -27,6 +27,24 @@ type LocalObjectReference struct {
 	Name string `json:"name"`
 }
 
+// Metadata is a structure similar to the metav1.ObjectMeta, but still
+// parseable by controller-gen to create a suitable CRD for the user.
+type Metadata struct {
+	// Map of string keys and values that can be used to organize and categorize
+	// (scope and select) objects. May match selectors of replication controllers
+	// and services.
+	// More info: http://kubernetes.io/docs/user-guide/labels
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations is an unstructured key value map stored with a resource that may be
+	// set by external tools to store and retrieve arbitrary metadata. They are not
+	// queryable and should be preserved when modifying objects.
+	// More info: http://kubernetes.io/docs/user-guide/annotations
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
 // SecretKeySelector contains enough information to let you locate
 // the key of a Secret
 type SecretKeySelector struct {
@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -76,7 +78,8 @@ func (cluster *Cluster) getElectableSyncReplicas() []string {
 	// We need to include every replica inside the list of possible synchronous standbys if we have no constraints
 	// or the topology extraction is failing. This avoids a continuous operator crash.
 	// One case this could happen is while draining nodes
-	if !cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint.Enabled {
+	constraints := cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint
+	if !constraints.Enabled {
 		return nonPrimaryInstances
 	}
 
@@ -112,6 +115,12 @@ func (cluster *Cluster) getElectableSyncReplicas() []string {
 			continue
 		}
 
+		// when the zone anti-affinity is required, the replicas in an unknown zone are not electable
+		if constraints.ZoneAntiAffinity && instanceTopology[corev1.LabelTopologyZone] == "" {
+			log.Debug("current instance zone is unknown", "instanceName", name)
+			continue
+		}
+
 		if !currentPrimaryTopology.matchesTopology(instanceTopology) {
 			electableReplicas = append(electableReplicas, string(name))
 		}
@@ -64,6 +64,39 @@ var _ = Describe("ensuring the correctness of synchronous replica data calculati
 		Expect(names).To(Equal([]string{differentAZPod}))
 	})
 
+	It("should require the replicas to be in a known zone different from the primary one", func() {
+		const (
+			primaryPod     = "example-1"
+			unknownZonePod = "example-2"
+			differentAZPod = "example-3"
+		)
+
+		cluster := createFakeCluster("example")
+		cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint = SyncReplicaElectionConstraints{
+			Enabled:          true,
+			ZoneAntiAffinity: true,
+		}
+		cluster.Status.Topology = Topology{
+			SuccessfullyExtracted: true,
+			Instances: map[PodName]PodTopologyLabels{
+				primaryPod: map[string]string{
+					"topology.kubernetes.io/zone": "one",
+				},
+				unknownZonePod: map[string]string{
+					"topology.kubernetes.io/zone": "",
+				},
+				differentAZPod: map[string]string{
+					"topology.kubernetes.io/zone": "three",
+				},
+			},
+		}
+
+		number, names := cluster.GetSyncReplicasData()
+
+		Expect(number).To(Equal(1))
+		Expect(names).To(Equal([]string{differentAZPod}))
+	})
+
 	It("should lower the synchronous replica number to enforce self-healing", func() {
 		cluster := createFakeCluster("example")
 		cluster.Status = ClusterStatus{
@@ -80,3 +113,34 @@ var _ = Describe("ensuring the correctness of synchronous replica data calculati
 		Expect(cluster.Spec.MinSyncReplicas).To(Equal(1))
 	})
 })
+
+var _ = Describe("synchronous replica election constraints", func() {
+	It("adds the zone label when the zone anti-affinity is required", func() {
+		constraints := SyncReplicaElectionConstraints{
+			NodeLabelsAntiAffinity: []string{"rack"},
+		}
+		Expect(constraints.GetNodeLabelsAntiAffinity()).To(Equal([]string{"rack"}))
+
+		constraints.ZoneAntiAffinity = true
+		Expect(constraints.GetNodeLabelsAntiAffinity()).To(Equal([]string{"rack", "topology.kubernetes.io/zone"}))
+		Expect(constraints.NodeLabelsAntiAffinity).To(Equal([]string{"rack"}))
+
+		constraints.NodeLabelsAntiAffinity = []string{"topology.kubernetes.io/zone"}
+		Expect(constraints.GetNodeLabelsAntiAffinity()).To(Equal([]string{"topology.kubernetes.io/zone"}))
+	})
+})
+
+var _ = Describe("topology zones", func() {
+	It("lists the distinct zones of the instances", func() {
+		topology := Topology{
+			Locations: map[PodName]InstanceLocation{
+				"example-1": {Node: "node-1", Zone: "b"},
+				"example-2": {Node: "node-2", Zone: "a"},
+				"example-3": {Node: "node-3", Zone: "b"},
+				"example-4": {Node: "node-4"},
+			},
+		}
+		Expect(topology.GetZones()).To(Equal([]string{"a", "b"}))
+		Expect(Topology{}.GetZones()).To(BeEmpty())
+	})
+})
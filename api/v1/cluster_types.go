@@ -19,13 +19,20 @@ package v1
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/strings/slices"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -75,10 +82,23 @@ const (
 	// data
 	ServiceReadWriteSuffix = "-rw"
 
+	// ServiceReadWriteExternalSuffix is the suffix appended to the cluster
+	// name to get the name of the LoadBalancer service pointing to the
+	// primary, published by external-dns
+	ServiceReadWriteExternalSuffix = "-rw-external"
+
 	// ClusterSecretSuffix is the suffix appended to the cluster name to
 	// get the name of the pull secret
 	ClusterSecretSuffix = "-pull-secret"
 
+	// PrimaryHeartbeatLeaseSuffix is the suffix appended to the cluster name
+	// to get the name of the lease used as a heartbeat by the primary
+	PrimaryHeartbeatLeaseSuffix = "-primary-heartbeat"
+
+	// minimumPrimaryHeartbeatTimeout is the minimum accepted value, in
+	// seconds, of the heartbeat timeout of the primary
+	minimumPrimaryHeartbeatTimeout = 15
+
 	// StreamingReplicationUser is the name of the user we'll use for
 	// streaming replication purposes
 	StreamingReplicationUser = "streaming_replica"
@@ -89,12 +109,40 @@ const (
 	// defaultPostgresGID is the default GID which is used by PostgreSQL
 	defaultPostgresGID = 26
 
+	// maximumTunedMaintenanceWorkMem is the maximum value of
+	// maintenance_work_mem set by the memory tuning
+	maximumTunedMaintenanceWorkMem = 2 * 1024 * 1024 * 1024
+
+	// tunedMemoryPerConnection is the amount of memory for each
+	// of the connections allowed by the memory tuning
+	tunedMemoryPerConnection = 16 * 1024 * 1024
+
+	// minimumTunedMaxConnections is the minimum value of
+	// max_connections set by the memory tuning
+	minimumTunedMaxConnections = 20
+
+	// maximumTunedMaxConnections is the maximum value of
+	// max_connections set by the memory tuning
+	maximumTunedMaxConnections = 500
+
 	// PodAntiAffinityTypeRequired is the label for required anti-affinity type
 	PodAntiAffinityTypeRequired = "required"
 
 	// PodAntiAffinityTypePreferred is the label for preferred anti-affinity type
 	PodAntiAffinityTypePreferred = "preferred"
 
+	// DefaultProbeTimeoutSeconds is the default timeout of the
+	// probes of the PostgreSQL container
+	DefaultProbeTimeoutSeconds = 5
+
+	// DefaultProbePeriodSeconds is the default period of the
+	// probes of the PostgreSQL container
+	DefaultProbePeriodSeconds = 10
+
+	// DefaultProbeFailureThreshold is the default failure threshold
+	// of the liveness and readiness probes of the PostgreSQL container
+	DefaultProbeFailureThreshold = 3
+
 	// DefaultPgBouncerPoolerSecretSuffix is the suffix for the default pgbouncer Pooler secret
 	DefaultPgBouncerPoolerSecretSuffix = "-pooler"
 
@@ -103,6 +151,14 @@ const (
 
 	// PGBouncerPoolerUserName is the name of the role to be used for
 	PGBouncerPoolerUserName = "cnpg_pooler_pgbouncer"
+
+	// VolumeSnapshotKind is the kind of the volume snapshots used
+	// as data source when recovering a cluster
+	VolumeSnapshotKind = "VolumeSnapshot"
+
+	// VolumeSnapshotAPIGroup is the API group of the volume snapshots
+	// used as data source when recovering a cluster
+	VolumeSnapshotAPIGroup = "snapshot.storage.k8s.io"
 )
 
 // ClusterSpec defines the desired state of Cluster
@@ -113,11 +169,30 @@ type ClusterSpec struct {
 	// Metadata that will be inherited by all objects related to the Cluster
 	InheritedMetadata *EmbeddedObjectMetadata `json:"inheritedMetadata,omitempty"`
 
+	// The names and the metadata of the objects generated by the operator
+	// +optional
+	GeneratedObjects *GeneratedObjectsConfiguration `json:"generatedObjects,omitempty"`
+
+	// The publication of the primary through a LoadBalancer service,
+	// whose external hostname is managed by external-dns
+	// +optional
+	ExternalDNS *ExternalDNSConfiguration `json:"externalDNS,omitempty"`
+
+	// The periodic detection and repair of the changes made out of
+	// band to the resources generated by the operator
+	// +optional
+	DriftDetection *DriftDetectionConfiguration `json:"driftDetection,omitempty"`
+
 	// Name of the container image, supporting both tags (`<image>:<tag>`)
 	// and digests for deterministic and repeatable deployments
 	// (`<image>:<tag>@sha256:<digestValue>`)
 	ImageName string `json:"imageName,omitempty"`
 
+	// Defines the major PostgreSQL version we want to use within an ImageCatalog
+	// or a ClusterImageCatalog, as an alternative to `imageName`
+	// +optional
+	ImageCatalogRef *ImageCatalogRef `json:"imageCatalogRef,omitempty"`
+
 	// Image pull policy.
 	// One of `Always`, `Never` or `IfNotPresent`.
 	// If not defined, it defaults to `IfNotPresent`.
@@ -134,6 +209,11 @@ type ClusterSpec struct {
 	// +kubebuilder:default:=26
 	PostgresGID int64 `json:"postgresGID,omitempty"`
 
+	// The SeccompProfile applied to every Pod and Container.
+	// Defaults to: `RuntimeDefault`
+	// +optional
+	SeccompProfile *corev1.SeccompProfile `json:"seccompProfile,omitempty"`
+
 	// Number of instances required in the cluster
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:default:=1
@@ -157,6 +237,11 @@ type ClusterSpec struct {
 	// +optional
 	PostgresConfiguration PostgresConfiguration `json:"postgresql,omitempty"`
 
+	// The objects of the databases which are managed by the
+	// instance manager of the primary
+	// +optional
+	Managed *ManagedConfiguration `json:"managed,omitempty"`
+
 	// Instructions to bootstrap this cluster
 	// +optional
 	Bootstrap *BootstrapConfiguration `json:"bootstrap,omitempty"`
@@ -183,9 +268,18 @@ type ClusterSpec struct {
 	// +optional
 	Certificates *CertificatesConfiguration `json:"certificates,omitempty"`
 
+	// The security settings of the streaming replication connections
+	// between the instances
+	// +optional
+	ReplicationConnection *ReplicationConnectionConfiguration `json:"replicationConnection,omitempty"`
+
 	// The list of pull secrets to be used to pull the images
 	ImagePullSecrets []LocalObjectReference `json:"imagePullSecrets,omitempty"`
 
+	// Configure the generation of the service account
+	// +optional
+	ServiceAccountTemplate *ServiceAccountTemplate `json:"serviceAccountTemplate,omitempty"`
+
 	// Configuration of the storage of the instances
 	// +optional
 	StorageConfiguration StorageConfiguration `json:"storage,omitempty"`
@@ -193,6 +287,16 @@ type ClusterSpec struct {
 	// Configuration of the storage for PostgreSQL WAL (Write-Ahead Log)
 	WalStorage *StorageConfiguration `json:"walStorage,omitempty"`
 
+	// The configuration of the monitoring of the disk space used by
+	// the volumes of the instances
+	// +optional
+	DiskSpace *DiskSpaceConfiguration `json:"diskSpace,omitempty"`
+
+	// The configuration of the periodic verification of the data
+	// of the instances, looking for corruptions
+	// +optional
+	DataVerification *DataVerificationConfiguration `json:"dataVerification,omitempty"`
+
 	// The time in seconds that is allowed for a PostgreSQL instance to
 	// successfully start up (default 30)
 	// +kubebuilder:default:=30
@@ -210,16 +314,99 @@ type ClusterSpec struct {
 	// +kubebuilder:default:=40000000
 	MaxSwitchoverDelay int32 `json:"switchoverDelay,omitempty"`
 
+	// The configuration of the probes to be injected
+	// in the PostgreSQL Pods
+	// +optional
+	Probes *ProbesConfiguration `json:"probes,omitempty"`
+
+	// The amount of time (in seconds) to wait before triggering a failover
+	// after the primary PostgreSQL instance in the cluster was detected
+	// to be unhealthy. The health of the primary is checked again during
+	// this period, and the failover is not triggered if it recovers
+	// +kubebuilder:default:=0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailoverDelay int32 `json:"failoverDelay,omitempty"`
+
+	// Constraints on the choice of the standby to be promoted during
+	// a failover. By default, the most advanced standby is promoted
+	// +optional
+	FailoverCandidates *FailoverCandidatesConfiguration `json:"failoverCandidates,omitempty"`
+
+	// The criteria used to consider a running instance degraded,
+	// reported in the `instancesReportedState` of the status
+	// +optional
+	InstanceHealth *InstanceHealthConfiguration `json:"instanceHealth,omitempty"`
+
+	// Whether the operator can promote a standby as soon as the primary
+	// fails (`automatic` - default) or it needs to wait for the user to
+	// approve the failover (`manual`)
+	// +kubebuilder:default:=automatic
+	// +kubebuilder:validation:Enum:=automatic;manual
+	// +optional
+	FailoverPolicy FailoverPolicy `json:"failoverPolicy,omitempty"`
+
+	// The time in seconds after which the instance manager of the primary
+	// shuts PostgreSQL down when it cannot reach the Kubernetes API server,
+	// to prevent a split-brain with an instance promoted by the operator
+	// in the meantime. Zero (default) disables the self-fencing
+	// +kubebuilder:default:=0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SelfFencingTimeout int32 `json:"selfFencingTimeout,omitempty"`
+
+	// The time in seconds after which the operator considers the primary
+	// failed when its instance manager has not renewed the heartbeat lease,
+	// for example because PostgreSQL or its storage is not responding even
+	// if the Pod looks healthy. Zero (default) disables the heartbeat
+	// +kubebuilder:default:=0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PrimaryHeartbeatTimeout int32 `json:"primaryHeartbeatTimeout,omitempty"`
+
+	// When enabled, the instance managers execute the chaos experiments
+	// requested with the `cnpg.io/chaosExperiment` annotation, simulating
+	// failures to rehearse the incident response. Meant for test
+	// environments only. Default: false
+	// +optional
+	EnableChaosTesting bool `json:"enableChaosTesting,omitempty"`
+
 	// Affinity/Anti-affinity rules for Pods
 	// +optional
 	Affinity AffinityConfiguration `json:"affinity,omitempty"`
 
+	// Name of the priority class which will be used in every generated Pod,
+	// if the PriorityClass specified does not exist, the pod will not be able to schedule.
+	// Please refer to
+	// https://kubernetes.io/docs/concepts/scheduling-eviction/pod-priority-preemption/#priorityclass
+	// for more information
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
+	// Name of the priority class used for the Pod of the primary instance,
+	// in place of priorityClassName. The priority of a Pod is set when the Pod
+	// is created: after a switchover or a failover, the new primary keeps
+	// its priority until its Pod is recreated
+	// +optional
+	PrimaryPriorityClassName string `json:"primaryPriorityClassName,omitempty"`
+
 	// Resources requirements of every generated Pod. Please refer to
 	// https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	// for more information.
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// When enabled, the resource requests of the generated Pods default
+	// to their limits, giving them the `Guaranteed` QoS class. Both the
+	// CPU and the memory limits are required
+	// +optional
+	GuaranteedQoS bool `json:"guaranteedQoS,omitempty"`
+
+	// Opt out of the defaults generated by the operator when the
+	// cluster is created
+	// +optional
+	GeneratedDefaults *GeneratedDefaultsConfiguration `json:"generatedDefaults,omitempty"`
+
 	// Strategy to follow to upgrade the primary server during a rolling
 	// update procedure, after all replicas have been successfully updated:
 	// it can be automated (`unsupervised` - default) or manual (`supervised`)
@@ -234,9 +421,61 @@ type ClusterSpec struct {
 	// +kubebuilder:validation:Enum:=switchover;restart
 	PrimaryUpdateMethod PrimaryUpdateMethod `json:"primaryUpdateMethod,omitempty"`
 
+	// The maintenance windows in which the operator can restart or switch
+	// over the primary instance to complete a rolling update. Outside of
+	// them, the replicas are updated and the primary waits for the next
+	// window. When empty, the primary can be updated at any time
+	// +optional
+	PrimaryUpdateWindows []MaintenanceWindow `json:"primaryUpdateWindows,omitempty"`
+
+	// Method to follow to restart the replicas when a change of the
+	// PostgreSQL configuration requires it: it can be by recreating their
+	// Pods (`recreate` - default) or by restarting PostgreSQL inside the
+	// running Pods (`restart`)
+	// +kubebuilder:default:=recreate
+	// +kubebuilder:validation:Enum:=recreate;restart
+	// +optional
+	ReplicaRestartMethod ReplicaRestartMethod `json:"replicaRestartMethod,omitempty"`
+
+	// Method to follow to create the data directory of a new replica:
+	// it can be by cloning the primary with pg_basebackup (`pg_basebackup` -
+	// default) or by restoring the latest backup from the object store
+	// configured in the backup section and then catching up with the
+	// primary (`backup`)
+	// +kubebuilder:default:=pg_basebackup
+	// +kubebuilder:validation:Enum:=pg_basebackup;backup
+	// +optional
+	ReplicaCreationMethod ReplicaCreationMethod `json:"replicaCreationMethod,omitempty"`
+
+	// The options of `pg_basebackup` used when cloning the primary to
+	// create a new replica, or to recreate a former primary which cannot
+	// be aligned with `pg_rewind`
+	// +optional
+	ReplicaClone *CloneConfiguration `json:"replicaClone,omitempty"`
+
+	// The policy for the rolling update of the instances when the
+	// PostgreSQL image changes, for example because a new minor version
+	// has been published in the image catalog used by the cluster
+	// +optional
+	ImageUpdate *ImageUpdateConfiguration `json:"imageUpdate,omitempty"`
+
+	// What to do when `pg_rewind` cannot align the data directory of a former
+	// primary with the new one: it can leave the instance failing (`fail` - default)
+	// or wipe the data directory and clone it again from the primary (`reclone`)
+	// +kubebuilder:default:=fail
+	// +kubebuilder:validation:Enum:=fail;reclone
+	// +optional
+	RewindFailurePolicy RewindFailurePolicy `json:"rewindFailurePolicy,omitempty"`
+
 	// The configuration to be used for backups
 	Backup *BackupConfiguration `json:"backup,omitempty"`
 
+	// The plugins extending the operator for this cluster, called at
+	// the defined points of its lifecycle to archive the WAL files, take
+	// and restore backups or change the definition of the instance Pods
+	// +optional
+	Plugins []PluginConfiguration `json:"plugins,omitempty"`
+
 	// Define a maintenance window for the Kubernetes nodes
 	NodeMaintenanceWindow *NodeMaintenanceWindow `json:"nodeMaintenanceWindow,omitempty"`
 
@@ -271,6 +510,10 @@ const (
 	// PhaseWaitingForUser set the status to wait for an action from the user
 	PhaseWaitingForUser = "Waiting for user action"
 
+	// PhaseWaitingForFailoverApproval when the primary has failed and the
+	// user must approve the failover
+	PhaseWaitingForFailoverApproval = "Waiting for failover approval"
+
 	// PhaseInplacePrimaryRestart for a cluster restarting the primary instance in-place
 	PhaseInplacePrimaryRestart = "Primary instance is being restarted in-place"
 
@@ -289,6 +532,22 @@ const (
 	// PhaseApplyingConfiguration is set by the instance manager when a configuration
 	// change is being detected
 	PhaseApplyingConfiguration = "Applying configuration"
+
+	// PhaseWaitingForMaintenanceWindow is set when the primary instance
+	// needs to be updated outside the primary update windows
+	PhaseWaitingForMaintenanceWindow = "Waiting for the maintenance window"
+
+	// PhaseWaitingForSwitchoverTarget is set when the primary instance
+	// needs to be updated with a switchover, but no replica can be promoted
+	PhaseWaitingForSwitchoverTarget = "Waiting for a replica to be promoted"
+
+	// PhaseWaitingForImageUpdate is set when the instances need to be
+	// updated to a new image, but the image update policy defers it
+	PhaseWaitingForImageUpdate = "Waiting for the image update to be allowed"
+
+	// PhaseImageCatalogError is set when the image of the cluster
+	// cannot be resolved from the referenced image catalog
+	PhaseImageCatalogError = "Cannot retrieve the image from the image catalog"
 )
 
 // PodTopologyLabels represent the topology of a Pod. map[labelName]labelValue
@@ -316,6 +575,36 @@ type Topology struct {
 	SuccessfullyExtracted bool `json:"successfullyExtracted,omitempty"`
 	// Instances contains the pod topology of the instances
 	Instances map[PodName]PodTopologyLabels `json:"instances,omitempty"`
+	// Locations contains the node and the topology zone where each
+	// instance is running
+	// +optional
+	Locations map[PodName]InstanceLocation `json:"locations,omitempty"`
+}
+
+// InstanceLocation is the node and the topology zone where an
+// instance is running
+type InstanceLocation struct {
+	// The name of the node
+	Node string `json:"node"`
+	// The topology zone of the node, as reported by
+	// the `topology.kubernetes.io/zone` label
+	// +optional
+	Zone string `json:"zone,omitempty"`
+}
+
+// GetZones gets the sorted list of the distinct topology zones
+// where the instances are running
+func (topology Topology) GetZones() []string {
+	zones := stringset.New()
+	for _, location := range topology.Locations {
+		if location.Zone != "" {
+			zones.Put(location.Zone)
+		}
+	}
+
+	result := zones.ToList()
+	sort.Strings(result)
+	return result
 }
 
 // ClusterStatus defines the observed state of Cluster
@@ -323,6 +612,15 @@ type ClusterStatus struct {
 	// Total number of instances in the cluster
 	Instances int `json:"instances,omitempty"`
 
+	// The image resolved from the image catalog referenced by the cluster
+	// +optional
+	Image string `json:"image,omitempty"`
+
+	// The architectures provided by the PostgreSQL image, as detected
+	// by the operator when the architecture affinity is enabled
+	// +optional
+	ImageArchitectures *ImageArchitecturesStatus `json:"imageArchitectures,omitempty"`
+
 	// Total number of ready instances in the cluster
 	ReadyInstances int `json:"readyInstances,omitempty"`
 
@@ -348,6 +646,19 @@ type ClusterStatus struct {
 	// during a switchover or a failover
 	TargetPrimary string `json:"targetPrimary,omitempty"`
 
+	// The target primary of the switchover in progress, set only when the
+	// primary is changed while the current one is healthy, like during a
+	// rolling update or when requested by the user. Only in this case the
+	// former primary archives its last WAL file before being demoted
+	// +optional
+	SwitchoverTarget string `json:"switchoverTarget,omitempty"`
+
+	// The LSN at which the former primary switched its last WAL file
+	// during a switchover, after having stopped accepting writes and
+	// before being demoted
+	// +optional
+	DemotedPrimaryLSN string `json:"demotedPrimaryLSN,omitempty"`
+
 	// How many PVCs have been created by this cluster
 	PVCCount int32 `json:"pvcCount,omitempty"`
 
@@ -376,6 +687,12 @@ type ClusterStatus struct {
 	// Current list of read pods
 	ReadService string `json:"readService,omitempty"`
 
+	// The secret containing the connection information of the application
+	// database, following the Provisioned Service duck type of the
+	// Service Binding specification (https://servicebinding.io)
+	// +optional
+	Binding *LocalObjectReference `json:"binding,omitempty"`
+
 	// Current phase of the cluster
 	Phase string `json:"phase,omitempty"`
 
@@ -400,6 +717,38 @@ type ClusterStatus struct {
 	// The first recoverability point, stored as a date in RFC3339 format
 	FirstRecoverabilityPoint string `json:"firstRecoverabilityPoint,omitempty"`
 
+	// Stored as a date in RFC3339 format
+	LastSuccessfulBackup string `json:"lastSuccessfulBackup,omitempty"`
+
+	// Stored as a date in RFC3339 format
+	LastFailedBackup string `json:"lastFailedBackup,omitempty"`
+
+	// The last WAL file archived by the primary
+	LastArchivedWAL string `json:"lastArchivedWAL,omitempty"`
+
+	// When the last WAL file was archived by the primary, stored as a date
+	// in RFC3339 format
+	LastArchivedWALTime string `json:"lastArchivedWALTime,omitempty"`
+
+	// The last WAL file the primary failed to archive
+	LastFailedWAL string `json:"lastFailedWAL,omitempty"`
+
+	// When the primary last failed to archive a WAL file, stored as a date
+	// in RFC3339 format
+	LastFailedWALTime string `json:"lastFailedWALTime,omitempty"`
+
+	// The name of the latest backup which passed the WAL check
+	LastWALCheckedBackup string `json:"lastWALCheckedBackup,omitempty"`
+
+	// When the latest backup passed the WAL check, stored as a date in
+	// RFC3339 format
+	LastSuccessfulBackupWALCheck string `json:"lastSuccessfulBackupWALCheck,omitempty"`
+
+	// The status of the additional object stores. Their failures
+	// never block the WAL archiving and the backups in the main one
+	// +optional
+	AdditionalObjectStores []AdditionalObjectStoreStatus `json:"additionalObjectStores,omitempty"`
+
 	// The commit hash number of which this operator running
 	CommitHash string `json:"cloudNativePGCommitHash,omitempty"`
 
@@ -409,9 +758,27 @@ type ClusterStatus struct {
 	// The timestamp when the last request for a new primary has occurred
 	TargetPrimaryTimestamp string `json:"targetPrimaryTimestamp,omitempty"`
 
+	// The timestamp when the current primary has been detected to be
+	// unhealthy, reset when it becomes healthy again or a new primary
+	// has been elected
+	CurrentPrimaryFailingSinceTimestamp string `json:"currentPrimaryFailingSinceTimestamp,omitempty"`
+
 	// The integration needed by poolers referencing the cluster
 	PoolerIntegrations *PoolerIntegrations `json:"poolerIntegrations,omitempty"`
 
+	// The status of the managed extensions, as reported by the primary
+	// +optional
+	ManagedExtensionsStatus *ManagedExtensionsStatus `json:"managedExtensionsStatus,omitempty"`
+
+	// The result of the last reconciliation of the object store
+	// against the Backup resources, when the garbage collection is enabled
+	// +optional
+	ObjectStoreReport *ObjectStoreReport `json:"objectStoreReport,omitempty"`
+
+	// The status of the managed roles, as reported by the primary
+	// +optional
+	ManagedRolesStatus *ManagedRolesStatus `json:"managedRolesStatus,omitempty"`
+
 	// The hash of the binary of the operator
 	OperatorHash string `json:"cloudNativePGOperatorHash,omitempty"`
 
@@ -431,6 +798,75 @@ type InstanceReportedState struct {
 	IsPrimary bool `json:"isPrimary"`
 	// indicates on which TimelineId the instance is
 	TimeLineID int `json:"timeLineID,omitempty"`
+	// the system identifier of the instance, as reported by pg_controldata
+	// +optional
+	SystemID string `json:"systemID,omitempty"`
+	// the LSN of the latest checkpoint of the instance, as reported by pg_controldata
+	// +optional
+	LatestCheckpointLSN string `json:"latestCheckpointLSN,omitempty"`
+	// the health of the instance: `ready`, `degraded` when PostgreSQL is
+	// running but not working correctly, or `failed`
+	// +optional
+	Health InstanceHealth `json:"health,omitempty"`
+	// the reason why the instance is not ready
+	// +optional
+	HealthReason string `json:"healthReason,omitempty"`
+	// the status of the WAL receiver of a standby, as reported by
+	// `pg_stat_wal_receiver`, e.g. `streaming`. Empty when the WAL
+	// receiver is not running
+	// +optional
+	WalReceiverStatus string `json:"walReceiverStatus,omitempty"`
+	// the WAL senders of the primary, one for each standby
+	// connected to it
+	// +optional
+	WalSenders []WalSenderState `json:"walSenders,omitempty"`
+	// the parameters whose new value will be applied only after
+	// a restart of the instance, as reported by `pg_settings`
+	// +optional
+	PendingRestartParameters []string `json:"pendingRestartParameters,omitempty"`
+}
+
+// WalSenderState is the state of a WAL sender of the primary, as reported
+// by `pg_stat_replication`. The LSNs are not included, as they change
+// continuously, and are exposed as metrics instead
+type WalSenderState struct {
+	// the name of the standby
+	Name string `json:"name"`
+	// the state of the WAL sender, e.g. `streaming` or `catchup`
+	// +optional
+	State string `json:"state,omitempty"`
+	// the synchronous state of the standby: `async`, `potential`,
+	// `sync` or `quorum`
+	// +optional
+	SyncState string `json:"syncState,omitempty"`
+}
+
+// InstanceHealth is the health of an instance, as
+// reported by its instance manager
+type InstanceHealth string
+
+const (
+	// InstanceHealthReady means that PostgreSQL is running
+	// and working correctly
+	InstanceHealthReady InstanceHealth = "ready"
+
+	// InstanceHealthDegraded means that PostgreSQL is running, but
+	// a replica is lagging or not streaming, or the WAL archiving
+	// of the primary is failing
+	InstanceHealthDegraded InstanceHealth = "degraded"
+
+	// InstanceHealthFailed means that the status of PostgreSQL
+	// can't be retrieved from the instance manager
+	InstanceHealthFailed InstanceHealth = "failed"
+)
+
+// InstanceHealthConfiguration contains the criteria
+// used to consider a running instance degraded
+type InstanceHealthConfiguration struct {
+	// The replay lag, in bytes, above which a replica is considered
+	// degraded. When not specified, the lag is not considered
+	// +optional
+	MaximumLag *resource.Quantity `json:"maximumLag,omitempty"`
 }
 
 // ClusterConditionType defines types of cluster conditions
@@ -445,6 +881,23 @@ const (
 	ConditionBackup ClusterConditionType = "LastBackupSucceeded"
 	// ConditionClusterReady represents whether a cluster is Ready
 	ConditionClusterReady ClusterConditionType = "Ready"
+	// ConditionDataVerification represents whether the last verification
+	// of the data of the instances found a corruption
+	ConditionDataVerification ClusterConditionType = "DataVerification"
+	// ConditionPromotion represents the status of the promotion
+	// of the target primary
+	ConditionPromotion ClusterConditionType = "Promotion"
+	// ConditionResourcesInSync represents whether the resources generated
+	// by the operator match their expected definition
+	ConditionResourcesInSync ClusterConditionType = "ResourcesInSync"
+	// ConditionSplitBrainSuspected represents whether more than one
+	// instance claims to be the primary, or the timelines of the
+	// instances diverged. Automated failovers are blocked while it holds
+	ConditionSplitBrainSuspected ClusterConditionType = "SplitBrainSuspected"
+	// ConditionSharedPreloadLibraries represents whether the instances
+	// can apply the PostgreSQL configuration, as the shared preload
+	// libraries it requires are available
+	ConditionSharedPreloadLibraries ClusterConditionType = "SharedPreloadLibrariesAvailable"
 )
 
 // ConditionStatus defines conditions of resources
@@ -490,6 +943,56 @@ const (
 
 	// ClusterIsNotReady means that the condition changed because the cluster is not ready
 	ClusterIsNotReady ConditionReason = "ClusterIsNotReady"
+
+	// ConditionReasonDataVerificationSucceeded means that the condition changed because
+	// the verification of the data didn't find any corruption
+	ConditionReasonDataVerificationSucceeded ConditionReason = "DataVerificationSucceeded"
+
+	// ConditionReasonDataCorruptionDetected means that the condition changed because
+	// the verification of the data found a corruption
+	ConditionReasonDataCorruptionDetected ConditionReason = "DataCorruptionDetected"
+
+	// ConditionReasonPromotionInProgress means that the condition changed
+	// because the promotion of the target primary started
+	ConditionReasonPromotionInProgress ConditionReason = "PromotionInProgress"
+
+	// ConditionReasonPromotionSucceeded means that the condition changed
+	// because the target primary has been promoted
+	ConditionReasonPromotionSucceeded ConditionReason = "PromotionSucceeded"
+
+	// ConditionReasonPromotionTimedOut means that the condition changed
+	// because the promotion of the target primary exceeded the timeout
+	ConditionReasonPromotionTimedOut ConditionReason = "PromotionTimedOut"
+
+	// ConditionReasonResourcesInSync means that the condition changed because
+	// the generated resources match their expected definition
+	ConditionReasonResourcesInSync ConditionReason = "ResourcesInSync"
+
+	// ConditionReasonResourcesDrifted means that the condition changed because
+	// some generated resources differ from their expected definition, and
+	// haven't been restored yet
+	ConditionReasonResourcesDrifted ConditionReason = "ResourcesDrifted"
+
+	// ConditionReasonInstancesConsistent means that the condition changed
+	// because a single primary exists and the instances follow its timeline
+	ConditionReasonInstancesConsistent ConditionReason = "InstancesConsistent"
+
+	// ConditionReasonMultiplePrimaries means that the condition changed
+	// because more than one instance claims to be the primary
+	ConditionReasonMultiplePrimaries ConditionReason = "MultiplePrimaries"
+
+	// ConditionReasonTimelineDiverged means that the condition changed
+	// because a replica is on a timeline newer than the primary one
+	ConditionReasonTimelineDiverged ConditionReason = "TimelineDiverged"
+
+	// ConditionReasonSharedPreloadLibrariesAvailable means that the condition
+	// changed because the shared preload libraries are available again
+	ConditionReasonSharedPreloadLibrariesAvailable ConditionReason = "SharedPreloadLibrariesAvailable"
+
+	// ConditionReasonSharedPreloadLibrariesMissing means that the condition
+	// changed because an instance cannot apply the PostgreSQL configuration,
+	// as some of its shared preload libraries are missing
+	ConditionReasonSharedPreloadLibrariesMissing ConditionReason = "SharedPreloadLibrariesMissing"
 )
 
 // EmbeddedObjectMetadata contains metadata to be inherited by all resources related to a Cluster
@@ -498,6 +1001,85 @@ type EmbeddedObjectMetadata struct {
 	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
+// GeneratedObjectsConfiguration customizes the names and the metadata
+// of the objects generated by the operator
+type GeneratedObjectsConfiguration struct {
+	// The template of the names of the secrets generated by the operator.
+	// The `{cluster}` placeholder is replaced by the name of the cluster and
+	// `{secret}` by the kind of secret: `superuser`, `app`, `ca`, `server`
+	// or `replication`. Defaults to `{cluster}-{secret}`, and cannot be
+	// changed after the cluster has been created
+	// +optional
+	SecretNameTemplate string `json:"secretNameTemplate,omitempty"`
+
+	// The labels and annotations of the generated secrets
+	// +optional
+	Secrets *EmbeddedObjectMetadata `json:"secrets,omitempty"`
+
+	// The labels and annotations of the generated services
+	// +optional
+	Services *EmbeddedObjectMetadata `json:"services,omitempty"`
+
+	// The labels and annotations of the generated Pods
+	// +optional
+	Pods *EmbeddedObjectMetadata `json:"pods,omitempty"`
+
+	// The labels and annotations of the generated PVCs
+	// +optional
+	PersistentVolumeClaims *EmbeddedObjectMetadata `json:"persistentVolumeClaims,omitempty"`
+}
+
+// ExternalDNSConfiguration defines the LoadBalancer service pointing to
+// the current primary, annotated to be published by external-dns
+type ExternalDNSConfiguration struct {
+	// The external hostname always pointing to the current primary
+	// +kubebuilder:validation:MinLength=1
+	Hostname string `json:"hostname"`
+
+	// The TTL of the DNS record, in seconds. When unset, the default
+	// of external-dns is used
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TTL *int32 `json:"ttl,omitempty"`
+
+	// Additional annotations of the LoadBalancer service, like the ones
+	// configuring the load balancer of the cloud provider
+	// +optional
+	ServiceAnnotations map[string]string `json:"serviceAnnotations,omitempty"`
+
+	// The client IP ranges allowed to connect to the load balancer.
+	// When empty, every client is allowed
+	// +optional
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+}
+
+// ServiceAccountTemplate contains the template needed to generate the service accounts
+type ServiceAccountTemplate struct {
+	// Metadata are the metadata to be used for the generated
+	// service account
+	Metadata Metadata `json:"metadata"`
+}
+
+// MergeMetadata adds the passed custom annotations and labels in the service account.
+func (st *ServiceAccountTemplate) MergeMetadata(sa *corev1.ServiceAccount) {
+	if st == nil {
+		return
+	}
+	if sa.Labels == nil {
+		sa.Labels = map[string]string{}
+	}
+	if sa.Annotations == nil {
+		sa.Annotations = map[string]string{}
+	}
+
+	for key, value := range st.Metadata.Labels {
+		sa.Labels[key] = value
+	}
+	for key, value := range st.Metadata.Annotations {
+		sa.Annotations[key] = value
+	}
+}
+
 // PoolerIntegrations encapsulates the needed integration for the poolers referencing the cluster
 type PoolerIntegrations struct {
 	PgBouncerIntegration PgBouncerIntegrationStatus `json:"pgBouncerIntegration,omitempty"`
@@ -513,7 +1095,8 @@ type PgBouncerIntegrationStatus struct {
 type ReplicaClusterConfiguration struct {
 	// If replica mode is enabled, this cluster will be a replica of an
 	// existing cluster. Replica cluster can be created from a recovery
-	// object store or via streaming through pg_basebackup.
+	// object store, from volume snapshots or via streaming through
+	// pg_basebackup.
 	// Refer to the Replication page of the documentation for more information.
 	// +optional
 	Enabled bool `json:"enabled"`
@@ -540,6 +1123,23 @@ const (
 	KubernetesUpgradeStrategyWaitForNode = "waitForNode"
 )
 
+// FailoverCandidatesConfiguration contains the constraints used to choose
+// the standby to be promoted during a failover. Among the allowed
+// instances, the one with the highest received and replayed LSN is chosen
+type FailoverCandidatesConfiguration struct {
+	// The names of the instances that must never be promoted
+	// during a failover
+	// +optional
+	ExcludedInstances []string `json:"excludedInstances,omitempty"`
+
+	// The label of the Kubernetes nodes defining their topology domain
+	// (i.e. `topology.kubernetes.io/zone`). When set, the standbys running
+	// in the same topology domain of the failed primary are preferred,
+	// if any of them can be promoted
+	// +optional
+	PreferSameTopologyKey string `json:"preferSameTopologyKey,omitempty"`
+}
+
 // NodeMaintenanceWindow contains information that the operator
 // will use while upgrading the underlying node.
 //
@@ -565,6 +1165,114 @@ type PrimaryUpdateStrategy string
 // the primary server of the cluster as part of rolling updates
 type PrimaryUpdateMethod string
 
+// ReplicaRestartMethod contains the method to use when the replicas
+// need to be restarted to apply a configuration change
+type ReplicaRestartMethod string
+
+const (
+	// ReplicaRestartMethodRecreate means that the operator will delete and
+	// create again the Pods of the replicas (`recreate`, default)
+	ReplicaRestartMethodRecreate ReplicaRestartMethod = "recreate"
+
+	// ReplicaRestartMethodRestart means that the operator will restart, one at
+	// a time, PostgreSQL inside the running Pods of the replicas (`restart`)
+	ReplicaRestartMethodRestart ReplicaRestartMethod = "restart"
+)
+
+// ReplicationConnectionConfiguration contains the security settings
+// used by the standbys to connect to the primary
+type ReplicationConnectionConfiguration struct {
+	// The sslmode used to verify the certificate of the primary:
+	// it can be checking that it is signed by the server CA (`verify-ca` -
+	// default) or also that it matches the `-rw` service name (`verify-full`)
+	// +kubebuilder:validation:Enum:=verify-ca;verify-full
+	// +optional
+	SSLMode ReplicationSSLMode `json:"sslMode,omitempty"`
+
+	// The channel_binding used by the standbys, requires PostgreSQL 13.
+	// The `require` value is not supported, as the streaming replication
+	// user is authenticated with a TLS client certificate
+	// +kubebuilder:validation:Enum:=disable;prefer
+	// +optional
+	ChannelBinding string `json:"channelBinding,omitempty"`
+}
+
+// ReplicationSSLMode is the sslmode used by the standbys
+type ReplicationSSLMode string
+
+const (
+	// ReplicationSSLModeVerifyCA means that the standbys check that the
+	// certificate of the primary is signed by the server CA (`verify-ca`, default)
+	ReplicationSSLModeVerifyCA ReplicationSSLMode = "verify-ca"
+
+	// ReplicationSSLModeVerifyFull means that the standbys also check that the
+	// certificate of the primary matches the `-rw` service name (`verify-full`)
+	ReplicationSSLModeVerifyFull ReplicationSSLMode = "verify-full"
+)
+
+// GetSSLMode gets the sslmode used by the standbys, defaulting to `verify-ca`
+func (configuration *ReplicationConnectionConfiguration) GetSSLMode() ReplicationSSLMode {
+	if configuration == nil || configuration.SSLMode == "" {
+		return ReplicationSSLModeVerifyCA
+	}
+	return configuration.SSLMode
+}
+
+// ReplicaCreationMethod contains the method to use to create the
+// data directory of a new replica
+type ReplicaCreationMethod string
+
+const (
+	// ReplicaCreationMethodPgBaseBackup means that the data directory of a new
+	// replica is cloned from the primary with pg_basebackup (`pg_basebackup`, default)
+	ReplicaCreationMethodPgBaseBackup ReplicaCreationMethod = "pg_basebackup"
+
+	// ReplicaCreationMethodBackup means that the data directory of a new replica
+	// is restored from the latest backup in the object store (`backup`)
+	ReplicaCreationMethodBackup ReplicaCreationMethod = "backup"
+)
+
+// ReadinessProbeType is the criteria used by the readiness probe
+// to consider an instance ready
+type ReadinessProbeType string
+
+const (
+	// ReadinessProbeTypeQuery means that an instance is ready when
+	// it accepts connections (`query`, default)
+	ReadinessProbeTypeQuery ReadinessProbeType = "query"
+
+	// ReadinessProbeTypeStreaming means that a replica is ready only when
+	// it accepts connections and is streaming WAL from its source (`streaming`)
+	ReadinessProbeTypeStreaming ReadinessProbeType = "streaming"
+)
+
+// FailoverPolicy contains the policy to follow when the primary fails
+type FailoverPolicy string
+
+const (
+	// FailoverPolicyAutomatic means that the operator promotes a standby
+	// as soon as the primary fails (`automatic`, default)
+	FailoverPolicyAutomatic FailoverPolicy = "automatic"
+
+	// FailoverPolicyManual means that the operator waits for the user
+	// to approve the promotion of a standby (`manual`)
+	FailoverPolicyManual FailoverPolicy = "manual"
+)
+
+// RewindFailurePolicy contains the action to take when a former primary
+// cannot be rewound to follow the new one
+type RewindFailurePolicy string
+
+const (
+	// RewindFailurePolicyFail means that the instance will keep failing until
+	// the user manually recovers it (`fail`, default)
+	RewindFailurePolicyFail RewindFailurePolicy = "fail"
+
+	// RewindFailurePolicyReclone means that the data directory of the instance
+	// will be wiped and cloned again from the current primary (`reclone`)
+	RewindFailurePolicyReclone RewindFailurePolicy = "reclone"
+)
+
 const (
 	// PrimaryUpdateStrategySupervised means that the operator need to wait for the
 	// user to manually issue a switchover request before updating the primary
@@ -592,6 +1300,47 @@ const (
 	// is gracefully shutdown during a switchover.
 	// It is greater than one year in seconds, big enough to simulate an infinite timeout
 	DefaultMaxSwitchoverDelay = 40000000
+
+	// DefaultDiskSpaceWarningThreshold is the default percentage of used space
+	// of a volume above which a warning event is emitted
+	DefaultDiskSpaceWarningThreshold = 80
+
+	// DefaultSecretNameTemplate is the default template of the
+	// names of the secrets generated by the operator
+	DefaultSecretNameTemplate = SecretNameClusterPlaceholder + "-" + SecretNameKindPlaceholder
+
+	// SecretNameClusterPlaceholder is replaced by the name of the
+	// cluster in the secret name template
+	SecretNameClusterPlaceholder = "{cluster}"
+
+	// SecretNameKindPlaceholder is replaced by the kind of the
+	// secret in the secret name template
+	SecretNameKindPlaceholder = "{secret}"
+
+	// DefaultDiskSpaceCriticalThreshold is the default percentage of used space
+	// of a volume above which a critical event is emitted
+	DefaultDiskSpaceCriticalThreshold = 95
+
+	// standbyDelayWarningThreshold is the standby delay, in seconds, above
+	// which enabling hot_standby_feedback deserves a warning
+	standbyDelayWarningThreshold = 1800
+)
+
+// PromotionTimeoutAction is the action to be taken when the
+// promotion of an instance exceeds the promotion timeout. As PostgreSQL
+// can't cancel a requested promotion, the action only controls whether
+// the instance manager keeps waiting for it
+type PromotionTimeoutAction string
+
+const (
+	// PromotionTimeoutActionWait means that the instance manager keeps
+	// waiting for the requested promotion to complete, for at most
+	// another promotion timeout
+	PromotionTimeoutActionWait PromotionTimeoutAction = "wait"
+
+	// PromotionTimeoutActionRequeue means that the instance manager stops
+	// waiting, and checks the promotion again in the next reconciliation loop
+	PromotionTimeoutActionRequeue PromotionTimeoutAction = "requeue"
 )
 
 // PostgresConfiguration defines the PostgreSQL configuration
@@ -614,34 +1363,684 @@ type PostgresConfiguration struct {
 	// +optional
 	PgCtlTimeoutForPromotion int32 `json:"promotionTimeout,omitempty"`
 
+	// The action to be taken when the promotion of an instance doesn't
+	// complete within the promotion timeout: `wait` (default) keeps waiting
+	// for the promotion to complete, while `requeue` stops waiting and
+	// checks it again in the next reconciliation loop. The requested
+	// promotion is never cancelled
+	// +kubebuilder:validation:Enum:=wait;requeue
+	// +optional
+	PromotionTimeoutAction PromotionTimeoutAction `json:"promotionTimeoutAction,omitempty"`
+
 	// Lists of shared preload libraries to add to the default ones
 	// +optional
 	AdditionalLibraries []string `json:"shared_preload_libraries,omitempty"`
 
-	// Options to specify LDAP configuration
+	// The container images of the extensions to be added to the PostgreSQL
+	// installation of the instances, without building a custom image.
+	// Changing them triggers a rolling update of the instances
 	// +optional
-	LDAP *LDAPConfig `json:"ldap,omitempty"`
-}
+	ExtensionImages []ExtensionImage `json:"extensionImages,omitempty"`
 
-// BootstrapConfiguration contains information about how to create the PostgreSQL
-// cluster. Only a single bootstrap method can be defined among the supported
-// ones. `initdb` will be used as the bootstrap method if left
-// unspecified. Refer to the Bootstrap page of the documentation for more
-// information.
-type BootstrapConfiguration struct {
-	// Bootstrap the cluster via initdb
-	InitDB *BootstrapInitDB `json:"initdb,omitempty"`
+	// Enable the `pg_stat_statements` extension, adding it to the shared
+	// preload libraries and creating it in every database
+	// +optional
+	EnablePgStatStatements bool `json:"enablePgStatStatements,omitempty"`
 
-	// Bootstrap the cluster from a backup
-	Recovery *BootstrapRecovery `json:"recovery,omitempty"`
+	// Enable the `auto_explain` module, adding it to the shared
+	// preload libraries
+	// +optional
+	EnableAutoExplain bool `json:"enableAutoExplain,omitempty"`
 
-	// Bootstrap the cluster taking a physical backup of another compatible
-	// PostgreSQL instance
-	PgBaseBackup *BootstrapPgBaseBackup `json:"pg_basebackup,omitempty"`
-}
+	// The configuration of the `pgaudit` extension. When specified, the
+	// extension is enabled and its parameters are managed by the operator
+	// +optional
+	PgAudit *PgAuditConfiguration `json:"pgaudit,omitempty"`
 
-// LDAPScheme defines the possible schemes for LDAP
-type LDAPScheme string
+	// The configuration of the PostgreSQL logging verbosity. When specified,
+	// the corresponding `log_*` parameters are managed by the operator
+	// +optional
+	Logging *PostgresLoggingConfiguration `json:"logging,omitempty"`
+
+	// The configuration of the queries running on the replicas. When
+	// specified, `hot_standby_feedback`, `max_standby_streaming_delay` and
+	// `max_standby_archive_delay` are managed by the operator
+	// +optional
+	Standby *StandbyConfiguration `json:"standby,omitempty"`
+
+	// The limits on the connections to the instances. When specified,
+	// `max_connections` and `superuser_reserved_connections` are managed
+	// by the operator
+	// +optional
+	Connections *ConnectionsConfiguration `json:"connections,omitempty"`
+
+	// The time zone and the locale used by the instances. When specified,
+	// `timezone`, `log_timezone`, `lc_messages`, `lc_monetary`,
+	// `lc_numeric`, `lc_time` and `datestyle` are managed by the operator
+	// +optional
+	Locale *LocaleConfiguration `json:"locale,omitempty"`
+
+	// When enabled, the default values of `shared_buffers`,
+	// `effective_cache_size`, `maintenance_work_mem` and `max_connections`
+	// are derived from the memory limit of the Pods. The values in the
+	// parameters section take precedence
+	// +optional
+	MemoryTuning bool `json:"memoryTuning,omitempty"`
+
+	// When enabled, the settings changed with `ALTER SYSTEM` are discarded
+	// by the instance manager, which removes them from
+	// `postgresql.auto.conf` and reloads the configuration. This makes the
+	// `postgresql` section the only source of the configuration
+	// +optional
+	DisableAlterSystem bool `json:"disableAlterSystem,omitempty"`
+
+	// Additional configuration files, taken from ConfigMaps, which are
+	// read in order after the configuration generated by the operator.
+	// The parameters which can't be set in the `parameters` section are
+	// ignored, as well as the ones managed by the sections in use
+	// +optional
+	ConfigurationFiles []ConfigMapKeySelector `json:"configurationFiles,omitempty"`
+
+	// Options to specify LDAP configuration
+	// +optional
+	LDAP *LDAPConfig `json:"ldap,omitempty"`
+
+	// The flavor of PostgreSQL contained in the image, which defines the
+	// name of the superuser, of the executables, and the default
+	// configuration. When empty, it is detected from the name of the image
+	// repository, using `epas` for the `edb-postgres-advanced` images
+	// +kubebuilder:validation:Enum=postgresql;epas
+	// +optional
+	Flavor postgres.Flavor `json:"flavor,omitempty"`
+
+	// The Transparent Data Encryption configuration, available
+	// with the `epas` flavor from the version 15
+	// +optional
+	TDE *TDEConfiguration `json:"tde,omitempty"`
+}
+
+// TDEConfiguration contains the Transparent Data Encryption configuration.
+// The data encryption key is generated by initdb and wrapped using either
+// a passphrase stored in a secret or an external command, for example
+// calling a key management service
+type TDEConfiguration struct {
+	// Encrypt the data files of the cluster. It can only be set
+	// when the cluster is created
+	Enabled bool `json:"enabled,omitempty"`
+
+	// The secret containing the passphrase used to wrap
+	// the data encryption key
+	// +optional
+	PassphraseSecret *SecretKeySelector `json:"passphraseSecret,omitempty"`
+
+	// The command used to wrap the data encryption key, reading it
+	// from the standard input and writing the wrapped key in the file
+	// whose path replaces `%p`
+	// +optional
+	WrapCommand string `json:"wrapCommand,omitempty"`
+
+	// The command used to unwrap the data encryption key, reading the
+	// wrapped key from the file whose path replaces `%p` and writing
+	// the key to the standard output
+	// +optional
+	UnwrapCommand string `json:"unwrapCommand,omitempty"`
+}
+
+// TDEPassphraseEnvironmentVariable is the environment variable containing
+// the passphrase used to wrap the data encryption key
+const TDEPassphraseEnvironmentVariable = "PGPASSPHRASE"
+
+// IsEnabled checks whether the Transparent Data Encryption is enabled
+func (tde *TDEConfiguration) IsEnabled() bool {
+	return tde != nil && tde.Enabled
+}
+
+// GetWrapCommand gets the command used to wrap the data encryption key
+func (tde *TDEConfiguration) GetWrapCommand() string {
+	if tde.PassphraseSecret != nil {
+		return fmt.Sprintf(`openssl enc -e -aes-128-cbc -pbkdf2 -pass env:%s -out "%%p"`,
+			TDEPassphraseEnvironmentVariable)
+	}
+
+	return tde.WrapCommand
+}
+
+// GetUnwrapCommand gets the command used to unwrap the data encryption key
+func (tde *TDEConfiguration) GetUnwrapCommand() string {
+	if tde.PassphraseSecret != nil {
+		return fmt.Sprintf(`openssl enc -d -aes-128-cbc -pbkdf2 -pass env:%s -in "%%p"`,
+			TDEPassphraseEnvironmentVariable)
+	}
+
+	return tde.UnwrapCommand
+}
+
+// ExtensionImage is a container image containing the files of a
+// PostgreSQL extension, built for the PostgreSQL version of the cluster:
+// the shared libraries in the `/lib` directory and the control and
+// SQL files in the `/share/extension` directory
+type ExtensionImage struct {
+	// The name of the extension, used to name the init container
+	// copying its files
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=50
+	Name string `json:"name"`
+
+	// The container image of the extension
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+
+	// The pull policy of the image
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+}
+
+// PgAuditConfiguration contains the configuration of the `pgaudit`
+// extension. Refer to the pgaudit documentation for the meaning
+// of each option
+type PgAuditConfiguration struct {
+	// The classes of statements to be logged by the session audit
+	// logging (`pgaudit.log`). Classes can be excluded with a `-` prefix
+	// +optional
+	Log []PgAuditLogClass `json:"log,omitempty"`
+
+	// Log the statements on the system catalog (`pgaudit.log_catalog`)
+	// +optional
+	LogCatalog *bool `json:"logCatalog,omitempty"`
+
+	// Log the parameters passed with the statements (`pgaudit.log_parameter`)
+	// +optional
+	LogParameter bool `json:"logParameter,omitempty"`
+
+	// Log a separate entry for each relation referenced by a
+	// statement (`pgaudit.log_relation`)
+	// +optional
+	LogRelation bool `json:"logRelation,omitempty"`
+
+	// Include the statement text and parameters only with the first log
+	// entry of a statement (`pgaudit.log_statement_once`)
+	// +optional
+	LogStatementOnce bool `json:"logStatementOnce,omitempty"`
+
+	// The role used for the object audit logging (`pgaudit.role`)
+	// +optional
+	Role string `json:"role,omitempty"`
+}
+
+// PgAuditLogClass is a class of statements logged by pgaudit,
+// optionally prefixed by `-` to exclude it
+// +kubebuilder:validation:Pattern=`^-?(read|write|function|role|ddl|misc|misc_set|all|none)$`
+type PgAuditLogClass string
+
+// GetParameters gets the PostgreSQL parameters corresponding
+// to this pgaudit configuration
+func (r PgAuditConfiguration) GetParameters() map[string]string {
+	toSetting := func(value bool) string {
+		if value {
+			return "on"
+		}
+		return "off"
+	}
+
+	parameters := map[string]string{
+		"pgaudit.log_parameter":      toSetting(r.LogParameter),
+		"pgaudit.log_relation":       toSetting(r.LogRelation),
+		"pgaudit.log_statement_once": toSetting(r.LogStatementOnce),
+	}
+	if len(r.Log) > 0 {
+		classes := make([]string, len(r.Log))
+		for idx, class := range r.Log {
+			classes[idx] = string(class)
+		}
+		parameters["pgaudit.log"] = strings.Join(classes, ", ")
+	}
+	if r.LogCatalog != nil {
+		parameters["pgaudit.log_catalog"] = toSetting(*r.LogCatalog)
+	}
+	if r.Role != "" {
+		parameters["pgaudit.role"] = r.Role
+	}
+
+	return parameters
+}
+
+// StandbyConfiguration contains the settings controlling the conflicts
+// between the queries running on the replicas and the changes replayed
+// from the primary. Refer to the "Hot Standby" section of the PostgreSQL
+// documentation for the meaning of each option
+type StandbyConfiguration struct {
+	// Send feedback to the primary about the queries running on the
+	// replicas, preventing vacuum from removing the rows they need
+	// (`hot_standby_feedback`)
+	// +optional
+	HotStandbyFeedback *bool `json:"hotStandbyFeedback,omitempty"`
+
+	// The maximum number of seconds a replica waits before canceling the
+	// queries conflicting with the changes received via streaming
+	// replication (`max_standby_streaming_delay`). -1 waits forever
+	// +kubebuilder:validation:Minimum=-1
+	// +optional
+	MaxStreamingDelay *int32 `json:"maxStreamingDelay,omitempty"`
+
+	// The maximum number of seconds a replica waits before canceling the
+	// queries conflicting with the changes read from the WAL archive
+	// (`max_standby_archive_delay`). -1 waits forever
+	// +kubebuilder:validation:Minimum=-1
+	// +optional
+	MaxArchiveDelay *int32 `json:"maxArchiveDelay,omitempty"`
+}
+
+// GetManagedParameters gets the names of the parameters which can
+// be set by this standby configuration
+func (r StandbyConfiguration) GetManagedParameters() []string {
+	return []string{
+		"hot_standby_feedback",
+		"max_standby_archive_delay",
+		"max_standby_streaming_delay",
+	}
+}
+
+// GetParameters gets the PostgreSQL parameters corresponding
+// to this standby configuration
+func (r StandbyConfiguration) GetParameters() map[string]string {
+	toDelay := func(seconds int32) string {
+		if seconds < 0 {
+			return "-1"
+		}
+		return fmt.Sprintf("%ds", seconds)
+	}
+
+	parameters := make(map[string]string)
+	if r.HotStandbyFeedback != nil {
+		parameters["hot_standby_feedback"] = "off"
+		if *r.HotStandbyFeedback {
+			parameters["hot_standby_feedback"] = "on"
+		}
+	}
+	if r.MaxStreamingDelay != nil {
+		parameters["max_standby_streaming_delay"] = toDelay(*r.MaxStreamingDelay)
+	}
+	if r.MaxArchiveDelay != nil {
+		parameters["max_standby_archive_delay"] = toDelay(*r.MaxArchiveDelay)
+	}
+
+	return parameters
+}
+
+// GetWarnings gets the guidance about the risks of this standby
+// configuration, which is valid but might not be what the user wants
+func (r StandbyConfiguration) GetWarnings() []string {
+	if r.HotStandbyFeedback == nil || !*r.HotStandbyFeedback {
+		return nil
+	}
+
+	var warnings []string
+	isLong := func(seconds *int32) bool {
+		return seconds != nil && (*seconds < 0 || *seconds > standbyDelayWarningThreshold)
+	}
+	if isLong(r.MaxStreamingDelay) || isLong(r.MaxArchiveDelay) {
+		warnings = append(warnings, fmt.Sprintf(
+			"hot_standby_feedback is enabled together with a standby delay longer than %d seconds: "+
+				"long-running queries on the replicas can prevent vacuum on the primary, causing bloat",
+			standbyDelayWarningThreshold))
+	}
+
+	return warnings
+}
+
+// ConnectionsConfiguration contains the limits on the connections
+// to the instances, protecting them from connection storms
+type ConnectionsConfiguration struct {
+	// The maximum number of concurrent connections to each
+	// instance (`max_connections`)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConnections *int32 `json:"maxConnections,omitempty"`
+
+	// The number of connection slots reserved to the superusers
+	// (`superuser_reserved_connections`)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	SuperuserReservedConnections *int32 `json:"superuserReservedConnections,omitempty"`
+}
+
+// GetManagedParameters gets the names of the parameters which can
+// be set by this connections configuration
+func (r ConnectionsConfiguration) GetManagedParameters() []string {
+	return []string{
+		"max_connections",
+		"superuser_reserved_connections",
+	}
+}
+
+// GetParameters gets the PostgreSQL parameters corresponding
+// to this connections configuration
+func (r ConnectionsConfiguration) GetParameters() map[string]string {
+	parameters := make(map[string]string)
+	if r.MaxConnections != nil {
+		parameters["max_connections"] = strconv.Itoa(int(*r.MaxConnections))
+	}
+	if r.SuperuserReservedConnections != nil {
+		parameters["superuser_reserved_connections"] = strconv.Itoa(int(*r.SuperuserReservedConnections))
+	}
+
+	return parameters
+}
+
+// LocaleConfiguration contains the time zone and the locale used by the
+// instances to display and interpret values and to write the log messages.
+// The locales must be available in the operand image
+type LocaleConfiguration struct {
+	// The time zone used to display and interpret the time stamps
+	// (`timezone`), as a name of the time zone database, e.g.
+	// `Europe/Rome`, or as a POSIX-style time zone specification
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// The time zone used in the time stamps of the log messages
+	// (`log_timezone`). Defaults to the value of timeZone
+	// +optional
+	LogTimeZone string `json:"logTimeZone,omitempty"`
+
+	// The language of the messages (`lc_messages`), e.g. `en_US.UTF-8`
+	// +optional
+	Messages string `json:"messages,omitempty"`
+
+	// The locale used to format the monetary amounts (`lc_monetary`)
+	// +optional
+	Monetary string `json:"monetary,omitempty"`
+
+	// The locale used to format the numbers (`lc_numeric`)
+	// +optional
+	Numeric string `json:"numeric,omitempty"`
+
+	// The locale used to format the dates and times (`lc_time`)
+	// +optional
+	Time string `json:"time,omitempty"`
+
+	// The display format for date and time values, and the rules for
+	// interpreting ambiguous date input values (`datestyle`), e.g.
+	// `ISO, DMY`
+	// +optional
+	DateStyle string `json:"dateStyle,omitempty"`
+}
+
+// GetManagedParameters gets the names of the parameters which can
+// be set by this locale configuration
+func (r LocaleConfiguration) GetManagedParameters() []string {
+	return []string{
+		"datestyle",
+		"lc_messages",
+		"lc_monetary",
+		"lc_numeric",
+		"lc_time",
+		"log_timezone",
+		"timezone",
+	}
+}
+
+// GetParameters gets the PostgreSQL parameters corresponding
+// to this locale configuration
+func (r LocaleConfiguration) GetParameters() map[string]string {
+	parameters := make(map[string]string)
+	setIfNotEmpty := func(key, value string) {
+		if value != "" {
+			parameters[key] = value
+		}
+	}
+
+	logTimeZone := r.LogTimeZone
+	if logTimeZone == "" {
+		logTimeZone = r.TimeZone
+	}
+
+	setIfNotEmpty("timezone", r.TimeZone)
+	setIfNotEmpty("log_timezone", logTimeZone)
+	setIfNotEmpty("lc_messages", r.Messages)
+	setIfNotEmpty("lc_monetary", r.Monetary)
+	setIfNotEmpty("lc_numeric", r.Numeric)
+	setIfNotEmpty("lc_time", r.Time)
+	setIfNotEmpty("datestyle", r.DateStyle)
+
+	return parameters
+}
+
+// PostgresLoggingPreset is a predefined set of values for the
+// PostgreSQL `log_*` parameters
+type PostgresLoggingPreset string
+
+const (
+	// PostgresLoggingPresetTerse only logs warnings, errors and
+	// the statements which failed
+	PostgresLoggingPresetTerse PostgresLoggingPreset = "terse"
+
+	// PostgresLoggingPresetDefault keeps the PostgreSQL defaults
+	PostgresLoggingPresetDefault PostgresLoggingPreset = "default"
+
+	// PostgresLoggingPresetVerbose logs connections, checkpoints, lock
+	// waits, temporary files, autovacuum runs and DDL statements
+	PostgresLoggingPresetVerbose PostgresLoggingPreset = "verbose"
+
+	// PostgresLoggingPresetDebug logs everything the verbose preset logs,
+	// together with every statement and the debug messages
+	PostgresLoggingPresetDebug PostgresLoggingPreset = "debug"
+)
+
+// PostgresLoggingConfiguration contains the configuration of
+// the PostgreSQL logging verbosity
+type PostgresLoggingConfiguration struct {
+	// The preset for the `log_*` parameters, one of `terse`,
+	// `default`, `verbose` and `debug`
+	// +kubebuilder:validation:Enum:=terse;default;verbose;debug
+	// +kubebuilder:default:=default
+	// +optional
+	Preset PostgresLoggingPreset `json:"preset,omitempty"`
+
+	// Log the statements whose execution took at least this number of
+	// milliseconds (`log_min_duration_statement`). Zero logs every
+	// statement, -1 disables this feature. Overrides the preset
+	// +kubebuilder:validation:Minimum=-1
+	// +optional
+	MinDurationStatement *int32 `json:"minDurationStatement,omitempty"`
+
+	// The statements to be logged (`log_statement`), one of `none`,
+	// `ddl`, `mod` and `all`. Overrides the preset
+	// +kubebuilder:validation:Enum:=none;ddl;mod;all
+	// +optional
+	Statement string `json:"statement,omitempty"`
+}
+
+// loggingPresetParameters contains the parameters corresponding
+// to each logging preset
+var loggingPresetParameters = map[PostgresLoggingPreset]map[string]string{
+	PostgresLoggingPresetTerse: {
+		"log_min_messages":           "warning",
+		"log_min_error_statement":    "error",
+		"log_min_duration_statement": "-1",
+		"log_statement":              "none",
+	},
+	PostgresLoggingPresetDefault: {},
+	PostgresLoggingPresetVerbose: {
+		"log_checkpoints":             "on",
+		"log_connections":             "on",
+		"log_disconnections":          "on",
+		"log_lock_waits":              "on",
+		"log_temp_files":              "0",
+		"log_autovacuum_min_duration": "0",
+		"log_statement":               "ddl",
+	},
+	PostgresLoggingPresetDebug: {
+		"log_checkpoints":             "on",
+		"log_connections":             "on",
+		"log_disconnections":          "on",
+		"log_lock_waits":              "on",
+		"log_temp_files":              "0",
+		"log_autovacuum_min_duration": "0",
+		"log_statement":               "all",
+		"log_min_messages":            "debug1",
+	},
+}
+
+// GetManagedParameters gets the names of the parameters which can
+// be set by this logging configuration
+func (r PostgresLoggingConfiguration) GetManagedParameters() []string {
+	managed := map[string]bool{
+		"log_min_duration_statement": true,
+		"log_statement":              true,
+	}
+	for _, parameters := range loggingPresetParameters {
+		for key := range parameters {
+			managed[key] = true
+		}
+	}
+
+	result := make([]string, 0, len(managed))
+	for key := range managed {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// GetParameters gets the PostgreSQL parameters corresponding
+// to this logging configuration
+func (r PostgresLoggingConfiguration) GetParameters() map[string]string {
+	parameters := make(map[string]string)
+	for key, value := range loggingPresetParameters[r.Preset] {
+		parameters[key] = value
+	}
+	if r.MinDurationStatement != nil {
+		parameters["log_min_duration_statement"] = strconv.Itoa(int(*r.MinDurationStatement))
+	}
+	if r.Statement != "" {
+		parameters["log_statement"] = r.Statement
+	}
+
+	return parameters
+}
+
+// GetParameters gets the PostgreSQL parameters requested by the user,
+// including the ones generated from the managed extensions and the
+// logging, standby and connections configurations
+func (r PostgresConfiguration) GetParameters() map[string]string {
+	if r.PgAudit == nil && r.Logging == nil && r.Standby == nil && r.Connections == nil &&
+		r.Locale == nil {
+		return r.Parameters
+	}
+
+	parameters := make(map[string]string, len(r.Parameters))
+	for key, value := range r.Parameters {
+		parameters[key] = value
+	}
+	if r.PgAudit != nil {
+		for key, value := range r.PgAudit.GetParameters() {
+			parameters[key] = value
+		}
+	}
+	if r.Logging != nil {
+		for key, value := range r.Logging.GetParameters() {
+			parameters[key] = value
+		}
+	}
+	if r.Standby != nil {
+		for key, value := range r.Standby.GetParameters() {
+			parameters[key] = value
+		}
+	}
+	if r.Connections != nil {
+		for key, value := range r.Connections.GetParameters() {
+			parameters[key] = value
+		}
+	}
+	if r.Locale != nil {
+		for key, value := range r.Locale.GetParameters() {
+			parameters[key] = value
+		}
+	}
+
+	return parameters
+}
+
+// GetMemoryTuningParameters gets the memory related parameters
+// suitable for a PostgreSQL instance having the passed amount of memory
+func GetMemoryTuningParameters(memory int64) map[string]string {
+	const megabyte = 1024 * 1024
+
+	maintenanceWorkMem := memory / 16
+	if maintenanceWorkMem > maximumTunedMaintenanceWorkMem {
+		maintenanceWorkMem = maximumTunedMaintenanceWorkMem
+	}
+
+	maxConnections := memory / tunedMemoryPerConnection
+	switch {
+	case maxConnections < minimumTunedMaxConnections:
+		maxConnections = minimumTunedMaxConnections
+	case maxConnections > maximumTunedMaxConnections:
+		maxConnections = maximumTunedMaxConnections
+	}
+
+	return map[string]string{
+		"shared_buffers":       fmt.Sprintf("%dMB", memory/4/megabyte),
+		"effective_cache_size": fmt.Sprintf("%dMB", memory*3/4/megabyte),
+		"maintenance_work_mem": fmt.Sprintf("%dMB", maintenanceWorkMem/megabyte),
+		"max_connections":      strconv.FormatInt(maxConnections, 10),
+	}
+}
+
+// GetEnabledExtensions gets the names of the managed extensions which
+// have been explicitly enabled
+func (r PostgresConfiguration) GetEnabledExtensions() []string {
+	var extensions []string
+	if r.PgAudit != nil {
+		extensions = append(extensions, "pgaudit")
+	}
+	if r.EnablePgStatStatements {
+		extensions = append(extensions, "pg_stat_statements")
+	}
+	if r.EnableAutoExplain {
+		extensions = append(extensions, "auto_explain")
+	}
+	return extensions
+}
+
+// BootstrapConfiguration contains information about how to create the PostgreSQL
+// cluster. Only a single bootstrap method can be defined among the supported
+// ones. `initdb` will be used as the bootstrap method if left
+// unspecified. Refer to the Bootstrap page of the documentation for more
+// information.
+type BootstrapConfiguration struct {
+	// Bootstrap the cluster via initdb
+	InitDB *BootstrapInitDB `json:"initdb,omitempty"`
+
+	// Bootstrap the cluster from a backup
+	Recovery *BootstrapRecovery `json:"recovery,omitempty"`
+
+	// Bootstrap the cluster taking a physical backup of another compatible
+	// PostgreSQL instance
+	PgBaseBackup *BootstrapPgBaseBackup `json:"pg_basebackup,omitempty"`
+
+	// Bootstrap the cluster adopting the PVCs of an existing instance,
+	// containing a PGDATA created by a compatible PostgreSQL version
+	Adopt *BootstrapAdopt `json:"adopt,omitempty"`
+}
+
+// BootstrapAdopt contains the configuration required to bootstrap the
+// first instance of the cluster from existing PersistentVolumeClaims,
+// i.e. the ones left behind by a deleted cluster having the same name.
+// The PVCs must be named after the instance, and must not be owned by
+// another resource.
+type BootstrapAdopt struct {
+	// The serial number of the instance whose PVCs will be adopted.
+	// The PGDATA PVC is expected to be named `<cluster-name>-<serial>`,
+	// and the WAL one, when `walStorage` is defined,
+	// `<cluster-name>-<serial>-wal`
+	// +kubebuilder:default:=1
+	// +kubebuilder:validation:Minimum=1
+	InstanceSerial int `json:"instanceSerial,omitempty"`
+}
+
+// LDAPScheme defines the possible schemes for LDAP
+type LDAPScheme string
 
 // These are the valid LDAP schemes
 const (
@@ -782,6 +2181,16 @@ type BootstrapInitDB struct {
 	// The value to be passed as option `--lc-ctype` for initdb (default:`C`)
 	LocaleCType string `json:"localeCType,omitempty"`
 
+	// The value to be passed as option `--locale-provider` for initdb
+	// (default: empty, resulting in PostgreSQL default: `libc`).
+	// Available from PostgreSQL 15
+	// +kubebuilder:validation:Enum=libc;icu
+	LocaleProvider string `json:"localeProvider,omitempty"`
+
+	// The value to be passed as option `--icu-locale` for initdb, required
+	// when the `icu` locale provider is used. Available from PostgreSQL 15
+	IcuLocale string `json:"icuLocale,omitempty"`
+
 	// The value in megabytes (1 to 1024) to be passed to the `--wal-segsize`
 	// option for initdb (default: empty, resulting in PostgreSQL default: 16MB)
 	// +kubebuilder:validation:Minimum=1
@@ -812,7 +2221,19 @@ type BootstrapInitDB struct {
 	// from all Secrets to all ConfigMaps, and inside Secrets or ConfigMaps,
 	// the implementation order is same as the order of each array
 	// (by default empty)
-	PostInitApplicationSQLRefs *PostInitApplicationSQLRefs `json:"postInitApplicationSQLRefs,omitempty"`
+	PostInitApplicationSQLRefs *SQLRefs `json:"postInitApplicationSQLRefs,omitempty"`
+
+	// PostInitSQLRefs points references to ConfigMaps or Secrets which
+	// contain SQL files to be executed as a superuser in the `postgres`
+	// database right after the cluster has been created, following the
+	// queries in `postInitSQL` (by default empty)
+	PostInitSQLRefs *SQLRefs `json:"postInitSQLRefs,omitempty"`
+
+	// PostInitTemplateSQLRefs points references to ConfigMaps or Secrets which
+	// contain SQL files to be executed as a superuser in the `template1`
+	// database right after the cluster has been created, following the
+	// queries in `postInitTemplateSQL` (by default empty)
+	PostInitTemplateSQLRefs *SQLRefs `json:"postInitTemplateSQLRefs,omitempty"`
 }
 
 // SnapshotType is a type of allowed import
@@ -853,11 +2274,11 @@ type ImportSource struct {
 	ExternalCluster string `json:"externalCluster"`
 }
 
-// PostInitApplicationSQLRefs points references to ConfigMaps or Secrets which
+// SQLRefs points references to ConfigMaps or Secrets which
 // contain SQL files, the general implementation order to these references is
 // from all Secrets to all ConfigMaps, and inside Secrets or ConfigMaps,
 // the implementation order is same as the order of each array
-type PostInitApplicationSQLRefs struct {
+type SQLRefs struct {
 	// SecretRefs holds a list of references to Secrets
 	SecretRefs []SecretKeySelector `json:"secretRefs,omitempty"`
 
@@ -876,9 +2297,16 @@ type BootstrapRecovery struct {
 
 	// The external cluster whose backup we will restore. This is also
 	// used as the name of the folder under which the backup is stored,
-	// so it must be set to the name of the source cluster
+	// so it must be set to the name of the source cluster.
+	// When recovering from volume snapshots, the WAL archive of this
+	// external cluster is used to replay the WAL files
 	Source string `json:"source,omitempty"`
 
+	// The volume snapshots to be cloned in the PVCs of the first instance,
+	// replacing the restore of a backup from the object store
+	// +optional
+	VolumeSnapshots *DataSource `json:"volumeSnapshots,omitempty"`
+
 	// By default, the recovery process applies all the available
 	// WAL files in the archive (full recovery). However, you can also
 	// end the recovery as soon as a consistent state is reached or
@@ -901,6 +2329,58 @@ type BootstrapRecovery struct {
 	// created from scratch
 	// +optional
 	Secret *LocalObjectReference `json:"secret,omitempty"`
+
+	// The remapping of the references to the source cluster, needed when
+	// restoring a backup into a different namespace or cluster name
+	// +optional
+	Remap *RecoveryRemapping `json:"remap,omitempty"`
+
+	// The maximum transfer rate of the backup restored from the object
+	// store. As `barman-cloud-restore` can't limit it, the instance
+	// manager pauses the restore whenever the data directory grows faster
+	// than this rate. Empty means no limit (default)
+	// +optional
+	MaxRate TransferRate `json:"maxRate,omitempty"`
+}
+
+// RecoveryRemapping contains the replacements of the references to the
+// source cluster contained in the backup being restored, like the names of
+// the secrets holding the credentials of the object store, which otherwise
+// make the recovery fail when the cluster is restored with another name or
+// in another namespace
+type RecoveryRemapping struct {
+	// The names of the secrets referenced by the backup, mapped to the
+	// names of the secrets to be used in their place
+	// +optional
+	Secrets map[string]string `json:"secrets,omitempty"`
+
+	// The path of the object store containing the backup, replacing the
+	// one recorded in the backup
+	// +optional
+	DestinationPath string `json:"destinationPath,omitempty"`
+
+	// The name of the folder containing the backup in the object store,
+	// replacing the one recorded in the backup
+	// +optional
+	ServerName string `json:"serverName,omitempty"`
+
+	// The names of the roles of the restored instance, mapped to their
+	// new names. The roles are renamed once the recovery is completed,
+	// before creating the owner of the application database
+	// +optional
+	Roles map[string]string `json:"roles,omitempty"`
+}
+
+// DataSource contains the configuration required to bootstrap a
+// PostgreSQL cluster from existing storage
+type DataSource struct {
+	// The data source of the storage of the instance. Kind and apiGroup
+	// default to VolumeSnapshot and snapshot.storage.k8s.io
+	Storage corev1.TypedLocalObjectReference `json:"storage"`
+
+	// The data source of the WAL storage of the instance
+	// +optional
+	WalStorage *corev1.TypedLocalObjectReference `json:"walStorage,omitempty"`
 }
 
 // BackupSource contains the backup we need to restore from, plus some
@@ -934,15 +2414,134 @@ type BootstrapPgBaseBackup struct {
 	// created from scratch
 	// +optional
 	Secret *LocalObjectReference `json:"secret,omitempty"`
+
+	// The options of `pg_basebackup` used to clone the source server
+	// +optional
+	Clone *CloneConfiguration `json:"clone,omitempty"`
 }
 
-// RecoveryTarget allows to configure the moment where the recovery process
-// will stop. All the target options except TargetTLI are mutually exclusive.
-type RecoveryTarget struct {
-	// The ID of the backup from which to start the recovery process.
-	// If empty (default) the operator will automatically detect the backup
-	// based on targetTime or targetLSN if specified. Otherwise use the
-	// latest available backup in chronological order.
+// CloneCompression is the compression method applied by the
+// source server to the data sent to pg_basebackup
+type CloneCompression string
+
+const (
+	// CloneCompressionGzip compresses the data with gzip
+	CloneCompressionGzip CloneCompression = "gzip"
+
+	// CloneCompressionLZ4 compresses the data with LZ4
+	CloneCompressionLZ4 CloneCompression = "lz4"
+
+	// CloneCompressionZstd compresses the data with Zstandard
+	CloneCompressionZstd CloneCompression = "zstd"
+)
+
+// CloneConfiguration contains the options of the pg_basebackup
+// invocation used to clone the data directory of a server
+type CloneConfiguration struct {
+	// The compression applied by the source server to the data directory
+	// before sending it, reducing the clone time over slow networks at
+	// the cost of CPU usage. It is passed to the `--compress` option of
+	// `pg_basebackup` as a server-side compression, and requires
+	// PostgreSQL 15 or newer. Empty means no compression (default)
+	// +kubebuilder:validation:Enum=gzip;lz4;zstd
+	// +optional
+	Compression CloneCompression `json:"compression,omitempty"`
+
+	// The compression level, whose range depends on the compression
+	// method: from 1 to 9 for `gzip`, to 12 for `lz4` and to 22 for
+	// `zstd`. When not set, the default level of the method is used
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=22
+	// +optional
+	CompressionLevel int `json:"compressionLevel,omitempty"`
+
+	// Whether to verify the data checksums of the source server while
+	// cloning it, when they are enabled. The clone fails when a checksum
+	// doesn't match. Defaults to true
+	// +kubebuilder:default:=true
+	// +optional
+	VerifyChecksums *bool `json:"verifyChecksums,omitempty"`
+
+	// When true, the clone is verified against its backup manifest using
+	// `pg_verifybackup` before starting the instance. Requires
+	// PostgreSQL 13 or newer
+	// +optional
+	VerifyBackup bool `json:"verifyBackup,omitempty"`
+
+	// The maximum transfer rate of the data directory from the source
+	// server, passed to the `--max-rate` option of `pg_basebackup`.
+	// Empty means no limit (default)
+	// +optional
+	MaxRate TransferRate `json:"maxRate,omitempty"`
+}
+
+// TransferRate is the maximum transfer rate of a copy of the data
+// directory, expressed in kilobytes per second unless the `k` or `M`
+// suffix is used (for example `32768k` or `32M`)
+// +kubebuilder:validation:Pattern=`^[0-9]+[kM]?$`
+type TransferRate string
+
+// GetBytesPerSecond gets the transfer rate in bytes per second,
+// zero when the rate is not limited
+func (rate TransferRate) GetBytesPerSecond() (int64, error) {
+	if rate == "" {
+		return 0, nil
+	}
+
+	value := string(rate)
+	multiplier := int64(1024)
+	switch {
+	case strings.HasSuffix(value, "M"):
+		value = strings.TrimSuffix(value, "M")
+		multiplier = 1024 * 1024
+	case strings.HasSuffix(value, "k"):
+		value = strings.TrimSuffix(value, "k")
+	}
+
+	result, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid transfer rate %q: %w", rate, err)
+	}
+	return result * multiplier, nil
+}
+
+// GetMaxCompressionLevel gets the maximum compression
+// level supported by a compression method
+func (compression CloneCompression) GetMaxCompressionLevel() int {
+	switch compression {
+	case CloneCompressionGzip:
+		return 9
+	case CloneCompressionLZ4:
+		return 12
+	case CloneCompressionZstd:
+		return 22
+	default:
+		return 0
+	}
+}
+
+// ShouldVerifyChecksums checks if the data checksums
+// should be verified while cloning the source server
+func (configuration *CloneConfiguration) ShouldVerifyChecksums() bool {
+	return configuration == nil || configuration.VerifyChecksums == nil || *configuration.VerifyChecksums
+}
+
+// GetMaxRate gets the maximum transfer rate of the clone,
+// empty when it is not limited
+func (configuration *CloneConfiguration) GetMaxRate() TransferRate {
+	if configuration == nil {
+		return ""
+	}
+	return configuration.MaxRate
+}
+
+// RecoveryTarget allows to configure the moment where the recovery process
+// will stop. All the target options except TargetTLI are mutually exclusive.
+type RecoveryTarget struct {
+	// The ID of the backup from which to start the recovery process.
+	// If empty (default) the operator will automatically detect the backup
+	// based on targetTime or targetLSN if specified. Otherwise use the
+	// latest available backup in chronological order.
 	BackupID string `json:"backupID,omitempty"`
 
 	// The target timeline ("latest" or a positive integer)
@@ -990,6 +2589,48 @@ type StorageConfiguration struct {
 	// Template to be used to generate the Persistent Volume Claim
 	// +optional
 	PersistentVolumeClaimTemplate *corev1.PersistentVolumeClaimSpec `json:"pvcTemplate,omitempty"`
+
+	// The policy describing what happens to the PVCs of the instances
+	// when the cluster is deleted or scaled down. It can only be set in
+	// the `storage` section, and it applies to the WAL PVCs too.
+	// +optional
+	PersistentVolumeClaimRetentionPolicy *PVCRetentionPolicy `json:"persistentVolumeClaimRetentionPolicy,omitempty"`
+}
+
+// PVCRetentionPolicyType is the type of the retention policy applied
+// to the PVCs of the instances
+type PVCRetentionPolicyType string
+
+const (
+	// PVCRetentionPolicyRetain means that the PVCs are kept, detached
+	// from the instance or the cluster they belonged to
+	PVCRetentionPolicyRetain PVCRetentionPolicyType = "Retain"
+
+	// PVCRetentionPolicyDelete means that the PVCs are deleted together
+	// with the instance or the cluster they belonged to
+	PVCRetentionPolicyDelete PVCRetentionPolicyType = "Delete"
+)
+
+// PVCRetentionPolicy describes what happens to the PVCs of the instances
+// when the cluster is deleted or scaled down
+type PVCRetentionPolicy struct {
+	// What happens to the PVCs when the cluster is deleted. With `Retain`
+	// the PVCs are released from the cluster and kept in the namespace,
+	// with `Delete` they are removed together with the cluster.
+	// Defaults to `Delete`
+	// +kubebuilder:validation:Enum:=Retain;Delete
+	// +kubebuilder:default:=Delete
+	// +optional
+	WhenDeleted PVCRetentionPolicyType `json:"whenDeleted,omitempty"`
+
+	// What happens to the PVCs of an instance removed because the cluster
+	// has been scaled down. With `Retain` the PVCs are kept and marked as
+	// detached, with `Delete` they are removed together with the instance.
+	// Defaults to `Delete`
+	// +kubebuilder:validation:Enum:=Retain;Delete
+	// +kubebuilder:default:=Delete
+	// +optional
+	WhenScaled PVCRetentionPolicyType `json:"whenScaled,omitempty"`
 }
 
 // SyncReplicaElectionConstraints contains the constraints for sync replicas election.
@@ -1004,6 +2645,26 @@ type SyncReplicaElectionConstraints struct {
 
 	// A list of node labels values to extract and compare to evaluate if the pods reside in the same topology or not
 	NodeLabelsAntiAffinity []string `json:"nodeLabelsAntiAffinity,omitempty"`
+
+	// When true, the synchronous replicas are required to run in a topology
+	// zone different from the one of the primary, as reported by the
+	// `topology.kubernetes.io/zone` node label. The replicas running on nodes
+	// without that label are not electable
+	// +optional
+	ZoneAntiAffinity bool `json:"zoneAntiAffinity,omitempty"`
+}
+
+// GetNodeLabelsAntiAffinity gets the node labels to be compared to evaluate
+// if the pods reside in the same topology, including the zone label when
+// the zone anti-affinity is requested
+func (constraints SyncReplicaElectionConstraints) GetNodeLabelsAntiAffinity() []string {
+	if !constraints.ZoneAntiAffinity || slices.Contains(constraints.NodeLabelsAntiAffinity, corev1.LabelTopologyZone) {
+		return constraints.NodeLabelsAntiAffinity
+	}
+
+	labels := make([]string, 0, len(constraints.NodeLabelsAntiAffinity)+1)
+	labels = append(labels, constraints.NodeLabelsAntiAffinity...)
+	return append(labels, corev1.LabelTopologyZone)
 }
 
 // AffinityConfiguration contains the info we need to create the
@@ -1032,9 +2693,10 @@ type AffinityConfiguration struct {
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
 	// PodAntiAffinityType allows the user to decide whether pod anti-affinity between cluster instance has to be
-	// considered a strong requirement during scheduling or not. Allowed values are: "preferred" (default if empty) or
-	// "required". Setting it to "required", could lead to instances remaining pending until new kubernetes nodes are
-	// added if all the existing nodes don't match the required pod anti-affinity rule.
+	// considered a strong requirement during scheduling or not. Allowed values are: "preferred" or "required"
+	// (default for the new clusters, unless disabled in the `generatedDefaults` section). Setting it to "required",
+	// could lead to instances remaining pending until new kubernetes nodes are added if all the existing nodes
+	// don't match the required pod anti-affinity rule.
 	// More info:
 	// https://kubernetes.io/docs/concepts/scheduling-eviction/assign-pod-node/#inter-pod-affinity-and-anti-affinity
 	// +optional
@@ -1048,6 +2710,34 @@ type AffinityConfiguration struct {
 	// AdditionalPodAffinity allows to specify pod affinity terms to be passed to all the cluster's pods.
 	// +optional
 	AdditionalPodAffinity *corev1.PodAffinity `json:"additionalPodAffinity,omitempty"`
+
+	// EnableArchitectureAffinity makes the operator detect the architectures
+	// provided by the PostgreSQL image, reading its manifest from the registry,
+	// and schedule the pods only on the nodes having one of them.
+	// Useful when the Kubernetes cluster has nodes with different architectures
+	// and the image is not available for all of them. Default: false
+	// +optional
+	EnableArchitectureAffinity bool `json:"enableArchitectureAffinity,omitempty"`
+}
+
+// ImageArchitecturesStatus contains the architectures provided by
+// the PostgreSQL image
+type ImageArchitecturesStatus struct {
+	// The image whose architectures have been detected
+	Image string `json:"image"`
+
+	// The Linux architectures provided by the image, with the
+	// names used by the `kubernetes.io/arch` node label
+	// +optional
+	Architectures []string `json:"architectures,omitempty"`
+
+	// The error raised while detecting the architectures, if any
+	// +optional
+	Error string `json:"error,omitempty"`
+
+	// The time of the last detection
+	// +optional
+	LastCheckTime metav1.Time `json:"lastCheckTime,omitempty"`
 }
 
 // RollingUpdateStatus contains the information about an instance which is
@@ -1169,8 +2859,184 @@ type BackupConfiguration struct {
 	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwm]$
 	// +optional
 	RetentionPolicy string `json:"retentionPolicy,omitempty"`
+
+	// The policy to decide which instance should perform backups. Available
+	// options are empty string, which will default to `primary` policy,
+	// `primary` to have backups run always on primary instances,
+	// `prefer-standby` to have backups run preferably on a ready standby,
+	// falling back to the primary if no standby is available.
+	// +kubebuilder:validation:Enum=primary;prefer-standby
+	// +kubebuilder:default:=primary
+	// +optional
+	Target BackupTarget `json:"target,omitempty"`
+
+	// The additional object stores where the WAL files are archived and
+	// the base backups are uploaded, together with the one configured in
+	// `barmanObjectStore`, each one with its own retention policy
+	// +optional
+	AdditionalObjectStores []AdditionalObjectStore `json:"additionalObjectStores,omitempty"`
+
+	// The periodic reconciliation of the content of `barmanObjectStore`
+	// against the Backup resources, reporting the orphaned base backups
+	// and WAL files in the status of the cluster
+	// +optional
+	GarbageCollection *BackupGarbageCollectionConfiguration `json:"garbageCollection,omitempty"`
+
+	// Allow the Backup and ScheduledBackup resources of this cluster to
+	// run hooks. The hooks run SQL statements as the superuser and commands
+	// in the PostgreSQL container, so they are refused unless the owner of
+	// the cluster enables them. Defaults to false
+	// +optional
+	AllowHooks bool `json:"allowHooks,omitempty"`
+}
+
+// BackupGarbageCollectionConfiguration contains the configuration of the
+// periodic reconciliation of the object store against the Backup resources
+type BackupGarbageCollectionConfiguration struct {
+	// The schedule of the reconciliation, following the same format used
+	// by the scheduled backups, see
+	// https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format
+	Schedule string `json:"schedule"`
+
+	// When true, the retention policy is applied at every reconciliation,
+	// deleting the base backups and the WAL files which are not needed
+	// anymore, even when no backup has been taken recently, together with
+	// the Backup resources whose base backup has been deleted. The orphaned
+	// base backups which are still within the retention policy are only
+	// reported. Requires `retentionPolicy` to be set
+	// +optional
+	ApplyRetentionPolicy bool `json:"applyRetentionPolicy,omitempty"`
+}
+
+// ObjectStoreReport is the result of the last reconciliation
+// of the object store against the Backup resources
+type ObjectStoreReport struct {
+	// When the object store has been reconciled, stored as a date in
+	// RFC3339 format
+	LastCheckTime string `json:"lastCheckTime,omitempty"`
+
+	// The IDs of the completed base backups in the object store which
+	// are not referenced by any Backup resource of the cluster
+	// +optional
+	OrphanedBackups []string `json:"orphanedBackups,omitempty"`
+
+	// The WAL files needed only to recover from the orphaned base backups,
+	// which are older than any base backup referenced by a Backup resource
+	// +optional
+	OrphanedWALRange *WALRange `json:"orphanedWALRange,omitempty"`
+
+	// The IDs of the base backups deleted by the retention
+	// policy during the last reconciliation
+	// +optional
+	DeletedBackups []string `json:"deletedBackups,omitempty"`
+}
+
+// WALRange is a range of WAL files
+type WALRange struct {
+	// The first WAL file of the range, included
+	Begin string `json:"begin"`
+
+	// The last WAL file of the range, excluded
+	End string `json:"end"`
+}
+
+// AdditionalObjectStore is an object store receiving a copy of
+// the WAL files and of the base backups of the cluster
+type AdditionalObjectStore struct {
+	// The name of the object store, unique in the cluster
+	// +kubebuilder:validation:Pattern=^[a-z0-9]([-a-z0-9]*[a-z0-9])?$
+	Name string `json:"name"`
+
+	// The configuration for the barman-cloud tool suite
+	BarmanObjectStore BarmanObjectStoreConfiguration `json:"barmanObjectStore"`
+
+	// RetentionPolicy is the retention policy to be used for the backups
+	// and the WALs in this object store (i.e. '60d'). The retention policy
+	// is expressed in the form of `XXu` where `XX` is a positive integer
+	// and `u` is in `[dwm]` - days, weeks, months.
+	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwm]$
+	// +optional
+	RetentionPolicy string `json:"retentionPolicy,omitempty"`
+
+	// Whether the base backups are uploaded to this object store too.
+	// Each object store receiving the base backups reads the data files
+	// again with its own barman-cloud-backup run, so this can be disabled
+	// to only archive the WAL files in it. Defaults to true
+	// +optional
+	BaseBackups *bool `json:"baseBackups,omitempty"`
 }
 
+// GetServerName gets the name of the server in the object store,
+// defaulting to the name of the cluster
+func (store AdditionalObjectStore) GetServerName(clusterName string) string {
+	if store.BarmanObjectStore.ServerName != "" {
+		return store.BarmanObjectStore.ServerName
+	}
+	return clusterName
+}
+
+// AreBaseBackupsEnabled checks if the base backups
+// are uploaded to the object store
+func (store AdditionalObjectStore) AreBaseBackupsEnabled() bool {
+	return store.BaseBackups == nil || *store.BaseBackups
+}
+
+// AdditionalObjectStoreStatus is the status of an additional object store
+type AdditionalObjectStoreStatus struct {
+	// The name of the additional object store
+	Name string `json:"name"`
+
+	// True when the last WAL file couldn't be archived in the object store
+	// +optional
+	WALArchivingFailing bool `json:"walArchivingFailing,omitempty"`
+
+	// The last WAL file which couldn't be archived in the object store.
+	// The WAL files failing in an additional object store are not
+	// archived there again, leaving a gap in its WAL archive
+	// +optional
+	LastFailedWAL string `json:"lastFailedWAL,omitempty"`
+
+	// When the last WAL file failed, stored as a date in RFC3339 format
+	// +optional
+	LastFailedWALTime string `json:"lastFailedWALTime,omitempty"`
+
+	// The error raised archiving the last failed WAL file
+	// +optional
+	LastWALArchivingError string `json:"lastWALArchivingError,omitempty"`
+
+	// The last successful upload of a backup, stored as a date in RFC3339 format
+	// +optional
+	LastSuccessfulBackup string `json:"lastSuccessfulBackup,omitempty"`
+
+	// The last failed upload of a backup, stored as a date in RFC3339 format
+	// +optional
+	LastFailedBackup string `json:"lastFailedBackup,omitempty"`
+}
+
+// GetAdditionalObjectStoreStatus gets the status of an additional
+// object store, adding it to the cluster status when missing
+func (status *ClusterStatus) GetAdditionalObjectStoreStatus(name string) *AdditionalObjectStoreStatus {
+	for idx := range status.AdditionalObjectStores {
+		if status.AdditionalObjectStores[idx].Name == name {
+			return &status.AdditionalObjectStores[idx]
+		}
+	}
+
+	status.AdditionalObjectStores = append(status.AdditionalObjectStores, AdditionalObjectStoreStatus{Name: name})
+	return &status.AdditionalObjectStores[len(status.AdditionalObjectStores)-1]
+}
+
+// BackupTarget describes the preferred targets for a backup
+type BackupTarget string
+
+const (
+	// BackupTargetPrimary means backups will be performed on the primary instance
+	BackupTargetPrimary = BackupTarget("primary")
+
+	// BackupTargetStandby means backups will be performed on a standby instance if available
+	BackupTargetStandby = BackupTarget("prefer-standby")
+)
+
 // WalBackupConfiguration is the configuration of the backup of the
 // WAL stream
 type WalBackupConfiguration struct {
@@ -1223,6 +3089,13 @@ type DataBackupConfiguration struct {
 	// to 2
 	// +kubebuilder:validation:Minimum=1
 	Jobs *int32 `json:"jobs,omitempty"`
+
+	// The maximum amount of data to be uploaded per second by each
+	// backup, for example `50M`. It requires Barman >= 2.19. Empty
+	// means no limit (default)
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?([kMGT]i?)?$`
+	// +optional
+	MaxBandwidth string `json:"maxBandwidth,omitempty"`
 }
 
 // S3Credentials is the type for the credentials to be used to upload
@@ -1262,32 +3135,485 @@ type AzureCredentials struct {
 	// The connection string to be used
 	ConnectionString *SecretKeySelector `json:"connectionString,omitempty"`
 
-	// The storage account where to upload data
-	StorageAccount *SecretKeySelector `json:"storageAccount,omitempty"`
+	// The storage account where to upload data
+	StorageAccount *SecretKeySelector `json:"storageAccount,omitempty"`
+
+	// The storage account key to be used in conjunction
+	// with the storage account name
+	StorageKey *SecretKeySelector `json:"storageKey,omitempty"`
+
+	// A shared-access-signature to be used in conjunction with
+	// the storage account name
+	StorageSasToken *SecretKeySelector `json:"storageSasToken,omitempty"`
+
+	// Use the Azure AD based authentication without providing explicitly the keys.
+	// This is the setting to be used with Azure AD Workload Identity, where the
+	// client ID is taken from the annotations of the ServiceAccount used by the
+	// instances (see `serviceAccountTemplate` in the Cluster spec)
+	// +optional
+	InheritFromAzureAD bool `json:"inheritFromAzureAD"`
+}
+
+// GoogleCredentials is the type for the Google Cloud Storage credentials.
+// This needs to be specified even if we run inside a GKE environment.
+type GoogleCredentials struct {
+	// If set to true, will presume that it's running inside a GKE environment,
+	// default to false.
+	// +optional
+	GKEEnvironment bool `json:"gkeEnvironment"`
+
+	// The secret containing the Google Cloud Storage JSON file with the credentials
+	ApplicationCredentials *SecretKeySelector `json:"applicationCredentials,omitempty"`
+}
+
+// DriftDetectionConfiguration contains the configuration of the periodic
+// comparison of the Services, Secrets, ConfigMaps, PodDisruptionBudgets
+// and Pods generated by the operator with their expected definition
+type DriftDetectionConfiguration struct {
+	// When true (default), the operator restores the expected definition
+	// of the generated resources, rolling out the Pods whose specification
+	// changed. When false, the generated resources are not changed once
+	// created, and their differences from the expected definition are only
+	// reported in the `ResourcesInSync` condition
+	// +optional
+	AutoRepair *bool `json:"autoRepair,omitempty"`
+
+	// The interval between two periodic reconciliations of the cluster, in
+	// which the generated resources are compared with their expected
+	// definition. Defaults to 5 minutes
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+// defaultDriftDetectionInterval is the default interval
+// between two periodic reconciliations of a cluster
+const defaultDriftDetectionInterval = 5 * time.Minute
+
+// minimumDriftDetectionInterval is the minimum interval
+// between two periodic reconciliations of a cluster
+const minimumDriftDetectionInterval = 30 * time.Second
+
+// ImageCatalogRef defines the reference to a major version in an
+// ImageCatalog or in a ClusterImageCatalog
+type ImageCatalogRef struct {
+	// The reference to the catalog, whose kind can be either
+	// `ImageCatalog` or `ClusterImageCatalog`
+	corev1.TypedLocalObjectReference `json:",inline"`
+
+	// The major version of PostgreSQL we want to use from the catalog
+	// +kubebuilder:validation:Minimum=10
+	Major int `json:"major"`
+}
+
+// ImageUpdateConfiguration contains the policy for the rolling update
+// of the instances when the PostgreSQL image changes
+type ImageUpdateConfiguration struct {
+	// When true, the rolling update to a new image is suspended, and the
+	// instances keep using their current image until it is resumed
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// The maintenance windows in which the rolling update to a new image
+	// can be started. When empty, it is started as soon as the image changes
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// MaintenanceWindow is a recurring interval of time in which
+// the operator can perform disruptive operations
+type MaintenanceWindow struct {
+	// The days of the week in which the window starts. When
+	// empty, the window starts every day
+	// +optional
+	Days []MaintenanceWindowDay `json:"days,omitempty"`
+
+	// The time when the window starts, in the `HH:MM` format (UTC)
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	StartTime string `json:"startTime"`
+
+	// The duration of the window, up to 7 days
+	Duration metav1.Duration `json:"duration"`
+}
+
+// MaintenanceWindowDay is a day of the week
+// +kubebuilder:validation:Enum=Sunday;Monday;Tuesday;Wednesday;Thursday;Friday;Saturday
+type MaintenanceWindowDay string
+
+// maximumMaintenanceWindowDuration is the maximum duration of a maintenance window
+const maximumMaintenanceWindowDuration = 7 * 24 * time.Hour
+
+// Contains checks if the passed time is inside the maintenance window
+func (window MaintenanceWindow) Contains(now time.Time) bool {
+	startTime, err := time.Parse("15:04", window.StartTime)
+	if err != nil {
+		return false
+	}
+
+	now = now.UTC()
+	// The window may have started in one of the previous days
+	for daysAgo := 0; daysAgo <= 7; daysAgo++ {
+		day := now.AddDate(0, 0, -daysAgo)
+		start := time.Date(day.Year(), day.Month(), day.Day(),
+			startTime.Hour(), startTime.Minute(), 0, 0, time.UTC)
+		if !window.startsOn(start.Weekday()) {
+			continue
+		}
+		if !now.Before(start) && now.Before(start.Add(window.Duration.Duration)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NextStart gets the first time, after the passed one, when the maintenance
+// window starts. It returns the zero time if the window is not valid
+func (window MaintenanceWindow) NextStart(now time.Time) time.Time {
+	startTime, err := time.Parse("15:04", window.StartTime)
+	if err != nil {
+		return time.Time{}
+	}
+
+	now = now.UTC()
+	for daysAhead := 0; daysAhead <= 7; daysAhead++ {
+		day := now.AddDate(0, 0, daysAhead)
+		start := time.Date(day.Year(), day.Month(), day.Day(),
+			startTime.Hour(), startTime.Minute(), 0, 0, time.UTC)
+		if window.startsOn(start.Weekday()) && start.After(now) {
+			return start
+		}
+	}
+
+	return time.Time{}
+}
+
+// startsOn checks if the window starts on the passed day of the week
+func (window MaintenanceWindow) startsOn(weekday time.Weekday) bool {
+	if len(window.Days) == 0 {
+		return true
+	}
+	for _, day := range window.Days {
+		if string(day) == weekday.String() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsImageUpdateAllowed checks if the rolling update to a new image
+// can be started at the passed time
+func (cluster *Cluster) IsImageUpdateAllowed(now time.Time) bool {
+	policy := cluster.Spec.ImageUpdate
+	if policy == nil {
+		return true
+	}
+	if policy.Paused {
+		return false
+	}
+	return isInMaintenanceWindows(policy.MaintenanceWindows, now)
+}
+
+// GetNextImageUpdateWindowStart gets the first time, after the passed one,
+// when one of the maintenance windows of the image update policy starts.
+// It returns false if there's no such time, i.e. when the image update
+// is paused or no maintenance window is defined
+func (cluster *Cluster) GetNextImageUpdateWindowStart(now time.Time) (time.Time, bool) {
+	policy := cluster.Spec.ImageUpdate
+	if policy == nil || policy.Paused {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	for _, window := range policy.MaintenanceWindows {
+		start := window.NextStart(now)
+		if !start.IsZero() && (next.IsZero() || start.Before(next)) {
+			next = start
+		}
+	}
+
+	return next, !next.IsZero()
+}
+
+// IsPrimaryUpdateAllowed checks if the primary instance can be
+// restarted or switched over at the passed time
+func (cluster *Cluster) IsPrimaryUpdateAllowed(now time.Time) bool {
+	return isInMaintenanceWindows(cluster.Spec.PrimaryUpdateWindows, now)
+}
+
+// isInMaintenanceWindows checks if the passed time is inside one of
+// the maintenance windows. An empty list of windows allows any time
+func isInMaintenanceWindows(windows []MaintenanceWindow, now time.Time) bool {
+	if len(windows) == 0 {
+		return true
+	}
+	for _, window := range windows {
+		if window.Contains(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// DiskSpaceConfiguration contains the configuration of the monitoring
+// of the disk space used by the volumes of the instances
+type DiskSpaceConfiguration struct {
+	// The percentage of used space of a volume above which a warning
+	// event is emitted (default 80)
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	WarningThreshold int32 `json:"warningThreshold,omitempty"`
+
+	// The percentage of used space of a volume above which a critical
+	// event is emitted (default 95)
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	CriticalThreshold int32 `json:"criticalThreshold,omitempty"`
+
+	// When enabled, the primary runs a `CHECKPOINT` when the usage of the
+	// volume containing the WAL files is above the critical threshold,
+	// allowing PostgreSQL to remove the WAL files which are not needed anymore
+	// +optional
+	CheckpointOnCritical bool `json:"checkpointOnCritical,omitempty"`
+}
+
+// ManagedConfiguration contains the objects of the databases
+// which are managed by the instance manager of the primary
+type ManagedConfiguration struct {
+	// The extensions to be created, updated or dropped in the databases
+	// +optional
+	Extensions []ManagedExtension `json:"extensions,omitempty"`
+
+	// The connection limits and the default settings of existing roles
+	// +optional
+	Roles []ManagedRole `json:"roles,omitempty"`
+}
+
+// ManagedRole contains the connection limit and the default settings
+// of an existing role, which are managed by the instance manager of
+// the primary. The role is not created by the operator
+type ManagedRole struct {
+	// The name of the role
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The maximum number of concurrent connections the role can open.
+	// -1 means no limit. When not specified, the limit is not managed
+	// +kubebuilder:validation:Minimum=-1
+	// +optional
+	ConnectionLimit *int32 `json:"connectionLimit,omitempty"`
+
+	// The default values of the configuration parameters for the
+	// sessions of the role, applied with `ALTER ROLE ... SET`, like
+	// `statement_timeout`. The settings which are not listed here are
+	// left untouched
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// ManagedRolesStatus contains the status of the
+// reconciliation of the managed roles
+type ManagedRolesStatus struct {
+	// The roles which are in the requested state
+	// +optional
+	Reconciled []string `json:"reconciled,omitempty"`
+
+	// The roles which cannot be reconciled, with the
+	// error which has been found
+	// +optional
+	CannotReconcile map[string]string `json:"cannotReconcile,omitempty"`
+}
+
+// EnsureOption represents whether an object should be present or absent
+type EnsureOption string
+
+const (
+	// EnsurePresent means that the object should be present
+	EnsurePresent EnsureOption = "present"
+
+	// EnsureAbsent means that the object should be absent
+	EnsureAbsent EnsureOption = "absent"
+)
+
+// ManagedExtension is an extension managed by the instance manager
+// of the primary in a database
+type ManagedExtension struct {
+	// The name of the extension
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The database where the extension is managed
+	// +kubebuilder:validation:MinLength=1
+	Database string `json:"database"`
+
+	// The version of the extension. When empty, the extension is created
+	// with its default version, and it is never updated
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// The schema where the objects of the extension are created.
+	// When empty, the first schema of the `search_path` is used when
+	// creating the extension, and it is never moved
+	// +optional
+	Schema string `json:"schema,omitempty"`
+
+	// Whether the extension should be present (default) or absent
+	// in the database
+	// +kubebuilder:validation:Enum=present;absent
+	// +kubebuilder:default:=present
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+}
+
+// GetKey gets the key identifying the extension in
+// the status, in the `database/name` format
+func (extension ManagedExtension) GetKey() string {
+	return fmt.Sprintf("%s/%s", extension.Database, extension.Name)
+}
+
+// ManagedExtensionsStatus contains the status of the
+// reconciliation of the managed extensions
+type ManagedExtensionsStatus struct {
+	// The extensions which are in the requested state,
+	// in the `database/name` format
+	// +optional
+	Reconciled []string `json:"reconciled,omitempty"`
+
+	// The extensions which cannot be reconciled, in the `database/name`
+	// format, with the error which has been found
+	// +optional
+	CannotReconcile map[string]string `json:"cannotReconcile,omitempty"`
+}
+
+// DataVerificationMethod is the method used to verify
+// the data of the instances
+type DataVerificationMethod string
+
+const (
+	// DataVerificationMethodAmcheck verifies the indexes and the tables
+	// of the running instances using the amcheck extension
+	DataVerificationMethodAmcheck DataVerificationMethod = "amcheck"
+
+	// DataVerificationMethodChecksums verifies the data checksums
+	// of the fenced instances using pg_checksums
+	DataVerificationMethodChecksums DataVerificationMethod = "checksums"
+)
+
+// DataVerificationConfiguration contains the configuration of the
+// periodic verification of the data of the instances
+type DataVerificationConfiguration struct {
+	// The schedule of the verification, following the same format used
+	// by the scheduled backups, see
+	// https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format
+	Schedule string `json:"schedule"`
+
+	// The verification method: `amcheck` (default) verifies online the
+	// B-tree indexes and, from PostgreSQL 14, the tables of every database
+	// where the amcheck extension is installed; `checksums` fences one
+	// standby at a time, in turn, and runs `pg_checksums --check` on it
+	// +kubebuilder:validation:Enum=amcheck;checksums
+	// +kubebuilder:default:=amcheck
+	// +optional
+	Method DataVerificationMethod `json:"method,omitempty"`
+
+	// When true, the primary is verified too. The `amcheck` verification
+	// adds I/O load to the primary, while the `checksums` one fences it,
+	// causing a downtime. Default: false
+	// +optional
+	IncludePrimary bool `json:"includePrimary,omitempty"`
+}
+
+// GetMethod gets the verification method, defaulting to amcheck
+func (configuration *DataVerificationConfiguration) GetMethod() DataVerificationMethod {
+	if configuration.Method == "" {
+		return DataVerificationMethodAmcheck
+	}
+	return configuration.Method
+}
+
+// GeneratedDefaultsConfiguration allows opting out of the defaults
+// generated by the operator when the cluster is created. The existing
+// clusters are never changed
+type GeneratedDefaultsConfiguration struct {
+	// When true, the pod anti-affinity type defaults to `preferred`
+	// instead of `required`, allowing more than one instance to run
+	// on the same node
+	// +optional
+	DisableRequiredPodAntiAffinity bool `json:"disableRequiredPodAntiAffinity,omitempty"`
+
+	// When true, the PostgreSQL containers are created without resource
+	// requests unless specified in the `resources` section
+	// +optional
+	DisableResources bool `json:"disableResources,omitempty"`
+
+	// When true, the timings of the probes are not written in the
+	// `probes` section, and follow the defaults of the operator in use
+	// +optional
+	DisableProbes bool `json:"disableProbes,omitempty"`
+}
+
+// ProbesConfiguration represent the configuration for the probes
+// to be injected in the PostgreSQL Pods
+type ProbesConfiguration struct {
+	// The startup probe configuration. Unless the failure
+	// threshold is specified, it is computed from `startDelay`
+	// +optional
+	Startup *Probe `json:"startup,omitempty"`
 
-	// The storage account key to be used in conjunction
-	// with the storage account name
-	StorageKey *SecretKeySelector `json:"storageKey,omitempty"`
+	// The liveness probe configuration
+	// +optional
+	Liveness *Probe `json:"liveness,omitempty"`
 
-	// A shared-access-signature to be used in conjunction with
-	// the storage account name
-	StorageSasToken *SecretKeySelector `json:"storageSasToken,omitempty"`
+	// The readiness probe configuration
+	// +optional
+	Readiness *ReadinessProbe `json:"readiness,omitempty"`
+}
 
-	// Use the Azure AD based authentication without providing explicitly the keys.
+// Probe is the tuning of a probe of the PostgreSQL container.
+// Each field left empty keeps the default value chosen by the operator
+type Probe struct {
+	// Number of seconds after the container has started before the probe
+	// is initiated
+	// +kubebuilder:validation:Minimum=0
 	// +optional
-	InheritFromAzureAD bool `json:"inheritFromAzureAD"`
+	InitialDelaySeconds int32 `json:"initialDelaySeconds,omitempty"`
+
+	// Number of seconds after which the probe times out
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// How often (in seconds) to perform the probe
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+
+	// Minimum consecutive failures for the probe to be considered failed
+	// after having succeeded
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
 }
 
-// GoogleCredentials is the type for the Google Cloud Storage credentials.
-// This needs to be specified even if we run inside a GKE environment.
-type GoogleCredentials struct {
-	// If set to true, will presume that it's running inside a GKE environment,
-	// default to false.
+// ReadinessProbe is the configuration of the readiness probe
+// of the PostgreSQL container
+type ReadinessProbe struct {
+	Probe `json:",inline"`
+
+	// The criteria used to consider an instance ready: accepting
+	// connections (`query` - default) or, for replicas, also streaming
+	// WAL from the source (`streaming`)
+	// +kubebuilder:validation:Enum:=query;streaming
 	// +optional
-	GKEEnvironment bool `json:"gkeEnvironment"`
+	Type ReadinessProbeType `json:"type,omitempty"`
 
-	// The secret containing the Google Cloud Storage JSON file with the credentials
-	ApplicationCredentials *SecretKeySelector `json:"applicationCredentials,omitempty"`
+	// The maximum replay lag, in bytes, of a replica to be considered
+	// ready. Once exceeded, the replica is ready again only when its
+	// replay lag goes below half of this value. Only available with the
+	// `streaming` type
+	// +optional
+	MaximumLag *resource.Quantity `json:"maximumLag,omitempty"`
 }
 
 // MonitoringConfiguration is the type containing all the monitoring
@@ -1308,6 +3634,57 @@ type MonitoringConfiguration struct {
 	// Enable or disable the `PodMonitor`
 	// +kubebuilder:default:=false
 	EnablePodMonitor bool `json:"enablePodMonitor,omitempty"`
+
+	// Enable or disable the `PrometheusRule` containing the default
+	// alerting rules for the cluster
+	// +kubebuilder:default:=false
+	// +optional
+	EnablePrometheusRule bool `json:"enablePrometheusRule,omitempty"`
+
+	// Configure TLS communication for the metrics endpoint
+	// +optional
+	TLSConfig *ClusterMonitoringTLSConfiguration `json:"tls,omitempty"`
+
+	// The secret containing the bearer token that the clients of the
+	// metrics endpoint are required to present
+	// +optional
+	BearerTokenSecret *SecretKeySelector `json:"bearerTokenSecret,omitempty"`
+
+	// Restrict the databases discovered through the patterns in the
+	// `target_databases` option of the monitoring queries
+	// +optional
+	DatabaseDiscovery *MonitoringDatabaseDiscovery `json:"databaseDiscovery,omitempty"`
+}
+
+// MonitoringDatabaseDiscovery contains the shell-like patterns restricting
+// the databases where the monitoring queries are executed when their
+// `target_databases` option contains a pattern
+type MonitoringDatabaseDiscovery struct {
+	// The patterns of the databases which can be discovered.
+	// By default, every database accepting connections which is
+	// not a template can be discovered
+	// +optional
+	Include []string `json:"include,omitempty"`
+
+	// The patterns of the databases which are never discovered
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// ClusterMonitoringTLSConfiguration is the type containing the TLS
+// configuration for the metrics endpoint of the instances
+type ClusterMonitoringTLSConfiguration struct {
+	// Enable TLS for the metrics endpoint, using the server
+	// certificate of the cluster
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Require the clients of the metrics endpoint to present a
+	// certificate signed by the client CA of the cluster.
+	// Requires TLS to be enabled
+	// +optional
+	RequireClientCertificate bool `json:"requireClientCertificate,omitempty"`
 }
 
 // AreDefaultQueriesDisabled checks whether default monitoring queries should be disabled
@@ -1315,6 +3692,17 @@ func (m *MonitoringConfiguration) AreDefaultQueriesDisabled() bool {
 	return m != nil && m.DisableDefaultQueries != nil && *m.DisableDefaultQueries
 }
 
+// IsTLSEnabled checks whether the metrics endpoint should be served over TLS
+func (m *MonitoringConfiguration) IsTLSEnabled() bool {
+	return m != nil && m.TLSConfig != nil && m.TLSConfig.Enabled
+}
+
+// IsClientCertificateRequired checks whether the clients of the
+// metrics endpoint are required to present a certificate
+func (m *MonitoringConfiguration) IsClientCertificateRequired() bool {
+	return m.IsTLSEnabled() && m.TLSConfig.RequireClientCertificate
+}
+
 // ExternalCluster represents the connection parameters to an
 // external cluster which is used in the other sections of the configuration
 type ExternalCluster struct {
@@ -1341,6 +3729,20 @@ type ExternalCluster struct {
 
 	// The configuration for the barman-cloud tool suite
 	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
+
+	// The plugin used to restore the backups of this server
+	// +optional
+	PluginConfiguration *PluginConfiguration `json:"pluginConfiguration,omitempty"`
+}
+
+// PluginConfiguration specifies a plugin and the parameters passed to it
+type PluginConfiguration struct {
+	// The name of the plugin, matching the one published in its metadata
+	Name string `json:"name"`
+
+	// The parameters passed to the plugin
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
 }
 
 // GetServerName returns the server name, defaulting to the name of the external cluster or using the one specified
@@ -1426,16 +3828,72 @@ type ConfigMapResourceVersion struct {
 	// A map with the versions of all the config maps used to pass metrics.
 	// Map keys are the config map names, map values are the versions
 	Metrics map[string]string `json:"metrics,omitempty"`
+
+	// A map with the versions of all the config maps containing
+	// additional PostgreSQL configuration files.
+	// Map keys are the config map names, map values are the versions
+	ConfigurationFiles map[string]string `json:"configurationFiles,omitempty"`
 }
 
 // GetImageName get the name of the image that should be used
 // to create the pods
 func (cluster *Cluster) GetImageName() string {
+	// The image of a cluster using an image catalog is resolved
+	// by the operator and stored in the status
+	if cluster.Spec.ImageCatalogRef != nil && len(cluster.Status.Image) > 0 {
+		return cluster.Status.Image
+	}
+
 	if len(cluster.Spec.ImageName) > 0 {
 		return cluster.Spec.ImageName
 	}
 
-	return configuration.Current.PostgresImageName
+	return configuration.Current().PostgresImageName
+}
+
+// GetActiveChaosExperiment gets the chaos experiment running on an
+// instance at the passed time, if chaos testing is enabled. Invalid
+// experiments are ignored
+func (cluster *Cluster) GetActiveChaosExperiment(instance string, now time.Time) *utils.ChaosExperiment {
+	if !cluster.Spec.EnableChaosTesting {
+		return nil
+	}
+
+	experiment, err := utils.GetChaosExperiment(cluster.Annotations)
+	if err != nil || experiment == nil || experiment.Validate() != nil {
+		return nil
+	}
+
+	if experiment.Instance != instance || !experiment.IsActive(now) {
+		return nil
+	}
+
+	return experiment
+}
+
+// GetPriorityClassName gets the name of the priority class to be used
+// for the Pods of an instance, depending on it being the primary one
+func (cluster *Cluster) GetPriorityClassName(instanceName string) string {
+	if cluster.Spec.PrimaryPriorityClassName != "" && instanceName == cluster.Status.TargetPrimary {
+		return cluster.Spec.PrimaryPriorityClassName
+	}
+
+	return cluster.Spec.PriorityClassName
+}
+
+// GetImageArchitectures gets the architectures provided by the PostgreSQL
+// image, as detected by the operator. The result is empty when the
+// architecture affinity is disabled or the architectures of the
+// current image are not known
+func (cluster *Cluster) GetImageArchitectures() []string {
+	status := cluster.Status.ImageArchitectures
+	if !cluster.Spec.Affinity.EnableArchitectureAffinity ||
+		status == nil ||
+		status.Image != cluster.GetImageName() {
+		return nil
+	}
+
+	return status.Architectures
 }
 
 // GetPostgresqlVersion gets the PostgreSQL image version detecting it from the
@@ -1446,11 +3904,45 @@ func (cluster *Cluster) GetImageName() string {
 // ghcr.io/cloudnative-pg/postgresql:13.2 corresponds to version 130002
 // ghcr.io/cloudnative-pg/postgresql:9.6.3 corresponds to version 90603
 func (cluster *Cluster) GetPostgresqlVersion() (int, error) {
+	// Until the image is resolved from the catalog, we can only
+	// rely on the requested major version
+	if cluster.Spec.ImageCatalogRef != nil && len(cluster.Status.Image) == 0 {
+		return cluster.Spec.ImageCatalogRef.Major * 10000, nil
+	}
+
 	image := cluster.GetImageName()
 	tag := utils.GetImageTag(image)
 	return postgres.GetPostgresVersionFromTag(tag)
 }
 
+// GetPostgresFlavor gets the flavor of PostgreSQL used by the cluster,
+// detecting it from the image when not explicitly requested
+func (cluster *Cluster) GetPostgresFlavor() postgres.Flavor {
+	if cluster.Spec.PostgresConfiguration.Flavor != "" {
+		return cluster.Spec.PostgresConfiguration.Flavor
+	}
+
+	return postgres.GetFlavorFromImage(cluster.GetImageName())
+}
+
+// GetSuperuserName gets the name of the PostgreSQL superuser,
+// which depends on the flavor of PostgreSQL
+func (cluster *Cluster) GetSuperuserName() string {
+	return cluster.GetPostgresFlavor().GetInfo().SuperUser
+}
+
+// GetPlugin gets the configuration of a plugin used by
+// the cluster, returning false when it is not used
+func (cluster *Cluster) GetPlugin(name string) (*PluginConfiguration, bool) {
+	for idx := range cluster.Spec.Plugins {
+		if cluster.Spec.Plugins[idx].Name == name {
+			return &cluster.Spec.Plugins[idx], true
+		}
+	}
+
+	return nil, false
+}
+
 // GetImagePullSecret get the name of the pull secret to use
 // to download the PostgreSQL image
 func (cluster *Cluster) GetImagePullSecret() string {
@@ -1464,7 +3956,59 @@ func (cluster *Cluster) GetSuperuserSecretName() string {
 		return cluster.Spec.SuperuserSecret.Name
 	}
 
-	return fmt.Sprintf("%v%v", cluster.Name, SuperUserSecretSuffix)
+	return cluster.getGeneratedSecretName(SuperUserSecretSuffix)
+}
+
+// GetSecretNameTemplate gets the template of the names
+// of the secrets generated by the operator
+func (cluster *Cluster) GetSecretNameTemplate() string {
+	if cluster.Spec.GeneratedObjects == nil || cluster.Spec.GeneratedObjects.SecretNameTemplate == "" {
+		return DefaultSecretNameTemplate
+	}
+	return cluster.Spec.GeneratedObjects.SecretNameTemplate
+}
+
+// GetGeneratedSecretsMetadata gets the labels and annotations
+// of the secrets generated by the operator
+func (cluster *Cluster) GetGeneratedSecretsMetadata() *EmbeddedObjectMetadata {
+	if cluster.Spec.GeneratedObjects == nil {
+		return nil
+	}
+	return cluster.Spec.GeneratedObjects.Secrets
+}
+
+// GetGeneratedServicesMetadata gets the labels and annotations
+// of the services generated by the operator
+func (cluster *Cluster) GetGeneratedServicesMetadata() *EmbeddedObjectMetadata {
+	if cluster.Spec.GeneratedObjects == nil {
+		return nil
+	}
+	return cluster.Spec.GeneratedObjects.Services
+}
+
+// GetGeneratedPodsMetadata gets the labels and annotations
+// of the Pods generated by the operator
+func (cluster *Cluster) GetGeneratedPodsMetadata() *EmbeddedObjectMetadata {
+	if cluster.Spec.GeneratedObjects == nil {
+		return nil
+	}
+	return cluster.Spec.GeneratedObjects.Pods
+}
+
+// GetGeneratedPVCsMetadata gets the labels and annotations
+// of the PVCs generated by the operator
+func (cluster *Cluster) GetGeneratedPVCsMetadata() *EmbeddedObjectMetadata {
+	if cluster.Spec.GeneratedObjects == nil {
+		return nil
+	}
+	return cluster.Spec.GeneratedObjects.PersistentVolumeClaims
+}
+
+// getGeneratedSecretName gets the name of a secret generated by the
+// operator, given the suffix identifying its kind
+func (cluster *Cluster) getGeneratedSecretName(suffix string) string {
+	name := strings.ReplaceAll(cluster.GetSecretNameTemplate(), SecretNameClusterPlaceholder, cluster.Name)
+	return strings.ReplaceAll(name, SecretNameKindPlaceholder, strings.TrimPrefix(suffix, "-"))
 }
 
 // GetEnableLDAPAuth return true if bind or bind+search method are
@@ -1501,7 +4045,7 @@ func (cluster *Cluster) GetEnableSuperuserAccess() bool {
 func (cluster *Cluster) GetApplicationSecretName() string {
 	bootstrap := cluster.Spec.Bootstrap
 	if bootstrap == nil {
-		return fmt.Sprintf("%v%v", cluster.Name, ApplicationUserSecretSuffix)
+		return cluster.getGeneratedSecretName(ApplicationUserSecretSuffix)
 	}
 	recovery := bootstrap.Recovery
 	if recovery != nil && recovery.Secret != nil && recovery.Secret.Name != "" {
@@ -1518,7 +4062,18 @@ func (cluster *Cluster) GetApplicationSecretName() string {
 		return initDB.Secret.Name
 	}
 
-	return fmt.Sprintf("%v%v", cluster.Name, ApplicationUserSecretSuffix)
+	return cluster.getGeneratedSecretName(ApplicationUserSecretSuffix)
+}
+
+// GetServiceBinding gets the reference to the secret exposed
+// through the Provisioned Service duck type of the Service Binding
+// specification, or nil when the cluster has no application database
+func (cluster *Cluster) GetServiceBinding() *LocalObjectReference {
+	if !cluster.ShouldCreateApplicationDatabase() {
+		return nil
+	}
+
+	return &LocalObjectReference{Name: cluster.GetApplicationSecretName()}
 }
 
 // GetApplicationDatabaseName get the name of the application database for a specific bootstrap
@@ -1565,13 +4120,68 @@ func (cluster *Cluster) GetApplicationDatabaseOwner() string {
 	return ""
 }
 
+// GetRecoveryRemapping gets the remapping of the references to the
+// source cluster applied during the recovery, if any
+func (cluster *Cluster) GetRecoveryRemapping() *RecoveryRemapping {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.Recovery == nil {
+		return nil
+	}
+
+	return cluster.Spec.Bootstrap.Recovery.Remap
+}
+
+// ApplyToBackup replaces the references to the source cluster contained
+// in the status of the passed backup, i.e. the object store and the secrets
+// holding its credentials, with the ones of the remapping
+func (remapping *RecoveryRemapping) ApplyToBackup(backup *Backup) {
+	if remapping == nil || backup == nil {
+		return
+	}
+
+	status := &backup.Status
+	if remapping.DestinationPath != "" {
+		status.DestinationPath = remapping.DestinationPath
+	}
+	if remapping.ServerName != "" {
+		status.ServerName = remapping.ServerName
+	}
+
+	selectors := []*SecretKeySelector{status.EndpointCA}
+	if credentials := status.AWS; credentials != nil {
+		selectors = append(selectors,
+			credentials.AccessKeyIDReference,
+			credentials.SecretAccessKeyReference,
+			credentials.RegionReference,
+			credentials.SessionToken)
+	}
+	if credentials := status.Azure; credentials != nil {
+		selectors = append(selectors,
+			credentials.ConnectionString,
+			credentials.StorageAccount,
+			credentials.StorageKey,
+			credentials.StorageSasToken)
+	}
+	if credentials := status.Google; credentials != nil {
+		selectors = append(selectors, credentials.ApplicationCredentials)
+	}
+
+	for _, selector := range selectors {
+		if selector == nil {
+			continue
+		}
+		if name, ok := remapping.Secrets[selector.Name]; ok {
+			selector.Name = name
+		}
+	}
+}
+
 // GetServerCASecretName get the name of the secret containing the CA
 // of the cluster
 func (cluster *Cluster) GetServerCASecretName() string {
 	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ServerCASecret != "" {
 		return cluster.Spec.Certificates.ServerCASecret
 	}
-	return fmt.Sprintf("%v%v", cluster.Name, DefaultServerCaSecretSuffix)
+	return cluster.getGeneratedSecretName(DefaultServerCaSecretSuffix)
 }
 
 // GetServerTLSSecretName get the name of the secret containing the
@@ -1580,7 +4190,7 @@ func (cluster *Cluster) GetServerTLSSecretName() string {
 	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ServerTLSSecret != "" {
 		return cluster.Spec.Certificates.ServerTLSSecret
 	}
-	return fmt.Sprintf("%v%v", cluster.Name, ServerSecretSuffix)
+	return cluster.getGeneratedSecretName(ServerSecretSuffix)
 }
 
 // GetClientCASecretName get the name of the secret containing the CA
@@ -1589,7 +4199,7 @@ func (cluster *Cluster) GetClientCASecretName() string {
 	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ClientCASecret != "" {
 		return cluster.Spec.Certificates.ClientCASecret
 	}
-	return fmt.Sprintf("%v%v", cluster.Name, ClientCaSecretSuffix)
+	return cluster.getGeneratedSecretName(ClientCaSecretSuffix)
 }
 
 // GetFixedInheritedAnnotations gets the annotations that should be
@@ -1615,7 +4225,20 @@ func (cluster *Cluster) GetReplicationSecretName() string {
 	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ReplicationTLSSecret != "" {
 		return cluster.Spec.Certificates.ReplicationTLSSecret
 	}
-	return fmt.Sprintf("%v%v", cluster.Name, ReplicationSecretSuffix)
+	return cluster.getGeneratedSecretName(ReplicationSecretSuffix)
+}
+
+// GetPrimaryHeartbeatLeaseName gets the name of the lease renewed by
+// the instance manager of the primary as a heartbeat
+func (cluster *Cluster) GetPrimaryHeartbeatLeaseName() string {
+	return fmt.Sprintf("%v%v", cluster.Name, PrimaryHeartbeatLeaseSuffix)
+}
+
+// GetPrimaryHeartbeatTimeout gets the time after which a primary which
+// has not renewed its heartbeat lease is considered failed, zero if the
+// heartbeat is disabled
+func (cluster *Cluster) GetPrimaryHeartbeatTimeout() time.Duration {
+	return time.Duration(cluster.Spec.PrimaryHeartbeatTimeout) * time.Second
 }
 
 // GetServiceAnyName return the name of the service that is used as DNS
@@ -1642,6 +4265,12 @@ func (cluster *Cluster) GetServiceReadWriteName() string {
 	return fmt.Sprintf("%v%v", cluster.Name, ServiceReadWriteSuffix)
 }
 
+// GetServiceReadWriteExternalName return the name of the LoadBalancer
+// service pointing to the primary, published by external-dns
+func (cluster *Cluster) GetServiceReadWriteExternalName() string {
+	return fmt.Sprintf("%v%v", cluster.Name, ServiceReadWriteExternalSuffix)
+}
+
 // GetMaxStartDelay get the amount of time of startDelay config option
 func (cluster *Cluster) GetMaxStartDelay() int32 {
 	if cluster.Spec.MaxStartDelay > 0 {
@@ -1650,6 +4279,51 @@ func (cluster *Cluster) GetMaxStartDelay() int32 {
 	return 30
 }
 
+// IsRequiredPodAntiAffinityDefaulted checks if the pod anti-affinity
+// type of a new cluster defaults to `required`
+func (cluster *Cluster) IsRequiredPodAntiAffinityDefaulted() bool {
+	return cluster.Spec.GeneratedDefaults == nil || !cluster.Spec.GeneratedDefaults.DisableRequiredPodAntiAffinity
+}
+
+// AreResourcesDefaulted checks if the resources of a new cluster
+// are set to the defaults of the operator
+func (cluster *Cluster) AreResourcesDefaulted() bool {
+	return cluster.Spec.GeneratedDefaults == nil || !cluster.Spec.GeneratedDefaults.DisableResources
+}
+
+// AreProbesDefaulted checks if the timings of the probes of a new
+// cluster are written in its specification
+func (cluster *Cluster) AreProbesDefaulted() bool {
+	return cluster.Spec.GeneratedDefaults == nil || !cluster.Spec.GeneratedDefaults.DisableProbes
+}
+
+// GetReadinessProbeType gets the criteria used by the readiness probe
+func (cluster *Cluster) GetReadinessProbeType() ReadinessProbeType {
+	if cluster.Spec.Probes == nil || cluster.Spec.Probes.Readiness == nil ||
+		cluster.Spec.Probes.Readiness.Type == "" {
+		return ReadinessProbeTypeQuery
+	}
+	return cluster.Spec.Probes.Readiness.Type
+}
+
+// GetDiskSpaceWarningThreshold gets the percentage of used space of a
+// volume above which a warning event is emitted
+func (cluster *Cluster) GetDiskSpaceWarningThreshold() int32 {
+	if cluster.Spec.DiskSpace != nil && cluster.Spec.DiskSpace.WarningThreshold > 0 {
+		return cluster.Spec.DiskSpace.WarningThreshold
+	}
+	return DefaultDiskSpaceWarningThreshold
+}
+
+// GetDiskSpaceCriticalThreshold gets the percentage of used space of a
+// volume above which a critical event is emitted
+func (cluster *Cluster) GetDiskSpaceCriticalThreshold() int32 {
+	if cluster.Spec.DiskSpace != nil && cluster.Spec.DiskSpace.CriticalThreshold > 0 {
+		return cluster.Spec.DiskSpace.CriticalThreshold
+	}
+	return DefaultDiskSpaceCriticalThreshold
+}
+
 // GetMaxStopDelay get the amount of time PostgreSQL has to stop
 func (cluster *Cluster) GetMaxStopDelay() int32 {
 	if cluster.Spec.MaxStopDelay > 0 {
@@ -1677,6 +4351,28 @@ func (cluster *Cluster) GetPrimaryUpdateStrategy() PrimaryUpdateStrategy {
 	return strategy
 }
 
+// GetReplicaRestartMethod get the method to restart the replicas
+// after a configuration change, defaulting to recreate
+func (cluster *Cluster) GetReplicaRestartMethod() ReplicaRestartMethod {
+	method := cluster.Spec.ReplicaRestartMethod
+	if method == "" {
+		return ReplicaRestartMethodRecreate
+	}
+
+	return method
+}
+
+// GetFailoverPolicy get the cluster failover policy,
+// defaulting to automatic
+func (cluster *Cluster) GetFailoverPolicy() FailoverPolicy {
+	policy := cluster.Spec.FailoverPolicy
+	if policy == "" {
+		return FailoverPolicyAutomatic
+	}
+
+	return policy
+}
+
 // GetPrimaryUpdateMethod get the cluster primary update method,
 // defaulting to switchover
 func (cluster *Cluster) GetPrimaryUpdateMethod() PrimaryUpdateMethod {
@@ -1703,6 +4399,24 @@ func (cluster *Cluster) GetPgCtlTimeoutForPromotion() int32 {
 	return timeout
 }
 
+// GetMaximumReplayLag gets the replay lag, in bytes, above which an
+// instance is considered degraded, or zero when the lag is not considered
+func (cluster *Cluster) GetMaximumReplayLag() int64 {
+	if cluster.Spec.InstanceHealth == nil || cluster.Spec.InstanceHealth.MaximumLag == nil {
+		return 0
+	}
+	return cluster.Spec.InstanceHealth.MaximumLag.Value()
+}
+
+// GetPromotionTimeoutAction returns the action to be taken when the
+// promotion of an instance exceeds the promotion timeout
+func (cluster *Cluster) GetPromotionTimeoutAction() PromotionTimeoutAction {
+	if cluster.Spec.PostgresConfiguration.PromotionTimeoutAction == "" {
+		return PromotionTimeoutActionWait
+	}
+	return cluster.Spec.PostgresConfiguration.PromotionTimeoutAction
+}
+
 // IsReusePVCEnabled check if in a maintenance window we should reuse PVCs
 func (cluster *Cluster) IsReusePVCEnabled() bool {
 	reusePVC := true
@@ -1712,6 +4426,16 @@ func (cluster *Cluster) IsReusePVCEnabled() bool {
 	return reusePVC
 }
 
+// IsSwitchoverInProgress checks if the primary is being changed while the
+// current one is healthy, rather than because of a failover. The switchover
+// target is reset whenever a new target primary is chosen for another
+// reason, and must match the current one
+func (cluster *Cluster) IsSwitchoverInProgress() bool {
+	return cluster.Status.SwitchoverTarget != "" &&
+		cluster.Status.SwitchoverTarget == cluster.Status.TargetPrimary &&
+		cluster.Status.TargetPrimary != cluster.Status.CurrentPrimary
+}
+
 // IsInstanceFenced check if in a given instance should be fenced
 func (cluster *Cluster) IsInstanceFenced(instance string) bool {
 	fencedInstances, err := utils.GetFencedInstances(cluster.Annotations)
@@ -1779,20 +4503,43 @@ func (cluster *Cluster) ShouldCreateApplicationDatabase() bool {
 // during the bootstrap phase using initDB, we need to run post application
 // SQL files from provided references.
 func (cluster *Cluster) ShouldInitDBRunPostInitApplicationSQLRefs() bool {
-	if cluster.Spec.Bootstrap == nil {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.InitDB == nil {
 		return false
 	}
 
-	if cluster.Spec.Bootstrap.InitDB == nil {
+	return cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs.HasReferences()
+}
+
+// ShouldInitDBRunPostInitSQLRefs returns true if for this cluster,
+// during the bootstrap phase using initDB, we need to run post init
+// SQL files from provided references against the `postgres` database.
+func (cluster *Cluster) ShouldInitDBRunPostInitSQLRefs() bool {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.InitDB == nil {
+		return false
+	}
+
+	return cluster.Spec.Bootstrap.InitDB.PostInitSQLRefs.HasReferences()
+}
+
+// ShouldInitDBRunPostInitTemplateSQLRefs returns true if for this cluster,
+// during the bootstrap phase using initDB, we need to run post init
+// SQL files from provided references against the `template1` database.
+func (cluster *Cluster) ShouldInitDBRunPostInitTemplateSQLRefs() bool {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.InitDB == nil {
 		return false
 	}
 
-	if cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs == nil {
+	return cluster.Spec.Bootstrap.InitDB.PostInitTemplateSQLRefs.HasReferences()
+}
+
+// HasReferences returns true if at least a Secret or a ConfigMap
+// is referenced
+func (refs *SQLRefs) HasReferences() bool {
+	if refs == nil {
 		return false
 	}
 
-	return (len(cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs.ConfigMapRefs) != 0 ||
-		len(cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs.SecretRefs) != 0)
+	return len(refs.ConfigMapRefs) != 0 || len(refs.SecretRefs) != 0
 }
 
 // ShouldInitDBCreateApplicationDatabase returns true if the application database needs to be created during initdb
@@ -1854,11 +4601,125 @@ func (cluster *Cluster) ShouldCreateWalArchiveVolume() bool {
 	return cluster.Spec.WalStorage != nil
 }
 
+// GetPVCRetentionPolicyWhenDeleted gets the retention policy applied
+// to the PVCs of the instances when the cluster is deleted
+func (cluster *Cluster) GetPVCRetentionPolicyWhenDeleted() PVCRetentionPolicyType {
+	policy := cluster.Spec.StorageConfiguration.PersistentVolumeClaimRetentionPolicy
+	if policy == nil || policy.WhenDeleted == "" {
+		return PVCRetentionPolicyDelete
+	}
+
+	return policy.WhenDeleted
+}
+
+// GetPVCRetentionPolicyWhenScaled gets the retention policy applied
+// to the PVCs of the instances removed by a scale-down
+func (cluster *Cluster) GetPVCRetentionPolicyWhenScaled() PVCRetentionPolicyType {
+	policy := cluster.Spec.StorageConfiguration.PersistentVolumeClaimRetentionPolicy
+	if policy == nil || policy.WhenScaled == "" {
+		return PVCRetentionPolicyDelete
+	}
+
+	return policy.WhenScaled
+}
+
 // GetWalArchiveVolumeSuffix gets the wal archive volume name suffix
 func (cluster *Cluster) GetWalArchiveVolumeSuffix() string {
 	return "-wal"
 }
 
+// GetPostgresParameters gets the PostgreSQL parameters of the cluster,
+// including the ones managed by the operator. When the memory tuning is
+// enabled, the memory related parameters not specified by the user are
+// derived from the memory limit of the Pods
+func (cluster *Cluster) GetPostgresParameters() map[string]string {
+	parameters := cluster.Spec.PostgresConfiguration.GetParameters()
+
+	defaults := make(map[string]string)
+	memoryLimit := cluster.Spec.Resources.Limits.Memory()
+	if cluster.Spec.PostgresConfiguration.MemoryTuning && !memoryLimit.IsZero() {
+		for key, value := range GetMemoryTuningParameters(memoryLimit.Value()) {
+			defaults[key] = value
+		}
+	}
+	if len(defaults) == 0 {
+		return parameters
+	}
+	for key, value := range parameters {
+		defaults[key] = value
+	}
+
+	return defaults
+}
+
+// GetSectionManagedParameters gets the names of the PostgreSQL parameters
+// managed by the sections of the PostgreSQL configuration in use: the
+// connections and the locale sections, the huge pages, whose size depends
+// on `shared_buffers`, and the memory tuning. These parameters can only be
+// set in the `parameters` section, and are ignored in the configuration
+// files
+func (cluster *Cluster) GetSectionManagedParameters() []string {
+	parameters := make(map[string]bool)
+	addParameters := func(keys ...string) {
+		for _, key := range keys {
+			parameters[key] = true
+		}
+	}
+
+	configuration := cluster.Spec.PostgresConfiguration
+	if configuration.Connections != nil {
+		addParameters(configuration.Connections.GetManagedParameters()...)
+	}
+	if configuration.Locale != nil {
+		addParameters(configuration.Locale.GetManagedParameters()...)
+	}
+	if len(cluster.GetHugePagesLimits()) > 0 {
+		addParameters("huge_pages", "shared_buffers")
+	}
+	if configuration.MemoryTuning && !cluster.Spec.Resources.Limits.Memory().IsZero() {
+		for key := range GetMemoryTuningParameters(0) {
+			addParameters(key)
+		}
+	}
+
+	result := make([]string, 0, len(parameters))
+	for key := range parameters {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// GetHugePagesLimits gets the huge pages limits of the Pods,
+// indexed by the resource name (e.g. `hugepages-2Mi`)
+func (cluster *Cluster) GetHugePagesLimits() corev1.ResourceList {
+	var result corev1.ResourceList
+	for name, quantity := range cluster.Spec.Resources.Limits {
+		if !strings.HasPrefix(string(name), corev1.ResourceHugePagesPrefix) || quantity.IsZero() {
+			continue
+		}
+
+		if result == nil {
+			result = make(corev1.ResourceList)
+		}
+		result[name] = quantity
+	}
+
+	return result
+}
+
+// GetSeccompProfile returns the seccomp profile applied to the Pods
+// and the containers of the cluster, defaulting to `RuntimeDefault`
+func (cluster Cluster) GetSeccompProfile() *corev1.SeccompProfile {
+	if cluster.Spec.SeccompProfile != nil {
+		return cluster.Spec.SeccompProfile
+	}
+
+	return &corev1.SeccompProfile{
+		Type: corev1.SeccompProfileTypeRuntimeDefault,
+	}
+}
+
 // GetPostgresUID returns the UID that is being used for the "postgres"
 // user
 func (cluster Cluster) GetPostgresUID() int64 {
@@ -1971,6 +4832,18 @@ func (cluster *Cluster) UsesConfigMap(config string) (ok bool) {
 	if _, ok := cluster.Status.ConfigMapResourceVersion.Metrics[config]; ok {
 		return true
 	}
+	if _, ok := cluster.Status.ConfigMapResourceVersion.ConfigurationFiles[config]; ok {
+		return true
+	}
+	return false
+}
+
+// IsPrometheusRuleEnabled checks if the PrometheusRule object needs to be created
+func (cluster *Cluster) IsPrometheusRuleEnabled() bool {
+	if cluster.Spec.Monitoring != nil {
+		return cluster.Spec.Monitoring.EnablePrometheusRule
+	}
+
 	return false
 }
 
@@ -2056,6 +4929,12 @@ func (backupConfiguration *BackupConfiguration) IsBarmanBackupConfigured() bool
 		backupConfiguration.BarmanObjectStore.BarmanCredentials.ArePopulated()
 }
 
+// AreHooksAllowed checks if the Backup and ScheduledBackup
+// resources of the cluster are allowed to run hooks
+func (backupConfiguration *BackupConfiguration) AreHooksAllowed() bool {
+	return backupConfiguration != nil && backupConfiguration.AllowHooks
+}
+
 // IsBarmanEndpointCASet returns true if we have a CA bundle for the endpoint
 // false otherwise
 func (backupConfiguration *BackupConfiguration) IsBarmanEndpointCASet() bool {
@@ -2119,3 +4998,23 @@ func (target *RecoveryTarget) BuildPostgresOptions() string {
 func init() {
 	SchemeBuilder.Register(&Cluster{}, &ClusterList{})
 }
+
+// IsDriftAutoRepairEnabled checks if the operator needs to restore the
+// expected definition of the generated resources
+func (cluster *Cluster) IsDriftAutoRepairEnabled() bool {
+	if cluster.Spec.DriftDetection == nil || cluster.Spec.DriftDetection.AutoRepair == nil {
+		return true
+	}
+
+	return *cluster.Spec.DriftDetection.AutoRepair
+}
+
+// GetDriftDetectionInterval gets the interval between two
+// periodic reconciliations of the cluster
+func (cluster *Cluster) GetDriftDetectionInterval() time.Duration {
+	if cluster.Spec.DriftDetection == nil || cluster.Spec.DriftDetection.Interval == nil {
+		return defaultDriftDetectionInterval
+	}
+
+	return cluster.Spec.DriftDetection.Interval.Duration
+}
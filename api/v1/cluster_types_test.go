@@ -17,8 +17,13 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -99,6 +104,54 @@ var _ = Describe("Primary update strategy", func() {
 	})
 })
 
+var _ = Describe("Primary update method", func() {
+	It("defaults to switchover", func() {
+		emptyCluster := Cluster{}
+		Expect(emptyCluster.GetPrimaryUpdateMethod()).To(BeEquivalentTo(PrimaryUpdateMethodSwitchover))
+	})
+
+	It("respect the preference of the user", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PrimaryUpdateMethod: PrimaryUpdateMethodRestart,
+			},
+		}
+		Expect(cluster.GetPrimaryUpdateMethod()).To(BeEquivalentTo(PrimaryUpdateMethodRestart))
+	})
+})
+
+var _ = Describe("Replica restart method", func() {
+	It("defaults to recreate", func() {
+		emptyCluster := Cluster{}
+		Expect(emptyCluster.GetReplicaRestartMethod()).To(BeEquivalentTo(ReplicaRestartMethodRecreate))
+	})
+
+	It("respect the preference of the user", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ReplicaRestartMethod: ReplicaRestartMethodRestart,
+			},
+		}
+		Expect(cluster.GetReplicaRestartMethod()).To(BeEquivalentTo(ReplicaRestartMethodRestart))
+	})
+})
+
+var _ = Describe("Failover policy", func() {
+	It("defaults to automatic", func() {
+		emptyCluster := Cluster{}
+		Expect(emptyCluster.GetFailoverPolicy()).To(BeEquivalentTo(FailoverPolicyAutomatic))
+	})
+
+	It("respect the preference of the user", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				FailoverPolicy: FailoverPolicyManual,
+			},
+		}
+		Expect(cluster.GetFailoverPolicy()).To(BeEquivalentTo(FailoverPolicyManual))
+	})
+})
+
 var _ = Describe("Node maintenance window", func() {
 	It("default maintenance not in progress", func() {
 		cluster := Cluster{}
@@ -171,7 +224,7 @@ var _ = Describe("Bootstrap via initdb", func() {
 						Secret: &LocalObjectReference{
 							Name: "appSecret",
 						},
-						PostInitApplicationSQLRefs: &PostInitApplicationSQLRefs{
+						PostInitApplicationSQLRefs: &SQLRefs{
 							SecretRefs: []SecretKeySelector{
 								{
 									Key: "secretKey",
@@ -202,7 +255,7 @@ var _ = Describe("Bootstrap via initdb", func() {
 						Secret: &LocalObjectReference{
 							Name: "appSecret",
 						},
-						PostInitApplicationSQLRefs: &PostInitApplicationSQLRefs{
+						PostInitApplicationSQLRefs: &SQLRefs{
 							ConfigMapRefs: []ConfigMapKeySelector{
 								{
 									Key: "configMapKey",
@@ -624,6 +677,21 @@ var _ = Describe("A config map resource version", func() {
 		found := cluster.UsesConfigMap("a-configmap")
 		Expect(found).To(BeTrue())
 	})
+
+	It("contains the configuration files configmap we are looking for", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "clustername",
+			},
+			Status: ClusterStatus{
+				ConfigMapResourceVersion: ConfigMapResourceVersion{
+					ConfigurationFiles: map[string]string{"a-configmap": "test-version"},
+				},
+			},
+		}
+		Expect(cluster.UsesConfigMap("a-configmap")).To(BeTrue())
+		Expect(cluster.UsesConfigMap("another-configmap")).To(BeFalse())
+	})
 })
 
 var _ = Describe("PostgreSQL version detection", func() {
@@ -795,3 +863,800 @@ var _ = Describe("Barman credentials", func() {
 		}.ArePopulated()).To(BeTrue())
 	})
 })
+
+var _ = Describe("Managed extensions", func() {
+	It("has no extension enabled by default", func() {
+		Expect(PostgresConfiguration{}.GetEnabledExtensions()).To(BeEmpty())
+	})
+
+	It("returns the explicitly enabled extensions", func() {
+		configuration := PostgresConfiguration{
+			EnablePgStatStatements: true,
+			EnableAutoExplain:      true,
+		}
+		Expect(configuration.GetEnabledExtensions()).To(ConsistOf("pg_stat_statements", "auto_explain"))
+	})
+})
+
+var _ = Describe("pgaudit configuration", func() {
+	It("generates the pgaudit parameters", func() {
+		logCatalog := false
+		configuration := PgAuditConfiguration{
+			Log:          []PgAuditLogClass{"all", "-misc"},
+			LogCatalog:   &logCatalog,
+			LogParameter: true,
+			Role:         "auditor",
+		}
+		Expect(configuration.GetParameters()).To(Equal(map[string]string{
+			"pgaudit.log":                "all, -misc",
+			"pgaudit.log_catalog":        "off",
+			"pgaudit.log_parameter":      "on",
+			"pgaudit.log_relation":       "off",
+			"pgaudit.log_statement_once": "off",
+			"pgaudit.role":               "auditor",
+		}))
+	})
+
+	It("merges the pgaudit parameters with the user ones", func() {
+		configuration := PostgresConfiguration{
+			Parameters: map[string]string{"work_mem": "8MB"},
+			PgAudit:    &PgAuditConfiguration{Log: []PgAuditLogClass{"ddl"}},
+		}
+		parameters := configuration.GetParameters()
+		Expect(parameters).To(HaveKeyWithValue("work_mem", "8MB"))
+		Expect(parameters).To(HaveKeyWithValue("pgaudit.log", "ddl"))
+		Expect(configuration.Parameters).ToNot(HaveKey("pgaudit.log"))
+		Expect(configuration.GetEnabledExtensions()).To(ConsistOf("pgaudit"))
+	})
+
+	It("returns the user parameters when pgaudit is not configured", func() {
+		configuration := PostgresConfiguration{
+			Parameters: map[string]string{"work_mem": "8MB"},
+		}
+		Expect(configuration.GetParameters()).To(Equal(configuration.Parameters))
+	})
+})
+
+var _ = Describe("PostgreSQL logging configuration", func() {
+	It("generates the parameters of the preset", func() {
+		configuration := PostgresLoggingConfiguration{Preset: PostgresLoggingPresetTerse}
+		Expect(configuration.GetParameters()).To(Equal(map[string]string{
+			"log_min_messages":           "warning",
+			"log_min_error_statement":    "error",
+			"log_min_duration_statement": "-1",
+			"log_statement":              "none",
+		}))
+	})
+
+	It("keeps the PostgreSQL defaults with the default preset", func() {
+		configuration := PostgresLoggingConfiguration{Preset: PostgresLoggingPresetDefault}
+		Expect(configuration.GetParameters()).To(BeEmpty())
+	})
+
+	It("overrides the preset with the explicit settings", func() {
+		minDuration := int32(250)
+		configuration := PostgresLoggingConfiguration{
+			Preset:               PostgresLoggingPresetVerbose,
+			MinDurationStatement: &minDuration,
+			Statement:            "mod",
+		}
+		parameters := configuration.GetParameters()
+		Expect(parameters).To(HaveKeyWithValue("log_min_duration_statement", "250"))
+		Expect(parameters).To(HaveKeyWithValue("log_statement", "mod"))
+		Expect(parameters).To(HaveKeyWithValue("log_connections", "on"))
+	})
+
+	It("lists every parameter which can be set", func() {
+		managed := PostgresLoggingConfiguration{}.GetManagedParameters()
+		Expect(managed).To(ContainElements("log_min_duration_statement", "log_statement", "log_min_messages"))
+		for _, parameters := range loggingPresetParameters {
+			for key := range parameters {
+				Expect(managed).To(ContainElement(key))
+			}
+		}
+	})
+
+	It("merges the logging parameters with the user ones", func() {
+		configuration := PostgresConfiguration{
+			Parameters: map[string]string{"work_mem": "8MB"},
+			Logging:    &PostgresLoggingConfiguration{Preset: PostgresLoggingPresetDebug},
+		}
+		parameters := configuration.GetParameters()
+		Expect(parameters).To(HaveKeyWithValue("work_mem", "8MB"))
+		Expect(parameters).To(HaveKeyWithValue("log_statement", "all"))
+		Expect(configuration.Parameters).ToNot(HaveKey("log_statement"))
+	})
+})
+
+var _ = Describe("disk space thresholds", func() {
+	It("uses the defaults when not specified", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetDiskSpaceWarningThreshold()).To(BeEquivalentTo(DefaultDiskSpaceWarningThreshold))
+		Expect(cluster.GetDiskSpaceCriticalThreshold()).To(BeEquivalentTo(DefaultDiskSpaceCriticalThreshold))
+	})
+
+	It("uses the thresholds of the cluster", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				DiskSpace: &DiskSpaceConfiguration{WarningThreshold: 70, CriticalThreshold: 90},
+			},
+		}
+		Expect(cluster.GetDiskSpaceWarningThreshold()).To(BeEquivalentTo(70))
+		Expect(cluster.GetDiskSpaceCriticalThreshold()).To(BeEquivalentTo(90))
+	})
+})
+
+var _ = Describe("image update policy", func() {
+	// 2023-01-07 is a Saturday
+	saturdayNight := time.Date(2023, 1, 7, 23, 30, 0, 0, time.UTC)
+
+	It("allows the update when there is no policy", func() {
+		cluster := Cluster{}
+		Expect(cluster.IsImageUpdateAllowed(saturdayNight)).To(BeTrue())
+	})
+
+	It("doesn't allow the update when it is paused", func() {
+		cluster := Cluster{Spec: ClusterSpec{ImageUpdate: &ImageUpdateConfiguration{Paused: true}}}
+		Expect(cluster.IsImageUpdateAllowed(saturdayNight)).To(BeFalse())
+	})
+
+	It("allows the update only inside the maintenance windows", func() {
+		cluster := Cluster{Spec: ClusterSpec{ImageUpdate: &ImageUpdateConfiguration{
+			MaintenanceWindows: []MaintenanceWindow{
+				{
+					Days:      []MaintenanceWindowDay{"Saturday"},
+					StartTime: "23:00",
+					Duration:  v1.Duration{Duration: 2 * time.Hour},
+				},
+			},
+		}}}
+		Expect(cluster.IsImageUpdateAllowed(saturdayNight)).To(BeTrue())
+		Expect(cluster.IsImageUpdateAllowed(saturdayNight.Add(time.Hour))).To(BeTrue())
+		Expect(cluster.IsImageUpdateAllowed(saturdayNight.Add(90 * time.Minute))).To(BeFalse())
+		Expect(cluster.IsImageUpdateAllowed(saturdayNight.Add(-time.Hour))).To(BeFalse())
+		Expect(cluster.IsImageUpdateAllowed(saturdayNight.AddDate(0, 0, -1))).To(BeFalse())
+	})
+
+	It("allows the update of the primary only inside the primary update windows", func() {
+		cluster := Cluster{}
+		Expect(cluster.IsPrimaryUpdateAllowed(saturdayNight)).To(BeTrue())
+
+		cluster.Spec.PrimaryUpdateWindows = []MaintenanceWindow{
+			{
+				Days:      []MaintenanceWindowDay{"Sunday"},
+				StartTime: "00:00",
+				Duration:  v1.Duration{Duration: 4 * time.Hour},
+			},
+		}
+		Expect(cluster.IsPrimaryUpdateAllowed(saturdayNight)).To(BeFalse())
+		Expect(cluster.IsPrimaryUpdateAllowed(saturdayNight.Add(time.Hour))).To(BeTrue())
+		Expect(cluster.IsPrimaryUpdateAllowed(saturdayNight.Add(5 * time.Hour))).To(BeFalse())
+	})
+
+	It("uses every day when the days are not specified", func() {
+		window := MaintenanceWindow{StartTime: "02:00", Duration: v1.Duration{Duration: time.Hour}}
+		for day := 1; day <= 7; day++ {
+			Expect(window.Contains(time.Date(2023, 1, day, 2, 30, 0, 0, time.UTC))).To(BeTrue())
+			Expect(window.Contains(time.Date(2023, 1, day, 3, 30, 0, 0, time.UTC))).To(BeFalse())
+		}
+	})
+
+	It("converts the time to UTC", func() {
+		window := MaintenanceWindow{StartTime: "02:00", Duration: v1.Duration{Duration: time.Hour}}
+		location := time.FixedZone("UTC+2", 2*60*60)
+		Expect(window.Contains(time.Date(2023, 1, 1, 4, 30, 0, 0, location))).To(BeTrue())
+	})
+
+	It("gets the next start of the maintenance windows", func() {
+		cluster := Cluster{Spec: ClusterSpec{ImageUpdate: &ImageUpdateConfiguration{
+			MaintenanceWindows: []MaintenanceWindow{
+				{
+					Days:      []MaintenanceWindowDay{"Saturday"},
+					StartTime: "23:00",
+					Duration:  v1.Duration{Duration: 2 * time.Hour},
+				},
+				{
+					Days:      []MaintenanceWindowDay{"Wednesday"},
+					StartTime: "02:00",
+					Duration:  v1.Duration{Duration: 2 * time.Hour},
+				},
+			},
+		}}}
+
+		next, ok := cluster.GetNextImageUpdateWindowStart(saturdayNight)
+		Expect(ok).To(BeTrue())
+		Expect(next).To(Equal(time.Date(2023, 1, 11, 2, 0, 0, 0, time.UTC)))
+
+		next, ok = cluster.GetNextImageUpdateWindowStart(saturdayNight.AddDate(0, 0, -1))
+		Expect(ok).To(BeTrue())
+		Expect(next).To(Equal(time.Date(2023, 1, 7, 23, 0, 0, 0, time.UTC)))
+	})
+
+	It("has no next start when the image update is paused or not restricted", func() {
+		cluster := Cluster{}
+		_, ok := cluster.GetNextImageUpdateWindowStart(saturdayNight)
+		Expect(ok).To(BeFalse())
+
+		cluster.Spec.ImageUpdate = &ImageUpdateConfiguration{
+			Paused: true,
+			MaintenanceWindows: []MaintenanceWindow{
+				{StartTime: "02:00", Duration: v1.Duration{Duration: time.Hour}},
+			},
+		}
+		_, ok = cluster.GetNextImageUpdateWindowStart(saturdayNight)
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("seccomp profile", func() {
+	It("defaults to RuntimeDefault", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetSeccompProfile().Type).To(Equal(corev1.SeccompProfileTypeRuntimeDefault))
+	})
+
+	It("uses the profile requested by the user", func() {
+		localhostProfile := "profiles/postgres.json"
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				SeccompProfile: &corev1.SeccompProfile{
+					Type:             corev1.SeccompProfileTypeLocalhost,
+					LocalhostProfile: &localhostProfile,
+				},
+			},
+		}
+		Expect(cluster.GetSeccompProfile()).To(Equal(cluster.Spec.SeccompProfile))
+	})
+})
+
+var _ = Describe("memory tuning", func() {
+	It("derives the memory related parameters from the available memory", func() {
+		Expect(GetMemoryTuningParameters(4 * 1024 * 1024 * 1024)).To(Equal(map[string]string{
+			"shared_buffers":       "1024MB",
+			"effective_cache_size": "3072MB",
+			"maintenance_work_mem": "256MB",
+			"max_connections":      "256",
+		}))
+	})
+
+	It("limits maintenance_work_mem and max_connections", func() {
+		parameters := GetMemoryTuningParameters(64 * 1024 * 1024 * 1024)
+		Expect(parameters["maintenance_work_mem"]).To(Equal("2048MB"))
+		Expect(parameters["max_connections"]).To(Equal("500"))
+
+		parameters = GetMemoryTuningParameters(128 * 1024 * 1024)
+		Expect(parameters["max_connections"]).To(Equal("20"))
+	})
+
+	It("applies the tuning only when enabled", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("4Gi"),
+					},
+				},
+			},
+		}
+		Expect(cluster.GetPostgresParameters()).ToNot(HaveKey("shared_buffers"))
+
+		cluster.Spec.PostgresConfiguration.MemoryTuning = true
+		Expect(cluster.GetPostgresParameters()).To(HaveKeyWithValue("shared_buffers", "1024MB"))
+	})
+
+	It("gives precedence to the parameters specified by the user", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("4Gi"),
+					},
+				},
+				PostgresConfiguration: PostgresConfiguration{
+					MemoryTuning: true,
+					Parameters: map[string]string{
+						"max_connections": "1000",
+					},
+				},
+			},
+		}
+		parameters := cluster.GetPostgresParameters()
+		Expect(parameters).To(HaveKeyWithValue("max_connections", "1000"))
+		Expect(parameters).To(HaveKeyWithValue("effective_cache_size", "3072MB"))
+	})
+})
+
+var _ = Describe("huge pages", func() {
+	It("gets the huge pages requested for the Pods", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						"hugepages-2Mi": resource.MustParse("512Mi"),
+						"hugepages-1Gi": resource.MustParse("0"),
+					},
+				},
+			},
+		}
+		Expect(cluster.GetHugePagesLimits()).To(HaveLen(1))
+		Expect(cluster.GetPostgresParameters()).ToNot(HaveKey("huge_pages"))
+	})
+
+	It("doesn't set huge_pages without huge pages", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetHugePagesLimits()).To(BeEmpty())
+		Expect(cluster.GetPostgresParameters()).ToNot(HaveKey("huge_pages"))
+	})
+})
+
+var _ = Describe("parameters managed by the configuration sections", func() {
+	It("doesn't manage any parameter when no section is in use", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetSectionManagedParameters()).To(BeEmpty())
+	})
+
+	It("manages the parameters of every section in use", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Connections:  &ConnectionsConfiguration{},
+					Locale:       &LocaleConfiguration{},
+					MemoryTuning: true,
+				},
+				Resources: corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("4Gi"),
+						"hugepages-2Mi":       resource.MustParse("512Mi"),
+					},
+				},
+			},
+		}
+		Expect(cluster.GetSectionManagedParameters()).To(Equal([]string{
+			"datestyle",
+			"effective_cache_size",
+			"huge_pages",
+			"lc_messages",
+			"lc_monetary",
+			"lc_numeric",
+			"lc_time",
+			"log_timezone",
+			"maintenance_work_mem",
+			"max_connections",
+			"shared_buffers",
+			"superuser_reserved_connections",
+			"timezone",
+		}))
+	})
+})
+
+var _ = Describe("service binding", func() {
+	It("points to the application secret", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{Database: "app", Owner: "app"},
+				},
+			},
+		}
+		Expect(cluster.GetServiceBinding()).To(Equal(&LocalObjectReference{Name: "cluster-example-app"}))
+
+		cluster.Spec.Bootstrap.InitDB.Secret = &LocalObjectReference{Name: "custom-secret"}
+		Expect(cluster.GetServiceBinding()).To(Equal(&LocalObjectReference{Name: "custom-secret"}))
+	})
+
+	It("is empty without an application database", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{Name: "cluster-example"},
+		}
+		Expect(cluster.GetServiceBinding()).To(BeNil())
+	})
+})
+
+var _ = Describe("PostgreSQL flavor", func() {
+	It("detects the flavor from the image", func() {
+		cluster := Cluster{Spec: ClusterSpec{ImageName: "quay.io/enterprisedb/edb-postgres-advanced:16"}}
+		Expect(cluster.GetPostgresFlavor()).To(Equal(postgres.FlavorEPAS))
+		Expect(cluster.GetSuperuserName()).To(Equal("enterprisedb"))
+
+		cluster.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:16.2"
+		Expect(cluster.GetPostgresFlavor()).To(Equal(postgres.FlavorPostgreSQL))
+		Expect(cluster.GetSuperuserName()).To(Equal("postgres"))
+	})
+
+	It("uses the requested flavor", func() {
+		cluster := Cluster{Spec: ClusterSpec{
+			ImageName:             "registry.example.com/epas:16",
+			PostgresConfiguration: PostgresConfiguration{Flavor: postgres.FlavorEPAS},
+		}}
+		Expect(cluster.GetPostgresFlavor()).To(Equal(postgres.FlavorEPAS))
+	})
+})
+
+var _ = Describe("Cluster plugins", func() {
+	It("finds the configuration of a plugin", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Plugins: []PluginConfiguration{
+					{Name: "backup", Parameters: map[string]string{"bucket": "test"}},
+				},
+			},
+		}
+
+		configuration, found := cluster.GetPlugin("backup")
+		Expect(found).To(BeTrue())
+		Expect(configuration.Parameters).To(HaveKeyWithValue("bucket", "test"))
+
+		_, found = cluster.GetPlugin("sidecar")
+		Expect(found).To(BeFalse())
+	})
+})
+
+var _ = Describe("Promotion timeout action", func() {
+	It("keeps waiting by default", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetPromotionTimeoutAction()).To(Equal(PromotionTimeoutActionWait))
+	})
+
+	It("uses the requested action", func() {
+		cluster := Cluster{Spec: ClusterSpec{
+			PostgresConfiguration: PostgresConfiguration{PromotionTimeoutAction: PromotionTimeoutActionRequeue},
+		}}
+		Expect(cluster.GetPromotionTimeoutAction()).To(Equal(PromotionTimeoutActionRequeue))
+	})
+})
+
+var _ = Describe("Standby configuration", func() {
+	feedback := true
+	unlimited := int32(-1)
+	shortDelay := int32(30)
+
+	It("generates the PostgreSQL parameters", func() {
+		configuration := PostgresConfiguration{
+			Standby: &StandbyConfiguration{
+				HotStandbyFeedback: &feedback,
+				MaxStreamingDelay:  &shortDelay,
+				MaxArchiveDelay:    &unlimited,
+			},
+		}
+		Expect(configuration.GetParameters()).To(Equal(map[string]string{
+			"hot_standby_feedback":        "on",
+			"max_standby_streaming_delay": "30s",
+			"max_standby_archive_delay":   "-1",
+		}))
+	})
+
+	It("warns about hot_standby_feedback with unlimited delays", func() {
+		configuration := StandbyConfiguration{
+			HotStandbyFeedback: &feedback,
+			MaxStreamingDelay:  &unlimited,
+		}
+		Expect(configuration.GetWarnings()).To(HaveLen(1))
+	})
+
+	It("doesn't warn about short delays", func() {
+		configuration := StandbyConfiguration{
+			HotStandbyFeedback: &feedback,
+			MaxStreamingDelay:  &shortDelay,
+		}
+		Expect(configuration.GetWarnings()).To(BeEmpty())
+		configuration = StandbyConfiguration{MaxStreamingDelay: &unlimited}
+		Expect(configuration.GetWarnings()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Connections configuration", func() {
+	It("generates the PostgreSQL parameters", func() {
+		maxConnections := int32(200)
+		reserved := int32(5)
+		configuration := PostgresConfiguration{
+			Parameters: map[string]string{"work_mem": "8MB"},
+			Connections: &ConnectionsConfiguration{
+				MaxConnections:               &maxConnections,
+				SuperuserReservedConnections: &reserved,
+			},
+		}
+		Expect(configuration.GetParameters()).To(Equal(map[string]string{
+			"work_mem":                       "8MB",
+			"max_connections":                "200",
+			"superuser_reserved_connections": "5",
+		}))
+	})
+})
+
+var _ = Describe("Locale configuration", func() {
+	It("generates the PostgreSQL parameters", func() {
+		configuration := PostgresConfiguration{
+			Parameters: map[string]string{"work_mem": "8MB"},
+			Locale: &LocaleConfiguration{
+				TimeZone:  "Europe/Rome",
+				Messages:  "en_US.UTF-8",
+				Time:      "it_IT.UTF-8",
+				DateStyle: "ISO, DMY",
+			},
+		}
+		Expect(configuration.GetParameters()).To(Equal(map[string]string{
+			"work_mem":     "8MB",
+			"timezone":     "Europe/Rome",
+			"log_timezone": "Europe/Rome",
+			"lc_messages":  "en_US.UTF-8",
+			"lc_time":      "it_IT.UTF-8",
+			"datestyle":    "ISO, DMY",
+		}))
+	})
+
+	It("uses a different time zone for the logs when requested", func() {
+		locale := LocaleConfiguration{TimeZone: "Europe/Rome", LogTimeZone: "UTC"}
+		Expect(locale.GetParameters()).To(Equal(map[string]string{
+			"timezone":     "Europe/Rome",
+			"log_timezone": "UTC",
+		}))
+	})
+})
+
+var _ = Describe("Generated secret names", func() {
+	It("keeps the default names without a template", func() {
+		cluster := Cluster{ObjectMeta: v1.ObjectMeta{Name: "cluster-example"}}
+		Expect(cluster.GetSuperuserSecretName()).To(Equal("cluster-example-superuser"))
+		Expect(cluster.GetApplicationSecretName()).To(Equal("cluster-example-app"))
+		Expect(cluster.GetServerCASecretName()).To(Equal("cluster-example-ca"))
+		Expect(cluster.GetReplicationSecretName()).To(Equal("cluster-example-replication"))
+	})
+
+	It("uses the template", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				GeneratedObjects: &GeneratedObjectsConfiguration{SecretNameTemplate: "pg-{secret}-{cluster}"},
+			},
+		}
+		Expect(cluster.GetSuperuserSecretName()).To(Equal("pg-superuser-cluster-example"))
+		Expect(cluster.GetServerTLSSecretName()).To(Equal("pg-server-cluster-example"))
+		Expect(cluster.GetClientCASecretName()).To(Equal("pg-ca-cluster-example"))
+	})
+
+	It("gives precedence to the names requested by the user", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				GeneratedObjects: &GeneratedObjectsConfiguration{SecretNameTemplate: "pg-{secret}-{cluster}"},
+				SuperuserSecret:  &LocalObjectReference{Name: "my-superuser"},
+			},
+		}
+		Expect(cluster.GetSuperuserSecretName()).To(Equal("my-superuser"))
+	})
+})
+
+var _ = Describe("PVC retention policy", func() {
+	It("deletes the PVCs by default", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetPVCRetentionPolicyWhenDeleted()).To(Equal(PVCRetentionPolicyDelete))
+		Expect(cluster.GetPVCRetentionPolicyWhenScaled()).To(Equal(PVCRetentionPolicyDelete))
+	})
+
+	It("uses the configured policies", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				StorageConfiguration: StorageConfiguration{
+					PersistentVolumeClaimRetentionPolicy: &PVCRetentionPolicy{
+						WhenDeleted: PVCRetentionPolicyRetain,
+					},
+				},
+			},
+		}
+		Expect(cluster.GetPVCRetentionPolicyWhenDeleted()).To(Equal(PVCRetentionPolicyRetain))
+		Expect(cluster.GetPVCRetentionPolicyWhenScaled()).To(Equal(PVCRetentionPolicyDelete))
+	})
+})
+
+var _ = Describe("drift detection", func() {
+	It("repairs the drift every five minutes by default", func() {
+		cluster := Cluster{}
+		Expect(cluster.IsDriftAutoRepairEnabled()).To(BeTrue())
+		Expect(cluster.GetDriftDetectionInterval()).To(Equal(5 * time.Minute))
+	})
+
+	It("can be configured", func() {
+		autoRepair := false
+		cluster := Cluster{Spec: ClusterSpec{DriftDetection: &DriftDetectionConfiguration{
+			AutoRepair: &autoRepair,
+			Interval:   &v1.Duration{Duration: time.Minute},
+		}}}
+		Expect(cluster.IsDriftAutoRepairEnabled()).To(BeFalse())
+		Expect(cluster.GetDriftDetectionInterval()).To(Equal(time.Minute))
+	})
+})
+
+var _ = Describe("primary heartbeat", func() {
+	It("is disabled by default", func() {
+		cluster := Cluster{ObjectMeta: v1.ObjectMeta{Name: "cluster-example"}}
+		Expect(cluster.GetPrimaryHeartbeatTimeout()).To(BeZero())
+		Expect(cluster.GetPrimaryHeartbeatLeaseName()).To(Equal("cluster-example-primary-heartbeat"))
+	})
+
+	It("can be enabled with a timeout", func() {
+		cluster := Cluster{Spec: ClusterSpec{PrimaryHeartbeatTimeout: 30}}
+		Expect(cluster.GetPrimaryHeartbeatTimeout()).To(Equal(30 * time.Second))
+	})
+})
+
+var _ = Describe("priority class", func() {
+	It("is not set by default", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetPriorityClassName("cluster-example-1")).To(BeEmpty())
+	})
+
+	It("uses the primary priority class for the primary instance", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PriorityClassName:        "database",
+				PrimaryPriorityClassName: "database-primary",
+			},
+			Status: ClusterStatus{TargetPrimary: "cluster-example-1"},
+		}
+		Expect(cluster.GetPriorityClassName("cluster-example-1")).To(Equal("database-primary"))
+		Expect(cluster.GetPriorityClassName("cluster-example-2")).To(Equal("database"))
+
+		cluster.Spec.PrimaryPriorityClassName = ""
+		Expect(cluster.GetPriorityClassName("cluster-example-1")).To(Equal("database"))
+	})
+})
+
+var _ = Describe("chaos experiments", func() {
+	startedAt := time.Now().Add(-time.Minute)
+	experiment := &utils.ChaosExperiment{
+		Action:    utils.ChaosExperimentPauseArchiving,
+		Instance:  "cluster-example-1",
+		StartedAt: v1.NewTime(startedAt),
+		Duration:  v1.Duration{Duration: 5 * time.Minute},
+	}
+
+	It("gets the experiment running on an instance", func() {
+		cluster := &Cluster{Spec: ClusterSpec{EnableChaosTesting: true}}
+		Expect(utils.SetChaosExperiment(&cluster.ObjectMeta, experiment)).To(Succeed())
+		experiment := cluster.GetActiveChaosExperiment("cluster-example-1", time.Now())
+		Expect(experiment).ToNot(BeNil())
+		Expect(experiment.Action).To(Equal(utils.ChaosExperimentPauseArchiving))
+
+		Expect(cluster.GetActiveChaosExperiment("cluster-example-2", time.Now())).To(BeNil())
+		Expect(cluster.GetActiveChaosExperiment("cluster-example-1", startedAt.Add(time.Hour))).To(BeNil())
+	})
+
+	It("ignores the experiments when chaos testing is disabled", func() {
+		cluster := &Cluster{}
+		Expect(utils.SetChaosExperiment(&cluster.ObjectMeta, experiment)).To(Succeed())
+		Expect(cluster.GetActiveChaosExperiment("cluster-example-1", time.Now())).To(BeNil())
+	})
+})
+
+var _ = Describe("recovery remapping", func() {
+	var backup *Backup
+
+	BeforeEach(func() {
+		backup = &Backup{
+			Status: BackupStatus{
+				BarmanCredentials: BarmanCredentials{
+					AWS: &S3Credentials{
+						AccessKeyIDReference: &SecretKeySelector{
+							LocalObjectReference: LocalObjectReference{Name: "prod-aws"},
+							Key:                  "ID",
+						},
+						SecretAccessKeyReference: &SecretKeySelector{
+							LocalObjectReference: LocalObjectReference{Name: "prod-aws"},
+							Key:                  "KEY",
+						},
+						RegionReference: &SecretKeySelector{
+							LocalObjectReference: LocalObjectReference{Name: "region"},
+							Key:                  "REGION",
+						},
+					},
+				},
+				EndpointCA: &SecretKeySelector{
+					LocalObjectReference: LocalObjectReference{Name: "prod-ca"},
+					Key:                  "ca.crt",
+				},
+				DestinationPath: "s3://prod/",
+				ServerName:      "cluster-prod",
+			},
+		}
+	})
+
+	It("is not set without a recovery", func() {
+		Expect((&Cluster{}).GetRecoveryRemapping()).To(BeNil())
+	})
+
+	It("leaves the backup alone without a remapping", func() {
+		originalBackup := backup.DeepCopy()
+		(&Cluster{}).GetRecoveryRemapping().ApplyToBackup(backup)
+		Expect(backup).To(Equal(originalBackup))
+	})
+
+	It("replaces the references of the backup", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Remap: &RecoveryRemapping{
+							Secrets: map[string]string{
+								"prod-aws": "staging-aws",
+								"prod-ca":  "staging-ca",
+							},
+							DestinationPath: "s3://replica/",
+						},
+					},
+				},
+			},
+		}
+
+		cluster.GetRecoveryRemapping().ApplyToBackup(backup)
+		Expect(backup.Status.AWS.AccessKeyIDReference.Name).To(Equal("staging-aws"))
+		Expect(backup.Status.AWS.AccessKeyIDReference.Key).To(Equal("ID"))
+		Expect(backup.Status.AWS.SecretAccessKeyReference.Name).To(Equal("staging-aws"))
+		Expect(backup.Status.AWS.RegionReference.Name).To(Equal("region"))
+		Expect(backup.Status.EndpointCA.Name).To(Equal("staging-ca"))
+		Expect(backup.Status.DestinationPath).To(Equal("s3://replica/"))
+		Expect(backup.Status.ServerName).To(Equal("cluster-prod"))
+	})
+})
+
+var _ = Describe("Switchover in progress", func() {
+	It("is detected when the target primary is the switchover target", func() {
+		cluster := &Cluster{
+			Status: ClusterStatus{
+				CurrentPrimary:   "cluster-example-1",
+				TargetPrimary:    "cluster-example-2",
+				SwitchoverTarget: "cluster-example-2",
+			},
+		}
+		Expect(cluster.IsSwitchoverInProgress()).To(BeTrue())
+	})
+
+	It("is not detected during a failover", func() {
+		cluster := &Cluster{
+			Status: ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-2",
+				Phase:          PhaseSwitchover,
+			},
+		}
+		Expect(cluster.IsSwitchoverInProgress()).To(BeFalse())
+	})
+
+	It("is not detected when the target primary changed after the switchover", func() {
+		cluster := &Cluster{
+			Status: ClusterStatus{
+				CurrentPrimary:   "cluster-example-1",
+				TargetPrimary:    "cluster-example-3",
+				SwitchoverTarget: "cluster-example-2",
+			},
+		}
+		Expect(cluster.IsSwitchoverInProgress()).To(BeFalse())
+	})
+
+	It("is not detected once the switchover is completed", func() {
+		cluster := &Cluster{
+			Status: ClusterStatus{
+				CurrentPrimary:   "cluster-example-2",
+				TargetPrimary:    "cluster-example-2",
+				SwitchoverTarget: "cluster-example-2",
+			},
+		}
+		Expect(cluster.IsSwitchoverInProgress()).To(BeFalse())
+	})
+})
+
+var _ = Describe("Transfer rate", func() {
+	It("is not limited when empty", func() {
+		Expect(TransferRate("").GetBytesPerSecond()).To(BeZero())
+	})
+
+	It("is expressed in kilobytes per second by default", func() {
+		Expect(TransferRate("32").GetBytesPerSecond()).To(BeEquivalentTo(32 * 1024))
+		Expect(TransferRate("32k").GetBytesPerSecond()).To(BeEquivalentTo(32 * 1024))
+		Expect(TransferRate("32M").GetBytesPerSecond()).To(BeEquivalentTo(32 * 1024 * 1024))
+	})
+
+	It("rejects an invalid rate", func() {
+		_, err := TransferRate("32G").GetBytesPerSecond()
+		Expect(err).To(HaveOccurred())
+	})
+})
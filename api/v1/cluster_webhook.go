@@ -19,10 +19,16 @@ package v1
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"path"
 	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/robfig/cron"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -60,6 +66,21 @@ const (
 // clusterLog is for logging in this package.
 var clusterLog = log.WithName("cluster-resource").WithValues("version", "v1")
 
+// timeZoneNameRegex matches the names of the time zone database of
+// PostgreSQL, e.g. `Europe/Rome` or `Etc/GMT+3`, and the abbreviations,
+// e.g. `UTC` or `EST5EDT`
+var timeZoneNameRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_+-]*(/[A-Za-z0-9_+-]+)*$`)
+
+// posixTimeZoneRegex matches the POSIX-style time zone specifications
+// accepted by PostgreSQL, e.g. `<+03>-3` or `CET-1CEST,M3.5.0,M10.5.0/3`
+var posixTimeZoneRegex = regexp.MustCompile(
+	`^(<[A-Za-z0-9+-]+>|[A-Za-z]{3,})[+-]?[0-9]{1,2}(:[0-9]{2}){0,2}` +
+		`((<[A-Za-z0-9+-]+>|[A-Za-z]{3,})([+-]?[0-9]{1,2}(:[0-9]{2}){0,2})?` +
+		`(,[A-Za-z0-9.:/+-]+,[A-Za-z0-9.:/+-]+)?)?$`)
+
+// localeNameRegex matches the names of the locales, e.g. `en_US.UTF-8`
+var localeNameRegex = regexp.MustCompile(`^[A-Za-z0-9_.@-]+$`)
+
 // SetupWebhookWithManager setup the webhook inside the controller manager
 func (r *Cluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
@@ -75,7 +96,108 @@ var _ webhook.Defaulter = &Cluster{}
 func (r *Cluster) Default() {
 	clusterLog.Info("default", "name", r.Name, "namespace", r.Namespace)
 
+	// The pod anti-affinity type needs to be defaulted before the
+	// other settings, which would otherwise default it to "preferred"
+	r.defaultPodAntiAffinityType()
 	r.setDefaults(true)
+	r.defaultResources(configuration.Current())
+	r.defaultProbes()
+	r.defaultHugePages()
+}
+
+// defaultHugePages sets `huge_pages` to `on` in a new cluster requesting
+// huge pages, so that PostgreSQL fails to start instead of silently falling
+// back to regular pages. The existing clusters are not changed, as their
+// huge pages may not be able to contain the shared memory of PostgreSQL
+func (r *Cluster) defaultHugePages() {
+	if !r.CreationTimestamp.IsZero() || len(r.GetHugePagesLimits()) == 0 {
+		return
+	}
+
+	if _, ok := r.Spec.PostgresConfiguration.Parameters["huge_pages"]; ok {
+		return
+	}
+
+	if r.Spec.PostgresConfiguration.Parameters == nil {
+		r.Spec.PostgresConfiguration.Parameters = make(map[string]string)
+	}
+	r.Spec.PostgresConfiguration.Parameters["huge_pages"] = "on"
+}
+
+// defaultPodAntiAffinityType requires the instances of a new cluster to
+// run on different nodes, unless the user opted out of it
+func (r *Cluster) defaultPodAntiAffinityType() {
+	if !r.CreationTimestamp.IsZero() || !r.IsRequiredPodAntiAffinityDefaulted() {
+		return
+	}
+
+	if r.Spec.Affinity.PodAntiAffinityType != "" ||
+		(r.Spec.Affinity.EnablePodAntiAffinity != nil && !*r.Spec.Affinity.EnablePodAntiAffinity) {
+		return
+	}
+
+	r.Spec.Affinity.PodAntiAffinityType = PodAntiAffinityTypeRequired
+}
+
+// defaultProbes writes the timings of the probes in the specification of a
+// new cluster, so that they are not changed by an upgrade of the operator.
+// The failure threshold of the startup probe is not written, as it is
+// computed from the start delay
+func (r *Cluster) defaultProbes() {
+	if !r.CreationTimestamp.IsZero() || !r.AreProbesDefaulted() {
+		return
+	}
+
+	if r.Spec.Probes == nil {
+		r.Spec.Probes = &ProbesConfiguration{}
+	}
+	if r.Spec.Probes.Startup == nil {
+		r.Spec.Probes.Startup = &Probe{}
+	}
+	if r.Spec.Probes.Liveness == nil {
+		r.Spec.Probes.Liveness = &Probe{}
+	}
+	if r.Spec.Probes.Readiness == nil {
+		r.Spec.Probes.Readiness = &ReadinessProbe{}
+	}
+
+	defaultProbeTimings(r.Spec.Probes.Startup)
+	defaultProbeTimings(r.Spec.Probes.Liveness)
+	defaultProbeTimings(&r.Spec.Probes.Readiness.Probe)
+	if r.Spec.Probes.Liveness.FailureThreshold == 0 {
+		r.Spec.Probes.Liveness.FailureThreshold = DefaultProbeFailureThreshold
+	}
+	if r.Spec.Probes.Readiness.FailureThreshold == 0 {
+		r.Spec.Probes.Readiness.FailureThreshold = DefaultProbeFailureThreshold
+	}
+}
+
+// defaultProbeTimings sets the timeout and the period of a probe
+// to the defaults of the operator, unless already specified
+func defaultProbeTimings(probe *Probe) {
+	if probe.TimeoutSeconds == 0 {
+		probe.TimeoutSeconds = DefaultProbeTimeoutSeconds
+	}
+	if probe.PeriodSeconds == 0 {
+		probe.PeriodSeconds = DefaultProbePeriodSeconds
+	}
+}
+
+// defaultResources sets the resources of the PostgreSQL containers to the
+// defaults of the operator configuration, unless they are already specified
+// or the user opted out of them.
+// This only happens when the cluster is created, as changing the operator
+// configuration must not trigger a rollout of the existing clusters
+func (r *Cluster) defaultResources(config *configuration.Data) {
+	if !r.CreationTimestamp.IsZero() || !r.AreResourcesDefaulted() {
+		return
+	}
+
+	if len(r.Spec.Resources.Requests) > 0 || len(r.Spec.Resources.Limits) > 0 {
+		return
+	}
+
+	r.Spec.Resources = config.GetDefaultResources()
 }
 
 // SetDefaults apply the defaults to undefined values in a Cluster
@@ -84,9 +206,10 @@ func (r *Cluster) SetDefaults() {
 }
 
 func (r *Cluster) setDefaults(preserveUserSettings bool) {
-	// Defaulting the image name if not specified
-	if r.Spec.ImageName == "" {
-		r.Spec.ImageName = configuration.Current.PostgresImageName
+	// Defaulting the image name if not specified, unless the image
+	// is taken from an image catalog
+	if r.Spec.ImageName == "" && r.Spec.ImageCatalogRef == nil {
+		r.Spec.ImageName = configuration.Current().PostgresImageName
 	}
 
 	// Defaulting the bootstrap method if not specified
@@ -100,6 +223,8 @@ func (r *Cluster) setDefaults(preserveUserSettings bool) {
 		r.defaultRecovery()
 	case r.Spec.Bootstrap.PgBaseBackup != nil:
 		r.defaultPgBaseBackup()
+	case r.Spec.Bootstrap.Adopt != nil:
+		r.defaultAdopt()
 	default:
 		r.defaultInitDB()
 	}
@@ -119,6 +244,7 @@ func (r *Cluster) setDefaults(preserveUserSettings bool) {
 			MajorVersion:                  psqlVersion,
 			UserSettings:                  r.Spec.PostgresConfiguration.Parameters,
 			IsReplicaCluster:              r.IsReplica(),
+			Flavor:                        r.GetPostgresFlavor(),
 			PreserveFixedSettingsFromUser: preserveUserSettings,
 		}
 		sanitizedParameters := postgres.CreatePostgresqlConfiguration(info).GetConfigurationParameters()
@@ -129,10 +255,29 @@ func (r *Cluster) setDefaults(preserveUserSettings bool) {
 		r.Spec.LogLevel = log.InfoLevelString
 	}
 
+	if r.Spec.GuaranteedQoS {
+		r.defaultGuaranteedQoSRequests()
+	}
+
 	// we inject the defaultMonitoringQueries if the MonitoringQueriesConfigmap parameter is not empty
 	// and defaultQueries not disabled on cluster crd
 	if !r.Spec.Monitoring.AreDefaultQueriesDisabled() {
-		r.defaultMonitoringQueries(configuration.Current)
+		r.defaultMonitoringQueries(configuration.Current())
+	}
+}
+
+// defaultGuaranteedQoSRequests sets the resource requests
+// which are not specified to the corresponding limits
+func (r *Cluster) defaultGuaranteedQoSRequests() {
+	for name, limit := range r.Spec.Resources.Limits {
+		if _, ok := r.Spec.Resources.Requests[name]; ok {
+			continue
+		}
+
+		if r.Spec.Resources.Requests == nil {
+			r.Spec.Resources.Requests = make(v1.ResourceList)
+		}
+		r.Spec.Resources.Requests[name] = limit.DeepCopy()
 	}
 }
 
@@ -215,6 +360,13 @@ func (r *Cluster) defaultInitDB() {
 
 // defaultRecovery enriches the recovery with defaults if not all the required arguments were passed
 func (r *Cluster) defaultRecovery() {
+	if r.Spec.Bootstrap.Recovery.VolumeSnapshots != nil {
+		defaultVolumeSnapshotReference(&r.Spec.Bootstrap.Recovery.VolumeSnapshots.Storage)
+		if r.Spec.Bootstrap.Recovery.VolumeSnapshots.WalStorage != nil {
+			defaultVolumeSnapshotReference(r.Spec.Bootstrap.Recovery.VolumeSnapshots.WalStorage)
+		}
+	}
+
 	// if none area is provided, will ignore the application database configuration
 	if r.Spec.Bootstrap.Recovery.Database == "" &&
 		r.Spec.Bootstrap.Recovery.Owner == "" &&
@@ -229,6 +381,20 @@ func (r *Cluster) defaultRecovery() {
 	}
 }
 
+// defaultVolumeSnapshotReference makes a data source refer to a
+// VolumeSnapshot when its kind is not specified
+func defaultVolumeSnapshotReference(reference *v1.TypedLocalObjectReference) {
+	if reference.Kind != "" {
+		return
+	}
+
+	reference.Kind = VolumeSnapshotKind
+	if reference.APIGroup == nil {
+		apiGroup := VolumeSnapshotAPIGroup
+		reference.APIGroup = &apiGroup
+	}
+}
+
 // defaultPgBaseBackup enriches the pg_basebackup with defaults if not all the required arguments were passed
 func (r *Cluster) defaultPgBaseBackup() {
 	// if none area is provided, will ignore the application database configuration
@@ -245,6 +411,14 @@ func (r *Cluster) defaultPgBaseBackup() {
 	}
 }
 
+// defaultAdopt enforces the adoption of the PVCs of the first instance
+// if no serial number has been specified
+func (r *Cluster) defaultAdopt() {
+	if r.Spec.Bootstrap.Adopt.InstanceSerial == 0 {
+		r.Spec.Bootstrap.Adopt.InstanceSerial = 1
+	}
+}
+
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
 // +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=create;update,path=/validate-postgresql-cnpg-io-v1-cluster,mutating=false,failurePolicy=fail,groups=postgresql.cnpg.io,resources=clusters,versions=v1,name=vcluster.kb.io,sideEffects=None
 
@@ -275,30 +449,716 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validateCerts,
 		r.validateBootstrapMethod,
 		r.validateImageName,
+		r.validateImageCatalogRef,
 		r.validateImagePullPolicy,
 		r.validateRecoveryTarget,
 		r.validatePrimaryUpdateStrategy,
 		r.validateMinSyncReplicas,
 		r.validateMaxSyncReplicas,
 		r.validateWalStorageSize,
+		r.validatePVCRetentionPolicy,
 		r.validateName,
 		r.validateBootstrapPgBaseBackupSource,
 		r.validateBootstrapRecoverySource,
+		r.validateRecoveryVolumeSnapshots,
+		r.validateRecoveryRemapping,
+		r.validateReplicaCreationMethod,
+		r.validateCloneConfigurations,
+		r.validateReplicationConnection,
+		r.validateBootstrapImportSource,
 		r.validateRecoveryAndBackupTarget,
 		r.validateExternalClusters,
 		r.validateTolerations,
+		r.validatePriorityClassNames,
+		r.validateExternalDNS,
 		r.validateAntiAffinity,
 		r.validateReplicaMode,
 		r.validateBackupConfiguration,
 		r.validateConfiguration,
 		r.validateLDAP,
+		r.validateReadinessProbe,
+		r.validateInstanceHealth,
+		r.validatePrimaryHeartbeat,
+		r.validateChaosExperiment,
+		r.validateSharedPreloadLibraries,
+		r.validatePgAudit,
+		r.validatePostgresLogging,
+		r.validateStandbyConfiguration,
+		r.validateConnectionsConfiguration,
+		r.validateLocaleConfiguration,
+		r.validateGeneratedObjects,
+		r.validateDiskSpace,
+		r.validateImageUpdate,
+		r.validatePrimaryUpdateWindows,
+		r.validateDriftDetection,
+		r.validateResources,
+		r.validateHugePages,
+		r.validateMonitoring,
+		r.validateDataVerification,
+		r.validateManagedExtensions,
+		r.validateManagedRoles,
+		r.validateExtensionImages,
+		r.validateTDE,
+		r.validatePlugins,
+		r.validateAdditionalObjectStores,
+	}
+
+	for _, validate := range validations {
+		allErrs = append(allErrs, validate()...)
+	}
+
+	return allErrs
+}
+
+// validatePgAudit checks that the pgaudit parameters are not specified
+// both in the pgaudit section and in the PostgreSQL parameters
+func (r *Cluster) validatePgAudit() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.PostgresConfiguration.PgAudit == nil {
+		return result
+	}
+
+	for key := range r.Spec.PostgresConfiguration.Parameters {
+		if strings.HasPrefix(key, "pgaudit.") {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "postgresql", "parameters", key),
+				r.Spec.PostgresConfiguration.Parameters[key],
+				"pgaudit parameters cannot be specified together with the pgaudit section"))
+		}
+	}
+
+	return result
+}
+
+// validatePostgresLogging checks that the parameters managed by the
+// logging section are not specified in the PostgreSQL parameters too
+func (r *Cluster) validatePostgresLogging() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.PostgresConfiguration.Logging == nil {
+		return result
+	}
+
+	for _, key := range r.Spec.PostgresConfiguration.Logging.GetManagedParameters() {
+		if value, ok := r.Spec.PostgresConfiguration.Parameters[key]; ok {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "postgresql", "parameters", key),
+				value,
+				"this parameter cannot be specified together with the logging section"))
+		}
+	}
+
+	return result
+}
+
+// validateStandbyConfiguration checks that the parameters managed by the
+// standby section are not specified in the PostgreSQL parameters too
+func (r *Cluster) validateStandbyConfiguration() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.PostgresConfiguration.Standby == nil {
+		return result
+	}
+
+	for _, key := range r.Spec.PostgresConfiguration.Standby.GetManagedParameters() {
+		if value, ok := r.Spec.PostgresConfiguration.Parameters[key]; ok {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "postgresql", "parameters", key),
+				value,
+				"this parameter cannot be specified together with the standby section"))
+		}
+	}
+
+	return result
+}
+
+// validateConnectionsConfiguration checks that the parameters managed by
+// the connections section are not specified in the PostgreSQL parameters
+// too, and that some connection slots are left to the non-superusers
+func (r *Cluster) validateConnectionsConfiguration() field.ErrorList {
+	var result field.ErrorList
+
+	connections := r.Spec.PostgresConfiguration.Connections
+	if connections == nil {
+		return result
+	}
+
+	for _, key := range connections.GetManagedParameters() {
+		if value, ok := r.Spec.PostgresConfiguration.Parameters[key]; ok {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "postgresql", "parameters", key),
+				value,
+				"this parameter cannot be specified together with the connections section"))
+		}
+	}
+
+	if connections.MaxConnections != nil && connections.SuperuserReservedConnections != nil &&
+		*connections.SuperuserReservedConnections >= *connections.MaxConnections {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "postgresql", "connections", "superuserReservedConnections"),
+			*connections.SuperuserReservedConnections,
+			"superuserReservedConnections must be lower than maxConnections"))
+	}
+
+	return result
+}
+
+// validateLocaleConfiguration checks that the parameters managed by the
+// locale section are not specified in the PostgreSQL parameters too, and
+// that the time zones, the locales and the date style are valid
+func (r *Cluster) validateLocaleConfiguration() field.ErrorList {
+	var result field.ErrorList
+
+	locale := r.Spec.PostgresConfiguration.Locale
+	if locale == nil {
+		return result
+	}
+
+	for _, key := range locale.GetManagedParameters() {
+		if value, ok := r.Spec.PostgresConfiguration.Parameters[key]; ok {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "postgresql", "parameters", key),
+				value,
+				"this parameter cannot be specified together with the locale section"))
+		}
+	}
+
+	path := field.NewPath("spec", "postgresql", "locale")
+	for name, timeZone := range map[string]string{
+		"timeZone":    locale.TimeZone,
+		"logTimeZone": locale.LogTimeZone,
+	} {
+		if timeZone == "" {
+			continue
+		}
+		// The names are resolved by PostgreSQL using its own time zone
+		// database, so only their syntax can be checked here
+		if !timeZoneNameRegex.MatchString(timeZone) && !posixTimeZoneRegex.MatchString(timeZone) {
+			result = append(result, field.Invalid(path.Child(name), timeZone,
+				"must be a time zone name, e.g. Europe/Rome, or a POSIX-style time zone specification"))
+		}
+	}
+
+	for name, localeName := range map[string]string{
+		"messages": locale.Messages,
+		"monetary": locale.Monetary,
+		"numeric":  locale.Numeric,
+		"time":     locale.Time,
+	} {
+		if localeName != "" && !localeNameRegex.MatchString(localeName) {
+			result = append(result, field.Invalid(path.Child(name), localeName,
+				"must be a locale name, e.g. en_US.UTF-8"))
+		}
+	}
+
+	if locale.DateStyle != "" {
+		if err := validateDateStyle(locale.DateStyle); err != nil {
+			result = append(result, field.Invalid(path.Child("dateStyle"), locale.DateStyle, err.Error()))
+		}
+	}
+
+	return result
+}
+
+// validateDateStyle checks that a date style is composed of at most an
+// output format and at most an input field order, as accepted by the
+// `datestyle` parameter
+func validateDateStyle(dateStyle string) error {
+	outputFormats := 0
+	fieldOrders := 0
+	for _, item := range strings.Split(dateStyle, ",") {
+		switch strings.ToLower(strings.TrimSpace(item)) {
+		case "iso", "postgres", "sql", "german":
+			outputFormats++
+		case "dmy", "mdy", "ymd", "euro", "european", "us", "noneuro", "noneuropean":
+			fieldOrders++
+		default:
+			return fmt.Errorf("unknown date style %q, expected an output format "+
+				"(ISO, Postgres, SQL or German) and/or an input order (DMY, MDY or YMD)",
+				strings.TrimSpace(item))
+		}
+	}
+
+	if outputFormats > 1 || fieldOrders > 1 {
+		return fmt.Errorf("the date style can contain only one output format and one input order")
+	}
+
+	return nil
+}
+
+// validateGeneratedObjects checks that the secret name template contains
+// both placeholders, so that the names of the secrets are unique, and that
+// it generates valid names
+func (r *Cluster) validateGeneratedObjects() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.GeneratedObjects == nil || r.Spec.GeneratedObjects.SecretNameTemplate == "" {
+		return result
+	}
+
+	template := r.Spec.GeneratedObjects.SecretNameTemplate
+	path := field.NewPath("spec", "generatedObjects", "secretNameTemplate")
+	for _, placeholder := range []string{SecretNameClusterPlaceholder, SecretNameKindPlaceholder} {
+		if !strings.Contains(template, placeholder) {
+			result = append(result, field.Invalid(
+				path,
+				template,
+				fmt.Sprintf("the template must contain the %s placeholder", placeholder)))
+		}
+	}
+	if len(result) > 0 {
+		return result
+	}
+
+	for _, suffix := range []string{SuperUserSecretSuffix, ReplicationSecretSuffix} {
+		name := r.getGeneratedSecretName(suffix)
+		if errs := validationutil.IsDNS1123Subdomain(name); len(errs) > 0 {
+			result = append(result, field.Invalid(
+				path,
+				template,
+				fmt.Sprintf("the template generates the invalid secret name %q: %s",
+					name, strings.Join(errs, ", "))))
+		}
+	}
+
+	return result
+}
+
+// validateSecretNameTemplateChange checks that the secret name template
+// is not changed, as the secrets would not be found anymore
+func (r *Cluster) validateSecretNameTemplateChange(old *Cluster) field.ErrorList {
+	var result field.ErrorList
+
+	if template := r.GetSecretNameTemplate(); template != old.GetSecretNameTemplate() {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "generatedObjects", "secretNameTemplate"),
+			template,
+			"the secret name template cannot be changed"))
+	}
+
+	return result
+}
+
+// validateDiskSpace checks that the warning threshold of the disk
+// space usage is lower than the critical one
+func (r *Cluster) validateDiskSpace() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.DiskSpace == nil {
+		return result
+	}
+
+	if r.GetDiskSpaceWarningThreshold() >= r.GetDiskSpaceCriticalThreshold() {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "diskSpace", "warningThreshold"),
+			r.GetDiskSpaceWarningThreshold(),
+			"the warning threshold must be lower than the critical threshold"))
+	}
+
+	return result
+}
+
+// validateImageUpdate validates the maintenance windows
+// of the image update policy
+func (r *Cluster) validateImageUpdate() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.ImageUpdate == nil {
+		return result
+	}
+
+	return validateMaintenanceWindows(
+		field.NewPath("spec", "imageUpdate", "maintenanceWindows"),
+		r.Spec.ImageUpdate.MaintenanceWindows)
+}
+
+// validatePrimaryUpdateWindows validates the maintenance
+// windows in which the primary instance can be updated
+func (r *Cluster) validatePrimaryUpdateWindows() field.ErrorList {
+	return validateMaintenanceWindows(
+		field.NewPath("spec", "primaryUpdateWindows"),
+		r.Spec.PrimaryUpdateWindows)
+}
+
+// validateMaintenanceWindows validates the start time
+// and the duration of a list of maintenance windows
+func validateMaintenanceWindows(windowsPath *field.Path, windows []MaintenanceWindow) field.ErrorList {
+	var result field.ErrorList
+
+	for idx, window := range windows {
+		if _, err := time.Parse("15:04", window.StartTime); err != nil {
+			result = append(result, field.Invalid(
+				windowsPath.Index(idx).Child("startTime"),
+				window.StartTime,
+				"the start time must be in the HH:MM format"))
+		}
+
+		if window.Duration.Duration <= 0 || window.Duration.Duration > maximumMaintenanceWindowDuration {
+			result = append(result, field.Invalid(
+				windowsPath.Index(idx).Child("duration"),
+				window.Duration.String(),
+				"the duration must be positive and not greater than 7 days"))
+		}
+	}
+
+	return result
+}
+
+// validateDriftDetection validates the interval
+// of the periodic drift detection
+func (r *Cluster) validateDriftDetection() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.DriftDetection == nil || r.Spec.DriftDetection.Interval == nil {
+		return result
+	}
+
+	if r.Spec.DriftDetection.Interval.Duration < minimumDriftDetectionInterval {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "driftDetection", "interval"),
+			r.Spec.DriftDetection.Interval.String(),
+			"the interval must be at least 30 seconds"))
+	}
+
+	return result
+}
+
+// validateResources validates the resources of the Pods, which
+// are required by the Guaranteed QoS class and by the memory tuning
+func (r *Cluster) validateResources() field.ErrorList {
+	var result field.ErrorList
+
+	resourcesPath := field.NewPath("spec", "resources")
+	if r.Spec.GuaranteedQoS {
+		for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+			limit, hasLimit := r.Spec.Resources.Limits[name]
+			if !hasLimit {
+				result = append(result, field.Required(
+					resourcesPath.Child("limits").Key(string(name)),
+					"the limit is required by the Guaranteed QoS class"))
+				continue
+			}
+
+			request, hasRequest := r.Spec.Resources.Requests[name]
+			if hasRequest && !request.Equal(limit) {
+				result = append(result, field.Invalid(
+					resourcesPath.Child("requests").Key(string(name)),
+					request.String(),
+					"the request must be equal to the limit with the Guaranteed QoS class"))
+			}
+		}
+	}
+
+	if r.Spec.PostgresConfiguration.MemoryTuning && r.Spec.Resources.Limits.Memory().IsZero() {
+		result = append(result, field.Required(
+			resourcesPath.Child("limits").Key(string(v1.ResourceMemory)),
+			"the memory limit is required by the memory tuning"))
+	}
+
+	return result
+}
+
+const (
+	// defaultSharedBuffers is the default value of shared_buffers in PostgreSQL
+	defaultSharedBuffers = "128MB"
+
+	// postgresBlockSize is the size of a PostgreSQL block, which is
+	// the unit of shared_buffers when no unit is specified
+	postgresBlockSize = 8192
+)
+
+// validateHugePages validates the coherence between the huge
+// pages requested for the Pods and the PostgreSQL configuration
+func (r *Cluster) validateHugePages() field.ErrorList {
+	var result field.ErrorList
+
+	parametersPath := field.NewPath("spec", "postgresql", "parameters")
+	limitsPath := field.NewPath("spec", "resources", "limits")
+	hugePages := r.GetHugePagesLimits()
+	parameters := r.GetPostgresParameters()
+	hugePagesSetting := strings.ToLower(parameters["huge_pages"])
+
+	switch {
+	case len(hugePages) == 0 && hugePagesSetting == "on":
+		return append(result, field.Invalid(
+			parametersPath.Key("huge_pages"), hugePagesSetting,
+			"huge pages must be requested in the resources of the Pods"))
+	case len(hugePages) == 0:
+		return result
+	case len(hugePages) > 1:
+		return append(result, field.Invalid(
+			limitsPath, len(hugePages),
+			"only one huge page size can be requested"))
+	case hugePagesSetting == "off":
+		return append(result, field.Invalid(
+			parametersPath.Key("huge_pages"), hugePagesSetting,
+			"huge pages are requested in the resources of the Pods"))
+	}
+
+	for name, quantity := range hugePages {
+		pageSize, err := resource.ParseQuantity(strings.TrimPrefix(string(name), v1.ResourceHugePagesPrefix))
+		if err != nil {
+			continue
+		}
+
+		if value, ok := parameters["huge_page_size"]; ok {
+			size, err := parsePostgresMemory(value, 1024)
+			if err == nil && size != 0 && size != pageSize.Value() {
+				result = append(result, field.Invalid(
+					parametersPath.Key("huge_page_size"), value,
+					fmt.Sprintf("the huge page size doesn't match the requested %s", name)))
+			}
+		}
+
+		size, err := estimateSharedMemorySize(parameters)
+		if err == nil && roundUp(size, pageSize.Value()) > quantity.Value() {
+			result = append(result, field.Invalid(
+				limitsPath.Key(string(name)), quantity.String(),
+				fmt.Sprintf("the huge pages must be able to contain the shared memory of PostgreSQL, "+
+					"estimated in %s from the shared buffers, the WAL buffers, the lock tables "+
+					"and min_dynamic_shared_memory",
+					resource.NewQuantity(size, resource.BinarySI).String())))
+		}
+	}
+
+	return result
+}
+
+// estimateSharedMemorySize estimates the size in bytes of the main shared
+// memory segment of PostgreSQL, which is the one allocated in huge pages,
+// with the formulas of the "Managing Kernel Resources" section of the
+// PostgreSQL documentation
+func estimateSharedMemorySize(parameters map[string]string) (int64, error) {
+	getInt := func(name string, defaultValue int64) (int64, error) {
+		value, ok := parameters[name]
+		if !ok {
+			return defaultValue, nil
+		}
+		return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	}
+
+	sharedBuffersValue := defaultSharedBuffers
+	if value, ok := parameters["shared_buffers"]; ok {
+		sharedBuffersValue = value
+	}
+	sharedBuffers, err := parsePostgresMemory(sharedBuffersValue, postgresBlockSize)
+	if err != nil {
+		return 0, err
+	}
+
+	// By default, the WAL buffers are 1/32 of the shared buffers,
+	// between 64kB and the size of a WAL segment
+	walBuffers := sharedBuffers / 32
+	switch value, ok := parameters["wal_buffers"]; {
+	case ok && strings.TrimSpace(value) != "-1":
+		if walBuffers, err = parsePostgresMemory(value, postgresBlockSize); err != nil {
+			return 0, err
+		}
+	case walBuffers < 64*1024:
+		walBuffers = 64 * 1024
+	case walBuffers > 16*1024*1024:
+		walBuffers = 16 * 1024 * 1024
+	}
+
+	minDynamicSharedMemory := int64(0)
+	if value, ok := parameters["min_dynamic_shared_memory"]; ok {
+		if minDynamicSharedMemory, err = parsePostgresMemory(value, 1024*1024); err != nil {
+			return 0, err
+		}
+	}
+
+	// Every backend, including the background workers and
+	// the WAL senders, has its own slot in the lock tables
+	var backends int64
+	for _, entry := range []struct {
+		name         string
+		defaultValue int64
+	}{
+		{"max_connections", 100},
+		{"autovacuum_max_workers", 3},
+		{"max_worker_processes", 8},
+		{"max_wal_senders", 10},
+	} {
+		value, err := getInt(entry.name, entry.defaultValue)
+		if err != nil {
+			return 0, err
+		}
+		backends += value
+	}
+	maxPreparedTransactions, err := getInt("max_prepared_transactions", 0)
+	if err != nil {
+		return 0, err
+	}
+	maxLocksPerTransaction, err := getInt("max_locks_per_transaction", 64)
+	if err != nil {
+		return 0, err
+	}
+
+	const fixedSize = 770 * 1024
+	return fixedSize +
+		(1800+270*maxLocksPerTransaction)*backends +
+		(770+270*maxLocksPerTransaction)*maxPreparedTransactions +
+		(postgresBlockSize+208)*(sharedBuffers/postgresBlockSize) +
+		(postgresBlockSize+8)*(walBuffers/postgresBlockSize) +
+		minDynamicSharedMemory, nil
+}
+
+// roundUp rounds a size up to a multiple of the passed unit
+func roundUp(size, unit int64) int64 {
+	if unit <= 0 {
+		return size
+	}
+	return (size + unit - 1) / unit * unit
+}
+
+// parsePostgresMemory parses the value of a PostgreSQL memory parameter,
+// returning the number of bytes. The unit is used when the value is a
+// plain number
+func parsePostgresMemory(value string, unit int64) (int64, error) {
+	value = strings.TrimSpace(value)
+	multipliers := []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"kB", 1024},
+		{"MB", 1024 * 1024},
+		{"GB", 1024 * 1024 * 1024},
+		{"TB", 1024 * 1024 * 1024 * 1024},
+		{"B", 1},
+	}
+
+	multiplier := unit
+	for _, entry := range multipliers {
+		if strings.HasSuffix(value, entry.suffix) {
+			value = strings.TrimSpace(strings.TrimSuffix(value, entry.suffix))
+			multiplier = entry.multiplier
+			break
+		}
+	}
+
+	number, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return number * multiplier, nil
+}
+
+// validateSharedPreloadLibraries validates the additional shared preload libraries
+func (r *Cluster) validateSharedPreloadLibraries() field.ErrorList {
+	var result field.ErrorList
+
+	librariesPath := field.NewPath("spec", "postgresql", "shared_preload_libraries")
+	seen := make(map[string]bool, len(r.Spec.PostgresConfiguration.AdditionalLibraries))
+	for idx, library := range r.Spec.PostgresConfiguration.AdditionalLibraries {
+		switch {
+		case strings.TrimSpace(library) == "":
+			result = append(result, field.Invalid(
+				librariesPath.Index(idx), library, "the library name cannot be empty"))
+		case strings.ContainsAny(library, ",'\"\\ "):
+			result = append(result, field.Invalid(
+				librariesPath.Index(idx), library,
+				"the library name cannot contain commas, quotes, backslashes or spaces"))
+		case seen[library]:
+			result = append(result, field.Duplicate(librariesPath.Index(idx), library))
+		}
+		seen[library] = true
+	}
+
+	return result
+}
+
+// validateReadinessProbe validates the configuration of the readiness probe
+func (r *Cluster) validateReadinessProbe() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Probes == nil || r.Spec.Probes.Readiness == nil || r.Spec.Probes.Readiness.MaximumLag == nil {
+		return result
+	}
+
+	maximumLagPath := field.NewPath("spec", "probes", "readiness", "maximumLag")
+	maximumLag := r.Spec.Probes.Readiness.MaximumLag
+	if r.GetReadinessProbeType() != ReadinessProbeTypeStreaming {
+		result = append(result, field.Invalid(
+			maximumLagPath,
+			maximumLag.String(),
+			"maximumLag is only available with the streaming readiness probe type"))
+	}
+
+	if maximumLag.Sign() <= 0 {
+		result = append(result, field.Invalid(
+			maximumLagPath,
+			maximumLag.String(),
+			"maximumLag must be positive"))
+	}
+
+	return result
+}
+
+// validateInstanceHealth validates the criteria
+// used to consider an instance degraded
+func (r *Cluster) validateInstanceHealth() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.InstanceHealth == nil || r.Spec.InstanceHealth.MaximumLag == nil {
+		return result
+	}
+
+	if maximumLag := r.Spec.InstanceHealth.MaximumLag; maximumLag.Sign() <= 0 {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "instanceHealth", "maximumLag"),
+			maximumLag.String(),
+			"maximumLag must be positive"))
+	}
+
+	return result
+}
+
+// validatePrimaryHeartbeat checks that the heartbeat timeout of the
+// primary leaves the instance manager the time to renew the lease
+func (r *Cluster) validatePrimaryHeartbeat() field.ErrorList {
+	var result field.ErrorList
+
+	if timeout := r.Spec.PrimaryHeartbeatTimeout; timeout != 0 && timeout < minimumPrimaryHeartbeatTimeout {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "primaryHeartbeatTimeout"),
+			timeout,
+			fmt.Sprintf("primaryHeartbeatTimeout must be zero or at least %v seconds",
+				minimumPrimaryHeartbeatTimeout)))
+	}
+
+	return result
+}
+
+// validateChaosExperiment checks the requested chaos experiment, which
+// is only allowed when chaos testing is enabled
+func (r *Cluster) validateChaosExperiment() field.ErrorList {
+	path := field.NewPath("metadata", "annotations", utils.ChaosExperimentAnnotationName)
+
+	experiment, err := utils.GetChaosExperiment(r.Annotations)
+	if err != nil {
+		return field.ErrorList{field.Invalid(path, r.Annotations[utils.ChaosExperimentAnnotationName], err.Error())}
+	}
+	if experiment == nil {
+		return nil
 	}
 
-	for _, validate := range validations {
-		allErrs = append(allErrs, validate()...)
+	if !r.Spec.EnableChaosTesting {
+		return field.ErrorList{field.Invalid(
+			path,
+			r.Annotations[utils.ChaosExperimentAnnotationName],
+			"chaos experiments require enableChaosTesting to be set")}
 	}
 
-	return allErrs
+	if err := experiment.Validate(); err != nil {
+		return field.ErrorList{field.Invalid(
+			path,
+			r.Annotations[utils.ChaosExperimentAnnotationName],
+			fmt.Sprintf("invalid chaos experiment: %s", err.Error()))}
+	}
+
+	return nil
 }
 
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
@@ -331,12 +1191,20 @@ func (r *Cluster) ValidateChanges(old *Cluster) (allErrs field.ErrorList) {
 			"old", old)
 		return nil
 	}
-	allErrs = append(allErrs, r.validateImageChange(old.Spec.ImageName)...)
+	if r.Spec.ImageCatalogRef != nil || old.Spec.ImageCatalogRef != nil {
+		allErrs = append(allErrs, r.validateImageCatalogChange(old)...)
+	} else {
+		allErrs = append(allErrs, r.validateImageChange(old.Spec.ImageName)...)
+	}
 	allErrs = append(allErrs, r.validateConfigurationChange(old)...)
+	allErrs = append(allErrs, r.validateBootstrapChange(old)...)
 	allErrs = append(allErrs, r.validateStorageChange(old)...)
 	allErrs = append(allErrs, r.validateWalStorageChange(old)...)
 	allErrs = append(allErrs, r.validateReplicaModeChange(old)...)
 	allErrs = append(allErrs, r.validateUnixPermissionIdentifierChange(old)...)
+	allErrs = append(allErrs, r.validateFlavorChange(old)...)
+	allErrs = append(allErrs, r.validateTDEChange(old)...)
+	allErrs = append(allErrs, r.validateSecretNameTemplateChange(old)...)
 	return allErrs
 }
 
@@ -404,30 +1272,162 @@ func (r *Cluster) validateInitDB() field.ErrorList {
 				"WAL segment size must be a power of 2"))
 	}
 
-	if initDBOptions.PostInitApplicationSQLRefs != nil {
-		for _, item := range initDBOptions.PostInitApplicationSQLRefs.SecretRefs {
-			if item.Name == "" || item.Key == "" {
-				result = append(
-					result,
-					field.Invalid(
-						field.NewPath("spec", "bootstrap", "initdb", "postInitApplicationSQLRefs", "secretRefs"),
-						item,
-						"key and name must be specified"))
-			}
+	result = append(result, r.validateInitDBLocaleProvider()...)
+
+	result = append(result, validateSQLRefs(
+		field.NewPath("spec", "bootstrap", "initdb", "postInitApplicationSQLRefs"),
+		initDBOptions.PostInitApplicationSQLRefs)...)
+	result = append(result, validateSQLRefs(
+		field.NewPath("spec", "bootstrap", "initdb", "postInitSQLRefs"),
+		initDBOptions.PostInitSQLRefs)...)
+	result = append(result, validateSQLRefs(
+		field.NewPath("spec", "bootstrap", "initdb", "postInitTemplateSQLRefs"),
+		initDBOptions.PostInitTemplateSQLRefs)...)
+
+	return result
+}
+
+// validateSQLRefs checks that every Secret and ConfigMap reference
+// containing SQL files has both a name and a key
+func validateSQLRefs(path *field.Path, refs *SQLRefs) field.ErrorList {
+	var result field.ErrorList
+
+	if refs == nil {
+		return result
+	}
+
+	for _, item := range refs.SecretRefs {
+		if item.Name == "" || item.Key == "" {
+			result = append(
+				result,
+				field.Invalid(
+					path.Child("secretRefs"),
+					item,
+					"key and name must be specified"))
 		}
+	}
 
-		for _, item := range initDBOptions.PostInitApplicationSQLRefs.ConfigMapRefs {
-			if item.Name == "" || item.Key == "" {
-				result = append(
-					result,
-					field.Invalid(
-						field.NewPath("spec", "bootstrap", "initdb", "postInitApplicationSQLRefs", "configMapRefs"),
-						item,
-						"key and name must be specified"))
-			}
+	for _, item := range refs.ConfigMapRefs {
+		if item.Name == "" || item.Key == "" {
+			result = append(
+				result,
+				field.Invalid(
+					path.Child("configMapRefs"),
+					item,
+					"key and name must be specified"))
+		}
+	}
+
+	return result
+}
+
+// validateCloneConfigurations validates the options of pg_basebackup
+// used by the pg_basebackup bootstrap and to clone the replicas
+func (r *Cluster) validateCloneConfigurations() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Bootstrap != nil && r.Spec.Bootstrap.PgBaseBackup != nil {
+		pgBaseBackup := r.Spec.Bootstrap.PgBaseBackup
+		path := field.NewPath("spec", "bootstrap", "pg_basebackup")
+		result = append(result, r.validateCloneConfiguration(pgBaseBackup.Clone, path.Child("clone"))...)
+	}
+	result = append(result, r.validateCloneConfiguration(
+		r.Spec.ReplicaClone,
+		field.NewPath("spec", "replicaClone"))...)
+
+	return result
+}
+
+// validateCloneConfiguration checks that the compression level is supported
+// by the compression method, and that the options are supported by the
+// PostgreSQL version used by the cluster
+func (r *Cluster) validateCloneConfiguration(
+	configuration *CloneConfiguration,
+	path *field.Path,
+) field.ErrorList {
+	var result field.ErrorList
+
+	if configuration == nil {
+		return result
+	}
+
+	if configuration.CompressionLevel != 0 {
+		switch {
+		case configuration.Compression == "":
+			result = append(result, field.Invalid(
+				path.Child("compressionLevel"),
+				configuration.CompressionLevel,
+				"the compression level requires a compression method"))
+		case configuration.CompressionLevel > configuration.Compression.GetMaxCompressionLevel():
+			result = append(result, field.Invalid(
+				path.Child("compressionLevel"),
+				configuration.CompressionLevel,
+				fmt.Sprintf("the maximum compression level of %s is %d",
+					configuration.Compression, configuration.Compression.GetMaxCompressionLevel())))
 		}
 	}
 
+	// The validation error for a wrong image name will be already
+	// raised by the validateImageName function
+	psqlVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		return result
+	}
+
+	if configuration.Compression != "" && psqlVersion < 150000 {
+		result = append(result, field.Invalid(
+			path.Child("compression"),
+			configuration.Compression,
+			"the server-side compression requires PostgreSQL 15 or newer"))
+	}
+
+	if configuration.VerifyBackup && psqlVersion < 130000 {
+		result = append(result, field.Invalid(
+			path.Child("verifyBackup"),
+			configuration.VerifyBackup,
+			"the verification of the backup manifest requires PostgreSQL 13 or newer"))
+	}
+
+	return result
+}
+
+// validateInitDBLocaleProvider validates the ICU related options of initdb
+func (r *Cluster) validateInitDBLocaleProvider() field.ErrorList {
+	var result field.ErrorList
+
+	initDBOptions := r.Spec.Bootstrap.InitDB
+	if initDBOptions.LocaleProvider == "" && initDBOptions.IcuLocale == "" {
+		return result
+	}
+
+	if psqlVersion, err := r.GetPostgresqlVersion(); err == nil && psqlVersion < 150000 {
+		// The validation error for a wrong image name will be already
+		// raised by the validateImageName function
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "localeProvider"),
+				initDBOptions.LocaleProvider,
+				"the locale provider can be chosen only from PostgreSQL 15"))
+	}
+
+	if initDBOptions.LocaleProvider == "icu" && initDBOptions.IcuLocale == "" {
+		result = append(
+			result,
+			field.Required(
+				field.NewPath("spec", "bootstrap", "initdb", "icuLocale"),
+				"the ICU locale is required when using the icu locale provider"))
+	}
+
+	if initDBOptions.LocaleProvider != "icu" && initDBOptions.IcuLocale != "" {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "icuLocale"),
+				initDBOptions.IcuLocale,
+				"the ICU locale can be set only when using the icu locale provider"))
+	}
+
 	return result
 }
 
@@ -692,6 +1692,9 @@ func (r *Cluster) validateBootstrapMethod() field.ErrorList {
 	if r.Spec.Bootstrap.PgBaseBackup != nil {
 		bootstrapMethods++
 	}
+	if r.Spec.Bootstrap.Adopt != nil {
+		bootstrapMethods++
+	}
 
 	if bootstrapMethods > 1 {
 		result = append(
@@ -752,6 +1755,249 @@ func (r *Cluster) validateBootstrapRecoverySource() field.ErrorList {
 	return result
 }
 
+// validateRecoveryVolumeSnapshots validates the volume snapshots
+// used to bootstrap a cluster
+func (r *Cluster) validateRecoveryVolumeSnapshots() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.Recovery == nil ||
+		r.Spec.Bootstrap.Recovery.VolumeSnapshots == nil {
+		return result
+	}
+
+	recovery := r.Spec.Bootstrap.Recovery
+	path := field.NewPath("spec", "bootstrap", "recovery", "volumeSnapshots")
+
+	if recovery.Backup != nil {
+		result = append(result, field.Invalid(
+			path,
+			recovery.VolumeSnapshots,
+			"volumeSnapshots and backup are mutually exclusive"))
+	}
+
+	result = append(result, validateVolumeSnapshotReference(&recovery.VolumeSnapshots.Storage, path.Child("storage"))...)
+
+	if recovery.VolumeSnapshots.WalStorage != nil {
+		result = append(result,
+			validateVolumeSnapshotReference(recovery.VolumeSnapshots.WalStorage, path.Child("walStorage"))...)
+		if r.Spec.WalStorage == nil {
+			result = append(result, field.Invalid(
+				path.Child("walStorage"),
+				recovery.VolumeSnapshots.WalStorage.Name,
+				"the cluster must have a WAL storage to recover it from a volume snapshot"))
+		}
+	} else if r.Spec.WalStorage != nil {
+		// The storage of an instance with a WAL storage only contains
+		// a symbolic link to it, which would be dangling
+		result = append(result, field.Required(
+			path.Child("walStorage"),
+			"the cluster has a WAL storage: the volume snapshot of the WAL storage is required, "+
+				"as the pg_wal directory of the storage snapshot is a symbolic link to it"))
+	}
+
+	if r.IsReplica() {
+		// The designated primary follows the source of the replica cluster
+		// and never promotes, so there's no target to recover to
+		if recovery.RecoveryTarget != nil {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "bootstrap", "recovery", "recoveryTarget"),
+				recovery.RecoveryTarget,
+				"a replica cluster recovering from volume snapshots follows its source and can't have a recovery target"))
+		}
+		return result
+	}
+
+	if recovery.Source == "" {
+		if recovery.RecoveryTarget != nil {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "bootstrap", "recovery", "recoveryTarget"),
+				recovery.RecoveryTarget,
+				"recovering from volume snapshots to a target requires a source with a WAL archive"))
+		}
+		return result
+	}
+
+	if server, found := r.ExternalCluster(recovery.Source); found && server.BarmanObjectStore == nil {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "bootstrap", "recovery", "source"),
+			recovery.Source,
+			"the source of a recovery from volume snapshots must have a barmanObjectStore"))
+	}
+
+	return result
+}
+
+// validateRecoveryRemapping validates the replacements of the references
+// to the source cluster applied during the recovery
+func (r *Cluster) validateRecoveryRemapping() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.Recovery == nil ||
+		r.Spec.Bootstrap.Recovery.Remap == nil {
+		return result
+	}
+
+	recovery := r.Spec.Bootstrap.Recovery
+	remap := recovery.Remap
+	path := field.NewPath("spec", "bootstrap", "recovery", "remap")
+
+	if recovery.Backup == nil &&
+		(len(remap.Secrets) > 0 || remap.DestinationPath != "" || remap.ServerName != "") {
+		result = append(result, field.Invalid(
+			path,
+			remap,
+			"the object store references can only be remapped when recovering from a backup object"))
+	}
+
+	for source, target := range remap.Secrets {
+		if source == "" || target == "" {
+			result = append(result, field.Invalid(
+				path.Child("secrets"),
+				remap.Secrets,
+				"the names of the secrets cannot be empty"))
+			break
+		}
+	}
+
+	sources := make([]string, 0, len(remap.Roles))
+	for source := range remap.Roles {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	superuser := r.GetSuperuserName()
+	renamedFrom := make(map[string]string, len(remap.Roles))
+	for _, source := range sources {
+		target := remap.Roles[source]
+		switch {
+		case source == "" || target == "":
+			result = append(result, field.Invalid(
+				path.Child("roles"),
+				remap.Roles,
+				"the names of the roles cannot be empty"))
+		case source == superuser || source == StreamingReplicationUser ||
+			target == superuser || target == StreamingReplicationUser:
+			result = append(result, field.Invalid(
+				path.Child("roles", source),
+				target,
+				"the roles managed by the operator cannot be renamed"))
+		case remap.Roles[target] != "":
+			// The roles are renamed one at a time: renaming a role to the
+			// name of another renamed role would fail halfway
+			result = append(result, field.Invalid(
+				path.Child("roles", source),
+				target,
+				fmt.Sprintf("the role %s is renamed as well: chained and swapped renames are not supported",
+					target)))
+		case renamedFrom[target] != "":
+			result = append(result, field.Invalid(
+				path.Child("roles", source),
+				target,
+				fmt.Sprintf("the role %s is renamed to %s as well", renamedFrom[target], target)))
+		default:
+			renamedFrom[target] = source
+		}
+	}
+
+	return result
+}
+
+// validateReplicaCreationMethod checks that the replicas can be
+// created from a backup only when the backups are stored in an
+// object store
+func (r *Cluster) validateReplicaCreationMethod() field.ErrorList {
+	if r.Spec.ReplicaCreationMethod != ReplicaCreationMethodBackup {
+		return nil
+	}
+
+	if r.Spec.Backup == nil || r.Spec.Backup.BarmanObjectStore == nil {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec", "replicaCreationMethod"),
+			r.Spec.ReplicaCreationMethod,
+			"creating the replicas from a backup requires barmanObjectStore to be configured")}
+	}
+
+	return nil
+}
+
+// validateReplicationConnection checks that the settings of the
+// streaming replication connections are supported by PostgreSQL
+func (r *Cluster) validateReplicationConnection() field.ErrorList {
+	if r.Spec.ReplicationConnection == nil || r.Spec.ReplicationConnection.ChannelBinding == "" {
+		return nil
+	}
+
+	// The validation error for a wrong image name will be already
+	// raised by the validateImageName function
+	if psqlVersion, err := r.GetPostgresqlVersion(); err == nil && psqlVersion < 130000 {
+		return field.ErrorList{field.Invalid(
+			field.NewPath("spec", "replicationConnection", "channelBinding"),
+			r.Spec.ReplicationConnection.ChannelBinding,
+			"channel_binding can be set only from PostgreSQL 13")}
+	}
+
+	return nil
+}
+
+// validateVolumeSnapshotReference checks that a data source refers
+// to a VolumeSnapshot
+func validateVolumeSnapshotReference(
+	reference *v1.TypedLocalObjectReference,
+	path *field.Path,
+) field.ErrorList {
+	var result field.ErrorList
+
+	if reference.Name == "" {
+		result = append(result, field.Required(path.Child("name"), "the name of the volume snapshot is required"))
+	}
+
+	if reference.Kind != VolumeSnapshotKind ||
+		reference.APIGroup == nil || *reference.APIGroup != VolumeSnapshotAPIGroup {
+		result = append(result, field.Invalid(
+			path.Child("kind"),
+			reference.Kind,
+			fmt.Sprintf("only %s resources of the %s API group are supported",
+				VolumeSnapshotKind, VolumeSnapshotAPIGroup)))
+	}
+
+	return result
+}
+
+// validateBootstrapImportSource is used to ensure that the source
+// server of a logical import is correctly defined
+func (r *Cluster) validateBootstrapImportSource() field.ErrorList {
+	var result field.ErrorList
+
+	// This validation is only applicable for the import based bootstrap
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.InitDB == nil || r.Spec.Bootstrap.InitDB.Import == nil {
+		return result
+	}
+
+	source := r.Spec.Bootstrap.InitDB.Import.Source.ExternalCluster
+	externalCluster, found := r.ExternalCluster(source)
+	if !found {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "import", "source", "externalCluster"),
+				source,
+				fmt.Sprintf("External cluster %v not found", source)))
+		return result
+	}
+
+	if len(externalCluster.ConnectionParameters) == 0 {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "import", "source", "externalCluster"),
+				source,
+				fmt.Sprintf("External cluster %v has no connection parameters, "+
+					"which are required to run a logical import", source)))
+	}
+
+	return result
+}
+
 // validateImageName validates the image name ensuring we aren't
 // using the "latest" tag
 func (r *Cluster) validateImageName() field.ErrorList {
@@ -822,23 +2068,24 @@ func (r *Cluster) validateConfiguration() field.ErrorList {
 		return result
 	}
 	info := postgres.ConfigurationInfo{
-		Settings:         postgres.CnpgConfigurationSettings,
-		MajorVersion:     psqlVersion,
-		UserSettings:     r.Spec.PostgresConfiguration.Parameters,
-		IsReplicaCluster: r.IsReplica(),
+		Settings:           postgres.CnpgConfigurationSettings,
+		MajorVersion:       psqlVersion,
+		UserSettings:       r.GetPostgresParameters(),
+		IsReplicaCluster:   r.IsReplica(),
+		Flavor:             r.GetPostgresFlavor(),
+		ClusterName:        r.Name,
+		IncludingMandatory: true,
 	}
-	sanitizedParameters := postgres.CreatePostgresqlConfiguration(info).GetConfigurationParameters()
+	enforcedParameters := postgres.CreatePostgresqlConfiguration(info).GetConfigurationParameters()
 
 	for key, value := range r.Spec.PostgresConfiguration.Parameters {
-		_, isFixed := postgres.FixedConfigurationParameters[key]
-		sanitizedValue, presentInSanitizedConfiguration := sanitizedParameters[key]
-		if isFixed && (!presentInSanitizedConfiguration || value != sanitizedValue) {
-			result = append(
-				result,
-				field.Invalid(
-					field.NewPath("spec", "postgresql", "parameters", key),
-					value,
-					"Can't set fixed configuration parameter"))
+		fixedParameter, isFixed := postgres.FixedConfigurationParameters[key]
+		if !isFixed {
+			continue
+		}
+
+		if err := validateFixedParameter(key, value, fixedParameter, enforcedParameters); err != nil {
+			result = append(result, err)
 		}
 	}
 
@@ -848,17 +2095,85 @@ func (r *Cluster) validateConfiguration() field.ErrorList {
 		result = append(result, err)
 	}
 
+	result = append(result, r.validateConfigurationFiles()...)
+
+	return result
+}
+
+// validateConfigurationFiles checks the references to the ConfigMaps
+// containing the additional configuration files
+func (r *Cluster) validateConfigurationFiles() field.ErrorList {
+	var result field.ErrorList
+
+	references := make(map[ConfigMapKeySelector]bool, len(r.Spec.PostgresConfiguration.ConfigurationFiles))
+	for idx, reference := range r.Spec.PostgresConfiguration.ConfigurationFiles {
+		path := field.NewPath("spec", "postgresql", "configurationFiles").Index(idx)
+		switch {
+		case reference.Name == "":
+			result = append(result, field.Required(path.Child("name"),
+				"the name of the ConfigMap is required"))
+		case reference.Key == "":
+			result = append(result, field.Required(path.Child("key"),
+				"the key of the ConfigMap is required"))
+		case references[reference]:
+			result = append(result, field.Duplicate(path, reference))
+		}
+		references[reference] = true
+	}
+
 	return result
 }
 
+// validateFixedParameter checks the value the user assigned to a parameter
+// which is not under the user control. Blocked parameters can't be set at
+// all, except for the values stored in the spec by the defaulting webhook,
+// while managed parameters are accepted only when their value is the
+// same as the one enforced by the operator
+func validateFixedParameter(
+	key, value string,
+	fixedParameter postgres.FixedParameter,
+	enforcedParameters map[string]string,
+) *field.Error {
+	path := field.NewPath("spec", "postgresql", "parameters", key)
+
+	if fixedParameter.IsBlocked() {
+		if defaultValue, isDefaulted := postgres.CnpgConfigurationSettings.GlobalDefaultSettings[key]; isDefaulted &&
+			value == defaultValue {
+			return nil
+		}
+		return field.Invalid(
+			path,
+			value,
+			fmt.Sprintf("Can't set blocked configuration parameter: %s", fixedParameter.Reason))
+	}
+
+	enforcedValue, hasEnforcedValue := enforcedParameters[key]
+	if !hasEnforcedValue {
+		return field.Invalid(
+			path,
+			value,
+			fmt.Sprintf("Can't set managed configuration parameter: %s", fixedParameter.Reason))
+	}
+
+	if value != enforcedValue {
+		return field.Invalid(
+			path,
+			value,
+			fmt.Sprintf("Can't set managed configuration parameter to a value different from %q: %s",
+				enforcedValue, fixedParameter.Reason))
+	}
+
+	return nil
+}
+
 // validateConfigurationChange determines whether a PostgreSQL configuration
 // change can be applied
 func (r *Cluster) validateConfigurationChange(old *Cluster) field.ErrorList {
 	var result field.ErrorList
 
 	if old.Spec.ImageName != r.Spec.ImageName {
-		diff := utils.CollectDifferencesFromMaps(old.Spec.PostgresConfiguration.Parameters,
-			r.Spec.PostgresConfiguration.Parameters)
+		diff := utils.CollectDifferencesFromMaps(old.GetPostgresParameters(),
+			r.GetPostgresParameters())
 		if len(diff) > 0 {
 			jsonDiff, _ := json.Marshal(diff)
 			result = append(
@@ -875,23 +2190,115 @@ func (r *Cluster) validateConfigurationChange(old *Cluster) field.ErrorList {
 	return result
 }
 
-func validateSyncReplicaElectionConstraint(constraints SyncReplicaElectionConstraints) *field.Error {
-	if !constraints.Enabled {
-		return nil
+func validateSyncReplicaElectionConstraint(constraints SyncReplicaElectionConstraints) *field.Error {
+	if !constraints.Enabled {
+		return nil
+	}
+	if len(constraints.GetNodeLabelsAntiAffinity()) > 0 {
+		return nil
+	}
+
+	return field.Invalid(
+		field.NewPath(
+			"spec", "postgresql", "syncReplicaElectionConstraint", "nodeLabelsAntiAffinity",
+		),
+		nil,
+		"Can't enable syncReplicaConstraints without passing labels for comparison inside nodeLabelsAntiAffinity "+
+			"or enabling zoneAntiAffinity",
+	)
+}
+
+// validateImageCatalogRef validates the reference to the image catalog,
+// which cannot be used together with the image name
+func (r *Cluster) validateImageCatalogRef() field.ErrorList {
+	var result field.ErrorList
+
+	catalogRef := r.Spec.ImageCatalogRef
+	if catalogRef == nil {
+		return result
+	}
+
+	catalogPath := field.NewPath("spec", "imageCatalogRef")
+	if r.Spec.ImageName != "" {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "imageName"),
+			r.Spec.ImageName,
+			"imageName and imageCatalogRef cannot be specified together"))
+	}
+
+	if catalogRef.APIGroup != nil && *catalogRef.APIGroup != GroupVersion.Group {
+		result = append(result, field.Invalid(
+			catalogPath.Child("apiGroup"),
+			*catalogRef.APIGroup,
+			fmt.Sprintf("the only supported API group is %s", GroupVersion.Group)))
+	}
+
+	switch catalogRef.Kind {
+	case ImageCatalogKind, ClusterImageCatalogKind:
+	default:
+		result = append(result, field.Invalid(
+			catalogPath.Child("kind"),
+			catalogRef.Kind,
+			fmt.Sprintf("the kind must be either %s or %s", ImageCatalogKind, ClusterImageCatalogKind)))
+	}
+
+	if catalogRef.Name == "" {
+		result = append(result, field.Required(catalogPath.Child("name"), "the name of the catalog is required"))
+	}
+
+	return result
+}
+
+// validateImageCatalogChange validates the changes of a cluster using an
+// image catalog, which cannot change the major version of PostgreSQL
+func (r *Cluster) validateImageCatalogChange(old *Cluster) field.ErrorList {
+	var result field.ErrorList
+
+	getMajor := func(cluster *Cluster) (int, error) {
+		if cluster.Spec.ImageCatalogRef != nil {
+			return cluster.Spec.ImageCatalogRef.Major, nil
+		}
+		version, err := cluster.GetPostgresqlVersion()
+		if err != nil {
+			return 0, err
+		}
+		return version / 10000, nil
 	}
-	if len(constraints.NodeLabelsAntiAffinity) > 0 {
-		return nil
+
+	oldMajor, err := getMajor(old)
+	if err != nil {
+		// We cannot detect the previous major version
+		return result
+	}
+	newMajor, err := getMajor(r)
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateImageName function
+		return result
 	}
 
-	return field.Invalid(
-		field.NewPath(
-			"spec", "postgresql", "syncReplicaElectionConstraint", "nodeLabelsAntiAffinity",
-		),
-		nil,
-		"Can't enable syncReplicaConstraints without passing labels for comparison inside nodeLabelsAntiAffinity",
-	)
+	if oldMajor != newMajor {
+		fieldPath := field.NewPath("spec", "imageCatalogRef", "major")
+		var value interface{} = newMajor
+		if r.Spec.ImageCatalogRef == nil {
+			fieldPath = field.NewPath("spec", "imageName")
+			value = r.Spec.ImageName
+		}
+		result = append(result, field.Invalid(
+			fieldPath,
+			value,
+			fmt.Sprintf("can't upgrade between majors %d and %d: %s", oldMajor, newMajor, majorUpgradeHint)))
+	}
+
+	return result
 }
 
+// majorUpgradeHint explains how to move a cluster to a new major
+// version of PostgreSQL
+const majorUpgradeHint = "the major version of PostgreSQL can't be changed in place, " +
+	"create a new cluster with the new major version and import the databases " +
+	"with the import section of the initdb bootstrap"
+
 // validateImageChange validate the change from a certain image name
 // to a new one.
 func (r *Cluster) validateImageChange(old string) field.ErrorList {
@@ -900,11 +2307,11 @@ func (r *Cluster) validateImageChange(old string) field.ErrorList {
 	newVersion := r.Spec.ImageName
 	if newVersion == "" {
 		// We'll use the default one
-		newVersion = configuration.Current.PostgresImageName
+		newVersion = configuration.Current().PostgresImageName
 	}
 
 	if old == "" {
-		old = configuration.Current.PostgresImageName
+		old = configuration.Current().PostgresImageName
 	}
 
 	status, err := postgres.CanUpgrade(old, newVersion)
@@ -921,8 +2328,8 @@ func (r *Cluster) validateImageChange(old string) field.ErrorList {
 			field.Invalid(
 				field.NewPath("spec", "imageName"),
 				r.Spec.ImageName,
-				fmt.Sprintf("can't upgrade between %v and %v",
-					old, newVersion)))
+				fmt.Sprintf("can't upgrade between %v and %v: %s",
+					old, newVersion, majorUpgradeHint)))
 	}
 
 	return result
@@ -1099,6 +2506,23 @@ func (r *Cluster) validateWalStorageSize() field.ErrorList {
 	return result
 }
 
+// validatePVCRetentionPolicy checks that the PVC retention policy
+// is only specified in the storage section, as it applies to all the
+// PVCs of an instance
+func (r *Cluster) validatePVCRetentionPolicy() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.WalStorage != nil && r.Spec.WalStorage.PersistentVolumeClaimRetentionPolicy != nil {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "walStorage", "persistentVolumeClaimRetentionPolicy"),
+			r.Spec.WalStorage.PersistentVolumeClaimRetentionPolicy,
+			"the PVC retention policy can only be set in the storage section, "+
+				"and it applies to the WAL PVCs too"))
+	}
+
+	return result
+}
+
 func validateStorageConfigurationSize(structPath string, storageConfiguration StorageConfiguration) field.ErrorList {
 	var result field.ErrorList
 
@@ -1116,6 +2540,8 @@ func validateStorageConfigurationSize(structPath string, storageConfiguration St
 func (r *Cluster) validateStorageChange(old *Cluster) field.ErrorList {
 	var result field.ErrorList
 
+	result = append(result, r.validateStorageClassChange(old)...)
+
 	return append(
 		result,
 		validateStorageConfigurationChange(
@@ -1126,6 +2552,43 @@ func (r *Cluster) validateStorageChange(old *Cluster) field.ErrorList {
 	)
 }
 
+// validateStorageClassChange checks that the storage class is changed only
+// while the volumes are being recreated, as the existing PVCs can't be
+// moved to a different storage class. The new storage class is used by
+// the PVCs created after the change
+func (r *Cluster) validateStorageClassChange(old *Cluster) field.ErrorList {
+	var result field.ErrorList
+
+	oldStorageClass := getStorageClassName(old.Spec.StorageConfiguration)
+	newStorageClass := getStorageClassName(r.Spec.StorageConfiguration)
+	if oldStorageClass == newStorageClass || !r.ShouldResizeInUseVolumes() {
+		return result
+	}
+
+	return append(result, field.Invalid(
+		field.NewPath("spec", "storage", "storageClass"),
+		newStorageClass,
+		fmt.Sprintf("the storage class of the existing volumes can't be changed from %q to %q: "+
+			"set spec.storage.resizeInUseVolumes to false and recreate the instances one at a time "+
+			"to move them to the new storage class", oldStorageClass, newStorageClass)))
+}
+
+// getStorageClassName gets the name of the storage class used by the
+// PVCs generated from the passed storage configuration, or an empty
+// string when the default storage class is used
+func getStorageClassName(configuration StorageConfiguration) string {
+	if configuration.StorageClass != nil {
+		return *configuration.StorageClass
+	}
+
+	if configuration.PersistentVolumeClaimTemplate != nil &&
+		configuration.PersistentVolumeClaimTemplate.StorageClassName != nil {
+		return *configuration.PersistentVolumeClaimTemplate.StorageClassName
+	}
+
+	return ""
+}
+
 func (r *Cluster) validateWalStorageChange(old *Cluster) field.ErrorList {
 	if old.Spec.WalStorage == nil && r.Spec.WalStorage == nil {
 		return nil
@@ -1256,12 +2719,22 @@ func (r *Cluster) validateExternalClusters() field.ErrorList {
 func (r *Cluster) validateExternalCluster(externalCluster *ExternalCluster, path *field.Path) field.ErrorList {
 	var result field.ErrorList
 
-	if externalCluster.ConnectionParameters == nil && externalCluster.BarmanObjectStore == nil {
+	if externalCluster.ConnectionParameters == nil &&
+		externalCluster.BarmanObjectStore == nil &&
+		externalCluster.PluginConfiguration == nil {
 		result = append(result,
 			field.Invalid(
 				path,
 				externalCluster,
-				"one of connectionParameters and barmanObjectStore is required"))
+				"one of connectionParameters, barmanObjectStore and pluginConfiguration is required"))
+	}
+
+	if externalCluster.BarmanObjectStore != nil && externalCluster.PluginConfiguration != nil {
+		result = append(result,
+			field.Invalid(
+				path.Child("pluginConfiguration"),
+				externalCluster.PluginConfiguration.Name,
+				"barmanObjectStore and pluginConfiguration are mutually exclusive"))
 	}
 
 	return result
@@ -1286,6 +2759,42 @@ func (r *Cluster) validateReplicaModeChange(old *Cluster) field.ErrorList {
 	return result
 }
 
+// validateBootstrapChange checks that the bootstrap configuration doesn't
+// change after the cluster has been created, as it is used only once and
+// any change would be silently ignored. The bootstrap section can still be
+// removed, to allow dropping the external clusters not needed anymore
+func (r *Cluster) validateBootstrapChange(old *Cluster) field.ErrorList {
+	var result field.ErrorList
+
+	if old.Spec.Bootstrap == nil || reflect.DeepEqual(old.Spec.Bootstrap, r.Spec.Bootstrap) {
+		return result
+	}
+
+	// An empty bootstrap section is defaulted to the initdb method,
+	// so we consider it as removed
+	removedBootstrap := Cluster{Spec: ClusterSpec{Bootstrap: &BootstrapConfiguration{}}}
+	removedBootstrap.defaultInitDB()
+	if r.Spec.Bootstrap == nil || reflect.DeepEqual(r.Spec.Bootstrap, removedBootstrap.Spec.Bootstrap) {
+		return result
+	}
+
+	if old.Spec.Bootstrap.InitDB != nil && r.Spec.Bootstrap.InitDB != nil &&
+		old.Spec.Bootstrap.InitDB.WalSegmentSize != r.Spec.Bootstrap.InitDB.WalSegmentSize {
+		return append(result, field.Invalid(
+			field.NewPath("spec", "bootstrap", "initdb", "walSegmentSize"),
+			r.Spec.Bootstrap.InitDB.WalSegmentSize,
+			"the WAL segment size is set by initdb when the cluster is created and can't be changed: "+
+				"create a new cluster with the new WAL segment size and import the data into it"))
+	}
+
+	return append(result, field.Invalid(
+		field.NewPath("spec", "bootstrap"),
+		r.Spec.Bootstrap,
+		"the bootstrap configuration is used only when the cluster is created and can't be changed: "+
+			"restore the previous configuration, or remove the bootstrap section, "+
+			"and create a new cluster to bootstrap from a different source"))
+}
+
 func (r *Cluster) validateUnixPermissionIdentifierChange(old *Cluster) field.ErrorList {
 	var result field.ErrorList
 
@@ -1306,6 +2815,21 @@ func (r *Cluster) validateUnixPermissionIdentifierChange(old *Cluster) field.Err
 	return result
 }
 
+// validateFlavorChange checks that the flavor of PostgreSQL doesn't
+// change, as the superuser of the instances is created by initdb
+func (r *Cluster) validateFlavorChange(old *Cluster) field.ErrorList {
+	var result field.ErrorList
+
+	if flavor := r.GetPostgresFlavor(); flavor != old.GetPostgresFlavor() {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "postgresql", "flavor"),
+			flavor,
+			"the flavor of PostgreSQL cannot be changed"))
+	}
+
+	return result
+}
+
 // Check if the replica mode is used with an incompatible bootstrap
 // method
 func (r *Cluster) validateReplicaMode() field.ErrorList {
@@ -1339,6 +2863,36 @@ func (r *Cluster) validateReplicaMode() field.ErrorList {
 	return result
 }
 
+// validateExternalDNS validates the publication of the
+// primary through external-dns
+func (r *Cluster) validateExternalDNS() field.ErrorList {
+	var result field.ErrorList
+
+	externalDNS := r.Spec.ExternalDNS
+	if externalDNS == nil {
+		return result
+	}
+
+	path := field.NewPath("spec", "externalDNS")
+	if errs := validationutil.IsDNS1123Subdomain(externalDNS.Hostname); len(errs) > 0 {
+		result = append(result, field.Invalid(
+			path.Child("hostname"),
+			externalDNS.Hostname,
+			fmt.Sprintf("invalid hostname: %s", strings.Join(errs, ", "))))
+	}
+
+	for idx, sourceRange := range externalDNS.LoadBalancerSourceRanges {
+		if _, _, err := net.ParseCIDR(sourceRange); err != nil {
+			result = append(result, field.Invalid(
+				path.Child("loadBalancerSourceRanges").Index(idx),
+				sourceRange,
+				"the source range must be a CIDR"))
+		}
+	}
+
+	return result
+}
+
 // validateTolerations check and validate the tolerations field
 // This code is almost a verbatim copy of
 // https://github.com/kubernetes/kubernetes/blob/4d38d21/pkg/apis/core/validation/validation.go#L3147
@@ -1424,6 +2978,33 @@ func validateTaintEffect(effect *v1.TaintEffect, allowEmpty bool, fldPath *field
 	return allErrors
 }
 
+// validatePriorityClassNames checks that the names of the priority
+// classes are valid object names
+func (r *Cluster) validatePriorityClassNames() field.ErrorList {
+	var result field.ErrorList
+
+	priorityClasses := []struct {
+		name string
+		path *field.Path
+	}{
+		{name: r.Spec.PriorityClassName, path: field.NewPath("spec", "priorityClassName")},
+		{name: r.Spec.PrimaryPriorityClassName, path: field.NewPath("spec", "primaryPriorityClassName")},
+	}
+	for _, priorityClass := range priorityClasses {
+		if priorityClass.name == "" {
+			continue
+		}
+		if errs := validationutil.IsDNS1123Subdomain(priorityClass.name); len(errs) > 0 {
+			result = append(result, field.Invalid(
+				priorityClass.path,
+				priorityClass.name,
+				fmt.Sprintf("invalid priority class name: %s", strings.Join(errs, ", "))))
+		}
+	}
+
+	return result
+}
+
 // validateAntiAffinity checks and validates the anti-affinity fields.
 func (r *Cluster) validateAntiAffinity() field.ErrorList {
 	path := field.NewPath("spec", "affinity", "podAntiAffinityType")
@@ -1442,6 +3023,209 @@ func (r *Cluster) validateAntiAffinity() field.ErrorList {
 	return allErrors
 }
 
+// validateMonitoring validates the TLS and authentication
+// configuration of the metrics endpoint
+func (r *Cluster) validateMonitoring() field.ErrorList {
+	var result field.ErrorList
+
+	monitoring := r.Spec.Monitoring
+	if monitoring == nil {
+		return result
+	}
+
+	if monitoring.TLSConfig != nil && monitoring.TLSConfig.RequireClientCertificate && !monitoring.TLSConfig.Enabled {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "monitoring", "tls", "requireClientCertificate"),
+			monitoring.TLSConfig.RequireClientCertificate,
+			"client certificates can be required only when TLS is enabled"))
+	}
+
+	if monitoring.BearerTokenSecret != nil &&
+		(monitoring.BearerTokenSecret.Name == "" || monitoring.BearerTokenSecret.Key == "") {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "monitoring", "bearerTokenSecret"),
+			monitoring.BearerTokenSecret,
+			"both the name and the key of the secret are required"))
+	}
+
+	if discovery := monitoring.DatabaseDiscovery; discovery != nil {
+		validatePatterns := func(fieldPath *field.Path, patterns []string) {
+			for idx, pattern := range patterns {
+				if _, err := path.Match(pattern, ""); err != nil {
+					result = append(result, field.Invalid(fieldPath.Index(idx), pattern, err.Error()))
+				}
+			}
+		}
+		discoveryPath := field.NewPath("spec", "monitoring", "databaseDiscovery")
+		validatePatterns(discoveryPath.Child("include"), discovery.Include)
+		validatePatterns(discoveryPath.Child("exclude"), discovery.Exclude)
+	}
+
+	return result
+}
+
+// validateDataVerification validates the schedule
+// of the data verification
+func (r *Cluster) validateDataVerification() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.DataVerification == nil {
+		return result
+	}
+
+	if _, err := cron.Parse(r.Spec.DataVerification.Schedule); err != nil {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "dataVerification", "schedule"),
+			r.Spec.DataVerification.Schedule,
+			err.Error()))
+	}
+
+	return result
+}
+
+// validateManagedExtensions checks that every extension
+// is managed only once in each database
+func (r *Cluster) validateManagedExtensions() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Managed == nil {
+		return result
+	}
+
+	extensions := stringset.New()
+	for idx, extension := range r.Spec.Managed.Extensions {
+		key := extension.GetKey()
+		if extensions.Has(key) {
+			result = append(result, field.Duplicate(
+				field.NewPath("spec", "managed", "extensions").Index(idx),
+				key))
+		}
+		extensions.Put(key)
+	}
+
+	return result
+}
+
+// validateManagedRoles checks that every role is managed only once
+func (r *Cluster) validateManagedRoles() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Managed == nil {
+		return result
+	}
+
+	roles := stringset.New()
+	for idx, role := range r.Spec.Managed.Roles {
+		if roles.Has(role.Name) {
+			result = append(result, field.Duplicate(
+				field.NewPath("spec", "managed", "roles").Index(idx).Child("name"),
+				role.Name))
+		}
+		roles.Put(role.Name)
+	}
+
+	return result
+}
+
+// validateExtensionImages checks that the names of the extension
+// images are unique and that the PostgreSQL major version, which
+// the location of the extensions depends on, is known
+func (r *Cluster) validateExtensionImages() field.ErrorList {
+	var result field.ErrorList
+
+	extensionImages := r.Spec.PostgresConfiguration.ExtensionImages
+	if len(extensionImages) == 0 {
+		return result
+	}
+
+	basePath := field.NewPath("spec", "postgresql", "extensionImages")
+	names := stringset.New()
+	for idx, extensionImage := range extensionImages {
+		if names.Has(extensionImage.Name) {
+			result = append(result, field.Duplicate(basePath.Index(idx).Child("name"), extensionImage.Name))
+		}
+		names.Put(extensionImage.Name)
+	}
+
+	if _, err := r.GetPostgresqlVersion(); err != nil {
+		result = append(result, field.Invalid(
+			basePath,
+			r.GetImageName(),
+			"cannot detect the PostgreSQL major version of the image, which is required by the extension images"))
+	}
+
+	return result
+}
+
+// validateTDE checks that the Transparent Data Encryption is used with
+// a PostgreSQL flavor supporting it, and that the data encryption key
+// is wrapped either with a passphrase or with a pair of commands
+func (r *Cluster) validateTDE() field.ErrorList {
+	var result field.ErrorList
+
+	tde := r.Spec.PostgresConfiguration.TDE
+	if !tde.IsEnabled() {
+		return result
+	}
+
+	basePath := field.NewPath("spec", "postgresql", "tde")
+	if r.GetPostgresFlavor() != postgres.FlavorEPAS {
+		result = append(result, field.Invalid(
+			basePath.Child("enabled"),
+			tde.Enabled,
+			"Transparent Data Encryption requires the epas flavor"))
+	} else if version, err := r.GetPostgresqlVersion(); err == nil && version < 150000 {
+		result = append(result, field.Invalid(
+			basePath.Child("enabled"),
+			tde.Enabled,
+			"Transparent Data Encryption requires version 15 or later"))
+	}
+
+	hasCommands := tde.WrapCommand != "" || tde.UnwrapCommand != ""
+	switch {
+	case tde.PassphraseSecret != nil && hasCommands:
+		result = append(result, field.Invalid(
+			basePath,
+			"",
+			"passphraseSecret cannot be used together with wrapCommand and unwrapCommand"))
+	case tde.PassphraseSecret == nil && !hasCommands:
+		result = append(result, field.Required(
+			basePath,
+			"either passphraseSecret or wrapCommand and unwrapCommand are required"))
+	case hasCommands:
+		if !strings.Contains(tde.WrapCommand, "%p") {
+			result = append(result, field.Invalid(
+				basePath.Child("wrapCommand"),
+				tde.WrapCommand,
+				"the command must contain the %p placeholder"))
+		}
+		if !strings.Contains(tde.UnwrapCommand, "%p") {
+			result = append(result, field.Invalid(
+				basePath.Child("unwrapCommand"),
+				tde.UnwrapCommand,
+				"the command must contain the %p placeholder"))
+		}
+	}
+
+	return result
+}
+
+// validateTDEChange checks that the Transparent Data Encryption
+// is not enabled or disabled after the cluster creation
+func (r *Cluster) validateTDEChange(old *Cluster) field.ErrorList {
+	var result field.ErrorList
+
+	enabled := r.Spec.PostgresConfiguration.TDE.IsEnabled()
+	if enabled != old.Spec.PostgresConfiguration.TDE.IsEnabled() {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "postgresql", "tde", "enabled"),
+			enabled,
+			"Transparent Data Encryption can only be configured when the cluster is created"))
+	}
+
+	return result
+}
+
 // validateBackupConfiguration validates the backup configuration
 func (r *Cluster) validateBackupConfiguration() field.ErrorList {
 	allErrors := field.ErrorList{}
@@ -1450,53 +3234,149 @@ func (r *Cluster) validateBackupConfiguration() field.ErrorList {
 		return nil
 	}
 
+	allErrors = append(allErrors, validateBarmanCredentials(
+		r.Spec.Backup.BarmanObjectStore, field.NewPath("spec", "backupConfiguration"))...)
+
+	if r.Spec.Backup.RetentionPolicy != "" {
+		_, err := utils.ParsePolicy(r.Spec.Backup.RetentionPolicy)
+		if err != nil {
+			allErrors = append(allErrors, field.Invalid(
+				field.NewPath("spec", "retentionPolicy"),
+				r.Spec.Backup.RetentionPolicy,
+				"not a valid retention policy",
+			))
+		}
+	}
+
+	if gc := r.Spec.Backup.GarbageCollection; gc != nil {
+		if _, err := cron.Parse(gc.Schedule); err != nil {
+			allErrors = append(allErrors, field.Invalid(
+				field.NewPath("spec", "backup", "garbageCollection", "schedule"),
+				gc.Schedule,
+				err.Error()))
+		}
+		if gc.ApplyRetentionPolicy && r.Spec.Backup.RetentionPolicy == "" {
+			allErrors = append(allErrors, field.Invalid(
+				field.NewPath("spec", "backup", "garbageCollection", "applyRetentionPolicy"),
+				gc.ApplyRetentionPolicy,
+				"the retention policy can be applied only when retentionPolicy is set"))
+		}
+	}
+
+	return allErrors
+}
+
+// validateBarmanCredentials checks that one and only one
+// set of credentials is specified for an object store
+func validateBarmanCredentials(store *BarmanObjectStoreConfiguration, path *field.Path) field.ErrorList {
+	var allErrors field.ErrorList
+
 	credentialsCount := 0
-	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Azure != nil {
+	if store.BarmanCredentials.Azure != nil {
 		credentialsCount++
-		allErrors = r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Azure.validateAzureCredentials(
-			field.NewPath("spec", "backupConfiguration", "azureCredentials"))
+		allErrors = append(allErrors,
+			store.BarmanCredentials.Azure.validateAzureCredentials(path.Child("azureCredentials"))...)
 	}
-	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.AWS != nil {
+	if store.BarmanCredentials.AWS != nil {
 		credentialsCount++
-		allErrors = r.Spec.Backup.BarmanObjectStore.BarmanCredentials.AWS.validateAwsCredentials(
-			field.NewPath("spec", "backupConfiguration", "s3Credentials"))
+		allErrors = append(allErrors,
+			store.BarmanCredentials.AWS.validateAwsCredentials(path.Child("s3Credentials"))...)
 	}
-	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Google != nil {
+	if store.BarmanCredentials.Google != nil {
 		credentialsCount++
-		allErrors = r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Google.validateGCSCredentials(
-			field.NewPath("spec", "backupConfiguration", "googleCredentials"))
+		allErrors = append(allErrors,
+			store.BarmanCredentials.Google.validateGCSCredentials(path.Child("googleCredentials"))...)
 	}
 	if credentialsCount == 0 {
 		allErrors = append(allErrors, field.Invalid(
-			field.NewPath("spec", "backupConfiguration"),
-			r.Spec.Backup.BarmanObjectStore,
+			path,
+			store,
 			"missing credentials. "+
 				"One and only one of azureCredentials, s3Credentials and googleCredentials are required",
 		))
 	}
 	if credentialsCount > 1 {
 		allErrors = append(allErrors, field.Invalid(
-			field.NewPath("spec", "backupConfiguration"),
-			r.Spec.Backup.BarmanObjectStore,
+			path,
+			store,
 			"too many credentials. "+
 				"One and only one of azureCredentials, s3Credentials and googleCredentials are required",
 		))
 	}
 
-	if r.Spec.Backup.RetentionPolicy != "" {
-		_, err := utils.ParsePolicy(r.Spec.Backup.RetentionPolicy)
-		if err != nil {
+	return allErrors
+}
+
+// validateAdditionalObjectStores validates the additional object stores
+// where the WAL files and the base backups are copied
+func (r *Cluster) validateAdditionalObjectStores() field.ErrorList {
+	var allErrors field.ErrorList
+
+	if r.Spec.Backup == nil || len(r.Spec.Backup.AdditionalObjectStores) == 0 {
+		return nil
+	}
+
+	basePath := field.NewPath("spec", "backup", "additionalObjectStores")
+	if r.Spec.Backup.BarmanObjectStore == nil {
+		return append(allErrors, field.Invalid(
+			basePath,
+			len(r.Spec.Backup.AdditionalObjectStores),
+			"additional object stores require barmanObjectStore to be configured"))
+	}
+
+	names := stringset.New()
+	destinations := stringset.New()
+	destinations.Put(getObjectStoreDestination(
+		r.Spec.Backup.BarmanObjectStore.DestinationPath,
+		r.Spec.Backup.BarmanObjectStore.ServerName,
+		r.Name))
+	for idx := range r.Spec.Backup.AdditionalObjectStores {
+		store := &r.Spec.Backup.AdditionalObjectStores[idx]
+		path := basePath.Index(idx)
+
+		if names.Has(store.Name) {
+			allErrors = append(allErrors, field.Duplicate(path.Child("name"), store.Name))
+		}
+		names.Put(store.Name)
+
+		destination := getObjectStoreDestination(
+			store.BarmanObjectStore.DestinationPath,
+			store.BarmanObjectStore.ServerName,
+			r.Name)
+		if destinations.Has(destination) {
 			allErrors = append(allErrors, field.Invalid(
-				field.NewPath("spec", "retentionPolicy"),
-				r.Spec.Backup.RetentionPolicy,
-				"not a valid retention policy",
-			))
+				path.Child("barmanObjectStore", "destinationPath"),
+				store.BarmanObjectStore.DestinationPath,
+				"the object stores must have different destination paths or server names"))
+		}
+		destinations.Put(destination)
+
+		allErrors = append(allErrors,
+			validateBarmanCredentials(&store.BarmanObjectStore, path.Child("barmanObjectStore"))...)
+
+		if store.RetentionPolicy != "" {
+			if _, err := utils.ParsePolicy(store.RetentionPolicy); err != nil {
+				allErrors = append(allErrors, field.Invalid(
+					path.Child("retentionPolicy"),
+					store.RetentionPolicy,
+					"not a valid retention policy",
+				))
+			}
 		}
 	}
 
 	return allErrors
 }
 
+// getObjectStoreDestination gets the location where a server
+// stores its backups and WAL files in an object store
+func getObjectStoreDestination(destinationPath, serverName, clusterName string) string {
+	if serverName == "" {
+		serverName = clusterName
+	}
+	return strings.TrimSuffix(destinationPath, "/") + "/" + serverName
+}
+
 // validateRecoveryAndBackupTarget validates that the recovery point and
 // the backup point are not the same
 func (r *Cluster) validateRecoveryAndBackupTarget() field.ErrorList {
@@ -1521,7 +3401,14 @@ func (r *Cluster) validateRecoveryAndBackupTarget() field.ErrorList {
 	barmanObjectStore := r.Spec.Backup.BarmanObjectStore
 	sourceBarmanObjectStore := sourceCluster.BarmanObjectStore
 
-	if barmanObjectStore.ServerName == sourceBarmanObjectStore.ServerName &&
+	// The server names default to the name of the cluster and
+	// to the name of the external cluster respectively
+	serverName := barmanObjectStore.ServerName
+	if serverName == "" {
+		serverName = r.Name
+	}
+
+	if serverName == sourceCluster.GetServerName() &&
 		barmanObjectStore.EndpointURL == sourceBarmanObjectStore.EndpointURL &&
 		barmanObjectStore.DestinationPath == sourceBarmanObjectStore.DestinationPath {
 		allErrors = append(
@@ -1644,3 +3531,23 @@ func (gcs *GoogleCredentials) validateGCSCredentials(path *field.Path) field.Err
 
 	return allErrors
 }
+
+// validatePlugins validates the list of the plugins used by the cluster
+func (r *Cluster) validatePlugins() field.ErrorList {
+	var result field.ErrorList
+
+	names := stringset.New()
+	for idx, plugin := range r.Spec.Plugins {
+		path := field.NewPath("spec", "plugins").Index(idx)
+		if plugin.Name == "" {
+			result = append(result, field.Required(path.Child("name"), "the name of the plugin is required"))
+			continue
+		}
+		if names.Has(plugin.Name) {
+			result = append(result, field.Duplicate(path.Child("name"), plugin.Name))
+		}
+		names.Put(plugin.Name)
+	}
+
+	return result
+}
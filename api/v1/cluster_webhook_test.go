@@ -18,12 +18,16 @@ package v1
 
 import (
 	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -72,6 +76,33 @@ var _ = Describe("bootstrap methods validation", func() {
 		result := invalidCluster.validateBootstrapMethod()
 		Expect(len(result)).To(Equal(1))
 	})
+
+	It("complains when adopting PVCs along with another bootstrap method", func() {
+		invalidCluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Adopt:  &BootstrapAdopt{},
+					InitDB: &BootstrapInitDB{},
+				},
+			},
+		}
+		result := invalidCluster.validateBootstrapMethod()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("defaults the adopted instance serial without enabling initdb", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Adopt: &BootstrapAdopt{},
+				},
+			},
+		}
+		cluster.Default()
+		Expect(cluster.Spec.Bootstrap.Adopt.InstanceSerial).To(Equal(1))
+		Expect(cluster.Spec.Bootstrap.InitDB).To(BeNil())
+		Expect(cluster.validateBootstrapMethod()).To(BeEmpty())
+	})
 })
 
 var _ = Describe("azure credentials", func() {
@@ -286,7 +317,7 @@ var _ = Describe("initdb options validation", func() {
 					InitDB: &BootstrapInitDB{
 						Database: "app",
 						Owner:    "app",
-						PostInitApplicationSQLRefs: &PostInitApplicationSQLRefs{
+						PostInitApplicationSQLRefs: &SQLRefs{
 							SecretRefs: []SecretKeySelector{
 								{
 									LocalObjectReference: LocalObjectReference{Name: "secret1"},
@@ -309,7 +340,7 @@ var _ = Describe("initdb options validation", func() {
 					InitDB: &BootstrapInitDB{
 						Database: "app",
 						Owner:    "app",
-						PostInitApplicationSQLRefs: &PostInitApplicationSQLRefs{
+						PostInitApplicationSQLRefs: &SQLRefs{
 							SecretRefs: []SecretKeySelector{
 								{
 									Key: "key",
@@ -332,7 +363,7 @@ var _ = Describe("initdb options validation", func() {
 					InitDB: &BootstrapInitDB{
 						Database: "app",
 						Owner:    "app",
-						PostInitApplicationSQLRefs: &PostInitApplicationSQLRefs{
+						PostInitApplicationSQLRefs: &SQLRefs{
 							ConfigMapRefs: []ConfigMapKeySelector{
 								{
 									LocalObjectReference: LocalObjectReference{Name: "configmap1"},
@@ -355,7 +386,7 @@ var _ = Describe("initdb options validation", func() {
 					InitDB: &BootstrapInitDB{
 						Database: "app",
 						Owner:    "app",
-						PostInitApplicationSQLRefs: &PostInitApplicationSQLRefs{
+						PostInitApplicationSQLRefs: &SQLRefs{
 							ConfigMapRefs: []ConfigMapKeySelector{
 								{
 									Key: "key",
@@ -378,7 +409,7 @@ var _ = Describe("initdb options validation", func() {
 					InitDB: &BootstrapInitDB{
 						Database: "app",
 						Owner:    "app",
-						PostInitApplicationSQLRefs: &PostInitApplicationSQLRefs{
+						PostInitApplicationSQLRefs: &SQLRefs{
 							ConfigMapRefs: []ConfigMapKeySelector{
 								{
 									LocalObjectReference: LocalObjectReference{Name: "configmap1"},
@@ -409,6 +440,38 @@ var _ = Describe("initdb options validation", func() {
 		Expect(result).To(BeEmpty())
 	})
 
+	It("complain if name or key are missing in the postInitSQLRefs and postInitTemplateSQLRefs", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						PostInitSQLRefs: &SQLRefs{
+							ConfigMapRefs: []ConfigMapKeySelector{
+								{
+									LocalObjectReference: LocalObjectReference{Name: "configmap1"},
+								},
+							},
+						},
+						PostInitTemplateSQLRefs: &SQLRefs{
+							SecretRefs: []SecretKeySelector{
+								{
+									Key: "key",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateInitDB()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.postInitSQLRefs.configMapRefs"))
+		Expect(result[1].Field).To(Equal("spec.bootstrap.initdb.postInitTemplateSQLRefs.secretRefs"))
+	})
+
 	It("doesn't complain if superuser secret it's empty", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{},
@@ -433,6 +496,66 @@ var _ = Describe("initdb options validation", func() {
 	})
 })
 
+var _ = Describe("initdb locale provider validation", func() {
+	It("doesn't complain when using the icu locale provider on PostgreSQL 15", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15.0",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						LocaleProvider: "icu",
+						IcuLocale:      "en-US",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateInitDB()).To(BeEmpty())
+	})
+
+	It("complains when choosing the locale provider before PostgreSQL 15", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:14.5",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						LocaleProvider: "icu",
+						IcuLocale:      "en-US",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateInitDB()).To(HaveLen(1))
+	})
+
+	It("complains when the ICU locale is missing", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15.0",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						LocaleProvider: "icu",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateInitDB()).To(HaveLen(1))
+	})
+
+	It("complains when the ICU locale is used without the icu locale provider", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15.0",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						IcuLocale: "en-US",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateInitDB()).To(HaveLen(1))
+	})
+})
+
 var _ = Describe("cluster configuration", func() {
 	It("defaults to creating an application database", func() {
 		cluster := Cluster{}
@@ -522,16 +645,34 @@ var _ = Describe("cluster configuration", func() {
 		Expect(len(cluster.Spec.PostgresConfiguration.Parameters)).To(BeNumerically(">", 0))
 	})
 
-	It("defaults the anti-affinity", func() {
+	It("defaults the anti-affinity of a new cluster to required", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
 				Affinity: AffinityConfiguration{},
 			},
 		}
 		cluster.Default()
-		Expect(cluster.Spec.Affinity.PodAntiAffinityType).To(BeEquivalentTo(PodAntiAffinityTypePreferred))
+		Expect(cluster.Spec.Affinity.PodAntiAffinityType).To(BeEquivalentTo(PodAntiAffinityTypeRequired))
 		Expect(cluster.Spec.Affinity.EnablePodAntiAffinity).To(BeNil())
 	})
+
+	It("defaults the anti-affinity to preferred for existing clusters and when opted out", func() {
+		existingCluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: metav1.Now(),
+			},
+		}
+		existingCluster.Default()
+		Expect(existingCluster.Spec.Affinity.PodAntiAffinityType).To(BeEquivalentTo(PodAntiAffinityTypePreferred))
+
+		optedOutCluster := Cluster{
+			Spec: ClusterSpec{
+				GeneratedDefaults: &GeneratedDefaultsConfiguration{DisableRequiredPodAntiAffinity: true},
+			},
+		}
+		optedOutCluster.Default()
+		Expect(optedOutCluster.Spec.Affinity.PodAntiAffinityType).To(BeEquivalentTo(PodAntiAffinityTypePreferred))
+	})
 })
 
 var _ = Describe("ImagePullPolicy validation", func() {
@@ -561,7 +702,7 @@ var _ = Describe("Defaulting webhook", func() {
 	It("should fill the image name if isn't already set", func() {
 		cluster := Cluster{}
 		cluster.Default()
-		Expect(cluster.Spec.ImageName).To(Equal(configuration.Current.PostgresImageName))
+		Expect(cluster.Spec.ImageName).To(Equal(configuration.Current().PostgresImageName))
 	})
 
 	It("shouldn't set the image name if already present", func() {
@@ -753,6 +894,256 @@ var _ = Describe("configuration change validation", func() {
 		}
 		Expect(len(clusterNew.validateConfigurationChange(&clusterOld))).To(Equal(1))
 	})
+
+	It("complains when changing postgres major version and the computed settings", func() {
+		maxConnections := int32(200)
+		clusterOld := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:10.4",
+			},
+		}
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:10.5",
+				PostgresConfiguration: PostgresConfiguration{
+					Connections: &ConnectionsConfiguration{MaxConnections: &maxConnections},
+				},
+			},
+		}
+		Expect(len(clusterNew.validateConfigurationChange(&clusterOld))).To(Equal(1))
+	})
+})
+
+var _ = Describe("fixed configuration parameters validation", func() {
+	It("doesn't complain when no fixed parameter is set", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+			},
+			Spec: ClusterSpec{
+				ImageName: "postgres:14.4",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"shared_buffers":             "1GB",
+						"log_min_duration_statement": "1s",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateConfiguration()).To(BeEmpty())
+	})
+
+	It("allows overriding the parameters defaulted by the operator", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+			},
+			Spec: ClusterSpec{
+				ImageName: "postgres:14.4",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"max_worker_processes": "64",
+						"wal_keep_size":        "1GB",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateConfiguration()).To(BeEmpty())
+	})
+
+	It("complains when a blocked parameter is set, even to its current value", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+			},
+			Spec: ClusterSpec{
+				ImageName: "postgres:14.4",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"port":           "5432",
+						"data_directory": "/tmp/pgdata",
+					},
+				},
+			},
+		}
+		result := cluster.validateConfiguration()
+		Expect(result).To(HaveLen(2))
+		for _, err := range result {
+			Expect(err.Detail).To(HavePrefix("Can't set blocked configuration parameter: "))
+		}
+	})
+
+	It("accepts the blocked parameters stored in the spec by the defaulting webhook", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+			},
+			Spec: ClusterSpec{
+				ImageName: "postgres:14.4",
+			},
+		}
+		cluster.Default()
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).To(HaveKeyWithValue("log_destination", "csvlog"))
+		Expect(cluster.validateConfiguration()).To(BeEmpty())
+
+		cluster.Spec.PostgresConfiguration.Parameters["log_destination"] = "stderr"
+		Expect(cluster.validateConfiguration()).To(HaveLen(1))
+	})
+
+	It("reports why a blocked parameter can't be set", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+			},
+			Spec: ClusterSpec{
+				ImageName: "postgres:14.4",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"port": "5433",
+					},
+				},
+			},
+		}
+		result := cluster.validateConfiguration()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.parameters.port"))
+		Expect(result[0].Detail).To(ContainSubstring(
+			postgres.FixedConfigurationParameters["port"].Reason))
+	})
+
+	It("doesn't complain when a managed parameter is set to the enforced value", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+			},
+			Spec: ClusterSpec{
+				ImageName: "postgres:14.4",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"hot_standby":  "true",
+						"archive_mode": "on",
+						"cluster_name": "cluster-example",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateConfiguration()).To(BeEmpty())
+	})
+
+	It("complains when a managed parameter is set to a different value", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+			},
+			Spec: ClusterSpec{
+				ImageName: "postgres:14.4",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"hot_standby": "off",
+					},
+				},
+			},
+		}
+		result := cluster.validateConfiguration()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Detail).To(ContainSubstring(`different from "true"`))
+		Expect(result[0].Detail).To(ContainSubstring(
+			postgres.FixedConfigurationParameters["hot_standby"].Reason))
+	})
+
+	It("enforces the archive mode of replica clusters", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+			},
+			Spec: ClusterSpec{
+				ImageName: "postgres:14.4",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"archive_mode": "on",
+					},
+				},
+			},
+		}
+		cluster.Spec.ReplicaCluster = &ReplicaClusterConfiguration{
+			Enabled: true,
+			Source:  "origin",
+		}
+		result := cluster.validateConfiguration()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Detail).To(ContainSubstring(`different from "always"`))
+	})
+
+	It("complains when a managed parameter without an enforced value is set", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+			},
+			Spec: ClusterSpec{
+				ImageName: "postgres:14.4",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"primary_conninfo": "host=somewhere",
+					},
+				},
+			},
+		}
+		result := cluster.validateConfiguration()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Detail).To(HavePrefix("Can't set managed configuration parameter: "))
+	})
+})
+
+var _ = Describe("configuration files validation", func() {
+	It("doesn't complain when there are no configuration files", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateConfigurationFiles()).To(BeEmpty())
+	})
+
+	It("doesn't complain with valid references", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					ConfigurationFiles: []ConfigMapKeySelector{
+						{LocalObjectReference: LocalObjectReference{Name: "tuning"}, Key: "memory.conf"},
+						{LocalObjectReference: LocalObjectReference{Name: "tuning"}, Key: "jit.conf"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateConfigurationFiles()).To(BeEmpty())
+	})
+
+	It("complains when the name or the key are missing", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					ConfigurationFiles: []ConfigMapKeySelector{
+						{Key: "memory.conf"},
+						{LocalObjectReference: LocalObjectReference{Name: "tuning"}},
+					},
+				},
+			},
+		}
+		result := cluster.validateConfigurationFiles()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.postgresql.configurationFiles[0].name"))
+		Expect(result[1].Field).To(Equal("spec.postgresql.configurationFiles[1].key"))
+	})
+
+	It("complains when a file is referenced twice", func() {
+		reference := ConfigMapKeySelector{LocalObjectReference: LocalObjectReference{Name: "tuning"}, Key: "memory.conf"}
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					ConfigurationFiles: []ConfigMapKeySelector{reference, reference},
+				},
+			},
+		}
+		result := cluster.validateConfigurationFiles()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Type).To(Equal(field.ErrorTypeDuplicate))
+	})
 })
 
 var _ = Describe("validate image name change", func() {
@@ -778,7 +1169,9 @@ var _ = Describe("validate image name change", func() {
 				ImageName: "postgres:11.0",
 			},
 		}
-		Expect(len(clusterNew.validateImageChange("postgres:12.0"))).To(Equal(1))
+		result := clusterNew.validateImageChange("postgres:12.0")
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Detail).To(ContainSubstring("import section of the initdb bootstrap"))
 	})
 
 	It("doesn't complain if image change it's valid", func() {
@@ -1365,6 +1758,54 @@ var _ = Describe("validation of an external cluster", func() {
 		cluster.Spec.ExternalClusters[0].BarmanObjectStore = &BarmanObjectStoreConfiguration{}
 		Expect(cluster.validateExternalClusters()).To(BeEmpty())
 	})
+	It("accepts a server whose backups are restored by a plugin", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ExternalClusters: []ExternalCluster{
+					{
+						Name:                "one",
+						PluginConfiguration: &PluginConfiguration{Name: "backup"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+	})
+
+	It("complains when a server uses both an object store and a plugin", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ExternalClusters: []ExternalCluster{
+					{
+						Name:                "one",
+						BarmanObjectStore:   &BarmanObjectStoreConfiguration{},
+						PluginConfiguration: &PluginConfiguration{Name: "backup"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateExternalClusters()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validation of the plugins", func() {
+	It("accepts a list of plugins with unique names", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Plugins: []PluginConfiguration{{Name: "backup"}, {Name: "sidecar"}},
+			},
+		}
+		Expect(cluster.validatePlugins()).To(BeEmpty())
+	})
+
+	It("complains about duplicate and empty names", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Plugins: []PluginConfiguration{{Name: "backup"}, {Name: "backup"}, {}},
+			},
+		}
+		Expect(cluster.validatePlugins()).To(HaveLen(2))
+	})
 })
 
 var _ = Describe("bootstrap base backup validation", func() {
@@ -1525,6 +1966,206 @@ var _ = Describe("bootstrap recovery validation", func() {
 	})
 })
 
+var _ = Describe("bootstrap recovery from volume snapshots", func() {
+	It("defaults the data sources to volume snapshots", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						VolumeSnapshots: &DataSource{
+							Storage:    v1.TypedLocalObjectReference{Name: "pgdata"},
+							WalStorage: &v1.TypedLocalObjectReference{Name: "pgwal"},
+						},
+					},
+				},
+			},
+		}
+		cluster.defaultRecovery()
+		volumeSnapshots := cluster.Spec.Bootstrap.Recovery.VolumeSnapshots
+		Expect(volumeSnapshots.Storage.Kind).To(Equal(VolumeSnapshotKind))
+		Expect(volumeSnapshots.Storage.APIGroup).To(HaveValue(Equal(VolumeSnapshotAPIGroup)))
+		Expect(volumeSnapshots.WalStorage.Kind).To(Equal(VolumeSnapshotKind))
+		Expect(volumeSnapshots.WalStorage.APIGroup).To(HaveValue(Equal(VolumeSnapshotAPIGroup)))
+	})
+
+	It("accepts a volume snapshot of the storage", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						VolumeSnapshots: &DataSource{Storage: v1.TypedLocalObjectReference{Name: "pgdata"}},
+					},
+				},
+			},
+		}
+		cluster.defaultRecovery()
+		Expect(cluster.validateRecoveryVolumeSnapshots()).To(BeEmpty())
+	})
+
+	It("complains about data sources which are not volume snapshots", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						VolumeSnapshots: &DataSource{
+							Storage: v1.TypedLocalObjectReference{Name: "pgdata", Kind: "PersistentVolumeClaim"},
+						},
+					},
+				},
+			},
+		}
+		cluster.defaultRecovery()
+		Expect(cluster.validateRecoveryVolumeSnapshots()).To(HaveLen(1))
+	})
+
+	It("complains if a backup is specified too", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						VolumeSnapshots: &DataSource{Storage: v1.TypedLocalObjectReference{Name: "pgdata"}},
+					},
+				},
+			},
+		}
+		cluster.defaultRecovery()
+		cluster.Spec.Bootstrap.Recovery.Backup = &BackupSource{LocalObjectReference: LocalObjectReference{Name: "backup"}}
+		Expect(cluster.validateRecoveryVolumeSnapshots()).To(HaveLen(1))
+	})
+
+	It("requires a WAL storage when recovering it from a volume snapshot", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						VolumeSnapshots: &DataSource{
+							Storage:    v1.TypedLocalObjectReference{Name: "pgdata"},
+							WalStorage: &v1.TypedLocalObjectReference{Name: "pgwal"},
+						},
+					},
+				},
+			},
+		}
+		cluster.defaultRecovery()
+		Expect(cluster.validateRecoveryVolumeSnapshots()).To(HaveLen(1))
+
+		cluster.Spec.WalStorage = &StorageConfiguration{Size: "1Gi"}
+		Expect(cluster.validateRecoveryVolumeSnapshots()).To(BeEmpty())
+	})
+
+	It("requires the volume snapshot of the WAL storage when the cluster has a WAL storage", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						VolumeSnapshots: &DataSource{Storage: v1.TypedLocalObjectReference{Name: "pgdata"}},
+					},
+				},
+			},
+		}
+		cluster.defaultRecovery()
+		cluster.Spec.WalStorage = &StorageConfiguration{Size: "1Gi"}
+		Expect(cluster.validateRecoveryVolumeSnapshots()).To(HaveLen(1))
+	})
+
+	It("accepts a replica cluster following its source", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						VolumeSnapshots: &DataSource{Storage: v1.TypedLocalObjectReference{Name: "pgdata"}},
+					},
+				},
+			},
+		}
+		cluster.defaultRecovery()
+		cluster.Spec.ReplicaCluster = &ReplicaClusterConfiguration{Enabled: true, Source: "origin"}
+		cluster.Spec.ExternalClusters = []ExternalCluster{{Name: "origin"}}
+		Expect(cluster.validateRecoveryVolumeSnapshots()).To(BeEmpty())
+
+		cluster.Spec.Bootstrap.Recovery.RecoveryTarget = &RecoveryTarget{TargetName: "restore-point"}
+		Expect(cluster.validateRecoveryVolumeSnapshots()).To(HaveLen(1))
+	})
+
+	It("requires a source with a WAL archive to recover to a target", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						VolumeSnapshots: &DataSource{Storage: v1.TypedLocalObjectReference{Name: "pgdata"}},
+					},
+				},
+			},
+		}
+		cluster.defaultRecovery()
+		cluster.Spec.Bootstrap.Recovery.RecoveryTarget = &RecoveryTarget{TargetName: "restore-point"}
+		Expect(cluster.validateRecoveryVolumeSnapshots()).To(HaveLen(1))
+
+		cluster.Spec.Bootstrap.Recovery.Source = "origin"
+		cluster.Spec.ExternalClusters = []ExternalCluster{{Name: "origin"}}
+		Expect(cluster.validateRecoveryVolumeSnapshots()).To(HaveLen(1))
+
+		cluster.Spec.ExternalClusters[0].BarmanObjectStore = &BarmanObjectStoreConfiguration{
+			DestinationPath: "s3://backups/",
+		}
+		Expect(cluster.validateRecoveryVolumeSnapshots()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("replica creation method validation", func() {
+	It("accepts the default method", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateReplicaCreationMethod()).To(BeEmpty())
+	})
+
+	It("requires an object store to create the replicas from a backup", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCreationMethod: ReplicaCreationMethodBackup,
+			},
+		}
+		Expect(cluster.validateReplicaCreationMethod()).To(HaveLen(1))
+
+		cluster.Spec.Backup = &BackupConfiguration{
+			BarmanObjectStore: &BarmanObjectStoreConfiguration{DestinationPath: "s3://backups/"},
+		}
+		Expect(cluster.validateReplicaCreationMethod()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("replication connection validation", func() {
+	It("accepts the channel_binding from PostgreSQL 13", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:13",
+				ReplicationConnection: &ReplicationConnectionConfiguration{
+					ChannelBinding: "prefer",
+				},
+			},
+		}
+		Expect(cluster.validateReplicationConnection()).To(BeEmpty())
+	})
+
+	It("complains about the channel_binding before PostgreSQL 13", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:12",
+				ReplicationConnection: &ReplicationConnectionConfiguration{
+					ChannelBinding: "prefer",
+				},
+			},
+		}
+		Expect(cluster.validateReplicationConnection()).To(HaveLen(1))
+	})
+
+	It("defaults the sslmode to verify-ca", func() {
+		var configuration *ReplicationConnectionConfiguration
+		Expect(configuration.GetSSLMode()).To(Equal(ReplicationSSLModeVerifyCA))
+		configuration = &ReplicationConnectionConfiguration{SSLMode: ReplicationSSLModeVerifyFull}
+		Expect(configuration.GetSSLMode()).To(Equal(ReplicationSSLModeVerifyFull))
+	})
+})
+
 var _ = Describe("toleration validation", func() {
 	It("doesn't complain if we provide a proper toleration", func() {
 		recoveryCluster := &Cluster{
@@ -1971,6 +2612,151 @@ var _ = Describe("Backup validation", func() {
 		err := cluster.validateBackupConfiguration()
 		Expect(len(err)).To(Equal(2))
 	})
+
+	It("validates the garbage collection of the object store", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						BarmanCredentials: BarmanCredentials{
+							AWS: &S3Credentials{InheritFromIAMRole: true},
+						},
+					},
+					GarbageCollection: &BackupGarbageCollectionConfiguration{
+						Schedule: "0 0 0 * * *",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+
+		cluster.Spec.Backup.GarbageCollection.ApplyRetentionPolicy = true
+		Expect(cluster.validateBackupConfiguration()).To(HaveLen(1))
+
+		cluster.Spec.Backup.RetentionPolicy = "30d"
+		Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+
+		cluster.Spec.Backup.GarbageCollection.Schedule = "invalid"
+		Expect(cluster.validateBackupConfiguration()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("Additional object stores validation", func() {
+	var cluster *Cluster
+
+	BeforeEach(func() {
+		cluster = &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://main/",
+						BarmanCredentials: BarmanCredentials{
+							AWS: &S3Credentials{InheritFromIAMRole: true},
+						},
+					},
+				},
+			},
+		}
+	})
+
+	It("doesn't complain if there are no additional object stores", func() {
+		Expect(cluster.validateAdditionalObjectStores()).To(BeEmpty())
+	})
+
+	It("accepts additional object stores with different destinations", func() {
+		cluster.Spec.Backup.AdditionalObjectStores = []AdditionalObjectStore{
+			{
+				Name: "minio",
+				BarmanObjectStore: BarmanObjectStoreConfiguration{
+					DestinationPath: "s3://minio/",
+					BarmanCredentials: BarmanCredentials{
+						AWS: &S3Credentials{InheritFromIAMRole: true},
+					},
+				},
+				RetentionPolicy: "7d",
+			},
+			{
+				Name: "offsite",
+				BarmanObjectStore: BarmanObjectStoreConfiguration{
+					DestinationPath: "s3://offsite/",
+					BarmanCredentials: BarmanCredentials{
+						AWS: &S3Credentials{InheritFromIAMRole: true},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateAdditionalObjectStores()).To(BeEmpty())
+	})
+
+	It("complains if the main object store is not configured", func() {
+		cluster.Spec.Backup.BarmanObjectStore = nil
+		cluster.Spec.Backup.AdditionalObjectStores = []AdditionalObjectStore{
+			{
+				Name: "minio",
+				BarmanObjectStore: BarmanObjectStoreConfiguration{
+					DestinationPath: "s3://minio/",
+					BarmanCredentials: BarmanCredentials{
+						AWS: &S3Credentials{InheritFromIAMRole: true},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateAdditionalObjectStores()).To(HaveLen(1))
+	})
+
+	It("complains about duplicated names", func() {
+		cluster.Spec.Backup.AdditionalObjectStores = []AdditionalObjectStore{
+			{
+				Name: "minio",
+				BarmanObjectStore: BarmanObjectStoreConfiguration{
+					DestinationPath: "s3://minio/",
+					BarmanCredentials: BarmanCredentials{
+						AWS: &S3Credentials{InheritFromIAMRole: true},
+					},
+				},
+			},
+			{
+				Name: "minio",
+				BarmanObjectStore: BarmanObjectStoreConfiguration{
+					DestinationPath: "s3://offsite/",
+					BarmanCredentials: BarmanCredentials{
+						AWS: &S3Credentials{InheritFromIAMRole: true},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateAdditionalObjectStores()).To(HaveLen(1))
+	})
+
+	It("complains if two object stores share the same destination", func() {
+		cluster.Spec.Backup.AdditionalObjectStores = []AdditionalObjectStore{
+			{
+				Name: "minio",
+				BarmanObjectStore: BarmanObjectStoreConfiguration{
+					DestinationPath: "s3://main",
+					BarmanCredentials: BarmanCredentials{
+						AWS: &S3Credentials{InheritFromIAMRole: true},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateAdditionalObjectStores()).To(HaveLen(1))
+
+		cluster.Spec.Backup.AdditionalObjectStores[0].BarmanObjectStore.ServerName = "other"
+		Expect(cluster.validateAdditionalObjectStores()).To(BeEmpty())
+	})
+
+	It("complains about missing credentials and invalid retention policies", func() {
+		cluster.Spec.Backup.AdditionalObjectStores = []AdditionalObjectStore{
+			{
+				Name:              "minio",
+				BarmanObjectStore: BarmanObjectStoreConfiguration{DestinationPath: "s3://minio/"},
+				RetentionPolicy:   "09",
+			},
+		}
+		Expect(cluster.validateAdditionalObjectStores()).To(HaveLen(2))
+	})
 })
 
 var _ = Describe("Default monitoring queries", func() {
@@ -2050,25 +2836,331 @@ var _ = Describe("Default monitoring queries", func() {
 	})
 })
 
-var _ = Describe("Recovery and Backup Target", func() {
-	cluster := &Cluster{
-		Spec: ClusterSpec{
-			Bootstrap: &BootstrapConfiguration{
-				Recovery: &BootstrapRecovery{
-					Source: "one",
+var _ = Describe("Default resources", func() {
+	config := &configuration.Data{
+		DefaultRequestsMemory: "512Mi",
+		DefaultLimitsMemory:   "1Gi",
+	}
+
+	It("sets the default resources of a new cluster", func() {
+		cluster := &Cluster{}
+		cluster.defaultResources(config)
+		Expect(cluster.Spec.Resources.Requests.Memory().String()).To(Equal("512Mi"))
+		Expect(cluster.Spec.Resources.Limits.Memory().String()).To(Equal("1Gi"))
+	})
+
+	It("doesn't change the resources specified by the user", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{
+						v1.ResourceCPU: resource.MustParse("1"),
+					},
 				},
 			},
-			Backup: &BackupConfiguration{
-				BarmanObjectStore: &BarmanObjectStoreConfiguration{
-					BarmanCredentials: BarmanCredentials{
-						AWS: &S3Credentials{
-							AccessKeyIDReference: &SecretKeySelector{
-								LocalObjectReference: LocalObjectReference{
-									Name: "s3-creds",
-								},
-								Key: "access_key",
-							},
-							SecretAccessKeyReference: &SecretKeySelector{
+		}
+		cluster.defaultResources(config)
+		Expect(cluster.Spec.Resources.Requests).To(HaveLen(1))
+		Expect(cluster.Spec.Resources.Limits).To(BeEmpty())
+	})
+
+	It("doesn't set the resources when the user opted out of them", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				GeneratedDefaults: &GeneratedDefaultsConfiguration{DisableResources: true},
+			},
+		}
+		cluster.defaultResources(config)
+		Expect(cluster.Spec.Resources.Requests).To(BeEmpty())
+		Expect(cluster.Spec.Resources.Limits).To(BeEmpty())
+	})
+
+	It("doesn't change the resources of an existing cluster", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: metav1.Now(),
+			},
+		}
+		cluster.defaultResources(config)
+		Expect(cluster.Spec.Resources.Requests).To(BeEmpty())
+		Expect(cluster.Spec.Resources.Limits).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Resources validation", func() {
+	It("requires the CPU and memory limits for the Guaranteed QoS class", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				GuaranteedQoS: true,
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{
+						v1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				},
+			},
+		}
+		Expect(cluster.validateResources()).To(HaveLen(1))
+
+		cluster.Spec.Resources.Limits[v1.ResourceCPU] = resource.MustParse("1")
+		Expect(cluster.validateResources()).To(BeEmpty())
+	})
+
+	It("rejects requests different from the limits with the Guaranteed QoS class", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				GuaranteedQoS: true,
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{
+						v1.ResourceCPU:    resource.MustParse("1"),
+						v1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+					Requests: v1.ResourceList{
+						v1.ResourceCPU: resource.MustParse("500m"),
+					},
+				},
+			},
+		}
+		Expect(cluster.validateResources()).To(HaveLen(1))
+	})
+
+	It("requires the memory limit for the memory tuning", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					MemoryTuning: true,
+				},
+			},
+		}
+		Expect(cluster.validateResources()).To(HaveLen(1))
+
+		cluster.Spec.Resources.Limits = v1.ResourceList{
+			v1.ResourceMemory: resource.MustParse("1Gi"),
+		}
+		Expect(cluster.validateResources()).To(BeEmpty())
+	})
+
+	It("defaults the requests to the limits with the Guaranteed QoS class", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				GuaranteedQoS: true,
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{
+						v1.ResourceCPU:    resource.MustParse("1"),
+						v1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				},
+			},
+		}
+		cluster.defaultGuaranteedQoSRequests()
+		Expect(cluster.Spec.Resources.Requests).To(Equal(cluster.Spec.Resources.Limits))
+	})
+})
+
+var _ = Describe("Huge pages validation", func() {
+	It("accepts huge pages able to contain the shared buffers", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"shared_buffers": "256MB"},
+				},
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{"hugepages-2Mi": resource.MustParse("512Mi")},
+				},
+			},
+		}
+		Expect(cluster.validateHugePages()).To(BeEmpty())
+	})
+
+	It("rejects huge_pages=on without huge pages", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"huge_pages": "on"},
+				},
+			},
+		}
+		Expect(cluster.validateHugePages()).To(HaveLen(1))
+	})
+
+	It("rejects huge_pages=off with huge pages", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"huge_pages": "off"},
+				},
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{"hugepages-2Mi": resource.MustParse("512Mi")},
+				},
+			},
+		}
+		Expect(cluster.validateHugePages()).To(HaveLen(1))
+	})
+
+	It("rejects multiple huge page sizes", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{
+						"hugepages-2Mi": resource.MustParse("512Mi"),
+						"hugepages-1Gi": resource.MustParse("1Gi"),
+					},
+				},
+			},
+		}
+		Expect(cluster.validateHugePages()).To(HaveLen(1))
+	})
+
+	It("rejects a huge_page_size not matching the requested huge pages", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"huge_page_size": "1GB"},
+				},
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{"hugepages-2Mi": resource.MustParse("512Mi")},
+				},
+			},
+		}
+		Expect(cluster.validateHugePages()).To(HaveLen(1))
+
+		cluster.Spec.PostgresConfiguration.Parameters["huge_page_size"] = "2MB"
+		Expect(cluster.validateHugePages()).To(BeEmpty())
+	})
+
+	It("rejects huge pages not able to contain the shared buffers", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"shared_buffers": "65536"},
+				},
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{"hugepages-2Mi": resource.MustParse("256Mi")},
+				},
+			},
+		}
+		Expect(cluster.validateHugePages()).To(HaveLen(1))
+
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{"hugepages-2Mi": resource.MustParse("64Mi")},
+				},
+			},
+		}
+		Expect(cluster.validateHugePages()).To(HaveLen(1))
+	})
+
+	It("rejects huge pages not able to contain the other shared memory consumers", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"shared_buffers": "256MB"},
+				},
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{"hugepages-2Mi": resource.MustParse("264Mi")},
+				},
+			},
+		}
+		Expect(cluster.validateHugePages()).To(HaveLen(1))
+
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"shared_buffers": "256MB", "min_dynamic_shared_memory": "512"},
+				},
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{"hugepages-2Mi": resource.MustParse("512Mi")},
+				},
+			},
+		}
+		Expect(cluster.validateHugePages()).To(HaveLen(1))
+
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"shared_buffers": "256MB", "max_connections": "1000", "max_locks_per_transaction": "1024"},
+				},
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{"hugepages-2Mi": resource.MustParse("512Mi")},
+				},
+			},
+		}
+		Expect(cluster.validateHugePages()).To(HaveLen(1))
+	})
+
+	It("estimates the shared memory of PostgreSQL", func() {
+		size, err := estimateSharedMemorySize(map[string]string{"shared_buffers": "128MB"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(size).To(BeNumerically(">", 128*1024*1024+4*1024*1024))
+		Expect(size).To(BeNumerically("<", 140*1024*1024))
+
+		_, err = estimateSharedMemorySize(map[string]string{"max_connections": "many"})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("sets huge_pages to on only in the new clusters requesting huge pages", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{"hugepages-2Mi": resource.MustParse("512Mi")},
+				},
+			},
+		}
+		cluster.defaultHugePages()
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).To(HaveKeyWithValue("huge_pages", "on"))
+
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"huge_pages": "try"},
+				},
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{"hugepages-2Mi": resource.MustParse("512Mi")},
+				},
+			},
+		}
+		cluster.defaultHugePages()
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).To(HaveKeyWithValue("huge_pages", "try"))
+
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				Resources: v1.ResourceRequirements{
+					Limits: v1.ResourceList{"hugepages-2Mi": resource.MustParse("512Mi")},
+				},
+			},
+		}
+		cluster.CreationTimestamp = metav1.Now()
+		cluster.defaultHugePages()
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).ToNot(HaveKey("huge_pages"))
+
+		cluster = &Cluster{}
+		cluster.defaultHugePages()
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).ToNot(HaveKey("huge_pages"))
+	})
+})
+
+var _ = Describe("Recovery and Backup Target", func() {
+	cluster := &Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "one",
+		},
+		Spec: ClusterSpec{
+			Bootstrap: &BootstrapConfiguration{
+				Recovery: &BootstrapRecovery{
+					Source: "one",
+				},
+			},
+			Backup: &BackupConfiguration{
+				BarmanObjectStore: &BarmanObjectStoreConfiguration{
+					BarmanCredentials: BarmanCredentials{
+						AWS: &S3Credentials{
+							AccessKeyIDReference: &SecretKeySelector{
+								LocalObjectReference: LocalObjectReference{
+									Name: "s3-creds",
+								},
+								Key: "access_key",
+							},
+							SecretAccessKeyReference: &SecretKeySelector{
 								LocalObjectReference: LocalObjectReference{
 									Name: "s3-creds",
 								},
@@ -2115,6 +3207,22 @@ var _ = Describe("Recovery and Backup Target", func() {
 		cluster.Spec.Backup.BarmanObjectStore.DestinationPath = "/destination/new/path"
 		result := cluster.validateRecoveryAndBackupTarget()
 		Expect(result).To(BeEmpty())
+		cluster.Spec.Backup.BarmanObjectStore.DestinationPath = "/destination/path"
+	})
+
+	It("does not complain if the cluster has a different name than the source", func() {
+		newCluster := cluster.DeepCopy()
+		newCluster.Name = "two"
+		result := newCluster.validateRecoveryAndBackupTarget()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("complains if the server name of the target backup is the one of the source", func() {
+		newCluster := cluster.DeepCopy()
+		newCluster.Name = "two"
+		newCluster.Spec.Backup.BarmanObjectStore.ServerName = "one"
+		result := newCluster.validateRecoveryAndBackupTarget()
+		Expect(result).NotTo(BeEmpty())
 	})
 })
 
@@ -2339,4 +3447,1303 @@ var _ = Describe("validation of imports", func() {
 		result := cluster.validateImport()
 		Expect(result).To(BeEmpty())
 	})
+
+	It("rejects imports from an undefined external cluster", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Import: &Import{
+							Type:      MicroserviceSnapshotType,
+							Databases: []string{"foo"},
+							Source:    ImportSource{ExternalCluster: "missing"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateBootstrapImportSource()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.import.source.externalCluster"))
+	})
+
+	It("rejects imports from an external cluster without connection parameters", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{"*"},
+							Source:    ImportSource{ExternalCluster: "origin"},
+						},
+					},
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name:              "origin",
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateBootstrapImportSource()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("accepts imports from a correctly defined external cluster", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{"*"},
+							Source:    ImportSource{ExternalCluster: "origin"},
+						},
+					},
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "origin",
+						ConnectionParameters: map[string]string{
+							"host": "pg.example.com",
+						},
+					},
+				},
+			},
+		}
+
+		Expect(cluster.validateBootstrapImportSource()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("readiness probe validation", func() {
+	It("accepts a maximum lag with the streaming type", func() {
+		maximumLag := resource.MustParse("16Mi")
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Probes: &ProbesConfiguration{
+					Readiness: &ReadinessProbe{
+						Type:       ReadinessProbeTypeStreaming,
+						MaximumLag: &maximumLag,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateReadinessProbe()).To(BeEmpty())
+	})
+
+	It("rejects a maximum lag without the streaming type", func() {
+		maximumLag := resource.MustParse("16Mi")
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Probes: &ProbesConfiguration{
+					Readiness: &ReadinessProbe{
+						MaximumLag: &maximumLag,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateReadinessProbe()).To(HaveLen(1))
+	})
+
+	It("rejects a maximum lag which is not positive", func() {
+		maximumLag := resource.MustParse("0")
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Probes: &ProbesConfiguration{
+					Readiness: &ReadinessProbe{
+						Type:       ReadinessProbeTypeStreaming,
+						MaximumLag: &maximumLag,
+					},
+				},
+			},
+		}
+		Expect(cluster.validateReadinessProbe()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("shared preload libraries validation", func() {
+	It("accepts valid library names", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					AdditionalLibraries: []string{"timescaledb", "$libdir/plugins/custom"},
+				},
+			},
+		}
+		Expect(cluster.validateSharedPreloadLibraries()).To(BeEmpty())
+	})
+
+	It("rejects empty, malformed and duplicated library names", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					AdditionalLibraries: []string{"", "first,second", "timescaledb", "timescaledb"},
+				},
+			},
+		}
+		Expect(cluster.validateSharedPreloadLibraries()).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("pgaudit validation", func() {
+	It("accepts the pgaudit section", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"work_mem": "8MB"},
+					PgAudit:    &PgAuditConfiguration{Log: []PgAuditLogClass{"ddl"}},
+				},
+			},
+		}
+		Expect(cluster.validatePgAudit()).To(BeEmpty())
+	})
+
+	It("rejects pgaudit parameters together with the pgaudit section", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"pgaudit.log": "all"},
+					PgAudit:    &PgAuditConfiguration{Log: []PgAuditLogClass{"ddl"}},
+				},
+			},
+		}
+		Expect(cluster.validatePgAudit()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("PostgreSQL logging validation", func() {
+	It("accepts the logging section", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"work_mem": "8MB"},
+					Logging:    &PostgresLoggingConfiguration{Preset: PostgresLoggingPresetVerbose},
+				},
+			},
+		}
+		Expect(cluster.validatePostgresLogging()).To(BeEmpty())
+	})
+
+	It("accepts the logging parameters without the logging section", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"log_statement": "all"},
+				},
+			},
+		}
+		Expect(cluster.validatePostgresLogging()).To(BeEmpty())
+	})
+
+	It("rejects the managed parameters together with the logging section", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"log_statement":   "all",
+						"log_connections": "on",
+						"work_mem":        "8MB",
+					},
+					Logging: &PostgresLoggingConfiguration{Preset: PostgresLoggingPresetDefault},
+				},
+			},
+		}
+		Expect(cluster.validatePostgresLogging()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("standby configuration validation", func() {
+	It("accepts the standby section", func() {
+		feedback := true
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"work_mem": "8MB"},
+					Standby:    &StandbyConfiguration{HotStandbyFeedback: &feedback},
+				},
+			},
+		}
+		Expect(cluster.validateStandbyConfiguration()).To(BeEmpty())
+	})
+
+	It("rejects the managed parameters together with the standby section", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"hot_standby_feedback": "on"},
+					Standby:    &StandbyConfiguration{},
+				},
+			},
+		}
+		Expect(cluster.validateStandbyConfiguration()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("connections configuration validation", func() {
+	maxConnections := int32(100)
+	reserved := int32(10)
+
+	It("accepts the connections section", func() {
+		cluster := &Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			Connections: &ConnectionsConfiguration{
+				MaxConnections:               &maxConnections,
+				SuperuserReservedConnections: &reserved,
+			},
+		}}}
+		Expect(cluster.validateConnectionsConfiguration()).To(BeEmpty())
+	})
+
+	It("rejects the managed parameters together with the connections section", func() {
+		cluster := &Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			Parameters:  map[string]string{"max_connections": "200"},
+			Connections: &ConnectionsConfiguration{},
+		}}}
+		Expect(cluster.validateConnectionsConfiguration()).To(HaveLen(1))
+	})
+
+	It("rejects reserving all the connections to the superusers", func() {
+		cluster := &Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			Connections: &ConnectionsConfiguration{
+				MaxConnections:               &reserved,
+				SuperuserReservedConnections: &reserved,
+			},
+		}}}
+		Expect(cluster.validateConnectionsConfiguration()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("locale configuration validation", func() {
+	It("accepts a valid locale section", func() {
+		cluster := &Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			Locale: &LocaleConfiguration{
+				TimeZone:    "America/New_York",
+				LogTimeZone: "UTC",
+				Messages:    "C",
+				Monetary:    "en_US.UTF-8",
+				Numeric:     "de_DE.utf8",
+				Time:        "sr_RS.UTF-8@latin",
+				DateStyle:   "iso, mdy",
+			},
+		}}}
+		Expect(cluster.validateLocaleConfiguration()).To(BeEmpty())
+	})
+
+	It("rejects the managed parameters together with the locale section", func() {
+		cluster := &Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			Parameters: map[string]string{"timezone": "UTC"},
+			Locale:     &LocaleConfiguration{},
+		}}}
+		Expect(cluster.validateLocaleConfiguration()).To(HaveLen(1))
+	})
+
+	It("accepts the time zone names and the POSIX-style specifications", func() {
+		for _, timeZone := range []string{
+			"Etc/GMT+3", "America/Argentina/Buenos_Aires", "EST5EDT", "<+03>-3", "CET-1CEST,M3.5.0,M10.5.0/3",
+		} {
+			cluster := &Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+				Locale: &LocaleConfiguration{TimeZone: timeZone},
+			}}}
+			Expect(cluster.validateLocaleConfiguration()).To(BeEmpty(), timeZone)
+		}
+	})
+
+	It("rejects invalid time zones", func() {
+		cluster := &Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			Locale: &LocaleConfiguration{TimeZone: "Europe/Rome'; DROP", LogTimeZone: "../etc/passwd"},
+		}}}
+		Expect(cluster.validateLocaleConfiguration()).To(HaveLen(2))
+
+		cluster = &Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			Locale: &LocaleConfiguration{TimeZone: "<+03-3", LogTimeZone: "Europe//Rome"},
+		}}}
+		Expect(cluster.validateLocaleConfiguration()).To(HaveLen(2))
+	})
+
+	It("rejects invalid locale names", func() {
+		cluster := &Cluster{Spec: ClusterSpec{PostgresConfiguration: PostgresConfiguration{
+			Locale: &LocaleConfiguration{Messages: "en_US.UTF-8'; DROP", Time: "it IT"},
+		}}}
+		Expect(cluster.validateLocaleConfiguration()).To(HaveLen(2))
+	})
+
+	It("rejects invalid date styles", func() {
+		Expect(validateDateStyle("ISO")).To(Succeed())
+		Expect(validateDateStyle("German, DMY")).To(Succeed())
+		Expect(validateDateStyle("ISO, SQL")).ToNot(Succeed())
+		Expect(validateDateStyle("DMY, MDY")).ToNot(Succeed())
+		Expect(validateDateStyle("ISO, Julian")).ToNot(Succeed())
+	})
+})
+
+var _ = Describe("managed roles validation", func() {
+	It("rejects a role managed twice", func() {
+		cluster := &Cluster{Spec: ClusterSpec{Managed: &ManagedConfiguration{
+			Roles: []ManagedRole{{Name: "app"}, {Name: "reporting"}},
+		}}}
+		Expect(cluster.validateManagedRoles()).To(BeEmpty())
+
+		cluster.Spec.Managed.Roles = append(cluster.Spec.Managed.Roles, ManagedRole{Name: "app"})
+		Expect(cluster.validateManagedRoles()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("generated objects validation", func() {
+	It("accepts a template with both placeholders", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				GeneratedObjects: &GeneratedObjectsConfiguration{SecretNameTemplate: "pg-{cluster}-{secret}"},
+			},
+		}
+		Expect(cluster.validateGeneratedObjects()).To(BeEmpty())
+	})
+
+	It("rejects a template missing a placeholder", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				GeneratedObjects: &GeneratedObjectsConfiguration{SecretNameTemplate: "pg-{cluster}"},
+			},
+		}
+		Expect(cluster.validateGeneratedObjects()).To(HaveLen(1))
+		cluster = &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				GeneratedObjects: &GeneratedObjectsConfiguration{SecretNameTemplate: "pg"},
+			},
+		}
+		Expect(cluster.validateGeneratedObjects()).To(HaveLen(2))
+	})
+
+	It("rejects a template generating invalid names", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				GeneratedObjects: &GeneratedObjectsConfiguration{SecretNameTemplate: "PG_{cluster}_{secret}"},
+			},
+		}
+		Expect(cluster.validateGeneratedObjects()).To(HaveLen(2))
+	})
+
+	It("rejects changing the template", func() {
+		oldCluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				GeneratedObjects: &GeneratedObjectsConfiguration{},
+			},
+		}
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				GeneratedObjects: &GeneratedObjectsConfiguration{},
+			},
+		}
+		Expect(cluster.validateSecretNameTemplateChange(oldCluster)).To(BeEmpty())
+		cluster = &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				GeneratedObjects: &GeneratedObjectsConfiguration{SecretNameTemplate: "{cluster}-{secret}"},
+			},
+		}
+		Expect(cluster.validateSecretNameTemplateChange(oldCluster)).To(BeEmpty())
+		cluster = &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				GeneratedObjects: &GeneratedObjectsConfiguration{SecretNameTemplate: "pg-{cluster}-{secret}"},
+			},
+		}
+		Expect(cluster.validateSecretNameTemplateChange(oldCluster)).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("PVC retention policy validation", func() {
+	policy := &PVCRetentionPolicy{WhenDeleted: PVCRetentionPolicyRetain}
+
+	It("accepts the policy in the storage section", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			StorageConfiguration: StorageConfiguration{PersistentVolumeClaimRetentionPolicy: policy},
+			WalStorage:           &StorageConfiguration{Size: "1Gi"},
+		}}
+		Expect(cluster.validatePVCRetentionPolicy()).To(BeEmpty())
+	})
+
+	It("rejects the policy in the WAL storage section", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			WalStorage: &StorageConfiguration{Size: "1Gi", PersistentVolumeClaimRetentionPolicy: policy},
+		}}
+		Expect(cluster.validatePVCRetentionPolicy()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("disk space validation", func() {
+	It("accepts the default thresholds", func() {
+		cluster := &Cluster{Spec: ClusterSpec{DiskSpace: &DiskSpaceConfiguration{CheckpointOnCritical: true}}}
+		Expect(cluster.validateDiskSpace()).To(BeEmpty())
+	})
+
+	It("rejects a warning threshold which is not lower than the critical one", func() {
+		cluster := &Cluster{Spec: ClusterSpec{DiskSpace: &DiskSpaceConfiguration{
+			WarningThreshold:  90,
+			CriticalThreshold: 90,
+		}}}
+		Expect(cluster.validateDiskSpace()).To(HaveLen(1))
+	})
+
+	It("compares the warning threshold with the default critical one", func() {
+		cluster := &Cluster{Spec: ClusterSpec{DiskSpace: &DiskSpaceConfiguration{WarningThreshold: 97}}}
+		Expect(cluster.validateDiskSpace()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("image catalog validation", func() {
+	It("accepts a reference to an image catalog", func() {
+		cluster := &Cluster{Spec: ClusterSpec{ImageCatalogRef: &ImageCatalogRef{
+			TypedLocalObjectReference: v1.TypedLocalObjectReference{
+				Kind: ClusterImageCatalogKind,
+				Name: "catalog",
+			},
+			Major: 15,
+		}}}
+		Expect(cluster.validateImageCatalogRef()).To(BeEmpty())
+	})
+
+	It("rejects the image name together with the image catalog", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			ImageName: "ghcr.io/cloudnative-pg/postgresql:15.4",
+			ImageCatalogRef: &ImageCatalogRef{
+				TypedLocalObjectReference: v1.TypedLocalObjectReference{
+					Kind: ImageCatalogKind,
+					Name: "catalog",
+				},
+				Major: 15,
+			},
+		}}
+		Expect(cluster.validateImageCatalogRef()).To(HaveLen(1))
+	})
+
+	It("doesn't default the image name of a cluster using an image catalog", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				Instances: 3,
+				ImageCatalogRef: &ImageCatalogRef{
+					TypedLocalObjectReference: v1.TypedLocalObjectReference{
+						Kind: ClusterImageCatalogKind,
+						Name: "catalog",
+					},
+					Major: 15,
+				},
+			},
+		}
+		cluster.Default()
+		Expect(cluster.Spec.ImageName).To(BeEmpty())
+		Expect(cluster.Validate()).To(BeEmpty())
+	})
+
+	It("rejects an invalid kind or API group", func() {
+		apiGroup := "example.com"
+		catalogRef := &ImageCatalogRef{
+			TypedLocalObjectReference: v1.TypedLocalObjectReference{
+				Kind: "ConfigMap",
+				Name: "catalog",
+			},
+			Major: 15,
+		}
+		catalogRef.APIGroup = &apiGroup
+		cluster := &Cluster{Spec: ClusterSpec{ImageCatalogRef: catalogRef}}
+		Expect(cluster.validateImageCatalogRef()).To(HaveLen(2))
+	})
+
+	It("rejects a change of the major version in the catalog", func() {
+		oldCluster := &Cluster{Spec: ClusterSpec{ImageCatalogRef: &ImageCatalogRef{
+			TypedLocalObjectReference: v1.TypedLocalObjectReference{
+				Kind: ImageCatalogKind,
+				Name: "catalog",
+			},
+			Major: 14,
+		}}}
+		cluster := &Cluster{Spec: ClusterSpec{ImageCatalogRef: &ImageCatalogRef{
+			TypedLocalObjectReference: v1.TypedLocalObjectReference{
+				Kind: ImageCatalogKind,
+				Name: "catalog",
+			},
+			Major: 15,
+		}}}
+		Expect(cluster.validateImageCatalogChange(oldCluster)).To(HaveLen(1))
+	})
+
+	It("allows to switch from the image name to a catalog with the same major version", func() {
+		oldCluster := &Cluster{Spec: ClusterSpec{ImageName: "ghcr.io/cloudnative-pg/postgresql:15.2"}}
+		cluster := &Cluster{Spec: ClusterSpec{ImageCatalogRef: &ImageCatalogRef{
+			TypedLocalObjectReference: v1.TypedLocalObjectReference{
+				Kind: ClusterImageCatalogKind,
+				Name: "catalog",
+			},
+			Major: 15,
+		}}}
+		Expect(cluster.validateImageCatalogChange(oldCluster)).To(BeEmpty())
+		Expect(oldCluster.validateImageCatalogChange(cluster)).To(BeEmpty())
+
+		oldCluster.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:14.7"
+		Expect(cluster.validateImageCatalogChange(oldCluster)).To(HaveLen(1))
+		Expect(oldCluster.validateImageCatalogChange(cluster)).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("image update policy validation", func() {
+	It("accepts valid maintenance windows", func() {
+		cluster := &Cluster{Spec: ClusterSpec{ImageUpdate: &ImageUpdateConfiguration{
+			MaintenanceWindows: []MaintenanceWindow{
+				{StartTime: "22:00", Duration: metav1.Duration{Duration: 4 * time.Hour}},
+			},
+		}}}
+		Expect(cluster.validateImageUpdate()).To(BeEmpty())
+	})
+
+	It("rejects invalid start times and durations", func() {
+		cluster := &Cluster{Spec: ClusterSpec{ImageUpdate: &ImageUpdateConfiguration{
+			MaintenanceWindows: []MaintenanceWindow{
+				{StartTime: "25:00", Duration: metav1.Duration{Duration: time.Hour}},
+				{StartTime: "22:00"},
+				{StartTime: "22:00", Duration: metav1.Duration{Duration: 8 * 24 * time.Hour}},
+			},
+		}}}
+		Expect(cluster.validateImageUpdate()).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("primary update windows validation", func() {
+	It("accepts a cluster without windows", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validatePrimaryUpdateWindows()).To(BeEmpty())
+	})
+
+	It("rejects invalid windows", func() {
+		cluster := &Cluster{Spec: ClusterSpec{PrimaryUpdateWindows: []MaintenanceWindow{
+			{StartTime: "02:00", Duration: metav1.Duration{Duration: time.Hour}},
+			{StartTime: "24:00", Duration: metav1.Duration{Duration: time.Hour}},
+		}}}
+		result := cluster.validatePrimaryUpdateWindows()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.primaryUpdateWindows[1].startTime"))
+	})
+})
+
+var _ = Describe("drift detection validation", func() {
+	It("accepts the default configuration", func() {
+		cluster := &Cluster{Spec: ClusterSpec{DriftDetection: &DriftDetectionConfiguration{}}}
+		Expect(cluster.validateDriftDetection()).To(BeEmpty())
+	})
+
+	It("rejects too short intervals", func() {
+		cluster := &Cluster{Spec: ClusterSpec{DriftDetection: &DriftDetectionConfiguration{
+			Interval: &metav1.Duration{Duration: 10 * time.Second},
+		}}}
+		Expect(cluster.validateDriftDetection()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("instance health validation", func() {
+	It("accepts a positive maximum lag", func() {
+		maximumLag := resource.MustParse("1Gi")
+		cluster := &Cluster{Spec: ClusterSpec{InstanceHealth: &InstanceHealthConfiguration{
+			MaximumLag: &maximumLag,
+		}}}
+		Expect(cluster.validateInstanceHealth()).To(BeEmpty())
+	})
+
+	It("rejects a maximum lag which is not positive", func() {
+		maximumLag := resource.MustParse("0")
+		cluster := &Cluster{Spec: ClusterSpec{InstanceHealth: &InstanceHealthConfiguration{
+			MaximumLag: &maximumLag,
+		}}}
+		Expect(cluster.validateInstanceHealth()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("primary heartbeat validation", func() {
+	It("accepts a disabled heartbeat", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validatePrimaryHeartbeat()).To(BeEmpty())
+	})
+
+	It("accepts a timeout long enough to renew the lease", func() {
+		cluster := &Cluster{Spec: ClusterSpec{PrimaryHeartbeatTimeout: 30}}
+		Expect(cluster.validatePrimaryHeartbeat()).To(BeEmpty())
+	})
+
+	It("rejects a timeout that is too short", func() {
+		cluster := &Cluster{Spec: ClusterSpec{PrimaryHeartbeatTimeout: 5}}
+		Expect(cluster.validatePrimaryHeartbeat()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("metrics endpoint security validation", func() {
+	It("accepts a cluster without monitoring configuration", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateMonitoring()).To(BeEmpty())
+	})
+
+	It("accepts TLS with client certificates and a bearer token", func() {
+		cluster := &Cluster{Spec: ClusterSpec{Monitoring: &MonitoringConfiguration{
+			TLSConfig: &ClusterMonitoringTLSConfiguration{Enabled: true, RequireClientCertificate: true},
+			BearerTokenSecret: &SecretKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: "metrics-token"},
+				Key:                  "token",
+			},
+		}}}
+		Expect(cluster.validateMonitoring()).To(BeEmpty())
+		Expect(cluster.Spec.Monitoring.IsTLSEnabled()).To(BeTrue())
+		Expect(cluster.Spec.Monitoring.IsClientCertificateRequired()).To(BeTrue())
+	})
+
+	It("rejects client certificates without TLS", func() {
+		cluster := &Cluster{Spec: ClusterSpec{Monitoring: &MonitoringConfiguration{
+			TLSConfig: &ClusterMonitoringTLSConfiguration{RequireClientCertificate: true},
+		}}}
+		Expect(cluster.validateMonitoring()).To(HaveLen(1))
+		Expect(cluster.Spec.Monitoring.IsClientCertificateRequired()).To(BeFalse())
+	})
+
+	It("rejects a bearer token secret without a key", func() {
+		cluster := &Cluster{Spec: ClusterSpec{Monitoring: &MonitoringConfiguration{
+			BearerTokenSecret: &SecretKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: "metrics-token"},
+			},
+		}}}
+		Expect(cluster.validateMonitoring()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("monitoring database discovery validation", func() {
+	It("accepts valid patterns", func() {
+		cluster := &Cluster{Spec: ClusterSpec{Monitoring: &MonitoringConfiguration{
+			DatabaseDiscovery: &MonitoringDatabaseDiscovery{
+				Include: []string{"app*", "reporting"},
+				Exclude: []string{"*_test"},
+			},
+		}}}
+		Expect(cluster.validateMonitoring()).To(BeEmpty())
+	})
+
+	It("rejects malformed patterns", func() {
+		cluster := &Cluster{Spec: ClusterSpec{Monitoring: &MonitoringConfiguration{
+			DatabaseDiscovery: &MonitoringDatabaseDiscovery{
+				Include: []string{"app["},
+				Exclude: []string{"[-"},
+			},
+		}}}
+		Expect(cluster.validateMonitoring()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("data verification validation", func() {
+	It("accepts a cluster without data verification", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateDataVerification()).To(BeEmpty())
+	})
+
+	It("accepts a valid schedule", func() {
+		cluster := &Cluster{Spec: ClusterSpec{DataVerification: &DataVerificationConfiguration{
+			Schedule: "0 0 3 * * 0",
+		}}}
+		Expect(cluster.validateDataVerification()).To(BeEmpty())
+		Expect(cluster.Spec.DataVerification.GetMethod()).To(Equal(DataVerificationMethodAmcheck))
+	})
+
+	It("rejects an invalid schedule", func() {
+		cluster := &Cluster{Spec: ClusterSpec{DataVerification: &DataVerificationConfiguration{
+			Schedule: "every sunday",
+			Method:   DataVerificationMethodChecksums,
+		}}}
+		Expect(cluster.validateDataVerification()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("managed extensions validation", func() {
+	It("accepts extensions managed in different databases", func() {
+		cluster := &Cluster{Spec: ClusterSpec{Managed: &ManagedConfiguration{
+			Extensions: []ManagedExtension{
+				{Name: "postgis", Database: "app"},
+				{Name: "postgis", Database: "reporting"},
+				{Name: "hstore", Database: "app"},
+			},
+		}}}
+		Expect(cluster.validateManagedExtensions()).To(BeEmpty())
+	})
+
+	It("rejects an extension managed twice in the same database", func() {
+		cluster := &Cluster{Spec: ClusterSpec{Managed: &ManagedConfiguration{
+			Extensions: []ManagedExtension{
+				{Name: "postgis", Database: "app"},
+				{Name: "postgis", Database: "app", Version: "3.2.1"},
+			},
+		}}}
+		Expect(cluster.validateManagedExtensions()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("extension images validation", func() {
+	It("accepts extension images with unique names", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			ImageName: "ghcr.io/cloudnative-pg/postgresql:16.2",
+			PostgresConfiguration: PostgresConfiguration{ExtensionImages: []ExtensionImage{
+				{Name: "pgvector", Image: "ghcr.io/example/pgvector:0.6.0"},
+				{Name: "postgis", Image: "ghcr.io/example/postgis:3.4"},
+			}},
+		}}
+		Expect(cluster.validateExtensionImages()).To(BeEmpty())
+	})
+
+	It("rejects extension images with the same name", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			ImageName: "ghcr.io/cloudnative-pg/postgresql:16.2",
+			PostgresConfiguration: PostgresConfiguration{ExtensionImages: []ExtensionImage{
+				{Name: "pgvector", Image: "ghcr.io/example/pgvector:0.6.0"},
+				{Name: "pgvector", Image: "ghcr.io/example/pgvector:0.7.0"},
+			}},
+		}}
+		Expect(cluster.validateExtensionImages()).To(HaveLen(1))
+	})
+
+	It("rejects extension images when the major version of the image is unknown", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			ImageName: "ghcr.io/cloudnative-pg/postgresql:latest",
+			PostgresConfiguration: PostgresConfiguration{ExtensionImages: []ExtensionImage{
+				{Name: "pgvector", Image: "ghcr.io/example/pgvector:0.6.0"},
+			}},
+		}}
+		Expect(cluster.validateExtensionImages()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("flavor change validation", func() {
+	It("rejects changing the flavor of PostgreSQL", func() {
+		oldCluster := &Cluster{Spec: ClusterSpec{ImageName: "ghcr.io/cloudnative-pg/postgresql:16.2"}}
+		cluster := &Cluster{Spec: ClusterSpec{ImageName: "quay.io/enterprisedb/edb-postgres-advanced:16.2"}}
+		Expect(cluster.validateFlavorChange(oldCluster)).To(HaveLen(1))
+	})
+
+	It("accepts setting the detected flavor explicitly", func() {
+		oldCluster := &Cluster{Spec: ClusterSpec{ImageName: "quay.io/enterprisedb/edb-postgres-advanced:16.2"}}
+		cluster := oldCluster.DeepCopy()
+		cluster.Spec.PostgresConfiguration.Flavor = postgres.FlavorEPAS
+		Expect(cluster.validateFlavorChange(oldCluster)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Transparent Data Encryption validation", func() {
+	passphraseSecret := &SecretKeySelector{
+		LocalObjectReference: LocalObjectReference{Name: "tde-passphrase"},
+		Key:                  "passphrase",
+	}
+
+	It("accepts a passphrase with a supported image", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			ImageName: "quay.io/enterprisedb/edb-postgres-advanced:16",
+			PostgresConfiguration: PostgresConfiguration{TDE: &TDEConfiguration{
+				Enabled:          true,
+				PassphraseSecret: passphraseSecret,
+			}},
+		}}
+		Expect(cluster.validateTDE()).To(BeEmpty())
+	})
+
+	It("rejects the images not supporting it", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			ImageName: "ghcr.io/cloudnative-pg/postgresql:16.2",
+			PostgresConfiguration: PostgresConfiguration{TDE: &TDEConfiguration{
+				Enabled:          true,
+				PassphraseSecret: passphraseSecret,
+			}},
+		}}
+		Expect(cluster.validateTDE()).To(HaveLen(1))
+
+		cluster.Spec.ImageName = "quay.io/enterprisedb/edb-postgres-advanced:14"
+		Expect(cluster.validateTDE()).To(HaveLen(1))
+	})
+
+	It("requires either the passphrase or both the commands", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			ImageName: "quay.io/enterprisedb/edb-postgres-advanced:16",
+			PostgresConfiguration: PostgresConfiguration{TDE: &TDEConfiguration{
+				Enabled: true,
+			}},
+		}}
+		Expect(cluster.validateTDE()).To(HaveLen(1))
+
+		cluster.Spec.PostgresConfiguration.TDE.WrapCommand = "kms-wrap --output %p"
+		Expect(cluster.validateTDE()).To(HaveLen(1))
+
+		cluster.Spec.PostgresConfiguration.TDE.UnwrapCommand = "kms-unwrap --input %p"
+		Expect(cluster.validateTDE()).To(BeEmpty())
+
+		cluster.Spec.PostgresConfiguration.TDE.PassphraseSecret = passphraseSecret
+		Expect(cluster.validateTDE()).To(HaveLen(1))
+	})
+
+	It("rejects enabling it after the creation of the cluster", func() {
+		oldCluster := &Cluster{Spec: ClusterSpec{ImageName: "quay.io/enterprisedb/edb-postgres-advanced:16"}}
+		cluster := oldCluster.DeepCopy()
+		cluster.Spec.PostgresConfiguration.TDE = &TDEConfiguration{
+			Enabled:          true,
+			PassphraseSecret: passphraseSecret,
+		}
+		Expect(cluster.validateTDEChange(oldCluster)).To(HaveLen(1))
+		Expect(cluster.validateTDEChange(cluster)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("synchronous replica election constraints validation", func() {
+	It("accepts disabled constraints", func() {
+		Expect(validateSyncReplicaElectionConstraint(SyncReplicaElectionConstraints{})).To(BeNil())
+	})
+
+	It("requires the labels to be compared", func() {
+		Expect(validateSyncReplicaElectionConstraint(SyncReplicaElectionConstraints{
+			Enabled: true,
+		})).ToNot(BeNil())
+		Expect(validateSyncReplicaElectionConstraint(SyncReplicaElectionConstraints{
+			Enabled:                true,
+			NodeLabelsAntiAffinity: []string{"rack"},
+		})).To(BeNil())
+	})
+
+	It("accepts the zone anti-affinity without additional labels", func() {
+		Expect(validateSyncReplicaElectionConstraint(SyncReplicaElectionConstraints{
+			Enabled:          true,
+			ZoneAntiAffinity: true,
+		})).To(BeNil())
+	})
+})
+
+var _ = Describe("priority class names validation", func() {
+	It("accepts valid names", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			PriorityClassName:        "database",
+			PrimaryPriorityClassName: "database.primary",
+		}}
+		Expect(cluster.validatePriorityClassNames()).To(BeEmpty())
+		cluster = &Cluster{}
+		Expect(cluster.validatePriorityClassNames()).To(BeEmpty())
+	})
+
+	It("rejects invalid names", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			PriorityClassName:        "Database",
+			PrimaryPriorityClassName: "database_primary",
+		}}
+		Expect(cluster.validatePriorityClassNames()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("chaos experiment validation", func() {
+	experiment := utils.ChaosExperiment{
+		Action:    utils.ChaosExperimentKillPostgres,
+		Instance:  "cluster-example-1",
+		StartedAt: metav1.Now(),
+		Duration:  metav1.Duration{Duration: time.Minute},
+	}
+
+	It("accepts clusters without experiments", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateChaosExperiment()).To(BeEmpty())
+	})
+
+	It("requires chaos testing to be enabled", func() {
+		cluster := &Cluster{}
+		Expect(utils.SetChaosExperiment(&cluster.ObjectMeta, &experiment)).To(Succeed())
+		Expect(cluster.validateChaosExperiment()).To(HaveLen(1))
+
+		cluster.Spec.EnableChaosTesting = true
+		Expect(cluster.validateChaosExperiment()).To(BeEmpty())
+	})
+
+	It("rejects invalid experiments", func() {
+		cluster := &Cluster{Spec: ClusterSpec{EnableChaosTesting: true}}
+		invalidExperiment := experiment
+		invalidExperiment.Action = "fillDisk"
+		Expect(utils.SetChaosExperiment(&cluster.ObjectMeta, &invalidExperiment)).To(Succeed())
+		Expect(cluster.validateChaosExperiment()).To(HaveLen(1))
+
+		cluster.Annotations[utils.ChaosExperimentAnnotationName] = "{"
+		Expect(cluster.validateChaosExperiment()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("recovery remapping validation", func() {
+	It("accepts recoveries without remapping", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Backup: &BackupSource{LocalObjectReference: LocalObjectReference{Name: "backup"}},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateRecoveryRemapping()).To(BeEmpty())
+	})
+
+	It("accepts a valid remapping", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Backup: &BackupSource{LocalObjectReference: LocalObjectReference{Name: "backup"}},
+						Remap: &RecoveryRemapping{
+							Secrets:         map[string]string{"prod-aws": "staging-aws"},
+							DestinationPath: "s3://staging/",
+							ServerName:      "cluster-prod",
+							Roles:           map[string]string{"prod_app": "app", "prod_reader": "reader"},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateRecoveryRemapping()).To(BeEmpty())
+	})
+
+	It("remaps the object store only when recovering from a backup object", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Backup: &BackupSource{LocalObjectReference: LocalObjectReference{Name: "backup"}},
+						Remap:  &RecoveryRemapping{DestinationPath: "s3://staging/"},
+					},
+				},
+			},
+		}
+		cluster.Spec.Bootstrap.Recovery.Backup = nil
+		cluster.Spec.Bootstrap.Recovery.Source = "cluster-prod"
+		Expect(cluster.validateRecoveryRemapping()).To(HaveLen(1))
+
+		cluster.Spec.Bootstrap.Recovery.Remap = &RecoveryRemapping{Roles: map[string]string{"prod_app": "app"}}
+		Expect(cluster.validateRecoveryRemapping()).To(BeEmpty())
+	})
+
+	It("rejects empty names", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Backup: &BackupSource{LocalObjectReference: LocalObjectReference{Name: "backup"}},
+						Remap: &RecoveryRemapping{
+							Secrets: map[string]string{"prod-aws": ""},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateRecoveryRemapping()).To(HaveLen(1))
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Backup: &BackupSource{LocalObjectReference: LocalObjectReference{Name: "backup"}},
+						Remap: &RecoveryRemapping{
+							Roles: map[string]string{"": "app"},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateRecoveryRemapping()).To(HaveLen(1))
+	})
+
+	It("rejects renaming the roles managed by the operator", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Backup: &BackupSource{LocalObjectReference: LocalObjectReference{Name: "backup"}},
+						Remap: &RecoveryRemapping{
+							Roles: map[string]string{"postgres": "admin"},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateRecoveryRemapping()).To(HaveLen(1))
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Backup: &BackupSource{LocalObjectReference: LocalObjectReference{Name: "backup"}},
+						Remap: &RecoveryRemapping{
+							Roles: map[string]string{"replicator": StreamingReplicationUser},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateRecoveryRemapping()).To(HaveLen(1))
+	})
+
+	It("rejects renaming two roles with the same name", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Backup: &BackupSource{LocalObjectReference: LocalObjectReference{Name: "backup"}},
+						Remap: &RecoveryRemapping{
+							Roles: map[string]string{"prod_app": "app", "prod_owner": "app"},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateRecoveryRemapping()).To(HaveLen(1))
+	})
+
+	It("rejects chained and swapped renames", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Backup: &BackupSource{LocalObjectReference: LocalObjectReference{Name: "backup"}},
+						Remap: &RecoveryRemapping{
+							Roles: map[string]string{"prod_app": "app", "app": "legacy_app"},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateRecoveryRemapping()).To(HaveLen(1))
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Backup: &BackupSource{LocalObjectReference: LocalObjectReference{Name: "backup"}},
+						Remap: &RecoveryRemapping{
+							Roles: map[string]string{"reader": "writer", "writer": "reader"},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateRecoveryRemapping()).To(HaveLen(2))
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Backup: &BackupSource{LocalObjectReference: LocalObjectReference{Name: "backup"}},
+						Remap: &RecoveryRemapping{
+							Roles: map[string]string{"app": "app"},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateRecoveryRemapping()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("external-dns validation", func() {
+	It("accepts clusters without external-dns", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateExternalDNS()).To(BeEmpty())
+	})
+
+	It("accepts a valid configuration", func() {
+		cluster := &Cluster{Spec: ClusterSpec{ExternalDNS: &ExternalDNSConfiguration{
+			Hostname:                 "db.example.com",
+			LoadBalancerSourceRanges: []string{"10.0.0.0/8", "192.168.1.0/24"},
+		}}}
+		Expect(cluster.validateExternalDNS()).To(BeEmpty())
+	})
+
+	It("rejects invalid hostnames", func() {
+		cluster := &Cluster{Spec: ClusterSpec{ExternalDNS: &ExternalDNSConfiguration{
+			Hostname: "db_example.com",
+		}}}
+		Expect(cluster.validateExternalDNS()).To(HaveLen(1))
+	})
+
+	It("rejects source ranges which are not CIDRs", func() {
+		cluster := &Cluster{Spec: ClusterSpec{ExternalDNS: &ExternalDNSConfiguration{
+			Hostname:                 "db.example.com",
+			LoadBalancerSourceRanges: []string{"10.0.0.1", "10.0.0.0/8"},
+		}}}
+		Expect(cluster.validateExternalDNS()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("clone configuration validation", func() {
+	It("accepts a cluster without clone configurations", func() {
+		cluster := &Cluster{Spec: ClusterSpec{ImageName: "ghcr.io/cloudnative-pg/postgresql:16.2"}}
+		Expect(cluster.validateCloneConfigurations()).To(BeEmpty())
+	})
+
+	It("accepts a supported compression level", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			ImageName: "ghcr.io/cloudnative-pg/postgresql:16.2",
+			ReplicaClone: &CloneConfiguration{
+				Compression:      CloneCompressionZstd,
+				CompressionLevel: 19,
+				VerifyBackup:     true,
+			},
+		}}
+		Expect(cluster.validateCloneConfigurations()).To(BeEmpty())
+	})
+
+	It("rejects a compression level without a compression method", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			ImageName:    "ghcr.io/cloudnative-pg/postgresql:16.2",
+			ReplicaClone: &CloneConfiguration{CompressionLevel: 5},
+		}}
+		Expect(cluster.validateCloneConfigurations()).To(HaveLen(1))
+	})
+
+	It("rejects a compression level not supported by the compression method", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			ImageName: "ghcr.io/cloudnative-pg/postgresql:16.2",
+			Bootstrap: &BootstrapConfiguration{PgBaseBackup: &BootstrapPgBaseBackup{
+				Source: "source",
+				Clone: &CloneConfiguration{
+					Compression:      CloneCompressionGzip,
+					CompressionLevel: 12,
+				},
+			}},
+		}}
+		result := cluster.validateCloneConfigurations()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.pg_basebackup.clone.compressionLevel"))
+	})
+
+	It("rejects the options not supported by the PostgreSQL version", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			ImageName: "ghcr.io/cloudnative-pg/postgresql:12.16",
+			ReplicaClone: &CloneConfiguration{
+				Compression:  CloneCompressionLZ4,
+				VerifyBackup: true,
+			},
+		}}
+		Expect(cluster.validateCloneConfigurations()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("bootstrap change validation", func() {
+	It("accepts an unchanged bootstrap configuration", func() {
+		oldCluster := &Cluster{Spec: ClusterSpec{Bootstrap: &BootstrapConfiguration{
+			InitDB: &BootstrapInitDB{Database: "db"},
+		}}}
+		oldCluster.SetDefaults()
+		cluster := &Cluster{Spec: ClusterSpec{Bootstrap: &BootstrapConfiguration{
+			InitDB: &BootstrapInitDB{Database: "db"},
+		}}}
+		cluster.SetDefaults()
+		Expect(cluster.validateBootstrapChange(oldCluster)).To(BeEmpty())
+	})
+
+	It("accepts the removal of the bootstrap section", func() {
+		oldCluster := &Cluster{Spec: ClusterSpec{Bootstrap: &BootstrapConfiguration{
+			Recovery: &BootstrapRecovery{Source: "origin"},
+		}}}
+		oldCluster.SetDefaults()
+		cluster := &Cluster{}
+		cluster.SetDefaults()
+		Expect(cluster.validateBootstrapChange(oldCluster)).To(BeEmpty())
+	})
+
+	It("rejects a change of the bootstrap method", func() {
+		oldCluster := &Cluster{Spec: ClusterSpec{Bootstrap: &BootstrapConfiguration{
+			InitDB: &BootstrapInitDB{Database: "db"},
+		}}}
+		oldCluster.SetDefaults()
+		cluster := &Cluster{Spec: ClusterSpec{Bootstrap: &BootstrapConfiguration{
+			PgBaseBackup: &BootstrapPgBaseBackup{Source: "origin"},
+		}}}
+		cluster.SetDefaults()
+		result := cluster.validateBootstrapChange(oldCluster)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap"))
+	})
+
+	It("rejects a change of the WAL segment size", func() {
+		oldCluster := &Cluster{Spec: ClusterSpec{Bootstrap: &BootstrapConfiguration{
+			InitDB: &BootstrapInitDB{WalSegmentSize: 16},
+		}}}
+		oldCluster.SetDefaults()
+		cluster := &Cluster{Spec: ClusterSpec{Bootstrap: &BootstrapConfiguration{
+			InitDB: &BootstrapInitDB{WalSegmentSize: 64},
+		}}}
+		cluster.SetDefaults()
+		result := cluster.validateBootstrapChange(oldCluster)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.walSegmentSize"))
+	})
+})
+
+var _ = Describe("storage class change validation", func() {
+	standard := "standard"
+	premium := "premium"
+	resizeInUseVolumes := false
+
+	It("accepts an unchanged storage class", func() {
+		oldCluster := &Cluster{Spec: ClusterSpec{StorageConfiguration: StorageConfiguration{StorageClass: &standard}}}
+		cluster := &Cluster{Spec: ClusterSpec{StorageConfiguration: StorageConfiguration{
+			PersistentVolumeClaimTemplate: &v1.PersistentVolumeClaimSpec{StorageClassName: &standard},
+		}}}
+		Expect(cluster.validateStorageClassChange(oldCluster)).To(BeEmpty())
+	})
+
+	It("rejects a change of the storage class of the existing volumes", func() {
+		oldCluster := &Cluster{Spec: ClusterSpec{StorageConfiguration: StorageConfiguration{StorageClass: &standard}}}
+		cluster := &Cluster{Spec: ClusterSpec{StorageConfiguration: StorageConfiguration{StorageClass: &premium}}}
+		Expect(cluster.validateStorageClassChange(oldCluster)).To(HaveLen(1))
+	})
+
+	It("accepts a change of the storage class while the volumes are recreated", func() {
+		oldCluster := &Cluster{Spec: ClusterSpec{StorageConfiguration: StorageConfiguration{StorageClass: &standard}}}
+		cluster := &Cluster{Spec: ClusterSpec{StorageConfiguration: StorageConfiguration{
+			StorageClass:       &premium,
+			ResizeInUseVolumes: &resizeInUseVolumes,
+		}}}
+		Expect(cluster.validateStorageClassChange(oldCluster)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("probes defaulting", func() {
+	It("writes the timings of the probes of a new cluster", func() {
+		cluster := &Cluster{Spec: ClusterSpec{Probes: &ProbesConfiguration{
+			Liveness: &Probe{TimeoutSeconds: 20},
+		}}}
+		cluster.defaultProbes()
+		Expect(cluster.Spec.Probes.Startup).To(Equal(&Probe{
+			TimeoutSeconds: DefaultProbeTimeoutSeconds,
+			PeriodSeconds:  DefaultProbePeriodSeconds,
+		}))
+		Expect(cluster.Spec.Probes.Liveness).To(Equal(&Probe{
+			TimeoutSeconds:   20,
+			PeriodSeconds:    DefaultProbePeriodSeconds,
+			FailureThreshold: DefaultProbeFailureThreshold,
+		}))
+		Expect(cluster.Spec.Probes.Readiness.Probe).To(Equal(Probe{
+			TimeoutSeconds:   DefaultProbeTimeoutSeconds,
+			PeriodSeconds:    DefaultProbePeriodSeconds,
+			FailureThreshold: DefaultProbeFailureThreshold,
+		}))
+		Expect(cluster.GetReadinessProbeType()).To(Equal(ReadinessProbeTypeQuery))
+	})
+
+	It("doesn't change the probes of an existing cluster", func() {
+		cluster := &Cluster{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.Now()}}
+		cluster.defaultProbes()
+		Expect(cluster.Spec.Probes).To(BeNil())
+	})
+
+	It("doesn't write the probes when the user opted out of them", func() {
+		cluster := &Cluster{Spec: ClusterSpec{
+			GeneratedDefaults: &GeneratedDefaultsConfiguration{DisableProbes: true},
+		}}
+		cluster.defaultProbes()
+		Expect(cluster.Spec.Probes).To(BeNil())
+	})
 })
@@ -0,0 +1,131 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+const (
+	// ImageCatalogKind is the kind of the namespaced image catalogs
+	ImageCatalogKind = "ImageCatalog"
+
+	// ClusterImageCatalogKind is the kind of the cluster-wide image catalogs
+	ClusterImageCatalogKind = "ClusterImageCatalog"
+)
+
+// ImageCatalogSpec defines the desired state of an image catalog
+type ImageCatalogSpec struct {
+	// List of CatalogImages available in the catalog
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=8
+	Images []CatalogImage `json:"images"`
+}
+
+// CatalogImage defines the image and major version
+type CatalogImage struct {
+	// The image reference
+	Image string `json:"image"`
+
+	// The PostgreSQL major version of the image. Must be unique within the catalog.
+	// +kubebuilder:validation:Minimum=10
+	Major int `json:"major"`
+}
+
+// FindImageForMajor finds the image corresponding to a PostgreSQL
+// major version, returning false when it is not in the catalog
+func (spec ImageCatalogSpec) FindImageForMajor(major int) (string, bool) {
+	for _, entry := range spec.Images {
+		if entry.Major == major {
+			return entry.Image, true
+		}
+	}
+
+	return "", false
+}
+
+// GenericImageCatalog is an interface implemented by both
+// ImageCatalog and ClusterImageCatalog
+// +kubebuilder:object:generate=false
+type GenericImageCatalog interface {
+	runtime.Object
+	metav1.Object
+
+	// GetSpec gets the specification of the catalog
+	GetSpec() *ImageCatalogSpec
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ImageCatalog is the Schema for the imagecatalogs API
+type ImageCatalog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired behavior of the ImageCatalog.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec ImageCatalogSpec `json:"spec"`
+}
+
+// GetSpec gets the specification of the catalog
+func (c *ImageCatalog) GetSpec() *ImageCatalogSpec {
+	return &c.Spec
+}
+
+// +kubebuilder:object:root=true
+
+// ImageCatalogList contains a list of ImageCatalog
+type ImageCatalogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ImageCatalog `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// ClusterImageCatalog is the Schema for the clusterimagecatalogs API
+type ClusterImageCatalog struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	// Specification of the desired behavior of the ClusterImageCatalog.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec ImageCatalogSpec `json:"spec"`
+}
+
+// GetSpec gets the specification of the catalog
+func (c *ClusterImageCatalog) GetSpec() *ImageCatalogSpec {
+	return &c.Spec
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterImageCatalogList contains a list of ClusterImageCatalog
+type ClusterImageCatalogList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterImageCatalog `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ImageCatalog{}, &ImageCatalogList{})
+	SchemeBuilder.Register(&ClusterImageCatalog{}, &ClusterImageCatalogList{})
+}
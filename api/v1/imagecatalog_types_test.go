@@ -0,0 +1,69 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("image catalog", func() {
+	catalog := ImageCatalogSpec{
+		Images: []CatalogImage{
+			{Major: 14, Image: "ghcr.io/cloudnative-pg/postgresql:14.9"},
+			{Major: 15, Image: "ghcr.io/cloudnative-pg/postgresql:15.4"},
+		},
+	}
+
+	It("finds the image of a major version", func() {
+		image, found := catalog.FindImageForMajor(15)
+		Expect(found).To(BeTrue())
+		Expect(image).To(Equal("ghcr.io/cloudnative-pg/postgresql:15.4"))
+
+		_, found = catalog.FindImageForMajor(16)
+		Expect(found).To(BeFalse())
+	})
+
+	It("uses the major version of the catalog until the image is resolved", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageCatalogRef: &ImageCatalogRef{
+					TypedLocalObjectReference: corev1.TypedLocalObjectReference{
+						Kind: ImageCatalogKind,
+						Name: "catalog",
+					},
+					Major: 15,
+				},
+			},
+		}
+		Expect(cluster.GetPostgresqlVersion()).To(Equal(150000))
+
+		cluster.Status.Image = "ghcr.io/cloudnative-pg/postgresql:15.4"
+		Expect(cluster.GetImageName()).To(Equal("ghcr.io/cloudnative-pg/postgresql:15.4"))
+		Expect(cluster.GetPostgresqlVersion()).To(Equal(150004))
+	})
+
+	It("ignores the resolved image when the catalog is not used", func() {
+		cluster := Cluster{
+			Spec:   ClusterSpec{ImageName: "ghcr.io/cloudnative-pg/postgresql:14.9"},
+			Status: ClusterStatus{Image: "ghcr.io/cloudnative-pg/postgresql:15.4"},
+		}
+		Expect(cluster.GetImageName()).To(Equal("ghcr.io/cloudnative-pg/postgresql:14.9"))
+	})
+})
@@ -19,6 +19,7 @@ package v1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // PoolerType is the type of the connection pool, meaning the service
@@ -68,6 +69,67 @@ type PoolerSpec struct {
 
 	// The PgBouncer configuration
 	PgBouncer *PgBouncerSpec `json:"pgbouncer"`
+
+	// The pod disruption budget of the PgBouncer pods. When specified,
+	// the operator creates a PodDisruptionBudget for the pooler
+	// +optional
+	PodDisruptionBudget *PoolerPodDisruptionBudget `json:"podDisruptionBudget,omitempty"`
+
+	// The anti-affinity rules the operator generates for the PgBouncer
+	// pods, spreading them across the nodes. They are not generated when
+	// the template already contains a pod anti-affinity section
+	// +optional
+	Affinity *PoolerAffinityConfiguration `json:"affinity,omitempty"`
+
+	// The configuration of the monitoring infrastructure of this pooler
+	// +optional
+	Monitoring *PoolerMonitoringConfiguration `json:"monitoring,omitempty"`
+}
+
+// PoolerMonitoringConfiguration is the type containing all the monitoring
+// configuration for a certain Pooler
+type PoolerMonitoringConfiguration struct {
+	// Enable or disable the `PodMonitor`
+	// +kubebuilder:default:=false
+	// +optional
+	EnablePodMonitor bool `json:"enablePodMonitor,omitempty"`
+}
+
+// PoolerPodDisruptionBudget contains the pod disruption budget
+// of the PgBouncer pods. Only one between minAvailable and
+// maxUnavailable can be specified, and when none of them is
+// specified a maximum of one unavailable pod is allowed
+type PoolerPodDisruptionBudget struct {
+	// The number or the percentage of the PgBouncer pods which
+	// must be available after an eviction
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+
+	// The number or the percentage of the PgBouncer pods which
+	// can be unavailable after an eviction
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// PoolerAffinityConfiguration contains the anti-affinity rules
+// generated by the operator for the PgBouncer pods
+type PoolerAffinityConfiguration struct {
+	// Activates the anti-affinity between the PgBouncer pods. The operator
+	// generates it unless this field is explicitly set to false
+	// +optional
+	EnablePodAntiAffinity *bool `json:"enablePodAntiAffinity,omitempty"`
+
+	// The topology key of the anti-affinity rule, defaults
+	// to `kubernetes.io/hostname`
+	// +optional
+	TopologyKey string `json:"topologyKey,omitempty"`
+
+	// Whether the anti-affinity rule is a strong requirement (`required`)
+	// or not (`preferred`, the default). With `required`, the pods exceeding
+	// the number of the available topology domains remain pending
+	// +kubebuilder:validation:Enum=preferred;required
+	// +optional
+	PodAntiAffinityType string `json:"podAntiAffinityType,omitempty"`
 }
 
 // PodTemplateSpec is a structure allowing the user to set
@@ -135,6 +197,25 @@ type PgBouncerSpec struct {
 	// the CNPG documentation for a list of options you can configure
 	Parameters map[string]string `json:"parameters,omitempty"`
 
+	// Additional users allowed to connect to the PgBouncer administration
+	// console, besides the `pgbouncer` one used by the operator
+	// +optional
+	AdminUsers []string `json:"adminUsers,omitempty"`
+
+	// The list of the databases and users allowed to connect through
+	// PgBouncer, rendered in its `pg_hba.conf` file. When empty, every
+	// user can connect to every database
+	// +optional
+	AccessRules []PgBouncerAccessRule `json:"accessRules,omitempty"`
+
+	// When true, the server connections opened by PgBouncer set
+	// `default_transaction_read_only` to `on`, so that the applications
+	// connecting through the pooler, typically of the `ro` type, cannot
+	// write by mistake, even when the pooler is pointed to the primary.
+	// Default: false
+	// +optional
+	DefaultTransactionReadOnly bool `json:"defaultTransactionReadOnly,omitempty"`
+
 	// When set to `true`, PgBouncer will disconnect from the PostgreSQL
 	// server, first waiting for all queries to complete, and pause all new
 	// client connections until this value is set to `false` (default). Internally,
@@ -143,6 +224,20 @@ type PgBouncerSpec struct {
 	Paused *bool `json:"paused,omitempty"`
 }
 
+// PgBouncerAccessRule allows a set of users to connect to a
+// set of databases through PgBouncer
+type PgBouncerAccessRule struct {
+	// The databases this rule applies to. When empty, the rule
+	// applies to every database
+	// +optional
+	Databases []string `json:"databases,omitempty"`
+
+	// The users this rule applies to. When empty, the rule
+	// applies to every user
+	// +optional
+	Users []string `json:"users,omitempty"`
+}
+
 // IsPaused returns whether all database should be paused or not
 func (in PgBouncerSpec) IsPaused() bool {
 	return in.Paused != nil && *in.Paused
@@ -154,6 +249,11 @@ type PoolerStatus struct {
 	Secrets *PoolerSecrets `json:"secrets,omitempty"`
 	// The number of pods trying to be scheduled
 	Instances int32 `json:"instances,omitempty"`
+
+	// The label selector of the PgBouncer pods, used by the
+	// scale subresource and by the HorizontalPodAutoscaler
+	// +optional
+	Selector string `json:"selector,omitempty"`
 }
 
 // PoolerSecrets contains the versions of all the secrets used
@@ -192,7 +292,7 @@ type SecretVersion struct {
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
 // +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
-// +kubebuilder:subresource:scale:specpath=.spec.instances,statuspath=.status.instances
+// +kubebuilder:subresource:scale:specpath=.spec.instances,statuspath=.status.instances,selectorpath=.status.selector
 
 // Pooler is the Schema for the poolers API
 type Pooler struct {
@@ -235,3 +335,12 @@ func (in *Pooler) GetAuthQuery() string {
 
 	return DefaultPgBouncerPoolerAuthQuery
 }
+
+// IsPodMonitorEnabled checks if the PodMonitor object needs to be created
+func (in *Pooler) IsPodMonitorEnabled() bool {
+	if in.Spec.Monitoring != nil {
+		return in.Spec.Monitoring.EnablePodMonitor
+	}
+
+	return false
+}
@@ -34,4 +34,20 @@ var _ = Describe("Pooler type tests", func() {
 		}
 		Expect(pgbouncer.IsPaused()).To(BeTrue())
 	})
+
+	It("doesn't create a PodMonitor by default", func() {
+		pooler := Pooler{}
+		Expect(pooler.IsPodMonitorEnabled()).To(BeFalse())
+	})
+
+	It("creates a PodMonitor when requested", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				Monitoring: &PoolerMonitoringConfiguration{
+					EnablePodMonitor: true,
+				},
+			},
+		}
+		Expect(pooler.IsPodMonitorEnabled()).To(BeTrue())
+	})
 })
@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"strings"
+
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -144,11 +146,45 @@ func (r *Pooler) validatePgBouncer() field.ErrorList {
 				"", "must specify an existing auth query secret when providing an auth query secret"))
 	}
 
-	result = append(result, r.validatePgbouncerGenericParameters()...)
+	if r.Spec.PgBouncer != nil {
+		result = append(result, r.validatePgbouncerGenericParameters()...)
+		result = append(result, r.validatePgbouncerAccessControl()...)
+	}
+
+	return result
+}
+
+// validatePgbouncerAccessControl checks the names used in the admin
+// users and in the access rules, which are rendered inside
+// the PgBouncer configuration files
+func (r *Pooler) validatePgbouncerAccessControl() field.ErrorList {
+	var result field.ErrorList
+
+	validateNames := func(path *field.Path, names []string) {
+		for idx, name := range names {
+			if !isValidPgBouncerIdentifier(name) {
+				result = append(result,
+					field.Invalid(path.Index(idx), name, "invalid name"))
+			}
+		}
+	}
+
+	validateNames(field.NewPath("spec", "pgbouncer", "adminUsers"), r.Spec.PgBouncer.AdminUsers)
+	for idx, rule := range r.Spec.PgBouncer.AccessRules {
+		rulePath := field.NewPath("spec", "pgbouncer", "accessRules").Index(idx)
+		validateNames(rulePath.Child("databases"), rule.Databases)
+		validateNames(rulePath.Child("users"), rule.Users)
+	}
 
 	return result
 }
 
+// isValidPgBouncerIdentifier checks if a name can be used inside
+// a comma-separated list of the PgBouncer configuration files
+func isValidPgBouncerIdentifier(name string) bool {
+	return name != "" && !strings.ContainsAny(name, ", \t\r\n\"'=#;")
+}
+
 func (r *Pooler) validateCluster() field.ErrorList {
 	var result field.ErrorList
 	if r.Spec.Cluster.Name == "" {
@@ -166,11 +202,28 @@ func (r *Pooler) validateCluster() field.ErrorList {
 	return result
 }
 
+// validatePodDisruptionBudget checks that only one between
+// minAvailable and maxUnavailable is specified
+func (r *Pooler) validatePodDisruptionBudget() field.ErrorList {
+	var result field.ErrorList
+
+	pdb := r.Spec.PodDisruptionBudget
+	if pdb != nil && pdb.MinAvailable != nil && pdb.MaxUnavailable != nil {
+		result = append(result,
+			field.Invalid(
+				field.NewPath("spec", "podDisruptionBudget"),
+				"", "minAvailable and maxUnavailable cannot be specified together"))
+	}
+
+	return result
+}
+
 // Validate validates the configuration of a Pooler, returning
 // a list of errors
 func (r *Pooler) Validate() (allErrs field.ErrorList) {
 	allErrs = append(allErrs, r.validatePgBouncer()...)
 	allErrs = append(allErrs, r.validateCluster()...)
+	allErrs = append(allErrs, r.validatePodDisruptionBudget()...)
 	return allErrs
 }
 
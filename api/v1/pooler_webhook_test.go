@@ -18,6 +18,7 @@ package v1
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -128,4 +129,46 @@ var _ = Describe("Pooler validation", func() {
 		}
 		Expect(pooler.validatePgbouncerGenericParameters()).To(BeEmpty())
 	})
+
+	It("doesn't allow both minAvailable and maxUnavailable in the pod disruption budget", func() {
+		one := intstr.FromInt(1)
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PodDisruptionBudget: &PoolerPodDisruptionBudget{MinAvailable: &one},
+			},
+		}
+		Expect(pooler.validatePodDisruptionBudget()).To(BeEmpty())
+
+		pooler.Spec.PodDisruptionBudget.MaxUnavailable = &one
+		Expect(pooler.validatePodDisruptionBudget()).NotTo(BeEmpty())
+	})
+
+	It("accepts valid access rules and admin users", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					AdminUsers: []string{"monitor"},
+					AccessRules: []PgBouncerAccessRule{
+						{Databases: []string{"app", "pgbouncer"}, Users: []string{"app", "monitor"}},
+						{Users: []string{"analyst"}},
+					},
+				},
+			},
+		}
+		Expect(pooler.validatePgbouncerAccessControl()).To(BeEmpty())
+	})
+
+	It("rejects names that would break the PgBouncer configuration", func() {
+		pooler := Pooler{
+			Spec: PoolerSpec{
+				PgBouncer: &PgBouncerSpec{
+					AdminUsers: []string{"monitor,admin"},
+					AccessRules: []PgBouncerAccessRule{
+						{Databases: []string{""}, Users: []string{"app all"}},
+					},
+				},
+			},
+		}
+		Expect(pooler.validatePgbouncerAccessControl()).To(HaveLen(3))
+	})
 })
@@ -45,6 +45,35 @@ type ScheduledBackupSpec struct {
 	// +kubebuilder:validation:Enum=none;self;cluster
 	// +kubebuilder:default:=none
 	BackupOwnerReference string `json:"backupOwnerReference,omitempty"`
+
+	// When true, the WAL files where every backup created by this
+	// schedule started and ended are checked once the backup is
+	// completed. Defaults to `false`
+	// +optional
+	WALCheck bool `json:"walCheck,omitempty"`
+
+	// The hooks executed before and after every backup
+	// created by this schedule
+	// +optional
+	Hooks *BackupHooks `json:"hooks,omitempty"`
+
+	// The method used to take the backups: it can be `barmanObjectStore`
+	// (default), using the object store configured in the cluster,
+	// `plugin`, delegating the backups to a plugin, or `physical`, storing
+	// a tarball of the data directory in a persistent volume
+	// +kubebuilder:validation:Enum=barmanObjectStore;plugin;physical
+	// +kubebuilder:default:=barmanObjectStore
+	// +optional
+	Method BackupMethod `json:"method,omitempty"`
+
+	// The plugin taking the backups, required by the `plugin` method
+	// +optional
+	PluginConfiguration *PluginConfiguration `json:"pluginConfiguration,omitempty"`
+
+	// Where the tarballs of the backups are stored, required
+	// by the `physical` method
+	// +optional
+	Physical *PhysicalBackupConfiguration `json:"physical,omitempty"`
 }
 
 // ScheduledBackupStatus defines the observed state of ScheduledBackup
@@ -140,10 +169,16 @@ func (scheduledBackup *ScheduledBackup) CreateBackup(name string) *Backup {
 			Namespace: scheduledBackup.Namespace,
 		},
 		Spec: BackupSpec{
-			Cluster: scheduledBackup.Spec.Cluster,
+			Cluster:  scheduledBackup.Spec.Cluster,
+			WALCheck: scheduledBackup.Spec.WALCheck,
+			Hooks:    scheduledBackup.Spec.Hooks.DeepCopy(),
+			Method:   scheduledBackup.Spec.Method,
+
+			PluginConfiguration: scheduledBackup.Spec.PluginConfiguration.DeepCopy(),
+			Physical:            scheduledBackup.Spec.Physical.DeepCopy(),
 		},
 	}
-	utils.InheritAnnotations(&backup.ObjectMeta, scheduledBackup.Annotations, nil, configuration.Current)
+	utils.InheritAnnotations(&backup.ObjectMeta, scheduledBackup.Annotations, nil, configuration.Current())
 	return &backup
 }
 
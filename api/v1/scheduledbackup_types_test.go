@@ -38,11 +38,37 @@ var _ = Describe("Scheduled backup", func() {
 		annotations := make(map[string]string, 1)
 		annotations["test"] = "annotations"
 		scheduledBackup.Annotations = annotations
-		configuration.Current.InheritedAnnotations = []string{"test"}
+		config := configuration.NewConfiguration()
+		config.InheritedAnnotations = []string{"test"}
+		configuration.SetCurrent(config)
 
 		backup := scheduledBackup.CreateBackup("test")
 		Expect(backup).ToNot(BeNil())
 		Expect(backup.ObjectMeta.Name).To(BeEquivalentTo(backupName))
 		Expect(backup.Annotations).ToNot(BeEmpty())
 	})
+
+	It("propagates the WAL check request to the backup", func() {
+		scheduledBackup := &ScheduledBackup{
+			Spec: ScheduledBackupSpec{
+				WALCheck: true,
+			},
+		}
+		backup := scheduledBackup.CreateBackup("test")
+		Expect(backup.Spec.WALCheck).To(BeTrue())
+	})
+
+	It("propagates the hooks to the backup", func() {
+		scheduledBackup := &ScheduledBackup{
+			Spec: ScheduledBackupSpec{
+				Hooks: &BackupHooks{
+					PreBackup: []BackupHook{{SQL: "SELECT 1"}},
+				},
+			},
+		}
+		backup := scheduledBackup.CreateBackup("test")
+		Expect(backup.Spec.Hooks).ToNot(BeNil())
+		Expect(backup.Spec.Hooks.PreBackup).To(HaveLen(1))
+		Expect(backup.Spec.Hooks).ToNot(BeIdenticalTo(scheduledBackup.Spec.Hooks))
+	})
 })
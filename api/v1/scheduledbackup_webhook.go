@@ -18,6 +18,7 @@ package v1
 
 import (
 	"github.com/robfig/cron"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -33,6 +34,7 @@ var scheduledBackupLog = log.WithName("scheduledbackup-resource").WithValues("ve
 
 // SetupWebhookWithManager setup the webhook inside the controller manager
 func (r *ScheduledBackup) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	backupHooksClusterReader = mgr.GetAPIReader()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
@@ -58,6 +60,11 @@ func (r *ScheduledBackup) ValidateCreate() error {
 	scheduledBackupLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
 
 	allErrs = append(allErrs, r.validateSchedule()...)
+	allErrs = append(allErrs, r.Spec.Hooks.validate(field.NewPath("spec", "hooks"))...)
+	allErrs = append(allErrs,
+		validateHooksAllowed(r.Namespace, r.Spec.Cluster.Name, r.Spec.Hooks, field.NewPath("spec", "hooks"))...)
+	allErrs = append(allErrs,
+		validateBackupMethod(r.Spec.Method, r.Spec.PluginConfiguration, r.Spec.Physical, field.NewPath("spec"))...)
 
 	if len(allErrs) == 0 {
 		return nil
@@ -71,7 +78,21 @@ func (r *ScheduledBackup) ValidateCreate() error {
 // ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
 func (r *ScheduledBackup) ValidateUpdate(old runtime.Object) error {
 	scheduledBackupLog.Info("validate update", "name", r.Name, "namespace", r.Namespace)
-	return nil
+
+	allErrs := r.Spec.Hooks.validate(field.NewPath("spec", "hooks"))
+	if !equality.Semantic.DeepEqual(r.Spec.Hooks, old.(*ScheduledBackup).Spec.Hooks) {
+		allErrs = append(allErrs,
+			validateHooksAllowed(r.Namespace, r.Spec.Cluster.Name, r.Spec.Hooks, field.NewPath("spec", "hooks"))...)
+	}
+	allErrs = append(allErrs,
+		validateBackupMethod(r.Spec.Method, r.Spec.PluginConfiguration, r.Spec.Physical, field.NewPath("spec"))...)
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "scheduledbackup.cnpg.io", Kind: "Backup"},
+		r.Name, allErrs)
 }
 
 // ValidateDelete implements webhook.Validator so a webhook will be registered for the type
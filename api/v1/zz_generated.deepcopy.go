@@ -26,8 +26,60 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalBackupStatus) DeepCopyInto(out *AdditionalBackupStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalBackupStatus.
+func (in *AdditionalBackupStatus) DeepCopy() *AdditionalBackupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalBackupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalObjectStore) DeepCopyInto(out *AdditionalObjectStore) {
+	*out = *in
+	in.BarmanObjectStore.DeepCopyInto(&out.BarmanObjectStore)
+	if in.BaseBackups != nil {
+		in, out := &in.BaseBackups, &out.BaseBackups
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalObjectStore.
+func (in *AdditionalObjectStore) DeepCopy() *AdditionalObjectStore {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalObjectStore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AdditionalObjectStoreStatus) DeepCopyInto(out *AdditionalObjectStoreStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AdditionalObjectStoreStatus.
+func (in *AdditionalObjectStoreStatus) DeepCopy() *AdditionalObjectStoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AdditionalObjectStoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AffinityConfiguration) DeepCopyInto(out *AffinityConfiguration) {
 	*out = *in
@@ -112,7 +164,7 @@ func (in *Backup) DeepCopyInto(out *Backup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -142,6 +194,18 @@ func (in *BackupConfiguration) DeepCopyInto(out *BackupConfiguration) {
 		*out = new(BarmanObjectStoreConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AdditionalObjectStores != nil {
+		in, out := &in.AdditionalObjectStores, &out.AdditionalObjectStores
+		*out = make([]AdditionalObjectStore, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GarbageCollection != nil {
+		in, out := &in.GarbageCollection, &out.GarbageCollection
+		*out = new(BackupGarbageCollectionConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupConfiguration.
@@ -154,6 +218,75 @@ func (in *BackupConfiguration) DeepCopy() *BackupConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupGarbageCollectionConfiguration) DeepCopyInto(out *BackupGarbageCollectionConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupGarbageCollectionConfiguration.
+func (in *BackupGarbageCollectionConfiguration) DeepCopy() *BackupGarbageCollectionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupGarbageCollectionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupHook) DeepCopyInto(out *BackupHook) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHook.
+func (in *BackupHook) DeepCopy() *BackupHook {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupHooks) DeepCopyInto(out *BackupHooks) {
+	*out = *in
+	if in.PreBackup != nil {
+		in, out := &in.PreBackup, &out.PreBackup
+		*out = make([]BackupHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PostBackup != nil {
+		in, out := &in.PostBackup, &out.PostBackup
+		*out = make([]BackupHook, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupHooks.
+func (in *BackupHooks) DeepCopy() *BackupHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupList) DeepCopyInto(out *BackupList) {
 	*out = *in
@@ -186,6 +319,21 @@ func (in *BackupList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupProgress) DeepCopyInto(out *BackupProgress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupProgress.
+func (in *BackupProgress) DeepCopy() *BackupProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupSource) DeepCopyInto(out *BackupSource) {
 	*out = *in
@@ -211,6 +359,21 @@ func (in *BackupSource) DeepCopy() *BackupSource {
 func (in *BackupSpec) DeepCopyInto(out *BackupSpec) {
 	*out = *in
 	out.Cluster = in.Cluster
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(BackupHooks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PluginConfiguration != nil {
+		in, out := &in.PluginConfiguration, &out.PluginConfiguration
+		*out = new(PluginConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Physical != nil {
+		in, out := &in.Physical, &out.Physical
+		*out = new(PhysicalBackupConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupSpec.
@@ -240,11 +403,26 @@ func (in *BackupStatus) DeepCopyInto(out *BackupStatus) {
 		in, out := &in.StoppedAt, &out.StoppedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.WALCheck != nil {
+		in, out := &in.WALCheck, &out.WALCheck
+		*out = new(BackupWALCheckStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AdditionalBackups != nil {
+		in, out := &in.AdditionalBackups, &out.AdditionalBackups
+		*out = make([]AdditionalBackupStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.InstanceID != nil {
 		in, out := &in.InstanceID, &out.InstanceID
 		*out = new(InstanceID)
 		**out = **in
 	}
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = new(BackupProgress)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupStatus.
@@ -257,6 +435,29 @@ func (in *BackupStatus) DeepCopy() *BackupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BackupWALCheckStatus) DeepCopyInto(out *BackupWALCheckStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.StoppedAt != nil {
+		in, out := &in.StoppedAt, &out.StoppedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupWALCheckStatus.
+func (in *BackupWALCheckStatus) DeepCopy() *BackupWALCheckStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BackupWALCheckStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BarmanCredentials) DeepCopyInto(out *BarmanCredentials) {
 	*out = *in
@@ -332,6 +533,21 @@ func (in *BarmanObjectStoreConfiguration) DeepCopy() *BarmanObjectStoreConfigura
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapAdopt) DeepCopyInto(out *BootstrapAdopt) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapAdopt.
+func (in *BootstrapAdopt) DeepCopy() *BootstrapAdopt {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapAdopt)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BootstrapConfiguration) DeepCopyInto(out *BootstrapConfiguration) {
 	*out = *in
@@ -350,6 +566,11 @@ func (in *BootstrapConfiguration) DeepCopyInto(out *BootstrapConfiguration) {
 		*out = new(BootstrapPgBaseBackup)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Adopt != nil {
+		in, out := &in.Adopt, &out.Adopt
+		*out = new(BootstrapAdopt)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapConfiguration.
@@ -402,7 +623,17 @@ func (in *BootstrapInitDB) DeepCopyInto(out *BootstrapInitDB) {
 	}
 	if in.PostInitApplicationSQLRefs != nil {
 		in, out := &in.PostInitApplicationSQLRefs, &out.PostInitApplicationSQLRefs
-		*out = new(PostInitApplicationSQLRefs)
+		*out = new(SQLRefs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostInitSQLRefs != nil {
+		in, out := &in.PostInitSQLRefs, &out.PostInitSQLRefs
+		*out = new(SQLRefs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PostInitTemplateSQLRefs != nil {
+		in, out := &in.PostInitTemplateSQLRefs, &out.PostInitTemplateSQLRefs
+		*out = new(SQLRefs)
 		(*in).DeepCopyInto(*out)
 	}
 }
@@ -425,6 +656,11 @@ func (in *BootstrapPgBaseBackup) DeepCopyInto(out *BootstrapPgBaseBackup) {
 		*out = new(LocalObjectReference)
 		**out = **in
 	}
+	if in.Clone != nil {
+		in, out := &in.Clone, &out.Clone
+		*out = new(CloneConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapPgBaseBackup.
@@ -445,6 +681,11 @@ func (in *BootstrapRecovery) DeepCopyInto(out *BootstrapRecovery) {
 		*out = new(BackupSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.VolumeSnapshots != nil {
+		in, out := &in.VolumeSnapshots, &out.VolumeSnapshots
+		*out = new(DataSource)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.RecoveryTarget != nil {
 		in, out := &in.RecoveryTarget, &out.RecoveryTarget
 		*out = new(RecoveryTarget)
@@ -455,6 +696,11 @@ func (in *BootstrapRecovery) DeepCopyInto(out *BootstrapRecovery) {
 		*out = new(LocalObjectReference)
 		**out = **in
 	}
+	if in.Remap != nil {
+		in, out := &in.Remap, &out.Remap
+		*out = new(RecoveryRemapping)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapRecovery.
@@ -467,6 +713,21 @@ func (in *BootstrapRecovery) DeepCopy() *BootstrapRecovery {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CatalogImage) DeepCopyInto(out *CatalogImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CatalogImage.
+func (in *CatalogImage) DeepCopy() *CatalogImage {
+	if in == nil {
+		return nil
+	}
+	out := new(CatalogImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificatesConfiguration) DeepCopyInto(out *CertificatesConfiguration) {
 	*out = *in
@@ -510,6 +771,26 @@ func (in *CertificatesStatus) DeepCopy() *CertificatesStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloneConfiguration) DeepCopyInto(out *CloneConfiguration) {
+	*out = *in
+	if in.VerifyChecksums != nil {
+		in, out := &in.VerifyChecksums, &out.VerifyChecksums
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloneConfiguration.
+func (in *CloneConfiguration) DeepCopy() *CloneConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CloneConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
@@ -537,6 +818,64 @@ func (in *Cluster) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterImageCatalog) DeepCopyInto(out *ClusterImageCatalog) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterImageCatalog.
+func (in *ClusterImageCatalog) DeepCopy() *ClusterImageCatalog {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterImageCatalog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterImageCatalog) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterImageCatalogList) DeepCopyInto(out *ClusterImageCatalogList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterImageCatalog, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterImageCatalogList.
+func (in *ClusterImageCatalogList) DeepCopy() *ClusterImageCatalogList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterImageCatalogList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterImageCatalogList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterList) DeepCopyInto(out *ClusterList) {
 	*out = *in
@@ -569,6 +908,21 @@ func (in *ClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMonitoringTLSConfiguration) DeepCopyInto(out *ClusterMonitoringTLSConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMonitoringTLSConfiguration.
+func (in *ClusterMonitoringTLSConfiguration) DeepCopy() *ClusterMonitoringTLSConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMonitoringTLSConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	*out = *in
@@ -577,7 +931,37 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(EmbeddedObjectMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.GeneratedObjects != nil {
+		in, out := &in.GeneratedObjects, &out.GeneratedObjects
+		*out = new(GeneratedObjectsConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ExternalDNS != nil {
+		in, out := &in.ExternalDNS, &out.ExternalDNS
+		*out = new(ExternalDNSConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DriftDetection != nil {
+		in, out := &in.DriftDetection, &out.DriftDetection
+		*out = new(DriftDetectionConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageCatalogRef != nil {
+		in, out := &in.ImageCatalogRef, &out.ImageCatalogRef
+		*out = new(ImageCatalogRef)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SeccompProfile != nil {
+		in, out := &in.SeccompProfile, &out.SeccompProfile
+		*out = new(corev1.SeccompProfile)
+		(*in).DeepCopyInto(*out)
+	}
 	in.PostgresConfiguration.DeepCopyInto(&out.PostgresConfiguration)
+	if in.Managed != nil {
+		in, out := &in.Managed, &out.Managed
+		*out = new(ManagedConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Bootstrap != nil {
 		in, out := &in.Bootstrap, &out.Bootstrap
 		*out = new(BootstrapConfiguration)
@@ -603,24 +987,88 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(CertificatesConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReplicationConnection != nil {
+		in, out := &in.ReplicationConnection, &out.ReplicationConnection
+		*out = new(ReplicationConnectionConfiguration)
+		**out = **in
+	}
 	if in.ImagePullSecrets != nil {
 		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
 		*out = make([]LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.ServiceAccountTemplate != nil {
+		in, out := &in.ServiceAccountTemplate, &out.ServiceAccountTemplate
+		*out = new(ServiceAccountTemplate)
+		(*in).DeepCopyInto(*out)
+	}
 	in.StorageConfiguration.DeepCopyInto(&out.StorageConfiguration)
 	if in.WalStorage != nil {
 		in, out := &in.WalStorage, &out.WalStorage
 		*out = new(StorageConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
-	in.Affinity.DeepCopyInto(&out.Affinity)
-	in.Resources.DeepCopyInto(&out.Resources)
-	if in.Backup != nil {
+	if in.DiskSpace != nil {
+		in, out := &in.DiskSpace, &out.DiskSpace
+		*out = new(DiskSpaceConfiguration)
+		**out = **in
+	}
+	if in.DataVerification != nil {
+		in, out := &in.DataVerification, &out.DataVerification
+		*out = new(DataVerificationConfiguration)
+		**out = **in
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(ProbesConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailoverCandidates != nil {
+		in, out := &in.FailoverCandidates, &out.FailoverCandidates
+		*out = new(FailoverCandidatesConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstanceHealth != nil {
+		in, out := &in.InstanceHealth, &out.InstanceHealth
+		*out = new(InstanceHealthConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Affinity.DeepCopyInto(&out.Affinity)
+	in.Resources.DeepCopyInto(&out.Resources)
+	if in.GeneratedDefaults != nil {
+		in, out := &in.GeneratedDefaults, &out.GeneratedDefaults
+		*out = new(GeneratedDefaultsConfiguration)
+		**out = **in
+	}
+	if in.PrimaryUpdateWindows != nil {
+		in, out := &in.PrimaryUpdateWindows, &out.PrimaryUpdateWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ReplicaClone != nil {
+		in, out := &in.ReplicaClone, &out.ReplicaClone
+		*out = new(CloneConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ImageUpdate != nil {
+		in, out := &in.ImageUpdate, &out.ImageUpdate
+		*out = new(ImageUpdateConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Backup != nil {
 		in, out := &in.Backup, &out.Backup
 		*out = new(BackupConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]PluginConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.NodeMaintenanceWindow != nil {
 		in, out := &in.NodeMaintenanceWindow, &out.NodeMaintenanceWindow
 		*out = new(NodeMaintenanceWindow)
@@ -653,6 +1101,11 @@ func (in *ClusterSpec) DeepCopy() *ClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 	*out = *in
+	if in.ImageArchitectures != nil {
+		in, out := &in.ImageArchitectures, &out.ImageArchitectures
+		*out = new(ImageArchitecturesStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.InstancesStatus != nil {
 		in, out := &in.InstancesStatus, &out.InstancesStatus
 		*out = make(map[utils.PodStatus][]string, len(*in))
@@ -672,7 +1125,7 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		in, out := &in.InstancesReportedState, &out.InstancesReportedState
 		*out = make(map[PodName]InstanceReportedState, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
 	in.Topology.DeepCopyInto(&out.Topology)
@@ -701,14 +1154,39 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Binding != nil {
+		in, out := &in.Binding, &out.Binding
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
 	in.SecretsResourceVersion.DeepCopyInto(&out.SecretsResourceVersion)
 	in.ConfigMapResourceVersion.DeepCopyInto(&out.ConfigMapResourceVersion)
 	in.Certificates.DeepCopyInto(&out.Certificates)
+	if in.AdditionalObjectStores != nil {
+		in, out := &in.AdditionalObjectStores, &out.AdditionalObjectStores
+		*out = make([]AdditionalObjectStoreStatus, len(*in))
+		copy(*out, *in)
+	}
 	if in.PoolerIntegrations != nil {
 		in, out := &in.PoolerIntegrations, &out.PoolerIntegrations
 		*out = new(PoolerIntegrations)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ManagedExtensionsStatus != nil {
+		in, out := &in.ManagedExtensionsStatus, &out.ManagedExtensionsStatus
+		*out = new(ManagedExtensionsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ObjectStoreReport != nil {
+		in, out := &in.ObjectStoreReport, &out.ObjectStoreReport
+		*out = new(ObjectStoreReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManagedRolesStatus != nil {
+		in, out := &in.ManagedRolesStatus, &out.ManagedRolesStatus
+		*out = new(ManagedRolesStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]metav1.Condition, len(*in))
@@ -754,6 +1232,13 @@ func (in *ConfigMapResourceVersion) DeepCopyInto(out *ConfigMapResourceVersion)
 			(*out)[key] = val
 		}
 	}
+	if in.ConfigurationFiles != nil {
+		in, out := &in.ConfigurationFiles, &out.ConfigurationFiles
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapResourceVersion.
@@ -766,6 +1251,31 @@ func (in *ConfigMapResourceVersion) DeepCopy() *ConfigMapResourceVersion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConnectionsConfiguration) DeepCopyInto(out *ConnectionsConfiguration) {
+	*out = *in
+	if in.MaxConnections != nil {
+		in, out := &in.MaxConnections, &out.MaxConnections
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SuperuserReservedConnections != nil {
+		in, out := &in.SuperuserReservedConnections, &out.SuperuserReservedConnections
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConnectionsConfiguration.
+func (in *ConnectionsConfiguration) DeepCopy() *ConnectionsConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ConnectionsConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataBackupConfiguration) DeepCopyInto(out *DataBackupConfiguration) {
 	*out = *in
@@ -786,6 +1296,82 @@ func (in *DataBackupConfiguration) DeepCopy() *DataBackupConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataSource) DeepCopyInto(out *DataSource) {
+	*out = *in
+	in.Storage.DeepCopyInto(&out.Storage)
+	if in.WalStorage != nil {
+		in, out := &in.WalStorage, &out.WalStorage
+		*out = new(corev1.TypedLocalObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataSource.
+func (in *DataSource) DeepCopy() *DataSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DataSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataVerificationConfiguration) DeepCopyInto(out *DataVerificationConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataVerificationConfiguration.
+func (in *DataVerificationConfiguration) DeepCopy() *DataVerificationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DataVerificationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DiskSpaceConfiguration) DeepCopyInto(out *DiskSpaceConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskSpaceConfiguration.
+func (in *DiskSpaceConfiguration) DeepCopy() *DiskSpaceConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DiskSpaceConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftDetectionConfiguration) DeepCopyInto(out *DriftDetectionConfiguration) {
+	*out = *in
+	if in.AutoRepair != nil {
+		in, out := &in.AutoRepair, &out.AutoRepair
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftDetectionConfiguration.
+func (in *DriftDetectionConfiguration) DeepCopy() *DriftDetectionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftDetectionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
 	*out = *in
@@ -815,6 +1401,21 @@ func (in *EmbeddedObjectMetadata) DeepCopy() *EmbeddedObjectMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionImage) DeepCopyInto(out *ExtensionImage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionImage.
+func (in *ExtensionImage) DeepCopy() *ExtensionImage {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionImage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalCluster) DeepCopyInto(out *ExternalCluster) {
 	*out = *in
@@ -850,6 +1451,11 @@ func (in *ExternalCluster) DeepCopyInto(out *ExternalCluster) {
 		*out = new(BarmanObjectStoreConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PluginConfiguration != nil {
+		in, out := &in.PluginConfiguration, &out.PluginConfiguration
+		*out = new(PluginConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalCluster.
@@ -863,180 +1469,652 @@ func (in *ExternalCluster) DeepCopy() *ExternalCluster {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GoogleCredentials) DeepCopyInto(out *GoogleCredentials) {
+func (in *ExternalDNSConfiguration) DeepCopyInto(out *ExternalDNSConfiguration) {
 	*out = *in
-	if in.ApplicationCredentials != nil {
-		in, out := &in.ApplicationCredentials, &out.ApplicationCredentials
-		*out = new(SecretKeySelector)
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int32)
 		**out = **in
 	}
+	if in.ServiceAnnotations != nil {
+		in, out := &in.ServiceAnnotations, &out.ServiceAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.LoadBalancerSourceRanges != nil {
+		in, out := &in.LoadBalancerSourceRanges, &out.LoadBalancerSourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleCredentials.
-func (in *GoogleCredentials) DeepCopy() *GoogleCredentials {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalDNSConfiguration.
+func (in *ExternalDNSConfiguration) DeepCopy() *ExternalDNSConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(GoogleCredentials)
+	out := new(ExternalDNSConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Import) DeepCopyInto(out *Import) {
+func (in *FailoverCandidatesConfiguration) DeepCopyInto(out *FailoverCandidatesConfiguration) {
 	*out = *in
-	out.Source = in.Source
-	if in.Databases != nil {
-		in, out := &in.Databases, &out.Databases
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.Roles != nil {
-		in, out := &in.Roles, &out.Roles
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.PostImportApplicationSQL != nil {
-		in, out := &in.PostImportApplicationSQL, &out.PostImportApplicationSQL
+	if in.ExcludedInstances != nil {
+		in, out := &in.ExcludedInstances, &out.ExcludedInstances
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Import.
-func (in *Import) DeepCopy() *Import {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FailoverCandidatesConfiguration.
+func (in *FailoverCandidatesConfiguration) DeepCopy() *FailoverCandidatesConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(Import)
+	out := new(FailoverCandidatesConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ImportSource) DeepCopyInto(out *ImportSource) {
+func (in *GeneratedDefaultsConfiguration) DeepCopyInto(out *GeneratedDefaultsConfiguration) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportSource.
-func (in *ImportSource) DeepCopy() *ImportSource {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratedDefaultsConfiguration.
+func (in *GeneratedDefaultsConfiguration) DeepCopy() *GeneratedDefaultsConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(ImportSource)
+	out := new(GeneratedDefaultsConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstanceID) DeepCopyInto(out *InstanceID) {
+func (in *GeneratedObjectsConfiguration) DeepCopyInto(out *GeneratedObjectsConfiguration) {
 	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = new(EmbeddedObjectMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = new(EmbeddedObjectMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = new(EmbeddedObjectMetadata)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PersistentVolumeClaims != nil {
+		in, out := &in.PersistentVolumeClaims, &out.PersistentVolumeClaims
+		*out = new(EmbeddedObjectMetadata)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceID.
-func (in *InstanceID) DeepCopy() *InstanceID {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratedObjectsConfiguration.
+func (in *GeneratedObjectsConfiguration) DeepCopy() *GeneratedObjectsConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(InstanceID)
+	out := new(GeneratedObjectsConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *InstanceReportedState) DeepCopyInto(out *InstanceReportedState) {
+func (in *GoogleCredentials) DeepCopyInto(out *GoogleCredentials) {
 	*out = *in
+	if in.ApplicationCredentials != nil {
+		in, out := &in.ApplicationCredentials, &out.ApplicationCredentials
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceReportedState.
-func (in *InstanceReportedState) DeepCopy() *InstanceReportedState {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleCredentials.
+func (in *GoogleCredentials) DeepCopy() *GoogleCredentials {
 	if in == nil {
 		return nil
 	}
-	out := new(InstanceReportedState)
+	out := new(GoogleCredentials)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPBindAsAuth) DeepCopyInto(out *LDAPBindAsAuth) {
+func (in *ImageArchitecturesStatus) DeepCopyInto(out *ImageArchitecturesStatus) {
 	*out = *in
+	if in.Architectures != nil {
+		in, out := &in.Architectures, &out.Architectures
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindAsAuth.
-func (in *LDAPBindAsAuth) DeepCopy() *LDAPBindAsAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageArchitecturesStatus.
+func (in *ImageArchitecturesStatus) DeepCopy() *ImageArchitecturesStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAPBindAsAuth)
+	out := new(ImageArchitecturesStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPBindSearchAuth) DeepCopyInto(out *LDAPBindSearchAuth) {
+func (in *ImageCatalog) DeepCopyInto(out *ImageCatalog) {
 	*out = *in
-	if in.BindPassword != nil {
-		in, out := &in.BindPassword, &out.BindPassword
-		*out = new(corev1.SecretKeySelector)
-		(*in).DeepCopyInto(*out)
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindSearchAuth.
-func (in *LDAPBindSearchAuth) DeepCopy() *LDAPBindSearchAuth {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCatalog.
+func (in *ImageCatalog) DeepCopy() *ImageCatalog {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAPBindSearchAuth)
+	out := new(ImageCatalog)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageCatalog) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LDAPConfig) DeepCopyInto(out *LDAPConfig) {
+func (in *ImageCatalogList) DeepCopyInto(out *ImageCatalogList) {
 	*out = *in
-	if in.BindAsAuth != nil {
-		in, out := &in.BindAsAuth, &out.BindAsAuth
-		*out = new(LDAPBindAsAuth)
-		**out = **in
-	}
-	if in.BindSearchAuth != nil {
-		in, out := &in.BindSearchAuth, &out.BindSearchAuth
-		*out = new(LDAPBindSearchAuth)
-		(*in).DeepCopyInto(*out)
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ImageCatalog, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPConfig.
-func (in *LDAPConfig) DeepCopy() *LDAPConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCatalogList.
+func (in *ImageCatalogList) DeepCopy() *ImageCatalogList {
 	if in == nil {
 		return nil
 	}
-	out := new(LDAPConfig)
+	out := new(ImageCatalogList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ImageCatalogList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *LocalObjectReference) DeepCopyInto(out *LocalObjectReference) {
+func (in *ImageCatalogRef) DeepCopyInto(out *ImageCatalogRef) {
 	*out = *in
+	in.TypedLocalObjectReference.DeepCopyInto(&out.TypedLocalObjectReference)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalObjectReference.
-func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCatalogRef.
+func (in *ImageCatalogRef) DeepCopy() *ImageCatalogRef {
 	if in == nil {
 		return nil
 	}
-	out := new(LocalObjectReference)
+	out := new(ImageCatalogRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MonitoringConfiguration) DeepCopyInto(out *MonitoringConfiguration) {
+func (in *ImageCatalogSpec) DeepCopyInto(out *ImageCatalogSpec) {
 	*out = *in
-	if in.DisableDefaultQueries != nil {
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]CatalogImage, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageCatalogSpec.
+func (in *ImageCatalogSpec) DeepCopy() *ImageCatalogSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageCatalogSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageUpdateConfiguration) DeepCopyInto(out *ImageUpdateConfiguration) {
+	*out = *in
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageUpdateConfiguration.
+func (in *ImageUpdateConfiguration) DeepCopy() *ImageUpdateConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageUpdateConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Import) DeepCopyInto(out *Import) {
+	*out = *in
+	out.Source = in.Source
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostImportApplicationSQL != nil {
+		in, out := &in.PostImportApplicationSQL, &out.PostImportApplicationSQL
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Import.
+func (in *Import) DeepCopy() *Import {
+	if in == nil {
+		return nil
+	}
+	out := new(Import)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImportSource) DeepCopyInto(out *ImportSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImportSource.
+func (in *ImportSource) DeepCopy() *ImportSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ImportSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceHealthConfiguration) DeepCopyInto(out *InstanceHealthConfiguration) {
+	*out = *in
+	if in.MaximumLag != nil {
+		in, out := &in.MaximumLag, &out.MaximumLag
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceHealthConfiguration.
+func (in *InstanceHealthConfiguration) DeepCopy() *InstanceHealthConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceHealthConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceID) DeepCopyInto(out *InstanceID) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceID.
+func (in *InstanceID) DeepCopy() *InstanceID {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceID)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceLocation) DeepCopyInto(out *InstanceLocation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceLocation.
+func (in *InstanceLocation) DeepCopy() *InstanceLocation {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceLocation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceReportedState) DeepCopyInto(out *InstanceReportedState) {
+	*out = *in
+	if in.WalSenders != nil {
+		in, out := &in.WalSenders, &out.WalSenders
+		*out = make([]WalSenderState, len(*in))
+		copy(*out, *in)
+	}
+	if in.PendingRestartParameters != nil {
+		in, out := &in.PendingRestartParameters, &out.PendingRestartParameters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceReportedState.
+func (in *InstanceReportedState) DeepCopy() *InstanceReportedState {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceReportedState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPBindAsAuth) DeepCopyInto(out *LDAPBindAsAuth) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindAsAuth.
+func (in *LDAPBindAsAuth) DeepCopy() *LDAPBindAsAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPBindAsAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPBindSearchAuth) DeepCopyInto(out *LDAPBindSearchAuth) {
+	*out = *in
+	if in.BindPassword != nil {
+		in, out := &in.BindPassword, &out.BindPassword
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPBindSearchAuth.
+func (in *LDAPBindSearchAuth) DeepCopy() *LDAPBindSearchAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPBindSearchAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LDAPConfig) DeepCopyInto(out *LDAPConfig) {
+	*out = *in
+	if in.BindAsAuth != nil {
+		in, out := &in.BindAsAuth, &out.BindAsAuth
+		*out = new(LDAPBindAsAuth)
+		**out = **in
+	}
+	if in.BindSearchAuth != nil {
+		in, out := &in.BindSearchAuth, &out.BindSearchAuth
+		*out = new(LDAPBindSearchAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LDAPConfig.
+func (in *LDAPConfig) DeepCopy() *LDAPConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(LDAPConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalObjectReference) DeepCopyInto(out *LocalObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalObjectReference.
+func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocaleConfiguration) DeepCopyInto(out *LocaleConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocaleConfiguration.
+func (in *LocaleConfiguration) DeepCopy() *LocaleConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(LocaleConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]MaintenanceWindowDay, len(*in))
+		copy(*out, *in)
+	}
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
+	*out = *in
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]ManagedExtension, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]ManagedRole, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedConfiguration.
+func (in *ManagedConfiguration) DeepCopy() *ManagedConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedExtension) DeepCopyInto(out *ManagedExtension) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedExtension.
+func (in *ManagedExtension) DeepCopy() *ManagedExtension {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedExtension)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedExtensionsStatus) DeepCopyInto(out *ManagedExtensionsStatus) {
+	*out = *in
+	if in.Reconciled != nil {
+		in, out := &in.Reconciled, &out.Reconciled
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CannotReconcile != nil {
+		in, out := &in.CannotReconcile, &out.CannotReconcile
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedExtensionsStatus.
+func (in *ManagedExtensionsStatus) DeepCopy() *ManagedExtensionsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedExtensionsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedRole) DeepCopyInto(out *ManagedRole) {
+	*out = *in
+	if in.ConnectionLimit != nil {
+		in, out := &in.ConnectionLimit, &out.ConnectionLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedRole.
+func (in *ManagedRole) DeepCopy() *ManagedRole {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedRole)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedRolesStatus) DeepCopyInto(out *ManagedRolesStatus) {
+	*out = *in
+	if in.Reconciled != nil {
+		in, out := &in.Reconciled, &out.Reconciled
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.CannotReconcile != nil {
+		in, out := &in.CannotReconcile, &out.CannotReconcile
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedRolesStatus.
+func (in *ManagedRolesStatus) DeepCopy() *ManagedRolesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedRolesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Metadata) DeepCopyInto(out *Metadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Metadata.
+func (in *Metadata) DeepCopy() *Metadata {
+	if in == nil {
+		return nil
+	}
+	out := new(Metadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringConfiguration) DeepCopyInto(out *MonitoringConfiguration) {
+	*out = *in
+	if in.DisableDefaultQueries != nil {
 		in, out := &in.DisableDefaultQueries, &out.DisableDefaultQueries
 		*out = new(bool)
 		**out = **in
@@ -1051,6 +2129,21 @@ func (in *MonitoringConfiguration) DeepCopyInto(out *MonitoringConfiguration) {
 		*out = make([]SecretKeySelector, len(*in))
 		copy(*out, *in)
 	}
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(ClusterMonitoringTLSConfiguration)
+		**out = **in
+	}
+	if in.BearerTokenSecret != nil {
+		in, out := &in.BearerTokenSecret, &out.BearerTokenSecret
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+	if in.DatabaseDiscovery != nil {
+		in, out := &in.DatabaseDiscovery, &out.DatabaseDiscovery
+		*out = new(MonitoringDatabaseDiscovery)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringConfiguration.
@@ -1063,6 +2156,31 @@ func (in *MonitoringConfiguration) DeepCopy() *MonitoringConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonitoringDatabaseDiscovery) DeepCopyInto(out *MonitoringDatabaseDiscovery) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringDatabaseDiscovery.
+func (in *MonitoringDatabaseDiscovery) DeepCopy() *MonitoringDatabaseDiscovery {
+	if in == nil {
+		return nil
+	}
+	out := new(MonitoringDatabaseDiscovery)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeMaintenanceWindow) DeepCopyInto(out *NodeMaintenanceWindow) {
 	*out = *in
@@ -1083,6 +2201,101 @@ func (in *NodeMaintenanceWindow) DeepCopy() *NodeMaintenanceWindow {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectStoreReport) DeepCopyInto(out *ObjectStoreReport) {
+	*out = *in
+	if in.OrphanedBackups != nil {
+		in, out := &in.OrphanedBackups, &out.OrphanedBackups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.OrphanedWALRange != nil {
+		in, out := &in.OrphanedWALRange, &out.OrphanedWALRange
+		*out = new(WALRange)
+		**out = **in
+	}
+	if in.DeletedBackups != nil {
+		in, out := &in.DeletedBackups, &out.DeletedBackups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectStoreReport.
+func (in *ObjectStoreReport) DeepCopy() *ObjectStoreReport {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectStoreReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PVCRetentionPolicy) DeepCopyInto(out *PVCRetentionPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PVCRetentionPolicy.
+func (in *PVCRetentionPolicy) DeepCopy() *PVCRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PVCRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgAuditConfiguration) DeepCopyInto(out *PgAuditConfiguration) {
+	*out = *in
+	if in.Log != nil {
+		in, out := &in.Log, &out.Log
+		*out = make([]PgAuditLogClass, len(*in))
+		copy(*out, *in)
+	}
+	if in.LogCatalog != nil {
+		in, out := &in.LogCatalog, &out.LogCatalog
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgAuditConfiguration.
+func (in *PgAuditConfiguration) DeepCopy() *PgAuditConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PgAuditConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PgBouncerAccessRule) DeepCopyInto(out *PgBouncerAccessRule) {
+	*out = *in
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PgBouncerAccessRule.
+func (in *PgBouncerAccessRule) DeepCopy() *PgBouncerAccessRule {
+	if in == nil {
+		return nil
+	}
+	out := new(PgBouncerAccessRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PgBouncerIntegrationStatus) DeepCopyInto(out *PgBouncerIntegrationStatus) {
 	*out = *in
@@ -1134,6 +2347,18 @@ func (in *PgBouncerSpec) DeepCopyInto(out *PgBouncerSpec) {
 			(*out)[key] = val
 		}
 	}
+	if in.AdminUsers != nil {
+		in, out := &in.AdminUsers, &out.AdminUsers
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AccessRules != nil {
+		in, out := &in.AccessRules, &out.AccessRules
+		*out = make([]PgBouncerAccessRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Paused != nil {
 		in, out := &in.Paused, &out.Paused
 		*out = new(bool)
@@ -1151,6 +2376,43 @@ func (in *PgBouncerSpec) DeepCopy() *PgBouncerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PhysicalBackupConfiguration) DeepCopyInto(out *PhysicalBackupConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PhysicalBackupConfiguration.
+func (in *PhysicalBackupConfiguration) DeepCopy() *PhysicalBackupConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PhysicalBackupConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PluginConfiguration) DeepCopyInto(out *PluginConfiguration) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PluginConfiguration.
+func (in *PluginConfiguration) DeepCopy() *PluginConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PluginConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodMeta) DeepCopyInto(out *PodMeta) {
 	*out = *in
@@ -1245,6 +2507,26 @@ func (in *Pooler) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerAffinityConfiguration) DeepCopyInto(out *PoolerAffinityConfiguration) {
+	*out = *in
+	if in.EnablePodAntiAffinity != nil {
+		in, out := &in.EnablePodAntiAffinity, &out.EnablePodAntiAffinity
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerAffinityConfiguration.
+func (in *PoolerAffinityConfiguration) DeepCopy() *PoolerAffinityConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerAffinityConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolerIntegrations) DeepCopyInto(out *PoolerIntegrations) {
 	*out = *in
@@ -1275,22 +2557,62 @@ func (in *PoolerList) DeepCopyInto(out *PoolerList) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerList.
-func (in *PoolerList) DeepCopy() *PoolerList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerList.
+func (in *PoolerList) DeepCopy() *PoolerList {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PoolerList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerMonitoringConfiguration) DeepCopyInto(out *PoolerMonitoringConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerMonitoringConfiguration.
+func (in *PoolerMonitoringConfiguration) DeepCopy() *PoolerMonitoringConfiguration {
 	if in == nil {
 		return nil
 	}
-	out := new(PoolerList)
+	out := new(PoolerMonitoringConfiguration)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *PoolerList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolerPodDisruptionBudget) DeepCopyInto(out *PoolerPodDisruptionBudget) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
 	}
-	return nil
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerPodDisruptionBudget.
+func (in *PoolerPodDisruptionBudget) DeepCopy() *PoolerPodDisruptionBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolerPodDisruptionBudget)
+	in.DeepCopyInto(out)
+	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
@@ -1330,6 +2652,21 @@ func (in *PoolerSpec) DeepCopyInto(out *PoolerSpec) {
 		*out = new(PgBouncerSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PodDisruptionBudget != nil {
+		in, out := &in.PodDisruptionBudget, &out.PodDisruptionBudget
+		*out = new(PoolerPodDisruptionBudget)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Affinity != nil {
+		in, out := &in.Affinity, &out.Affinity
+		*out = new(PoolerAffinityConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Monitoring != nil {
+		in, out := &in.Monitoring, &out.Monitoring
+		*out = new(PoolerMonitoringConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolerSpec.
@@ -1362,31 +2699,6 @@ func (in *PoolerStatus) DeepCopy() *PoolerStatus {
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *PostInitApplicationSQLRefs) DeepCopyInto(out *PostInitApplicationSQLRefs) {
-	*out = *in
-	if in.SecretRefs != nil {
-		in, out := &in.SecretRefs, &out.SecretRefs
-		*out = make([]SecretKeySelector, len(*in))
-		copy(*out, *in)
-	}
-	if in.ConfigMapRefs != nil {
-		in, out := &in.ConfigMapRefs, &out.ConfigMapRefs
-		*out = make([]ConfigMapKeySelector, len(*in))
-		copy(*out, *in)
-	}
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostInitApplicationSQLRefs.
-func (in *PostInitApplicationSQLRefs) DeepCopy() *PostInitApplicationSQLRefs {
-	if in == nil {
-		return nil
-	}
-	out := new(PostInitApplicationSQLRefs)
-	in.DeepCopyInto(out)
-	return out
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
 	*out = *in
@@ -1408,11 +2720,51 @@ func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExtensionImages != nil {
+		in, out := &in.ExtensionImages, &out.ExtensionImages
+		*out = make([]ExtensionImage, len(*in))
+		copy(*out, *in)
+	}
+	if in.PgAudit != nil {
+		in, out := &in.PgAudit, &out.PgAudit
+		*out = new(PgAuditConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logging != nil {
+		in, out := &in.Logging, &out.Logging
+		*out = new(PostgresLoggingConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Standby != nil {
+		in, out := &in.Standby, &out.Standby
+		*out = new(StandbyConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = new(ConnectionsConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Locale != nil {
+		in, out := &in.Locale, &out.Locale
+		*out = new(LocaleConfiguration)
+		**out = **in
+	}
+	if in.ConfigurationFiles != nil {
+		in, out := &in.ConfigurationFiles, &out.ConfigurationFiles
+		*out = make([]ConfigMapKeySelector, len(*in))
+		copy(*out, *in)
+	}
 	if in.LDAP != nil {
 		in, out := &in.LDAP, &out.LDAP
 		*out = new(LDAPConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.TDE != nil {
+		in, out := &in.TDE, &out.TDE
+		*out = new(TDEConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfiguration.
@@ -1425,6 +2777,121 @@ func (in *PostgresConfiguration) DeepCopy() *PostgresConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostgresLoggingConfiguration) DeepCopyInto(out *PostgresLoggingConfiguration) {
+	*out = *in
+	if in.MinDurationStatement != nil {
+		in, out := &in.MinDurationStatement, &out.MinDurationStatement
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresLoggingConfiguration.
+func (in *PostgresLoggingConfiguration) DeepCopy() *PostgresLoggingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PostgresLoggingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Probe) DeepCopyInto(out *Probe) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Probe.
+func (in *Probe) DeepCopy() *Probe {
+	if in == nil {
+		return nil
+	}
+	out := new(Probe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbesConfiguration) DeepCopyInto(out *ProbesConfiguration) {
+	*out = *in
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(Probe)
+		**out = **in
+	}
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(Probe)
+		**out = **in
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(ReadinessProbe)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbesConfiguration.
+func (in *ProbesConfiguration) DeepCopy() *ProbesConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbesConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessProbe) DeepCopyInto(out *ReadinessProbe) {
+	*out = *in
+	out.Probe = in.Probe
+	if in.MaximumLag != nil {
+		in, out := &in.MaximumLag, &out.MaximumLag
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessProbe.
+func (in *ReadinessProbe) DeepCopy() *ReadinessProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoveryRemapping) DeepCopyInto(out *RecoveryRemapping) {
+	*out = *in
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryRemapping.
+func (in *RecoveryRemapping) DeepCopy() *RecoveryRemapping {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoveryRemapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RecoveryTarget) DeepCopyInto(out *RecoveryTarget) {
 	*out = *in
@@ -1465,6 +2932,21 @@ func (in *ReplicaClusterConfiguration) DeepCopy() *ReplicaClusterConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationConnectionConfiguration) DeepCopyInto(out *ReplicationConnectionConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationConnectionConfiguration.
+func (in *ReplicationConnectionConfiguration) DeepCopy() *ReplicationConnectionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationConnectionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RollingUpdateStatus) DeepCopyInto(out *RollingUpdateStatus) {
 	*out = *in
@@ -1516,6 +2998,31 @@ func (in *S3Credentials) DeepCopy() *S3Credentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SQLRefs) DeepCopyInto(out *SQLRefs) {
+	*out = *in
+	if in.SecretRefs != nil {
+		in, out := &in.SecretRefs, &out.SecretRefs
+		*out = make([]SecretKeySelector, len(*in))
+		copy(*out, *in)
+	}
+	if in.ConfigMapRefs != nil {
+		in, out := &in.ConfigMapRefs, &out.ConfigMapRefs
+		*out = make([]ConfigMapKeySelector, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQLRefs.
+func (in *SQLRefs) DeepCopy() *SQLRefs {
+	if in == nil {
+		return nil
+	}
+	out := new(SQLRefs)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ScheduledBackup) DeepCopyInto(out *ScheduledBackup) {
 	*out = *in
@@ -1589,6 +3096,21 @@ func (in *ScheduledBackupSpec) DeepCopyInto(out *ScheduledBackupSpec) {
 		**out = **in
 	}
 	out.Cluster = in.Cluster
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(BackupHooks)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PluginConfiguration != nil {
+		in, out := &in.PluginConfiguration, &out.PluginConfiguration
+		*out = new(PluginConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Physical != nil {
+		in, out := &in.Physical, &out.Physical
+		*out = new(PhysicalBackupConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledBackupSpec.
@@ -1681,6 +3203,52 @@ func (in *SecretsResourceVersion) DeepCopy() *SecretsResourceVersion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountTemplate) DeepCopyInto(out *ServiceAccountTemplate) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountTemplate.
+func (in *ServiceAccountTemplate) DeepCopy() *ServiceAccountTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StandbyConfiguration) DeepCopyInto(out *StandbyConfiguration) {
+	*out = *in
+	if in.HotStandbyFeedback != nil {
+		in, out := &in.HotStandbyFeedback, &out.HotStandbyFeedback
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxStreamingDelay != nil {
+		in, out := &in.MaxStreamingDelay, &out.MaxStreamingDelay
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxArchiveDelay != nil {
+		in, out := &in.MaxArchiveDelay, &out.MaxArchiveDelay
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StandbyConfiguration.
+func (in *StandbyConfiguration) DeepCopy() *StandbyConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(StandbyConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 	*out = *in
@@ -1699,6 +3267,11 @@ func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 		*out = new(corev1.PersistentVolumeClaimSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.PersistentVolumeClaimRetentionPolicy != nil {
+		in, out := &in.PersistentVolumeClaimRetentionPolicy, &out.PersistentVolumeClaimRetentionPolicy
+		*out = new(PVCRetentionPolicy)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StorageConfiguration.
@@ -1731,6 +3304,26 @@ func (in *SyncReplicaElectionConstraints) DeepCopy() *SyncReplicaElectionConstra
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TDEConfiguration) DeepCopyInto(out *TDEConfiguration) {
+	*out = *in
+	if in.PassphraseSecret != nil {
+		in, out := &in.PassphraseSecret, &out.PassphraseSecret
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TDEConfiguration.
+func (in *TDEConfiguration) DeepCopy() *TDEConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TDEConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Topology) DeepCopyInto(out *Topology) {
 	*out = *in
@@ -1751,6 +3344,13 @@ func (in *Topology) DeepCopyInto(out *Topology) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.Locations != nil {
+		in, out := &in.Locations, &out.Locations
+		*out = make(map[PodName]InstanceLocation, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Topology.
@@ -1763,6 +3363,21 @@ func (in *Topology) DeepCopy() *Topology {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WALRange) DeepCopyInto(out *WALRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WALRange.
+func (in *WALRange) DeepCopy() *WALRange {
+	if in == nil {
+		return nil
+	}
+	out := new(WALRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WalBackupConfiguration) DeepCopyInto(out *WalBackupConfiguration) {
 	*out = *in
@@ -1777,3 +3392,18 @@ func (in *WalBackupConfiguration) DeepCopy() *WalBackupConfiguration {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WalSenderState) DeepCopyInto(out *WalSenderState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalSenderState.
+func (in *WalSenderState) DeepCopy() *WalSenderState {
+	if in == nil {
+		return nil
+	}
+	out := new(WalSenderState)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -19,8 +19,10 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -29,6 +31,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,6 +43,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/notifications"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
@@ -62,7 +66,8 @@ type BackupReconciler struct {
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters,verbs=get
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups="",resources=pods/exec,verbs=get;list;delete;patch;create;watch
-// +kubebuilder:rbac:groups="",resources=pods,verbs=get
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create
 
 // Reconcile is the main reconciliation loop
 func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -84,6 +89,14 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		return ctrl.Result{}, err
 	}
 
+	if backup.Spec.GetMethod() == apiv1.BackupMethodPhysical &&
+		len(backup.Status.Phase) != 0 && backup.Status.Phase != apiv1.BackupPhasePending &&
+		backup.Status.IsInProgress() {
+		// The Job taking the backup updates its status, but
+		// it can fail before having the chance to do it
+		return ctrl.Result{}, r.reconcilePhysicalBackupJob(ctx, &backup)
+	}
+
 	if len(backup.Status.Phase) != 0 && backup.Status.Phase != apiv1.BackupPhasePending {
 		// Nothing to do here
 		return ctrl.Result{}, nil
@@ -110,23 +123,28 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	contextLogger.Debug("Found cluster for backup", "cluster", clusterName)
 
 	// Detect the pod where a backup will be executed
+	targetPodName, err := r.getBackupTargetPodName(ctx, &cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
 	var pod corev1.Pod
-	err := r.Get(ctx, client.ObjectKey{
+	err = r.Get(ctx, client.ObjectKey{
 		Namespace: backup.Namespace,
-		Name:      cluster.Status.TargetPrimary,
+		Name:      targetPodName,
 	}, &pod)
 	if err != nil {
 		if apierrs.IsNotFound(err) {
 			r.Recorder.Eventf(&backup, "Warning", "FindingPod",
-				"Couldn't find target pod %s, will retry in 30 seconds", cluster.Status.TargetPrimary)
+				"Couldn't find target pod %s, will retry in 30 seconds", targetPodName)
 			contextLogger.Info("Couldn't find target pod, will retry in 30 seconds", "target",
-				cluster.Status.TargetPrimary)
+				targetPodName)
 			backup.Status.Phase = apiv1.BackupPhasePending
 			return ctrl.Result{RequeueAfter: 30 * time.Second}, r.Status().Update(ctx, &backup)
 		}
 		backup.Status.SetAsFailed(fmt.Errorf("while getting pod: %w", err))
 		r.Recorder.Eventf(&backup, "Warning", "FindingPod", "Error getting target pod: %s",
-			cluster.Status.TargetPrimary)
+			targetPodName)
 		return ctrl.Result{}, r.Status().Update(ctx, &backup)
 	}
 	contextLogger.Debug("Found pod for backup", "pod", pod.Name)
@@ -135,7 +153,7 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		contextLogger.Info("Not ready backup target, will retry in 30 seconds", "target", pod.Name)
 		backup.Status.Phase = apiv1.BackupPhasePending
 		r.Recorder.Eventf(&backup, "Warning", "BackupPending", "Backup target pod not ready: %s",
-			cluster.Status.TargetPrimary)
+			targetPodName)
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, r.Status().Update(ctx, &backup)
 	}
 
@@ -148,10 +166,10 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		}, &pod)
 		// we found the pod
 		if err == nil &&
-			// the pod is actually the target primary,
+			// the pod is actually the backup target (usually the target primary),
 			// we don't care whether it's the current one as running the backup on the new primary would
 			// still be the correct thing to do
-			backup.Status.InstanceID.PodName == cluster.Status.TargetPrimary &&
+			backup.Status.InstanceID.PodName == targetPodName &&
 			// the pod was not restarted since when we started the backup
 			backup.Status.InstanceID.ContainerID == pod.Status.ContainerStatuses[0].ContainerID &&
 			// the pod is active
@@ -176,6 +194,10 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		"cluster", cluster.Name,
 		"pod", pod.Name)
 
+	if backup.Spec.GetMethod() == apiv1.BackupMethodPhysical {
+		return ctrl.Result{}, r.startPhysicalBackup(ctx, &backup, pod, &cluster)
+	}
+
 	// This backup has been started
 	err = StartBackup(ctx, r.Client, &backup, pod, &cluster)
 	if err != nil {
@@ -185,6 +207,43 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	return ctrl.Result{}, err
 }
 
+// getBackupTargetPodName gets the name of the pod where the backup should be
+// taken, according to the backup target policy of the cluster
+func (r *BackupReconciler) getBackupTargetPodName(ctx context.Context, cluster *apiv1.Cluster) (string, error) {
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.Target != apiv1.BackupTargetStandby {
+		return cluster.Status.TargetPrimary, nil
+	}
+
+	var pods corev1.PodList
+	if err := r.List(ctx, &pods,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingFields{podOwnerKey: cluster.Name},
+	); err != nil {
+		return "", fmt.Errorf("while listing the instances: %w", err)
+	}
+
+	return selectBackupTargetPodName(cluster, pods.Items), nil
+}
+
+// selectBackupTargetPodName chooses the first ready standby in alphabetical
+// order, falling back to the target primary when there is none
+func selectBackupTargetPodName(cluster *apiv1.Cluster, pods []corev1.Pod) string {
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	for _, pod := range pods {
+		if pod.Name == cluster.Status.TargetPrimary || pod.Name == cluster.Status.CurrentPrimary {
+			continue
+		}
+		if utils.IsPodActive(pod) && utils.IsPodReady(pod) {
+			return pod.Name
+		}
+	}
+
+	return cluster.Status.TargetPrimary
+}
+
 // StartBackup request a backup in a Pod and marks the backup started
 // or failed if needed
 func StartBackup(
@@ -244,6 +303,62 @@ func StartBackup(
 	return nil
 }
 
+// startPhysicalBackup marks the backup as started and creates the Job
+// taking it from the passed Pod with the physical method
+func (r *BackupReconciler) startPhysicalBackup(
+	ctx context.Context,
+	backup *apiv1.Backup,
+	pod corev1.Pod,
+	cluster *apiv1.Cluster,
+) error {
+	status := backup.GetStatus()
+	status.Phase = apiv1.BackupPhaseStarted
+	status.InstanceID = &apiv1.InstanceID{PodName: pod.Name, ContainerID: pod.Status.ContainerStatuses[0].ContainerID}
+	if err := postgres.UpdateBackupStatusAndRetry(ctx, r.Client, backup); err != nil {
+		return err
+	}
+
+	job := specs.CreatePhysicalBackupJob(*cluster, *backup, pod)
+	if err := ctrl.SetControllerReference(backup, job, r.Scheme); err != nil {
+		return err
+	}
+
+	if err := r.Create(ctx, job); err != nil && !apierrs.IsAlreadyExists(err) {
+		r.Recorder.Eventf(backup, "Warning", "Error", "Can't create the physical backup job: %v", err)
+		status.SetAsFailed(fmt.Errorf("while creating the physical backup job: %w", err))
+		return postgres.UpdateBackupStatusAndRetry(ctx, r.Client, backup)
+	}
+
+	return nil
+}
+
+// reconcilePhysicalBackupJob marks as failed a physical backup whose
+// Job failed without updating the backup status
+func (r *BackupReconciler) reconcilePhysicalBackupJob(ctx context.Context, backup *apiv1.Backup) error {
+	var job batchv1.Job
+	err := r.Get(ctx, client.ObjectKey{
+		Namespace: backup.Namespace,
+		Name:      specs.GetPhysicalBackupJobName(backup.Name),
+	}, &job)
+	if apierrs.IsNotFound(err) {
+		// The Job may have just been created and not be in the cache yet
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !utils.IsJobFailed(job) {
+		return nil
+	}
+
+	backup.Status.SetAsFailed(fmt.Errorf("the physical backup job %s failed", job.Name))
+
+	r.Recorder.Eventf(backup, "Warning", "Error", "Physical backup failed: %s", backup.Status.Error)
+	// Updating the backup with its resource version, we don't
+	// overwrite the result written by the Job in the meantime
+	return r.Status().Update(ctx, backup)
+}
+
 // SetupWithManager sets up this controller given a controller manager
 func (r *BackupReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
 	if err := mgr.GetFieldIndexer().IndexField(
@@ -256,6 +371,8 @@ func (r *BackupReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manage
 	}
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1.Backup{}).
+		Owns(&batchv1.Job{}).
+		Watches(&source.Kind{Type: &apiv1.Backup{}}, backupFailureNotifier(ctx)).
 		Watches(&source.Kind{Type: &apiv1.Cluster{}},
 			handler.EnqueueRequestsFromMapFunc(r.mapClustersToBackup(ctx)),
 			builder.WithPredicates(clustersWithBackupPredicate),
@@ -263,6 +380,30 @@ func (r *BackupReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manage
 		Complete(r)
 }
 
+// backupFailureNotifier returns an event handler sending a notification
+// every time a backup fails. It doesn't enqueue any reconciliation request
+func backupFailureNotifier(ctx context.Context) handler.EventHandler {
+	return handler.Funcs{
+		UpdateFunc: func(e event.UpdateEvent, _ workqueue.RateLimitingInterface) {
+			oldBackup, oldOk := e.ObjectOld.(*apiv1.Backup)
+			newBackup, newOk := e.ObjectNew.(*apiv1.Backup)
+			if !oldOk || !newOk || !isBackupFailureTransition(oldBackup, newBackup) {
+				return
+			}
+
+			notifications.Notify(ctx, notifications.EventBackupFailed,
+				newBackup.Namespace, newBackup.Spec.Cluster.Name,
+				fmt.Sprintf("Backup %s failed: %s", newBackup.Name, newBackup.Status.Error))
+		},
+	}
+}
+
+// isBackupFailureTransition checks if a backup just moved to the failed phase
+func isBackupFailureTransition(oldBackup, newBackup *apiv1.Backup) bool {
+	return oldBackup.Status.Phase != apiv1.BackupPhaseFailed &&
+		newBackup.Status.Phase == apiv1.BackupPhaseFailed
+}
+
 func (r *BackupReconciler) mapClustersToBackup(ctx context.Context) handler.MapFunc {
 	return func(obj client.Object) []reconcile.Request {
 		cluster, ok := obj.(*apiv1.Cluster)
@@ -0,0 +1,108 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backup target selection", func() {
+	cluster := &apiv1.Cluster{
+		Status: apiv1.ClusterStatus{
+			CurrentPrimary: "cluster-example-1",
+			TargetPrimary:  "cluster-example-1",
+		},
+	}
+
+	It("chooses the first ready standby", func() {
+		pods := []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					Conditions: []corev1.PodCondition{
+						{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					Conditions: []corev1.PodCondition{
+						{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					Conditions: []corev1.PodCondition{
+						{Type: corev1.ContainersReady, Status: corev1.ConditionFalse},
+					},
+				},
+			},
+		}
+		Expect(selectBackupTargetPodName(cluster, pods)).To(Equal("cluster-example-3"))
+	})
+
+	It("falls back to the primary when no standby is ready", func() {
+		pods := []corev1.Pod{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					Conditions: []corev1.PodCondition{
+						{Type: corev1.ContainersReady, Status: corev1.ConditionTrue},
+					},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"},
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					Conditions: []corev1.PodCondition{
+						{Type: corev1.ContainersReady, Status: corev1.ConditionFalse},
+					},
+				},
+			},
+		}
+		Expect(selectBackupTargetPodName(cluster, pods)).To(Equal("cluster-example-1"))
+	})
+})
+
+var _ = Describe("Backup failure notifications", func() {
+	runningBackup := &apiv1.Backup{Status: apiv1.BackupStatus{Phase: apiv1.BackupPhaseRunning}}
+	completedBackup := &apiv1.Backup{Status: apiv1.BackupStatus{Phase: apiv1.BackupPhaseCompleted}}
+	failedBackup := &apiv1.Backup{Status: apiv1.BackupStatus{Phase: apiv1.BackupPhaseFailed}}
+
+	It("detects the backups which just failed", func() {
+		Expect(isBackupFailureTransition(runningBackup, failedBackup)).To(BeTrue())
+	})
+
+	It("ignores the backups which were already failed or didn't fail", func() {
+		Expect(isBackupFailureTransition(failedBackup, failedBackup)).To(BeFalse())
+		Expect(isBackupFailureTransition(runningBackup, completedBackup)).To(BeFalse())
+	})
+})
@@ -40,6 +40,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -72,6 +73,7 @@ type ClusterReconciler struct {
 	Recorder        record.EventRecorder
 
 	timeoutHTTPClient *http.Client
+	primaryHeartbeats primaryHeartbeatObserver
 }
 
 // NewClusterReconciler creates a new ClusterReconciler initializing it
@@ -109,12 +111,15 @@ var ErrNextLoop = errors.New("stop this loop and return the associated Result ob
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;update;list
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;update;list
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;delete;patch;create;watch
-// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;delete
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors,verbs=get;create;list;watch;delete;patch
+// +kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;create;list;watch;delete;patch
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=create;delete;get;list;watch;update;patch
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters/finalizers,verbs=update
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters/status,verbs=get;watch;update;patch
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=imagecatalogs,verbs=get;watch;list
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusterimagecatalogs,verbs=get;watch;list
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=create;patch;update;get;list;watch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=create;patch;update;get;list;watch
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;watch;delete;patch
@@ -157,11 +162,29 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, err
 	}
 
+	if !cluster.DeletionTimestamp.IsZero() {
+		// The cluster is being deleted, and we only need to
+		// release its PVCs if the retention policy requires it
+		return ctrl.Result{}, r.reconcileClusterDeletion(ctx, cluster)
+	}
+
 	// Run the inner reconcile loop. Translate any ErrNextLoop to an errorless return
 	result, err := r.reconcile(ctx, cluster)
 	if errors.Is(err, ErrNextLoop) {
 		return result, nil
 	}
+	if err == nil && result.IsZero() {
+		// Periodically compare the generated resources with their expected
+		// definition, even when no watch event is received
+		result.RequeueAfter = cluster.GetDriftDetectionInterval()
+
+		// The expiration of the heartbeat lease of the primary doesn't
+		// generate any event, so we need to check it periodically. As the
+		// renewals are seen only when we check, we do it twice per timeout
+		if timeout := cluster.GetPrimaryHeartbeatTimeout() / 2; timeout > 0 && timeout < result.RequeueAfter {
+			result.RequeueAfter = timeout
+		}
+	}
 	return result, err
 }
 
@@ -194,6 +217,25 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, err
 	}
 
+	r.reportConfigurationWarnings(cluster)
+
+	// Make sure the PVCs will survive the cluster deletion, if required
+	if err := r.reconcilePVCRetentionFinalizer(ctx, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the PVC retention finalizer: %w", err)
+	}
+
+	// Resolve the image of the cluster from the image catalog
+	if res, err := r.reconcileImage(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	} else if res != nil {
+		return *res, nil
+	}
+
+	// Detect the architectures provided by the image, used in the node affinity
+	if err := r.reconcileImageArchitectures(ctx, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the image architectures: %w", err)
+	}
+
 	// Ensure we have the required global objects
 	if err := r.createPostgresClusterObjects(ctx, cluster); err != nil {
 		return ctrl.Result{}, fmt.Errorf("cannot create Cluster auxiliary objects: %w", err)
@@ -230,6 +272,11 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 	// Get the replication status
 	instancesStatus := r.getStatusFromInstances(ctx, resources.instances)
 
+	// Consider failed a primary that is not renewing its heartbeat lease
+	if err := r.checkPrimaryHeartbeat(ctx, cluster, &instancesStatus); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot check the primary heartbeat: %w", err)
+	}
+
 	// we update all the cluster status fields that require the instances status
 	if err := r.updateClusterStatusThatRequiresInstancesState(ctx, cluster, instancesStatus); err != nil {
 		if apierrs.IsConflict(err) {
@@ -238,9 +285,18 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, fmt.Errorf("cannot update the instances status on the cluster: %w", err)
 	}
 
+	// Compare the roles and the timelines of the instances, blocking the
+	// automated failovers when they diverge
+	if err := r.reconcileSplitBrainDetection(ctx, cluster, instancesStatus); err != nil {
+		if apierrs.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot update the split-brain detection condition: %w", err)
+	}
+
 	// Verify the architecture of all the instances and update the OnlineUpdateEnabled
 	// field in the status
-	onlineUpdateEnabled := configuration.Current.EnableInstanceManagerInplaceUpdates
+	onlineUpdateEnabled := configuration.Current().EnableInstanceManagerInplaceUpdates
 	isArchitectureConsistent := r.checkPodsArchitecture(ctx, &instancesStatus)
 	if !isArchitectureConsistent && onlineUpdateEnabled {
 		contextLogger.Info("Architecture mismatch detected, disabling instance manager online updates")
@@ -310,6 +366,22 @@ func (r *ClusterReconciler) handleSwitchover(
 			contextLogger.Info("Waiting for all WAL receivers to be down to elect a new primary")
 			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 		}
+		if err == ErrNoFailoverCandidate {
+			contextLogger.Info("Waiting for an instance that can be promoted")
+			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+		}
+		if err == ErrWaitingOnFailoverApproval {
+			contextLogger.Info("Waiting for the user to approve the failover")
+			return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+		if err == ErrWaitingOnFailOverDelay {
+			contextLogger.Info("Waiting for the failover delay to expire")
+			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+		}
+		if err == ErrSplitBrainSuspected {
+			contextLogger.Info("Waiting for the split-brain to be resolved before changing the primary")
+			return &ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+		}
 		contextLogger.Info("Cannot update target primary: operation cannot be fulfilled. "+
 			"An immediate retry will be scheduled",
 			"cluster", cluster.Name)
@@ -352,12 +424,34 @@ func (r *ClusterReconciler) getCluster(
 	if !namespace.DeletionTimestamp.IsZero() {
 		// This happens when you delete a namespace containing a Cluster resource. If that's the case,
 		// let's just wait for the Kubernetes to remove all object in the namespace.
+		// The PVCs are going to be removed together with the namespace, so there's
+		// no point in retaining them, and the finalizer would block the deletion
+		if controllerutil.ContainsFinalizer(cluster, utils.RetainPVCsFinalizerName) {
+			origCluster := cluster.DeepCopy()
+			controllerutil.RemoveFinalizer(cluster, utils.RetainPVCsFinalizerName)
+			if err := r.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+				return nil, fmt.Errorf("cannot remove the PVC retention finalizer: %w", err)
+			}
+		}
 		return nil, nil
 	}
 
 	return cluster, nil
 }
 
+// reportConfigurationWarnings emits a warning event for each risk found in
+// the configuration of the cluster, which is valid but might not be what
+// the user wants. Repeated events are aggregated by the event recorder
+func (r *ClusterReconciler) reportConfigurationWarnings(cluster *apiv1.Cluster) {
+	if cluster.Spec.PostgresConfiguration.Standby == nil {
+		return
+	}
+
+	for _, warning := range cluster.Spec.PostgresConfiguration.Standby.GetWarnings() {
+		r.Recorder.Event(cluster, "Warning", "StandbyConfiguration", warning)
+	}
+}
+
 func (r *ClusterReconciler) setDefaults(ctx context.Context, cluster *apiv1.Cluster) error {
 	contextLogger := log.FromContext(ctx)
 	originCluster := cluster.DeepCopy()
@@ -418,6 +512,14 @@ func (r *ClusterReconciler) reconcileResources(
 		return ctrl.Result{}, fmt.Errorf("cannot update annotations on pvcs: %w", err)
 	}
 
+	// Update the metadata requested for the generated Pods and PVCs
+	if err := r.updateGeneratedMetadataOnPods(ctx, cluster, resources.instances); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot update the generated metadata on pods: %w", err)
+	}
+	if err := r.updateGeneratedMetadataOnPVCs(ctx, cluster, resources.pvcs); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot update the generated metadata on pvcs: %w", err)
+	}
+
 	// Act on Pods and PVCs only if there is nothing that is currently being created or deleted
 	if runningJobs := resources.countRunningJobs(); runningJobs > 0 {
 		contextLogger.Debug("A job is currently running. Waiting", "count", runningJobs)
@@ -641,6 +743,15 @@ func (r *ClusterReconciler) ReconcilePods(ctx context.Context, cluster *apiv1.Cl
 	// Are there missing nodes? Let's create one
 	if cluster.Status.Instances < cluster.Spec.Instances &&
 		instancesStatus.InstancesReportingStatus() == cluster.Status.Instances {
+		// Reuse the PVCs retained by a previous scale-down, if possible
+		reattached, err := r.reattachRetainedPVCs(ctx, cluster, resources)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("cannot reattach retained PVCs: %w", err)
+		}
+		if reattached {
+			return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
+		}
+
 		newNodeSerial, err := r.generateNodeSerial(ctx, cluster)
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("cannot generate node serial: %w", err)
@@ -715,10 +826,29 @@ func (r *ClusterReconciler) handleRollingUpdate(
 		return ctrl.Result{}, err
 	}
 	if done {
+		if cluster.Status.Phase == apiv1.PhaseWaitingForMaintenanceWindow {
+			// Check again when the next maintenance window may have started
+			return ctrl.Result{RequeueAfter: 1 * time.Minute}, ErrNextLoop
+		}
+		if cluster.Status.Phase == apiv1.PhaseWaitingForSwitchoverTarget {
+			// Check again when a replica may be ready to be promoted
+			return ctrl.Result{RequeueAfter: 10 * time.Second}, ErrNextLoop
+		}
 		// Rolling upgrade is in progress, let's avoid marking stuff as synchronized
 		return ctrl.Result{}, ErrNextLoop
 	}
 
+	// The update to a new image is deferred by the image update policy:
+	// wait for the next maintenance window, if any
+	if deferred, reason, requeueAfter := getDeferredImageUpdate(
+		cluster, &instancesStatus, time.Now()); deferred {
+		contextLogger.Info("Waiting for the image update to be allowed", "reason", reason)
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForImageUpdate, reason); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: requeueAfter}, ErrNextLoop
+	}
+
 	if instancesStatus.ArePodsWaitingForDecreasedSettings() {
 		// requeue and wait for the pods to be ready to be restarted,
 		// which will be handled by rolloutDueToCondition
@@ -774,6 +904,14 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 			&source.Kind{Type: &apiv1.Pooler{}},
 			handler.EnqueueRequestsFromMapFunc(r.mapPoolersToClusters(ctx)),
 		).
+		Watches(
+			&source.Kind{Type: &apiv1.ImageCatalog{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapImageCatalogsToClusters(ctx)),
+		).
+		Watches(
+			&source.Kind{Type: &apiv1.ClusterImageCatalog{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapImageCatalogsToClusters(ctx)),
+		).
 		Watches(
 			&source.Kind{Type: &corev1.Node{}},
 			handler.EnqueueRequestsFromMapFunc(r.mapNodeToClusters(ctx)),
@@ -819,6 +957,25 @@ func (r *ClusterReconciler) createFieldIndexes(ctx context.Context, mgr ctrl.Man
 		return err
 	}
 
+	// Create a new indexed field on Clusters. This field will be used to easily
+	// find all the Clusters using an image catalog
+	if err := mgr.GetFieldIndexer().IndexField(
+		ctx,
+		&apiv1.Cluster{},
+		imageCatalogKey, func(rawObj client.Object) []string {
+			cluster := rawObj.(*apiv1.Cluster)
+			if cluster.Spec.ImageCatalogRef == nil {
+				return nil
+			}
+
+			return []string{getImageCatalogIndexValue(
+				cluster.Spec.ImageCatalogRef.Kind,
+				cluster.Spec.ImageCatalogRef.Name,
+			)}
+		}); err != nil {
+		return err
+	}
+
 	// Create a new indexed field on Pods. This field will be used to easily
 	// find all the Pods created by node
 	if err := mgr.GetFieldIndexer().IndexField(
@@ -930,9 +1087,9 @@ func (r *ClusterReconciler) getClustersForSecretsOrConfigMapsToClustersMapper(
 	}
 
 	// Get all the clusters handled by the operator in the secret namespaces
-	if object.GetNamespace() == configuration.Current.OperatorNamespace &&
-		((isConfigMap && object.GetName() == configuration.Current.MonitoringQueriesConfigmap) ||
-			(isSecret && object.GetName() == configuration.Current.MonitoringQueriesSecret)) {
+	if object.GetNamespace() == configuration.Current().OperatorNamespace &&
+		((isConfigMap && object.GetName() == configuration.Current().MonitoringQueriesConfigmap) ||
+			(isSecret && object.GetName() == configuration.Current().MonitoringQueriesSecret)) {
 		// The events in MonitoringQueriesSecrets impacts all the clusters.
 		// We proceed to fetch all the clusters and create a reconciliation request for them.
 		// This works as long as the replicated MonitoringQueriesConfigmap in the different namespaces
@@ -1116,15 +1273,15 @@ func (r *ClusterReconciler) deleteOldCustomQueriesConfigmap(ctx context.Context,
 
 	// if the cluster didn't have default monitoring queries, do nothing
 	if cluster.Spec.Monitoring.AreDefaultQueriesDisabled() ||
-		configuration.Current.MonitoringQueriesConfigmap == "" ||
-		configuration.Current.MonitoringQueriesConfigmap == apiv1.DefaultMonitoringConfigMapName {
+		configuration.Current().MonitoringQueriesConfigmap == "" ||
+		configuration.Current().MonitoringQueriesConfigmap == apiv1.DefaultMonitoringConfigMapName {
 		return
 	}
 
 	// otherwise, remove the old default monitoring queries configmap from the cluster and delete it, if present
 	oldCmID := -1
 	for idx, cm := range cluster.Spec.Monitoring.CustomQueriesConfigMap {
-		if cm.Name == configuration.Current.MonitoringQueriesConfigmap &&
+		if cm.Name == configuration.Current().MonitoringQueriesConfigmap &&
 			cm.Key == apiv1.DefaultMonitoringKey {
 			oldCmID = idx
 			break
@@ -1139,7 +1296,7 @@ func (r *ClusterReconciler) deleteOldCustomQueriesConfigmap(ctx context.Context,
 	// if we found it, we are going to get it and check it was actually created by the operator or was already deleted
 	var oldCm corev1.ConfigMap
 	err := r.Get(ctx, types.NamespacedName{
-		Name:      configuration.Current.MonitoringQueriesConfigmap,
+		Name:      configuration.Current().MonitoringQueriesConfigmap,
 		Namespace: cluster.Namespace,
 	}, &oldCm)
 	// if we found it, we check the annotation the operator should have set to be sure it was created by us
@@ -1151,7 +1308,7 @@ func (r *ClusterReconciler) deleteOldCustomQueriesConfigmap(ctx context.Context,
 			if err != nil && !apierrs.IsNotFound(err) {
 				contextLogger.Warning("error while deleting old default monitoring custom queries configmap",
 					"err", err,
-					"configmap", configuration.Current.MonitoringQueriesConfigmap)
+					"configmap", configuration.Current().MonitoringQueriesConfigmap)
 				return
 			}
 		} else {
@@ -1166,7 +1323,7 @@ func (r *ClusterReconciler) deleteOldCustomQueriesConfigmap(ctx context.Context,
 		// if there is any error except the cm was already deleted, we return
 		contextLogger.Warning("error while getting old default monitoring custom queries configmap",
 			"err", err,
-			"configmap", configuration.Current.MonitoringQueriesConfigmap)
+			"configmap", configuration.Current().MonitoringQueriesConfigmap)
 		return
 	}
 	// both if it exists or not, if we are here we should delete it from the list of custom queries configmaps
@@ -1178,6 +1335,6 @@ func (r *ClusterReconciler) deleteOldCustomQueriesConfigmap(ctx context.Context,
 		log.Warning("had an error while removing the old custom monitoring queries configmap from "+
 			"the monitoring section in the cluster",
 			"err", err,
-			"configmap", configuration.Current.MonitoringQueriesConfigmap)
+			"configmap", configuration.Current().MonitoringQueriesConfigmap)
 	}
 }
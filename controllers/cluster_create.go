@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strconv"
 	"time"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
@@ -61,11 +62,26 @@ func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cl
 		return err
 	}
 
+	err = r.updateGeneratedMetadataOnSecretsAndServices(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	err = r.reconcilePrimaryExternalService(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
 	err = r.reconcilePodDisruptionBudget(ctx, cluster)
 	if err != nil {
 		return err
 	}
 
+	err = r.reconcilePrimaryHeartbeatLease(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
 	err = r.createOrPatchServiceAccount(ctx, cluster)
 	if err != nil {
 		return err
@@ -93,6 +109,16 @@ func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cl
 		return err
 	}
 
+	err = r.createOrPatchPrometheusRule(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	err = r.reconcileGeneratedResourcesDrift(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
 	// TODO: only required to cleanup custom monitoring queries configmaps from older versions (v1.10 and v1.11)
 	// 		 that could have been copied with the source configmap name instead of the new default one.
 	// 		 Should be removed in future releases.
@@ -175,8 +201,9 @@ func (r *ClusterReconciler) reconcileSuperuserSecret(ctx context.Context, cluste
 			cluster.Namespace,
 			cluster.GetServiceReadWriteName(),
 			"*",
-			"postgres",
+			cluster.GetSuperuserName(),
 			postgresPassword)
+		setGeneratedObjectMetadata(&postgresSecret.ObjectMeta, cluster.GetGeneratedSecretsMetadata())
 		SetClusterOwnerAnnotationsAndLabels(&postgresSecret.ObjectMeta, cluster)
 
 		if err := r.Create(ctx, postgresSecret); err != nil {
@@ -222,6 +249,7 @@ func (r *ClusterReconciler) reconcileAppUserSecret(ctx context.Context, cluster
 			cluster.GetApplicationDatabaseOwner(),
 			appPassword)
 
+		setGeneratedObjectMetadata(&appSecret.ObjectMeta, cluster.GetGeneratedSecretsMetadata())
 		SetClusterOwnerAnnotationsAndLabels(&appSecret.ObjectMeta, cluster)
 		if err := r.Create(ctx, appSecret); err != nil {
 			if !apierrs.IsAlreadyExists(err) {
@@ -270,43 +298,34 @@ func (r *ClusterReconciler) reconcilePoolerSecrets(ctx context.Context, cluster
 }
 
 func (r *ClusterReconciler) createPostgresServices(ctx context.Context, cluster *apiv1.Cluster) error {
-	anyService := specs.CreateClusterAnyService(*cluster)
-	SetClusterOwnerAnnotationsAndLabels(&anyService.ObjectMeta, cluster)
+	for _, service := range buildPostgresServices(cluster) {
+		SetClusterOwnerAnnotationsAndLabels(&service.ObjectMeta, cluster)
 
-	if err := r.Create(ctx, anyService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
-			return err
-		}
-	}
-
-	readService := specs.CreateClusterReadService(*cluster)
-	SetClusterOwnerAnnotationsAndLabels(&readService.ObjectMeta, cluster)
-
-	if err := r.Create(ctx, readService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
-			return err
+		if err := r.Create(ctx, service); err != nil {
+			if !apierrs.IsAlreadyExists(err) {
+				return err
+			}
 		}
 	}
 
-	readOnlyService := specs.CreateClusterReadOnlyService(*cluster)
-	SetClusterOwnerAnnotationsAndLabels(&readOnlyService.ObjectMeta, cluster)
+	return nil
+}
 
-	if err := r.Create(ctx, readOnlyService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
-			return err
-		}
+// buildPostgresServices builds the services of the cluster,
+// with the metadata requested in the generated objects section
+func buildPostgresServices(cluster *apiv1.Cluster) []*corev1.Service {
+	services := []*corev1.Service{
+		specs.CreateClusterAnyService(*cluster),
+		specs.CreateClusterReadService(*cluster),
+		specs.CreateClusterReadOnlyService(*cluster),
+		specs.CreateClusterReadWriteService(*cluster),
 	}
 
-	readWriteService := specs.CreateClusterReadWriteService(*cluster)
-	SetClusterOwnerAnnotationsAndLabels(&readWriteService.ObjectMeta, cluster)
-
-	if err := r.Create(ctx, readWriteService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
-			return err
-		}
+	for _, service := range services {
+		setGeneratedObjectMetadata(&service.ObjectMeta, cluster.GetGeneratedServicesMetadata())
 	}
 
-	return nil
+	return services
 }
 
 // createOrPatchOwnedPodDisruptionBudget ensures that we have a PDB requiring to remove one node at a time
@@ -340,6 +359,11 @@ func (r *ClusterReconciler) createOrPatchOwnedPodDisruptionBudget(
 		return nil
 	}
 
+	if !cluster.IsDriftAutoRepairEnabled() {
+		// The difference is reported in the ResourcesInSync condition
+		return nil
+	}
+
 	r.Recorder.Event(cluster, "Normal", "UpdatingPodDisruptionBudget",
 		fmt.Sprintf("Updating PodDisruptionBudget %s", pdb.Name))
 
@@ -417,7 +441,12 @@ func (r *ClusterReconciler) createOrPatchServiceAccount(ctx context.Context, clu
 		return fmt.Errorf("while generating pull secret names: %w", err)
 	}
 
-	serviceAccountAligned, err := specs.IsServiceAccountAligned(&sa, generatedPullSecretNames)
+	var saTemplateMetadata apiv1.Metadata
+	if cluster.Spec.ServiceAccountTemplate != nil {
+		saTemplateMetadata = cluster.Spec.ServiceAccountTemplate.Metadata
+	}
+
+	serviceAccountAligned, err := specs.IsServiceAccountAligned(&sa, generatedPullSecretNames, saTemplateMetadata)
 	if err != nil {
 		contextLogger.Error(err, "Cannot detect if a ServiceAccount need to be refreshed or not, refreshing it",
 			"serviceAccount", sa)
@@ -433,6 +462,7 @@ func (r *ClusterReconciler) createOrPatchServiceAccount(ctx context.Context, clu
 	if err != nil {
 		return fmt.Errorf("while generating service account: %w", err)
 	}
+	cluster.Spec.ServiceAccountTemplate.MergeMetadata(&sa)
 
 	r.Recorder.Event(cluster, "Normal", "UpdatingServiceAccount", "Updating ServiceAccount")
 	SetClusterOwnerAnnotationsAndLabels(&sa.ObjectMeta, cluster)
@@ -460,6 +490,7 @@ func (r *ClusterReconciler) createServiceAccount(ctx context.Context, cluster *a
 	if err != nil {
 		return fmt.Errorf("while creating new ServiceAccount: %w", err)
 	}
+	cluster.Spec.ServiceAccountTemplate.MergeMetadata(serviceAccount)
 
 	SetClusterOwnerAnnotationsAndLabels(&serviceAccount.ObjectMeta, cluster)
 	err = r.Create(ctx, serviceAccount)
@@ -499,7 +530,7 @@ func (r *ClusterReconciler) generateServiceAccountPullSecretsNames(
 // operator was downloaded via a Secret.
 // It will return the string of the secret name if a secret need to be used to use the operator
 func (r *ClusterReconciler) copyPullSecretFromOperator(ctx context.Context, cluster *apiv1.Cluster) (string, error) {
-	if configuration.Current.OperatorNamespace == "" {
+	if configuration.Current().OperatorNamespace == "" {
 		// We are not getting started via a k8s deployment. Perhaps we are running in our development environment
 		return "", nil
 	}
@@ -507,8 +538,8 @@ func (r *ClusterReconciler) copyPullSecretFromOperator(ctx context.Context, clus
 	// Let's find the operator secret
 	var operatorSecret corev1.Secret
 	if err := r.Get(ctx, client.ObjectKey{
-		Name:      configuration.Current.OperatorPullSecretName,
-		Namespace: configuration.Current.OperatorNamespace,
+		Name:      configuration.Current().OperatorPullSecretName,
+		Namespace: configuration.Current().OperatorNamespace,
 	}, &operatorSecret); err != nil {
 		if apierrs.IsNotFound(err) {
 			// There is no secret like that, probably because we are running in our development environment
@@ -584,8 +615,8 @@ func (r *ClusterReconciler) createOrPatchDefaultMetricsConfigmap(ctx context.Con
 	var sourceConfigmap corev1.ConfigMap
 	if err := r.Get(ctx,
 		client.ObjectKey{
-			Name:      configuration.Current.MonitoringQueriesConfigmap,
-			Namespace: configuration.Current.OperatorNamespace,
+			Name:      configuration.Current().MonitoringQueriesConfigmap,
+			Namespace: configuration.Current().OperatorNamespace,
 		}, &sourceConfigmap); err != nil {
 		if apierrs.IsNotFound(err) {
 			contextLogger.Error(err, "while trying to get default metrics configMap")
@@ -600,8 +631,8 @@ func (r *ClusterReconciler) createOrPatchDefaultMetricsConfigmap(ctx context.Con
 		return nil
 	}
 
-	if cluster.Namespace == configuration.Current.OperatorNamespace &&
-		configuration.Current.MonitoringQueriesConfigmap == apiv1.DefaultMonitoringConfigMapName {
+	if cluster.Namespace == configuration.Current().OperatorNamespace &&
+		configuration.Current().MonitoringQueriesConfigmap == apiv1.DefaultMonitoringConfigMapName {
 		contextLogger.Debug(
 			"skipping default metrics synchronization. The cluster resides in the same namespace of the operator",
 			"clusterNamespace", cluster.Namespace,
@@ -650,6 +681,11 @@ func (r *ClusterReconciler) createOrPatchDefaultMetricsConfigmap(ctx context.Con
 		return nil
 	}
 
+	if !cluster.IsDriftAutoRepairEnabled() {
+		// The difference is reported in the ResourcesInSync condition
+		return nil
+	}
+
 	// The configuration changed, and we need the patch the secret we have
 	patchedConfigMap := targetConfigMap.DeepCopy()
 	utils.SetOperatorVersion(&patchedConfigMap.ObjectMeta, versions.Version)
@@ -671,8 +707,8 @@ func (r *ClusterReconciler) createOrPatchDefaultMetricsSecret(ctx context.Contex
 	var sourceSecret corev1.Secret
 	if err := r.Get(ctx,
 		client.ObjectKey{
-			Name:      configuration.Current.MonitoringQueriesSecret,
-			Namespace: configuration.Current.OperatorNamespace,
+			Name:      configuration.Current().MonitoringQueriesSecret,
+			Namespace: configuration.Current().OperatorNamespace,
 		}, &sourceSecret); err != nil {
 		if apierrs.IsNotFound(err) {
 			contextLogger.Error(err, "while trying to get default metrics secret")
@@ -687,8 +723,8 @@ func (r *ClusterReconciler) createOrPatchDefaultMetricsSecret(ctx context.Contex
 		return nil
 	}
 
-	if cluster.Namespace == configuration.Current.OperatorNamespace &&
-		configuration.Current.MonitoringQueriesSecret == apiv1.DefaultMonitoringSecretName {
+	if cluster.Namespace == configuration.Current().OperatorNamespace &&
+		configuration.Current().MonitoringQueriesSecret == apiv1.DefaultMonitoringSecretName {
 		contextLogger.Debug(
 			"skipping default metrics synchronization. The cluster resides in the same namespace of the operator",
 			"clusterNamespace", cluster.Namespace,
@@ -737,6 +773,11 @@ func (r *ClusterReconciler) createOrPatchDefaultMetricsSecret(ctx context.Contex
 		return nil
 	}
 
+	if !cluster.IsDriftAutoRepairEnabled() {
+		// The difference is reported in the ResourcesInSync condition
+		return nil
+	}
+
 	// The configuration changed, and we need the patch the secret we have
 	patchedSecret := targetSecret.DeepCopy()
 	utils.SetOperatorVersion(&patchedSecret.ObjectMeta, versions.Version)
@@ -750,13 +791,13 @@ func (r *ClusterReconciler) createOrPatchDefaultMetricsSecret(ctx context.Contex
 }
 
 func (r *ClusterReconciler) createOrPatchDefaultMetrics(ctx context.Context, cluster *apiv1.Cluster) (err error) {
-	if configuration.Current.MonitoringQueriesConfigmap != "" {
+	if configuration.Current().MonitoringQueriesConfigmap != "" {
 		err = r.createOrPatchDefaultMetricsConfigmap(ctx, cluster)
 		if err != nil {
 			return err
 		}
 	}
-	if configuration.Current.MonitoringQueriesSecret != "" {
+	if configuration.Current().MonitoringQueriesSecret != "" {
 		err = r.createOrPatchDefaultMetricsSecret(ctx, cluster)
 		if err != nil {
 			return err
@@ -827,6 +868,69 @@ func (r *ClusterReconciler) createOrPatchPodMonitor(ctx context.Context, cluster
 	}
 }
 
+// createOrPatchPrometheusRule creates, updates or deletes the PrometheusRule
+// containing the default alerting rules of the cluster
+func (r *ClusterReconciler) createOrPatchPrometheusRule(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	// Checking for the PrometheusRule resource in the cluster
+	havePrometheusRule, err := utils.PrometheusRuleExist(r.DiscoveryClient)
+	if err != nil || !havePrometheusRule {
+		contextLogger.Debug("Kind PrometheusRule not detected", "err", err)
+		return err
+	}
+
+	// We get the current prometheus rule
+	prometheusRule := &monitoringv1.PrometheusRule{}
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{
+			Name:      cluster.Name,
+			Namespace: cluster.Namespace,
+		},
+		prometheusRule,
+	); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while getting the prometheusrule: %w", err)
+		}
+		prometheusRule = nil
+	}
+
+	switch {
+	// Prometheus rule disabled and no prometheus rule - nothing to do
+	case !cluster.IsPrometheusRuleEnabled() && prometheusRule == nil:
+		return nil
+	// Prometheus rule disabled and prometheus rule present - delete it
+	case !cluster.IsPrometheusRuleEnabled() && prometheusRule != nil:
+		contextLogger.Info("Deleting PrometheusRule")
+		if err := r.Delete(ctx, prometheusRule); err != nil {
+			if !apierrs.IsNotFound(err) {
+				return err
+			}
+		}
+		return nil
+	// Prometheus rule enabled and no prometheus rule - create it
+	case cluster.IsPrometheusRuleEnabled() && prometheusRule == nil:
+		contextLogger.Debug("Creating PrometheusRule")
+		newPrometheusRule := specs.CreatePrometheusRule(cluster)
+		SetClusterOwnerAnnotationsAndLabels(&newPrometheusRule.ObjectMeta, cluster)
+		return r.Create(ctx, newPrometheusRule)
+	// Prometheus rule enabled and prometheus rule present - update it
+	default:
+		origPrometheusRule := prometheusRule.DeepCopy()
+		prometheusRule.Spec = specs.CreatePrometheusRule(cluster).Spec
+
+		// If there's no changes we are done
+		if reflect.DeepEqual(origPrometheusRule, prometheusRule) {
+			return nil
+		}
+
+		// Patch the PrometheusRule, so we always reconcile it with the cluster changes
+		contextLogger.Debug("Patching PrometheusRule")
+		return r.Patch(ctx, prometheusRule, client.MergeFrom(origPrometheusRule))
+	}
+}
+
 // createRole creates the role
 func (r *ClusterReconciler) createRole(ctx context.Context, cluster *apiv1.Cluster, backupOrigin *apiv1.Backup) error {
 	role := specs.CreateRole(*cluster, backupOrigin)
@@ -891,18 +995,32 @@ func (r *ClusterReconciler) createPrimaryInstance(
 		return ctrl.Result{}, nil
 	}
 
+	if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.Adopt != nil {
+		return r.createPrimaryInstanceAdoptingPVCs(ctx, cluster)
+	}
+
 	// Generate a new node serial
 	nodeSerial, err := r.generateNodeSerial(ctx, cluster)
 	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("cannot generate node serial: %w", err)
 	}
 
+	// When recovering from volume snapshots, the PVCs of the
+	// first instance are cloned from them
+	var pgDataSource, pgWalSource *corev1.TypedLocalObjectReference
+	if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.Recovery != nil &&
+		cluster.Spec.Bootstrap.Recovery.VolumeSnapshots != nil {
+		pgDataSource = &cluster.Spec.Bootstrap.Recovery.VolumeSnapshots.Storage
+		pgWalSource = cluster.Spec.Bootstrap.Recovery.VolumeSnapshots.WalStorage
+	}
+
 	if err := r.createPVC(
 		ctx,
 		cluster,
 		cluster.Spec.StorageConfiguration,
 		nodeSerial,
 		utils.PVCRolePgData,
+		pgDataSource,
 	); err != nil {
 		return ctrl.Result{RequeueAfter: time.Minute}, err
 	}
@@ -914,6 +1032,7 @@ func (r *ClusterReconciler) createPrimaryInstance(
 			*cluster.Spec.WalStorage,
 			nodeSerial,
 			utils.PVCRolePgWal,
+			pgWalSource,
 		); err != nil {
 			return ctrl.Result{RequeueAfter: time.Minute}, err
 		}
@@ -950,20 +1069,65 @@ func (r *ClusterReconciler) createPrimaryInstance(
 		job = specs.CreatePrimaryJobViaInitdb(*cluster, nodeSerial)
 	}
 
+	return r.createPrimaryJob(ctx, cluster, nodeSerial, job)
+}
+
+// createPrimaryInstanceAdoptingPVCs takes the ownership of the existing PVCs
+// of the instance chosen by the user and creates the Job checking them and
+// configuring them to be used as the primary instance of the cluster
+func (r *ClusterReconciler) createPrimaryInstanceAdoptingPVCs(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (ctrl.Result, error) {
+	nodeSerial := cluster.Spec.Bootstrap.Adopt.InstanceSerial
+
+	// We adopt the PVCs before updating the latest generated serial,
+	// so that we can try again if the PVCs are not there yet
+	if err := r.adoptPVC(ctx, cluster, nodeSerial, utils.PVCRolePgData); err != nil {
+		return ctrl.Result{RequeueAfter: time.Minute}, err
+	}
+
+	if cluster.ShouldCreateWalArchiveVolume() {
+		if err := r.adoptPVC(ctx, cluster, nodeSerial, utils.PVCRolePgWal); err != nil {
+			return ctrl.Result{RequeueAfter: time.Minute}, err
+		}
+	}
+
+	// The next instances will be generated after the adopted one
+	cluster.Status.LatestGeneratedNode = nodeSerial
+	if err := r.Status().Update(ctx, cluster); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	r.Recorder.Event(cluster, "Normal", "CreatingInstance", "Primary instance (adopting existing PVCs)")
+	job := specs.CreatePrimaryJobViaAdopt(*cluster, nodeSerial)
+
+	return r.createPrimaryJob(ctx, cluster, nodeSerial, job)
+}
+
+// createPrimaryJob creates the Job bootstrapping the primary
+// instance with the passed serial
+func (r *ClusterReconciler) createPrimaryJob(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	nodeSerial int,
+	job *batchv1.Job,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
 	if err := ctrl.SetControllerReference(cluster, job, r.Scheme); err != nil {
 		contextLogger.Error(err, "Unable to set the owner reference for instance")
 		return ctrl.Result{}, err
 	}
 
 	podName := fmt.Sprintf("%v-%v", cluster.Name, nodeSerial)
-	if err = r.setPrimaryInstance(ctx, cluster, podName); err != nil {
+	if err := r.setPrimaryInstance(ctx, cluster, podName); err != nil {
 		contextLogger.Error(err, "Unable to set the primary instance name")
 		return ctrl.Result{}, err
 	}
 
-	err = r.RegisterPhase(ctx, cluster, apiv1.PhaseFirstPrimary,
-		fmt.Sprintf("Creating primary instance %v", podName))
-	if err != nil {
+	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFirstPrimary,
+		fmt.Sprintf("Creating primary instance %v", podName)); err != nil {
 		return ctrl.Result{}, err
 	}
 
@@ -973,15 +1137,20 @@ func (r *ClusterReconciler) createPrimaryInstance(
 
 	utils.SetOperatorVersion(&job.ObjectMeta, versions.Version)
 	utils.InheritAnnotations(&job.ObjectMeta, cluster.Annotations,
-		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+		cluster.GetFixedInheritedAnnotations(), configuration.Current())
 	utils.InheritAnnotations(&job.Spec.Template.ObjectMeta, cluster.Annotations,
-		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+		cluster.GetFixedInheritedAnnotations(), configuration.Current())
 	utils.InheritLabels(&job.ObjectMeta, cluster.Labels,
-		cluster.GetFixedInheritedLabels(), configuration.Current)
+		cluster.GetFixedInheritedLabels(), configuration.Current())
 	utils.InheritLabels(&job.Spec.Template.ObjectMeta, cluster.Labels,
-		cluster.GetFixedInheritedLabels(), configuration.Current)
+		cluster.GetFixedInheritedLabels(), configuration.Current())
 
-	if err = r.Create(ctx, job); err != nil {
+	if err := r.mutateJobWithPlugins(ctx, cluster, job); err != nil {
+		contextLogger.Error(err, "Unable to apply the plugins to the job", "job", job.Name)
+		return ctrl.Result{}, err
+	}
+
+	if err := r.Create(ctx, job); err != nil {
 		if apierrs.IsAlreadyExists(err) {
 			// This Job was already created, maybe the cache is stale.
 			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
@@ -994,6 +1163,50 @@ func (r *ClusterReconciler) createPrimaryInstance(
 	return ctrl.Result{RequeueAfter: 30 * time.Second}, ErrNextLoop
 }
 
+// adoptPVC takes the ownership of an existing PVC, which must be named
+// after the instance having the passed serial, marking it as initializing
+func (r *ClusterReconciler) adoptPVC(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	nodeSerial int,
+	role utils.PVCRole,
+) error {
+	instanceName := fmt.Sprintf("%s-%v", cluster.Name, nodeSerial)
+	pvcName := specs.GetPVCName(*cluster, instanceName, role)
+
+	var pvc corev1.PersistentVolumeClaim
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: pvcName}, &pvc); err != nil {
+		if apierrs.IsNotFound(err) {
+			r.Recorder.Eventf(cluster, "Warning", "MissingPVC",
+				"Cannot find the PVC %v to be adopted", pvcName)
+		}
+		return fmt.Errorf("while getting the PVC %v to be adopted: %w", pvcName, err)
+	}
+
+	if owner := metav1.GetControllerOf(&pvc); owner != nil && owner.UID != cluster.UID {
+		r.Recorder.Eventf(cluster, "Warning", "PVCNotAdoptable",
+			"The PVC %v is controlled by %v %v and cannot be adopted", pvcName, owner.Kind, owner.Name)
+		return fmt.Errorf("the PVC %v is controlled by %v %v and cannot be adopted",
+			pvcName, owner.Kind, owner.Name)
+	}
+
+	origPVC := pvc.DeepCopy()
+	SetClusterOwnerAnnotationsAndLabels(&pvc.ObjectMeta, cluster)
+	pvc.Labels[utils.InstanceNameLabelName] = instanceName
+	pvc.Labels[utils.PvcRoleLabelName] = string(role)
+	if pvc.Annotations == nil {
+		pvc.Annotations = make(map[string]string)
+	}
+	pvc.Annotations[specs.ClusterSerialAnnotationName] = strconv.Itoa(nodeSerial)
+	pvc.Annotations[specs.PVCStatusAnnotationName] = specs.PVCStatusInitializing
+
+	log.FromContext(ctx).Info("Adopting existing PVC",
+		"pvc", pvcName,
+		"instance", instanceName)
+
+	return r.Patch(ctx, &pvc, client.MergeFrom(origPVC))
+}
+
 // getOriginBackup gets the backup that is used to bootstrap a new PostgreSQL cluster
 func (r *ClusterReconciler) getOriginBackup(ctx context.Context, cluster *apiv1.Cluster) (*apiv1.Backup, error) {
 	if cluster.Spec.Bootstrap == nil ||
@@ -1020,6 +1233,7 @@ func (r *ClusterReconciler) getOriginBackup(ctx context.Context, cluster *apiv1.
 		return nil, fmt.Errorf("cannot get the backup object: %w", err)
 	}
 
+	cluster.GetRecoveryRemapping().ApplyToBackup(&backup)
 	return &backup, nil
 }
 
@@ -1055,13 +1269,18 @@ func (r *ClusterReconciler) joinReplicaInstance(
 
 	utils.SetOperatorVersion(&job.ObjectMeta, versions.Version)
 	utils.InheritAnnotations(&job.ObjectMeta, cluster.Annotations,
-		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+		cluster.GetFixedInheritedAnnotations(), configuration.Current())
 	utils.InheritAnnotations(&job.Spec.Template.ObjectMeta, cluster.Annotations,
-		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+		cluster.GetFixedInheritedAnnotations(), configuration.Current())
 	utils.InheritLabels(&job.ObjectMeta, cluster.Labels,
-		cluster.GetFixedInheritedLabels(), configuration.Current)
+		cluster.GetFixedInheritedLabels(), configuration.Current())
 	utils.InheritLabels(&job.Spec.Template.ObjectMeta, cluster.Labels,
-		cluster.GetFixedInheritedLabels(), configuration.Current)
+		cluster.GetFixedInheritedLabels(), configuration.Current())
+
+	if err = r.mutateJobWithPlugins(ctx, cluster, job); err != nil {
+		contextLogger.Error(err, "Unable to apply the plugins to the job", "job", job.Name)
+		return ctrl.Result{}, err
+	}
 
 	if err = r.Create(ctx, job); err != nil {
 		if apierrs.IsAlreadyExists(err) {
@@ -1080,6 +1299,7 @@ func (r *ClusterReconciler) joinReplicaInstance(
 		cluster.Spec.StorageConfiguration,
 		nodeSerial,
 		utils.PVCRolePgData,
+		nil,
 	); err != nil {
 		return ctrl.Result{RequeueAfter: time.Minute}, err
 	}
@@ -1091,6 +1311,7 @@ func (r *ClusterReconciler) joinReplicaInstance(
 			*cluster.Spec.WalStorage,
 			nodeSerial,
 			utils.PVCRolePgWal,
+			nil,
 		); err != nil {
 			return ctrl.Result{RequeueAfter: time.Minute}, err
 		}
@@ -1163,7 +1384,7 @@ func (r *ClusterReconciler) reconcilePVCs(
 
 	pod := specs.PodWithExistingStorage(*cluster, nodeSerial)
 
-	if configuration.Current.EnableAzurePVCUpdates {
+	if configuration.Current().EnableAzurePVCUpdates {
 		for _, pvcName := range cluster.Status.ResizingPVC {
 			// if the pvc is in resizing state we requeue and wait
 			if pvcName == pvc.Name {
@@ -1181,6 +1402,16 @@ func (r *ClusterReconciler) reconcilePVCs(
 		pod.Annotations[specs.ClusterRestartAnnotationName] = clusterRestart
 	}
 
+	// If the PVC has been retained by a scale-down, let the instance manager
+	// know that it may need to align the data directory with the primary
+	_, reattached := pvc.Annotations[specs.ReattachedAnnotationName]
+	if reattached {
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[specs.ReattachedAnnotationName] = "true"
+	}
+
 	contextLogger.Info("Creating new Pod to reattach a PVC",
 		"pod", pod.Name,
 		"pvc", pvc.Name)
@@ -1191,9 +1422,14 @@ func (r *ClusterReconciler) reconcilePVCs(
 
 	utils.SetOperatorVersion(&pod.ObjectMeta, versions.Version)
 	utils.InheritAnnotations(&pod.ObjectMeta, cluster.Annotations,
-		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+		cluster.GetFixedInheritedAnnotations(), configuration.Current())
 	utils.InheritLabels(&pod.ObjectMeta, cluster.Labels,
-		cluster.GetFixedInheritedLabels(), configuration.Current)
+		cluster.GetFixedInheritedLabels(), configuration.Current())
+	setGeneratedObjectMetadata(&pod.ObjectMeta, cluster.GetGeneratedPodsMetadata())
+
+	if err := r.mutatePodWithPlugins(ctx, cluster, pod); err != nil {
+		return ctrl.Result{}, fmt.Errorf("unable to apply the plugins to the Pod: %w", err)
+	}
 
 	if err := r.Create(ctx, pod); err != nil {
 		if apierrs.IsAlreadyExists(err) {
@@ -1206,10 +1442,43 @@ func (r *ClusterReconciler) reconcilePVCs(
 		return ctrl.Result{}, fmt.Errorf("unable to create Pod: %w", err)
 	}
 
+	if reattached {
+		if err := r.removeReattachedAnnotation(ctx, cluster, resources, pod.Name); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Do another reconcile cycle after handling a dangling PVC
 	return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 }
 
+// removeReattachedAnnotation removes the reattached mark from the PVCs
+// of an instance, now that it has been moved to its Pod
+func (r *ClusterReconciler) removeReattachedAnnotation(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	instanceName string,
+) error {
+	for idx := range resources.pvcs.Items {
+		pvc := &resources.pvcs.Items[idx]
+		if !specs.DoesPVCBelongToInstance(cluster, instanceName, pvc.Name) {
+			continue
+		}
+		if _, ok := pvc.Annotations[specs.ReattachedAnnotationName]; !ok {
+			continue
+		}
+
+		origPVC := pvc.DeepCopy()
+		delete(pvc.Annotations, specs.ReattachedAnnotationName)
+		if err := r.Patch(ctx, pvc, client.MergeFrom(origPVC)); err != nil {
+			return fmt.Errorf("while removing the reattached mark from PVC %s: %w", pvc.Name, err)
+		}
+	}
+
+	return nil
+}
+
 // electPvcToReattach chooses a PVC between the initializing and the dangling ones that should be reattached
 // to the cluster, giving precedence to the target primary if existing in the set. If the target primary is fine,
 // let's start using the PVC we have initialized. After that we use the PVC that are initializing or dangling
@@ -1263,6 +1532,7 @@ func (r *ClusterReconciler) createPVC(
 	storageConfiguration apiv1.StorageConfiguration,
 	nodeSerial int,
 	role utils.PVCRole,
+	dataSource *corev1.TypedLocalObjectReference,
 ) error {
 	contextLogger := log.FromContext(ctx)
 
@@ -1279,7 +1549,11 @@ func (r *ClusterReconciler) createPVC(
 		}
 		return fmt.Errorf("unable to create a PVC spec for node with serial %v: %w", nodeSerial, err)
 	}
+	if dataSource != nil {
+		pvc.Spec.DataSource = dataSource.DeepCopy()
+	}
 
+	setGeneratedObjectMetadata(&pvc.ObjectMeta, cluster.GetGeneratedPVCsMetadata())
 	SetClusterOwnerAnnotationsAndLabels(&pvc.ObjectMeta, cluster)
 
 	if err = r.Create(ctx, pvc); err != nil && !apierrs.IsAlreadyExists(err) {
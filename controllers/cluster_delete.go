@@ -31,15 +31,15 @@ import (
 // deleteDanglingMonitoringQueries deletes the default monitoring configMap and/or secret if no cluster in the namespace
 // is using it.
 func (r *ClusterReconciler) deleteDanglingMonitoringQueries(ctx context.Context, namespace string) error {
-	configMapName := configuration.Current.MonitoringQueriesConfigmap
-	secretName := configuration.Current.MonitoringQueriesSecret
+	configMapName := configuration.Current().MonitoringQueriesConfigmap
+	secretName := configuration.Current().MonitoringQueriesSecret
 	if secretName == "" && configMapName == "" {
 		// no configmap or secretName configured, we can exit.
 		return nil
 	}
 
 	// we avoid deleting the operator configmap.
-	if namespace == configuration.Current.OperatorNamespace {
+	if namespace == configuration.Current().OperatorNamespace {
 		return nil
 	}
 
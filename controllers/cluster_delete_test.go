@@ -36,8 +36,9 @@ var _ = Describe("ensures that deleteDanglingMonitoringQueries works correctly",
 	const cmName = apiv1.DefaultMonitoringConfigMapName
 
 	BeforeEach(func() {
-		configuration.Current = configuration.NewConfiguration()
-		configuration.Current.MonitoringQueriesConfigmap = cmName
+		config := configuration.NewConfiguration()
+		config.MonitoringQueriesConfigmap = cmName
+		configuration.SetCurrent(config)
 	})
 
 	It("should make sure that a dangling monitoring queries config map is deleted", func() {
@@ -0,0 +1,495 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/policy/v1beta1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
+)
+
+// driftedResource is a resource generated by the operator which differs
+// from its expected definition
+type driftedResource struct {
+	// The kind of the resource, like Service or Secret
+	kind string
+
+	// The resource as read from the API server
+	current client.Object
+
+	// A copy of the resource having the expected definition, or nil
+	// when the resource can't be restored by patching it, as it happens
+	// to the Pods whose specification changed, which are rolled out
+	repaired client.Object
+}
+
+// String gets the kind and the name of the drifted resource
+func (drift driftedResource) String() string {
+	return drift.kind + "/" + drift.current.GetName()
+}
+
+// reconcileGeneratedResourcesDrift compares the Services, Secrets,
+// ConfigMaps, PodDisruptionBudgets and Pods generated by the operator with
+// their expected definition, restoring the ones changed out of band or,
+// when the automatic repair is disabled, reporting them in the
+// ResourcesInSync condition
+func (r *ClusterReconciler) reconcileGeneratedResourcesDrift(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	detectors := []func(context.Context, *apiv1.Cluster) ([]driftedResource, error){
+		r.detectServicesDrift,
+		r.detectSecretsDrift,
+		r.detectDefaultMetricsDrift,
+		r.detectPodDisruptionBudgetsDrift,
+		r.detectPodsDrift,
+	}
+
+	var drifts []driftedResource
+	for _, detect := range detectors {
+		detected, err := detect(ctx, cluster)
+		if err != nil {
+			return err
+		}
+		drifts = append(drifts, detected...)
+	}
+
+	var driftedResources []string
+	for _, drift := range drifts {
+		if !cluster.IsDriftAutoRepairEnabled() || drift.repaired == nil {
+			driftedResources = append(driftedResources, drift.String())
+			continue
+		}
+
+		contextLogger.Info("Repairing a resource changed out of band",
+			"kind", drift.kind, "name", drift.current.GetName())
+		if err := r.Patch(ctx, drift.repaired, client.MergeFrom(drift.current)); err != nil {
+			return fmt.Errorf("while repairing %s: %w", drift, err)
+		}
+		r.Recorder.Eventf(cluster, "Normal", "RepairedDrift",
+			"Restored the expected definition of %s", drift)
+	}
+
+	return r.setResourcesInSyncCondition(ctx, cluster, driftedResources)
+}
+
+// detectServicesDrift compares the services generated by
+// the operator with their expected definition
+func (r *ClusterReconciler) detectServicesDrift(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) ([]driftedResource, error) {
+	var drifts []driftedResource
+	for _, expectedService := range buildPostgresServices(cluster) {
+		var service corev1.Service
+		err := r.Get(ctx, client.ObjectKeyFromObject(expectedService), &service)
+		if apierrs.IsNotFound(err) {
+			// The service will be created again by createPostgresServices
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("while getting service %s: %w", expectedService.Name, err)
+		}
+
+		if owner, isOwned := IsOwnedByCluster(&service); !isOwned || owner != cluster.Name {
+			continue
+		}
+
+		if repairedService, drifted := repairServiceDrift(cluster, &service, expectedService); drifted {
+			drifts = append(drifts, driftedResource{kind: "Service", current: &service, repaired: repairedService})
+		}
+	}
+
+	return drifts, nil
+}
+
+// repairServiceDrift returns a copy of the passed service having the
+// expected definition, and whether the service has been changed out of band.
+// The fields which are not set by the operator, and the additional labels
+// and annotations, are preserved
+func repairServiceDrift(
+	cluster *apiv1.Cluster,
+	service *corev1.Service,
+	expectedService *corev1.Service,
+) (*corev1.Service, bool) {
+	repairedService := service.DeepCopy()
+	repairedService.Spec.Type = expectedService.Spec.Type
+	repairedService.Spec.Selector = expectedService.Spec.Selector
+	repairedService.Spec.PublishNotReadyAddresses = expectedService.Spec.PublishNotReadyAddresses
+	repairedService.Spec.Ports = expectedService.Spec.Ports
+	setGeneratedObjectMetadata(&repairedService.ObjectMeta, &apiv1.EmbeddedObjectMetadata{
+		Labels:      expectedService.Labels,
+		Annotations: expectedService.Annotations,
+	})
+	utils.LabelClusterName(&repairedService.ObjectMeta, cluster.Name)
+
+	return repairedService, !reflect.DeepEqual(service, repairedService)
+}
+
+// detectSecretsDrift compares the secrets containing the credentials
+// generated by the operator with their expected definition. The secrets
+// provided by the users and the certificates, which are renewed by the
+// operator, are not considered
+func (r *ClusterReconciler) detectSecretsDrift(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) ([]driftedResource, error) {
+	type credentials struct {
+		secretName string
+		dbname     string
+		username   string
+	}
+
+	var generatedCredentials []credentials
+	if cluster.GetEnableSuperuserAccess() &&
+		(cluster.Spec.SuperuserSecret == nil || cluster.Spec.SuperuserSecret.Name == "") {
+		generatedCredentials = append(generatedCredentials, credentials{
+			secretName: cluster.GetSuperuserSecretName(),
+			dbname:     "*",
+			username:   cluster.GetSuperuserName(),
+		})
+	}
+	if cluster.ShouldCreateApplicationSecret() {
+		generatedCredentials = append(generatedCredentials, credentials{
+			secretName: cluster.GetApplicationSecretName(),
+			dbname:     cluster.GetApplicationDatabaseName(),
+			username:   cluster.GetApplicationDatabaseOwner(),
+		})
+	}
+
+	var drifts []driftedResource
+	for _, item := range generatedCredentials {
+		var secret corev1.Secret
+		err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: item.secretName}, &secret)
+		if apierrs.IsNotFound(err) || apierrs.IsForbidden(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("while getting secret %s: %w", item.secretName, err)
+		}
+
+		if owner, isOwned := IsOwnedByCluster(&secret); !isOwned || owner != cluster.Name {
+			continue
+		}
+
+		password, hasPassword := secret.Data["password"]
+		if !hasPassword {
+			// The password can't be restored, so this secret is only reported
+			drifts = append(drifts, driftedResource{kind: "Secret", current: &secret})
+			continue
+		}
+
+		expectedSecret := specs.CreateSecret(
+			secret.Name,
+			secret.Namespace,
+			cluster.GetServiceReadWriteName(),
+			item.dbname,
+			item.username,
+			string(password))
+		setGeneratedObjectMetadata(&expectedSecret.ObjectMeta, cluster.GetGeneratedSecretsMetadata())
+		if repairedSecret, drifted := repairSecretDrift(cluster, &secret, expectedSecret); drifted {
+			drifts = append(drifts, driftedResource{kind: "Secret", current: &secret, repaired: repairedSecret})
+		}
+	}
+
+	return drifts, nil
+}
+
+// repairSecretDrift returns a copy of the passed secret having the
+// expected definition, and whether the secret has been changed out of band.
+// The expected definition must be built with the current password, as it
+// can be changed by the users. The additional keys, labels and annotations
+// are preserved
+func repairSecretDrift(
+	cluster *apiv1.Cluster,
+	secret *corev1.Secret,
+	expectedSecret *corev1.Secret,
+) (*corev1.Secret, bool) {
+	repairedSecret := secret.DeepCopy()
+	if repairedSecret.Data == nil {
+		repairedSecret.Data = make(map[string][]byte, len(expectedSecret.StringData))
+	}
+	for key, value := range expectedSecret.StringData {
+		repairedSecret.Data[key] = []byte(value)
+	}
+	setGeneratedObjectMetadata(&repairedSecret.ObjectMeta, &apiv1.EmbeddedObjectMetadata{
+		Labels:      expectedSecret.Labels,
+		Annotations: expectedSecret.Annotations,
+	})
+	utils.LabelClusterName(&repairedSecret.ObjectMeta, cluster.Name)
+
+	return repairedSecret, !reflect.DeepEqual(secret, repairedSecret)
+}
+
+// detectDefaultMetricsDrift compares the copies of the default monitoring
+// queries in the namespace of the cluster with the ones of the operator
+func (r *ClusterReconciler) detectDefaultMetricsDrift(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) ([]driftedResource, error) {
+	if cluster.Spec.Monitoring.AreDefaultQueriesDisabled() {
+		return nil, nil
+	}
+
+	var drifts []driftedResource
+	if name := configuration.Current().MonitoringQueriesConfigmap; name != "" {
+		var source, target corev1.ConfigMap
+		found, err := r.getDefaultMetricsCopy(ctx, cluster, name, apiv1.DefaultMonitoringConfigMapName, &source, &target)
+		if err != nil {
+			return nil, err
+		}
+		if _, hasQueries := source.Data[apiv1.DefaultMonitoringKey]; found && hasQueries &&
+			!reflect.DeepEqual(source.Data, target.Data) {
+			repaired := target.DeepCopy()
+			repaired.Data = source.Data
+			utils.SetOperatorVersion(&repaired.ObjectMeta, versions.Version)
+			drifts = append(drifts, driftedResource{kind: "ConfigMap", current: &target, repaired: repaired})
+		}
+	}
+
+	if name := configuration.Current().MonitoringQueriesSecret; name != "" {
+		var source, target corev1.Secret
+		found, err := r.getDefaultMetricsCopy(ctx, cluster, name, apiv1.DefaultMonitoringSecretName, &source, &target)
+		if err != nil {
+			return nil, err
+		}
+		if _, hasQueries := source.Data[apiv1.DefaultMonitoringKey]; found && hasQueries &&
+			!reflect.DeepEqual(source.Data, target.Data) {
+			repaired := target.DeepCopy()
+			repaired.Data = source.Data
+			utils.SetOperatorVersion(&repaired.ObjectMeta, versions.Version)
+			drifts = append(drifts, driftedResource{kind: "Secret", current: &target, repaired: repaired})
+		}
+	}
+
+	return drifts, nil
+}
+
+// getDefaultMetricsCopy gets the object with the default monitoring queries
+// in the namespace of the operator, and the copy the operator made of it in
+// the namespace of the cluster. It returns false when any of them doesn't
+// exist, when they are the same object, or when the copy has not been
+// created by the operator
+func (r *ClusterReconciler) getDefaultMetricsCopy(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	sourceName string,
+	targetName string,
+	source client.Object,
+	target client.Object,
+) (bool, error) {
+	operatorNamespace := configuration.Current().OperatorNamespace
+	if cluster.Namespace == operatorNamespace && sourceName == targetName {
+		return false, nil
+	}
+
+	if err := r.Get(ctx, client.ObjectKey{Namespace: operatorNamespace, Name: sourceName}, source); err != nil {
+		if apierrs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("while getting the default monitoring queries %s: %w", sourceName, err)
+	}
+
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: targetName}, target); err != nil {
+		if apierrs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("while getting the default monitoring queries %s: %w", targetName, err)
+	}
+
+	_, isGenerated := target.GetAnnotations()[utils.OperatorVersionAnnotationName]
+	return isGenerated, nil
+}
+
+// detectPodDisruptionBudgetsDrift compares the PodDisruptionBudgets
+// generated by the operator with their expected definition
+func (r *ClusterReconciler) detectPodDisruptionBudgetsDrift(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) ([]driftedResource, error) {
+	var drifts []driftedResource
+	for _, expectedPdb := range []*v1beta1.PodDisruptionBudget{
+		specs.BuildPrimaryPodDisruptionBudget(cluster),
+		specs.BuildReplicasPodDisruptionBudget(cluster),
+	} {
+		if expectedPdb == nil {
+			continue
+		}
+
+		var pdb v1beta1.PodDisruptionBudget
+		err := r.Get(ctx, client.ObjectKeyFromObject(expectedPdb), &pdb)
+		if apierrs.IsNotFound(err) {
+			// The PodDisruptionBudget is not required, or will
+			// be created again by reconcilePodDisruptionBudget
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("while getting PodDisruptionBudget %s: %w", expectedPdb.Name, err)
+		}
+
+		if owner, isOwned := IsOwnedByCluster(&pdb); !isOwned || owner != cluster.Name {
+			continue
+		}
+
+		if reflect.DeepEqual(pdb.Spec, expectedPdb.Spec) {
+			continue
+		}
+
+		repairedPdb := pdb.DeepCopy()
+		repairedPdb.Spec = expectedPdb.Spec
+		drifts = append(drifts, driftedResource{kind: "PodDisruptionBudget", current: &pdb, repaired: repairedPdb})
+	}
+
+	return drifts, nil
+}
+
+// detectPodsDrift compares the Pods of the instances with their expected
+// definition. The labels set by the operator are restored by patching
+// the Pod, while the Pods whose specification changed are rolled out
+func (r *ClusterReconciler) detectPodsDrift(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) ([]driftedResource, error) {
+	pods, err := r.getManagedInstances(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var drifts []driftedResource
+	for idx := range pods.Items {
+		pod := &pods.Items[idx]
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		if getPodSpecDriftReason(cluster, *pod) != "" {
+			drifts = append(drifts, driftedResource{kind: "Pod", current: pod})
+			continue
+		}
+
+		if repairedPod, drifted := repairPodMetadataDrift(cluster, pod); drifted {
+			drifts = append(drifts, driftedResource{kind: "Pod", current: pod, repaired: repairedPod})
+		}
+	}
+
+	return drifts, nil
+}
+
+// repairPodMetadataDrift returns a copy of the passed Pod having the labels
+// set by the operator, and whether they have been changed out of band.
+// The Pods without a serial can't be compared with their expected
+// definition, and are skipped
+func repairPodMetadataDrift(cluster *apiv1.Cluster, pod *corev1.Pod) (*corev1.Pod, bool) {
+	nodeSerial, err := specs.GetNodeSerial(pod.ObjectMeta)
+	if err != nil {
+		return nil, false
+	}
+
+	expectedPod := specs.PodWithExistingStorage(*cluster, nodeSerial)
+	repairedPod := pod.DeepCopy()
+	setGeneratedObjectMetadata(&repairedPod.ObjectMeta, &apiv1.EmbeddedObjectMetadata{
+		Labels: expectedPod.Labels,
+	})
+
+	return repairedPod, !reflect.DeepEqual(pod, repairedPod)
+}
+
+// getPodSpecDriftReason checks whether the fields of the specification of a
+// Pod which can be changed while it is running, which are its tolerations
+// and its active deadline, have been changed out of band, returning the
+// reason of the rollout or an empty string. The images are not considered,
+// as they are updated by the rolling update
+func getPodSpecDriftReason(cluster *apiv1.Cluster, pod corev1.Pod) string {
+	if pod.Spec.ActiveDeadlineSeconds != nil {
+		return fmt.Sprintf("the active deadline of the pod has been set to %d seconds",
+			*pod.Spec.ActiveDeadlineSeconds)
+	}
+
+	for idx := range pod.Spec.Tolerations {
+		toleration := &pod.Spec.Tolerations[idx]
+		if isDefaultToleration(toleration) || isTolerationRequested(cluster, toleration) {
+			continue
+		}
+		return fmt.Sprintf("the pod tolerates the taint %q, which is not requested by the cluster",
+			toleration.Key)
+	}
+
+	return ""
+}
+
+// isDefaultToleration checks whether the passed toleration is one of the
+// ones Kubernetes adds to every Pod, to evict it from unreachable nodes
+func isDefaultToleration(toleration *corev1.Toleration) bool {
+	return toleration.Effect == corev1.TaintEffectNoExecute &&
+		(toleration.Key == corev1.TaintNodeNotReady || toleration.Key == corev1.TaintNodeUnreachable)
+}
+
+// isTolerationRequested checks whether the passed
+// toleration is requested in the cluster definition
+func isTolerationRequested(cluster *apiv1.Cluster, toleration *corev1.Toleration) bool {
+	for idx := range cluster.Spec.Affinity.Tolerations {
+		if cluster.Spec.Affinity.Tolerations[idx].MatchToleration(toleration) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setResourcesInSyncCondition updates the ResourcesInSync condition
+// given the list of the resources which differ from their expected
+// definition
+func (r *ClusterReconciler) setResourcesInSyncCondition(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	driftedResources []string,
+) error {
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionResourcesInSync),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonResourcesInSync),
+		Message: "The generated resources match their expected definition",
+	}
+	if len(driftedResources) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(apiv1.ConditionReasonResourcesDrifted)
+		condition.Message = fmt.Sprintf("Resources differing from their expected definition: %s",
+			strings.Join(driftedResources, ", "))
+	}
+
+	origCluster := cluster.DeepCopy()
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	if reflect.DeepEqual(origCluster.Status.Conditions, cluster.Status.Conditions) {
+		return nil
+	}
+
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
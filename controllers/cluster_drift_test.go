@@ -0,0 +1,217 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Drift of the generated services", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			GeneratedObjects: &apiv1.GeneratedObjectsConfiguration{
+				Services: &apiv1.EmbeddedObjectMetadata{
+					Labels: map[string]string{"team": "dba"},
+				},
+			},
+		},
+	}
+
+	It("builds the services with the generated metadata", func() {
+		services := buildPostgresServices(cluster)
+		Expect(services).To(HaveLen(4))
+		for _, service := range services {
+			Expect(service.Labels).To(HaveKeyWithValue("team", "dba"))
+		}
+	})
+
+	It("doesn't detect any drift in an untouched service", func() {
+		expectedService := buildPostgresServices(cluster)[3]
+		service := expectedService.DeepCopy()
+		service.Spec.ClusterIP = "10.0.0.1"
+		utils.LabelClusterName(&service.ObjectMeta, cluster.Name)
+
+		_, drifted := repairServiceDrift(cluster, service, expectedService)
+		Expect(drifted).To(BeFalse())
+	})
+
+	It("preserves the fields and the metadata not managed by the operator", func() {
+		expectedService := buildPostgresServices(cluster)[3]
+		service := expectedService.DeepCopy()
+		service.Spec.ClusterIP = "10.0.0.1"
+		utils.LabelClusterName(&service.ObjectMeta, cluster.Name)
+		service.Annotations = map[string]string{"external": "true"}
+
+		_, drifted := repairServiceDrift(cluster, service, expectedService)
+		Expect(drifted).To(BeFalse())
+	})
+
+	It("restores the selector, the type and the generated labels", func() {
+		expectedService := buildPostgresServices(cluster)[3]
+		service := expectedService.DeepCopy()
+		service.Spec.ClusterIP = "10.0.0.1"
+		utils.LabelClusterName(&service.ObjectMeta, cluster.Name)
+		service.Spec.Selector = map[string]string{"app": "other"}
+		service.Spec.Type = corev1.ServiceTypeNodePort
+		delete(service.Labels, "team")
+
+		repairedService, drifted := repairServiceDrift(cluster, service, expectedService)
+		Expect(drifted).To(BeTrue())
+		Expect(repairedService.Spec.Selector).To(HaveKeyWithValue(
+			specs.ClusterRoleLabelName, specs.ClusterRoleLabelPrimary))
+		Expect(repairedService.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+		Expect(repairedService.Spec.ClusterIP).To(Equal("10.0.0.1"))
+		Expect(repairedService.Labels).To(HaveKeyWithValue("team", "dba"))
+	})
+})
+
+var _ = Describe("Drift of the generated secrets", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+	}
+
+	It("doesn't detect any drift in a secret having a different password", func() {
+		expectedSecret := specs.CreateSecret("cluster-example-app", "default",
+			"cluster-example-rw", "app", "app", "changed")
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example-app",
+				Namespace: "default",
+				Labels: map[string]string{
+					specs.WatchedLabelName: "true",
+					utils.ClusterLabelName: "cluster-example",
+				},
+			},
+			Type: corev1.SecretTypeBasicAuth,
+			Data: make(map[string][]byte, len(expectedSecret.StringData)),
+		}
+		for key, value := range expectedSecret.StringData {
+			secret.Data[key] = []byte(value)
+		}
+
+		_, drifted := repairSecretDrift(cluster, secret, expectedSecret)
+		Expect(drifted).To(BeFalse())
+	})
+
+	It("restores the connection information, preserving the additional keys", func() {
+		expectedSecret := specs.CreateSecret("cluster-example-app", "default",
+			"cluster-example-rw", "app", "app", "secret")
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example-app",
+				Namespace: "default",
+			},
+			Type: corev1.SecretTypeBasicAuth,
+			Data: map[string][]byte{
+				"username": []byte("app"),
+				"password": []byte("secret"),
+				"host":     []byte("somewhere-else"),
+				"extra":    []byte("value"),
+			},
+		}
+
+		repairedSecret, drifted := repairSecretDrift(cluster, secret, expectedSecret)
+		Expect(drifted).To(BeTrue())
+		Expect(repairedSecret.Data).To(HaveKeyWithValue("host", []byte("cluster-example-rw")))
+		Expect(repairedSecret.Data).To(HaveKeyWithValue("password", []byte("secret")))
+		Expect(repairedSecret.Data).To(HaveKeyWithValue("extra", []byte("value")))
+		Expect(repairedSecret.Data).To(HaveKey("uri"))
+		Expect(repairedSecret.Labels).To(HaveKeyWithValue(specs.WatchedLabelName, "true"))
+		Expect(repairedSecret.Labels).To(HaveKeyWithValue(utils.ClusterLabelName, "cluster-example"))
+	})
+})
+
+var _ = Describe("Drift of the instance pods", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			Affinity: apiv1.AffinityConfiguration{
+				Tolerations: []corev1.Toleration{
+					{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "postgres"},
+				},
+			},
+		},
+	}
+
+	It("doesn't detect any drift in the pod created by the operator", func() {
+		pod := specs.PodWithExistingStorage(*cluster, 1)
+		pod.Spec.Tolerations = append(pod.Spec.Tolerations, corev1.Toleration{
+			Key:      corev1.TaintNodeNotReady,
+			Operator: corev1.TolerationOpExists,
+			Effect:   corev1.TaintEffectNoExecute,
+		})
+
+		Expect(getPodSpecDriftReason(cluster, *pod)).To(BeEmpty())
+		_, drifted := repairPodMetadataDrift(cluster, pod)
+		Expect(drifted).To(BeFalse())
+	})
+
+	It("detects the tolerations added out of band", func() {
+		pod := specs.PodWithExistingStorage(*cluster, 1)
+		pod.Spec.Tolerations = append(pod.Spec.Tolerations, corev1.Toleration{
+			Key:      "other",
+			Operator: corev1.TolerationOpExists,
+		})
+
+		Expect(getPodSpecDriftReason(cluster, *pod)).To(ContainSubstring("other"))
+	})
+
+	It("detects the active deadline set out of band", func() {
+		pod := specs.PodWithExistingStorage(*cluster, 1)
+		deadline := int64(60)
+		pod.Spec.ActiveDeadlineSeconds = &deadline
+
+		Expect(getPodSpecDriftReason(cluster, *pod)).ToNot(BeEmpty())
+	})
+
+	It("rolls out the pods changed out of band only when the drift is repaired", func() {
+		pod := specs.PodWithExistingStorage(*cluster, 1)
+		deadline := int64(60)
+		pod.Spec.ActiveDeadlineSeconds = &deadline
+
+		reason, err := GetPodSpecChangeReason(cluster, *pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reason).ToNot(BeEmpty())
+
+		autoRepair := false
+		reportingCluster := cluster.DeepCopy()
+		reportingCluster.Spec.DriftDetection = &apiv1.DriftDetectionConfiguration{AutoRepair: &autoRepair}
+		reason, err = GetPodSpecChangeReason(reportingCluster, *pod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(reason).To(BeEmpty())
+	})
+
+	It("restores the labels removed out of band", func() {
+		pod := specs.PodWithExistingStorage(*cluster, 1)
+		delete(pod.Labels, utils.InstanceNameLabelName)
+		pod.Labels["team"] = "dba"
+
+		repairedPod, drifted := repairPodMetadataDrift(cluster, pod)
+		Expect(drifted).To(BeTrue())
+		Expect(repairedPod.Labels).To(HaveKeyWithValue(utils.InstanceNameLabelName, "cluster-example-1"))
+		Expect(repairedPod.Labels).To(HaveKeyWithValue("team", "dba"))
+	})
+})
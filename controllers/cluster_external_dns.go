@@ -0,0 +1,138 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// reconcilePrimaryExternalService creates or updates the LoadBalancer service
+// pointing to the primary, whose hostname is published by external-dns, and
+// deletes it when the cluster doesn't use external-dns anymore.
+// As the service selects the primary by its role label, a switchover only
+// changes the endpoints of the service, while the load balancer and the DNS
+// record stay the same
+func (r *ClusterReconciler) reconcilePrimaryExternalService(ctx context.Context, cluster *apiv1.Cluster) error {
+	expectedService := buildPrimaryExternalService(cluster)
+
+	var service corev1.Service
+	err := r.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetServiceReadWriteExternalName()},
+		&service)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while getting the external service: %w", err)
+	}
+	found := err == nil
+
+	if found {
+		if owner, isOwned := IsOwnedByCluster(&service); !isOwned || owner != cluster.Name {
+			return nil
+		}
+	}
+
+	switch {
+	case expectedService == nil && !found:
+		return nil
+
+	case expectedService == nil:
+		r.Recorder.Eventf(cluster, "Normal", "DeletingExternalService",
+			"Deleting the external service %s", service.Name)
+		if err := r.Delete(ctx, &service); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while deleting the external service: %w", err)
+		}
+		return nil
+
+	case !found:
+		SetClusterOwnerAnnotationsAndLabels(&expectedService.ObjectMeta, cluster)
+		r.Recorder.Eventf(cluster, "Normal", "CreatingExternalService",
+			"Creating the external service %s for %s", expectedService.Name, cluster.Spec.ExternalDNS.Hostname)
+		if err := r.Create(ctx, expectedService); err != nil && !apierrs.IsAlreadyExists(err) {
+			return fmt.Errorf("while creating the external service: %w", err)
+		}
+		return nil
+	}
+
+	updatedService, changed := updatePrimaryExternalService(&service, expectedService)
+	if !changed {
+		return nil
+	}
+
+	r.Recorder.Eventf(cluster, "Normal", "UpdatingExternalService",
+		"Updating the external service %s for %s", service.Name, cluster.Spec.ExternalDNS.Hostname)
+	if err := r.Patch(ctx, updatedService, client.MergeFrom(&service)); err != nil {
+		return fmt.Errorf("while updating the external service: %w", err)
+	}
+
+	return nil
+}
+
+// buildPrimaryExternalService builds the LoadBalancer service pointing to
+// the primary, with the metadata requested in the generated objects section,
+// or returns nil when the cluster doesn't use external-dns
+func buildPrimaryExternalService(cluster *apiv1.Cluster) *corev1.Service {
+	service := specs.CreateClusterReadWriteExternalService(*cluster)
+	if service == nil {
+		return nil
+	}
+
+	setGeneratedObjectMetadata(&service.ObjectMeta, cluster.GetGeneratedServicesMetadata())
+	return service
+}
+
+// updatePrimaryExternalService returns a copy of the passed service having
+// the expected definition, and whether it differs from the passed one.
+// The node ports allocated by Kubernetes and the additional labels and
+// annotations are preserved
+func updatePrimaryExternalService(
+	service *corev1.Service,
+	expectedService *corev1.Service,
+) (*corev1.Service, bool) {
+	updatedService := service.DeepCopy()
+	updatedService.Spec.Type = expectedService.Spec.Type
+	updatedService.Spec.Selector = expectedService.Spec.Selector
+	updatedService.Spec.LoadBalancerSourceRanges = expectedService.Spec.LoadBalancerSourceRanges
+
+	nodePorts := make(map[string]int32, len(service.Spec.Ports))
+	for _, port := range service.Spec.Ports {
+		nodePorts[port.Name] = port.NodePort
+	}
+	updatedService.Spec.Ports = make([]corev1.ServicePort, len(expectedService.Spec.Ports))
+	for idx, port := range expectedService.Spec.Ports {
+		port.NodePort = nodePorts[port.Name]
+		updatedService.Spec.Ports[idx] = port
+	}
+
+	if _, hasTTL := expectedService.Annotations[specs.ExternalDNSTTLAnnotationName]; !hasTTL {
+		delete(updatedService.Annotations, specs.ExternalDNSTTLAnnotationName)
+	}
+	setGeneratedObjectMetadata(&updatedService.ObjectMeta, &apiv1.EmbeddedObjectMetadata{
+		Labels:      expectedService.Labels,
+		Annotations: expectedService.Annotations,
+	})
+
+	return updatedService, !reflect.DeepEqual(service, updatedService)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("External service of the primary", func() {
+	ttl := int32(60)
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			ExternalDNS: &apiv1.ExternalDNSConfiguration{
+				Hostname: "db.example.com",
+				TTL:      &ttl,
+			},
+			GeneratedObjects: &apiv1.GeneratedObjectsConfiguration{
+				Services: &apiv1.EmbeddedObjectMetadata{
+					Labels: map[string]string{"team": "dba"},
+				},
+			},
+		},
+	}
+
+	// getService returns a copy of the expected service as it would
+	// be read from the API server, with the allocated node port
+	getService := func() *corev1.Service {
+		service := buildPrimaryExternalService(cluster).DeepCopy()
+		service.Spec.ClusterIP = "10.0.0.1"
+		service.Spec.Ports[0].NodePort = 31234
+		service.Annotations["cloud.example.com/managed"] = "true"
+		return service
+	}
+
+	It("is not built without external-dns", func() {
+		Expect(buildPrimaryExternalService(&apiv1.Cluster{})).To(BeNil())
+	})
+
+	It("is built with the generated metadata", func() {
+		service := buildPrimaryExternalService(cluster)
+		Expect(service.Labels).To(HaveKeyWithValue("team", "dba"))
+		Expect(service.Annotations).To(HaveKeyWithValue(specs.ExternalDNSHostnameAnnotationName, "db.example.com"))
+	})
+
+	It("doesn't change a service matching the expected definition", func() {
+		service := getService()
+		_, changed := updatePrimaryExternalService(service, buildPrimaryExternalService(cluster))
+		Expect(changed).To(BeFalse())
+	})
+
+	It("updates the hostname preserving the node ports and the other annotations", func() {
+		service := getService()
+		updatedCluster := cluster.DeepCopy()
+		updatedCluster.Spec.ExternalDNS.Hostname = "primary.example.com"
+		updatedCluster.Spec.ExternalDNS.TTL = nil
+
+		updatedService, changed := updatePrimaryExternalService(service, buildPrimaryExternalService(updatedCluster))
+		Expect(changed).To(BeTrue())
+		Expect(updatedService.Annotations).To(HaveKeyWithValue(
+			specs.ExternalDNSHostnameAnnotationName, "primary.example.com"))
+		Expect(updatedService.Annotations).ToNot(HaveKey(specs.ExternalDNSTTLAnnotationName))
+		Expect(updatedService.Annotations).To(HaveKeyWithValue("cloud.example.com/managed", "true"))
+		Expect(updatedService.Spec.Ports[0].NodePort).To(BeEquivalentTo(31234))
+		Expect(updatedService.Spec.ClusterIP).To(Equal("10.0.0.1"))
+	})
+})
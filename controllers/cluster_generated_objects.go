@@ -0,0 +1,190 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// setGeneratedObjectMetadata adds the labels and annotations requested
+// in the generatedObjects section of the cluster to the passed object
+func setGeneratedObjectMetadata(obj metav1.Object, metadata *apiv1.EmbeddedObjectMetadata) {
+	if metadata == nil {
+		return
+	}
+
+	if len(metadata.Labels) > 0 && obj.GetLabels() == nil {
+		obj.SetLabels(make(map[string]string))
+	}
+	for key, value := range metadata.Labels {
+		obj.GetLabels()[key] = value
+	}
+
+	if len(metadata.Annotations) > 0 && obj.GetAnnotations() == nil {
+		obj.SetAnnotations(make(map[string]string))
+	}
+	for key, value := range metadata.Annotations {
+		obj.GetAnnotations()[key] = value
+	}
+}
+
+// isGeneratedObjectMetadataApplied checks if the passed object already has
+// the labels and annotations requested in the generatedObjects section
+func isGeneratedObjectMetadataApplied(obj metav1.Object, metadata *apiv1.EmbeddedObjectMetadata) bool {
+	if metadata == nil {
+		return true
+	}
+
+	for key, value := range metadata.Labels {
+		if current, ok := obj.GetLabels()[key]; !ok || current != value {
+			return false
+		}
+	}
+	for key, value := range metadata.Annotations {
+		if current, ok := obj.GetAnnotations()[key]; !ok || current != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// patchGeneratedObjectMetadata adds the labels and annotations requested
+// in the generatedObjects section to an existing object. As it happens
+// with the inherited metadata, the labels and annotations removed from
+// the cluster are not removed from the object
+func (r *ClusterReconciler) patchGeneratedObjectMetadata(
+	ctx context.Context,
+	obj client.Object,
+	metadata *apiv1.EmbeddedObjectMetadata,
+) error {
+	if isGeneratedObjectMetadataApplied(obj, metadata) {
+		return nil
+	}
+
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	setGeneratedObjectMetadata(obj, metadata)
+
+	log.FromContext(ctx).Info("Updating the metadata of a generated object", "name", obj.GetName())
+	return r.Patch(ctx, obj, patch)
+}
+
+// updateGeneratedMetadataOnSecretsAndServices applies the metadata requested
+// in the generatedObjects section to the existing secrets and services
+// generated by the operator. The objects not owned by the cluster, like
+// the secrets provided by the user, are never changed
+func (r *ClusterReconciler) updateGeneratedMetadataOnSecretsAndServices(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) error {
+	secretNames := []string{
+		cluster.GetSuperuserSecretName(),
+		cluster.GetApplicationSecretName(),
+		cluster.GetServerCASecretName(),
+		cluster.GetServerTLSSecretName(),
+		cluster.GetClientCASecretName(),
+		cluster.GetReplicationSecretName(),
+	}
+	for _, name := range secretNames {
+		var secret corev1.Secret
+		if err := r.updateGeneratedMetadataOnObject(
+			ctx, cluster, name, &secret, cluster.GetGeneratedSecretsMetadata()); err != nil {
+			return err
+		}
+	}
+
+	for _, service := range buildPostgresServices(cluster) {
+		var current corev1.Service
+		if err := r.updateGeneratedMetadataOnObject(
+			ctx, cluster, service.Name, &current, cluster.GetGeneratedServicesMetadata()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateGeneratedMetadataOnObject applies the passed metadata to the object
+// with the passed name, if it exists and is owned by the cluster
+func (r *ClusterReconciler) updateGeneratedMetadataOnObject(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	name string,
+	obj client.Object,
+	metadata *apiv1.EmbeddedObjectMetadata,
+) error {
+	if metadata == nil {
+		return nil
+	}
+
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, obj)
+	if apierrs.IsNotFound(err) || apierrs.IsForbidden(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("while getting %s: %w", name, err)
+	}
+
+	if owner, isOwned := IsOwnedByCluster(obj); !isOwned || owner != cluster.Name {
+		return nil
+	}
+
+	return r.patchGeneratedObjectMetadata(ctx, obj, metadata)
+}
+
+// updateGeneratedMetadataOnPods applies the metadata requested in the
+// generatedObjects section to the existing Pods of the cluster
+func (r *ClusterReconciler) updateGeneratedMetadataOnPods(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pods corev1.PodList,
+) error {
+	for i := range pods.Items {
+		if err := r.patchGeneratedObjectMetadata(
+			ctx, &pods.Items[i], cluster.GetGeneratedPodsMetadata()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateGeneratedMetadataOnPVCs applies the metadata requested in the
+// generatedObjects section to the existing PVCs of the cluster
+func (r *ClusterReconciler) updateGeneratedMetadataOnPVCs(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pvcs corev1.PersistentVolumeClaimList,
+) error {
+	for i := range pvcs.Items {
+		if err := r.patchGeneratedObjectMetadata(
+			ctx, &pvcs.Items[i], cluster.GetGeneratedPVCsMetadata()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
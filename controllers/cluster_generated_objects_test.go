@@ -0,0 +1,62 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("generated objects metadata", func() {
+	It("adds the requested labels and annotations", func() {
+		meta := metav1.ObjectMeta{Labels: map[string]string{"existing": "true"}}
+		setGeneratedObjectMetadata(&meta, &apiv1.EmbeddedObjectMetadata{
+			Labels:      map[string]string{"team": "payments"},
+			Annotations: map[string]string{"owner": "dba"},
+		})
+		Expect(meta.Labels).To(Equal(map[string]string{"existing": "true", "team": "payments"}))
+		Expect(meta.Annotations).To(Equal(map[string]string{"owner": "dba"}))
+	})
+
+	It("does nothing without metadata", func() {
+		meta := metav1.ObjectMeta{}
+		setGeneratedObjectMetadata(&meta, nil)
+		Expect(meta.Labels).To(BeNil())
+		Expect(meta.Annotations).To(BeNil())
+	})
+
+	It("checks if the requested labels and annotations are applied", func() {
+		metadata := &apiv1.EmbeddedObjectMetadata{
+			Labels:      map[string]string{"team": "payments"},
+			Annotations: map[string]string{"owner": "dba"},
+		}
+		Expect(isGeneratedObjectMetadataApplied(&metav1.ObjectMeta{}, nil)).To(BeTrue())
+		Expect(isGeneratedObjectMetadataApplied(&metav1.ObjectMeta{}, metadata)).To(BeFalse())
+		Expect(isGeneratedObjectMetadataApplied(&metav1.ObjectMeta{
+			Labels:      map[string]string{"team": "billing"},
+			Annotations: map[string]string{"owner": "dba"},
+		}, metadata)).To(BeFalse())
+		Expect(isGeneratedObjectMetadataApplied(&metav1.ObjectMeta{
+			Labels:      map[string]string{"team": "payments", "other": "true"},
+			Annotations: map[string]string{"owner": "dba"},
+		}, metadata)).To(BeTrue())
+	})
+})
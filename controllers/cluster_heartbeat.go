@@ -0,0 +1,192 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// reconcilePrimaryHeartbeatLease creates the lease renewed by the instance
+// manager of the primary when the heartbeat is enabled, and removes it when
+// the heartbeat is disabled. The instance manager is only allowed to read
+// and update it.
+func (r *ClusterReconciler) reconcilePrimaryHeartbeatLease(ctx context.Context, cluster *apiv1.Cluster) error {
+	var lease coordinationv1.Lease
+	err := r.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetPrimaryHeartbeatLeaseName()},
+		&lease)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while getting the primary heartbeat lease: %w", err)
+	}
+	exists := err == nil
+
+	switch {
+	case cluster.Spec.PrimaryHeartbeatTimeout == 0 && exists:
+		if err := r.Delete(ctx, &lease); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while deleting the primary heartbeat lease: %w", err)
+		}
+
+	case cluster.Spec.PrimaryHeartbeatTimeout > 0 && !exists:
+		lease = coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: cluster.Namespace,
+				Name:      cluster.GetPrimaryHeartbeatLeaseName(),
+			},
+		}
+		SetClusterOwnerAnnotationsAndLabels(&lease.ObjectMeta, cluster)
+		if err := r.Create(ctx, &lease); err != nil && !apierrs.IsAlreadyExists(err) {
+			return fmt.Errorf("while creating the primary heartbeat lease: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// checkPrimaryHeartbeat marks the current primary as failed in the passed
+// instances status when the heartbeat is enabled and its lease has expired,
+// so that a failover is triggered even if the Pod of the primary is ready
+func (r *ClusterReconciler) checkPrimaryHeartbeat(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status *postgres.PostgresqlStatusList,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	if cluster.Spec.PrimaryHeartbeatTimeout == 0 || cluster.IsReplica() ||
+		cluster.IsInstanceFenced(cluster.Status.CurrentPrimary) {
+		return nil
+	}
+
+	// During a switchover the former primary is expected to stop renewing
+	// the lease, while during a failover we keep excluding it from the
+	// election of the new primary
+	if cluster.Status.TargetPrimary != cluster.Status.CurrentPrimary &&
+		cluster.Status.TargetPrimary != apiv1.PendingFailoverMarker {
+		return nil
+	}
+
+	var lease coordinationv1.Lease
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetPrimaryHeartbeatLeaseName()},
+		&lease,
+	); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if !r.primaryHeartbeats.isExpired(client.ObjectKeyFromObject(cluster), &lease,
+		cluster.Status.CurrentPrimary, cluster.GetPrimaryHeartbeatTimeout(), time.Now()) {
+		return nil
+	}
+
+	for idx := range status.Items {
+		item := &status.Items[idx]
+		if item.Pod.Name != cluster.Status.CurrentPrimary || item.Error != nil {
+			continue
+		}
+
+		if cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary {
+			contextLogger.Info("The primary has not renewed its heartbeat lease, considering it failed",
+				"primary", cluster.Status.CurrentPrimary,
+				"renewTime", lease.Spec.RenewTime,
+				"primaryHeartbeatTimeout", cluster.Spec.PrimaryHeartbeatTimeout)
+			r.Recorder.Eventf(cluster, "Warning", "PrimaryHeartbeatExpired",
+				"The primary %v has not renewed its heartbeat lease since %v",
+				cluster.Status.CurrentPrimary, lease.Spec.RenewTime.Time)
+		}
+		item.Error = fmt.Errorf("the heartbeat lease of the primary has expired")
+		item.IsReady = false
+		sort.Sort(status)
+		break
+	}
+
+	return nil
+}
+
+// primaryHeartbeatObserver keeps track of the heartbeat leases of the
+// primaries as seen by the operator. As the renew time of a lease comes from
+// the clock of the node running the primary, the lease is considered expired
+// when the operator hasn't seen it renewed for longer than the timeout,
+// measured with its own clock, as the leader election clients do. This way
+// a clock skew between the nodes can't cause a spurious failover
+type primaryHeartbeatObserver struct {
+	mutex        sync.Mutex
+	observations map[types.NamespacedName]primaryHeartbeatObservation
+}
+
+// primaryHeartbeatObservation is the last renewal of a heartbeat lease
+// seen by the operator, together with the time when it has been seen
+type primaryHeartbeatObservation struct {
+	holder       string
+	renewTime    time.Time
+	observedTime time.Time
+}
+
+// isExpired checks if, at the passed time, the heartbeat lease of the
+// passed cluster, held by the passed primary, has not been seen renewed for
+// longer than the timeout. A lease never renewed, or held by another
+// instance, is not considered expired, since the primary may have not
+// acquired it yet
+func (o *primaryHeartbeatObserver) isExpired(
+	key types.NamespacedName,
+	lease *coordinationv1.Lease,
+	primary string,
+	timeout time.Duration,
+	now time.Time,
+) bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != primary ||
+		lease.Spec.RenewTime == nil {
+		delete(o.observations, key)
+		return false
+	}
+
+	observation, ok := o.observations[key]
+	if !ok || observation.holder != primary || !observation.renewTime.Equal(lease.Spec.RenewTime.Time) {
+		if o.observations == nil {
+			o.observations = make(map[types.NamespacedName]primaryHeartbeatObservation)
+		}
+		o.observations[key] = primaryHeartbeatObservation{
+			holder:       primary,
+			renewTime:    lease.Spec.RenewTime.Time,
+			observedTime: now,
+		}
+		return false
+	}
+
+	return now.Sub(observation.observedTime) > timeout
+}
@@ -0,0 +1,86 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Primary heartbeat", func() {
+	key := types.NamespacedName{Namespace: "default", Name: "cluster-example"}
+	renewTime := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	lease := &coordinationv1.Lease{
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity: pointer.String("cluster-example-1"),
+			RenewTime:      &metav1.MicroTime{Time: renewTime},
+		},
+	}
+
+	It("considers expired a lease not seen renewed by the primary for longer than the timeout", func() {
+		var observer primaryHeartbeatObserver
+		now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		Expect(observer.isExpired(key, lease, "cluster-example-1", 30*time.Second, now)).To(BeFalse())
+		Expect(observer.isExpired(key, lease, "cluster-example-1", 30*time.Second,
+			now.Add(30*time.Second))).To(BeFalse())
+		Expect(observer.isExpired(key, lease, "cluster-example-1", 30*time.Second,
+			now.Add(31*time.Second))).To(BeTrue())
+	})
+
+	It("measures the expiration from the last renewal seen", func() {
+		var observer primaryHeartbeatObserver
+		now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		Expect(observer.isExpired(key, lease, "cluster-example-1", 30*time.Second, now)).To(BeFalse())
+
+		renewedLease := lease.DeepCopy()
+		renewedLease.Spec.RenewTime = &metav1.MicroTime{Time: renewTime.Add(5 * time.Second)}
+		Expect(observer.isExpired(key, renewedLease, "cluster-example-1", 30*time.Second,
+			now.Add(20*time.Second))).To(BeFalse())
+		Expect(observer.isExpired(key, renewedLease, "cluster-example-1", 30*time.Second,
+			now.Add(50*time.Second))).To(BeFalse())
+		Expect(observer.isExpired(key, renewedLease, "cluster-example-1", 30*time.Second,
+			now.Add(51*time.Second))).To(BeTrue())
+	})
+
+	It("ignores a lease held by another instance", func() {
+		var observer primaryHeartbeatObserver
+		now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		Expect(observer.isExpired(key, lease, "cluster-example-2", 30*time.Second, now)).To(BeFalse())
+		Expect(observer.isExpired(key, lease, "cluster-example-2", 30*time.Second,
+			now.Add(time.Hour))).To(BeFalse())
+	})
+
+	It("ignores a lease which has never been renewed", func() {
+		var observer primaryHeartbeatObserver
+		now := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+		Expect(observer.isExpired(key, &coordinationv1.Lease{}, "cluster-example-1", 30*time.Second,
+			now)).To(BeFalse())
+		Expect(observer.isExpired(key, &coordinationv1.Lease{}, "cluster-example-1", 30*time.Second,
+			now.Add(time.Hour))).To(BeFalse())
+	})
+})
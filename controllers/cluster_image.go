@@ -0,0 +1,162 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// imageCatalogKey is the key of the index of the clusters by
+// the image catalog they reference
+const imageCatalogKey = ".spec.imageCatalogRef"
+
+// reconcileImage resolves the image of a cluster using an image catalog
+// and stores it in the status. A new image in the catalog for the requested
+// major version will then trigger the rollout of the instances
+func (r *ClusterReconciler) reconcileImage(ctx context.Context, cluster *apiv1.Cluster) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	catalogRef := cluster.Spec.ImageCatalogRef
+	if catalogRef == nil {
+		return nil, nil
+	}
+
+	var catalog apiv1.GenericImageCatalog
+	key := types.NamespacedName{Name: catalogRef.Name}
+	switch catalogRef.Kind {
+	case apiv1.ClusterImageCatalogKind:
+		catalog = &apiv1.ClusterImageCatalog{}
+	case apiv1.ImageCatalogKind:
+		catalog = &apiv1.ImageCatalog{}
+		key.Namespace = cluster.Namespace
+	default:
+		return &ctrl.Result{}, r.RegisterPhase(ctx, cluster, apiv1.PhaseImageCatalogError,
+			fmt.Sprintf("Invalid image catalog kind: %s", catalogRef.Kind))
+	}
+
+	if err := r.Get(ctx, key, catalog); err != nil {
+		if apierrs.IsNotFound(err) {
+			return &ctrl.Result{}, r.RegisterPhase(ctx, cluster, apiv1.PhaseImageCatalogError,
+				fmt.Sprintf("%s %s not found", catalogRef.Kind, catalogRef.Name))
+		}
+		return &ctrl.Result{}, err
+	}
+
+	image, err := getCatalogImage(catalog.GetSpec(), catalogRef.Major)
+	if err != nil {
+		return &ctrl.Result{}, r.RegisterPhase(ctx, cluster, apiv1.PhaseImageCatalogError,
+			fmt.Sprintf("%s %s: %s", catalogRef.Kind, catalogRef.Name, err.Error()))
+	}
+
+	if image == cluster.Status.Image {
+		return nil, nil
+	}
+
+	contextLogger.Info("Updating the image from the image catalog",
+		"catalogKind", catalogRef.Kind,
+		"catalogName", catalogRef.Name,
+		"major", catalogRef.Major,
+		"previousImage", cluster.Status.Image,
+		"image", image)
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.Image = image
+	if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return &ctrl.Result{}, err
+	}
+
+	return nil, nil
+}
+
+// getCatalogImage gets the image corresponding to a major version from
+// an image catalog, checking that its tag matches that major version
+func getCatalogImage(catalog *apiv1.ImageCatalogSpec, major int) (string, error) {
+	image, found := catalog.FindImageForMajor(major)
+	if !found {
+		return "", fmt.Errorf("unknown major version %d", major)
+	}
+
+	version, err := postgres.GetPostgresVersionFromTag(utils.GetImageTag(image))
+	if err != nil {
+		return "", fmt.Errorf("invalid version tag in image %s: %w", image, err)
+	}
+	if version/10000 != major {
+		return "", fmt.Errorf("the image %s doesn't correspond to the major version %d", image, major)
+	}
+
+	return image, nil
+}
+
+// getImageCatalogIndexValue gets the value used to index a cluster
+// by the image catalog it references
+func getImageCatalogIndexValue(kind, name string) string {
+	return fmt.Sprintf("%s/%s", kind, name)
+}
+
+// mapImageCatalogsToClusters returns a function mapping image catalog
+// events to the reconcile requests of the clusters using them
+func (r *ClusterReconciler) mapImageCatalogsToClusters(ctx context.Context) handler.MapFunc {
+	return func(obj client.Object) []reconcile.Request {
+		var listOptions []client.ListOption
+		switch catalog := obj.(type) {
+		case *apiv1.ImageCatalog:
+			listOptions = append(listOptions,
+				client.InNamespace(catalog.Namespace),
+				client.MatchingFields{
+					imageCatalogKey: getImageCatalogIndexValue(apiv1.ImageCatalogKind, catalog.Name),
+				})
+		case *apiv1.ClusterImageCatalog:
+			listOptions = append(listOptions,
+				client.MatchingFields{
+					imageCatalogKey: getImageCatalogIndexValue(apiv1.ClusterImageCatalogKind, catalog.Name),
+				})
+		default:
+			return nil
+		}
+
+		var clusters apiv1.ClusterList
+		if err := r.List(ctx, &clusters, listOptions...); err != nil {
+			log.FromContext(ctx).Error(err, "while getting the clusters using an image catalog",
+				"catalog", obj.GetName())
+			return nil
+		}
+
+		requests := make([]reconcile.Request, 0, len(clusters.Items))
+		for idx := range clusters.Items {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      clusters.Items[idx].Name,
+					Namespace: clusters.Items[idx].Namespace,
+				},
+			})
+		}
+		return requests
+	}
+}
@@ -0,0 +1,152 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/registry"
+)
+
+const (
+	// imageArchitecturesTimeout is the maximum time spent detecting
+	// the architectures of an image
+	imageArchitecturesTimeout = 10 * time.Second
+
+	// imageArchitecturesRetryInterval is the time to wait before detecting
+	// again the architectures of an image after a failure
+	imageArchitecturesRetryInterval = 5 * time.Minute
+)
+
+// reconcileImageArchitectures detects the architectures provided by the
+// PostgreSQL image when the architecture affinity is enabled, and stores
+// them in the status. The detection happens once for every image, and the
+// result is used to restrict the nodes where the pods can be scheduled
+func (r *ClusterReconciler) reconcileImageArchitectures(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	if !cluster.Spec.Affinity.EnableArchitectureAffinity {
+		if cluster.Status.ImageArchitectures == nil {
+			return nil
+		}
+		origCluster := cluster.DeepCopy()
+		cluster.Status.ImageArchitectures = nil
+		return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+	}
+
+	image := cluster.GetImageName()
+	if !needsImageArchitecturesDetection(cluster.Status.ImageArchitectures, image, time.Now()) {
+		return nil
+	}
+
+	credentials, err := r.getImagePullCredentials(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	detectionCtx, cancel := context.WithTimeout(ctx, imageArchitecturesTimeout)
+	defer cancel()
+	registryClient := registry.NewClient(&http.Client{Timeout: imageArchitecturesTimeout}, credentials)
+	architectures, err := registryClient.GetArchitectures(detectionCtx, image)
+
+	status := &apiv1.ImageArchitecturesStatus{
+		Image:         image,
+		Architectures: architectures,
+		LastCheckTime: metav1.Now(),
+	}
+	if err != nil {
+		contextLogger.Warning("Cannot detect the architectures of the image",
+			"image", image, "error", err.Error())
+		r.Recorder.Eventf(cluster, "Warning", "ImageArchitectures",
+			"Cannot detect the architectures of the image %s: %s", image, err.Error())
+		status.Error = err.Error()
+	} else {
+		contextLogger.Info("Detected the architectures of the image",
+			"image", image, "architectures", architectures)
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.ImageArchitectures = status
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// needsImageArchitecturesDetection checks if the architectures of an
+// image need to be detected, given the current status
+func needsImageArchitecturesDetection(
+	status *apiv1.ImageArchitecturesStatus,
+	image string,
+	now time.Time,
+) bool {
+	if status == nil || status.Image != image {
+		return true
+	}
+
+	return status.Error != "" && now.Sub(status.LastCheckTime.Time) >= imageArchitecturesRetryInterval
+}
+
+// getImagePullCredentials gets the registry credentials from the pull
+// secrets of the cluster and from the one of the operator, if any
+func (r *ClusterReconciler) getImagePullCredentials(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (registry.Credentials, error) {
+	secretKeys := make([]client.ObjectKey, 0, len(cluster.Spec.ImagePullSecrets)+1)
+	if configuration.Current().OperatorNamespace != "" && configuration.Current().OperatorPullSecretName != "" {
+		secretKeys = append(secretKeys, client.ObjectKey{
+			Namespace: configuration.Current().OperatorNamespace,
+			Name:      configuration.Current().OperatorPullSecretName,
+		})
+	}
+	for _, secretReference := range cluster.Spec.ImagePullSecrets {
+		secretKeys = append(secretKeys, client.ObjectKey{
+			Namespace: cluster.Namespace,
+			Name:      secretReference.Name,
+		})
+	}
+
+	credentials := make(registry.Credentials)
+	for _, key := range secretKeys {
+		var secret corev1.Secret
+		if err := r.Get(ctx, key, &secret); err != nil {
+			if apierrs.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		data, ok := secret.Data[corev1.DockerConfigJsonKey]
+		if !ok {
+			continue
+		}
+		if err := credentials.ParseDockerConfigJSON(data); err != nil {
+			return nil, fmt.Errorf("while reading the pull secret %s: %w", key.Name, err)
+		}
+	}
+
+	return credentials, nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("image architectures detection", func() {
+	now := time.Now()
+	image := "ghcr.io/cloudnative-pg/postgresql:15.4"
+
+	It("detects the architectures of new images", func() {
+		Expect(needsImageArchitecturesDetection(nil, image, now)).To(BeTrue())
+		Expect(needsImageArchitecturesDetection(&apiv1.ImageArchitecturesStatus{
+			Image:         "ghcr.io/cloudnative-pg/postgresql:15.3",
+			Architectures: []string{"amd64"},
+			LastCheckTime: metav1.NewTime(now),
+		}, image, now)).To(BeTrue())
+	})
+
+	It("doesn't detect again the architectures of the same image", func() {
+		Expect(needsImageArchitecturesDetection(&apiv1.ImageArchitecturesStatus{
+			Image:         image,
+			Architectures: []string{"amd64"},
+			LastCheckTime: metav1.NewTime(now.Add(-time.Hour)),
+		}, image, now)).To(BeFalse())
+	})
+
+	It("retries failed detections after a while", func() {
+		status := &apiv1.ImageArchitecturesStatus{
+			Image:         image,
+			Error:         "unauthorized",
+			LastCheckTime: metav1.NewTime(now.Add(-time.Minute)),
+		}
+		Expect(needsImageArchitecturesDetection(status, image, now)).To(BeFalse())
+
+		status.LastCheckTime = metav1.NewTime(now.Add(-imageArchitecturesRetryInterval))
+		Expect(needsImageArchitecturesDetection(status, image, now)).To(BeTrue())
+	})
+})
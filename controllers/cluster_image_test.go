@@ -0,0 +1,61 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("image catalog resolution", func() {
+	catalog := &apiv1.ImageCatalogSpec{
+		Images: []apiv1.CatalogImage{
+			{Major: 14, Image: "ghcr.io/cloudnative-pg/postgresql:14.9"},
+			{Major: 15, Image: "ghcr.io/cloudnative-pg/postgresql:15.4"},
+			{Major: 16, Image: "ghcr.io/cloudnative-pg/postgresql:15.4-wrong"},
+			{Major: 13, Image: "ghcr.io/cloudnative-pg/postgresql:latest"},
+		},
+	}
+
+	It("finds the image of the requested major version", func() {
+		image, err := getCatalogImage(catalog, 15)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(image).To(Equal("ghcr.io/cloudnative-pg/postgresql:15.4"))
+	})
+
+	It("fails when the major version is not in the catalog", func() {
+		_, err := getCatalogImage(catalog, 12)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("fails when the image doesn't correspond to the major version", func() {
+		_, err := getCatalogImage(catalog, 16)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("fails when the image has an invalid tag", func() {
+		_, err := getCatalogImage(catalog, 13)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("indexes the clusters by the kind and the name of the catalog", func() {
+		Expect(getImageCatalogIndexValue(apiv1.ClusterImageCatalogKind, "postgresql")).
+			To(Equal("ClusterImageCatalog/postgresql"))
+	})
+})
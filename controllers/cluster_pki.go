@@ -26,6 +26,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/notifications"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -46,6 +47,11 @@ func (r *ClusterReconciler) setupPostgresPKI(ctx context.Context, cluster *apiv1
 	// This is the certificate for the server
 	serverCertificateName := client.ObjectKey{Namespace: cluster.GetNamespace(), Name: cluster.GetServerTLSSecretName()}
 	opts := x509.VerifyOptions{KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}
+	if cluster.Spec.ReplicationConnection.GetSSLMode() == apiv1.ReplicationSSLModeVerifyFull {
+		// The standbys connect to the primary via the read-write service,
+		// and with verify-full they check the certificate matches its name
+		opts.DNSName = cluster.GetServiceReadWriteName()
+	}
 	err = r.ensureServerLeafCertificate(
 		ctx,
 		cluster,
@@ -108,7 +114,7 @@ func (r *ClusterReconciler) ensureClientCASecret(ctx context.Context, cluster *a
 		return nil, err
 	}
 
-	err = r.verifyCAValidity(secret, cluster)
+	err = r.verifyCAValidity(ctx, secret, cluster)
 	if err != nil {
 		return nil, err
 	}
@@ -145,7 +151,7 @@ func (r *ClusterReconciler) ensureServerCASecret(ctx context.Context, cluster *a
 		return nil, err
 	}
 
-	err = r.verifyCAValidity(secret, cluster)
+	err = r.verifyCAValidity(ctx, secret, cluster)
 	if err != nil {
 		return nil, err
 	}
@@ -164,7 +170,7 @@ func (r *ClusterReconciler) ensureServerCASecret(ctx context.Context, cluster *a
 	return &secret, nil
 }
 
-func (r *ClusterReconciler) verifyCAValidity(secret v1.Secret, cluster *apiv1.Cluster) error {
+func (r *ClusterReconciler) verifyCAValidity(ctx context.Context, secret v1.Secret, cluster *apiv1.Cluster) error {
 	// Verify validity of the CA and expiration (only ca.crt)
 	publicKey, ok := secret.Data[certs.CACertKey]
 	if !ok {
@@ -182,6 +188,8 @@ func (r *ClusterReconciler) verifyCAValidity(secret v1.Secret, cluster *apiv1.Cl
 		r.Recorder.Event(cluster, "Warning", "SecretIsExpiring",
 			"Checking expiring date of secret "+secret.Name)
 		log.Info("CA certificate is expiring or is already expired", "secret", secret.Name)
+		notifications.Notify(ctx, notifications.EventCertificateExpiring, cluster.Namespace, cluster.Name,
+			fmt.Sprintf("The CA certificate in the secret %s is expiring or is already expired", secret.Name))
 	}
 
 	return nil
@@ -210,6 +218,7 @@ func (r *ClusterReconciler) ensureCASecret(ctx context.Context, cluster *apiv1.C
 	}
 
 	derivedCaSecret := caPair.GenerateCASecret(cluster.Namespace, secretName)
+	setGeneratedObjectMetadata(&derivedCaSecret.ObjectMeta, cluster.GetGeneratedSecretsMetadata())
 	utils.SetAsOwnedBy(&derivedCaSecret.ObjectMeta, cluster.ObjectMeta, cluster.TypeMeta)
 	err = r.Create(ctx, derivedCaSecret)
 
@@ -308,9 +317,10 @@ func (r *ClusterReconciler) ensureLeafCertificate(
 		return err
 	}
 
+	setGeneratedObjectMetadata(&serverSecret.ObjectMeta, cluster.GetGeneratedSecretsMetadata())
 	utils.SetAsOwnedBy(&serverSecret.ObjectMeta, cluster.ObjectMeta, cluster.TypeMeta)
 	for k, v := range additionalLabels {
-		if serverSecret.Annotations == nil {
+		if serverSecret.Labels == nil {
 			serverSecret.Labels = make(map[string]string)
 		}
 		serverSecret.Labels[k] = v
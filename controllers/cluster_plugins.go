@@ -0,0 +1,73 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/plugin"
+)
+
+// mutatePodWithPlugins passes the definition of an instance Pod to
+// the plugins used by the cluster, which can change it, for example
+// adding a sidecar container
+func (r *ClusterReconciler) mutatePodWithPlugins(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pod *corev1.Pod,
+) error {
+	if len(cluster.Spec.Plugins) == 0 {
+		return nil
+	}
+
+	mutatedPod, err := plugin.MutatePod(ctx, r.Client, cluster, pod.DeepCopy())
+	if err != nil {
+		return err
+	}
+
+	mutatedPod.DeepCopyInto(pod)
+	return nil
+}
+
+// mutateJobWithPlugins passes the Pod template of an instance Job to
+// the plugins used by the cluster, which can change it
+func (r *ClusterReconciler) mutateJobWithPlugins(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	job *batchv1.Job,
+) error {
+	if len(cluster.Spec.Plugins) == 0 {
+		return nil
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: *job.Spec.Template.ObjectMeta.DeepCopy(),
+		Spec:       *job.Spec.Template.Spec.DeepCopy(),
+	}
+	mutatedPod, err := plugin.MutatePod(ctx, r.Client, cluster, pod)
+	if err != nil {
+		return err
+	}
+
+	job.Spec.Template.ObjectMeta = mutatedPod.ObjectMeta
+	job.Spec.Template.Spec = mutatedPod.Spec
+	return nil
+}
@@ -0,0 +1,290 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcilePVCRetentionFinalizer adds the finalizer retaining the PVCs
+// when the cluster requires its PVCs to survive its deletion, and
+// removes it otherwise
+func (r *ClusterReconciler) reconcilePVCRetentionFinalizer(ctx context.Context, cluster *apiv1.Cluster) error {
+	shouldRetain := cluster.GetPVCRetentionPolicyWhenDeleted() == apiv1.PVCRetentionPolicyRetain
+	hasFinalizer := controllerutil.ContainsFinalizer(cluster, utils.RetainPVCsFinalizerName)
+	if shouldRetain == hasFinalizer {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	if shouldRetain {
+		controllerutil.AddFinalizer(cluster, utils.RetainPVCsFinalizerName)
+	} else {
+		controllerutil.RemoveFinalizer(cluster, utils.RetainPVCsFinalizerName)
+	}
+
+	return r.Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// reconcileClusterDeletion releases the PVCs of a cluster being deleted
+// when the retention policy requires it, and then removes the finalizer
+// allowing Kubernetes to complete the deletion
+func (r *ClusterReconciler) reconcileClusterDeletion(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	if !controllerutil.ContainsFinalizer(cluster, utils.RetainPVCsFinalizerName) {
+		return nil
+	}
+
+	if cluster.GetPVCRetentionPolicyWhenDeleted() == apiv1.PVCRetentionPolicyRetain {
+		pvcs, err := r.getManagedPVCs(ctx, cluster)
+		if err != nil {
+			return err
+		}
+
+		for idx := range pvcs.Items {
+			contextLogger.Info("Releasing PVC from the deleted cluster", "pvcName", pvcs.Items[idx].Name)
+			if err := r.releasePVC(ctx, cluster, &pvcs.Items[idx]); err != nil {
+				return err
+			}
+		}
+	}
+
+	origCluster := cluster.DeepCopy()
+	controllerutil.RemoveFinalizer(cluster, utils.RetainPVCsFinalizerName)
+	return r.Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// retainInstancePVCs marks the PVCs of an instance removed by a scale-down
+// as detached, so that they are kept but not used by any other instance.
+// The current timeline is recorded to validate the PVCs before reattaching
+// them on a later scale-up
+func (r *ClusterReconciler) retainInstancePVCs(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instanceName string,
+) error {
+	for _, role := range []utils.PVCRole{utils.PVCRolePgData, utils.PVCRolePgWal} {
+		if role == utils.PVCRolePgWal && !cluster.ShouldCreateWalArchiveVolume() {
+			continue
+		}
+
+		var pvc corev1.PersistentVolumeClaim
+		err := r.Get(ctx, client.ObjectKey{
+			Namespace: cluster.Namespace,
+			Name:      specs.GetPVCName(*cluster, instanceName, role),
+		}, &pvc)
+		if apierrs.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		origPVC := pvc.DeepCopy()
+		if pvc.Annotations == nil {
+			pvc.Annotations = make(map[string]string, 2)
+		}
+		pvc.Annotations[specs.PVCStatusAnnotationName] = specs.PVCStatusDetached
+		pvc.Annotations[specs.PVCDetachedTimelineAnnotationName] = strconv.Itoa(cluster.Status.TimelineID)
+		if err := r.Patch(ctx, &pvc, client.MergeFrom(origPVC)); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while detaching PVC %s: %w", pvc.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// releasePVC marks a PVC as detached and removes the owner reference
+// pointing to the cluster, so that the PVC is not garbage collected
+// together with the cluster
+func (r *ClusterReconciler) releasePVC(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pvc *corev1.PersistentVolumeClaim,
+) error {
+	origPVC := pvc.DeepCopy()
+
+	if pvc.Annotations == nil {
+		pvc.Annotations = make(map[string]string, 1)
+	}
+	pvc.Annotations[specs.PVCStatusAnnotationName] = specs.PVCStatusDetached
+
+	pvc.OwnerReferences = removeClusterOwnerReference(pvc.OwnerReferences, cluster)
+
+	if err := r.Patch(ctx, pvc, client.MergeFrom(origPVC)); err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while detaching PVC %s: %w", pvc.Name, err)
+	}
+
+	return nil
+}
+
+// removeClusterOwnerReference removes the owner reference pointing
+// to the passed cluster from a list of owner references
+func removeClusterOwnerReference(
+	references []metav1.OwnerReference,
+	cluster *apiv1.Cluster,
+) []metav1.OwnerReference {
+	result := make([]metav1.OwnerReference, 0, len(references))
+	for _, reference := range references {
+		if reference.Kind == apiv1.ClusterKind && reference.UID == cluster.UID {
+			continue
+		}
+		result = append(result, reference)
+	}
+
+	return result
+}
+
+// reattachRetainedPVCs looks for the PVCs retained by a previous scale-down
+// and, if a usable set is found, marks them as ready again. They will then
+// be reattached to a new Pod as dangling PVCs, instead of cloning a new
+// instance from scratch. Returns true when a set of PVCs has been reattached
+func (r *ClusterReconciler) reattachRetainedPVCs(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (bool, error) {
+	contextLogger := log.FromContext(ctx)
+
+	instanceName := electRetainedInstanceToReattach(cluster, resources.pvcs.Items, resources.instances.Items)
+	if instanceName == "" {
+		return false, nil
+	}
+
+	contextLogger.Info("Reattaching the PVCs retained by a previous scale-down",
+		"instance", instanceName)
+	r.Recorder.Eventf(cluster, "Normal", "ReattachPVCs",
+		"Scaling up: reusing the PVCs of instance %v", instanceName)
+
+	for idx := range resources.pvcs.Items {
+		pvc := &resources.pvcs.Items[idx]
+		if !specs.DoesPVCBelongToInstance(cluster, instanceName, pvc.Name) {
+			continue
+		}
+
+		origPVC := pvc.DeepCopy()
+		pvc.Annotations[specs.PVCStatusAnnotationName] = specs.PVCStatusReady
+		pvc.Annotations[specs.ReattachedAnnotationName] = "true"
+		delete(pvc.Annotations, specs.PVCDetachedTimelineAnnotationName)
+		if err := r.Patch(ctx, pvc, client.MergeFrom(origPVC)); err != nil {
+			return false, fmt.Errorf("while reattaching PVC %s: %w", pvc.Name, err)
+		}
+	}
+
+	return true, nil
+}
+
+// electRetainedInstanceToReattach chooses the instance whose retained PVCs
+// can be reused by a scale-up, returning an empty string if there is none.
+// The PVCs of an instance can be reused when they are all present and have
+// been detached by a scale-down on a timeline not newer than the current
+// one of the cluster, and no Pod is using them. When the timeline is older,
+// the instance manager will align the data directory with pg_rewind at startup.
+// The instances detached on the most recent timeline are preferred, and
+// among them the ones with the higher serial
+func electRetainedInstanceToReattach(
+	cluster *apiv1.Cluster,
+	pvcs []corev1.PersistentVolumeClaim,
+	pods []corev1.Pod,
+) string {
+	type candidate struct {
+		instanceName string
+		serial       int
+		timeline     int
+	}
+
+	podNames := make(map[string]bool, len(pods))
+	for idx := range pods {
+		podNames[pods[idx].Name] = true
+	}
+
+	pvcsByInstance := make(map[string]map[string]corev1.PersistentVolumeClaim)
+	for idx := range pvcs {
+		instanceName := pvcs[idx].Labels[utils.InstanceNameLabelName]
+		if instanceName == "" || podNames[instanceName] {
+			continue
+		}
+		if pvcsByInstance[instanceName] == nil {
+			pvcsByInstance[instanceName] = make(map[string]corev1.PersistentVolumeClaim)
+		}
+		pvcsByInstance[instanceName][pvcs[idx].Name] = pvcs[idx]
+	}
+
+	var candidates []candidate
+instancesLoop:
+	for instanceName, instancePVCs := range pvcsByInstance {
+		dataPVC, ok := instancePVCs[instanceName]
+		if !ok {
+			continue
+		}
+
+		serial, err := specs.GetNodeSerial(dataPVC.ObjectMeta)
+		if err != nil {
+			continue
+		}
+
+		timeline := -1
+		for _, pvcName := range specs.GetExpectedInstancePVCNames(cluster, instanceName) {
+			pvc, ok := instancePVCs[pvcName]
+			if !ok || pvc.Annotations[specs.PVCStatusAnnotationName] != specs.PVCStatusDetached {
+				continue instancesLoop
+			}
+
+			pvcTimeline, err := strconv.Atoi(pvc.Annotations[specs.PVCDetachedTimelineAnnotationName])
+			if err != nil || pvcTimeline > cluster.Status.TimelineID {
+				continue instancesLoop
+			}
+			if timeline == -1 || pvcTimeline < timeline {
+				timeline = pvcTimeline
+			}
+		}
+
+		candidates = append(candidates, candidate{
+			instanceName: instanceName,
+			serial:       serial,
+			timeline:     timeline,
+		})
+	}
+
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].timeline != candidates[j].timeline {
+			return candidates[i].timeline > candidates[j].timeline
+		}
+		return candidates[i].serial > candidates[j].serial
+	})
+
+	return candidates[0].instanceName
+}
@@ -0,0 +1,186 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PVC retention", func() {
+	It("removes only the owner reference of the cluster", func() {
+		cluster := &apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", UID: "cluster-uid"}}
+		references := []metav1.OwnerReference{
+			{Kind: apiv1.ClusterKind, Name: "cluster-example", UID: "cluster-uid"},
+			{Kind: "ConfigMap", Name: "other", UID: "other-uid"},
+		}
+
+		result := removeClusterOwnerReference(references, cluster)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Name).To(Equal("other"))
+	})
+
+	It("elects the retained PVCs to reattach", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Status:     apiv1.ClusterStatus{TimelineID: 2},
+		}
+		pods := []corev1.Pod{{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}}}
+
+		By("ignoring the PVCs which are not detached or are used by a Pod", func() {
+			pvcs := []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "cluster-example-1",
+						Labels: map[string]string{utils.InstanceNameLabelName: "cluster-example-1"},
+						Annotations: map[string]string{
+							specs.ClusterSerialAnnotationName:       "1",
+							specs.PVCStatusAnnotationName:           specs.PVCStatusDetached,
+							specs.PVCDetachedTimelineAnnotationName: "2",
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "cluster-example-2",
+						Labels: map[string]string{utils.InstanceNameLabelName: "cluster-example-2"},
+						Annotations: map[string]string{
+							specs.ClusterSerialAnnotationName:       "2",
+							specs.PVCStatusAnnotationName:           specs.PVCStatusReady,
+							specs.PVCDetachedTimelineAnnotationName: "2",
+						},
+					},
+				},
+			}
+			Expect(electRetainedInstanceToReattach(cluster, pvcs, pods)).To(BeEmpty())
+		})
+
+		By("ignoring the PVCs detached on a newer timeline", func() {
+			pvcs := []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "cluster-example-2",
+						Labels: map[string]string{utils.InstanceNameLabelName: "cluster-example-2"},
+						Annotations: map[string]string{
+							specs.ClusterSerialAnnotationName:       "2",
+							specs.PVCStatusAnnotationName:           specs.PVCStatusDetached,
+							specs.PVCDetachedTimelineAnnotationName: "3",
+						},
+					},
+				},
+			}
+			Expect(electRetainedInstanceToReattach(cluster, pvcs, pods)).To(BeEmpty())
+		})
+
+		By("preferring the most recent timeline and the higher serial", func() {
+			pvcs := []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "cluster-example-2",
+						Labels: map[string]string{utils.InstanceNameLabelName: "cluster-example-2"},
+						Annotations: map[string]string{
+							specs.ClusterSerialAnnotationName:       "2",
+							specs.PVCStatusAnnotationName:           specs.PVCStatusDetached,
+							specs.PVCDetachedTimelineAnnotationName: "1",
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "cluster-example-3",
+						Labels: map[string]string{utils.InstanceNameLabelName: "cluster-example-3"},
+						Annotations: map[string]string{
+							specs.ClusterSerialAnnotationName:       "3",
+							specs.PVCStatusAnnotationName:           specs.PVCStatusDetached,
+							specs.PVCDetachedTimelineAnnotationName: "2",
+						},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "cluster-example-4",
+						Labels: map[string]string{utils.InstanceNameLabelName: "cluster-example-4"},
+						Annotations: map[string]string{
+							specs.ClusterSerialAnnotationName:       "4",
+							specs.PVCStatusAnnotationName:           specs.PVCStatusDetached,
+							specs.PVCDetachedTimelineAnnotationName: "2",
+						},
+					},
+				},
+			}
+			Expect(electRetainedInstanceToReattach(cluster, pvcs, pods)).To(Equal("cluster-example-4"))
+		})
+
+		By("requiring the WAL PVC when the cluster has a WAL storage", func() {
+			clusterWithWal := cluster.DeepCopy()
+			clusterWithWal.Spec.WalStorage = &apiv1.StorageConfiguration{Size: "1Gi"}
+			pvcs := []corev1.PersistentVolumeClaim{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:   "cluster-example-2",
+						Labels: map[string]string{utils.InstanceNameLabelName: "cluster-example-2"},
+						Annotations: map[string]string{
+							specs.ClusterSerialAnnotationName:       "2",
+							specs.PVCStatusAnnotationName:           specs.PVCStatusDetached,
+							specs.PVCDetachedTimelineAnnotationName: "2",
+						},
+					},
+				},
+			}
+			Expect(electRetainedInstanceToReattach(clusterWithWal, pvcs, pods)).To(BeEmpty())
+		})
+	})
+
+	It("keeps the PVCs of the removed instance when scaling down", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		cluster.Spec.StorageConfiguration.PersistentVolumeClaimRetentionPolicy = &apiv1.PVCRetentionPolicy{
+			WhenScaled: apiv1.PVCRetentionPolicyRetain,
+		}
+
+		resources := &managedResources{
+			pvcs:      corev1.PersistentVolumeClaimList{Items: generateFakePVCWithDefaultClient(cluster)},
+			jobs:      batchv1.JobList{Items: generateFakeInitDBJobsWithDefaultClient(cluster)},
+			instances: corev1.PodList{Items: generateFakeClusterPodsWithDefaultClient(cluster, true)},
+		}
+
+		sacrificialInstance := getSacrificialInstance(resources.instances.Items)
+		Expect(clusterReconciler.scaleDownCluster(ctx, cluster, resources)).To(Succeed())
+
+		var pvc corev1.PersistentVolumeClaim
+		err := k8sClient.Get(
+			ctx,
+			types.NamespacedName{Name: sacrificialInstance.Name, Namespace: cluster.Namespace},
+			&pvc,
+		)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pvc.Annotations).To(HaveKeyWithValue(specs.PVCStatusAnnotationName, specs.PVCStatusDetached))
+		Expect(pvc.Annotations).To(HaveKey(specs.PVCDetachedTimelineAnnotationName))
+	})
+})
@@ -71,19 +71,25 @@ func (r *ClusterReconciler) scaleDownCluster(
 		}
 	}
 
-	// Let's drop the PVC too
-	pvc := v1.PersistentVolumeClaim{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      sacrificialInstance.Name,
-			Namespace: sacrificialInstance.Namespace,
-		},
-	}
+	if cluster.GetPVCRetentionPolicyWhenScaled() == apiv1.PVCRetentionPolicyRetain {
+		// The PVCs need to be kept, let's mark them as detached
+		if err := r.retainInstancePVCs(ctx, cluster, sacrificialInstance.Name); err != nil {
+			return fmt.Errorf("scaling down node (pvc) %v: %w", sacrificialInstance.Name, err)
+		}
+	} else {
+		// Let's drop the PVC too
+		pvc := v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      sacrificialInstance.Name,
+				Namespace: sacrificialInstance.Namespace,
+			},
+		}
 
-	err := r.Delete(ctx, &pvc)
-	if err != nil {
-		// Ignore if NotFound, otherwise report the error
-		if !apierrs.IsNotFound(err) {
-			return fmt.Errorf("scaling down node (pvc) %v: %v", sacrificialInstance.Name, err)
+		if err := r.Delete(ctx, &pvc); err != nil {
+			// Ignore if NotFound, otherwise report the error
+			if !apierrs.IsNotFound(err) {
+				return fmt.Errorf("scaling down node (pvc) %v: %v", sacrificialInstance.Name, err)
+			}
 		}
 	}
 
@@ -93,7 +99,7 @@ func (r *ClusterReconciler) scaleDownCluster(
 			// This job was working against the PVC of this Pod,
 			// let's remove it
 			foreground := metav1.DeletePropagationForeground
-			err = r.Delete(
+			err := r.Delete(
 				ctx,
 				&resources.jobs.Items[idx],
 				&client.DeleteOptions{
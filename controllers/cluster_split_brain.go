@@ -0,0 +1,135 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/notifications"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// ErrSplitBrainSuspected is raised when an automated failover or switchover
+// is blocked because the instances don't agree on the primary or on its timeline
+var ErrSplitBrainSuspected = fmt.Errorf("split-brain suspected, automated failover is blocked")
+
+// reconcileSplitBrainDetection compares the roles and the timelines
+// reported by the instances, updating the SplitBrainSuspected condition
+func (r *ClusterReconciler) reconcileSplitBrainDetection(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	statuses postgres.PostgresqlStatusList,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	condition := detectSplitBrain(cluster, statuses)
+	wasSuspected := isSplitBrainSuspected(cluster)
+
+	origCluster := cluster.DeepCopy()
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	if reflect.DeepEqual(origCluster.Status.Conditions, cluster.Status.Conditions) {
+		return nil
+	}
+
+	if condition.Status == metav1.ConditionTrue && !wasSuspected {
+		contextLogger.Warning("Split-brain suspected, blocking automated failovers",
+			"reason", condition.Reason, "message", condition.Message)
+		statuses.LogStatus(ctx)
+		r.Recorder.Event(cluster, "Warning", "SplitBrainSuspected", condition.Message)
+		notifications.Notify(ctx, notifications.EventSplitBrainSuspected,
+			cluster.Namespace, cluster.Name, condition.Message)
+	}
+
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// isSplitBrainSuspected checks if the last reconciliation
+// found the instances in a diverging state
+func isSplitBrainSuspected(cluster *apiv1.Cluster) bool {
+	return meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionSplitBrainSuspected))
+}
+
+// detectSplitBrain builds the SplitBrainSuspected condition given the
+// status reported by the instances. The instances which failed to report
+// their status are not considered. A split-brain is suspected when more than
+// one instance is a primary, or when a replica is on a timeline newer than the
+// primary one. The LSNs are not compared, as the instances report them at
+// different times and a replica can be legitimately ahead of a primary
+// sampled a moment before
+func detectSplitBrain(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) metav1.Condition {
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionSplitBrainSuspected),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonInstancesConsistent),
+		Message: "The instances agree on the primary and on its timeline",
+	}
+
+	var primaries []string
+	for _, item := range statuses.Items {
+		if item.Error == nil && item.IsPrimary {
+			primaries = append(primaries, item.Pod.Name)
+		}
+	}
+
+	if len(primaries) > 1 {
+		sort.Strings(primaries)
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = string(apiv1.ConditionReasonMultiplePrimaries)
+		condition.Message = fmt.Sprintf("Multiple instances claim to be the primary: %s",
+			strings.Join(primaries, ", "))
+		return condition
+	}
+
+	primary := getPrimaryStatus(&statuses)
+	if primary == nil || primary.TimeLineID == 0 {
+		return condition
+	}
+
+	var divergedInstances []string
+	for _, item := range statuses.Items {
+		if item.Error != nil || item.IsPrimary || item.TimeLineID == 0 {
+			continue
+		}
+
+		if item.TimeLineID > primary.TimeLineID {
+			divergedInstances = append(divergedInstances,
+				fmt.Sprintf("%s (timeline %d)", item.Pod.Name, item.TimeLineID))
+		}
+	}
+
+	if len(divergedInstances) > 0 {
+		sort.Strings(divergedInstances)
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = string(apiv1.ConditionReasonTimelineDiverged)
+		condition.Message = fmt.Sprintf(
+			"Instances ahead of the timeline of the primary %s (timeline %d): %s",
+			primary.Pod.Name, primary.TimeLineID,
+			strings.Join(divergedInstances, ", "))
+	}
+
+	return condition
+}
@@ -0,0 +1,174 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Split-brain detection", func() {
+	cluster := &apiv1.Cluster{}
+
+	It("doesn't suspect anything when the replicas follow the primary", func() {
+		condition := detectSplitBrain(cluster, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{
+				Pod:        corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+				IsPrimary:  true,
+				TimeLineID: 2,
+				CurrentLsn: "0/5000000",
+			},
+			{
+				Pod:                 corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+				TimeLineID:          2,
+				LatestCheckpointLSN: "0/4000000",
+			},
+			{
+				Pod:                 corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}},
+				TimeLineID:          1,
+				LatestCheckpointLSN: "0/3000000",
+			},
+		}})
+		Expect(condition.Type).To(Equal(string(apiv1.ConditionSplitBrainSuspected)))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonInstancesConsistent)))
+	})
+
+	It("suspects a split-brain when more than one instance is a primary", func() {
+		condition := detectSplitBrain(cluster, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{
+				Pod:        corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}},
+				IsPrimary:  true,
+				TimeLineID: 3,
+				CurrentLsn: "0/5000000",
+			},
+			{
+				Pod:        corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+				IsPrimary:  true,
+				TimeLineID: 2,
+				CurrentLsn: "0/5000000",
+			},
+			{
+				Pod:                 corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+				TimeLineID:          2,
+				LatestCheckpointLSN: "0/4000000",
+			},
+		}})
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonMultiplePrimaries)))
+		Expect(condition.Message).To(ContainSubstring("cluster-example-1, cluster-example-3"))
+	})
+
+	It("ignores the instances which didn't report their status", func() {
+		failedPrimary := postgres.PostgresqlStatus{
+			Pod:        corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}},
+			IsPrimary:  true,
+			TimeLineID: 3,
+			CurrentLsn: "0/5000000",
+		}
+		failedPrimary.Error = fmt.Errorf("the heartbeat lease of the primary has expired")
+		condition := detectSplitBrain(cluster, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{
+				Pod:        corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+				IsPrimary:  true,
+				TimeLineID: 2,
+				CurrentLsn: "0/5000000",
+			},
+			failedPrimary,
+		}})
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("suspects a split-brain when a replica is on a newer timeline", func() {
+		condition := detectSplitBrain(cluster, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{
+				Pod:        corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+				IsPrimary:  true,
+				TimeLineID: 2,
+				CurrentLsn: "0/5000000",
+			},
+			{
+				Pod:                 corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+				TimeLineID:          3,
+				LatestCheckpointLSN: "0/4000000",
+			},
+		}})
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonTimelineDiverged)))
+		Expect(condition.Message).To(ContainSubstring("cluster-example-2 (timeline 3)"))
+	})
+
+	It("doesn't compare the LSNs sampled on different instances", func() {
+		condition := detectSplitBrain(cluster, postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{
+				Pod:        corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+				IsPrimary:  true,
+				TimeLineID: 2,
+				CurrentLsn: "0/5000000",
+			},
+			{
+				Pod:                 corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+				TimeLineID:          2,
+				LatestCheckpointLSN: "0/6000000",
+			},
+		}})
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonInstancesConsistent)))
+	})
+
+	It("blocks the automated failover while a split-brain is suspected", func() {
+		suspectedCluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+				Conditions: []metav1.Condition{
+					{
+						Type:   string(apiv1.ConditionSplitBrainSuspected),
+						Status: metav1.ConditionTrue,
+						Reason: string(apiv1.ConditionReasonMultiplePrimaries),
+					},
+				},
+			},
+		}
+		r := &ClusterReconciler{}
+		_, err := r.updateTargetPrimaryFromPodsPrimaryCluster(context.TODO(), suspectedCluster,
+			postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				{
+					Pod:        corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+					IsPrimary:  true,
+					TimeLineID: 3,
+					CurrentLsn: "0/5000000",
+				},
+				{
+					Pod:        corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					IsPrimary:  true,
+					TimeLineID: 2,
+					CurrentLsn: "0/5000000",
+				},
+			}}, &managedResources{})
+		Expect(err).To(Equal(ErrSplitBrainSuspected))
+	})
+})
@@ -299,6 +299,7 @@ func (r *ClusterReconciler) updateResourceStatus(
 	// Services
 	cluster.Status.WriteService = cluster.GetServiceReadWriteName()
 	cluster.Status.ReadService = cluster.GetServiceReadName()
+	cluster.Status.Binding = cluster.GetServiceBinding()
 
 	// If we are switching, check if the target primary is still active
 	// Ignore this check if current primary is empty (it happens during the bootstrap)
@@ -419,8 +420,8 @@ func (r *ClusterReconciler) updateOnlineUpdateEnabled(
 // SetClusterOwnerAnnotationsAndLabels sets the cluster as owner of the passed object and then
 // sets all the needed annotations and labels
 func SetClusterOwnerAnnotationsAndLabels(obj *metav1.ObjectMeta, cluster *apiv1.Cluster) {
-	utils.InheritAnnotations(obj, cluster.Annotations, cluster.GetFixedInheritedAnnotations(), configuration.Current)
-	utils.InheritLabels(obj, cluster.Labels, cluster.GetFixedInheritedLabels(), configuration.Current)
+	utils.InheritAnnotations(obj, cluster.Annotations, cluster.GetFixedInheritedAnnotations(), configuration.Current())
+	utils.InheritLabels(obj, cluster.Labels, cluster.GetFixedInheritedLabels(), configuration.Current())
 	utils.LabelClusterName(obj, cluster.GetName())
 	utils.SetAsOwnedBy(obj, cluster.ObjectMeta, cluster.TypeMeta)
 	utils.SetOperatorVersion(obj, versions.Version)
@@ -603,6 +604,17 @@ func (r *ClusterReconciler) refreshConfigMapResourceVersions(ctx context.Context
 		}
 	}
 
+	if files := cluster.Spec.PostgresConfiguration.ConfigurationFiles; len(files) > 0 {
+		versions.ConfigurationFiles = make(map[string]string, len(files))
+		for _, config := range files {
+			version, err := r.getConfigMapResourceVersion(ctx, cluster, config.Name)
+			if err != nil {
+				return err
+			}
+			versions.ConfigurationFiles[config.Name] = version
+		}
+	}
+
 	cluster.Status.ConfigMapResourceVersion = versions
 
 	return nil
@@ -727,6 +739,20 @@ func (r *ClusterReconciler) setPrimaryInstance(
 ) error {
 	cluster.Status.TargetPrimary = podName
 	cluster.Status.TargetPrimaryTimestamp = utils.GetCurrentTimestamp()
+	cluster.Status.SwitchoverTarget = ""
+	return r.Status().Update(ctx, cluster)
+}
+
+// setSwitchoverTarget sets the passed instance as the target primary of a
+// switchover, which is requested while the current primary is healthy
+func (r *ClusterReconciler) setSwitchoverTarget(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	podName string,
+) error {
+	cluster.Status.TargetPrimary = podName
+	cluster.Status.TargetPrimaryTimestamp = utils.GetCurrentTimestamp()
+	cluster.Status.SwitchoverTarget = podName
 	return r.Status().Update(ctx, cluster)
 }
 
@@ -783,10 +809,18 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 	cluster.Status.InstancesReportedState = make(map[apiv1.PodName]apiv1.InstanceReportedState, len(statuses.Items))
 
 	// we extract the instances reported state
-	for _, item := range statuses.Items {
+	for idx, item := range statuses.Items {
+		health, healthReason := getInstanceHealth(&statuses.Items[idx])
 		cluster.Status.InstancesReportedState[apiv1.PodName(item.Pod.Name)] = apiv1.InstanceReportedState{
-			IsPrimary:  item.IsPrimary,
-			TimeLineID: item.TimeLineID,
+			IsPrimary:                item.IsPrimary,
+			TimeLineID:               item.TimeLineID,
+			SystemID:                 item.SystemID,
+			LatestCheckpointLSN:      string(item.LatestCheckpointLSN),
+			Health:                   health,
+			HealthReason:             healthReason,
+			WalReceiverStatus:        getWalReceiverStatus(item),
+			WalSenders:               getWalSenderStates(item),
+			PendingRestartParameters: item.PendingRestartParameters,
 		}
 	}
 
@@ -797,6 +831,15 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 		if item.IsPrimary && item.TimeLineID != 0 {
 			cluster.Status.TimelineID = item.TimeLineID
 		}
+
+		// we report the WAL archiving status of the primary, to let
+		// the user check whether the RPO is at risk
+		if item.IsPrimary && item.Error == nil {
+			cluster.Status.LastArchivedWAL = item.LastArchivedWAL
+			cluster.Status.LastArchivedWALTime = getArchiverTimestamp(item.LastArchivedWAL, item.LastArchivedWALTime)
+			cluster.Status.LastFailedWAL = item.LastFailedWAL
+			cluster.Status.LastFailedWALTime = getArchiverTimestamp(item.LastFailedWAL, item.LastFailedWALTime)
+		}
 	}
 
 	if !reflect.DeepEqual(existingClusterStatus, cluster.Status) {
@@ -805,6 +848,51 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 	return nil
 }
 
+// getArchiverTimestamp converts a time reported by pg_stat_archiver to
+// the RFC3339 format, returning an empty string when the related WAL
+// file is not set or the time can't be parsed
+func getArchiverTimestamp(walFile, timestamp string) string {
+	if walFile == "" {
+		return ""
+	}
+
+	parsedTimestamp, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return ""
+	}
+
+	return parsedTimestamp.Format(time.RFC3339)
+}
+
+// getWalReceiverStatus gets the status of the WAL receiver of a standby
+func getWalReceiverStatus(item postgres.PostgresqlStatus) string {
+	if item.IsPrimary || item.WalReceiverInfo == nil {
+		return ""
+	}
+	return item.WalReceiverInfo.Status
+}
+
+// getWalSenderStates summarizes the WAL senders of the primary, leaving out
+// the LSNs, which would require an update of the status at every reconciliation
+func getWalSenderStates(item postgres.PostgresqlStatus) []apiv1.WalSenderState {
+	if !item.IsPrimary || len(item.ReplicationInfo) == 0 {
+		return nil
+	}
+
+	senders := make([]apiv1.WalSenderState, 0, len(item.ReplicationInfo))
+	for _, sender := range item.ReplicationInfo {
+		senders = append(senders, apiv1.WalSenderState{
+			Name:      sender.ApplicationName,
+			State:     sender.State,
+			SyncState: sender.SyncState,
+		})
+	}
+	sort.Slice(senders, func(i, j int) bool {
+		return senders[i].Name < senders[j].Name
+	})
+	return senders
+}
+
 // extractInstancesStatus extracts the status of the underlying PostgreSQL instance from
 // the requested Pod, via the instance manager. In case of failure, errors are passed
 // in the result list
@@ -920,6 +1008,7 @@ func getPodsTopology(
 	topology apiv1.SyncReplicaElectionConstraints,
 ) apiv1.Topology {
 	contextLogger := log.FromContext(ctx)
+	locations := getPodsLocation(pods, nodes)
 	data := make(map[apiv1.PodName]apiv1.PodTopologyLabels)
 	for _, pod := range pods {
 		podName := apiv1.PodName(pod.Name)
@@ -930,12 +1019,33 @@ func getPodsTopology(
 			// - the node could have been drained
 			// - others
 			contextLogger.Debug("node not found, skipping pod topology matching")
-			return apiv1.Topology{}
+			return apiv1.Topology{Locations: locations}
 		}
-		for _, labelName := range topology.NodeLabelsAntiAffinity {
+		for _, labelName := range topology.GetNodeLabelsAntiAffinity() {
 			data[podName][labelName] = node.Labels[labelName]
 		}
 	}
 
-	return apiv1.Topology{SuccessfullyExtracted: true, Instances: data}
+	return apiv1.Topology{SuccessfullyExtracted: true, Instances: data, Locations: locations}
+}
+
+// getPodsLocation returns the node and the topology zone where each
+// scheduled pod is running. The zone is empty when the node is unknown
+func getPodsLocation(pods []corev1.Pod, nodes map[string]corev1.Node) map[apiv1.PodName]apiv1.InstanceLocation {
+	locations := make(map[apiv1.PodName]apiv1.InstanceLocation, len(pods))
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" {
+			continue
+		}
+
+		locations[apiv1.PodName(pod.Name)] = apiv1.InstanceLocation{
+			Node: pod.Spec.NodeName,
+			Zone: nodes[pod.Spec.NodeName].Labels[corev1.LabelTopologyZone],
+		}
+	}
+
+	if len(locations) == 0 {
+		return nil
+	}
+	return locations
 }
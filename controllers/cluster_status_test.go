@@ -21,12 +21,14 @@ import (
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -107,6 +109,22 @@ var _ = Describe("cluster_status unit tests", func() {
 		})
 	})
 
+	It("marks the switchover target, and resets it choosing another target primary", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+
+		err := clusterReconciler.setSwitchoverTarget(ctx, cluster, "test-pod-2")
+		Expect(err).To(BeNil())
+		Expect(cluster.Status.TargetPrimary).To(Equal("test-pod-2"))
+		Expect(cluster.Status.SwitchoverTarget).To(Equal("test-pod-2"))
+
+		err = clusterReconciler.setPrimaryInstance(ctx, cluster, "test-pod-3")
+		Expect(err).To(BeNil())
+		Expect(cluster.Status.TargetPrimary).To(Equal("test-pod-3"))
+		Expect(cluster.Status.SwitchoverTarget).To(BeEmpty())
+	})
+
 	It("makes sure RegisterPhase works correctly", func() {
 		const phaseReason = "testing"
 		ctx := context.Background()
@@ -162,3 +180,85 @@ var _ = Describe("cluster_status unit tests", func() {
 		})
 	})
 })
+
+var _ = Describe("replication state of the instances", func() {
+	primary := postgres.PostgresqlStatus{
+		IsPrimary: true,
+		ReplicationInfo: postgres.PgStatReplicationList{
+			{ApplicationName: "cluster-example-3", State: "catchup", SyncState: "async", SentLsn: "0/3000000"},
+			{ApplicationName: "cluster-example-2", State: "streaming", SyncState: "quorum", SentLsn: "0/5000000"},
+		},
+	}
+	replica := postgres.PostgresqlStatus{
+		WalReceiverInfo: &postgres.PgStatWalReceiver{Status: "streaming", ReceivedLsn: "0/5000000"},
+	}
+
+	It("summarizes the WAL senders of the primary", func() {
+		Expect(getWalSenderStates(primary)).To(Equal([]v1.WalSenderState{
+			{Name: "cluster-example-2", State: "streaming", SyncState: "quorum"},
+			{Name: "cluster-example-3", State: "catchup", SyncState: "async"},
+		}))
+		Expect(getWalReceiverStatus(primary)).To(BeEmpty())
+	})
+
+	It("reports the status of the WAL receiver of a standby", func() {
+		Expect(getWalReceiverStatus(replica)).To(Equal("streaming"))
+		Expect(getWalSenderStates(replica)).To(BeNil())
+	})
+
+	It("reports an empty status when the WAL receiver is not running", func() {
+		Expect(getWalReceiverStatus(postgres.PostgresqlStatus{})).To(BeEmpty())
+	})
+})
+
+var _ = Describe("topology of the instances", func() {
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-1"}, Spec: corev1.PodSpec{NodeName: "node-a"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-2"}, Spec: corev1.PodSpec{NodeName: "node-b"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-3"}},
+	}
+	nodes := map[string]corev1.Node{
+		"node-a": {ObjectMeta: metav1.ObjectMeta{
+			Name:   "node-a",
+			Labels: map[string]string{"topology.kubernetes.io/zone": "zone-a"},
+		}},
+	}
+
+	It("reports the node and the zone of the scheduled instances", func() {
+		Expect(getPodsLocation(pods, nodes)).To(Equal(map[v1.PodName]v1.InstanceLocation{
+			"cluster-1": {Node: "node-a", Zone: "zone-a"},
+			"cluster-2": {Node: "node-b"},
+		}))
+		Expect(getPodsLocation(nil, nodes)).To(BeNil())
+	})
+
+	It("reports the locations even when the topology extraction fails", func() {
+		topology := getPodsTopology(context.TODO(), pods[:2], nodes, v1.SyncReplicaElectionConstraints{
+			Enabled:          true,
+			ZoneAntiAffinity: true,
+		})
+		Expect(topology.SuccessfullyExtracted).To(BeFalse())
+		Expect(topology.Locations).To(HaveLen(2))
+
+		topology = getPodsTopology(context.TODO(), pods[:1], nodes, v1.SyncReplicaElectionConstraints{
+			Enabled:          true,
+			ZoneAntiAffinity: true,
+		})
+		Expect(topology.SuccessfullyExtracted).To(BeTrue())
+		Expect(topology.Instances).To(Equal(map[v1.PodName]v1.PodTopologyLabels{
+			"cluster-1": {"topology.kubernetes.io/zone": "zone-a"},
+		}))
+	})
+})
+
+var _ = Describe("WAL archiving status of the primary", func() {
+	It("reports the archiver times in RFC3339 format", func() {
+		Expect(getArchiverTimestamp("000000010000000000000003", "2022-09-22T10:20:30.123456Z")).
+			To(Equal("2022-09-22T10:20:30Z"))
+	})
+
+	It("ignores the times of the WAL files which were never archived", func() {
+		Expect(getArchiverTimestamp("", "-infinity")).To(BeEmpty())
+		Expect(getArchiverTimestamp("000000010000000000000003", "-infinity")).To(BeEmpty())
+	})
+})
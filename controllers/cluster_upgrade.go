@@ -18,20 +18,27 @@ package controllers
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	neturl "net/url"
+	"strings"
+	"time"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/notifications"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/executablehash"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
@@ -47,9 +54,10 @@ func (r *ClusterReconciler) rolloutDueToCondition(
 	// The following code works under the assumption that podList.Items list is ordered
 	// by lag (primary first)
 
-	// upgrade all the replicas starting from the more lagged
+	// upgrade all the replicas starting from the degraded ones, which
+	// aren't working correctly anyway, and then from the more lagged
 	var primaryPostgresqlStatus *postgres.PostgresqlStatus
-	for i := len(podList.Items) - 1; i >= 0; i-- {
+	for _, i := range getRolloutOrder(podList) {
 		postgresqlStatus := podList.Items[i]
 
 		// If this pod is the current primary, we upgrade it in the last step
@@ -62,11 +70,21 @@ func (r *ClusterReconciler) rolloutDueToCondition(
 			continue
 		}
 
-		shouldRestart, _, reason := conditionFunc(postgresqlStatus, cluster)
+		shouldRestart, inPlacePossible, reason := conditionFunc(postgresqlStatus, cluster)
 		if !shouldRestart {
 			continue
 		}
 
+		if inPlacePossible && isReplicaRestartingInPlace(cluster, postgresqlStatus) {
+			if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseUpgrade,
+				fmt.Sprintf("Restarting instance %s in-place, because: %s", postgresqlStatus.Pod.Name, reason),
+			); err != nil {
+				return false, fmt.Errorf("postgresqlStatus pod name: %s, %w", postgresqlStatus.Pod.Name, err)
+			}
+
+			return true, r.restartReplicaInPlace(ctx, cluster, &postgresqlStatus.Pod)
+		}
+
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseUpgrade,
 			fmt.Sprintf("Restarting instance %s, because: %s", postgresqlStatus.Pod.Name, reason),
 		); err != nil {
@@ -103,6 +121,25 @@ func (r *ClusterReconciler) rolloutDueToCondition(
 	return r.updatePrimaryPod(ctx, cluster, podList, primaryPostgresqlStatus.Pod, inPlacePossible, reason)
 }
 
+// getRolloutOrder gets the indexes of the instances in the order they
+// should be restarted: the degraded ones first, then the others, both
+// starting from the more lagged
+func getRolloutOrder(podList *postgres.PostgresqlStatusList) []int {
+	order := make([]int, 0, len(podList.Items))
+	for i := len(podList.Items) - 1; i >= 0; i-- {
+		if isDegraded(&podList.Items[i]) {
+			order = append(order, i)
+		}
+	}
+	for i := len(podList.Items) - 1; i >= 0; i-- {
+		if !isDegraded(&podList.Items[i]) {
+			order = append(order, i)
+		}
+	}
+
+	return order
+}
+
 func (r *ClusterReconciler) updatePrimaryPod(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -126,6 +163,20 @@ func (r *ClusterReconciler) updatePrimaryPod(
 		return true, nil
 	}
 
+	// the replicas are already updated, and the primary waits
+	// for the next maintenance window to be restarted
+	if !cluster.IsPrimaryUpdateAllowed(time.Now()) {
+		contextLogger.Info("Waiting for the next maintenance window to complete the rolling update",
+			"reason", reason)
+		err := r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForMaintenanceWindow,
+			fmt.Sprintf("The primary instance needs to be updated, because: %s", reason))
+		if err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+
 	if cluster.GetPrimaryUpdateMethod() == apiv1.PrimaryUpdateMethodRestart {
 		if inPlacePossible {
 			// In-place restart is possible
@@ -150,17 +201,21 @@ func (r *ClusterReconciler) updatePrimaryPod(
 
 	// if the cluster has more than one instance, we should trigger a switchover before upgrading
 	if cluster.Status.Instances > 1 && len(podList.Items) > 1 {
-		// If this is not a replica cluster, podList.Items[1] is the first replica,
-		// as the pod list is sorted in the same order we use for switchover / failover.
-		// This may not be true for replica clusters, where every instance is a replica
-		// from the PostgreSQL point-of-view.
-		targetPrimary := podList.Items[1].Pod.Name
-
-		// If this is a replica cluster, the target primary we chose may be
-		// the one we're trying to upgrade, as the list isn't sorted. In
-		// this case, we promote the first instance of the list
-		if targetPrimary == primaryPod.Name {
-			targetPrimary = podList.Items[0].Pod.Name
+		targetPrimary := getSwitchoverTarget(cluster, podList, primaryPod.Name)
+		if targetPrimary == "" {
+			// There is no replica that can take over, and the user asked
+			// for a switchover: we wait for one to be available instead
+			// of restarting the primary
+			contextLogger.Info("The primary needs to be restarted, but no replica can be promoted, "+
+				"waiting for a switchover target",
+				"reason", reason,
+				"podList", podList)
+			if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForSwitchoverTarget,
+				fmt.Sprintf("No replica can be promoted to restart the primary instance, because: %s", reason),
+			); err != nil {
+				return false, err
+			}
+			return true, nil
 		}
 
 		contextLogger.Info("The primary needs to be restarted, we'll trigger a switchover to do that",
@@ -170,7 +225,9 @@ func (r *ClusterReconciler) updatePrimaryPod(
 			"podList", podList)
 		r.Recorder.Eventf(cluster, "Normal", "Switchover",
 			"Initiating switchover to %s to upgrade %s", targetPrimary, primaryPod.Name)
-		return true, r.setPrimaryInstance(ctx, cluster, targetPrimary)
+		notifications.Notify(ctx, notifications.EventSwitchover, cluster.Namespace, cluster.Name,
+			fmt.Sprintf("Initiating switchover to %s to upgrade %s", targetPrimary, primaryPod.Name))
+		return true, r.setSwitchoverTarget(ctx, cluster, targetPrimary)
 	}
 
 	// if there is only one instance in the cluster, we should upgrade it even if it's a primary
@@ -184,6 +241,50 @@ func (r *ClusterReconciler) updatePrimaryPod(
 	return true, r.upgradePod(ctx, cluster, &primaryPod)
 }
 
+// getSwitchoverTarget gets the instance to be promoted when the primary needs
+// to be restarted with a switchover. The pod list is sorted in the same order
+// we use for switchover / failover, so the first ready replica that can be
+// promoted is the most aligned one. This may not be true for replica clusters,
+// where every instance is a replica from the PostgreSQL point-of-view.
+// It returns an empty string if no replica can be promoted
+func getSwitchoverTarget(
+	cluster *apiv1.Cluster,
+	podList *postgres.PostgresqlStatusList,
+	primaryName string,
+) string {
+	var excludedInstances []string
+	if cluster.Spec.FailoverCandidates != nil {
+		excludedInstances = cluster.Spec.FailoverCandidates.ExcludedInstances
+	}
+
+	// The ready replicas are preferred to the degraded ones,
+	// which are chosen only when no ready replica is available
+	degradedTarget := ""
+	for idx, item := range podList.Items {
+		switch {
+		case item.Pod.Name == primaryName:
+			continue
+		case item.Error != nil || !item.IsReady:
+			continue
+		case cluster.IsInstanceFenced(item.Pod.Name):
+			continue
+		case utils.StringInSlice(excludedInstances, item.Pod.Name):
+			continue
+		}
+
+		if isDegraded(&podList.Items[idx]) {
+			if degradedTarget == "" {
+				degradedTarget = item.Pod.Name
+			}
+			continue
+		}
+
+		return item.Pod.Name
+	}
+
+	return degradedTarget
+}
+
 func (r *ClusterReconciler) updateRestartAnnotation(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -237,7 +338,7 @@ func IsPodNeedingRollout(status postgres.PostgresqlStatus, cluster *apiv1.Cluste
 		return true, false, ""
 	}
 
-	if configuration.Current.EnableAzurePVCUpdates {
+	if configuration.Current().EnableAzurePVCUpdates {
 		for _, pvcName := range cluster.Status.ResizingPVC {
 			// This code works on the assumption that the PVC begins with the name of the pod using it.
 			if specs.DoesPVCBelongToInstance(cluster, status.Pod.Name, pvcName) {
@@ -252,12 +353,23 @@ func IsPodNeedingRollout(status postgres.PostgresqlStatus, cluster *apiv1.Cluste
 		log.Error(err, "while checking if image could be upgraded")
 		return false, false, ""
 	}
-	if newImage != "" {
-		return true, false, fmt.Sprintf("the instance is using an old image: %s -> %s",
-			oldImage, newImage)
+	// While the update to the new image is not allowed, we don't
+	// roll out the instance for other reasons too, as its new Pod
+	// would be using the new image
+	if newImage != "" && !cluster.IsImageUpdateAllowed(time.Now()) {
+		log.Debug("the image update is deferred by the image update policy",
+			"pod", status.Pod.Name, "oldImage", oldImage, "newImage", newImage)
+		return false, false, ""
 	}
 
-	if !configuration.Current.EnableInstanceManagerInplaceUpdates {
+	if reason, err := GetPodSpecChangeReason(cluster, status.Pod); err != nil {
+		log.Error(err, "while checking if the pod specification changed")
+		return false, false, ""
+	} else if reason != "" {
+		return true, false, reason
+	}
+
+	if !configuration.Current().EnableInstanceManagerInplaceUpdates {
 		oldImage, newImage, err = isPodNeedingUpgradedInitContainerImage(status.Pod)
 		if err != nil {
 			log.Error(err, "while checking if init container image could be upgraded")
@@ -270,8 +382,66 @@ func IsPodNeedingRollout(status postgres.PostgresqlStatus, cluster *apiv1.Cluste
 		}
 	}
 
+	// check if pod needs to be restarted because of some config requiring it
+	return isPodNeedingRestart(cluster, status), true, getRestartReason(status)
+}
+
+// getDeferredImageUpdate checks whether the update of any instance to a new
+// image is deferred, at the passed time, by the image update policy. It
+// returns the reason of the wait, and how long to wait before the next
+// maintenance window starts, which is zero when the update is paused
+func getDeferredImageUpdate(
+	cluster *apiv1.Cluster,
+	podList *postgres.PostgresqlStatusList,
+	now time.Time,
+) (bool, string, time.Duration) {
+	if cluster.IsImageUpdateAllowed(now) {
+		return false, "", 0
+	}
+
+	for _, item := range podList.Items {
+		if cluster.IsInstanceFenced(item.Pod.Name) {
+			continue
+		}
+
+		oldImage, newImage, err := isPodNeedingUpgradedImage(cluster, item.Pod)
+		if err != nil || newImage == "" {
+			continue
+		}
+
+		next, ok := cluster.GetNextImageUpdateWindowStart(now)
+		if !ok {
+			return true, fmt.Sprintf("the update of %s from %s to %s is paused",
+				item.Pod.Name, oldImage, newImage), 0
+		}
+		return true, fmt.Sprintf("the update of %s from %s to %s is deferred to %s",
+			item.Pod.Name, oldImage, newImage, next.Format(time.RFC3339)), next.Sub(now)
+	}
+
+	return false, "", 0
+}
+
+// GetPodSpecChangeReason checks whether the Pod of an instance differs from
+// the one requested by the cluster in the image, the extension images, the
+// resources or the probes of PostgreSQL or, when the drift is repaired
+// automatically, in the fields changed out of band, returning the reason of
+// the rollout or an empty string. The plan command of the plugin uses it to preview
+// the rollouts caused by a change of the cluster
+func GetPodSpecChangeReason(cluster *apiv1.Cluster, pod v1.Pod) (string, error) {
+	oldImage, newImage, err := isPodNeedingUpgradedImage(cluster, pod)
+	if err != nil {
+		return "", err
+	}
+	if newImage != "" {
+		return fmt.Sprintf("the instance is using an old image: %s -> %s", oldImage, newImage), nil
+	}
+
+	if reason := getExtensionImagesChangeReason(*cluster, pod); reason != "" {
+		return reason, nil
+	}
+
 	// Detect changes in the postgres container configuration
-	for _, container := range status.Pod.Spec.Containers {
+	for _, container := range pod.Spec.Containers {
 		// we go to the next array element if it isn't the postgres container
 		if container.Name != specs.PostgresContainerName {
 			continue
@@ -279,15 +449,125 @@ func IsPodNeedingRollout(status postgres.PostgresqlStatus, cluster *apiv1.Cluste
 
 		// Check if there is a change in the resource requirements
 		if !utils.IsResourceSubset(container.Resources, cluster.Spec.Resources) {
-			return true, false, fmt.Sprintf("resources changed, old: %+v, new: %+v",
-				cluster.Spec.Resources,
-				container.Resources)
+			return fmt.Sprintf("resources changed, old: %+v, new: %+v",
+				container.Resources,
+				cluster.Spec.Resources), nil
+		}
+
+		// Check if there is a change in the probes
+		if reason := getPostgresProbesChangeReason(*cluster, container); reason != "" {
+			return reason, nil
 		}
 	}
 
-	// check if pod needs to be restarted because of some config requiring it
-	return isPodNeedingRestart(cluster, status),
-		true, "configuration needs a restart to apply some configuration changes"
+	if cluster.IsDriftAutoRepairEnabled() {
+		return getPodSpecDriftReason(cluster, pod), nil
+	}
+
+	return "", nil
+}
+
+// getRestartReason describes why an instance needs to be restarted,
+// listing the parameters which are waiting for it when known
+func getRestartReason(status postgres.PostgresqlStatus) string {
+	const reason = "configuration needs a restart to apply some configuration changes"
+	if len(status.PendingRestartParameters) == 0 {
+		return reason
+	}
+
+	return fmt.Sprintf("%s: %s", reason, strings.Join(status.PendingRestartParameters, ", "))
+}
+
+// getPostgresProbesChangeReason checks whether the probes of the PostgreSQL
+// container differ from the ones requested by the cluster, returning
+// the reason of the rollout or an empty string. Only the settings chosen
+// by the user are compared, so that a change in the defaults of the
+// operator doesn't cause a rollout of every Pod
+func getPostgresProbesChangeReason(cluster apiv1.Cluster, container v1.Container) string {
+	var startup, liveness, readiness *apiv1.Probe
+	if cluster.Spec.Probes != nil {
+		startup = cluster.Spec.Probes.Startup
+		liveness = cluster.Spec.Probes.Liveness
+		if cluster.Spec.Probes.Readiness != nil {
+			readiness = &cluster.Spec.Probes.Readiness.Probe
+		}
+	}
+
+	probes := []struct {
+		name          string
+		current       *v1.Probe
+		desired       *v1.Probe
+		configuration *apiv1.Probe
+	}{
+		{
+			name:          "startup",
+			current:       container.StartupProbe,
+			desired:       specs.CreateStartupProbe(cluster),
+			configuration: startup,
+		},
+		{
+			name:          "liveness",
+			current:       container.LivenessProbe,
+			desired:       specs.CreateLivenessProbe(cluster),
+			configuration: liveness,
+		},
+		{
+			name:          "readiness",
+			current:       container.ReadinessProbe,
+			desired:       specs.CreateReadinessProbe(cluster),
+			configuration: readiness,
+		},
+	}
+
+	for _, probe := range probes {
+		if isProbeConfigurationChanged(probe.current, probe.desired, probe.configuration) {
+			return fmt.Sprintf("the %s probe changed", probe.name)
+		}
+	}
+
+	// The criteria of the readiness probe is in the path of its request
+	if container.ReadinessProbe != nil && container.ReadinessProbe.HTTPGet != nil &&
+		container.ReadinessProbe.HTTPGet.Path != probes[2].desired.HTTPGet.Path {
+		return "the readiness probe changed"
+	}
+
+	return ""
+}
+
+// isProbeConfigurationChanged checks whether the settings of the current
+// probe differ from the desired ones, which have the defaults applied.
+// Only the probes specified by the user are compared, so that a change
+// of the defaults doesn't roll out the Pods, while clearing a setting
+// restores its default value
+func isProbeConfigurationChanged(current, desired *v1.Probe, configuration *apiv1.Probe) bool {
+	if configuration == nil {
+		return false
+	}
+	if current == nil {
+		return true
+	}
+
+	return current.InitialDelaySeconds != desired.InitialDelaySeconds ||
+		current.TimeoutSeconds != desired.TimeoutSeconds ||
+		current.PeriodSeconds != desired.PeriodSeconds ||
+		current.FailureThreshold != desired.FailureThreshold
+}
+
+// getExtensionImagesChangeReason checks whether the extension images used
+// by the Pod differ from the ones requested by the cluster, returning
+// the reason of the rollout or an empty string
+func getExtensionImagesChangeReason(cluster apiv1.Cluster, pod v1.Pod) string {
+	desired := make(map[string]string, len(cluster.Spec.PostgresConfiguration.ExtensionImages))
+	for _, extensionImage := range cluster.Spec.PostgresConfiguration.ExtensionImages {
+		desired[extensionImage.Name] = extensionImage.Image
+	}
+
+	current := specs.GetExtensionImages(pod)
+	if !equality.Semantic.DeepEqual(current, desired) {
+		return fmt.Sprintf("the extension images changed, old: %v, new: %v", current, desired)
+	}
+
+	return ""
 }
 
 // isPodNeedingUpgradedImage checks whether an image in a pod has to be changed
@@ -328,9 +608,9 @@ func isPodNeedingUpgradedInitContainerImage(
 		return "", "", err
 	}
 
-	if opCurrentImageName != configuration.Current.OperatorImageName {
+	if opCurrentImageName != configuration.Current().OperatorImageName {
 		// We need to apply a different version of the instance manager
-		return opCurrentImageName, configuration.Current.OperatorImageName, nil
+		return opCurrentImageName, configuration.Current().OperatorImageName, nil
 	}
 
 	return "", "", nil
@@ -355,6 +635,81 @@ func isPodNeedingRestart(
 	return instanceStatus.PendingRestart
 }
 
+// isReplicaRestartingInPlace returns true if the replica only needs a restart
+// to apply a configuration change, and PostgreSQL is going to be restarted
+// inside the running Pod because of the replica restart method
+func isReplicaRestartingInPlace(
+	cluster *apiv1.Cluster,
+	instanceStatus postgres.PostgresqlStatus,
+) bool {
+	if cluster.GetReplicaRestartMethod() != apiv1.ReplicaRestartMethodRestart {
+		return false
+	}
+
+	// A restart of the cluster requested by the user is
+	// still applied recreating the Pods
+	if clusterRestart, ok := cluster.Annotations[specs.ClusterRestartAnnotationName]; ok &&
+		clusterRestart != instanceStatus.Pod.Annotations[specs.ClusterRestartAnnotationName] {
+		return false
+	}
+
+	return instanceStatus.PendingRestart
+}
+
+// restartReplicaInPlace requests the instance manager of a replica to
+// restart PostgreSQL inside the running Pod, waiting for it to accept
+// connections again, so that the replicas are restarted one at a time
+func (r *ClusterReconciler) restartReplicaInPlace(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pod *v1.Pod,
+) error {
+	log.FromContext(ctx).Info("Restarting replica in-place", "pod", pod.Name)
+	r.Recorder.Eventf(cluster, "Normal", "RestartingInstance",
+		"Restarting instance %v in-place", pod.Name)
+
+	secureClient, err := r.newSecureInstanceManagerClient(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	if err := webserver.RequestRestart(ctx, secureClient, pod.Status.PodIP); err != nil {
+		return fmt.Errorf("while restarting %s in-place: %w", pod.Name, err)
+	}
+
+	return nil
+}
+
+// newSecureInstanceManagerClient creates an HTTP client for the secure
+// webserver of the instance managers of the cluster, authenticating with
+// the certificate of the streaming replication user
+func (r *ClusterReconciler) newSecureInstanceManagerClient(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (*http.Client, error) {
+	var replicationSecret v1.Secret
+	if err := r.Get(ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetReplicationSecretName()},
+		&replicationSecret); err != nil {
+		return nil, fmt.Errorf("while getting the replication secret: %w", err)
+	}
+	certificate, err := tls.X509KeyPair(
+		replicationSecret.Data[certs.TLSCertKey],
+		replicationSecret.Data[certs.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, fmt.Errorf("while loading the certificate of the replication secret: %w", err)
+	}
+
+	var serverCASecret v1.Secret
+	if err := r.Get(ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetServerCASecretName()},
+		&serverCASecret); err != nil {
+		return nil, fmt.Errorf("while getting the server CA secret: %w", err)
+	}
+
+	return webserver.NewSecureClient(certificate, serverCASecret.Data[certs.CACertKey]), nil
+}
+
 // upgradePod updates an instance to a newer image version
 func (r *ClusterReconciler) upgradePod(ctx context.Context, cluster *apiv1.Cluster, pod *v1.Pod) error {
 	log.FromContext(ctx).Info("Deleting old Pod",
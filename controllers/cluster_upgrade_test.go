@@ -17,9 +17,17 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -90,4 +98,291 @@ var _ = Describe("Pod upgrade", func() {
 		Expect(inplacePossible).To(BeTrue())
 		Expect(reason).To(BeEquivalentTo("configuration needs a restart to apply some configuration changes"))
 	})
+
+	It("lists the parameters waiting for a restart in the rollout reason", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{
+			Pod:                      *pod,
+			IsReady:                  true,
+			ExecutableHash:           "test_hash",
+			PendingRestart:           true,
+			PendingRestartParameters: []string{"max_connections", "shared_buffers"},
+		}
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, &cluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeTrue())
+		Expect(reason).To(Equal("configuration needs a restart to apply some configuration changes: " +
+			"max_connections, shared_buffers"))
+	})
+
+	It("defers the rollout to a new image according to the image update policy", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		pod.Spec.Containers[0].Image = "postgres:13.1"
+		status := postgres.PostgresqlStatus{Pod: *pod, IsReady: true, ExecutableHash: "test_hash"}
+
+		needRollout, _, reason := IsPodNeedingRollout(status, &cluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(reason).To(ContainSubstring("old image"))
+
+		pausedCluster := cluster.DeepCopy()
+		pausedCluster.Spec.ImageUpdate = &apiv1.ImageUpdateConfiguration{Paused: true}
+		needRollout, _, _ = IsPodNeedingRollout(status, pausedCluster)
+		Expect(needRollout).To(BeFalse())
+
+		// A change of the probes is deferred too, as the new Pod would use the new image
+		pausedCluster.Spec.Probes = &apiv1.ProbesConfiguration{
+			Readiness: &apiv1.ReadinessProbe{Type: apiv1.ReadinessProbeTypeStreaming},
+		}
+		needRollout, _, _ = IsPodNeedingRollout(status, pausedCluster)
+		Expect(needRollout).To(BeFalse())
+	})
+
+	It("waits for the next maintenance window when the image update is deferred", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		pod.Spec.Containers[0].Image = "postgres:13.1"
+		podList := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{{Pod: *pod, IsReady: true, ExecutableHash: "test_hash"}},
+		}
+		// 2023-01-07 is a Saturday
+		saturdayNight := time.Date(2023, 1, 7, 23, 30, 0, 0, time.UTC)
+
+		deferred, _, _ := getDeferredImageUpdate(&cluster, &podList, saturdayNight)
+		Expect(deferred).To(BeFalse())
+
+		windowCluster := cluster.DeepCopy()
+		windowCluster.Spec.ImageUpdate = &apiv1.ImageUpdateConfiguration{
+			MaintenanceWindows: []apiv1.MaintenanceWindow{
+				{
+					Days:      []apiv1.MaintenanceWindowDay{"Sunday"},
+					StartTime: "02:00",
+					Duration:  metav1.Duration{Duration: time.Hour},
+				},
+			},
+		}
+		deferred, reason, requeueAfter := getDeferredImageUpdate(windowCluster, &podList, saturdayNight)
+		Expect(deferred).To(BeTrue())
+		Expect(reason).To(ContainSubstring("is deferred to 2023-01-08T02:00:00Z"))
+		Expect(requeueAfter).To(Equal(150 * time.Minute))
+
+		deferred, _, _ = getDeferredImageUpdate(windowCluster, &podList, saturdayNight.Add(3*time.Hour))
+		Expect(deferred).To(BeFalse())
+
+		windowCluster.Spec.ImageUpdate.Paused = true
+		deferred, reason, requeueAfter = getDeferredImageUpdate(windowCluster, &podList, saturdayNight)
+		Expect(deferred).To(BeTrue())
+		Expect(reason).To(ContainSubstring("is paused"))
+		Expect(requeueAfter).To(BeZero())
+	})
+
+	It("doesn't wait when no instance needs a new image", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		podList := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{{Pod: *pod, IsReady: true, ExecutableHash: "test_hash"}},
+		}
+		pausedCluster := cluster.DeepCopy()
+		pausedCluster.Spec.ImageUpdate = &apiv1.ImageUpdateConfiguration{Paused: true}
+
+		deferred, _, _ := getDeferredImageUpdate(pausedCluster, &podList, time.Now())
+		Expect(deferred).To(BeFalse())
+	})
+
+	It("requires rollout when the probes change", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsReady: true, ExecutableHash: "test_hash"}
+		needRollout, _, _ := IsPodNeedingRollout(status, &cluster)
+		Expect(needRollout).To(BeFalse())
+
+		newCluster := cluster.DeepCopy()
+		newCluster.Spec.Probes = &apiv1.ProbesConfiguration{
+			Readiness: &apiv1.ReadinessProbe{Type: apiv1.ReadinessProbeTypeStreaming},
+		}
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, newCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(Equal("the readiness probe changed"))
+	})
+
+	It("doesn't require rollout when only the defaults of the probes changed", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		pod.Spec.Containers[0].StartupProbe = nil
+		pod.Spec.Containers[0].LivenessProbe.PeriodSeconds++
+		pod.Spec.Containers[0].ReadinessProbe.TimeoutSeconds++
+		status := postgres.PostgresqlStatus{Pod: *pod, IsReady: true, ExecutableHash: "test_hash"}
+		needRollout, _, _ := IsPodNeedingRollout(status, &cluster)
+		Expect(needRollout).To(BeFalse())
+	})
+
+	It("requires rollout when a setting of the probes chosen by the user changes", func() {
+		newCluster := cluster.DeepCopy()
+		newCluster.Spec.Probes = &apiv1.ProbesConfiguration{
+			Liveness: &apiv1.Probe{PeriodSeconds: 30},
+		}
+		pod := specs.PodWithExistingStorage(*newCluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsReady: true, ExecutableHash: "test_hash"}
+		needRollout, _, _ := IsPodNeedingRollout(status, newCluster)
+		Expect(needRollout).To(BeFalse())
+
+		newCluster.Spec.Probes.Liveness.PeriodSeconds = 20
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, newCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(Equal("the liveness probe changed"))
+	})
+
+	It("requires rollout when a setting of the probes is restored to its default", func() {
+		newCluster := cluster.DeepCopy()
+		newCluster.Spec.Probes = &apiv1.ProbesConfiguration{
+			Liveness: &apiv1.Probe{PeriodSeconds: 30},
+		}
+		pod := specs.PodWithExistingStorage(*newCluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsReady: true, ExecutableHash: "test_hash"}
+
+		newCluster.Spec.Probes.Liveness.PeriodSeconds = 0
+		needRollout, _, reason := IsPodNeedingRollout(status, newCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(reason).To(Equal("the liveness probe changed"))
+	})
+
+	It("requires rollout when the extension images change", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsReady: true, ExecutableHash: "test_hash"}
+
+		newCluster := cluster.DeepCopy()
+		newCluster.Spec.PostgresConfiguration.ExtensionImages = []apiv1.ExtensionImage{
+			{Name: "pgvector", Image: "ghcr.io/example/pgvector:0.6.0"},
+		}
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, newCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(ContainSubstring("the extension images changed"))
+
+		status.Pod = *specs.PodWithExistingStorage(*newCluster, 1)
+		needRollout, _, _ = IsPodNeedingRollout(status, newCluster)
+		Expect(needRollout).To(BeFalse())
+	})
+})
+
+var _ = Describe("Switchover target for the primary update", func() {
+	podList := &postgres.PostgresqlStatusList{
+		Items: []postgres.PostgresqlStatus{
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}}, IsReady: true},
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}}},
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}}, IsReady: true},
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-4"}}, IsReady: true},
+		},
+	}
+
+	It("chooses the most aligned ready replica", func() {
+		cluster := &apiv1.Cluster{}
+		Expect(getSwitchoverTarget(cluster, podList, "cluster-example-1")).To(Equal("cluster-example-3"))
+	})
+
+	It("skips the fenced and the excluded instances", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.FencedInstanceAnnotation: `["cluster-example-3"]`,
+				},
+			},
+		}
+		Expect(getSwitchoverTarget(cluster, podList, "cluster-example-1")).To(Equal("cluster-example-4"))
+
+		cluster.Spec.FailoverCandidates = &apiv1.FailoverCandidatesConfiguration{
+			ExcludedInstances: []string{"cluster-example-4"},
+		}
+		Expect(getSwitchoverTarget(cluster, podList, "cluster-example-1")).To(BeEmpty())
+	})
+
+	It("prefers the ready replicas to the degraded ones", func() {
+		degradedPodList := &postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}}, IsReady: true, IsPrimary: true},
+				{
+					Pod:          corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+					IsReady:      true,
+					Health:       string(apiv1.InstanceHealthDegraded),
+					HealthReason: postgres.HealthReasonNotStreaming,
+				},
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}}, IsReady: true},
+			},
+		}
+		cluster := &apiv1.Cluster{}
+		Expect(getSwitchoverTarget(cluster, degradedPodList, "cluster-example-1")).To(Equal("cluster-example-3"))
+
+		degradedPodList.Items[2].Health = string(apiv1.InstanceHealthDegraded)
+		Expect(getSwitchoverTarget(cluster, degradedPodList, "cluster-example-1")).To(Equal("cluster-example-2"))
+	})
+
+	It("restarts the degraded replicas first", func() {
+		degradedPodList := &postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}}, IsPrimary: true},
+				{
+					Pod:    corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+					Health: string(apiv1.InstanceHealthDegraded),
+				},
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}}},
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-4"}}},
+			},
+		}
+		Expect(getRolloutOrder(degradedPodList)).To(Equal([]int{1, 3, 2, 0}))
+	})
+})
+
+var _ = Describe("In-place restart of the replicas", func() {
+	status := postgres.PostgresqlStatus{
+		Pod:            corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+		PendingRestart: true,
+	}
+
+	It("recreates the Pods by default", func() {
+		cluster := &apiv1.Cluster{}
+		Expect(isReplicaRestartingInPlace(cluster, status)).To(BeFalse())
+	})
+
+	It("lets the instance manager restart PostgreSQL", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{ReplicaRestartMethod: apiv1.ReplicaRestartMethodRestart},
+		}
+		Expect(isReplicaRestartingInPlace(cluster, status)).To(BeTrue())
+		Expect(isReplicaRestartingInPlace(cluster, postgres.PostgresqlStatus{})).To(BeFalse())
+	})
+
+	It("recreates the Pods when the user requested a restart of the cluster", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{specs.ClusterRestartAnnotationName: "now"},
+			},
+			Spec: apiv1.ClusterSpec{ReplicaRestartMethod: apiv1.ReplicaRestartMethodRestart},
+		}
+		Expect(isReplicaRestartingInPlace(cluster, status)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Primary update with a switchover", func() {
+	It("waits for a replica to be promoted instead of restarting the primary", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		pods := generateFakeClusterPodsWithDefaultClient(cluster, true)
+
+		podList := &postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: pods[0], IsPrimary: true, IsReady: true},
+				{Pod: pods[1]},
+				{Pod: pods[2]},
+			},
+		}
+
+		done, err := clusterReconciler.updatePrimaryPod(ctx, cluster, podList, pods[0], false, "testing")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(done).To(BeTrue())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseWaitingForSwitchoverTarget))
+		Expect(cluster.Status.TargetPrimary).To(BeEmpty())
+
+		var primaryPod corev1.Pod
+		err = k8sClient.Get(ctx, types.NamespacedName{Name: pods[0].Name, Namespace: namespace}, &primaryPod)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(primaryPod.DeletionTimestamp).To(BeNil())
+	})
 })
@@ -0,0 +1,62 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// getInstanceHealth gets the health of an instance, as reported by its
+// instance manager. An instance whose status can't be collected is failed,
+// while the instance managers not reporting the health are considered ready
+func getInstanceHealth(status *postgres.PostgresqlStatus) (apiv1.InstanceHealth, string) {
+	if status.Error != nil {
+		return apiv1.InstanceHealthFailed, status.Error.Error()
+	}
+
+	if status.Health == "" {
+		return apiv1.InstanceHealthReady, ""
+	}
+
+	return apiv1.InstanceHealth(status.Health), status.HealthReason
+}
+
+// isDegraded checks whether an instance is reported as degraded
+func isDegraded(status *postgres.PostgresqlStatus) bool {
+	health, _ := getInstanceHealth(status)
+	return health == apiv1.InstanceHealthDegraded
+}
+
+// isDegradedFailoverCandidate checks whether a standby is reported as degraded
+// for a reason other than not streaming WAL, which is expected when the
+// primary has failed
+func isDegradedFailoverCandidate(status *postgres.PostgresqlStatus) bool {
+	return isDegraded(status) && status.HealthReason != postgres.HealthReasonNotStreaming
+}
+
+// getPrimaryStatus gets the status of the primary
+// instance from the list, or nil if not reported
+func getPrimaryStatus(statuses *postgres.PostgresqlStatusList) *postgres.PostgresqlStatus {
+	for idx := range statuses.Items {
+		if statuses.Items[idx].Error == nil && statuses.Items[idx].IsPrimary {
+			return &statuses.Items[idx]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Instance health", func() {
+	replica := postgres.PostgresqlStatus{
+		Pod:                 corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+		IsWalReceiverActive: true,
+	}
+
+	It("considers failed the instances not reporting their status", func() {
+		status := replica
+		status.Error = errors.New("connection refused")
+		health, reason := getInstanceHealth(&status)
+		Expect(health).To(Equal(apiv1.InstanceHealthFailed))
+		Expect(reason).To(Equal("connection refused"))
+	})
+
+	It("considers ready the instances not reporting their health", func() {
+		health, reason := getInstanceHealth(&replica)
+		Expect(health).To(Equal(apiv1.InstanceHealthReady))
+		Expect(reason).To(BeEmpty())
+	})
+
+	It("uses the health reported by the instance manager", func() {
+		status := replica
+		status.Health = string(apiv1.InstanceHealthDegraded)
+		status.HealthReason = "the replay lag is 268435456 bytes"
+		health, reason := getInstanceHealth(&status)
+		Expect(health).To(Equal(apiv1.InstanceHealthDegraded))
+		Expect(reason).To(Equal("the replay lag is 268435456 bytes"))
+		Expect(isDegraded(&status)).To(BeTrue())
+		Expect(isDegradedFailoverCandidate(&status)).To(BeTrue())
+	})
+
+	It("doesn't discard the failover candidates which are not streaming", func() {
+		status := replica
+		status.IsWalReceiverActive = false
+		status.Health = string(apiv1.InstanceHealthDegraded)
+		status.HealthReason = postgres.HealthReasonNotStreaming
+		Expect(isDegraded(&status)).To(BeTrue())
+		Expect(isDegradedFailoverCandidate(&status)).To(BeFalse())
+	})
+})
@@ -20,7 +20,10 @@ import (
 	"context"
 	"reflect"
 
+	"k8s.io/apimachinery/pkg/labels"
+
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs/pgbouncer"
 )
 
 // updatePoolerStatus sets the status of the pooler and writes it inside kubernetes
@@ -65,6 +68,11 @@ func (r *PoolerReconciler) updatePoolerStatus(
 		updatedStatus.Instances = resources.Deployment.Status.Replicas
 	}
 
+	// The selector is needed by the scale subresource
+	updatedStatus.Selector = labels.SelectorFromSet(map[string]string{
+		pgbouncer.PgbouncerNameLabel: pooler.Name,
+	}).String()
+
 	// then update the status if anything changed
 	if !reflect.DeepEqual(pooler.Status, updatedStatus) {
 		pooler.Status = *updatedStatus
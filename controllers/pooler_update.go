@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"reflect"
 
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -31,6 +32,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs/pgbouncer"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
 )
 
@@ -52,6 +54,14 @@ func (r *PoolerReconciler) updateOwnedObjects(
 		return err
 	}
 
+	if err := r.updatePodDisruptionBudget(ctx, pooler, resources); err != nil {
+		return err
+	}
+
+	if err := r.updatePodMonitor(ctx, pooler); err != nil {
+		return err
+	}
+
 	return r.updateService(ctx, pooler, resources)
 }
 
@@ -145,6 +155,122 @@ func (r *PoolerReconciler) updateService(
 	return nil
 }
 
+// updatePodDisruptionBudget creates, updates or deletes the pod
+// disruption budget of the pgbouncer pods as needed
+func (r *PoolerReconciler) updatePodDisruptionBudget(
+	ctx context.Context,
+	pooler *apiv1.Pooler,
+	resources *poolerManagedResources,
+) error {
+	contextLog := log.FromContext(ctx)
+
+	pdb := pgbouncer.PodDisruptionBudget(pooler)
+	current := resources.PodDisruptionBudget
+	if current != nil {
+		if owner, isOwned := isOwnedByPooler(current); !isOwned || owner != pooler.Name {
+			return fmt.Errorf("the pod disruption budget %s is not owned by the pooler", current.Name)
+		}
+	}
+
+	switch {
+	case pdb == nil && current == nil:
+		return nil
+
+	case pdb == nil:
+		contextLog.Info("Deleting pod disruption budget")
+		if err := r.Delete(ctx, current); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		resources.PodDisruptionBudget = nil
+		return nil
+
+	case current == nil:
+		if err := ctrl.SetControllerReference(pooler, pdb, r.Scheme); err != nil {
+			return err
+		}
+
+		contextLog.Info("Creating pod disruption budget")
+		if err := r.Create(ctx, pdb); err != nil && !apierrs.IsAlreadyExists(err) {
+			return err
+		}
+		resources.PodDisruptionBudget = pdb
+		return nil
+
+	case !reflect.DeepEqual(pdb.Spec, current.Spec):
+		updatedPDB := current.DeepCopy()
+		updatedPDB.Spec = pdb.Spec
+
+		contextLog.Info("Updating pod disruption budget")
+		if err := r.Patch(ctx, updatedPDB, client.MergeFrom(current)); err != nil {
+			return err
+		}
+		resources.PodDisruptionBudget = updatedPDB
+	}
+
+	return nil
+}
+
+// updatePodMonitor creates, updates or deletes the PodMonitor
+// scraping the metrics of the pgbouncer pods as needed
+func (r *PoolerReconciler) updatePodMonitor(ctx context.Context, pooler *apiv1.Pooler) error {
+	contextLog := log.FromContext(ctx)
+
+	// Checking for the PodMonitor resource in the cluster
+	havePodMonitor, err := utils.PodMonitorExist(r.DiscoveryClient)
+	if err != nil || !havePodMonitor {
+		contextLog.Debug("Kind PodMonitor not detected", "err", err)
+		return err
+	}
+
+	current := &monitoringv1.PodMonitor{}
+	if err := r.Get(ctx, client.ObjectKey{Name: pooler.Name, Namespace: pooler.Namespace}, current); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while getting the podmonitor: %w", err)
+		}
+		current = nil
+	}
+
+	if current != nil {
+		if owner, isOwned := isOwnedByPooler(current); !isOwned || owner != pooler.Name {
+			return fmt.Errorf("the podmonitor %s is not owned by the pooler", current.Name)
+		}
+	}
+
+	switch {
+	case !pooler.IsPodMonitorEnabled() && current == nil:
+		return nil
+
+	case !pooler.IsPodMonitorEnabled():
+		contextLog.Info("Deleting PodMonitor")
+		if err := r.Delete(ctx, current); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		return nil
+
+	case current == nil:
+		podMonitor := pgbouncer.PodMonitor(pooler)
+		if err := ctrl.SetControllerReference(pooler, podMonitor, r.Scheme); err != nil {
+			return err
+		}
+
+		contextLog.Info("Creating PodMonitor")
+		if err := r.Create(ctx, podMonitor); err != nil && !apierrs.IsAlreadyExists(err) {
+			return err
+		}
+		return nil
+
+	default:
+		podMonitor := current.DeepCopy()
+		podMonitor.Spec = pgbouncer.PodMonitor(pooler).Spec
+		if reflect.DeepEqual(podMonitor.Spec, current.Spec) {
+			return nil
+		}
+
+		contextLog.Debug("Patching PodMonitor")
+		return r.Patch(ctx, podMonitor, client.MergeFrom(current))
+	}
+}
+
 // updateRBAC update or create the pgbouncer RBAC
 func (r *PoolerReconciler) updateRBAC(
 	ctx context.Context,
@@ -242,21 +368,21 @@ func (r *PoolerReconciler) ensureServiceAccountPullSecret(
 	pooler *apiv1.Pooler,
 ) (pullSecretName string, err error) {
 	contextLog := log.FromContext(ctx)
-	if configuration.Current.OperatorNamespace == "" {
+	if configuration.Current().OperatorNamespace == "" {
 		// We are not getting started via a k8s deployment. Perhaps we are running in our development environment
 		return "", nil
 	}
 
 	// no pull secret name, there is nothing to do
-	if configuration.Current.OperatorPullSecretName == "" {
+	if configuration.Current().OperatorPullSecretName == "" {
 		return "", nil
 	}
 
 	// Let's find the operator secret
 	var operatorSecret corev1.Secret
 	if err = r.Get(ctx, client.ObjectKey{
-		Name:      configuration.Current.OperatorPullSecretName,
-		Namespace: configuration.Current.OperatorNamespace,
+		Name:      configuration.Current().OperatorPullSecretName,
+		Namespace: configuration.Current().OperatorNamespace,
 	}, &operatorSecret); err != nil {
 		if apierrs.IsNotFound(err) {
 			// There is no secret like that, probably because we are running in our development environment
@@ -21,11 +21,13 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs/pgbouncer"
 
@@ -35,11 +37,11 @@ import (
 
 var _ = Describe("unit test of pooler_update reconciliation logic", func() {
 	AfterEach(func() {
-		configuration.Current = configuration.NewConfiguration()
+		configuration.SetCurrent(configuration.NewConfiguration())
 	})
 
 	BeforeEach(func() {
-		configuration.Current = configuration.NewConfiguration()
+		configuration.SetCurrent(configuration.NewConfiguration())
 	})
 
 	It("it should test the deployment update logic", func() {
@@ -99,6 +101,39 @@ var _ = Describe("unit test of pooler_update reconciliation logic", func() {
 		})
 	})
 
+	It("should test the pod disruption budget update logic", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		pooler := newFakePooler(cluster)
+		res := &poolerManagedResources{Cluster: cluster}
+
+		By("not creating a pod disruption budget when not required", func() {
+			Expect(poolerReconciler.updatePodDisruptionBudget(ctx, pooler, res)).To(Succeed())
+			Expect(res.PodDisruptionBudget).To(BeNil())
+		})
+
+		By("creating the pod disruption budget when required", func() {
+			pooler.Spec.PodDisruptionBudget = &apiv1.PoolerPodDisruptionBudget{}
+			Expect(poolerReconciler.updatePodDisruptionBudget(ctx, pooler, res)).To(Succeed())
+
+			pdb := &policyv1beta1.PodDisruptionBudget{}
+			Expect(k8sClient.Get(
+				ctx,
+				types.NamespacedName{Name: pooler.Name, Namespace: pooler.Namespace},
+				pdb,
+			)).To(Succeed())
+			Expect(pdb.Spec.MaxUnavailable.IntValue()).To(Equal(1))
+			res.PodDisruptionBudget = pdb
+		})
+
+		By("deleting the pod disruption budget when not required anymore", func() {
+			pooler.Spec.PodDisruptionBudget = nil
+			Expect(poolerReconciler.updatePodDisruptionBudget(ctx, pooler, res)).To(Succeed())
+			Expect(res.PodDisruptionBudget).To(BeNil())
+		})
+	})
+
 	It("should test the ServiceAccount and RBAC update logic", func() {
 		ctx := context.Background()
 		namespace := newFakeNamespace()
@@ -145,13 +180,15 @@ var _ = Describe("unit test of pooler_update reconciliation logic", func() {
 		By("creating the requirement for the imagePullSecret", func() {
 			namespace := newFakeNamespace()
 
-			configuration.Current.OperatorPullSecretName = "test-secret-pull"
-			configuration.Current.OperatorNamespace = namespace
+			config := configuration.NewConfiguration()
+			config.OperatorPullSecretName = "test-secret-pull"
+			config.OperatorNamespace = namespace
+			configuration.SetCurrent(config)
 
 			pullSecret := &corev1.Secret{
 				ObjectMeta: metav1.ObjectMeta{
-					Name:      configuration.Current.OperatorPullSecretName,
-					Namespace: configuration.Current.OperatorNamespace,
+					Name:      configuration.Current().OperatorPullSecretName,
+					Namespace: configuration.Current().OperatorNamespace,
 				},
 				Data: map[string][]byte{
 					corev1.TLSCertKey:       []byte("test-cert"),
@@ -20,12 +20,16 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/notifications"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
@@ -36,6 +40,19 @@ import (
 // because there is a WAL receiver running in our Pod list
 var ErrWalReceiversRunning = fmt.Errorf("wal receivers are still running")
 
+// ErrWaitingOnFailOverDelay is raised when the failover is postponed because
+// the primary has been detected unhealthy for less than the failover delay
+var ErrWaitingOnFailOverDelay = fmt.Errorf("current primary isn't healthy, waiting for the failover delay")
+
+// ErrNoFailoverCandidate is raised when a new primary server can't be elected
+// because no standby can be promoted, i.e. every one of them has been
+// excluded from the failover candidates
+var ErrNoFailoverCandidate = fmt.Errorf("no instance can be promoted")
+
+// ErrWaitingOnFailoverApproval is raised when a new primary server can't be elected
+// because the cluster has a manual failover policy and the user hasn't approved it yet
+var ErrWaitingOnFailoverApproval = fmt.Errorf("current primary isn't healthy, waiting for the failover approval")
+
 // updateTargetPrimaryFromPods sets the name of the target primary from the Pods status if needed
 // this function will returns the name of the new primary selected for promotion
 func (r *ClusterReconciler) updateTargetPrimaryFromPods(
@@ -62,6 +79,9 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPods(
 			contextLogger.Error(err, "while checking if current primary is on an unschedulable node")
 			// in case of error it's better to proceed with the normal target primary reconciliation
 		} else if isPrimaryOnUnschedulableNode {
+			if isSplitBrainSuspected(cluster) {
+				return "", ErrSplitBrainSuspected
+			}
 			contextLogger.Info("Primary is running on an unschedulable node, will try switching over",
 				"node", primary.Node, "primary", primary.Pod.Name)
 			return r.setPrimaryOnSchedulableNode(ctx, cluster, status, &primary)
@@ -96,7 +116,25 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 	// If the first pod in the sorted list is already the targetPrimary,
 	// we have nothing to do here.
 	if cluster.Status.TargetPrimary == status.Items[0].Pod.Name {
-		return "", nil
+		// The primary is healthy again, or it has been replaced
+		// by a new one: we need to reset the failing timestamp
+		if cluster.Status.CurrentPrimaryFailingSinceTimestamp != "" {
+			contextLogger.Info("Current primary is healthy again, resetting the failing timestamp",
+				"failingSince", cluster.Status.CurrentPrimaryFailingSinceTimestamp)
+			cluster.Status.CurrentPrimaryFailingSinceTimestamp = ""
+			if err := r.Status().Update(ctx, cluster); err != nil {
+				return "", err
+			}
+		}
+
+		// A failover approval which has not been used is not valid anymore
+		return "", r.removeFailoverApproval(ctx, cluster)
+	}
+
+	// A failover would promote yet another instance while the existing ones
+	// don't agree on the primary: wait for a human to resolve the situation
+	if isSplitBrainSuspected(cluster) {
+		return "", ErrSplitBrainSuspected
 	}
 
 	// The current primary is not correctly working, and we need to elect a new one
@@ -105,11 +143,21 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 	// (if is still alive) to shut down by setting the apiv1.PendingFailoverMarker as
 	// target primary.
 	if cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary {
+		if err := r.enforceFailoverDelay(ctx, cluster); err != nil {
+			return "", err
+		}
+
+		if err := r.enforceFailoverApproval(ctx, cluster, status, resources); err != nil {
+			return "", err
+		}
+
 		contextLogger.Info("Current primary isn't healthy, initiating a failover")
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before initiating the failover", "instances", resources.instances)
 		r.Recorder.Eventf(cluster, "Normal", "FailingOver",
 			"Current primary isn't healthy, initiating a failover from %v", cluster.Status.CurrentPrimary)
+		notifications.Notify(ctx, notifications.EventFailover, cluster.Namespace, cluster.Name,
+			fmt.Sprintf("Current primary isn't healthy, initiating a failover from %v", cluster.Status.CurrentPrimary))
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailOver,
 			fmt.Sprintf("Initiating a failover from %v", cluster.Status.CurrentPrimary)); err != nil {
 			return "", err
@@ -126,35 +174,404 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 		return "", ErrWalReceiversRunning
 	}
 
+	candidate, reason, err := r.selectFailoverCandidate(ctx, cluster, status, resources)
+	if err != nil {
+		return "", err
+	}
+	if candidate == nil {
+		contextLogger.Info("No instance can be promoted, as no standby is a failover candidate",
+			"failoverCandidates", cluster.Spec.FailoverCandidates)
+		status.LogStatus(ctx)
+		return "", ErrNoFailoverCandidate
+	}
+
+	// The chosen candidate may not be the first instance of the list, i.e.
+	// when it is preferred because of its topology: in that case we just
+	// need to wait for it to be promoted
+	if cluster.Status.TargetPrimary == candidate.Pod.Name {
+		return "", nil
+	}
+
 	// This may be tha last step of a failover if target primary is set to apiv1.PendingFailoverMarker
 	// or change the target primary if the current one is not valid anymore.
 	if cluster.Status.TargetPrimary == apiv1.PendingFailoverMarker {
-		contextLogger.Info("Failing over", "newPrimary", status.Items[0].Pod.Name)
+		contextLogger.Info("Failing over", "newPrimary", candidate.Pod.Name, "reason", reason)
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before failover", "instances", resources.instances)
 		r.Recorder.Eventf(cluster, "Normal", "FailoverTarget",
-			"Failing over from %v to %v",
-			cluster.Status.CurrentPrimary, status.Items[0].Pod.Name)
+			"Failing over from %v to %v: %v",
+			cluster.Status.CurrentPrimary, candidate.Pod.Name, reason)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailOver,
-			fmt.Sprintf("Failing over from %v to %v", cluster.Status.CurrentPrimary, status.Items[0].Pod.Name)); err != nil {
+			fmt.Sprintf("Failing over from %v to %v", cluster.Status.CurrentPrimary, candidate.Pod.Name)); err != nil {
 			return "", err
 		}
 	} else {
 		contextLogger.Info("Target primary isn't healthy, switching target",
-			"newPrimary", status.Items[0].Pod.Name)
+			"newPrimary", candidate.Pod.Name, "reason", reason)
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before switching target", "instances", resources.instances)
 		r.Recorder.Eventf(cluster, "Normal", "FailingOver",
-			"Target primary isn't healthy, switching target from %v to %v",
-			cluster.Status.TargetPrimary, status.Items[0].Pod.Name)
+			"Target primary isn't healthy, switching target from %v to %v: %v",
+			cluster.Status.TargetPrimary, candidate.Pod.Name, reason)
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseSwitchover,
-			fmt.Sprintf("Switching over to %v", status.Items[0].Pod.Name)); err != nil {
+			fmt.Sprintf("Switching over to %v", candidate.Pod.Name)); err != nil {
 			return "", err
 		}
 	}
 
-	// Set the first pod in the sorted list as the new targetPrimary
-	return status.Items[0].Pod.Name, r.setPrimaryInstance(ctx, cluster, status.Items[0].Pod.Name)
+	// Set the selected candidate as the new targetPrimary
+	if err := r.setPrimaryInstance(ctx, cluster, candidate.Pod.Name); err != nil {
+		return "", err
+	}
+
+	// The approval has been used, and the next failover will need a new one
+	return candidate.Pod.Name, r.removeFailoverApproval(ctx, cluster)
+}
+
+// enforceFailoverApproval returns ErrWaitingOnFailoverApproval when the cluster
+// has a manual failover policy and the user hasn't approved the failover yet,
+// reporting the recommended candidate
+func (r *ClusterReconciler) enforceFailoverApproval(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+	resources *managedResources,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	if cluster.GetFailoverPolicy() != apiv1.FailoverPolicyManual {
+		return nil
+	}
+
+	approvedInstance, approved := getPendingFailoverApproval(cluster)
+	if !approved {
+		// An approval given before the cluster started waiting for it is
+		// stale, as the user didn't know about this failover
+		if err := r.removeFailoverApproval(ctx, cluster); err != nil {
+			return err
+		}
+	}
+	if approved && (approvedInstance == "" || getApprovedFailoverCandidate(cluster, status) != nil) {
+		contextLogger.Info("Failover approved by the user", "approvedInstance", approvedInstance)
+		return nil
+	}
+
+	recommendation := "no instance can be promoted"
+	candidate, reason, err := r.selectFailoverCandidate(ctx, cluster, status, resources)
+	if err != nil {
+		return err
+	}
+	if candidate != nil {
+		recommendation = fmt.Sprintf("the recommended candidate is %v, %v", candidate.Pod.Name, reason)
+	}
+
+	if approved {
+		contextLogger.Info("The approved instance cannot be promoted, waiting for a new approval",
+			"approvedInstance", approvedInstance)
+		r.Recorder.Eventf(cluster, "Warning", "InvalidFailoverApproval",
+			"The approved instance %v cannot be promoted, %v", approvedInstance, recommendation)
+	}
+
+	if cluster.Status.Phase != apiv1.PhaseWaitingForFailoverApproval {
+		contextLogger.Info("Current primary isn't healthy, waiting for the user to approve the failover",
+			"recommendation", recommendation)
+		status.LogStatus(ctx)
+		r.Recorder.Eventf(cluster, "Warning", "FailoverApprovalRequired",
+			"Current primary %v isn't healthy, waiting for the failover to be approved: %v",
+			cluster.Status.CurrentPrimary, recommendation)
+	}
+	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForFailoverApproval,
+		fmt.Sprintf("Current primary isn't healthy, %v", recommendation)); err != nil {
+		return err
+	}
+
+	return ErrWaitingOnFailoverApproval
+}
+
+// getPendingFailoverApproval gets the instance approved by the user while
+// the cluster is waiting for the failover approval. The approvals found
+// at any other time are stale and are ignored
+func getPendingFailoverApproval(cluster *apiv1.Cluster) (string, bool) {
+	if cluster.Status.Phase != apiv1.PhaseWaitingForFailoverApproval {
+		return "", false
+	}
+
+	approvedInstance, approved := cluster.Annotations[utils.FailoverApprovalAnnotationName]
+	return approvedInstance, approved
+}
+
+// removeFailoverApproval removes the annotation approving a failover
+// from the cluster, if present
+func (r *ClusterReconciler) removeFailoverApproval(ctx context.Context, cluster *apiv1.Cluster) error {
+	if _, approved := cluster.Annotations[utils.FailoverApprovalAnnotationName]; !approved {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	delete(cluster.Annotations, utils.FailoverApprovalAnnotationName)
+	return r.Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// getApprovedFailoverCandidate gets the status of the instance the user approved
+// to be promoted when the cluster has a manual failover policy. It returns nil
+// if no instance has been explicitly approved, or if the approved one is not
+// a standby which can be promoted
+func getApprovedFailoverCandidate(
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+) *postgres.PostgresqlStatus {
+	if cluster.GetFailoverPolicy() != apiv1.FailoverPolicyManual {
+		return nil
+	}
+
+	approvedInstance := cluster.Annotations[utils.FailoverApprovalAnnotationName]
+	if approvedInstance == "" {
+		return nil
+	}
+
+	for idx := range status.Items {
+		item := &status.Items[idx]
+		if item.Pod.Name == approvedInstance && isPromotableStandby(item, cluster.Status.CurrentPrimary) {
+			return item
+		}
+	}
+
+	return nil
+}
+
+// selectFailoverCandidate chooses the instance to be promoted during a failover,
+// applying the constraints defined in the cluster. It returns the reason
+// of the choice, to be reported to the user
+func (r *ClusterReconciler) selectFailoverCandidate(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+	resources *managedResources,
+) (*postgres.PostgresqlStatus, string, error) {
+	if candidate := getApprovedFailoverCandidate(cluster, status); candidate != nil {
+		return candidate, "approved by the user", nil
+	}
+
+	if cluster.Spec.FailoverCandidates == nil {
+		candidate, reason := chooseFailoverCandidate(status, cluster.Status.CurrentPrimary, nil, "", nil)
+		return candidate, reason, nil
+	}
+
+	topologyKey := cluster.Spec.FailoverCandidates.PreferSameTopologyKey
+	preferredNodes, err := r.getNodesInPrimaryTopology(ctx, cluster, status, resources, topologyKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	candidate, reason := chooseFailoverCandidate(
+		status,
+		cluster.Status.CurrentPrimary,
+		cluster.Spec.FailoverCandidates.ExcludedInstances,
+		topologyKey,
+		preferredNodes,
+	)
+	return candidate, reason, nil
+}
+
+// getNodesInPrimaryTopology gets the names of the nodes, running the standbys
+// which can be promoted, sharing the value of the passed label with the node
+// of the current primary. It returns nil if the topology of the primary is unknown
+func (r *ClusterReconciler) getNodesInPrimaryTopology(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+	resources *managedResources,
+	topologyKey string,
+) (map[string]bool, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if topologyKey == "" {
+		return nil, nil
+	}
+
+	var primaryNodeName string
+	for idx := range resources.instances.Items {
+		if resources.instances.Items[idx].Name == cluster.Status.CurrentPrimary {
+			primaryNodeName = resources.instances.Items[idx].Spec.NodeName
+			break
+		}
+	}
+	if primaryNodeName == "" {
+		contextLogger.Info("Cannot detect the node of the former primary, "+
+			"ignoring its topology while choosing the failover candidate",
+			"currentPrimary", cluster.Status.CurrentPrimary)
+		return nil, nil
+	}
+
+	var primaryNode corev1.Node
+	if err := r.Get(ctx, client.ObjectKey{Name: primaryNodeName}, &primaryNode); err != nil {
+		if apierrs.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	primaryTopology, ok := primaryNode.Labels[topologyKey]
+	if !ok {
+		return nil, nil
+	}
+
+	preferredNodes := make(map[string]bool)
+	for idx := range status.Items {
+		item := &status.Items[idx]
+		if item.Node == "" || preferredNodes[item.Node] || !isPromotableStandby(item, cluster.Status.CurrentPrimary) {
+			continue
+		}
+
+		var node corev1.Node
+		if err := r.Get(ctx, client.ObjectKey{Name: item.Node}, &node); err != nil {
+			if apierrs.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if node.Labels[topologyKey] == primaryTopology {
+			preferredNodes[item.Node] = true
+		}
+	}
+
+	return preferredNodes, nil
+}
+
+// isPromotableStandby checks whether an instance is a standby, other than the
+// current primary, whose status has been collected without errors. The Ready
+// condition of the Pod is not taken into account, as the streaming readiness
+// probe fails on every replica once the primary is gone
+func isPromotableStandby(item *postgres.PostgresqlStatus, currentPrimary string) bool {
+	return item.Error == nil && item.Pod.Name != currentPrimary
+}
+
+// chooseFailoverCandidate chooses the instance to be promoted among the
+// standbys in the passed status list, which is sorted by replication status with
+// the most advanced instance first. The excluded instances are never chosen, and
+// the ones running on the preferred nodes are chosen when available.
+// It returns nil if no instance can be promoted
+func chooseFailoverCandidate(
+	status postgres.PostgresqlStatusList,
+	currentPrimary string,
+	excludedInstances []string,
+	topologyKey string,
+	preferredNodes map[string]bool,
+) (*postgres.PostgresqlStatus, string) {
+	var candidates []*postgres.PostgresqlStatus
+	excluded := false
+	for idx := range status.Items {
+		item := &status.Items[idx]
+		if !isPromotableStandby(item, currentPrimary) {
+			continue
+		}
+		if utils.StringInSlice(excludedInstances, item.Pod.Name) {
+			excluded = true
+			continue
+		}
+		candidates = append(candidates, item)
+	}
+	if len(candidates) == 0 {
+		return nil, ""
+	}
+	candidates = preferReadyCandidates(candidates)
+
+	candidate := candidates[0]
+	reason := "most advanced instance"
+	for _, item := range candidates {
+		if preferredNodes[item.Node] {
+			candidate = item
+			reason = fmt.Sprintf("most advanced instance sharing the %v of the former primary", topologyKey)
+			break
+		}
+	}
+
+	reason = fmt.Sprintf("%v (receivedLsn: %v, replayLsn: %v)", reason, candidate.ReceivedLsn, candidate.ReplayLsn)
+	if excluded {
+		reason = fmt.Sprintf("%v, excluding %v", reason, strings.Join(excludedInstances, ", "))
+	}
+
+	return candidate, reason
+}
+
+// preferReadyCandidates reorders the failover candidates, sorted by received
+// LSN, so that the ones reported as degraded come after the ready ones with
+// the same received LSN. The health never prevails over the received LSN,
+// as promoting a less advanced instance would lose data
+func preferReadyCandidates(candidates []*postgres.PostgresqlStatus) []*postgres.PostgresqlStatus {
+	result := make([]*postgres.PostgresqlStatus, 0, len(candidates))
+	for start := 0; start < len(candidates); {
+		end := start
+		for end < len(candidates) && candidates[end].ReceivedLsn == candidates[start].ReceivedLsn {
+			end++
+		}
+
+		var degraded []*postgres.PostgresqlStatus
+		for _, item := range candidates[start:end] {
+			if isDegradedFailoverCandidate(item) {
+				degraded = append(degraded, item)
+			} else {
+				result = append(result, item)
+			}
+		}
+		result = append(result, degraded...)
+		start = end
+	}
+
+	return result
+}
+
+// enforceFailoverDelay records when the current primary has been detected
+// unhealthy for the first time, and returns ErrWaitingOnFailOverDelay
+// until the failover delay has passed since then
+func (r *ClusterReconciler) enforceFailoverDelay(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	// Without a delay there's no need to record when
+	// the current primary started failing
+	if cluster.Spec.FailoverDelay <= 0 {
+		return nil
+	}
+
+	if cluster.Status.CurrentPrimaryFailingSinceTimestamp == "" {
+		cluster.Status.CurrentPrimaryFailingSinceTimestamp = utils.GetCurrentTimestamp()
+		if err := r.Status().Update(ctx, cluster); err != nil {
+			return err
+		}
+	}
+
+	remaining, err := getFailoverDelayRemaining(cluster, utils.GetCurrentTimestamp())
+	if err != nil {
+		return err
+	}
+	if remaining > 0 {
+		contextLogger.Info("Current primary isn't healthy, waiting for the failover delay",
+			"failingSince", cluster.Status.CurrentPrimaryFailingSinceTimestamp,
+			"failoverDelay", cluster.Spec.FailoverDelay,
+			"remaining", remaining)
+		return ErrWaitingOnFailOverDelay
+	}
+
+	return nil
+}
+
+// getFailoverDelayRemaining returns how long we still need to wait, at the
+// passed timestamp, before triggering a failover of the current primary
+func getFailoverDelayRemaining(cluster *apiv1.Cluster, now string) (time.Duration, error) {
+	if cluster.Spec.FailoverDelay <= 0 || cluster.Status.CurrentPrimaryFailingSinceTimestamp == "" {
+		return 0, nil
+	}
+
+	elapsed, err := utils.DifferenceBetweenTimestamps(now, cluster.Status.CurrentPrimaryFailingSinceTimestamp)
+	if err != nil {
+		return 0, err
+	}
+
+	delay := time.Duration(cluster.Spec.FailoverDelay) * time.Second
+	if elapsed >= delay {
+		return 0, nil
+	}
+
+	return delay - elapsed, nil
 }
 
 // isNodeUnschedulable checks whether a node is set to unschedulable
@@ -231,7 +648,7 @@ func (r *ClusterReconciler) setPrimaryOnSchedulableNode(
 				primaryPod.Node)); err != nil {
 			return "", err
 		}
-		return candidate.Pod.Name, r.setPrimaryInstance(ctx, cluster, candidate.Pod.Name)
+		return candidate.Pod.Name, r.setSwitchoverTarget(ctx, cluster, candidate.Pod.Name)
 	}
 
 	// if we are here this means no new primary has been chosen
@@ -345,7 +762,7 @@ func (r *ClusterReconciler) updateClusterAnnotationsOnPods(
 		// if all the required annotations are already set and with the correct value,
 		// we proceed to the next item
 		if utils.IsAnnotationSubset(pod.Annotations, cluster.Annotations, cluster.GetFixedInheritedAnnotations(),
-			configuration.Current) &&
+			configuration.Current()) &&
 			utils.IsAnnotationAppArmorPresentInObject(&pod.ObjectMeta, cluster.Annotations) {
 			contextLogger.Debug(
 				"Skipping cluster annotations reconciliation, because they are already present on pod",
@@ -359,7 +776,7 @@ func (r *ClusterReconciler) updateClusterAnnotationsOnPods(
 		// otherwise, we add the modified/new annotations to the pod
 		patch := client.MergeFrom(pod.DeepCopy())
 		utils.InheritAnnotations(&pod.ObjectMeta, cluster.Annotations,
-			cluster.GetFixedInheritedAnnotations(), configuration.Current)
+			cluster.GetFixedInheritedAnnotations(), configuration.Current())
 		if utils.IsAnnotationAppArmorPresent(cluster.Annotations) {
 			utils.AnnotateAppArmor(&pod.ObjectMeta, cluster.Annotations)
 		}
@@ -388,7 +805,7 @@ func (r *ClusterReconciler) updateClusterLabelsOnPods(
 		// if all the required labels are already set and with the correct value,
 		// we proceed to the next item
 		if utils.IsLabelSubset(pod.Labels, cluster.Labels, cluster.GetFixedInheritedLabels(),
-			configuration.Current) {
+			configuration.Current()) {
 			contextLogger.Debug(
 				"Skipping cluster label reconciliation, because they are already present on pod",
 				"pod", pod.Name,
@@ -400,7 +817,7 @@ func (r *ClusterReconciler) updateClusterLabelsOnPods(
 
 		// otherwise, we add the modified/new labels to the pod
 		patch := client.MergeFrom(pod.DeepCopy())
-		utils.InheritLabels(&pod.ObjectMeta, cluster.Labels, cluster.GetFixedInheritedLabels(), configuration.Current)
+		utils.InheritLabels(&pod.ObjectMeta, cluster.Labels, cluster.GetFixedInheritedLabels(), configuration.Current())
 
 		contextLogger.Info("Updating cluster labels on pod", "pod", pod.Name)
 		if err := r.Patch(ctx, pod, patch); err != nil {
@@ -428,7 +845,7 @@ func (r *ClusterReconciler) updateClusterAnnotationsOnPVCs(
 		if utils.IsAnnotationSubset(pvc.Annotations,
 			cluster.Annotations,
 			cluster.GetFixedInheritedLabels(),
-			configuration.Current) &&
+			configuration.Current()) &&
 			utils.IsAnnotationAppArmorPresentInObject(&pvc.ObjectMeta, cluster.Annotations) {
 			contextLogger.Debug(
 				"Skipping cluster annotations reconciliation, because they are already present on pvc",
@@ -442,7 +859,7 @@ func (r *ClusterReconciler) updateClusterAnnotationsOnPVCs(
 		// otherwise, we add the modified/new annotations to the pvc
 		patch := client.MergeFrom(pvc.DeepCopy())
 		utils.InheritAnnotations(&pvc.ObjectMeta, cluster.Annotations,
-			cluster.GetFixedInheritedAnnotations(), configuration.Current)
+			cluster.GetFixedInheritedAnnotations(), configuration.Current())
 
 		contextLogger.Info("Updating cluster annotations on pvc", "pvc", pvc.Name)
 		if err := r.Patch(ctx, pvc, patch); err != nil {
@@ -471,7 +888,7 @@ func (r *ClusterReconciler) updateClusterLabelsOnPVCs(
 		if utils.IsLabelSubset(pvc.Labels,
 			cluster.Labels,
 			cluster.GetFixedInheritedAnnotations(),
-			configuration.Current) {
+			configuration.Current()) {
 			contextLogger.Debug(
 				"Skipping cluster label reconciliation, because they are already present on pvc",
 				"pvc", pvc.Name,
@@ -483,7 +900,7 @@ func (r *ClusterReconciler) updateClusterLabelsOnPVCs(
 
 		// otherwise, we add the modified/new labels to the pvc
 		patch := client.MergeFrom(pvc.DeepCopy())
-		utils.InheritLabels(&pvc.ObjectMeta, cluster.Labels, cluster.GetFixedInheritedLabels(), configuration.Current)
+		utils.InheritLabels(&pvc.ObjectMeta, cluster.Labels, cluster.GetFixedInheritedLabels(), configuration.Current())
 
 		contextLogger.Debug("Updating cluster labels on pvc", "pvc", pvc.Name)
 		if err := r.Patch(ctx, pvc, patch); err != nil {
@@ -520,7 +937,17 @@ func (r *ClusterReconciler) updateRoleLabelsOnPods(
 
 		podRole, hasRole := pod.ObjectMeta.Labels[specs.ClusterRoleLabelName]
 
+		// While the primary is being changed, the instance managers of the
+		// former and of the new primary label their own Pods, following the
+		// role reported by PostgreSQL
+		isChangingPrimary := cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary &&
+			(pod.Name == cluster.Status.CurrentPrimary || pod.Name == cluster.Status.TargetPrimary)
+
 		switch {
+		case isChangingPrimary:
+			primaryFound = primaryFound || pod.Name == cluster.Status.CurrentPrimary
+			continue
+
 		case pod.Name == cluster.Status.CurrentPrimary:
 			primaryFound = true
 
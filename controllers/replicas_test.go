@@ -17,11 +17,16 @@ limitations under the License.
 package controllers
 
 import (
+	"fmt"
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -150,3 +155,354 @@ var _ = Describe("Check pods not on primary node", func() {
 		Expect(GetPodsNotOnPrimaryNode(statusList2, &statusList2.Items[0]).Items).ToNot(BeEmpty())
 	})
 })
+
+var _ = Describe("Failover delay", func() {
+	failingSince := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	timestamp := func(t time.Time) string {
+		return t.Format(metav1.RFC3339Micro)
+	}
+
+	It("doesn't wait when the failover delay is not set", func() {
+		cluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{CurrentPrimaryFailingSinceTimestamp: timestamp(failingSince)},
+		}
+		Expect(getFailoverDelayRemaining(cluster, timestamp(failingSince))).To(BeZero())
+	})
+
+	It("waits until the failover delay has passed", func() {
+		cluster := &apiv1.Cluster{
+			Spec:   apiv1.ClusterSpec{FailoverDelay: 30},
+			Status: apiv1.ClusterStatus{CurrentPrimaryFailingSinceTimestamp: timestamp(failingSince)},
+		}
+		Expect(getFailoverDelayRemaining(cluster, timestamp(failingSince.Add(10*time.Second)))).
+			To(Equal(20 * time.Second))
+		Expect(getFailoverDelayRemaining(cluster, timestamp(failingSince.Add(30*time.Second)))).
+			To(BeZero())
+		Expect(getFailoverDelayRemaining(cluster, timestamp(failingSince.Add(time.Minute)))).
+			To(BeZero())
+	})
+
+	It("fails when the failing timestamp cannot be parsed", func() {
+		cluster := &apiv1.Cluster{
+			Spec:   apiv1.ClusterSpec{FailoverDelay: 30},
+			Status: apiv1.ClusterStatus{CurrentPrimaryFailingSinceTimestamp: "not a timestamp"},
+		}
+		_, err := getFailoverDelayRemaining(cluster, timestamp(failingSince))
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Failover candidate selection", func() {
+	status := postgres.PostgresqlStatusList{
+		Items: []postgres.PostgresqlStatus{
+			{
+				Pod:     corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+				Node:    "node-a",
+				IsReady: true,
+			},
+			{
+				Pod:     corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}},
+				Node:    "node-b",
+				IsReady: true,
+			},
+			{
+				Pod:     corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-4"}},
+				Node:    "node-c",
+				IsReady: true,
+			},
+		},
+	}
+
+	It("chooses the most advanced instance by default", func() {
+		candidate, reason := chooseFailoverCandidate(status, "cluster-example-1", nil, "", nil)
+		Expect(candidate.Pod.Name).To(Equal("cluster-example-2"))
+		Expect(reason).To(ContainSubstring("most advanced instance"))
+	})
+
+	It("never chooses an excluded instance", func() {
+		candidate, reason := chooseFailoverCandidate(status, "cluster-example-1", []string{"cluster-example-2"}, "", nil)
+		Expect(candidate.Pod.Name).To(Equal("cluster-example-3"))
+		Expect(reason).To(ContainSubstring("excluding cluster-example-2"))
+	})
+
+	It("returns nothing when every instance is excluded", func() {
+		candidate, _ := chooseFailoverCandidate(status, "cluster-example-1",
+			[]string{"cluster-example-2", "cluster-example-3", "cluster-example-4"}, "", nil)
+		Expect(candidate).To(BeNil())
+	})
+
+	It("prefers the instances in the same topology of the former primary", func() {
+		candidate, reason := chooseFailoverCandidate(status, "cluster-example-1", nil, "topology.kubernetes.io/zone",
+			map[string]bool{"node-c": true})
+		Expect(candidate.Pod.Name).To(Equal("cluster-example-4"))
+		Expect(reason).To(ContainSubstring("sharing the topology.kubernetes.io/zone"))
+	})
+
+	It("prefers only the promotable standbys in the same topology of the former primary", func() {
+		unhealthyStatus := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:     corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+					Node:    "node-a",
+					IsReady: true,
+				},
+				{
+					Pod:     corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}},
+					Node:    "node-b",
+					IsReady: true,
+				},
+				{
+					Pod:     corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-4"}},
+					Node:    "node-c",
+					IsReady: true,
+				},
+				{
+					Pod:     corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					Node:    "node-d",
+					IsReady: true,
+				},
+			},
+		}
+		unhealthyStatus.Items[1].Error = fmt.Errorf("instance manager unreachable")
+		unhealthyStatus.Items[2].Error = fmt.Errorf("instance manager unreachable")
+
+		candidate, reason := chooseFailoverCandidate(unhealthyStatus, "cluster-example-1", nil,
+			"topology.kubernetes.io/zone", map[string]bool{"node-b": true, "node-c": true, "node-d": true})
+		Expect(candidate.Pod.Name).To(Equal("cluster-example-2"))
+		Expect(reason).ToNot(ContainSubstring("sharing the topology.kubernetes.io/zone"))
+	})
+
+	It("never chooses the former primary or an instance whose status can't be collected", func() {
+		unhealthyStatus := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:     corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					Node:    "node-d",
+					IsReady: true,
+				},
+				{
+					Pod:     corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+					Node:    "node-a",
+					IsReady: true,
+				},
+				{
+					Pod:     corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}},
+					Node:    "node-b",
+					IsReady: true,
+				},
+				{
+					Pod:     corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-4"}},
+					Node:    "node-c",
+					IsReady: true,
+				},
+			},
+		}
+		unhealthyStatus.Items[1].Error = fmt.Errorf("instance manager unreachable")
+		unhealthyStatus.Items[2].Error = fmt.Errorf("instance manager unreachable")
+
+		candidate, reason := chooseFailoverCandidate(unhealthyStatus, "cluster-example-1", nil, "", nil)
+		Expect(candidate.Pod.Name).To(Equal("cluster-example-4"))
+		Expect(reason).ToNot(ContainSubstring("excluding"))
+
+		candidate, _ = chooseFailoverCandidate(unhealthyStatus, "cluster-example-1",
+			[]string{"cluster-example-4"}, "", nil)
+		Expect(candidate).To(BeNil())
+	})
+
+	It("chooses a standby when the primary is gone and no replica is streaming", func() {
+		notStreamingStatus := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:          corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+					ReceivedLsn:  "0/30000000",
+					Health:       string(apiv1.InstanceHealthDegraded),
+					HealthReason: postgres.HealthReasonNotStreaming,
+				},
+				{
+					Pod:          corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}},
+					ReceivedLsn:  "0/20000000",
+					Health:       string(apiv1.InstanceHealthDegraded),
+					HealthReason: postgres.HealthReasonNotStreaming,
+				},
+				{
+					Pod:   corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					Error: fmt.Errorf("instance manager unreachable"),
+				},
+			},
+		}
+
+		candidate, _ := chooseFailoverCandidate(notStreamingStatus, "cluster-example-1", nil, "", nil)
+		Expect(candidate).ToNot(BeNil())
+		Expect(candidate.Pod.Name).To(Equal("cluster-example-2"))
+	})
+
+	It("prefers the ready standbys among the equally advanced ones", func() {
+		degradedStatus := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:          corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+					Node:         "node-a",
+					IsReady:      true,
+					ReceivedLsn:  "0/30000000",
+					Health:       string(apiv1.InstanceHealthDegraded),
+					HealthReason: postgres.HealthReasonNotStreaming,
+				},
+				{
+					Pod:         corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}},
+					Node:        "node-b",
+					IsReady:     true,
+					ReceivedLsn: "0/30000000",
+				},
+				{
+					Pod:         corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-4"}},
+					Node:        "node-c",
+					IsReady:     true,
+					ReceivedLsn: "0/30000000",
+				},
+			},
+		}
+
+		By("ignoring the replicas not streaming WAL, as the primary is gone", func() {
+			candidate, _ := chooseFailoverCandidate(degradedStatus, "cluster-example-1", nil, "", nil)
+			Expect(candidate.Pod.Name).To(Equal("cluster-example-2"))
+		})
+
+		By("skipping the replicas degraded for any other reason", func() {
+			degradedStatus.Items[0].HealthReason = "the replay is paused"
+			candidate, _ := chooseFailoverCandidate(degradedStatus, "cluster-example-1", nil, "", nil)
+			Expect(candidate.Pod.Name).To(Equal("cluster-example-3"))
+		})
+
+		By("never choosing a less advanced replica", func() {
+			degradedStatus.Items[1].ReceivedLsn = "0/20000000"
+			degradedStatus.Items[2].ReceivedLsn = "0/20000000"
+			candidate, _ := chooseFailoverCandidate(degradedStatus, "cluster-example-1", nil, "", nil)
+			Expect(candidate.Pod.Name).To(Equal("cluster-example-2"))
+		})
+	})
+
+	It("falls back to the other instances when none is in the same topology", func() {
+		candidate, _ := chooseFailoverCandidate(status, "cluster-example-1", []string{"cluster-example-4"},
+			"topology.kubernetes.io/zone", map[string]bool{"node-c": true})
+		Expect(candidate.Pod.Name).To(Equal("cluster-example-2"))
+	})
+})
+
+var _ = Describe("Failover approval", func() {
+	status := postgres.PostgresqlStatusList{
+		Items: []postgres.PostgresqlStatus{
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}}, IsReady: true},
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}}, IsReady: true},
+			{
+				Pod:   corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-4"}},
+				Error: fmt.Errorf("instance manager unreachable"),
+			},
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}}, IsReady: true},
+		},
+	}
+
+	It("uses the instance approved by the user", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.FailoverApprovalAnnotationName: "cluster-example-3",
+				},
+			},
+			Spec: apiv1.ClusterSpec{FailoverPolicy: apiv1.FailoverPolicyManual},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				Phase:          apiv1.PhaseWaitingForFailoverApproval,
+			},
+		}
+		Expect(getApprovedFailoverCandidate(cluster, status).Pod.Name).To(Equal("cluster-example-3"))
+	})
+
+	It("ignores approvals not naming an instance", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.FailoverApprovalAnnotationName: "",
+				},
+			},
+			Spec: apiv1.ClusterSpec{FailoverPolicy: apiv1.FailoverPolicyManual},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				Phase:          apiv1.PhaseWaitingForFailoverApproval,
+			},
+		}
+		Expect(getApprovedFailoverCandidate(cluster, status)).To(BeNil())
+	})
+
+	It("ignores approvals naming an unknown instance", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.FailoverApprovalAnnotationName: "cluster-example-5",
+				},
+			},
+			Spec: apiv1.ClusterSpec{FailoverPolicy: apiv1.FailoverPolicyManual},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				Phase:          apiv1.PhaseWaitingForFailoverApproval,
+			},
+		}
+		Expect(getApprovedFailoverCandidate(cluster, status)).To(BeNil())
+	})
+
+	It("ignores approvals naming an instance which is not a promotable standby", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.FailoverApprovalAnnotationName: "cluster-example-4",
+				},
+			},
+			Spec: apiv1.ClusterSpec{FailoverPolicy: apiv1.FailoverPolicyManual},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				Phase:          apiv1.PhaseWaitingForFailoverApproval,
+			},
+		}
+		Expect(getApprovedFailoverCandidate(cluster, status)).To(BeNil())
+
+		cluster.Annotations[utils.FailoverApprovalAnnotationName] = "cluster-example-1"
+		Expect(getApprovedFailoverCandidate(cluster, status)).To(BeNil())
+	})
+
+	It("only honors the approvals given while waiting for them", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.FailoverApprovalAnnotationName: "cluster-example-3",
+				},
+			},
+			Spec: apiv1.ClusterSpec{FailoverPolicy: apiv1.FailoverPolicyManual},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				Phase:          apiv1.PhaseWaitingForFailoverApproval,
+			},
+		}
+		approvedInstance, approved := getPendingFailoverApproval(cluster)
+		Expect(approved).To(BeTrue())
+		Expect(approvedInstance).To(Equal("cluster-example-3"))
+
+		cluster.Status.Phase = apiv1.PhaseHealthy
+		_, approved = getPendingFailoverApproval(cluster)
+		Expect(approved).To(BeFalse())
+	})
+
+	It("ignores approvals when the failover is automatic", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.FailoverApprovalAnnotationName: "cluster-example-3",
+				},
+			},
+			Spec: apiv1.ClusterSpec{FailoverPolicy: apiv1.FailoverPolicyAutomatic},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				Phase:          apiv1.PhaseWaitingForFailoverApproval,
+			},
+		}
+		Expect(getApprovedFailoverCandidate(cluster, status)).To(BeNil())
+	})
+})
@@ -23,6 +23,8 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/pprof"
+	"reflect"
+	"sort"
 	"time"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
@@ -83,8 +85,7 @@ const (
 	LeaderElectionID = "db9c8771.cnpg.io"
 
 	// CaSecretName is the name of the secret which is hosting the Operator CA
-	CaSecretName = "cnpg-ca-secret" // #nosec
-
+	CaSecretName = certs.OperatorCASecretName
 )
 
 func init() {
@@ -118,6 +119,22 @@ func RunController(
 		startPprofDebugServer(ctx)
 	}
 
+	// The configuration is loaded before creating the manager,
+	// as it contains the list of the namespaces to be watched
+	restConfig := ctrl.GetConfigOrDie()
+	if err := createKubernetesClient(restConfig); err != nil {
+		setupLog.Error(err, "unable to create Kubernetes clients")
+		return err
+	}
+
+	configData, err := loadConfiguration(ctx, configMapName, secretName)
+	if err != nil {
+		return err
+	}
+
+	setupLog.Info("Operator configuration loaded",
+		"configuration", configuration.Current().Redacted(configData.secretKeys))
+
 	managerOptions := ctrl.Options{
 		Scheme:             scheme,
 		MetricsBindAddress: metricsAddr,
@@ -138,29 +155,29 @@ func RunController(
 		LeaderElectionReleaseOnCancel: true,
 	}
 
-	if configuration.Current.WatchNamespace != "" {
-		namespaces := configuration.Current.WatchedNamespaces()
+	if configuration.Current().WatchNamespace != "" {
+		namespaces := configuration.Current().WatchedNamespaces()
 		managerOptions.NewCache = multicache.DelegatingMultiNamespacedCacheBuilder(
 			namespaces,
-			configuration.Current.OperatorNamespace)
+			configuration.Current().OperatorNamespace)
 		setupLog.Info("Listening for changes", "watchNamespaces", namespaces)
 	} else {
 		setupLog.Info("Listening for changes on all namespaces")
 	}
 
-	if configuration.Current.WebhookCertDir != "" {
+	if configuration.Current().WebhookCertDir != "" {
 		// If OLM will generate certificates for us, let's just
 		// use those
-		managerOptions.CertDir = configuration.Current.WebhookCertDir
+		managerOptions.CertDir = configuration.Current().WebhookCertDir
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), managerOptions)
+	mgr, err := ctrl.NewManager(restConfig, managerOptions)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		return err
 	}
 
-	if configuration.Current.WebhookCertDir != "" {
+	if configuration.Current().WebhookCertDir != "" {
 		// Use certificate names compatible with OLM
 		mgr.GetWebhookServer().CertName = "apiserver.crt"
 		mgr.GetWebhookServer().KeyName = "apiserver.key"
@@ -169,19 +186,6 @@ func RunController(
 		mgr.GetWebhookServer().KeyName = "tls.key"
 	}
 
-	err = createKubernetesClient(mgr.GetConfig())
-	if err != nil {
-		setupLog.Error(err, "unable to create Kubernetes clients")
-		return err
-	}
-
-	err = loadConfiguration(ctx, configMapName, secretName)
-	if err != nil {
-		return err
-	}
-
-	setupLog.Info("Operator configuration loaded", "configuration", configuration.Current)
-
 	discoveryClient, err := utils.GetDiscoveryClient()
 	if err != nil {
 		return err
@@ -231,9 +235,10 @@ func RunController(
 	}
 
 	if err = (&controllers.PoolerReconciler{
-		Client:   mgr.GetClient(),
-		Scheme:   mgr.GetScheme(),
-		Recorder: mgr.GetEventRecorderFor("cloudnative-pg-pooler"),
+		Client:          mgr.GetClient(),
+		DiscoveryClient: discoveryClient,
+		Scheme:          mgr.GetScheme(),
+		Recorder:        mgr.GetEventRecorderFor("cloudnative-pg-pooler"),
 	}).SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Pooler")
 		return err
@@ -259,6 +264,13 @@ func RunController(
 		return err
 	}
 
+	if configMapName != "" || secretName != "" {
+		if err = mgr.Add(newConfigurationReloader(configMapName, secretName, configData)); err != nil {
+			setupLog.Error(err, "unable to add the configuration reloader")
+			return err
+		}
+	}
+
 	// Setup the handler used by the readiness and liveliness probe.
 	//
 	// Unfortunately the readiness of the probe is not sufficient for the operator to be
@@ -283,46 +295,176 @@ func RunController(
 	return nil
 }
 
+// configurationData is the content of the ConfigMap and of the Secret
+// holding the operator configuration
+type configurationData struct {
+	// values contains the options, with the values in the Secret
+	// overwriting the ones in the ConfigMap
+	values map[string]string
+
+	// secretKeys are the options read from the Secret, whose
+	// values are redacted when logging the configuration
+	secretKeys []string
+}
+
 // loadConfiguration reads the configuration from the provided configmap and secret
-func loadConfiguration(ctx context.Context, configMapName string, secretName string) error {
-	configData := make(map[string]string)
+func loadConfiguration(ctx context.Context, configMapName string, secretName string) (*configurationData, error) {
+	configData, err := readConfigurationData(ctx, configMapName, secretName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Finally, read the config if it was provided
+	if len(configData.values) > 0 {
+		loaded := *configuration.Current()
+		loaded.ReadConfigMap(configData.values)
+		configuration.SetCurrent(&loaded)
+	}
+
+	return configData, nil
+}
+
+// readConfigurationData reads the content of the provided configmap
+// and secret, with the values in the secret overwriting the ones in the configmap
+func readConfigurationData(
+	ctx context.Context,
+	configMapName string,
+	secretName string,
+) (*configurationData, error) {
+	configData := &configurationData{values: make(map[string]string)}
 
 	// First read the configmap if provided and store it in configData
 	if configMapName != "" {
-		configMapData, err := readConfigMap(ctx, configuration.Current.OperatorNamespace, configMapName)
+		configMapData, err := readConfigMap(ctx, configuration.Current().OperatorNamespace, configMapName)
 		if err != nil {
 			setupLog.Error(err, "unable to read ConfigMap",
-				"namespace", configuration.Current.OperatorNamespace,
+				"namespace", configuration.Current().OperatorNamespace,
 				"name", configMapName)
-			return err
+			return nil, err
 		}
 		for k, v := range configMapData {
-			configData[k] = v
+			configData.values[k] = v
 		}
 	}
 
 	// Then read the secret if provided and store it in configData, overwriting configmap's values
 	if secretName != "" {
-		secretData, err := readSecret(ctx, configuration.Current.OperatorNamespace, secretName)
+		secretData, err := readSecret(ctx, configuration.Current().OperatorNamespace, secretName)
 		if err != nil {
 			setupLog.Error(err, "unable to read Secret",
-				"namespace", configuration.Current.OperatorNamespace,
+				"namespace", configuration.Current().OperatorNamespace,
 				"name", secretName)
-			return err
+			return nil, err
 		}
 		for k, v := range secretData {
-			configData[k] = v
+			configData.values[k] = v
+			configData.secretKeys = append(configData.secretKeys, k)
 		}
+		sort.Strings(configData.secretKeys)
 	}
 
-	// Finally, read the config if it was provided
-	if len(configData) > 0 {
-		configuration.Current.ReadConfigMap(configData)
+	return configData, nil
+}
+
+// configurationReloadPeriod is the interval between two
+// checks of the operator configuration
+const configurationReloadPeriod = 30 * time.Second
+
+// configurationReloader implements the Runnable interface and periodically
+// reads the operator configuration from the ConfigMap and the Secret,
+// replacing the current configuration when they change
+type configurationReloader struct {
+	configMapName string
+	secretName    string
+
+	// data is the content of the ConfigMap and the Secret
+	// used to build the current configuration
+	data *configurationData
+}
+
+// newConfigurationReloader creates a new configurationReloader, given
+// the data used to load the configuration at startup
+func newConfigurationReloader(
+	configMapName, secretName string,
+	data *configurationData,
+) *configurationReloader {
+	return &configurationReloader{
+		configMapName: configMapName,
+		secretName:    secretName,
+		data:          data,
+	}
+}
+
+// NeedLeaderElection implements the LeaderElectionRunnable interface.
+// Every operator replica serves the webhooks, which use the configuration
+func (r *configurationReloader) NeedLeaderElection() bool {
+	return false
+}
+
+// Start starts checking the operator configuration for changes
+func (r *configurationReloader) Start(ctx context.Context) error {
+	ticker := time.NewTicker(configurationReloadPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := r.reload(ctx); err != nil {
+			setupLog.Info("Cannot reload the operator configuration", "err", err)
+		}
+	}
+}
+
+// reload reads the operator configuration, replacing the current one
+// when the content of the ConfigMap or of the Secret changed
+func (r *configurationReloader) reload(ctx context.Context) error {
+	data, err := readConfigurationData(ctx, r.configMapName, r.secretName)
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(r.data, data) {
+		return nil
 	}
+	r.data = data
 
+	configuration.SetCurrent(newReloadedConfiguration(configuration.Current(), data.values))
+	setupLog.Info("Operator configuration reloaded",
+		"configuration", configuration.Current().Redacted(data.secretKeys))
 	return nil
 }
 
+// newReloadedConfiguration creates the configuration corresponding to
+// the passed data, keeping the options which are only used at startup
+func newReloadedConfiguration(current *configuration.Data, data map[string]string) *configuration.Data {
+	result := &configuration.Data{}
+	result.ReadConfigMap(data)
+
+	if result.WatchNamespace != current.WatchNamespace {
+		setupLog.Warning("The list of the watched namespaces changed, " +
+			"the operator needs to be restarted to apply it")
+	}
+	if result.WebhookCertDir != current.WebhookCertDir {
+		setupLog.Warning("The webhook certificate directory changed, " +
+			"the operator needs to be restarted to apply it")
+	}
+	if !reflect.DeepEqual(result.InheritedLabels, current.InheritedLabels) ||
+		!reflect.DeepEqual(result.InheritedAnnotations, current.InheritedAnnotations) {
+		setupLog.Info("The inherited labels or annotations changed, " +
+			"they will be added to the Pods and PVCs of the existing clusters " +
+			"at their next reconciliation, and never removed")
+	}
+
+	result.WatchNamespace = current.WatchNamespace
+	result.WebhookCertDir = current.WebhookCertDir
+	result.OperatorNamespace = current.OperatorNamespace
+	return result
+}
+
 // readinessProbeHandler is used to implement the readiness probe handler
 func readinessProbeHandler(w http.ResponseWriter, _r *http.Request) {
 	_, _ = fmt.Fprint(w, "OK")
@@ -348,7 +490,7 @@ func createKubernetesClient(config *rest.Config) error {
 // ensurePKI ensures that we have the required PKI infrastructure to make
 // the operator and the clusters working
 func ensurePKI(ctx context.Context, mgrCertDir string) error {
-	if configuration.Current.WebhookCertDir != "" {
+	if configuration.Current().WebhookCertDir != "" {
 		// OLM is generating certificates for us, so we can avoid injecting/creating certificates.
 		return nil
 	}
@@ -360,7 +502,7 @@ func ensurePKI(ctx context.Context, mgrCertDir string) error {
 		CertDir:                            mgrCertDir,
 		SecretName:                         WebhookSecretName,
 		ServiceName:                        WebhookServiceName,
-		OperatorNamespace:                  configuration.Current.OperatorNamespace,
+		OperatorNamespace:                  configuration.Current().OperatorNamespace,
 		MutatingWebhookConfigurationName:   MutatingWebhookConfigurationName,
 		ValidatingWebhookConfigurationName: ValidatingWebhookConfigurationName,
 		CustomResourceDefinitionsName: []string{
@@ -387,7 +529,7 @@ func readConfigMap(ctx context.Context, namespace, name string) (map[string]stri
 		return nil, nil
 	}
 
-	setupLog.Info("Loading configuration from ConfigMap",
+	setupLog.Debug("Loading configuration from ConfigMap",
 		"namespace", namespace,
 		"name", name)
 
@@ -412,7 +554,7 @@ func readSecret(ctx context.Context, namespace, name string) (map[string]string,
 		return nil, nil
 	}
 
-	setupLog.Info("Loading configuration from Secret",
+	setupLog.Debug("Loading configuration from Secret",
 		"namespace", namespace,
 		"name", name)
 
@@ -0,0 +1,105 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package extensions implements the commands preparing the PostgreSQL
+// installation of the instances with the files of the extension images
+package extensions
+
+import (
+	"os"
+	"path"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// NewCmd creates the new cobra command
+func NewCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:           "extensions [cmd]",
+		Short:         "Prepare the PostgreSQL installation with the extension images",
+		SilenceErrors: true,
+	}
+
+	cmd.AddCommand(newSetupCmd())
+	cmd.AddCommand(newInstallCmd())
+
+	return &cmd
+}
+
+// newSetupCmd creates the command copying the PostgreSQL
+// installation of the image into the extensions volume
+func newSetupCmd() *cobra.Command {
+	var shareDir, libDir string
+
+	cmd := cobra.Command{
+		Use:  "setup",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return copyDirectories(map[string]string{
+				shareDir: path.Join(specs.ExtensionsMountPath, specs.ExtensionsShareDirectory),
+				libDir:   path.Join(specs.ExtensionsMountPath, specs.ExtensionsLibDirectory),
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&shareDir, "share-dir", "", "The share directory of the PostgreSQL installation")
+	cmd.Flags().StringVar(&libDir, "lib-dir", "", "The library directory of the PostgreSQL installation")
+	_ = cmd.MarkFlagRequired("share-dir")
+	_ = cmd.MarkFlagRequired("lib-dir")
+
+	return &cmd
+}
+
+// newInstallCmd creates the command copying the files of
+// the extension image into the extensions volume
+func newInstallCmd() *cobra.Command {
+	cmd := cobra.Command{
+		Use:  "install",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return copyDirectories(map[string]string{
+				"/share": path.Join(specs.ExtensionsMountPath, specs.ExtensionsShareDirectory),
+				"/lib":   path.Join(specs.ExtensionsMountPath, specs.ExtensionsLibDirectory),
+			})
+		},
+	}
+
+	return &cmd
+}
+
+// copyDirectories copies the content of the source directories into
+// the corresponding destination ones, skipping the missing sources
+func copyDirectories(directories map[string]string) error {
+	for source, destination := range directories {
+		if _, err := os.Stat(source); os.IsNotExist(err) {
+			log.Info("Skipping missing directory", "source", source)
+			continue
+		}
+
+		log.Info("Copying directory", "source", source, "destination", destination)
+		if err := fileutils.CopyDirectory(source, destination); err != nil {
+			log.Error(err, "Error while copying directory", "source", source, "destination", destination)
+			return err
+		}
+	}
+
+	log.Info("Extensions copy completed")
+	return nil
+}
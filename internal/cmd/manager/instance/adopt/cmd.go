@@ -0,0 +1,71 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package adopt implements the "instance adopt" subcommand of the operator
+package adopt
+
+import (
+	"context"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// NewCmd creates the "adopt" subcommand
+func NewCmd() *cobra.Command {
+	var clusterName string
+	var namespace string
+	var pgData string
+	var pgWal string
+
+	cmd := &cobra.Command{
+		Use:           "adopt [flags]",
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			info := postgres.InitInfo{
+				ClusterName: clusterName,
+				Namespace:   namespace,
+				PgData:      pgData,
+				PgWal:       pgWal,
+			}
+
+			return adoptSubCommand(ctx, info)
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster-name", os.Getenv("CLUSTER_NAME"), "The name of the "+
+		"current cluster in k8s, used to coordinate switchover and failover")
+	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
+		"the cluster and the Pod in k8s")
+	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be adopted")
+	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "the PGWAL to be adopted")
+
+	return cmd
+}
+
+func adoptSubCommand(ctx context.Context, info postgres.InitInfo) error {
+	if err := info.Adopt(ctx); err != nil {
+		log.Error(err, "Error while adopting an existing data directory")
+		return err
+	}
+
+	return nil
+}
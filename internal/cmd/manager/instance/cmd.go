@@ -22,9 +22,12 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/adopt"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/control"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/initdb"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/join"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/pgbasebackup"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/physicalbackup"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/restore"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/run"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/status"
@@ -44,8 +47,12 @@ func NewCmd() *cobra.Command {
 	cmd.AddCommand(join.NewCmd())
 	cmd.AddCommand(run.NewCmd())
 	cmd.AddCommand(status.NewCmd())
+	cmd.AddCommand(control.NewReloadCmd())
+	cmd.AddCommand(control.NewRestartCmd())
 	cmd.AddCommand(pgbasebackup.NewCmd())
 	cmd.AddCommand(restore.NewCmd())
+	cmd.AddCommand(adopt.NewCmd())
+	cmd.AddCommand(physicalbackup.NewCmd())
 
 	return cmd
 }
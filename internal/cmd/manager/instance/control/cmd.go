@@ -0,0 +1,85 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package control implement the "instance reload" and "instance restart"
+// subcommands of the operator, which control the PostgreSQL instance
+// through the secure web server of the instance manager
+package control
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
+	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// NewReloadCmd create the "instance reload" subcommand
+func NewReloadCmd() *cobra.Command {
+	return newCmd("reload", webserver.RequestReload, "configuration reload")
+}
+
+// NewRestartCmd create the "instance restart" subcommand
+func NewRestartCmd() *cobra.Command {
+	return newCmd("restart", webserver.RequestRestart, "instance restart")
+}
+
+func newCmd(
+	use string,
+	requestFunc func(context.Context, *http.Client, string) error,
+	action string,
+) *cobra.Command {
+	cmd := &cobra.Command{
+		Use: use,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return request(cmd.Context(), requestFunc, action)
+		},
+	}
+
+	return cmd
+}
+
+// request sends a request to the secure web server of the local instance
+// manager, authenticating with the certificate of the streaming replication
+// user stored in the Pod
+func request(
+	ctx context.Context,
+	requestFunc func(context.Context, *http.Client, string) error,
+	action string,
+) error {
+	credentials := webserver.ClientCredentials{
+		CertificateFile: postgresSpec.StreamingReplicaCertificateLocation,
+		KeyFile:         postgresSpec.StreamingReplicaKeyLocation,
+		ServerCAFile:    postgresSpec.ServerCACertificateLocation,
+	}
+	client, err := credentials.NewClient()
+	if err != nil {
+		log.Error(err, "Error while loading the credentials to request "+action)
+		return err
+	}
+
+	if err := requestFunc(ctx, client, "localhost"); err != nil {
+		log.Error(err, "Error while requesting "+action)
+		return fmt.Errorf("while requesting %s: %w", action, err)
+	}
+
+	log.Info("Completed " + action)
+	return nil
+}
@@ -43,6 +43,8 @@ func NewCmd() *cobra.Command {
 	var postInitApplicationSQLStr string
 	var postInitTemplateSQLStr string
 	var postInitApplicationSQLRefsFolder string
+	var postInitSQLRefsFolder string
+	var postInitTemplateSQLRefsFolder string
 
 	cmd := &cobra.Command{
 		Use: "init [options]",
@@ -89,6 +91,8 @@ func NewCmd() *cobra.Command {
 				// if the value to postInitApplicationSQLRefsFolder is empty,
 				// bootstrap will do nothing for post init application SQL refs.
 				PostInitApplicationSQLRefsFolder: postInitApplicationSQLRefsFolder,
+				PostInitSQLRefsFolder:            postInitSQLRefsFolder,
+				PostInitTemplateSQLRefsFolder:    postInitTemplateSQLRefsFolder,
 			}
 
 			return initSubCommand(ctx, info)
@@ -119,6 +123,12 @@ func NewCmd() *cobra.Command {
 	cmd.Flags().StringVar(&postInitApplicationSQLRefsFolder, "post-init-application-sql-refs-folder",
 		"", "The folder contains a set of SQL files to be executed in alphabetical order "+
 			"against the application database immediately after its creationd")
+	cmd.Flags().StringVar(&postInitSQLRefsFolder, "post-init-sql-refs-folder",
+		"", "The folder contains a set of SQL files to be executed in alphabetical order "+
+			"against the postgres database to configure the new instance")
+	cmd.Flags().StringVar(&postInitTemplateSQLRefsFolder, "post-init-template-sql-refs-folder",
+		"", "The folder contains a set of SQL files to be executed in alphabetical order "+
+			"against the template1 database to configure the new instance")
 
 	return cmd
 }
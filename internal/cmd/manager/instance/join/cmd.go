@@ -113,7 +113,13 @@ func joinSubCommand(ctx context.Context, instance *postgres.Instance, info postg
 
 	reconciler.RefreshSecrets(ctx, &cluster)
 
-	err = info.Join()
+	info.CloneConfiguration = cluster.Spec.ReplicaClone
+
+	if cluster.Spec.ReplicaCreationMethod == apiv1.ReplicaCreationMethodBackup {
+		err = info.JoinFromBackup(ctx, reconciler.GetClient(), &cluster)
+	} else {
+		err = info.Join(ctx)
+	}
 	if err != nil {
 		log.Error(err, "Error joining node")
 		return err
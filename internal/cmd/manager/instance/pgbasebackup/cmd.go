@@ -115,7 +115,12 @@ func (env *CloneInfo) bootstrapUsingPgbasebackup(ctx context.Context) error {
 			return err
 		}
 	}
-	err = postgres.ClonePgData(connectionString, env.info.PgData, env.info.PgWal)
+	err = postgres.ClonePgData(
+		ctx,
+		connectionString,
+		env.info.PgData,
+		env.info.PgWal,
+		cluster.Spec.Bootstrap.PgBaseBackup.Clone)
 	if err != nil {
 		return err
 	}
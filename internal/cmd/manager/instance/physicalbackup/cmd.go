@@ -0,0 +1,226 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package physicalbackup implement the "instance physical-backup" subcommand
+// of the operator
+package physicalbackup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
+)
+
+// remoteOptions are the options of a physical backup requested
+// to the instance manager of another Pod
+type remoteOptions struct {
+	host        string
+	credentials webserver.ClientCredentials
+	output      string
+	backupName  string
+	namespace   string
+}
+
+// NewCmd create the "instance physical-backup" subcommand
+func NewCmd() *cobra.Command {
+	var options postgres.PhysicalBackupOptions
+	var remote remoteOptions
+
+	cmd := &cobra.Command{
+		Use: "physical-backup",
+		Short: "Stream a hot physical backup of the local instance, or of the one running on " +
+			"the passed host, as a tarball",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if remote.host == "" {
+				return physicalBackupSubCommand(cmd.Context(), options)
+			}
+			return remotePhysicalBackupSubCommand(cmd.Context(), options, remote)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.Label, "label", "",
+		"the label of the backup, defaults to one containing the current time")
+	cmd.Flags().BoolVar(&options.Fast, "fast", false,
+		"request an immediate checkpoint instead of a spread one")
+	cmd.Flags().StringVar(&remote.host, "host", "",
+		"the address of the instance to back up, defaults to the local one")
+	cmd.Flags().StringVar(&remote.credentials.CertificateFile, "certificate", "",
+		"the client certificate of the streaming replication user, used with --host")
+	cmd.Flags().StringVar(&remote.credentials.KeyFile, "key", "",
+		"the private key of the client certificate, used with --host")
+	cmd.Flags().StringVar(&remote.credentials.ServerCAFile, "server-ca", "",
+		"the CA verifying the certificate of the instance, used with --host")
+	cmd.Flags().StringVar(&remote.output, "output", "",
+		"the file where the tarball is written, used with --host")
+	cmd.Flags().StringVar(&remote.backupName, "backup-name", "",
+		"the Backup whose status is updated with the result, used with --host")
+	cmd.Flags().StringVar(&remote.namespace, "namespace", os.Getenv("NAMESPACE"),
+		"the namespace of the Backup")
+
+	return cmd
+}
+
+func physicalBackupSubCommand(ctx context.Context, options postgres.PhysicalBackupOptions) error {
+	stream, err := webserver.RequestPhysicalBackup(ctx, http.DefaultClient, options)
+	if err != nil {
+		log.Error(err, "Error while requesting the physical backup")
+		return err
+	}
+	defer func() {
+		if closeErr := stream.Close(); closeErr != nil {
+			log.Error(closeErr, "Can't close the connection")
+		}
+	}()
+
+	if _, err = io.Copy(os.Stdout, stream); err != nil {
+		log.Error(err, "Error while streaming the physical backup")
+		return err
+	}
+
+	result, err := stream.Result()
+	if err != nil {
+		log.Error(err, "Error while completing the physical backup")
+		return err
+	}
+
+	// The standard output contains the tarball, the result goes
+	// to the standard error
+	_, err = fmt.Fprintf(os.Stderr, "begin LSN: %s\nend LSN: %s\nbegin WAL: %s\n",
+		result.BeginLSN, result.EndLSN, result.BeginWAL)
+	return err
+}
+
+// remotePhysicalBackupSubCommand writes to the output file the physical
+// backup of the instance running on the remote host. When a Backup is
+// passed, its status is updated with the result
+func remotePhysicalBackupSubCommand(
+	ctx context.Context,
+	options postgres.PhysicalBackupOptions,
+	remote remoteOptions,
+) error {
+	if remote.output == "" {
+		return errors.New("the output file is required with --host")
+	}
+
+	if remote.backupName == "" {
+		result, err := writeRemotePhysicalBackup(ctx, options, remote)
+		if err != nil {
+			log.Error(err, "Error while taking the physical backup", "host", remote.host)
+			return err
+		}
+		log.Info("Physical backup completed",
+			"output", remote.output,
+			"beginLSN", result.BeginLSN,
+			"endLSN", result.EndLSN,
+			"beginWAL", result.BeginWAL)
+		return nil
+	}
+
+	typedClient, err := management.NewControllerRuntimeClient()
+	if err != nil {
+		return fmt.Errorf("creating controller-runtine client: %v", err)
+	}
+
+	var backup apiv1.Backup
+	if err := typedClient.Get(ctx, client.ObjectKey{Namespace: remote.namespace, Name: remote.backupName},
+		&backup); err != nil {
+		return fmt.Errorf("while getting the backup: %w", err)
+	}
+
+	status := backup.GetStatus()
+	status.Phase = apiv1.BackupPhaseRunning
+	status.StartedAt = &metav1.Time{Time: time.Now()}
+	status.BackupID = options.Label
+	status.DestinationPath = remote.output
+	if err := postgres.UpdateBackupStatusAndRetry(ctx, typedClient, &backup); err != nil {
+		return fmt.Errorf("can't set backup as running: %w", err)
+	}
+
+	result, err := writeRemotePhysicalBackup(ctx, options, remote)
+	status.StoppedAt = &metav1.Time{Time: time.Now()}
+	if err != nil {
+		log.Error(err, "Error while taking the physical backup", "host", remote.host)
+		status.SetAsFailed(err)
+	} else {
+		status.BeginLSN = result.BeginLSN
+		status.EndLSN = result.EndLSN
+		status.BeginWal = result.BeginWAL
+		status.SetAsCompleted()
+	}
+
+	if updateErr := postgres.UpdateBackupStatusAndRetry(ctx, typedClient, &backup); updateErr != nil {
+		log.Error(updateErr, "Can't update the backup status")
+		if err == nil {
+			err = updateErr
+		}
+	}
+
+	return err
+}
+
+// writeRemotePhysicalBackup writes the tarball of the physical backup to
+// the output file, which is removed when the backup is not completed
+func writeRemotePhysicalBackup(
+	ctx context.Context,
+	options postgres.PhysicalBackupOptions,
+	remote remoteOptions,
+) (result *postgres.PhysicalBackupResult, err error) {
+	stream, err := webserver.RequestRemotePhysicalBackup(ctx, remote.host, remote.credentials, options)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := stream.Close(); closeErr != nil {
+			log.Error(closeErr, "Can't close the connection")
+		}
+	}()
+
+	output, err := os.Create(remote.output)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := output.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(remote.output)
+		}
+	}()
+
+	if _, err = io.Copy(output, stream); err != nil {
+		return nil, err
+	}
+	if err = output.Sync(); err != nil {
+		return nil, err
+	}
+
+	return stream.Result()
+}
@@ -23,6 +23,7 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
+	coordinationv1 "k8s.io/api/coordination/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -107,10 +108,14 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 			},
 		}),
 		// We don't need a cache for secrets and configmap, as all reloads
-		// should be driven by changes in the Cluster we are watching
+		// should be driven by changes in the Cluster we are watching.
+		// The instance manager only reads its own Pod and the heartbeat
+		// lease of the primary
 		ClientDisableCacheFor: []client.Object{
 			&corev1.Secret{},
 			&corev1.ConfigMap{},
+			&corev1.Pod{},
+			&coordinationv1.Lease{},
 		},
 		MetricsBindAddress: "0", // TODO: merge metrics to the manager one
 	})
@@ -178,6 +183,51 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		return err
 	}
 
+	if err = mgr.Add(controller.NewSelfFencingWatchdog(instance, mgr.GetAPIReader())); err != nil {
+		setupLog.Error(err, "unable to create self-fencing watchdog")
+		return err
+	}
+
+	if err = mgr.Add(controller.NewPrimaryHeartbeatWatchdog(instance, mgr.GetClient())); err != nil {
+		setupLog.Error(err, "unable to create primary heartbeat watchdog")
+		return err
+	}
+
+	if err = mgr.Add(controller.NewArchiverWatchdog(instance, mgr.GetClient())); err != nil {
+		setupLog.Error(err, "unable to create WAL archiver watchdog")
+		return err
+	}
+
+	if err = mgr.Add(controller.NewDiskSpaceWatchdog(
+		instance, mgr.GetClient(), mgr.GetEventRecorderFor("instance-manager"))); err != nil {
+		setupLog.Error(err, "unable to create disk space watchdog")
+		return err
+	}
+
+	if err = mgr.Add(controller.NewAlterSystemWatchdog(
+		instance, mgr.GetClient(), mgr.GetEventRecorderFor("instance-manager"))); err != nil {
+		setupLog.Error(err, "unable to create ALTER SYSTEM watchdog")
+		return err
+	}
+
+	if err = mgr.Add(controller.NewDataVerificationWatchdog(
+		instance, mgr.GetClient(), mgr.GetEventRecorderFor("instance-manager"))); err != nil {
+		setupLog.Error(err, "unable to create data verification watchdog")
+		return err
+	}
+
+	if err = mgr.Add(controller.NewObjectStoreGarbageCollector(
+		instance, mgr.GetClient(), mgr.GetEventRecorderFor("instance-manager"))); err != nil {
+		setupLog.Error(err, "unable to create object store garbage collector")
+		return err
+	}
+
+	if err = mgr.Add(controller.NewChaosExperimentRunner(
+		instance, mgr.GetClient(), mgr.GetEventRecorderFor("instance-manager"))); err != nil {
+		setupLog.Error(err, "unable to create chaos experiment runner")
+		return err
+	}
+
 	// onlineUpgradeCtx is a child context of the postgres context.
 	// onlineUpgradeCtx will be the context passed to all the manager handled Runnables via Start(ctx),
 	// its deletion will imply all Runnables to stop, but will be handled
@@ -204,6 +254,15 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		return err
 	}
 
+	secureSrv, err := webserver.NewSecureWebServer(instance)
+	if err != nil {
+		return err
+	}
+	if err = mgr.Add(secureSrv); err != nil {
+		setupLog.Error(err, "unable to add secure webserver runnable")
+		return err
+	}
+
 	setupLog.Info("starting controller-runtime manager")
 	if err := mgr.Start(onlineUpgradeCtx); err != nil {
 		setupLog.Error(err, "unable to run controller-runtime manager")
@@ -79,23 +79,6 @@ func (l *Flags) ConfigureLogging() {
 	mlog.SetLogger(logger)
 }
 
-func getLogLevel(l string) zapcore.Level {
-	switch l {
-	case mlog.ErrorLevelString:
-		return mlog.ErrorLevel
-	case mlog.WarningLevelString:
-		return mlog.WarningLevel
-	case mlog.InfoLevelString:
-		return mlog.InfoLevel
-	case mlog.DebugLevelString:
-		return mlog.DebugLevel
-	case mlog.TraceLevelString:
-		return mlog.TraceLevel
-	default:
-		return mlog.DefaultLevel
-	}
-}
-
 func getLogLevelString(l zapcore.Level) string {
 	switch l {
 	case mlog.ErrorLevel:
@@ -114,7 +97,8 @@ func getLogLevelString(l zapcore.Level) string {
 }
 
 func customLevel(in *zap.Options) {
-	in.Level = getLogLevel(logLevel)
+	mlog.SetLevel(mlog.ParseLevel(logLevel))
+	in.Level = mlog.GetLevel()
 	in.EncoderConfigOptions = append(in.EncoderConfigOptions, func(c *zapcore.EncoderConfig) {
 		c.EncodeLevel = func(l zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
 			enc.AppendString(getLogLevelString(l))
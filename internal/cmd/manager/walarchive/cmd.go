@@ -25,6 +25,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"time"
 
@@ -41,6 +42,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/plugin"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -51,6 +53,10 @@ const (
 	SpoolDirectory = postgres.ScratchDataDirectory + "/wal-archive-spool"
 )
 
+// errChaosArchivingPaused is raised when the WAL archiving is
+// paused by a chaos experiment
+var errChaosArchivingPaused = errors.New("WAL archiving paused by a chaos experiment")
+
 // NewCmd creates the new cobra command
 func NewCmd() *cobra.Command {
 	var podName string
@@ -104,14 +110,9 @@ func run(ctx context.Context, podName string, args []string, client client.WithW
 		return fmt.Errorf("failed to get cluster: %w", err)
 	}
 
-	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
-		// Backup not configured, skipping WAL
-		contextLog.Info("Backup not configured, skip WAL archiving",
-			"walName", walName,
-			"currentPrimary", cluster.Status.CurrentPrimary,
-			"targetPrimary", cluster.Status.TargetPrimary,
-		)
-		return nil
+	if experiment := cluster.GetActiveChaosExperiment(podName, time.Now()); experiment != nil &&
+		experiment.Action == utils.ChaosExperimentPauseArchiving {
+		return errChaosArchivingPaused
 	}
 
 	if cluster.Spec.ReplicaCluster != nil && cluster.Spec.ReplicaCluster.Enabled {
@@ -127,9 +128,23 @@ func run(ctx context.Context, podName string, args []string, client client.WithW
 		}
 	}
 
+	if archived, err := archiveWALWithPlugin(ctx, cluster, walName); err != nil || archived {
+		return err
+	}
+
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+		// Backup not configured, skipping WAL
+		contextLog.Info("Backup not configured, skip WAL archiving",
+			"walName", walName,
+			"currentPrimary", cluster.Status.CurrentPrimary,
+			"targetPrimary", cluster.Status.TargetPrimary,
+		)
+		return nil
+	}
+
 	maxParallel := 1
-	if cluster.Spec.Backup.BarmanObjectStore.Wal != nil {
-		maxParallel = cluster.Spec.Backup.BarmanObjectStore.Wal.MaxParallel
+	if wal := cluster.Spec.Backup.BarmanObjectStore.Wal; wal != nil && wal.MaxParallel > 1 {
+		maxParallel = wal.MaxParallel
 	}
 
 	// Get environment from cache
@@ -167,7 +182,7 @@ func run(ctx context.Context, podName string, args []string, client client.WithW
 		return err
 	}
 
-	options, err := barmanCloudWalArchiveOptions(cluster, cluster.Name)
+	options, err := barmanCloudWalArchiveOptions(cluster.Spec.Backup.BarmanObjectStore, cluster.Name)
 	if err != nil {
 		log.Error(err, "while getting barman-cloud-wal-archive options")
 		condition := metav1.Condition{
@@ -182,6 +197,10 @@ func run(ctx context.Context, podName string, args []string, client client.WithW
 		return err
 	}
 
+	// The additional object stores which can't be configured are only
+	// reported, as they must never block the archiving in the main one
+	setupErrors := addAdditionalDestinations(cluster, walArchiver)
+
 	// Step 5: archive the WAL files in parallel
 	uploadStartTime := time.Now()
 	walStatus := walArchiver.ArchiveList(ctx, walFilesList, options)
@@ -201,8 +220,16 @@ func run(ctx context.Context, podName string, args []string, client client.WithW
 		Reason:  string(apiv1.ConditionReasonContinuousArchivingSuccess),
 		Message: "Continuous archiving is working",
 	}
+	if walStatus[0].Err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(apiv1.ConditionReasonContinuousArchivingFailing)
+		condition.Message = walStatus[0].Err.Error()
+	}
 	if errCond := manager.UpdateCondition(ctx, client, cluster, &condition); errCond != nil {
-		log.Error(errCond, "Error while updating wal archiving condition (wal archiving succeeded)")
+		log.Error(errCond, "Error while updating wal archiving condition")
+	}
+	if err := updateAdditionalObjectStoresStatus(ctx, client, cluster, walStatus, setupErrors); err != nil {
+		log.Error(err, "Error while updating the status of the additional object stores")
 	}
 	// We return only the first error to PostgreSQL, because the first error
 	// is the one raised by the file that PostgreSQL has requested to archive.
@@ -285,15 +312,117 @@ func gatherWALFilesToArchive(ctx context.Context, requestedWALFile string, paral
 	return walList
 }
 
-func barmanCloudWalArchiveOptions(
+// addAdditionalDestinations adds to the archiver the additional object
+// stores of the cluster, reading their environment from the cache.
+// The stores which can't be configured are skipped, and their
+// errors are returned indexed by their name
+func addAdditionalDestinations(cluster *apiv1.Cluster, walArchiver *archiver.WALArchiver) map[string]error {
+	setupErrors := make(map[string]error)
+	for _, store := range cluster.Spec.Backup.AdditionalObjectStores {
+		env, err := cacheClient.GetEnv(cache.GetAdditionalWALArchiveKey(store.Name))
+		if err != nil {
+			setupErrors[store.Name] = fmt.Errorf("failed to get envs: %w", err)
+			continue
+		}
+
+		storeConfiguration := store.BarmanObjectStore
+		options, err := barmanCloudWalArchiveOptions(&storeConfiguration, cluster.Name)
+		if err != nil {
+			setupErrors[store.Name] = fmt.Errorf("while getting barman-cloud-wal-archive options: %w", err)
+			continue
+		}
+
+		walArchiver.AddDestination(archiver.Destination{
+			Name:    store.Name,
+			Env:     env,
+			Options: options,
+		})
+	}
+
+	return setupErrors
+}
+
+// updateAdditionalObjectStoresStatus reports in the cluster status which
+// additional object stores failed archiving the WAL files. The WAL files
+// archived in the main object store are never retried in the additional
+// ones, so a failure leaves a gap in their WAL archive
+func updateAdditionalObjectStoresStatus(
+	ctx context.Context,
+	c client.Client,
+	cluster *apiv1.Cluster,
+	walStatus []archiver.WALArchiverResult,
+	setupErrors map[string]error,
+) error {
+	existingCluster := cluster.DeepCopy()
+	cluster.Status.AdditionalObjectStores = getAdditionalObjectStoresStatus(
+		cluster, walStatus, setupErrors, time.Now())
+
+	if reflect.DeepEqual(existingCluster.Status.AdditionalObjectStores, cluster.Status.AdditionalObjectStores) {
+		return nil
+	}
+	return c.Status().Patch(ctx, cluster, client.MergeFrom(existingCluster))
+}
+
+// getAdditionalObjectStoresStatus computes the status of the additional
+// object stores of the cluster after archiving a list of WAL files
+func getAdditionalObjectStoresStatus(
 	cluster *apiv1.Cluster,
+	walStatus []archiver.WALArchiverResult,
+	setupErrors map[string]error,
+	now time.Time,
+) []apiv1.AdditionalObjectStoreStatus {
+	previousStatus := make(map[string]apiv1.AdditionalObjectStoreStatus, len(cluster.Status.AdditionalObjectStores))
+	for _, storeStatus := range cluster.Status.AdditionalObjectStores {
+		previousStatus[storeStatus.Name] = storeStatus
+	}
+
+	var result []apiv1.AdditionalObjectStoreStatus
+	for _, store := range cluster.Spec.Backup.AdditionalObjectStores {
+		storeStatus := previousStatus[store.Name]
+		storeStatus.Name = store.Name
+
+		var failedWAL string
+		err := setupErrors[store.Name]
+		for _, walResult := range walStatus {
+			// The WAL files failing in the main object store
+			// haven't been tried in the additional ones
+			if walResult.Err != nil {
+				continue
+			}
+			if err != nil {
+				failedWAL = walResult.WalName
+				break
+			}
+			if destinationErr := walResult.DestinationErrors[store.Name]; destinationErr != nil {
+				failedWAL, err = walResult.WalName, destinationErr
+				break
+			}
+		}
+
+		switch {
+		case failedWAL != "":
+			storeStatus.WALArchivingFailing = true
+			storeStatus.LastFailedWAL = failedWAL
+			storeStatus.LastFailedWALTime = now.Format(time.RFC3339)
+			storeStatus.LastWALArchivingError = err.Error()
+		case len(walStatus) > 0 && walStatus[0].Err == nil:
+			storeStatus.WALArchivingFailing = false
+		}
+
+		result = append(result, storeStatus)
+	}
+
+	return result
+}
+
+func barmanCloudWalArchiveOptions(
+	configuration *apiv1.BarmanObjectStoreConfiguration,
 	clusterName string,
 ) ([]string, error) {
 	capabilities, err := barmanCapabilities.CurrentCapabilities()
 	if err != nil {
 		return nil, err
 	}
-	configuration := cluster.Spec.Backup.BarmanObjectStore
 
 	var options []string
 	if configuration.Wal != nil {
@@ -393,3 +522,49 @@ func checkWalArchive(ctx context.Context,
 
 	return nil
 }
+
+// archiveWALWithPlugin archives a WAL file using the first plugin of
+// the cluster implementing the wal capability, returning false when
+// there is no such plugin. The plugin is looked up by the instance
+// manager, which stores its name in the cache
+func archiveWALWithPlugin(ctx context.Context, cluster *apiv1.Cluster, walName string) (bool, error) {
+	if len(cluster.Spec.Plugins) == 0 {
+		return false, nil
+	}
+
+	pluginName, err := cacheClient.GetWALPlugin()
+	if err != nil {
+		return false, fmt.Errorf("while getting the WAL plugin: %w", err)
+	}
+	pluginConfiguration, found := cluster.GetPlugin(pluginName)
+	if !found {
+		return false, nil
+	}
+
+	pluginClient, err := plugin.DialLocal(ctx, pluginName)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = pluginClient.Close()
+	}()
+
+	// The archive command is executed inside the data directory
+	sourcePath, err := filepath.Abs(walName)
+	if err != nil {
+		return false, err
+	}
+
+	if _, err := pluginClient.ArchiveWAL(ctx, &plugin.ArchiveWALRequest{
+		Cluster:    cluster,
+		Parameters: pluginConfiguration.Parameters,
+		SourcePath: sourcePath,
+	}); err != nil {
+		return false, fmt.Errorf("while archiving %s with plugin %s: %w", walName, pluginConfiguration.Name, err)
+	}
+
+	log.FromContext(ctx).Info("Archived WAL file with plugin",
+		"walName", walName,
+		"plugin", pluginConfiguration.Name)
+	return true, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchive
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Function gatherWALFilesToArchive", func() {
+	var pgData string
+
+	markReady := func(names ...string) {
+		for _, name := range names {
+			Expect(os.WriteFile(
+				path.Join(pgData, "pg_wal", "archive_status", name+".ready"), nil, 0o600)).To(Succeed())
+		}
+	}
+
+	BeforeEach(func() {
+		pgData = GinkgoT().TempDir()
+		Expect(os.MkdirAll(path.Join(pgData, "pg_wal", "archive_status"), 0o700)).To(Succeed())
+		GinkgoT().Setenv("PGDATA", pgData)
+	})
+
+	It("only archives the requested WAL file when parallelism is disabled", func() {
+		markReady("000000010000000000000001", "000000010000000000000002")
+		walList := gatherWALFilesToArchive(context.TODO(), "pg_wal/000000010000000000000001", 1)
+		Expect(walList).To(Equal([]string{"pg_wal/000000010000000000000001"}))
+	})
+
+	It("adds the other ready WAL files, in order, up to the parallelism limit", func() {
+		markReady(
+			"000000010000000000000001",
+			"000000010000000000000002",
+			"000000010000000000000003",
+			"000000010000000000000004",
+		)
+		Expect(os.WriteFile(
+			path.Join(pgData, "pg_wal", "archive_status", "000000010000000000000000.done"), nil, 0o600)).
+			To(Succeed())
+
+		walList := gatherWALFilesToArchive(context.TODO(), "pg_wal/000000010000000000000001", 3)
+		Expect(walList).To(Equal([]string{
+			"pg_wal/000000010000000000000001",
+			"pg_wal/000000010000000000000002",
+			"pg_wal/000000010000000000000003",
+		}))
+	})
+
+	It("returns the requested WAL file when no other file is ready", func() {
+		walList := gatherWALFilesToArchive(context.TODO(), "pg_wal/000000010000000000000001", 8)
+		Expect(walList).To(Equal([]string{"pg_wal/000000010000000000000001"}))
+	})
+})
+
+var _ = Describe("Function getAdditionalObjectStoresStatus", func() {
+	now := time.Date(2022, 8, 1, 10, 0, 0, 0, time.UTC)
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{Backup: &apiv1.BackupConfiguration{
+				AdditionalObjectStores: []apiv1.AdditionalObjectStore{{Name: "minio"}, {Name: "offsite"}},
+			}},
+		}
+	})
+
+	It("reports the stores which failed archiving a WAL file", func() {
+		walStatus := []archiver.WALArchiverResult{
+			{WalName: "000000010000000000000001"},
+			{
+				WalName:           "000000010000000000000002",
+				DestinationErrors: map[string]error{"offsite": errors.New("unreachable")},
+			},
+		}
+		result := getAdditionalObjectStoresStatus(cluster, walStatus, nil, now)
+		Expect(result).To(HaveLen(2))
+		Expect(result[0]).To(Equal(apiv1.AdditionalObjectStoreStatus{Name: "minio"}))
+		Expect(result[1].WALArchivingFailing).To(BeTrue())
+		Expect(result[1].LastFailedWAL).To(Equal("000000010000000000000002"))
+		Expect(result[1].LastFailedWALTime).To(Equal("2022-08-01T10:00:00Z"))
+		Expect(result[1].LastWALArchivingError).To(Equal("unreachable"))
+	})
+
+	It("reports the stores which couldn't be configured", func() {
+		walStatus := []archiver.WALArchiverResult{{WalName: "000000010000000000000001"}}
+		setupErrors := map[string]error{"minio": errors.New("missing credentials")}
+		result := getAdditionalObjectStoresStatus(cluster, walStatus, setupErrors, now)
+		Expect(result[0].WALArchivingFailing).To(BeTrue())
+		Expect(result[0].LastFailedWAL).To(Equal("000000010000000000000001"))
+		Expect(result[1].WALArchivingFailing).To(BeFalse())
+	})
+
+	It("clears the failure, keeping the last failed WAL file, and drops the removed stores", func() {
+		cluster.Status.AdditionalObjectStores = []apiv1.AdditionalObjectStoreStatus{
+			{
+				Name:                "offsite",
+				WALArchivingFailing: true,
+				LastFailedWAL:       "000000010000000000000001",
+			},
+			{Name: "removed", WALArchivingFailing: true},
+		}
+		walStatus := []archiver.WALArchiverResult{{WalName: "000000010000000000000002"}}
+		result := getAdditionalObjectStoresStatus(cluster, walStatus, nil, now)
+		Expect(result).To(HaveLen(2))
+		Expect(result[1].Name).To(Equal("offsite"))
+		Expect(result[1].WALArchivingFailing).To(BeFalse())
+		Expect(result[1].LastFailedWAL).To(Equal("000000010000000000000001"))
+	})
+
+	It("doesn't change the status when the main object store failed", func() {
+		cluster.Status.AdditionalObjectStores = []apiv1.AdditionalObjectStoreStatus{
+			{Name: "offsite", WALArchivingFailing: true},
+		}
+		walStatus := []archiver.WALArchiverResult{
+			{WalName: "000000010000000000000002", Err: errors.New("main store failed")},
+		}
+		result := getAdditionalObjectStoresStatus(cluster, walStatus, nil, now)
+		Expect(result[1].WALArchivingFailing).To(BeTrue())
+	})
+})
@@ -33,6 +33,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/plugin"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
@@ -112,6 +113,13 @@ func run(ctx context.Context, podName string, args []string) error {
 	}
 
 	recoverClusterName, recoverEnv, barmanConfiguration, err := GetRecoverConfiguration(cluster, podName)
+	if errors.Is(err, ErrNoBackupConfigured) {
+		// Without an object store, the WAL files may be restored by a plugin
+		err = restoreWALWithPlugin(ctx, cluster, podName, walName, destinationPath)
+		if !errors.Is(err, ErrNoBackupConfigured) {
+			return err
+		}
+	}
 	if errors.Is(err, ErrNoBackupConfigured) {
 		// Backup not configured, skipping WAL
 		contextLog.Trace("Skipping WAL restore, there is no backup configuration",
@@ -281,30 +289,15 @@ func GetRecoverConfiguration(
 	*apiv1.BarmanObjectStoreConfiguration,
 	error,
 ) {
-	var env []string
 	// If I am the designated primary. Let's use the recovery object store for this wal
 	if cluster.IsReplica() && cluster.Status.CurrentPrimary == podName {
-		sourceName := cluster.Spec.ReplicaCluster.Source
-		externalCluster, found := cluster.ExternalCluster(sourceName)
-		if !found {
-			return "", nil, nil, ErrExternalClusterNotFound
-		}
-
-		if externalCluster.BarmanObjectStore == nil {
-			return "", nil, nil, ErrNoBackupConfigured
-		}
-		configuration := externalCluster.BarmanObjectStore
-		if configuration.EndpointCA != nil && configuration.BarmanCredentials.AWS != nil {
-			env = append(env, fmt.Sprintf("AWS_CA_BUNDLE=%s", postgres.BarmanRestoreEndpointCACertificateLocation))
-		} else if configuration.EndpointCA != nil && configuration.BarmanCredentials.Azure != nil {
-			env = append(env, fmt.Sprintf("REQUESTS_CA_BUNDLE=%s", postgres.BarmanRestoreEndpointCACertificateLocation))
-		}
-		return externalCluster.Name, env, externalCluster.BarmanObjectStore, nil
+		return getReplicaSourceRecoverConfiguration(cluster)
 	}
 
 	// Otherwise, let's use the object store which we are using to
 	// back up this cluster
 	if cluster.Spec.Backup != nil && cluster.Spec.Backup.BarmanObjectStore != nil {
+		var env []string
 		configuration := cluster.Spec.Backup.BarmanObjectStore
 		if configuration.EndpointCA != nil && configuration.BarmanCredentials.AWS != nil {
 			env = append(env, fmt.Sprintf("AWS_CA_BUNDLE=%s", postgres.BarmanBackupEndpointCACertificateLocation))
@@ -314,9 +307,125 @@ func GetRecoverConfiguration(
 		return cluster.Name, env, cluster.Spec.Backup.BarmanObjectStore, nil
 	}
 
+	// The standbys of a replica cluster which is not backed up can
+	// fetch the WAL files from the object store of the source cluster,
+	// where the designated primary fetches them too
+	if cluster.IsReplica() {
+		return getReplicaSourceRecoverConfiguration(cluster)
+	}
+
 	return "", nil, nil, ErrNoBackupConfigured
 }
 
+// getReplicaSourceRecoverConfiguration gets the recover configuration
+// pointing to the object store of the source of a replica cluster
+func getReplicaSourceRecoverConfiguration(
+	cluster *apiv1.Cluster,
+) (
+	string,
+	[]string,
+	*apiv1.BarmanObjectStoreConfiguration,
+	error,
+) {
+	var env []string
+	sourceName := cluster.Spec.ReplicaCluster.Source
+	externalCluster, found := cluster.ExternalCluster(sourceName)
+	if !found {
+		return "", nil, nil, ErrExternalClusterNotFound
+	}
+
+	if externalCluster.BarmanObjectStore == nil {
+		return "", nil, nil, ErrNoBackupConfigured
+	}
+	configuration := externalCluster.BarmanObjectStore
+	if configuration.EndpointCA != nil && configuration.BarmanCredentials.AWS != nil {
+		env = append(env, fmt.Sprintf("AWS_CA_BUNDLE=%s", postgres.BarmanRestoreEndpointCACertificateLocation))
+	} else if configuration.EndpointCA != nil && configuration.BarmanCredentials.Azure != nil {
+		env = append(env, fmt.Sprintf("REQUESTS_CA_BUNDLE=%s", postgres.BarmanRestoreEndpointCACertificateLocation))
+	}
+	return externalCluster.Name, env, externalCluster.BarmanObjectStore, nil
+}
+
+// restoreWALWithPlugin restores a WAL file using a plugin, returning
+// ErrNoBackupConfigured when there is no plugin able to restore it.
+// The designated primary of a replica cluster uses the plugin of
+// the source cluster, the other instances the first plugin of the
+// cluster implementing the wal capability
+func restoreWALWithPlugin(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	podName string,
+	walName string,
+	destinationPath string,
+) error {
+	var pluginClient *plugin.Client
+	var pluginConfiguration *apiv1.PluginConfiguration
+	var err error
+	if cluster.IsReplica() && cluster.Status.CurrentPrimary == podName {
+		externalCluster, found := cluster.ExternalCluster(cluster.Spec.ReplicaCluster.Source)
+		if !found || externalCluster.PluginConfiguration == nil {
+			return ErrNoBackupConfigured
+		}
+		pluginConfiguration = externalCluster.PluginConfiguration
+		pluginClient, err = plugin.DialLocal(ctx, pluginConfiguration.Name)
+	} else {
+		pluginConfiguration, err = getWALPlugin(cluster)
+		if err == nil {
+			pluginClient, err = plugin.DialLocal(ctx, pluginConfiguration.Name)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if pluginClient == nil {
+		return ErrNoBackupConfigured
+	}
+	defer func() {
+		_ = pluginClient.Close()
+	}()
+
+	result, err := pluginClient.RestoreWAL(ctx, &plugin.RestoreWALRequest{
+		Cluster:         cluster,
+		Parameters:      pluginConfiguration.Parameters,
+		WALName:         walName,
+		DestinationPath: destinationPath,
+	})
+	if err != nil {
+		return fmt.Errorf("while restoring %s with plugin %s: %w", walName, pluginConfiguration.Name, err)
+	}
+	if result.NotFound {
+		log.FromContext(ctx).Info("WAL file not found in the archive of the plugin",
+			"walName", walName,
+			"plugin", pluginConfiguration.Name)
+		return restorer.ErrWALNotFound
+	}
+
+	log.FromContext(ctx).Info("Restored WAL file with plugin",
+		"walName", walName,
+		"plugin", pluginConfiguration.Name)
+	return nil
+}
+
+// getWALPlugin gets the configuration of the plugin implementing the wal
+// capability, whose name is stored in the cache by the instance manager,
+// returning ErrNoBackupConfigured when there is no such plugin
+func getWALPlugin(cluster *apiv1.Cluster) (*apiv1.PluginConfiguration, error) {
+	if len(cluster.Spec.Plugins) == 0 {
+		return nil, ErrNoBackupConfigured
+	}
+
+	pluginName, err := cacheClient.GetWALPlugin()
+	if err != nil {
+		return nil, fmt.Errorf("while getting the WAL plugin: %w", err)
+	}
+	pluginConfiguration, found := cluster.GetPlugin(pluginName)
+	if !found {
+		return nil, ErrNoBackupConfigured
+	}
+
+	return pluginConfiguration, nil
+}
+
 // gatherWALFilesToRestore files a list of possible WAL files to restore, always
 // including as the first one the requested WAL file
 func gatherWALFilesToRestore(walName string, parallel int) (walList []string, err error) {
@@ -108,3 +108,70 @@ var _ = Describe("Function isStreamingAvailable", func() {
 		Expect(isStreamingAvailable(&cluster, "primaryPod")).To(BeTrue())
 	})
 })
+
+var _ = Describe("Function GetRecoverConfiguration", func() {
+	sourceStore := &apiv1.BarmanObjectStoreConfiguration{DestinationPath: "s3://source/"}
+	clusterStore := &apiv1.BarmanObjectStoreConfiguration{DestinationPath: "s3://cluster/"}
+
+	var replicaCluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		replicaCluster = &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "primaryPod",
+			},
+			Spec: apiv1.ClusterSpec{
+				ExternalClusters: []apiv1.ExternalCluster{
+					{
+						Name:              "clusterSource",
+						BarmanObjectStore: sourceStore,
+					},
+				},
+				ReplicaCluster: &apiv1.ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "clusterSource",
+				},
+			},
+		}
+	})
+
+	It("uses the object store of the cluster for the standbys", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{BarmanObjectStore: clusterStore},
+			},
+		}
+		cluster.Name = "cluster"
+		name, _, configuration, err := GetRecoverConfiguration(cluster, "replicaPod")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(Equal("cluster"))
+		Expect(configuration).To(Equal(clusterStore))
+	})
+
+	It("returns ErrNoBackupConfigured when there's no object store", func() {
+		_, _, _, err := GetRecoverConfiguration(&apiv1.Cluster{}, "replicaPod")
+		Expect(err).To(Equal(ErrNoBackupConfigured))
+	})
+
+	It("uses the object store of the source for the designated primary", func() {
+		replicaCluster.Spec.Backup = &apiv1.BackupConfiguration{BarmanObjectStore: clusterStore}
+		name, _, configuration, err := GetRecoverConfiguration(replicaCluster, "primaryPod")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(Equal("clusterSource"))
+		Expect(configuration).To(Equal(sourceStore))
+	})
+
+	It("uses the object store of the source for the standbys of a replica cluster not backed up", func() {
+		name, _, configuration, err := GetRecoverConfiguration(replicaCluster, "replicaPod")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(Equal("clusterSource"))
+		Expect(configuration).To(Equal(sourceStore))
+	})
+
+	It("prefers the object store of a replica cluster for its standbys", func() {
+		replicaCluster.Spec.Backup = &apiv1.BackupConfiguration{BarmanObjectStore: clusterStore}
+		_, _, configuration, err := GetRecoverConfiguration(replicaCluster, "replicaPod")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(configuration).To(Equal(clusterStore))
+	})
+})
@@ -0,0 +1,102 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package chaos implements a command to run chaos experiments on the
+// instances of a cluster
+package chaos
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// start requests a chaos experiment on an instance of a cluster
+func start(
+	ctx context.Context,
+	clusterName string,
+	experiment utils.ChaosExperiment,
+) error {
+	var cluster apiv1.Cluster
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return err
+	}
+
+	if !cluster.Spec.EnableChaosTesting {
+		return fmt.Errorf("chaos testing is not enabled in cluster %s", clusterName)
+	}
+
+	var pod v1.Pod
+	err = plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: experiment.Instance}, &pod)
+	if err != nil {
+		return fmt.Errorf("instance %s not found in namespace %s", experiment.Instance, plugin.Namespace)
+	}
+
+	experiment.StartedAt = metav1.Now()
+	if err := experiment.Validate(); err != nil {
+		return err
+	}
+
+	if err := applyChaosExperiment(ctx, &cluster, &experiment); err != nil {
+		return err
+	}
+
+	fmt.Printf("chaos experiment %s started on %s, ending at %s\n",
+		experiment.Action, experiment.Instance,
+		experiment.StartedAt.Add(experiment.Duration.Duration).Format(time.RFC3339))
+	return nil
+}
+
+// stop ends the chaos experiment running on a cluster
+func stop(ctx context.Context, clusterName string) error {
+	var cluster apiv1.Cluster
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := cluster.Annotations[utils.ChaosExperimentAnnotationName]; !ok {
+		return fmt.Errorf("no chaos experiment requested in cluster %s", clusterName)
+	}
+
+	if err := applyChaosExperiment(ctx, &cluster, nil); err != nil {
+		return err
+	}
+
+	fmt.Printf("chaos experiment stopped in cluster %s\n", clusterName)
+	return nil
+}
+
+// applyChaosExperiment sets, or removes when nil, the chaos
+// experiment requested on a cluster
+func applyChaosExperiment(ctx context.Context, cluster *apiv1.Cluster, experiment *utils.ChaosExperiment) error {
+	updatedCluster := cluster.DeepCopy()
+	if err := utils.SetChaosExperiment(&updatedCluster.ObjectMeta, experiment); err != nil {
+		return err
+	}
+	updatedCluster.ManagedFields = nil
+
+	return plugin.Client.Patch(ctx, updatedCluster, client.MergeFrom(cluster))
+}
@@ -0,0 +1,91 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chaos
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// NewCmd creates the new "chaos" command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "chaos",
+		Short: `Chaos experiments simulating failures on the instances`,
+		Long: `Chaos experiments simulating failures on the instances, to rehearse the incident
+response. They require chaos testing to be enabled in the cluster, and the
+simulated failures are automatically reverted when the experiments end.`,
+	}
+	cmd.AddCommand(newStartCmd())
+	cmd.AddCommand(newStopCmd())
+
+	return cmd
+}
+
+func newStartCmd() *cobra.Command {
+	var duration, delay time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "start [cluster] [node] [action]",
+		Short: `Start a chaos experiment on the instance named [cluster]-[node] or [node]`,
+		Long: `Start a chaos experiment on the instance named [cluster]-[node] or [node].
+The available actions are:
+
+  killPostgres    kill PostgreSQL with SIGKILL, simulating a crash
+  pauseArchiving  make the WAL archiving fail
+  replicationLag  delay the replay of the WAL on a replica by --delay`,
+		Args: cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName := args[0]
+			node := args[1]
+			if _, err := strconv.Atoi(args[1]); err == nil {
+				node = fmt.Sprintf("%s-%s", clusterName, node)
+			}
+
+			return start(cmd.Context(), clusterName, utils.ChaosExperiment{
+				Action:   utils.ChaosExperimentAction(args[2]),
+				Instance: node,
+				Duration: metav1.Duration{Duration: duration},
+				Delay:    metav1.Duration{Duration: delay},
+			})
+		},
+	}
+
+	cmd.Flags().DurationVar(&duration, "duration", 5*time.Minute,
+		"The duration of the experiment, after which the failure is reverted")
+	cmd.Flags().DurationVar(&delay, "delay", 0,
+		"The replication lag injected by the replicationLag action")
+
+	return cmd
+}
+
+func newStopCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stop [cluster]",
+		Short: `Stop the chaos experiment running on a cluster, reverting the simulated failure`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return stop(cmd.Context(), args[0])
+		},
+	}
+}
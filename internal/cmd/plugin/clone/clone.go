@@ -0,0 +1,452 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clone implements the kubectl-cnpg clone command, creating an
+// independent copy of a cluster from its latest backup or from a set
+// of volume snapshots
+package clone
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+)
+
+// Options contains the parameters of the clone operation
+type Options struct {
+	// The namespace of the new cluster, defaulting to the
+	// namespace of the source cluster
+	TargetNamespace string
+
+	// The volume snapshot of the storage to clone the cluster from,
+	// instead of its latest backup
+	Snapshot string
+
+	// The volume snapshot of the WAL storage, needed together with
+	// Snapshot when the source cluster has a WAL storage
+	WalSnapshot string
+
+	// When true, the new cluster is printed instead of being created
+	DryRun bool
+}
+
+// Clone implements the "clone" subcommand, creating the cluster targetName
+// as an independent copy of the cluster sourceName, bootstrapped from the
+// latest completed backup of the latter or from the passed volume snapshots
+func Clone(ctx context.Context, sourceName, targetName string, options Options) error {
+	targetNamespace := options.TargetNamespace
+	if targetNamespace == "" {
+		targetNamespace = plugin.Namespace
+	}
+
+	var source apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: sourceName},
+		&source,
+	); err != nil {
+		return fmt.Errorf("while getting the cluster %s: %w", sourceName, err)
+	}
+
+	var target *apiv1.Cluster
+	var origin string
+	if options.Snapshot != "" {
+		// Volume snapshots are namespaced, and a PVC can only be
+		// restored from a snapshot in its own namespace
+		if targetNamespace != source.Namespace {
+			return fmt.Errorf("a cluster can be cloned from volume snapshots only in the namespace %s",
+				source.Namespace)
+		}
+
+		snapshots := &apiv1.DataSource{
+			Storage: newVolumeSnapshotReference(options.Snapshot),
+		}
+		if options.WalSnapshot != "" {
+			walStorage := newVolumeSnapshotReference(options.WalSnapshot)
+			snapshots.WalStorage = &walStorage
+		}
+
+		target = NewClonedClusterFromSnapshots(&source, snapshots, targetName, targetNamespace)
+		origin = fmt.Sprintf("volume snapshot %s", options.Snapshot)
+	} else {
+		if source.Spec.Backup == nil || source.Spec.Backup.BarmanObjectStore == nil {
+			return fmt.Errorf("cluster %s has no object store configured for backups", sourceName)
+		}
+
+		var backups apiv1.BackupList
+		if err := plugin.Client.List(ctx, &backups, client.InNamespace(plugin.Namespace)); err != nil {
+			return fmt.Errorf("while listing the backups: %w", err)
+		}
+
+		backup := getLatestBackup(sourceName, backups.Items)
+		if backup == nil {
+			return fmt.Errorf("cluster %s has no completed backup on an object store", sourceName)
+		}
+
+		target = NewClonedCluster(&source, backup, targetName, targetNamespace)
+		origin = fmt.Sprintf("backup %s (%s)", backup.Name, backup.Status.BackupID)
+	}
+
+	if options.DryRun {
+		data, err := yaml.Marshal(target)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+		return nil
+	}
+
+	if targetNamespace != source.Namespace {
+		secrets := stringset.From(getReferencedSecrets(&target.Spec)).ToList()
+		if err := copySecrets(ctx, source.Namespace, targetNamespace, secrets); err != nil {
+			return err
+		}
+
+		configMaps := stringset.From(getReferencedConfigMaps(&target.Spec)).ToList()
+		if err := copyConfigMaps(ctx, source.Namespace, targetNamespace, configMaps); err != nil {
+			return err
+		}
+	}
+
+	if err := plugin.Client.Create(ctx, target); err != nil {
+		return fmt.Errorf("while creating the cluster %s: %w", targetName, err)
+	}
+
+	fmt.Printf("cluster %s/%s created from %s of cluster %s/%s\n",
+		targetNamespace, targetName, origin, source.Namespace, sourceName)
+	return nil
+}
+
+// NewClonedCluster builds the definition of a cluster named name in the
+// passed namespace, bootstrapped with a recovery of the passed backup of
+// the source cluster. The new cluster has its own credentials and certificates,
+// and doesn't archive its WALs, so that it cannot write in the object store
+// of the source cluster
+func NewClonedCluster(source *apiv1.Cluster, backup *apiv1.Backup, name, namespace string) *apiv1.Cluster {
+	spec := newClonedSpec(source, namespace)
+
+	objectStore := source.Spec.Backup.BarmanObjectStore.DeepCopy()
+	if objectStore.ServerName == "" {
+		objectStore.ServerName = source.Name
+	}
+
+	externalClusters := []apiv1.ExternalCluster{
+		{
+			Name:              source.Name,
+			BarmanObjectStore: objectStore,
+		},
+	}
+	for _, server := range spec.ExternalClusters {
+		if server.Name != source.Name {
+			externalClusters = append(externalClusters, server)
+		}
+	}
+	spec.ExternalClusters = externalClusters
+
+	spec.Bootstrap = &apiv1.BootstrapConfiguration{
+		Recovery: &apiv1.BootstrapRecovery{
+			Source: source.Name,
+			RecoveryTarget: &apiv1.RecoveryTarget{
+				BackupID: backup.Status.BackupID,
+			},
+			Database: source.GetApplicationDatabaseName(),
+			Owner:    source.GetApplicationDatabaseOwner(),
+		},
+	}
+
+	return newClonedCluster(spec, name, namespace)
+}
+
+// NewClonedClusterFromSnapshots builds the definition of a cluster named name
+// in the namespace of the source cluster, bootstrapped from the passed volume
+// snapshots of the source cluster. As for NewClonedCluster, the new cluster
+// has its own credentials and certificates, and doesn't archive its WALs
+func NewClonedClusterFromSnapshots(
+	source *apiv1.Cluster,
+	snapshots *apiv1.DataSource,
+	name, namespace string,
+) *apiv1.Cluster {
+	spec := newClonedSpec(source, namespace)
+	spec.Bootstrap = &apiv1.BootstrapConfiguration{
+		Recovery: &apiv1.BootstrapRecovery{
+			VolumeSnapshots: snapshots.DeepCopy(),
+			Database:        source.GetApplicationDatabaseName(),
+			Owner:           source.GetApplicationDatabaseOwner(),
+		},
+	}
+
+	return newClonedCluster(spec, name, namespace)
+}
+
+// newClonedSpec copies the specification of the source cluster, removing
+// everything that must not be shared with the new cluster
+func newClonedSpec(source *apiv1.Cluster, namespace string) *apiv1.ClusterSpec {
+	spec := source.Spec.DeepCopy()
+
+	// The new cluster gets its own credentials and certificates, and
+	// must never archive its WALs in the object store of the source cluster
+	spec.SuperuserSecret = nil
+	spec.Certificates = nil
+	spec.Backup = nil
+	spec.ReplicaCluster = nil
+
+	// A namespaced image catalog can't be referenced from another
+	// namespace, so the new cluster uses the image resolved from it
+	if spec.ImageCatalogRef != nil && spec.ImageCatalogRef.Kind == apiv1.ImageCatalogKind &&
+		namespace != source.Namespace {
+		spec.ImageCatalogRef = nil
+		spec.ImageName = source.Status.Image
+	}
+
+	return spec
+}
+
+// newClonedCluster builds a cluster with the passed specification. The
+// labels and the annotations of the source cluster are not copied, as
+// they usually belong to the tools managing it, like GitOps controllers
+func newClonedCluster(spec *apiv1.ClusterSpec, name, namespace string) *apiv1.Cluster {
+	return &apiv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiv1.GroupVersion.String(),
+			Kind:       apiv1.ClusterKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Spec: *spec,
+	}
+}
+
+// newVolumeSnapshotReference builds a reference to the volume snapshot
+// with the passed name
+func newVolumeSnapshotReference(name string) corev1.TypedLocalObjectReference {
+	apiGroup := apiv1.VolumeSnapshotAPIGroup
+	return corev1.TypedLocalObjectReference{
+		APIGroup: &apiGroup,
+		Kind:     apiv1.VolumeSnapshotKind,
+		Name:     name,
+	}
+}
+
+// getLatestBackup gets the most recent completed backup of the cluster taken
+// on the object store, or nil if there is none
+func getLatestBackup(clusterName string, backups []apiv1.Backup) *apiv1.Backup {
+	var latest *apiv1.Backup
+	for idx := range backups {
+		backup := &backups[idx]
+		if backup.Spec.Cluster.Name != clusterName ||
+			backup.Spec.GetMethod() != apiv1.BackupMethodBarmanObjectStore ||
+			backup.Status.Phase != apiv1.BackupPhaseCompleted ||
+			backup.Status.BackupID == "" ||
+			backup.Status.StoppedAt == nil {
+			continue
+		}
+
+		if latest == nil || latest.Status.StoppedAt.Before(backup.Status.StoppedAt) {
+			latest = backup
+		}
+	}
+
+	return latest
+}
+
+// getReferencedSecrets gets the names of the secrets referenced by the
+// specification of a cluster, which must exist in its namespace
+func getReferencedSecrets(spec *apiv1.ClusterSpec) []string {
+	var result []string
+	addSelectors := func(selectors ...*apiv1.SecretKeySelector) {
+		for _, selector := range selectors {
+			if selector != nil {
+				result = append(result, selector.Name)
+			}
+		}
+	}
+	addCoreSelectors := func(selectors ...*corev1.SecretKeySelector) {
+		for _, selector := range selectors {
+			if selector != nil {
+				result = append(result, selector.Name)
+			}
+		}
+	}
+
+	for _, reference := range spec.ImagePullSecrets {
+		result = append(result, reference.Name)
+	}
+
+	if ldap := spec.PostgresConfiguration.LDAP; ldap != nil && ldap.BindSearchAuth != nil {
+		addCoreSelectors(ldap.BindSearchAuth.BindPassword)
+	}
+
+	if tde := spec.PostgresConfiguration.TDE; tde != nil {
+		addSelectors(tde.PassphraseSecret)
+	}
+
+	if monitoring := spec.Monitoring; monitoring != nil {
+		for idx := range monitoring.CustomQueriesSecret {
+			addSelectors(&monitoring.CustomQueriesSecret[idx])
+		}
+		addSelectors(monitoring.BearerTokenSecret)
+	}
+
+	for idx := range spec.ExternalClusters {
+		server := &spec.ExternalClusters[idx]
+		addCoreSelectors(server.SSLCert, server.SSLKey, server.SSLRootCert, server.Password)
+		if server.BarmanObjectStore != nil {
+			result = append(result, getObjectStoreSecrets(server.BarmanObjectStore)...)
+		}
+	}
+
+	return result
+}
+
+// getReferencedConfigMaps gets the names of the config maps referenced by
+// the specification of a cluster, which must exist in its namespace
+func getReferencedConfigMaps(spec *apiv1.ClusterSpec) []string {
+	var result []string
+	for _, selector := range spec.PostgresConfiguration.ConfigurationFiles {
+		result = append(result, selector.Name)
+	}
+
+	if monitoring := spec.Monitoring; monitoring != nil {
+		for _, selector := range monitoring.CustomQueriesConfigMap {
+			result = append(result, selector.Name)
+		}
+	}
+
+	return result
+}
+
+// getObjectStoreSecrets gets the names of the secrets needed to access
+// an object store
+func getObjectStoreSecrets(objectStore *apiv1.BarmanObjectStoreConfiguration) []string {
+	var result []string
+	for _, selector := range []*apiv1.SecretKeySelector{
+		objectStore.EndpointCA,
+	} {
+		if selector != nil {
+			result = append(result, selector.Name)
+		}
+	}
+
+	if credentials := objectStore.AWS; credentials != nil {
+		for _, selector := range []*apiv1.SecretKeySelector{
+			credentials.AccessKeyIDReference,
+			credentials.SecretAccessKeyReference,
+			credentials.RegionReference,
+			credentials.SessionToken,
+		} {
+			if selector != nil {
+				result = append(result, selector.Name)
+			}
+		}
+	}
+
+	if credentials := objectStore.Azure; credentials != nil {
+		for _, selector := range []*apiv1.SecretKeySelector{
+			credentials.ConnectionString,
+			credentials.StorageAccount,
+			credentials.StorageKey,
+			credentials.StorageSasToken,
+		} {
+			if selector != nil {
+				result = append(result, selector.Name)
+			}
+		}
+	}
+
+	if credentials := objectStore.Google; credentials != nil && credentials.ApplicationCredentials != nil {
+		result = append(result, credentials.ApplicationCredentials.Name)
+	}
+
+	return result
+}
+
+// copySecrets copies the passed secrets into the target namespace,
+// leaving alone the ones already existing there
+func copySecrets(ctx context.Context, sourceNamespace, targetNamespace string, names []string) error {
+	for _, name := range names {
+		var secret corev1.Secret
+		if err := plugin.Client.Get(
+			ctx,
+			client.ObjectKey{Namespace: sourceNamespace, Name: name},
+			&secret,
+		); err != nil {
+			return fmt.Errorf("while getting the secret %s: %w", name, err)
+		}
+
+		copied := corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: targetNamespace,
+			},
+			Type: secret.Type,
+			Data: secret.Data,
+		}
+		err := plugin.Client.Create(ctx, &copied)
+		switch {
+		case apierrs.IsAlreadyExists(err):
+			fmt.Fprintf(os.Stderr, "secret %s already exists in namespace %s, not copied\n", name, targetNamespace)
+		case err != nil:
+			return fmt.Errorf("while copying the secret %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// copyConfigMaps copies the passed config maps into the target namespace,
+// leaving alone the ones already existing there
+func copyConfigMaps(ctx context.Context, sourceNamespace, targetNamespace string, names []string) error {
+	for _, name := range names {
+		var configMap corev1.ConfigMap
+		if err := plugin.Client.Get(
+			ctx,
+			client.ObjectKey{Namespace: sourceNamespace, Name: name},
+			&configMap,
+		); err != nil {
+			return fmt.Errorf("while getting the config map %s: %w", name, err)
+		}
+
+		copied := corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: targetNamespace,
+			},
+			Data:       configMap.Data,
+			BinaryData: configMap.BinaryData,
+		}
+		err := plugin.Client.Create(ctx, &copied)
+		switch {
+		case apierrs.IsAlreadyExists(err):
+			fmt.Fprintf(os.Stderr, "config map %s already exists in namespace %s, not copied\n", name, targetNamespace)
+		case err != nil:
+			return fmt.Errorf("while copying the config map %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
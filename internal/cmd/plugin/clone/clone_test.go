@@ -0,0 +1,252 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clone
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cluster clone", func() {
+	source := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "production",
+			Labels:    map[string]string{"app": "example"},
+		},
+		Spec: apiv1.ClusterSpec{
+			Instances:       3,
+			SuperuserSecret: &apiv1.LocalObjectReference{Name: "superuser"},
+			Certificates:    &apiv1.CertificatesConfiguration{ServerTLSSecret: "server-tls"},
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				InitDB: &apiv1.BootstrapInitDB{
+					Database: "sales",
+					Owner:    "seller",
+					Secret:   &apiv1.LocalObjectReference{Name: "sales-credentials"},
+				},
+			},
+			Backup: &apiv1.BackupConfiguration{
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					DestinationPath: "s3://backups/",
+					BarmanCredentials: apiv1.BarmanCredentials{
+						AWS: &apiv1.S3Credentials{
+							AccessKeyIDReference: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "aws"},
+								Key:                  "ACCESS_KEY_ID",
+							},
+							SecretAccessKeyReference: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "aws"},
+								Key:                  "ACCESS_SECRET_KEY",
+							},
+						},
+					},
+				},
+			},
+			ExternalClusters: []apiv1.ExternalCluster{
+				{Name: "cluster-example"},
+				{Name: "other"},
+			},
+		},
+	}
+
+	backup := apiv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Name: "20230101T000000", Namespace: "production"},
+		Spec:       apiv1.BackupSpec{Cluster: apiv1.LocalObjectReference{Name: "cluster-example"}},
+		Status: apiv1.BackupStatus{
+			Phase:     apiv1.BackupPhaseCompleted,
+			BackupID:  "20230101T000000",
+			StoppedAt: &metav1.Time{Time: time.Now()},
+		},
+	}
+
+	It("builds a cluster recovering the passed backup with new credentials", func() {
+		target := NewClonedCluster(source, &backup, "cluster-staging", "staging")
+
+		Expect(target.Name).To(Equal("cluster-staging"))
+		Expect(target.Namespace).To(Equal("staging"))
+		Expect(target.Labels).To(BeEmpty())
+		Expect(target.Kind).To(Equal(apiv1.ClusterKind))
+		Expect(target.Spec.Instances).To(Equal(3))
+
+		Expect(target.Spec.Bootstrap.InitDB).To(BeNil())
+		recovery := target.Spec.Bootstrap.Recovery
+		Expect(recovery).ToNot(BeNil())
+		Expect(recovery.Source).To(Equal("cluster-example"))
+		Expect(recovery.RecoveryTarget.BackupID).To(Equal("20230101T000000"))
+		Expect(recovery.Database).To(Equal("sales"))
+		Expect(recovery.Owner).To(Equal("seller"))
+		Expect(recovery.Secret).To(BeNil())
+
+		Expect(target.Spec.SuperuserSecret).To(BeNil())
+		Expect(target.Spec.Certificates).To(BeNil())
+		Expect(target.Spec.Backup).To(BeNil())
+	})
+
+	It("restores from the object store of the source cluster", func() {
+		target := NewClonedCluster(source, &backup, "cluster-staging", "staging")
+
+		Expect(target.Spec.ExternalClusters).To(HaveLen(2))
+		Expect(target.Spec.ExternalClusters[0].Name).To(Equal("cluster-example"))
+		Expect(target.Spec.ExternalClusters[1].Name).To(Equal("other"))
+
+		objectStore := target.Spec.ExternalClusters[0].BarmanObjectStore
+		Expect(objectStore.DestinationPath).To(Equal("s3://backups/"))
+		Expect(objectStore.ServerName).To(Equal("cluster-example"))
+		Expect(source.Spec.Backup.BarmanObjectStore.ServerName).To(BeEmpty())
+	})
+
+	It("chooses the latest completed backup of the cluster", func() {
+		now := time.Now()
+		backups := []apiv1.Backup{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "old", Namespace: "production"},
+				Spec:       apiv1.BackupSpec{Cluster: apiv1.LocalObjectReference{Name: "cluster-example"}},
+				Status: apiv1.BackupStatus{
+					Phase:     apiv1.BackupPhaseCompleted,
+					BackupID:  "old",
+					StoppedAt: &metav1.Time{Time: now.Add(-2 * time.Hour)},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "latest", Namespace: "production"},
+				Spec:       apiv1.BackupSpec{Cluster: apiv1.LocalObjectReference{Name: "cluster-example"}},
+				Status: apiv1.BackupStatus{
+					Phase:     apiv1.BackupPhaseCompleted,
+					BackupID:  "latest",
+					StoppedAt: &metav1.Time{Time: now.Add(-time.Hour)},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "failed", Namespace: "production"},
+				Spec:       apiv1.BackupSpec{Cluster: apiv1.LocalObjectReference{Name: "cluster-example"}},
+				Status: apiv1.BackupStatus{
+					Phase:     apiv1.BackupPhaseFailed,
+					BackupID:  "failed",
+					StoppedAt: &metav1.Time{Time: now},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "production"},
+				Spec:       apiv1.BackupSpec{Cluster: apiv1.LocalObjectReference{Name: "other-cluster"}},
+				Status: apiv1.BackupStatus{
+					Phase:     apiv1.BackupPhaseCompleted,
+					BackupID:  "other",
+					StoppedAt: &metav1.Time{Time: now},
+				},
+			},
+		}
+
+		Expect(getLatestBackup("cluster-example", backups).Name).To(Equal("latest"))
+		Expect(getLatestBackup("missing", backups)).To(BeNil())
+	})
+
+	It("lists the secrets needed to access the object store", func() {
+		Expect(getObjectStoreSecrets(source.Spec.Backup.BarmanObjectStore)).To(Equal([]string{"aws", "aws"}))
+	})
+
+	It("builds a cluster recovering the passed volume snapshots", func() {
+		snapshots := &apiv1.DataSource{
+			Storage: newVolumeSnapshotReference("snapshot"),
+		}
+		target := NewClonedClusterFromSnapshots(source, snapshots, "cluster-staging", "production")
+
+		Expect(target.Namespace).To(Equal("production"))
+		Expect(target.Labels).To(BeEmpty())
+		Expect(target.Spec.ExternalClusters).To(Equal(source.Spec.ExternalClusters))
+
+		recovery := target.Spec.Bootstrap.Recovery
+		Expect(recovery.Source).To(BeEmpty())
+		Expect(recovery.RecoveryTarget).To(BeNil())
+		Expect(recovery.VolumeSnapshots.Storage.Name).To(Equal("snapshot"))
+		Expect(recovery.VolumeSnapshots.Storage.Kind).To(Equal(apiv1.VolumeSnapshotKind))
+		Expect(*recovery.VolumeSnapshots.Storage.APIGroup).To(Equal(apiv1.VolumeSnapshotAPIGroup))
+		Expect(recovery.Database).To(Equal("sales"))
+
+		Expect(target.Spec.SuperuserSecret).To(BeNil())
+		Expect(target.Spec.Certificates).To(BeNil())
+		Expect(target.Spec.Backup).To(BeNil())
+	})
+
+	It("resolves a namespaced image catalog only when cloning into another namespace", func() {
+		cluster := source.DeepCopy()
+		cluster.Spec.ImageCatalogRef = &apiv1.ImageCatalogRef{
+			TypedLocalObjectReference: corev1.TypedLocalObjectReference{
+				Kind: apiv1.ImageCatalogKind,
+				Name: "catalog",
+			},
+			Major: 15,
+		}
+		cluster.Status.Image = "postgres:15.4"
+
+		target := NewClonedCluster(cluster, &backup, "cluster-staging", "production")
+		Expect(target.Spec.ImageCatalogRef).ToNot(BeNil())
+		Expect(target.Spec.ImageName).To(BeEmpty())
+
+		target = NewClonedCluster(cluster, &backup, "cluster-staging", "staging")
+		Expect(target.Spec.ImageCatalogRef).To(BeNil())
+		Expect(target.Spec.ImageName).To(Equal("postgres:15.4"))
+	})
+
+	It("lists the secrets and the config maps referenced by the cluster", func() {
+		spec := apiv1.ClusterSpec{
+			ImagePullSecrets: []apiv1.LocalObjectReference{{Name: "registry"}},
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				ConfigurationFiles: []apiv1.ConfigMapKeySelector{
+					{LocalObjectReference: apiv1.LocalObjectReference{Name: "tuning"}, Key: "tuning.conf"},
+				},
+				LDAP: &apiv1.LDAPConfig{
+					BindSearchAuth: &apiv1.LDAPBindSearchAuth{
+						BindPassword: &corev1.SecretKeySelector{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "ldap"},
+							Key:                  "password",
+						},
+					},
+				},
+			},
+			Monitoring: &apiv1.MonitoringConfiguration{
+				CustomQueriesConfigMap: []apiv1.ConfigMapKeySelector{
+					{LocalObjectReference: apiv1.LocalObjectReference{Name: "queries"}, Key: "queries.yaml"},
+				},
+				CustomQueriesSecret: []apiv1.SecretKeySelector{
+					{LocalObjectReference: apiv1.LocalObjectReference{Name: "secret-queries"}, Key: "queries.yaml"},
+				},
+			},
+			ExternalClusters: []apiv1.ExternalCluster{
+				{
+					Name: "other",
+					Password: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "other-password"},
+						Key:                  "password",
+					},
+					BarmanObjectStore: source.Spec.Backup.BarmanObjectStore,
+				},
+			},
+		}
+
+		Expect(getReferencedSecrets(&spec)).To(Equal([]string{
+			"registry", "ldap", "secret-queries", "other-password", "aws", "aws",
+		}))
+		Expect(getReferencedConfigMaps(&spec)).To(Equal([]string{"tuning", "queries"}))
+	})
+})
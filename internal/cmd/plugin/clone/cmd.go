@@ -0,0 +1,53 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clone
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmd creates the new "clone" subcommand
+func NewCmd() *cobra.Command {
+	var options Options
+
+	cloneCmd := &cobra.Command{
+		Use:   "clone [cluster] [new cluster]",
+		Short: "Create an independent copy of a cluster from its latest backup or from volume snapshots",
+		Long: `Creates a new cluster, in the same or in another namespace, restoring the latest
+completed backup of the source cluster from its object store. Alternatively, the
+new cluster can be created in the same namespace from volume snapshots of the
+source cluster. The new cluster has its own credentials and certificates, and
+doesn't archive its WALs, so that it can be used as a staging environment without
+affecting the source cluster. When the namespace differs, the secrets and the
+config maps referenced by the cluster are copied into the new namespace.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return Clone(cmd.Context(), args[0], args[1], options)
+		},
+	}
+
+	cloneCmd.Flags().StringVar(&options.TargetNamespace, "target-namespace", "",
+		"The namespace of the new cluster, defaulting to the one of the source cluster")
+	cloneCmd.Flags().StringVar(&options.Snapshot, "snapshot", "",
+		"The volume snapshot of the storage to clone the cluster from, instead of its latest backup")
+	cloneCmd.Flags().StringVar(&options.WalSnapshot, "wal-snapshot", "",
+		"The volume snapshot of the WAL storage, required with --snapshot when the cluster has a WAL storage")
+	cloneCmd.Flags().BoolVar(&options.DryRun, "dry-run", false,
+		"Print the definition of the new cluster instead of creating it")
+
+	return cloneCmd
+}
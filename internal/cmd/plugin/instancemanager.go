@@ -0,0 +1,62 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// RunInstanceManagerSubCommand runs an "instance" subcommand of the instance
+// manager inside the Pod of the given instance, which must belong to the
+// given cluster. This requires the permission to execute commands in the Pod
+func RunInstanceManagerSubCommand(
+	ctx context.Context,
+	clusterName, instanceName string,
+	timeout time.Duration,
+	subCommand string,
+) error {
+	var pod corev1.Pod
+
+	err := Client.Get(ctx, client.ObjectKey{Namespace: Namespace, Name: instanceName}, &pod)
+	if err != nil {
+		return err
+	}
+
+	if pod.Labels[utils.ClusterLabelName] != clusterName {
+		return fmt.Errorf("instance %s does not belong to cluster %s", instanceName, clusterName)
+	}
+
+	clientInterface := kubernetes.NewForConfigOrDie(Config)
+	_, _, err = utils.ExecCommand(
+		ctx,
+		clientInterface,
+		Config,
+		pod,
+		specs.PostgresContainerName,
+		&timeout,
+		"/controller/manager", "instance", subCommand)
+	return err
+}
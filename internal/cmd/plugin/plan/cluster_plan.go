@@ -0,0 +1,217 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/controllers"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// ClusterPlan contains the effects of a change of the specification
+// of a cluster on its instances
+type ClusterPlan struct {
+	// Parameters are the PostgreSQL parameters which are changing
+	Parameters []ParameterChange `json:"parameters,omitempty"`
+
+	// HBAChanged is true when the pg_hba.conf file will be changed,
+	// which only requires a reload of the configuration
+	HBAChanged bool `json:"hbaChanged"`
+
+	// RestartRequired is true when the instances will be restarted
+	// to apply the changed parameters
+	RestartRequired bool `json:"restartRequired"`
+
+	// RolloutReasons are the reasons why the Pods of the instances
+	// will be recreated
+	RolloutReasons []string `json:"rolloutReasons,omitempty"`
+
+	// PrimaryUpdate describes how the primary will be updated, when the
+	// instances are restarted or recreated
+	PrimaryUpdate string `json:"primaryUpdate,omitempty"`
+
+	// Instances is the change of the number of instances, if any
+	Instances *ValueChange `json:"instances,omitempty"`
+
+	// Storage is the change of the size of the storage, if any
+	Storage *ValueChange `json:"storage,omitempty"`
+
+	// WalStorage is the change of the size of the WAL storage, if any
+	WalStorage *ValueChange `json:"walStorage,omitempty"`
+}
+
+// ParameterChange is the change of a PostgreSQL parameter
+type ParameterChange struct {
+	Name     string `json:"name"`
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+
+	// RequiresRestart is nil when it was not possible
+	// to ask PostgreSQL the context of the parameter
+	RequiresRestart *bool `json:"requiresRestart,omitempty"`
+}
+
+// ValueChange is the change of a value of the specification
+type ValueChange struct {
+	OldValue string `json:"oldValue"`
+	NewValue string `json:"newValue"`
+}
+
+// IsEmpty checks if the change of the specification has no
+// effect on the instances
+func (plan *ClusterPlan) IsEmpty() bool {
+	return len(plan.Parameters) == 0 && !plan.HBAChanged && len(plan.RolloutReasons) == 0 &&
+		plan.Instances == nil && plan.Storage == nil && plan.WalStorage == nil
+}
+
+// NewClusterPlan computes the effects of changing the specification of the
+// current cluster, whose instances are running in the passed Pods, to the
+// proposed one. The restartParameters are the parameters which PostgreSQL
+// can only change with a restart, nil when they are not known
+func NewClusterPlan(
+	current, proposed *apiv1.Cluster,
+	pods []corev1.Pod,
+	restartParameters map[string]bool,
+) (*ClusterPlan, error) {
+	plan := &ClusterPlan{}
+
+	parameters, err := getParameterChanges(current, proposed, restartParameters)
+	if err != nil {
+		return nil, err
+	}
+	plan.Parameters = parameters
+	for _, parameter := range parameters {
+		if parameter.RequiresRestart != nil && *parameter.RequiresRestart {
+			plan.RestartRequired = true
+		}
+	}
+
+	plan.HBAChanged = !equality.Semantic.DeepEqual(
+		current.Spec.PostgresConfiguration.PgHBA, proposed.Spec.PostgresConfiguration.PgHBA) ||
+		!equality.Semantic.DeepEqual(
+			current.Spec.PostgresConfiguration.LDAP, proposed.Spec.PostgresConfiguration.LDAP)
+
+	rolloutReasons, err := getRolloutReasons(proposed, pods)
+	if err != nil {
+		return nil, err
+	}
+	plan.RolloutReasons = rolloutReasons
+	if plan.RestartRequired || len(plan.RolloutReasons) > 0 {
+		plan.PrimaryUpdate = fmt.Sprintf("the primary is updated last, with the %s method (%s)",
+			proposed.GetPrimaryUpdateMethod(), proposed.GetPrimaryUpdateStrategy())
+	}
+
+	if current.Spec.Instances != proposed.Spec.Instances {
+		plan.Instances = &ValueChange{
+			OldValue: fmt.Sprint(current.Spec.Instances),
+			NewValue: fmt.Sprint(proposed.Spec.Instances),
+		}
+	}
+	plan.Storage = getStorageSizeChange(&current.Spec.StorageConfiguration, &proposed.Spec.StorageConfiguration)
+	plan.WalStorage = getStorageSizeChange(current.Spec.WalStorage, proposed.Spec.WalStorage)
+
+	return plan, nil
+}
+
+// getParameterChanges compares the PostgreSQL configurations generated by the
+// instance manager for the current and the proposed cluster
+func getParameterChanges(
+	current, proposed *apiv1.Cluster,
+	restartParameters map[string]bool,
+) ([]ParameterChange, error) {
+	currentConfiguration, err := postgres.CreatePostgresqlConfiguration(current)
+	if err != nil {
+		return nil, err
+	}
+	proposedConfiguration, err := postgres.CreatePostgresqlConfiguration(proposed)
+	if err != nil {
+		return nil, err
+	}
+
+	currentParameters := currentConfiguration.GetConfigurationParameters()
+	proposedParameters := proposedConfiguration.GetConfigurationParameters()
+
+	names := make(map[string]bool, len(currentParameters))
+	for name := range currentParameters {
+		names[name] = true
+	}
+	for name := range proposedParameters {
+		names[name] = true
+	}
+
+	var changes []ParameterChange
+	for name := range names {
+		oldValue, newValue := currentParameters[name], proposedParameters[name]
+		if oldValue == newValue {
+			continue
+		}
+
+		change := ParameterChange{Name: name, OldValue: oldValue, NewValue: newValue}
+		if restartParameters != nil {
+			requiresRestart := restartParameters[name]
+			change.RequiresRestart = &requiresRestart
+		}
+		changes = append(changes, change)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	return changes, nil
+}
+
+// getRolloutReasons gets the reasons why the operator will recreate the
+// Pods of the instances, comparing them with the proposed cluster as the
+// rolling update does
+func getRolloutReasons(proposed *apiv1.Cluster, pods []corev1.Pod) ([]string, error) {
+	var reasons []string
+	for _, pod := range pods {
+		reason, err := controllers.GetPodSpecChangeReason(proposed, pod)
+		if err != nil {
+			return nil, fmt.Errorf("while checking the Pod %s: %w", pod.Name, err)
+		}
+		if reason != "" && !utils.StringInSlice(reasons, reason) {
+			reasons = append(reasons, reason)
+		}
+	}
+
+	return reasons, nil
+}
+
+// getStorageSizeChange gets the change of the size of a storage,
+// which is applied resizing the PVCs
+func getStorageSizeChange(current, proposed *apiv1.StorageConfiguration) *ValueChange {
+	var currentSize, proposedSize string
+	if current != nil {
+		currentSize = current.Size
+	}
+	if proposed != nil {
+		proposedSize = proposed.Size
+	}
+
+	if currentSize == proposedSize {
+		return nil
+	}
+	return &ValueChange{OldValue: currentSize, NewValue: proposedSize}
+}
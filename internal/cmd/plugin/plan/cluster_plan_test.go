@@ -0,0 +1,159 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Cluster plan", func() {
+	current := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			Instances: 3,
+			ImageName: "ghcr.io/cloudnative-pg/postgresql:14.5",
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				Parameters: map[string]string{
+					"shared_buffers": "256MB",
+					"work_mem":       "4MB",
+				},
+			},
+			StorageConfiguration: apiv1.StorageConfiguration{Size: "1Gi"},
+		},
+	}
+	restartParameters := map[string]bool{"shared_buffers": true}
+	pods := []corev1.Pod{
+		*specs.PodWithExistingStorage(*current, 1),
+		*specs.PodWithExistingStorage(*current, 2),
+		*specs.PodWithExistingStorage(*current, 3),
+	}
+
+	It("has no effect when the definition doesn't change", func() {
+		plan, err := NewClusterPlan(current, current.DeepCopy(), pods, restartParameters)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.IsEmpty()).To(BeTrue())
+		Expect(plan.PrimaryUpdate).To(BeEmpty())
+	})
+
+	It("reports the parameters which can be changed with a reload", func() {
+		proposed := current.DeepCopy()
+		proposed.Spec.PostgresConfiguration.Parameters["work_mem"] = "8MB"
+
+		plan, err := NewClusterPlan(current, proposed, pods, restartParameters)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.Parameters).To(HaveLen(1))
+		Expect(plan.Parameters[0].Name).To(Equal("work_mem"))
+		Expect(plan.Parameters[0].OldValue).To(Equal("4MB"))
+		Expect(plan.Parameters[0].NewValue).To(Equal("8MB"))
+		Expect(*plan.Parameters[0].RequiresRestart).To(BeFalse())
+		Expect(plan.RestartRequired).To(BeFalse())
+		Expect(plan.PrimaryUpdate).To(BeEmpty())
+	})
+
+	It("reports a restart when a parameter requires it", func() {
+		proposed := current.DeepCopy()
+		proposed.Spec.PostgresConfiguration.Parameters["shared_buffers"] = "512MB"
+
+		plan, err := NewClusterPlan(current, proposed, pods, restartParameters)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.RestartRequired).To(BeTrue())
+		Expect(plan.PrimaryUpdate).To(ContainSubstring("switchover"))
+	})
+
+	It("doesn't know if a restart is needed without the parameters context", func() {
+		proposed := current.DeepCopy()
+		proposed.Spec.PostgresConfiguration.Parameters["shared_buffers"] = "512MB"
+
+		plan, err := NewClusterPlan(current, proposed, pods, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.Parameters[0].RequiresRestart).To(BeNil())
+		Expect(plan.RestartRequired).To(BeFalse())
+	})
+
+	It("reports a rollout when the image changes", func() {
+		proposed := current.DeepCopy()
+		proposed.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:14.6"
+
+		plan, err := NewClusterPlan(current, proposed, pods, restartParameters)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.RolloutReasons).To(ConsistOf(
+			"the instance is using an old image: ghcr.io/cloudnative-pg/postgresql:14.5 -> " +
+				"ghcr.io/cloudnative-pg/postgresql:14.6",
+		))
+		Expect(plan.PrimaryUpdate).ToNot(BeEmpty())
+	})
+
+	It("reports a rollout when the resources change", func() {
+		proposed := current.DeepCopy()
+		proposed.Spec.Resources = corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		}
+
+		plan, err := NewClusterPlan(current, proposed, pods, restartParameters)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.RolloutReasons).To(HaveLen(1))
+		Expect(plan.RolloutReasons[0]).To(HavePrefix("resources changed"))
+	})
+
+	It("doesn't report a rollout when the resources of the Pods already satisfy the request", func() {
+		podsWithResources := make([]corev1.Pod, len(pods))
+		for idx := range pods {
+			pods[idx].DeepCopyInto(&podsWithResources[idx])
+			podsWithResources[idx].Spec.Containers[0].Resources = corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			}
+		}
+		proposed := current.DeepCopy()
+		proposed.Spec.Resources = corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+		}
+
+		plan, err := NewClusterPlan(current, proposed, podsWithResources, restartParameters)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.RolloutReasons).To(BeEmpty())
+	})
+
+	It("reports the changes of the pg_hba rules, the instances and the storage", func() {
+		proposed := current.DeepCopy()
+		proposed.Spec.PostgresConfiguration.PgHBA = []string{"host all all 10.0.0.0/8 md5"}
+		proposed.Spec.Instances = 5
+		proposed.Spec.StorageConfiguration.Size = "2Gi"
+
+		plan, err := NewClusterPlan(current, proposed, pods, restartParameters)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.HBAChanged).To(BeTrue())
+		Expect(plan.Instances).To(Equal(&ValueChange{OldValue: "3", NewValue: "5"}))
+		Expect(plan.Storage).To(Equal(&ValueChange{OldValue: "1Gi", NewValue: "2Gi"}))
+		Expect(plan.WalStorage).To(BeNil())
+		Expect(plan.RolloutReasons).To(BeEmpty())
+	})
+
+	It("parses the parameters requiring a restart", func() {
+		Expect(parseRestartParameters("max_connections\nshared_buffers\n\n")).To(Equal(map[string]bool{
+			"max_connections": true,
+			"shared_buffers":  true,
+		}))
+	})
+})
@@ -0,0 +1,55 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd creates the new "plan" subcommand
+func NewCmd() *cobra.Command {
+	planCmd := &cobra.Command{
+		Use:   "plan [cluster] -f [file]",
+		Short: "Show the effects of a change of the cluster definition before applying it",
+		Long: `Compares the definition of the cluster contained in the file with the current one,
+showing the PostgreSQL parameters that will change, and if the instances will be
+reloaded, restarted or recreated. The change is validated by the operator with a
+dry-run update, and is not applied.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			clusterName := args[0]
+
+			fileName, _ := cmd.Flags().GetString("filename")
+			output, _ := cmd.Flags().GetString("output")
+
+			return Plan(ctx, clusterName, fileName, plugin.OutputFormat(output))
+		},
+	}
+
+	planCmd.Flags().StringP(
+		"filename", "f", "", "The file containing the new definition of the cluster")
+	_ = planCmd.MarkFlagRequired("filename")
+	planCmd.Flags().StringP(
+		"output", "o", "text", "Output format. One of text|json|yaml")
+
+	return planCmd
+}
@@ -0,0 +1,212 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plan implements the kubectl-cnpg plan command, showing the
+// effects of a change of the specification of a cluster before applying it
+package plan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cheynewallace/tabby"
+	"github.com/logrusorgru/aurora/v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// Plan implements the "plan" subcommand, showing the effects of applying
+// the cluster definition contained in the passed file
+func Plan(ctx context.Context, clusterName, fileName string, format plugin.OutputFormat) error {
+	var current apiv1.Cluster
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName},
+		&current,
+	); err != nil {
+		return fmt.Errorf("while getting the cluster %s: %w", clusterName, err)
+	}
+
+	proposed, err := getProposedCluster(ctx, &current, fileName)
+	if err != nil {
+		return err
+	}
+
+	var pods corev1.PodList
+	if err := plugin.Client.List(
+		ctx,
+		&pods,
+		client.InNamespace(current.Namespace),
+		client.MatchingLabels{
+			utils.ClusterLabelName: current.Name,
+			utils.PodRoleLabelName: string(utils.PodRoleInstance),
+		},
+	); err != nil {
+		return fmt.Errorf("while getting the Pods of the cluster %s: %w", clusterName, err)
+	}
+
+	restartParameters, err := getRestartParameters(ctx, &current)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Cannot get the parameters requiring a restart from the primary: %v\n", err)
+	}
+
+	plan, err := NewClusterPlan(&current, proposed, pods.Items, restartParameters)
+	if err != nil {
+		return err
+	}
+
+	if format != plugin.OutputFormatText {
+		return plugin.Print(plan, format, os.Stdout)
+	}
+
+	printPlan(plan)
+	return nil
+}
+
+// getProposedCluster reads the proposed definition of the cluster, and
+// submits it to the API server as a dry-run update. This way the operator
+// webhooks apply the defaults and validate the change, without persisting it
+func getProposedCluster(ctx context.Context, current *apiv1.Cluster, fileName string) (*apiv1.Cluster, error) {
+	content, err := os.ReadFile(fileName) // #nosec
+	if err != nil {
+		return nil, err
+	}
+
+	var proposed apiv1.Cluster
+	if err := yaml.UnmarshalStrict(content, &proposed); err != nil {
+		return nil, fmt.Errorf("while parsing %s: %w", fileName, err)
+	}
+
+	if proposed.Name != "" && proposed.Name != current.Name {
+		return nil, fmt.Errorf("the definition in %s is for the cluster %s, not %s",
+			fileName, proposed.Name, current.Name)
+	}
+	proposed.Name = current.Name
+	proposed.Namespace = current.Namespace
+	proposed.ResourceVersion = current.ResourceVersion
+
+	if err := plugin.Client.Update(ctx, &proposed, client.DryRunAll); err != nil {
+		return nil, fmt.Errorf("the change has been rejected: %w", err)
+	}
+
+	// The status is needed to generate the configuration,
+	// e.g. to get the synchronous standby names
+	proposed.Status = current.Status
+	return &proposed, nil
+}
+
+// getRestartParameters asks the primary the PostgreSQL parameters
+// which can only be changed restarting the server
+func getRestartParameters(ctx context.Context, cluster *apiv1.Cluster) (map[string]bool, error) {
+	var primary corev1.Pod
+	if err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.Status.CurrentPrimary},
+		&primary,
+	); err != nil {
+		return nil, err
+	}
+
+	timeout := 10 * time.Second
+	stdout, _, err := utils.ExecCommand(
+		ctx,
+		kubernetes.NewForConfigOrDie(plugin.Config),
+		plugin.Config,
+		primary,
+		specs.PostgresContainerName,
+		&timeout,
+		"psql", "-AtX", "-c",
+		"SELECT name FROM pg_catalog.pg_settings WHERE context = 'postmaster'")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRestartParameters(stdout), nil
+}
+
+// parseRestartParameters parses the names of the parameters, one per line
+func parseRestartParameters(output string) map[string]bool {
+	result := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		if name := strings.TrimSpace(line); name != "" {
+			result[name] = true
+		}
+	}
+	return result
+}
+
+// printPlan prints the plan in a human-readable way
+func printPlan(plan *ClusterPlan) {
+	if plan.IsEmpty() {
+		fmt.Println(aurora.Green("The change has no effect on the instances"))
+		return
+	}
+
+	if len(plan.Parameters) > 0 {
+		fmt.Println(aurora.Green("PostgreSQL parameters"))
+		parameters := tabby.New()
+		parameters.AddHeader("Name", "Old value", "New value", "Restart")
+		for _, parameter := range plan.Parameters {
+			restart := "unknown"
+			if parameter.RequiresRestart != nil {
+				restart = "no"
+				if *parameter.RequiresRestart {
+					restart = "yes"
+				}
+			}
+			parameters.AddLine(parameter.Name, parameter.OldValue, parameter.NewValue, restart)
+		}
+		parameters.Print()
+		fmt.Println()
+	}
+
+	fmt.Println(aurora.Green("Effects on the instances"))
+	summary := tabby.New()
+	if plan.HBAChanged {
+		summary.AddLine("pg_hba.conf:", "changed, the configuration will be reloaded")
+	}
+	if plan.RestartRequired {
+		summary.AddLine("Restart:", "the instances will be restarted to apply the parameters")
+	}
+	for _, reason := range plan.RolloutReasons {
+		summary.AddLine("Rollout:", "the Pods will be recreated because "+reason)
+	}
+	if plan.PrimaryUpdate != "" {
+		summary.AddLine("Primary:", plan.PrimaryUpdate)
+	}
+	if plan.Instances != nil {
+		summary.AddLine("Instances:", fmt.Sprintf("%s -> %s", plan.Instances.OldValue, plan.Instances.NewValue))
+	}
+	if plan.Storage != nil {
+		summary.AddLine("Storage:", fmt.Sprintf("%s -> %s, the PVCs will be resized",
+			plan.Storage.OldValue, plan.Storage.NewValue))
+	}
+	if plan.WalStorage != nil {
+		summary.AddLine("WAL storage:", fmt.Sprintf("%s -> %s, the PVCs will be resized",
+			plan.WalStorage.OldValue, plan.WalStorage.NewValue))
+	}
+	summary.Print()
+}
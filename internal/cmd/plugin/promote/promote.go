@@ -53,9 +53,16 @@ func Promote(ctx context.Context, clusterName string, serverName string) error {
 		return fmt.Errorf("new primary node %s not found in namespace %s", serverName, plugin.Namespace)
 	}
 
+	// The cluster is waiting for the user to approve a failover,
+	// let's approve it for the requested instance
+	if cluster.Status.Phase == apiv1.PhaseWaitingForFailoverApproval {
+		return approveFailover(ctx, &cluster, serverName)
+	}
+
 	// The Pod exists, let's update status fields
 	cluster.Status.TargetPrimary = serverName
 	cluster.Status.TargetPrimaryTimestamp = utils.GetCurrentTimestamp()
+	cluster.Status.SwitchoverTarget = serverName
 	cluster.Status.Phase = apiv1.PhaseSwitchover
 	cluster.Status.PhaseReason = fmt.Sprintf("Switching over to %v", serverName)
 
@@ -67,3 +74,21 @@ func Promote(ctx context.Context, clusterName string, serverName string) error {
 	fmt.Printf("Node %s in cluster %s will be promoted\n", serverName, clusterName)
 	return nil
 }
+
+// approveFailover approves the pending failover of a cluster
+// having a manual failover policy, promoting the passed instance
+func approveFailover(ctx context.Context, cluster *apiv1.Cluster, serverName string) error {
+	origCluster := cluster.DeepCopy()
+	if cluster.Annotations == nil {
+		cluster.Annotations = make(map[string]string)
+	}
+	cluster.Annotations[utils.FailoverApprovalAnnotationName] = serverName
+
+	err := plugin.Client.Patch(ctx, cluster, client.MergeFrom(origCluster))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Failover of cluster %s approved, node %s will be promoted\n", cluster.Name, serverName)
+	return nil
+}
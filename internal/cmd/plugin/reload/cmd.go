@@ -25,13 +25,18 @@ import (
 // NewCmd creates the new "reset" command
 func NewCmd() *cobra.Command {
 	restartCmd := &cobra.Command{
-		Use:   "reload [clusterName]",
-		Short: `Reload the cluster`,
-		Long:  `Triggers a reconciliation loop for all the cluster's instances, rolling out new configurations if present.`,
-		Args:  cobra.ExactArgs(1),
+		Use:   "reload [clusterName] [instance]",
+		Short: `Reload the cluster or a single instance`,
+		Long: `Triggers a reconciliation loop for all the cluster's instances, rolling out new configurations if present.
+If an instance is specified, the PostgreSQL configuration of that instance is reloaded
+through the instance manager, without involving the operator.`,
+		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := context.Background()
 			clusterName := args[0]
+			if len(args) == 2 {
+				return ReloadInstance(ctx, clusterName, args[1])
+			}
 			return Reload(ctx, clusterName)
 		},
 	}
@@ -20,6 +20,7 @@ package reload
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -54,3 +55,15 @@ func Reload(ctx context.Context, clusterName string) error {
 	fmt.Printf("%s will be reloaded\n", clusterRestarted.Name)
 	return nil
 }
+
+// ReloadInstance asks the instance manager of the given instance to reload
+// the PostgreSQL configuration
+func ReloadInstance(ctx context.Context, clusterName, instanceName string) error {
+	err := plugin.RunInstanceManagerSubCommand(ctx, clusterName, instanceName, 10*time.Second, "reload")
+	if err != nil {
+		return fmt.Errorf("while reloading instance %s: %w", instanceName, err)
+	}
+
+	fmt.Printf("%s has been reloaded\n", instanceName)
+	return nil
+}
@@ -25,27 +25,44 @@ import (
 
 // NewCmd creates the new "reset" command
 func NewCmd() *cobra.Command {
+	var postgresOnly bool
+
 	restartCmd := &cobra.Command{
 		Use:   "restart clusterName [instance]",
 		Short: `Restart a cluster or a single instance in a cluster`,
 		Long: `If only the cluster name is specified, the whole cluster will be restarted, 
 rolling out new configurations if present.
 If a specific instance is specified, only that instance will be restarted, 
-in-place if it is a primary, deleting the pod if it is a replica.`,
+in-place if it is a primary, deleting the pod if it is a replica.
+With --postgres-only, only PostgreSQL is restarted inside the pod of the
+instance, through the instance manager, without involving the operator.`,
 		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
 			clusterName := args[0]
 			if len(args) == 1 {
+				if postgresOnly {
+					return fmt.Errorf("--postgres-only requires the name of the instance")
+				}
 				return restart(ctx, clusterName)
 			}
 			node := args[1]
 			if _, err := strconv.Atoi(args[1]); err == nil {
 				node = fmt.Sprintf("%s-%s", clusterName, node)
 			}
+			if postgresOnly {
+				return postgresRestart(ctx, clusterName, node)
+			}
 			return instanceRestart(ctx, clusterName, node)
 		},
 	}
 
+	restartCmd.Flags().BoolVar(
+		&postgresOnly,
+		"postgres-only",
+		false,
+		"Restart only PostgreSQL inside the pod of the instance, without deleting the pod",
+	)
+
 	return restartCmd
 }
@@ -87,3 +87,15 @@ func instanceRestart(ctx context.Context, clusterName, node string) error {
 	fmt.Printf("instance %s restarted\n", node)
 	return nil
 }
+
+// postgresRestart asks the instance manager of the given instance to restart
+// PostgreSQL, waiting for it to accept connections again
+func postgresRestart(ctx context.Context, clusterName, node string) error {
+	err := plugin.RunInstanceManagerSubCommand(ctx, clusterName, node, 5*time.Minute, "restart")
+	if err != nil {
+		return fmt.Errorf("while restarting PostgreSQL in instance %s: %w", node, err)
+	}
+
+	fmt.Printf("PostgreSQL in instance %s restarted\n", node)
+	return nil
+}
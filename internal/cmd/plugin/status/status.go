@@ -24,6 +24,7 @@ import (
 	"os"
 	"path"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/cheynewallace/tabby"
@@ -177,6 +178,9 @@ func (fullStatus *PostgresqlStatus) printBasicInfo() {
 	} else {
 		summary.AddLine("Ready instances:", aurora.Red(cluster.Status.ReadyInstances))
 	}
+	if zones := cluster.Status.Topology.GetZones(); len(zones) > 0 {
+		summary.AddLine("Zones:", strings.Join(zones, ", "))
+	}
 
 	if cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
 		if cluster.Status.CurrentPrimary == "" {
@@ -247,6 +251,16 @@ func (fullStatus *PostgresqlStatus) printBackupStatus() {
 		FPoR = "Not Available"
 	}
 	status.AddLine("First Point of Recoverability:", FPoR)
+	lastSuccessfulBackup := cluster.Status.LastSuccessfulBackup
+	if lastSuccessfulBackup == "" {
+		lastSuccessfulBackup = "-"
+	}
+	status.AddLine("Last Successful Backup:", lastSuccessfulBackup)
+	lastFailedBackup := cluster.Status.LastFailedBackup
+	if lastFailedBackup == "" {
+		lastFailedBackup = "-"
+	}
+	status.AddLine("Last Failed Backup:", lastFailedBackup)
 
 	primaryInstanceStatus := fullStatus.tryGetPrimaryInstance()
 	if primaryInstanceStatus == nil {
@@ -385,7 +399,15 @@ func (fullStatus *PostgresqlStatus) printInstancesStatus() {
 			continue
 		}
 		statusMsg := "OK"
-		if instance.PendingRestart {
+		reportedState := fullStatus.Cluster.Status.InstancesReportedState[apiv1.PodName(instance.Pod.Name)]
+		if reportedState.Health == apiv1.InstanceHealthDegraded {
+			statusMsg = fmt.Sprintf("Degraded (%s)", reportedState.HealthReason)
+		}
+		switch {
+		case instance.PendingRestart && len(instance.PendingRestartParameters) > 0:
+			statusMsg += fmt.Sprintf(" (pending restart: %s)",
+				strings.Join(instance.PendingRestartParameters, ", "))
+		case instance.PendingRestart:
 			statusMsg += " (pending restart)"
 		}
 
@@ -20,7 +20,12 @@ package configuration
 
 import (
 	"path"
+	"reflect"
 	"strings"
+	"sync/atomic"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configparser"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -32,6 +37,16 @@ var configurationLog = log.WithName("configuration")
 // DefaultOperatorPullSecretName is implicitly copied into newly created clusters.
 const DefaultOperatorPullSecretName = "cnpg-pull-secret" // #nosec
 
+const (
+	// DefaultRequestsCPU is the CPU requested by default by the
+	// PostgreSQL containers of the new clusters
+	DefaultRequestsCPU = "100m"
+
+	// DefaultRequestsMemory is the memory requested by default by the
+	// PostgreSQL containers of the new clusters
+	DefaultRequestsMemory = "256Mi"
+)
+
 // Data is the struct containing the configuration of the operator.
 // Usually the operator code will use the "Current" configuration.
 type Data struct {
@@ -84,10 +99,51 @@ type Data struct {
 	// MonitoringQueriesSecret is the name of the secret in the operator namespace which contain
 	// the monitoring queries. The queries will be read from the data key: "queries".
 	MonitoringQueriesSecret string `json:"monitoringQueriesSecret" env:"MONITORING_QUERIES_SECRET"`
+
+	// DefaultRequestsCPU is the amount of CPU requested by the PostgreSQL
+	// containers of the clusters not specifying their resources
+	DefaultRequestsCPU string `json:"defaultRequestsCPU" env:"DEFAULT_REQUESTS_CPU"`
+
+	// DefaultRequestsMemory is the amount of memory requested by the PostgreSQL
+	// containers of the clusters not specifying their resources
+	DefaultRequestsMemory string `json:"defaultRequestsMemory" env:"DEFAULT_REQUESTS_MEMORY"`
+
+	// DefaultLimitsCPU is the CPU limit of the PostgreSQL containers
+	// of the clusters not specifying their resources
+	DefaultLimitsCPU string `json:"defaultLimitsCPU" env:"DEFAULT_LIMITS_CPU"`
+
+	// DefaultLimitsMemory is the memory limit of the PostgreSQL containers
+	// of the clusters not specifying their resources
+	DefaultLimitsMemory string `json:"defaultLimitsMemory" env:"DEFAULT_LIMITS_MEMORY"`
+
+	// NotificationWebhookURL is the URL where the operator posts the
+	// lifecycle events of the clusters, such as failovers and failed backups
+	NotificationWebhookURL string `json:"notificationWebhookURL" env:"NOTIFICATION_WEBHOOK_URL"`
+
+	// NotificationPayloadTemplate is the Go template used to build the
+	// payload posted to the notification webhook
+	NotificationPayloadTemplate string `json:"notificationPayloadTemplate" env:"NOTIFICATION_PAYLOAD_TEMPLATE"`
 }
 
-// Current is the configuration used by the operator
-var Current = NewConfiguration()
+// current is the configuration used by the operator, which is
+// replaced as a whole when the operator configuration is reloaded
+var current atomic.Value
+
+func init() {
+	current.Store(NewConfiguration())
+}
+
+// Current gets the configuration used by the operator. The returned
+// configuration is shared and must not be changed: use SetCurrent to
+// replace it
+func Current() *Data {
+	return current.Load().(*Data)
+}
+
+// SetCurrent replaces the configuration used by the operator
+func SetCurrent(data *Data) {
+	current.Store(data)
+}
 
 // newDefaultConfig creates a configuration holding the defaults
 func newDefaultConfig() *Data {
@@ -95,6 +151,8 @@ func newDefaultConfig() *Data {
 		OperatorPullSecretName: DefaultOperatorPullSecretName,
 		OperatorImageName:      versions.DefaultOperatorImageName,
 		PostgresImageName:      versions.DefaultImageName,
+		DefaultRequestsCPU:     DefaultRequestsCPU,
+		DefaultRequestsMemory:  DefaultRequestsMemory,
 	}
 }
 
@@ -111,6 +169,46 @@ func (config *Data) ReadConfigMap(data map[string]string) {
 	configparser.ReadConfigMap(config, newDefaultConfig(), data, configparser.OsEnvironment{})
 }
 
+// redactedValue replaces the redacted values of the configuration
+const redactedValue = "<redacted>"
+
+// Redacted gets a copy of the configuration where the values of the
+// passed options, usually the ones read from a Secret, are replaced by
+// a placeholder, so that it can be logged
+func (config *Data) Redacted(options []string) *Data {
+	result := *config
+	value := reflect.ValueOf(&result).Elem()
+	for i := 0; i < value.NumField(); i++ {
+		envName := value.Type().Field(i).Tag.Get("env")
+		if envName == "" || !stringSliceContains(options, envName) {
+			continue
+		}
+
+		field := value.Field(i)
+		switch field.Kind() {
+		case reflect.String:
+			if field.String() != "" {
+				field.SetString(redactedValue)
+			}
+		case reflect.Slice:
+			if field.Len() > 0 {
+				field.Set(reflect.ValueOf([]string{redactedValue}))
+			}
+		}
+	}
+
+	return &result
+}
+
+func stringSliceContains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
 // IsAnnotationInherited checks if an annotation with a certain name should
 // be inherited from the Cluster specification to the generated objects
 func (config *Data) IsAnnotationInherited(name string) bool {
@@ -123,6 +221,22 @@ func (config *Data) IsLabelInherited(name string) bool {
 	return evaluateGlobPatterns(config.InheritedLabels, name)
 }
 
+// GetDefaultResources gets the resources to be used by the PostgreSQL
+// containers of the clusters not specifying them. Invalid quantities
+// are skipped
+func (config *Data) GetDefaultResources() corev1.ResourceRequirements {
+	var result corev1.ResourceRequirements
+	result.Requests = parseResourceList(map[corev1.ResourceName]string{
+		corev1.ResourceCPU:    config.DefaultRequestsCPU,
+		corev1.ResourceMemory: config.DefaultRequestsMemory,
+	})
+	result.Limits = parseResourceList(map[corev1.ResourceName]string{
+		corev1.ResourceCPU:    config.DefaultLimitsCPU,
+		corev1.ResourceMemory: config.DefaultLimitsMemory,
+	})
+	return result
+}
+
 // WatchedNamespaces get the list of additional watched namespaces.
 // The result is a list of namespaces specified in the WATCHED_NAMESPACE where
 // each namespace is separated by comma
@@ -144,6 +258,30 @@ func cleanNamespaceList(namespaces string) (result []string) {
 	return
 }
 
+func parseResourceList(values map[corev1.ResourceName]string) corev1.ResourceList {
+	var result corev1.ResourceList
+	for name, value := range values {
+		if value == "" {
+			continue
+		}
+
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			configurationLog.Info(
+				"Skipping invalid quantity in the default resources",
+				"resource", name, "value", value)
+			continue
+		}
+
+		if result == nil {
+			result = make(corev1.ResourceList)
+		}
+		result[name] = quantity
+	}
+
+	return result
+}
+
 func evaluateGlobPatterns(patterns []string, value string) (result bool) {
 	var err error
 
@@ -17,6 +17,9 @@ limitations under the License.
 package configuration
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -128,3 +131,95 @@ var _ = Describe("Annotation and label inheritance", func() {
 		})
 	})
 })
+
+var _ = Describe("Default resources", func() {
+	It("is empty when not configured", func() {
+		config := Data{}
+		resources := config.GetDefaultResources()
+		Expect(resources.Requests).To(BeNil())
+		Expect(resources.Limits).To(BeNil())
+	})
+
+	It("requests small resources by default", func() {
+		config := newDefaultConfig()
+		resources := config.GetDefaultResources()
+		Expect(resources.Requests).To(Equal(corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse(DefaultRequestsCPU),
+			corev1.ResourceMemory: resource.MustParse(DefaultRequestsMemory),
+		}))
+		Expect(resources.Limits).To(BeNil())
+	})
+
+	It("can be disabled with empty values in the configuration", func() {
+		config := newDefaultConfig()
+		config.ReadConfigMap(map[string]string{
+			"DEFAULT_REQUESTS_CPU":    "",
+			"DEFAULT_REQUESTS_MEMORY": "",
+		})
+		Expect(config.GetDefaultResources().Requests).To(BeNil())
+	})
+
+	It("parses the configured quantities", func() {
+		config := Data{
+			DefaultRequestsCPU:    "500m",
+			DefaultRequestsMemory: "512Mi",
+			DefaultLimitsMemory:   "1Gi",
+		}
+		resources := config.GetDefaultResources()
+		Expect(resources.Requests).To(Equal(corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		}))
+		Expect(resources.Limits).To(Equal(corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		}))
+	})
+
+	It("skips invalid quantities", func() {
+		config := Data{
+			DefaultRequestsCPU:    "one",
+			DefaultRequestsMemory: "512Mi",
+		}
+		resources := config.GetDefaultResources()
+		Expect(resources.Requests).To(Equal(corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		}))
+	})
+})
+
+var _ = Describe("current configuration", func() {
+	It("is replaced as a whole", func() {
+		previous := Current()
+		DeferCleanup(SetCurrent, previous)
+
+		replacement := newDefaultConfig()
+		replacement.OperatorPullSecretName = "other-pull-secret"
+		SetCurrent(replacement)
+		Expect(Current().OperatorPullSecretName).To(Equal("other-pull-secret"))
+		Expect(previous.OperatorPullSecretName).To(Equal(DefaultOperatorPullSecretName))
+	})
+})
+
+var _ = Describe("redacted configuration", func() {
+	It("hides the values of the passed options", func() {
+		config := newDefaultConfig()
+		config.NotificationWebhookURL = "https://hooks.example.com/token"
+		config.InheritedLabels = []string{"environment"}
+		config.EnablePodDebugging = true
+
+		redacted := config.Redacted([]string{"NOTIFICATION_WEBHOOK_URL", "INHERITED_LABELS", "POD_DEBUG"})
+		Expect(redacted.NotificationWebhookURL).To(Equal(redactedValue))
+		Expect(redacted.InheritedLabels).To(Equal([]string{redactedValue}))
+		Expect(redacted.EnablePodDebugging).To(BeTrue())
+		Expect(redacted.PostgresImageName).To(Equal(config.PostgresImageName))
+
+		Expect(config.NotificationWebhookURL).To(Equal("https://hooks.example.com/token"))
+		Expect(config.InheritedLabels).To(Equal([]string{"environment"}))
+	})
+
+	It("keeps the empty values", func() {
+		redacted := newDefaultConfig().Redacted([]string{"NOTIFICATION_WEBHOOK_URL", "INHERITED_LABELS"})
+		Expect(redacted.NotificationWebhookURL).To(BeEmpty())
+		Expect(redacted.InheritedLabels).To(BeEmpty())
+	})
+})
@@ -19,6 +19,7 @@ limitations under the License.
 package cache
 
 import (
+	"strings"
 	"sync"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -31,8 +32,25 @@ const (
 	WALArchiveKey = "wal-archive"
 	// WALRestoreKey is the key to be used to access the cached envs for wal-restore
 	WALRestoreKey = "wal-restore"
+	// WALPluginKey is the key to be used to access the cached name of
+	// the plugin archiving and restoring the WAL files
+	WALPluginKey = "wal-plugin"
+	// AdditionalWALArchiveKeyPrefix is the prefix of the keys to be used to access
+	// the cached envs for wal-archive in the additional object stores
+	AdditionalWALArchiveKeyPrefix = "wal-archive-additional-"
 )
 
+// GetAdditionalWALArchiveKey gets the key to be used to access the cached
+// envs for wal-archive in an additional object store
+func GetAdditionalWALArchiveKey(name string) string {
+	return AdditionalWALArchiveKeyPrefix + name
+}
+
+// IsEnvKey checks whether a key is used to access cached envs
+func IsEnvKey(key string) bool {
+	return key == WALArchiveKey || key == WALRestoreKey || strings.HasPrefix(key, AdditionalWALArchiveKeyPrefix)
+}
+
 var cache sync.Map
 
 // Store write an object into the local cache
@@ -72,3 +90,19 @@ func LoadCluster() (*apiv1.Cluster, error) {
 
 	return nil, ErrUnsupportedObject
 }
+
+// LoadWALPlugin loads the name of the plugin archiving and restoring the
+// WAL files from the local cache. The name is empty when no plugin of the
+// cluster has the wal capability
+func LoadWALPlugin() (string, error) {
+	value, ok := cache.Load(WALPluginKey)
+	if !ok {
+		return "", ErrCacheMiss
+	}
+
+	if v, ok := value.(string); ok {
+		return v, nil
+	}
+
+	return "", ErrUnsupportedObject
+}
@@ -62,6 +62,23 @@ func GetEnv(key string) ([]string, error) {
 	return env, nil
 }
 
+// GetWALPlugin gets from cache the name of the plugin archiving and
+// restoring the WAL files, which is empty when there is no such plugin
+func GetWALPlugin() (string, error) {
+	bytes, err := httpCacheGet(cache.WALPluginKey)
+	if err != nil {
+		return "", err
+	}
+
+	var name string
+	err = json.Unmarshal(bytes, &name)
+	if err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
 // httpCacheGet retrieves an object from the cache.
 // In case of failures it retries for a while before giving up
 func httpCacheGet(urlPath string) ([]byte, error) {
@@ -0,0 +1,106 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// alterSystemCheckPeriod is the interval between two checks of the
+// settings changed with ALTER SYSTEM
+const alterSystemCheckPeriod = 10 * time.Second
+
+// AlterSystemWatchdog implements the Runnable interface and, when
+// ALTER SYSTEM is disabled in the cluster, periodically removes the
+// settings changed with it from "postgresql.auto.conf", reloading the
+// configuration and emitting an event on the cluster
+type AlterSystemWatchdog struct {
+	instance *postgres.Instance
+	client   ctrl.Client
+	recorder record.EventRecorder
+}
+
+// NewAlterSystemWatchdog creates a new AlterSystemWatchdog for an instance
+func NewAlterSystemWatchdog(
+	instance *postgres.Instance,
+	client ctrl.Client,
+	recorder record.EventRecorder,
+) *AlterSystemWatchdog {
+	return &AlterSystemWatchdog{
+		instance: instance,
+		client:   client,
+		recorder: recorder,
+	}
+}
+
+// Start starts checking the settings changed with ALTER SYSTEM
+func (w *AlterSystemWatchdog) Start(ctx context.Context) error {
+	return runPeriodically(ctx, alterSystemCheckPeriod,
+		"Cannot revert the settings changed with ALTER SYSTEM", w.revertAlterSystem)
+}
+
+// revertAlterSystem removes the settings changed with ALTER SYSTEM,
+// if it is disabled in the cluster
+func (w *AlterSystemWatchdog) revertAlterSystem(ctx context.Context) error {
+	var cluster apiv1.Cluster
+	if err := w.client.Get(
+		ctx,
+		ctrl.ObjectKey{Namespace: w.instance.Namespace, Name: w.instance.ClusterName},
+		&cluster,
+	); err != nil {
+		return err
+	}
+
+	if !cluster.Spec.PostgresConfiguration.DisableAlterSystem {
+		return nil
+	}
+
+	// While PostgreSQL is running, the settings are reset through
+	// PostgreSQL itself, which serializes the changes to the file
+	isRunning := !w.instance.IsFenced() && w.instance.IsServerHealthy() == nil
+
+	var removedOptions []string
+	var err error
+	if isRunning {
+		removedOptions, err = w.instance.ResetAlterSystemSettings(ctx)
+	} else {
+		removedOptions, err = postgres.RemoveAlterSystemSettingsFromPostgresAutoConf(w.instance.PgData)
+	}
+	if err != nil || len(removedOptions) == 0 {
+		return err
+	}
+
+	log.FromContext(ctx).Warning("Reverted the settings changed with ALTER SYSTEM",
+		"options", removedOptions)
+	w.recorder.Eventf(&cluster, "Warning", "AlterSystemReverted",
+		"The settings changed with ALTER SYSTEM on %s have been reverted: %s",
+		w.instance.PodName, strings.Join(removedOptions, ", "))
+
+	if !isRunning {
+		return nil
+	}
+	return w.instance.Reload()
+}
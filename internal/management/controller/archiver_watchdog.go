@@ -0,0 +1,146 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// archiverCheckPeriod is the interval between two checks
+// of the WAL archiver status
+const archiverCheckPeriod = 30 * time.Second
+
+// ArchiverWatchdog implements the Runnable interface and periodically checks
+// the status of the WAL archiver on the primary instance, setting the
+// ContinuousArchiving condition of the cluster to false when the last
+// archiving attempt failed.
+//
+// When the archiving is failing and the object store configuration changes,
+// the archiver is woken up to retry the stuck WAL file immediately, instead
+// of waiting for the next retry scheduled by PostgreSQL.
+type ArchiverWatchdog struct {
+	instance *postgres.Instance
+	client   ctrl.Client
+
+	// objectStore is the object store configuration used in the last check
+	objectStore *apiv1.BarmanObjectStoreConfiguration
+
+	// checked is true after the first check of the cluster
+	checked bool
+}
+
+// NewArchiverWatchdog creates a new ArchiverWatchdog for an instance
+func NewArchiverWatchdog(instance *postgres.Instance, client ctrl.Client) *ArchiverWatchdog {
+	return &ArchiverWatchdog{
+		instance: instance,
+		client:   client,
+	}
+}
+
+// Start starts checking the status of the WAL archiver
+func (w *ArchiverWatchdog) Start(ctx context.Context) error {
+	return runPeriodically(ctx, archiverCheckPeriod,
+		"Cannot check the status of the WAL archiver", w.checkArchiver)
+}
+
+// checkArchiver checks the status of the WAL archiver, updating the
+// ContinuousArchiving condition of the cluster when it is failing
+func (w *ArchiverWatchdog) checkArchiver(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx)
+
+	if isPrimary, err := w.instance.IsPrimary(); err != nil || !isPrimary {
+		return err
+	}
+	if w.instance.IsFenced() || w.instance.IsServerHealthy() != nil {
+		return nil
+	}
+
+	var cluster apiv1.Cluster
+	if err := w.client.Get(
+		ctx,
+		ctrl.ObjectKey{Namespace: w.instance.Namespace, Name: w.instance.ClusterName},
+		&cluster,
+	); err != nil {
+		return err
+	}
+
+	var objectStore *apiv1.BarmanObjectStoreConfiguration
+	if cluster.Spec.Backup != nil {
+		objectStore = cluster.Spec.Backup.BarmanObjectStore
+	}
+	objectStoreChanged := w.checked && !equality.Semantic.DeepEqual(w.objectStore, objectStore)
+	w.objectStore = objectStore
+	w.checked = true
+	if objectStore == nil {
+		return nil
+	}
+
+	status, err := w.instance.GetArchiverStatus()
+	if err != nil {
+		return err
+	}
+
+	condition := getArchiverFailingCondition(status)
+	if condition == nil {
+		return nil
+	}
+
+	contextLogger.Warning("WAL archiving is failing",
+		"lastFailedWAL", status.LastFailedWAL,
+		"lastFailedTime", status.LastFailedTime,
+		"failedCount", status.FailedCount)
+	if err := manager.UpdateCondition(ctx, w.client, &cluster, condition); err != nil {
+		return err
+	}
+
+	if objectStoreChanged {
+		// Reloading the configuration wakes up the archiver, which
+		// will retry archiving the failed WAL file
+		contextLogger.Info("The object store configuration changed, retrying WAL archiving",
+			"lastFailedWAL", status.LastFailedWAL)
+		return w.instance.Reload()
+	}
+
+	return nil
+}
+
+// getArchiverFailingCondition gets the ContinuousArchiving condition to be
+// set when the status of the archiver reports a failure, or nil otherwise
+func getArchiverFailingCondition(status *postgres.ArchiverStatus) *metav1.Condition {
+	if !status.IsFailing {
+		return nil
+	}
+
+	return &metav1.Condition{
+		Type:   string(apiv1.ConditionContinuousArchiving),
+		Status: metav1.ConditionFalse,
+		Reason: string(apiv1.ConditionReasonContinuousArchivingFailing),
+		Message: fmt.Sprintf("Cannot archive the WAL file %s, last failure at %s",
+			status.LastFailedWAL, status.LastFailedTime),
+	}
+}
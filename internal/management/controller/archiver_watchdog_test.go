@@ -0,0 +1,50 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL archiver watchdog", func() {
+	It("does not change the condition when the archiving is working", func() {
+		status := &postgres.ArchiverStatus{ArchivedCount: 10, FailedCount: 2}
+		Expect(getArchiverFailingCondition(status)).To(BeNil())
+	})
+
+	It("reports the failed WAL file when the archiving is failing", func() {
+		status := &postgres.ArchiverStatus{
+			ArchivedCount:  10,
+			FailedCount:    3,
+			LastFailedWAL:  "000000010000000000000005",
+			LastFailedTime: "2023-01-01 10:00:00+00",
+			IsFailing:      true,
+		}
+		condition := getArchiverFailingCondition(status)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Type).To(Equal(string(apiv1.ConditionContinuousArchiving)))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonContinuousArchivingFailing)))
+		Expect(condition.Message).To(ContainSubstring("000000010000000000000005"))
+	})
+})
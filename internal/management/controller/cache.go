@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"os"
+	"reflect"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 
@@ -28,6 +29,8 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
 	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 )
 
 // updateCacheFromCluster will update the internal cache with the cluster
@@ -47,9 +50,49 @@ func (r *InstanceReconciler) updateCacheFromCluster(
 
 	// Populate the cache with the recover configuration
 	r.updateWALRestoreSettingsCache(ctx, cluster)
+
+	// Populate the cache with the plugin managing the WAL files
+	r.updateWALPluginCache(ctx, cluster)
 	return requeue
 }
 
+// updateWALPluginCache stores in the cache the name of the plugin, running
+// as a sidecar of the instance, which archives and restores the WAL files,
+// so that wal-archive and wal-restore don't need to look for it for every
+// WAL file. The plugins are looked up again when their list changes, or
+// while no plugin with the wal capability has been found, as the sidecars
+// may still be starting
+func (r *InstanceReconciler) updateWALPluginCache(ctx context.Context, cluster *apiv1.Cluster) {
+	pluginNames := make([]string, len(cluster.Spec.Plugins))
+	for idx := range cluster.Spec.Plugins {
+		pluginNames[idx] = cluster.Spec.Plugins[idx].Name
+	}
+
+	if len(pluginNames) == 0 {
+		cache.Delete(cache.WALPluginKey)
+		r.walPluginCandidates = nil
+		return
+	}
+	if reflect.DeepEqual(pluginNames, r.walPluginCandidates) {
+		return
+	}
+
+	pluginClient, pluginConfiguration, err := plugin.FindLocal(ctx, cluster, plugin.CapabilityWAL)
+	if err != nil {
+		log.Error(err, "while looking for the WAL plugin")
+		return
+	}
+	if pluginClient == nil {
+		cache.Store(cache.WALPluginKey, "")
+		r.walPluginCandidates = nil
+		return
+	}
+	_ = pluginClient.Close()
+
+	cache.Store(cache.WALPluginKey, pluginConfiguration.Name)
+	r.walPluginCandidates = pluginNames
+}
+
 func (r *InstanceReconciler) updateWALRestoreSettingsCache(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -86,6 +129,8 @@ func (r *InstanceReconciler) shouldUpdateWALArchiveSettingsCache(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
 ) (shouldRetry bool) {
+	r.updateAdditionalWALArchiveSettingsCache(ctx, cluster)
+
 	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
 		cache.Delete(cache.WALArchiveKey)
 		return false
@@ -111,3 +156,44 @@ func (r *InstanceReconciler) shouldUpdateWALArchiveSettingsCache(
 	cache.Store(cache.WALArchiveKey, envArchive)
 	return false
 }
+
+// updateAdditionalWALArchiveSettingsCache updates the cache with the
+// credentials of the additional object stores, removing the ones of
+// the object stores which are not used anymore
+func (r *InstanceReconciler) updateAdditionalWALArchiveSettingsCache(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) {
+	var stores []apiv1.AdditionalObjectStore
+	if cluster.Spec.Backup != nil {
+		stores = cluster.Spec.Backup.AdditionalObjectStores
+	}
+
+	cachedNames := stringset.New()
+	for idx := range stores {
+		store := &stores[idx]
+		envArchive, err := barmanCredentials.EnvSetBackupCloudCredentials(
+			ctx,
+			r.GetClient(),
+			cluster.Namespace,
+			&store.BarmanObjectStore,
+			os.Environ())
+		if err != nil {
+			log.Error(err, "while getting the credentials of an additional object store",
+				"objectStore", store.Name)
+			continue
+		}
+
+		cache.Store(cache.GetAdditionalWALArchiveKey(store.Name), envArchive)
+		cachedNames.Put(store.Name)
+	}
+
+	if r.additionalObjectStores != nil {
+		for _, name := range r.additionalObjectStores.ToList() {
+			if !cachedNames.Has(name) {
+				cache.Delete(cache.GetAdditionalWALArchiveKey(name))
+			}
+		}
+	}
+	r.additionalObjectStores = cachedNames
+}
@@ -0,0 +1,54 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL plugin cache", func() {
+	AfterEach(func() {
+		cache.Delete(cache.WALPluginKey)
+	})
+
+	It("doesn't cache a WAL plugin when the cluster has no plugins", func() {
+		r := &InstanceReconciler{}
+		r.updateWALPluginCache(context.TODO(), &apiv1.Cluster{})
+
+		_, err := cache.LoadWALPlugin()
+		Expect(err).To(Equal(cache.ErrCacheMiss))
+	})
+
+	It("looks for the WAL plugin again while it is not found", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Plugins: []apiv1.PluginConfiguration{{Name: "missing.example.com"}},
+			},
+		}
+		r := &InstanceReconciler{}
+		r.updateWALPluginCache(context.TODO(), cluster)
+
+		Expect(cache.LoadWALPlugin()).To(BeEmpty())
+		Expect(r.walPluginCandidates).To(BeNil())
+	})
+})
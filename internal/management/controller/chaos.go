@@ -0,0 +1,199 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// chaosExperimentCheckPeriod is the interval between two checks
+// of the chaos experiment requested on the cluster
+const chaosExperimentCheckPeriod = 5 * time.Second
+
+// ChaosExperimentRunner implements the Runnable interface and executes
+// the chaos experiments requested on the instance, when chaos testing is
+// enabled in the cluster. The simulated failures are reverted when the
+// experiments end, and every step is recorded with an event on the cluster
+type ChaosExperimentRunner struct {
+	instance *postgres.Instance
+	client   ctrl.Client
+	recorder record.EventRecorder
+
+	// the experiment running on the instance, if any
+	current *utils.ChaosExperiment
+
+	// true when the replication lag may have been injected and
+	// needs to be reverted
+	lagInjected bool
+
+	// true after the replication lag left by a previous execution
+	// of the instance manager has been checked
+	staleLagChecked bool
+}
+
+// NewChaosExperimentRunner creates a new ChaosExperimentRunner for an instance
+func NewChaosExperimentRunner(
+	instance *postgres.Instance,
+	client ctrl.Client,
+	recorder record.EventRecorder,
+) *ChaosExperimentRunner {
+	return &ChaosExperimentRunner{
+		instance: instance,
+		client:   client,
+		recorder: recorder,
+	}
+}
+
+// Start starts executing the chaos experiments
+func (r *ChaosExperimentRunner) Start(ctx context.Context) error {
+	return runPeriodically(ctx, chaosExperimentCheckPeriod,
+		"Cannot execute the chaos experiment", r.run)
+}
+
+// run starts, continues or ends the chaos experiment on the instance
+func (r *ChaosExperimentRunner) run(ctx context.Context) error {
+	var cluster apiv1.Cluster
+	if err := r.client.Get(
+		ctx,
+		ctrl.ObjectKey{Namespace: r.instance.Namespace, Name: r.instance.ClusterName},
+		&cluster,
+	); err != nil {
+		return err
+	}
+
+	// An instance manager restarted in the middle of an experiment may have
+	// left the replication lag in place
+	if !r.staleLagChecked {
+		r.staleLagChecked = true
+		r.lagInjected = cluster.Spec.EnableChaosTesting
+	}
+
+	experiment := cluster.GetActiveChaosExperiment(r.instance.PodName, time.Now())
+	if r.current != nil && (experiment == nil || !equality.Semantic.DeepEqual(*r.current, *experiment)) {
+		if err := r.endExperiment(ctx, &cluster); err != nil {
+			return err
+		}
+	}
+
+	if experiment == nil || experiment.Action != utils.ChaosExperimentReplicationLag {
+		if err := r.revertReplicationLag(); err != nil {
+			return err
+		}
+	}
+
+	if experiment == nil {
+		return nil
+	}
+
+	// The failure is simulated starting from the next check, to be sure
+	// the event announcing it has been recorded
+	if r.current == nil {
+		r.current = experiment
+		log.FromContext(ctx).Warning("Starting the chaos experiment", "experiment", experiment)
+		r.recorder.Eventf(&cluster, "Warning", "ChaosExperimentStarted",
+			"Chaos experiment %s started on %s, ending at %s",
+			experiment.Action, r.instance.PodName,
+			experiment.StartedAt.Add(experiment.Duration.Duration).Format(time.RFC3339))
+		return nil
+	}
+
+	if r.instance.IsFenced() {
+		return nil
+	}
+
+	switch experiment.Action {
+	case utils.ChaosExperimentKillPostgres:
+		return r.killPostgres(ctx, experiment)
+	case utils.ChaosExperimentReplicationLag:
+		return r.injectReplicationLag(experiment)
+	}
+
+	// The WAL archiving is paused by the archive command
+	return nil
+}
+
+// endExperiment reverts the failure simulated by the current experiment
+func (r *ChaosExperimentRunner) endExperiment(ctx context.Context, cluster *apiv1.Cluster) error {
+	if r.current.Action == utils.ChaosExperimentReplicationLag {
+		if err := r.revertReplicationLag(); err != nil {
+			return err
+		}
+	}
+
+	log.FromContext(ctx).Info("The chaos experiment ended", "experiment", r.current)
+	r.recorder.Eventf(cluster, "Normal", "ChaosExperimentEnded",
+		"Chaos experiment %s ended on %s", r.current.Action, r.instance.PodName)
+	r.current = nil
+	return nil
+}
+
+// killPostgres kills PostgreSQL, unless it has already been restarted
+// after the beginning of the experiment
+func (r *ChaosExperimentRunner) killPostgres(ctx context.Context, experiment *utils.ChaosExperiment) error {
+	startTime, err := r.instance.GetPostmasterStartTime()
+	if err != nil {
+		return err
+	}
+	if !startTime.Before(experiment.StartedAt.Time) {
+		return nil
+	}
+
+	log.FromContext(ctx).Warning("Killing PostgreSQL for the chaos experiment", "experiment", experiment)
+	return r.instance.KillPostmaster()
+}
+
+// injectReplicationLag delays the replay of the WAL, when the instance is a replica
+func (r *ChaosExperimentRunner) injectReplicationLag(experiment *utils.ChaosExperiment) error {
+	if isPrimary, err := r.instance.IsPrimary(); err != nil || isPrimary {
+		return err
+	}
+
+	r.lagInjected = true
+	changed, err := postgres.SetRecoveryMinApplyDelay(r.instance.PgData, experiment.Delay.Duration)
+	if err != nil || !changed {
+		return err
+	}
+	return r.instance.Reload()
+}
+
+// revertReplicationLag removes the replication lag, if injected
+func (r *ChaosExperimentRunner) revertReplicationLag() error {
+	if !r.lagInjected {
+		return nil
+	}
+
+	changed, err := postgres.SetRecoveryMinApplyDelay(r.instance.PgData, 0)
+	if err != nil {
+		return err
+	}
+	r.lagInjected = false
+
+	if !changed || r.instance.IsFenced() || r.instance.IsServerHealthy() != nil {
+		return nil
+	}
+	return r.instance.Reload()
+}
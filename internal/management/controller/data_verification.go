@@ -0,0 +1,370 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// dataVerificationCheckPeriod is the interval between two checks
+// of the schedule of the data verification
+const dataVerificationCheckPeriod = 30 * time.Second
+
+// maxReportedFindings is the maximum number of corruption
+// findings reported in the events and in the conditions
+const maxReportedFindings = 5
+
+// DataVerificationWatchdog implements the Runnable interface and runs the
+// verification of the data of the instance following the schedule defined
+// in the cluster, reporting the corruptions which have been found as events,
+// as the DataVerification condition of the cluster and as metrics.
+//
+// The `amcheck` verification runs on the running standbys, and on the
+// primary only when requested. The `checksums` one runs on a standby at a
+// time, in turn: the instance manager of the chosen standby fences it,
+// verifies the data once PostgreSQL has been shut down, and unfences it.
+type DataVerificationWatchdog struct {
+	instance *postgres.Instance
+	client   ctrl.Client
+	recorder record.EventRecorder
+
+	// schedule is the schedule used to compute the next verification
+	schedule string
+
+	// nextVerification is when the next verification is due
+	nextVerification time.Time
+}
+
+// NewDataVerificationWatchdog creates a new DataVerificationWatchdog for an instance
+func NewDataVerificationWatchdog(
+	instance *postgres.Instance,
+	client ctrl.Client,
+	recorder record.EventRecorder,
+) *DataVerificationWatchdog {
+	return &DataVerificationWatchdog{
+		instance: instance,
+		client:   client,
+		recorder: recorder,
+	}
+}
+
+// Start starts checking the schedule of the data verification
+func (w *DataVerificationWatchdog) Start(ctx context.Context) error {
+	return runPeriodically(ctx, dataVerificationCheckPeriod,
+		"Cannot verify the data of the instance", func(ctx context.Context) error {
+			return w.checkSchedule(ctx, time.Now())
+		})
+}
+
+// checkSchedule runs the data verification when it is due
+func (w *DataVerificationWatchdog) checkSchedule(ctx context.Context, now time.Time) error {
+	var cluster apiv1.Cluster
+	if err := w.client.Get(
+		ctx,
+		ctrl.ObjectKey{Namespace: w.instance.Namespace, Name: w.instance.ClusterName},
+		&cluster,
+	); err != nil {
+		return err
+	}
+
+	// This instance may have been fenced to verify its checksums
+	// before the instance manager was restarted
+	fencedInstance := cluster.Annotations[utils.DataVerificationFencedInstanceAnnotation]
+	if fencedInstance == w.instance.PodName {
+		return w.verifyChecksums(ctx, &cluster)
+	}
+
+	configuration := cluster.Spec.DataVerification
+	if configuration == nil {
+		w.schedule = ""
+		return nil
+	}
+
+	if !w.isVerificationDue(configuration.Schedule, now) {
+		return nil
+	}
+
+	if configuration.GetMethod() == apiv1.DataVerificationMethodChecksums {
+		if getChecksumsVerificationTarget(&cluster) != w.instance.PodName {
+			return nil
+		}
+		if fencedInstance != "" {
+			log.FromContext(ctx).Info("Skipping the verification of the checksums, "+
+				"another instance is being verified", "instance", fencedInstance)
+			return nil
+		}
+		if w.instance.IsFenced() {
+			// The instance has already been fenced by the user
+			return w.verifyChecksums(ctx, &cluster)
+		}
+
+		log.FromContext(ctx).Info("Fencing the instance to verify the data checksums")
+		return w.setVerificationFencing(ctx, true)
+	}
+
+	if !configuration.IncludePrimary {
+		isPrimary, err := w.instance.IsPrimary()
+		if err != nil {
+			return err
+		}
+		if isPrimary {
+			log.FromContext(ctx).Debug("Skipping the verification of the primary")
+			return nil
+		}
+	}
+
+	if w.instance.IsFenced() || w.instance.IsServerHealthy() != nil {
+		log.FromContext(ctx).Debug("Skipping the verification of an instance which is not running")
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Verifying the data using amcheck")
+	result, err := w.instance.VerifyDataWithAmcheck(ctx)
+	if err != nil {
+		return err
+	}
+
+	return w.recordResult(ctx, &cluster, result)
+}
+
+// isVerificationDue checks if the verification is due, scheduling
+// the next one. The first verification is scheduled at the first
+// occurrence of the schedule after the watchdog started or the
+// schedule changed
+func (w *DataVerificationWatchdog) isVerificationDue(schedule string, now time.Time) bool {
+	if schedule != w.schedule {
+		parsedSchedule, err := cron.Parse(schedule)
+		if err != nil {
+			return false
+		}
+		w.schedule = schedule
+		w.nextVerification = parsedSchedule.Next(now)
+		return false
+	}
+
+	if now.Before(w.nextVerification) {
+		return false
+	}
+
+	parsedSchedule, err := cron.Parse(schedule)
+	if err != nil {
+		return false
+	}
+	w.nextVerification = parsedSchedule.Next(now)
+	return true
+}
+
+// verifyChecksums verifies the data checksums of the fenced instance, as
+// soon as PostgreSQL has been shut down, unfencing it at the end of the
+// verification when it was fenced by the instance manager
+func (w *DataVerificationWatchdog) verifyChecksums(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	// pg_checksums requires PostgreSQL to be shut down
+	isRunning, err := fileutils.FileExists(path.Join(w.instance.PgData, postgres.PostgresqlPidFile))
+	if err != nil {
+		return err
+	}
+	if !w.instance.IsFenced() || isRunning {
+		contextLogger.Debug("Waiting for PostgreSQL to be shut down to verify the checksums")
+		return nil
+	}
+
+	contextLogger.Info("Verifying the data checksums")
+	result, verifyErr := w.instance.VerifyDataChecksums(ctx)
+	if verifyErr == nil {
+		verifyErr = w.recordResult(ctx, cluster, result)
+	}
+
+	if cluster.Annotations[utils.DataVerificationFencedInstanceAnnotation] != w.instance.PodName {
+		return verifyErr
+	}
+
+	contextLogger.Info("Unfencing the instance after the verification of the data checksums")
+	if err := w.setVerificationFencing(ctx, false); err != nil {
+		return err
+	}
+
+	return verifyErr
+}
+
+// recordResult makes the result of a verification available to the
+// metrics and reports it in the cluster
+func (w *DataVerificationWatchdog) recordResult(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	result *postgres.DataVerificationResult,
+) error {
+	w.instance.SetDataVerificationResult(result)
+	return w.reportResult(ctx, cluster, result)
+}
+
+// setVerificationFencing fences or unfences this instance to verify
+// its checksums, keeping track of it in the cluster annotations
+func (w *DataVerificationWatchdog) setVerificationFencing(ctx context.Context, enabled bool) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		var cluster apiv1.Cluster
+		if err := w.client.Get(
+			ctx,
+			ctrl.ObjectKey{Namespace: w.instance.Namespace, Name: w.instance.ClusterName},
+			&cluster,
+		); err != nil {
+			return err
+		}
+
+		updatedCluster := cluster.DeepCopy()
+		if enabled {
+			if err := utils.AddFencedInstance(w.instance.PodName, &updatedCluster.ObjectMeta); err != nil {
+				return err
+			}
+			updatedCluster.Annotations[utils.DataVerificationFencedInstanceAnnotation] = w.instance.PodName
+		} else {
+			err := utils.RemoveFencedInstance(w.instance.PodName, &updatedCluster.ObjectMeta)
+			if err != nil && !errors.Is(err, utils.ErrorServerAlreadyUnfenced) {
+				return err
+			}
+			delete(updatedCluster.Annotations, utils.DataVerificationFencedInstanceAnnotation)
+		}
+
+		return w.client.Patch(ctx, updatedCluster, ctrl.MergeFromWithOptions(&cluster, ctrl.MergeFromWithOptimisticLock{}))
+	})
+}
+
+// getChecksumsVerificationTarget gets the instance whose checksums
+// are to be verified: the healthy instances are verified in turn,
+// starting after the last verified one, and skipping the primary
+// unless requested
+func getChecksumsVerificationTarget(cluster *apiv1.Cluster) string {
+	var candidates []string
+	for _, name := range cluster.Status.InstancesStatus[utils.PodHealthy] {
+		isPrimary := name == cluster.Status.CurrentPrimary || name == cluster.Status.TargetPrimary
+		if isPrimary && !cluster.Spec.DataVerification.IncludePrimary {
+			continue
+		}
+		candidates = append(candidates, name)
+	}
+	if len(candidates) == 0 {
+		return ""
+	}
+	sort.Strings(candidates)
+
+	var lastVerified string
+	if condition := meta.FindStatusCondition(
+		cluster.Status.Conditions,
+		string(apiv1.ConditionDataVerification),
+	); condition != nil {
+		lastVerified, _, _ = strings.Cut(condition.Message, ":")
+	}
+
+	for _, name := range candidates {
+		if name > lastVerified {
+			return name
+		}
+	}
+	return candidates[0]
+}
+
+// reportResult emits an event and updates the DataVerification
+// condition of the cluster with the result of the verification
+func (w *DataVerificationWatchdog) reportResult(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	result *postgres.DataVerificationResult,
+) error {
+	if result.IsCorrupted() {
+		log.FromContext(ctx).Warning("Data corruption detected",
+			"method", result.Method,
+			"findings", result.Findings)
+		w.recorder.Eventf(cluster, "Warning", string(apiv1.ConditionReasonDataCorruptionDetected),
+			"%s", getDataVerificationMessage(w.instance.PodName, result))
+	} else {
+		w.recorder.Eventf(cluster, "Normal", string(apiv1.ConditionReasonDataVerificationSucceeded),
+			"%s", getDataVerificationMessage(w.instance.PodName, result))
+	}
+
+	condition := getDataVerificationCondition(
+		meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionDataVerification)),
+		w.instance.PodName,
+		result)
+	return manager.UpdateCondition(ctx, w.client, cluster, condition)
+}
+
+// getDataVerificationCondition gets the DataVerification condition to be set
+// after a verification. The condition reporting a corruption is cleared only
+// by a successful verification of the same instance
+func getDataVerificationCondition(
+	existing *metav1.Condition,
+	podName string,
+	result *postgres.DataVerificationResult,
+) *metav1.Condition {
+	message := getDataVerificationMessage(podName, result)
+	if result.IsCorrupted() {
+		return &metav1.Condition{
+			Type:    string(apiv1.ConditionDataVerification),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonDataCorruptionDetected),
+			Message: message,
+		}
+	}
+
+	if existing != nil && existing.Status == metav1.ConditionFalse &&
+		!strings.HasPrefix(existing.Message, podName+":") {
+		return nil
+	}
+
+	return &metav1.Condition{
+		Type:    string(apiv1.ConditionDataVerification),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonDataVerificationSucceeded),
+		Message: message,
+	}
+}
+
+// getDataVerificationMessage gets the message describing the
+// result of a verification, prefixed by the name of the instance
+func getDataVerificationMessage(podName string, result *postgres.DataVerificationResult) string {
+	if !result.IsCorrupted() {
+		return fmt.Sprintf("%s: no corruption found by the %s verification", podName, result.Method)
+	}
+
+	findings := result.Findings
+	if len(findings) > maxReportedFindings {
+		findings = findings[:maxReportedFindings]
+	}
+	return fmt.Sprintf("%s: %d corruptions found by the %s verification: %s",
+		podName, len(result.Findings), result.Method, strings.Join(findings, "; "))
+}
@@ -0,0 +1,132 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("data verification watchdog", func() {
+	corrupted := &postgres.DataVerificationResult{
+		Method:   postgres.DataVerificationMethodAmcheck,
+		Findings: []string{"1", "2", "3", "4", "5", "6"},
+	}
+	healthy := &postgres.DataVerificationResult{
+		Method: postgres.DataVerificationMethodAmcheck,
+	}
+
+	It("runs the verification following the schedule", func() {
+		watchdog := NewDataVerificationWatchdog(postgres.NewInstance(), nil, nil)
+		now := time.Date(2022, 10, 10, 12, 0, 0, 0, time.UTC)
+
+		// The first verification is scheduled at the next occurrence
+		Expect(watchdog.isVerificationDue("0 0 * * * *", now)).To(BeFalse())
+		Expect(watchdog.isVerificationDue("0 0 * * * *", now.Add(30*time.Minute))).To(BeFalse())
+		Expect(watchdog.isVerificationDue("0 0 * * * *", now.Add(time.Hour))).To(BeTrue())
+		Expect(watchdog.isVerificationDue("0 0 * * * *", now.Add(time.Hour+time.Minute))).To(BeFalse())
+
+		// Changing the schedule reschedules the verification
+		Expect(watchdog.isVerificationDue("0 30 * * * *", now.Add(2*time.Hour))).To(BeFalse())
+		Expect(watchdog.isVerificationDue("0 30 * * * *", now.Add(150*time.Minute))).To(BeTrue())
+	})
+
+	It("ignores invalid schedules", func() {
+		watchdog := NewDataVerificationWatchdog(postgres.NewInstance(), nil, nil)
+		Expect(watchdog.isVerificationDue("invalid", time.Now())).To(BeFalse())
+		Expect(watchdog.isVerificationDue("invalid", time.Now().Add(time.Hour))).To(BeFalse())
+	})
+
+	It("limits the number of findings in the messages", func() {
+		message := getDataVerificationMessage("cluster-example-1", corrupted)
+		Expect(message).To(HavePrefix("cluster-example-1: 6 corruptions found"))
+		Expect(message).To(HaveSuffix("1; 2; 3; 4; 5"))
+	})
+
+	It("reports a corruption", func() {
+		condition := getDataVerificationCondition(nil, "cluster-example-1", corrupted)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonDataCorruptionDetected)))
+	})
+
+	It("clears a corruption only after verifying the same instance", func() {
+		existing := getDataVerificationCondition(nil, "cluster-example-1", corrupted)
+		Expect(getDataVerificationCondition(existing, "cluster-example-2", healthy)).To(BeNil())
+
+		condition := getDataVerificationCondition(existing, "cluster-example-1", healthy)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonDataVerificationSucceeded)))
+	})
+
+	It("verifies the checksums of the healthy standbys in turn", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				DataVerification: &apiv1.DataVerificationConfiguration{
+					Method: apiv1.DataVerificationMethodChecksums,
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+				InstancesStatus: map[utils.PodStatus][]string{
+					utils.PodHealthy: {"cluster-example-3", "cluster-example-1", "cluster-example-2"},
+					utils.PodFailed:  {"cluster-example-4"},
+				},
+			},
+		}
+		Expect(getChecksumsVerificationTarget(cluster)).To(Equal("cluster-example-2"))
+
+		cluster.Status.Conditions = []metav1.Condition{
+			*getDataVerificationCondition(nil, "cluster-example-2", healthy),
+		}
+		Expect(getChecksumsVerificationTarget(cluster)).To(Equal("cluster-example-3"))
+
+		cluster.Status.Conditions = []metav1.Condition{
+			*getDataVerificationCondition(nil, "cluster-example-3", healthy),
+		}
+		Expect(getChecksumsVerificationTarget(cluster)).To(Equal("cluster-example-2"))
+	})
+
+	It("verifies the checksums of the primary only when requested", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				DataVerification: &apiv1.DataVerificationConfiguration{
+					Method: apiv1.DataVerificationMethodChecksums,
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+				InstancesStatus: map[utils.PodStatus][]string{
+					utils.PodHealthy: {"cluster-example-1"},
+				},
+			},
+		}
+		Expect(getChecksumsVerificationTarget(cluster)).To(BeEmpty())
+
+		cluster.Spec.DataVerification.IncludePrimary = true
+		Expect(getChecksumsVerificationTarget(cluster)).To(Equal("cluster-example-1"))
+	})
+})
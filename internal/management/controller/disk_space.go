@@ -0,0 +1,171 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// diskSpaceCheckPeriod is the interval between two checks
+// of the disk space used by the volumes of the instance
+const diskSpaceCheckPeriod = 30 * time.Second
+
+// diskSpaceLevel is the severity of the disk space usage of a volume
+type diskSpaceLevel int
+
+const (
+	diskSpaceLevelOk diskSpaceLevel = iota
+	diskSpaceLevelWarning
+	diskSpaceLevelCritical
+)
+
+// DiskSpaceWatchdog implements the Runnable interface and periodically
+// checks the disk space used by the volumes of the instance, emitting
+// an event on the cluster every time the usage of a volume crosses the
+// warning or the critical threshold.
+//
+// When requested, the primary instance runs a CHECKPOINT while the volume
+// containing the WAL files is above the critical threshold, allowing
+// PostgreSQL to remove the WAL files which are not needed anymore.
+type DiskSpaceWatchdog struct {
+	instance *postgres.Instance
+	client   ctrl.Client
+	recorder record.EventRecorder
+
+	// levels contains the last detected severity of each volume
+	levels map[string]diskSpaceLevel
+}
+
+// NewDiskSpaceWatchdog creates a new DiskSpaceWatchdog for an instance
+func NewDiskSpaceWatchdog(
+	instance *postgres.Instance,
+	client ctrl.Client,
+	recorder record.EventRecorder,
+) *DiskSpaceWatchdog {
+	return &DiskSpaceWatchdog{
+		instance: instance,
+		client:   client,
+		recorder: recorder,
+		levels:   make(map[string]diskSpaceLevel),
+	}
+}
+
+// Start starts checking the disk space used by the volumes
+func (w *DiskSpaceWatchdog) Start(ctx context.Context) error {
+	return runPeriodically(ctx, diskSpaceCheckPeriod,
+		"Cannot check the disk space of the volumes", w.checkDiskSpace)
+}
+
+// checkDiskSpace checks the disk space used by the volumes of the
+// instance, emitting the events and taking the protective actions
+func (w *DiskSpaceWatchdog) checkDiskSpace(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx)
+
+	var cluster apiv1.Cluster
+	if err := w.client.Get(
+		ctx,
+		ctrl.ObjectKey{Namespace: w.instance.Namespace, Name: w.instance.ClusterName},
+		&cluster,
+	); err != nil {
+		return err
+	}
+
+	volumes, err := w.instance.GetVolumesUsage()
+	if err != nil {
+		return err
+	}
+
+	warningThreshold := cluster.GetDiskSpaceWarningThreshold()
+	criticalThreshold := cluster.GetDiskSpaceCriticalThreshold()
+	isWalVolumeCritical := false
+	for _, volume := range volumes {
+		percentage := volume.GetUsedPercentage()
+		level := getDiskSpaceLevel(percentage, warningThreshold, criticalThreshold)
+		w.recordLevelChange(&cluster, volume, w.levels[volume.Name], level)
+		w.levels[volume.Name] = level
+
+		// The data volume contains the WAL files too, unless
+		// they are stored in a separate volume
+		if level == diskSpaceLevelCritical &&
+			(volume.Name == postgres.WalVolumeName || len(volumes) == 1) {
+			isWalVolumeCritical = true
+		}
+	}
+
+	if !isWalVolumeCritical || cluster.Spec.DiskSpace == nil || !cluster.Spec.DiskSpace.CheckpointOnCritical {
+		return nil
+	}
+
+	if isPrimary, err := w.instance.IsPrimary(); err != nil || !isPrimary {
+		return err
+	}
+	if w.instance.IsFenced() || w.instance.IsServerHealthy() != nil {
+		return nil
+	}
+
+	contextLogger.Warning("The volume containing the WAL files is almost full, running a checkpoint")
+	db, err := w.instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, "CHECKPOINT")
+	return err
+}
+
+// recordLevelChange emits an event on the cluster when the severity
+// of the disk space usage of a volume changes
+func (w *DiskSpaceWatchdog) recordLevelChange(
+	cluster *apiv1.Cluster,
+	volume postgres.VolumeUsage,
+	previous, current diskSpaceLevel,
+) {
+	percentage := volume.GetUsedPercentage()
+	switch {
+	case current == previous:
+		return
+	case current == diskSpaceLevelCritical:
+		w.recorder.Eventf(cluster, "Warning", "DiskSpaceCritical",
+			"The %s volume of %s is %.1f%% full", volume.Name, w.instance.PodName, percentage)
+	case current == diskSpaceLevelWarning && previous == diskSpaceLevelOk:
+		w.recorder.Eventf(cluster, "Warning", "DiskSpaceLow",
+			"The %s volume of %s is %.1f%% full", volume.Name, w.instance.PodName, percentage)
+	case current == diskSpaceLevelOk:
+		w.recorder.Eventf(cluster, "Normal", "DiskSpaceRecovered",
+			"The %s volume of %s is %.1f%% full", volume.Name, w.instance.PodName, percentage)
+	}
+}
+
+// getDiskSpaceLevel gets the severity of the disk space usage
+// of a volume given the percentage of used space
+func getDiskSpaceLevel(percentage float64, warningThreshold, criticalThreshold int32) diskSpaceLevel {
+	switch {
+	case percentage >= float64(criticalThreshold):
+		return diskSpaceLevelCritical
+	case percentage >= float64(warningThreshold):
+		return diskSpaceLevelWarning
+	default:
+		return diskSpaceLevelOk
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"k8s.io/client-go/tools/record"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("disk space watchdog", func() {
+	It("computes the severity of the disk space usage", func() {
+		Expect(getDiskSpaceLevel(50, 80, 95)).To(Equal(diskSpaceLevelOk))
+		Expect(getDiskSpaceLevel(80, 80, 95)).To(Equal(diskSpaceLevelWarning))
+		Expect(getDiskSpaceLevel(94.9, 80, 95)).To(Equal(diskSpaceLevelWarning))
+		Expect(getDiskSpaceLevel(95, 80, 95)).To(Equal(diskSpaceLevelCritical))
+	})
+
+	It("emits an event only when the severity changes", func() {
+		recorder := record.NewFakeRecorder(10)
+		instance := postgres.NewInstance()
+		instance.PodName = "cluster-example-1"
+		watchdog := NewDiskSpaceWatchdog(instance, nil, recorder)
+		cluster := &apiv1.Cluster{}
+		volume := postgres.VolumeUsage{Name: postgres.DataVolumeName, TotalBytes: 100, AvailableBytes: 10}
+
+		watchdog.recordLevelChange(cluster, volume, diskSpaceLevelOk, diskSpaceLevelOk)
+		Expect(recorder.Events).To(BeEmpty())
+
+		watchdog.recordLevelChange(cluster, volume, diskSpaceLevelOk, diskSpaceLevelWarning)
+		Expect(recorder.Events).To(Receive(ContainSubstring("DiskSpaceLow")))
+
+		watchdog.recordLevelChange(cluster, volume, diskSpaceLevelWarning, diskSpaceLevelCritical)
+		Expect(recorder.Events).To(Receive(And(
+			ContainSubstring("DiskSpaceCritical"),
+			ContainSubstring("cluster-example-1"))))
+
+		watchdog.recordLevelChange(cluster, volume, diskSpaceLevelCritical, diskSpaceLevelWarning)
+		Expect(recorder.Events).To(BeEmpty())
+
+		watchdog.recordLevelChange(cluster, volume, diskSpaceLevelWarning, diskSpaceLevelOk)
+		Expect(recorder.Events).To(Receive(ContainSubstring("DiskSpaceRecovered")))
+	})
+})
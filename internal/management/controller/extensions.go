@@ -0,0 +1,228 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// installedExtension is the state of an extension installed in a database
+type installedExtension struct {
+	Version string
+	Schema  string
+}
+
+// reconcileManagedExtensions creates, updates or drops the extensions
+// listed in the managed section of the cluster, reporting the result
+// in the status. It runs only on the primary instance
+func (r *InstanceReconciler) reconcileManagedExtensions(ctx context.Context, cluster *apiv1.Cluster) error {
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil || !isPrimary {
+		return err
+	}
+
+	var extensions []apiv1.ManagedExtension
+	if cluster.Spec.Managed != nil {
+		extensions = cluster.Spec.Managed.Extensions
+	}
+	if len(extensions) == 0 && cluster.Status.ManagedExtensionsStatus == nil {
+		return nil
+	}
+
+	status := &apiv1.ManagedExtensionsStatus{}
+	for _, extension := range extensions {
+		if err := r.reconcileManagedExtension(ctx, extension); err != nil {
+			log.FromContext(ctx).Info("Cannot reconcile a managed extension",
+				"database", extension.Database,
+				"extension", extension.Name,
+				"err", err)
+			if status.CannotReconcile == nil {
+				status.CannotReconcile = make(map[string]string)
+			}
+			status.CannotReconcile[extension.GetKey()] = err.Error()
+			continue
+		}
+		status.Reconciled = append(status.Reconciled, extension.GetKey())
+	}
+	sort.Strings(status.Reconciled)
+
+	if len(extensions) == 0 {
+		status = nil
+	}
+	if reflect.DeepEqual(status, cluster.Status.ManagedExtensionsStatus) {
+		return nil
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.ManagedExtensionsStatus = status
+	return r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster))
+}
+
+// reconcileManagedExtension reconciles an extension in its database
+func (r *InstanceReconciler) reconcileManagedExtension(
+	ctx context.Context,
+	extension apiv1.ManagedExtension,
+) error {
+	superUserDB, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	var databaseExists bool
+	row := superUserDB.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM pg_database WHERE datname = $1 AND datallowconn)", extension.Database)
+	if err := row.Scan(&databaseExists); err != nil {
+		return err
+	}
+	if !databaseExists {
+		return fmt.Errorf("database %s not found", extension.Database)
+	}
+
+	db, err := r.instance.ConnectionPool().Connection(extension.Database)
+	if err != nil {
+		return err
+	}
+
+	current, err := getInstalledExtension(ctx, db, extension.Name)
+	if err != nil {
+		return err
+	}
+
+	statements := getManagedExtensionStatements(extension, current)
+	if len(statements) == 0 {
+		return nil
+	}
+
+	if extension.Ensure != apiv1.EnsureAbsent {
+		if err := checkExtensionAvailability(ctx, db, extension); err != nil {
+			return err
+		}
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// This is a no-op when the transaction is committed
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, "SET LOCAL synchronous_commit TO local"); err != nil {
+		return err
+	}
+	for _, statement := range statements {
+		log.FromContext(ctx).Info("Reconciling a managed extension",
+			"database", extension.Database,
+			"statement", statement)
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// getInstalledExtension gets the state of an extension
+// in a database, or nil if it is not installed
+func getInstalledExtension(ctx context.Context, db *sql.DB, name string) (*installedExtension, error) {
+	var result installedExtension
+	row := db.QueryRowContext(ctx,
+		`SELECT e.extversion, n.nspname
+		FROM pg_extension e JOIN pg_namespace n ON e.extnamespace = n.oid
+		WHERE e.extname = $1`, name)
+	err := row.Scan(&result.Version, &result.Schema)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// checkExtensionAvailability checks that the requested version of the
+// extension is available in the PostgreSQL installation
+func checkExtensionAvailability(ctx context.Context, db *sql.DB, extension apiv1.ManagedExtension) error {
+	var available bool
+	row := db.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM pg_available_extension_versions
+		WHERE name = $1 AND ($2 = '' OR version = $2))`,
+		extension.Name, extension.Version)
+	if err := row.Scan(&available); err != nil {
+		return err
+	}
+
+	switch {
+	case available:
+		return nil
+	case extension.Version != "":
+		return fmt.Errorf("version %s of extension %s is not available", extension.Version, extension.Name)
+	default:
+		return fmt.Errorf("extension %s is not available", extension.Name)
+	}
+}
+
+// getManagedExtensionStatements gets the statements needed to bring an
+// extension from its current state to the requested one
+func getManagedExtensionStatements(extension apiv1.ManagedExtension, current *installedExtension) []string {
+	name := pgx.Identifier{extension.Name}.Sanitize()
+
+	if extension.Ensure == apiv1.EnsureAbsent {
+		if current == nil {
+			return nil
+		}
+		return []string{fmt.Sprintf("DROP EXTENSION %s", name)}
+	}
+
+	if current == nil {
+		statement := []string{"CREATE EXTENSION", name}
+		if extension.Schema != "" {
+			statement = append(statement, "SCHEMA", pgx.Identifier{extension.Schema}.Sanitize())
+		}
+		if extension.Version != "" {
+			statement = append(statement, "VERSION", pq.QuoteLiteral(extension.Version))
+		}
+		return []string{strings.Join(statement, " ")}
+	}
+
+	var statements []string
+	if extension.Version != "" && extension.Version != current.Version {
+		statements = append(statements,
+			fmt.Sprintf("ALTER EXTENSION %s UPDATE TO %s", name, pq.QuoteLiteral(extension.Version)))
+	}
+	if extension.Schema != "" && extension.Schema != current.Schema {
+		statements = append(statements,
+			fmt.Sprintf("ALTER EXTENSION %s SET SCHEMA %s", name, pgx.Identifier{extension.Schema}.Sanitize()))
+	}
+
+	return statements
+}
@@ -0,0 +1,80 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("managed extensions", func() {
+	It("creates a missing extension", func() {
+		Expect(getManagedExtensionStatements(apiv1.ManagedExtension{
+			Name:     "postgis",
+			Database: "app",
+		}, nil)).To(Equal([]string{`CREATE EXTENSION "postgis"`}))
+
+		Expect(getManagedExtensionStatements(apiv1.ManagedExtension{
+			Name:     "postgis",
+			Database: "app",
+			Schema:   "gis",
+			Version:  "3.2.1",
+		}, nil)).To(Equal([]string{`CREATE EXTENSION "postgis" SCHEMA "gis" VERSION '3.2.1'`}))
+	})
+
+	It("updates an extension to the requested version and schema", func() {
+		extension := apiv1.ManagedExtension{
+			Name:     "postgis",
+			Database: "app",
+			Schema:   "gis",
+			Version:  "3.3.0",
+		}
+		Expect(getManagedExtensionStatements(extension, &installedExtension{
+			Version: "3.2.1",
+			Schema:  "public",
+		})).To(Equal([]string{
+			`ALTER EXTENSION "postgis" UPDATE TO '3.3.0'`,
+			`ALTER EXTENSION "postgis" SET SCHEMA "gis"`,
+		}))
+	})
+
+	It("doesn't touch an extension in the requested state", func() {
+		Expect(getManagedExtensionStatements(apiv1.ManagedExtension{
+			Name:     "postgis",
+			Database: "app",
+			Version:  "3.2.1",
+		}, &installedExtension{Version: "3.2.1", Schema: "public"})).To(BeEmpty())
+
+		Expect(getManagedExtensionStatements(apiv1.ManagedExtension{
+			Name:     "postgis",
+			Database: "app",
+		}, &installedExtension{Version: "3.2.1", Schema: "public"})).To(BeEmpty())
+	})
+
+	It("drops an extension which should be absent", func() {
+		extension := apiv1.ManagedExtension{
+			Name:     "postgis",
+			Database: "app",
+			Ensure:   apiv1.EnsureAbsent,
+		}
+		Expect(getManagedExtensionStatements(extension, nil)).To(BeEmpty())
+		Expect(getManagedExtensionStatements(extension, &installedExtension{Version: "3.2.1"})).To(
+			Equal([]string{`DROP EXTENSION "postgis"`}))
+	})
+})
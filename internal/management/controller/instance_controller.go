@@ -24,12 +24,15 @@ import (
 	"math"
 	"path"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v4"
 	"github.com/lib/pq"
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -38,6 +41,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/controllers"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
@@ -57,6 +61,10 @@ const (
 	userSearchFunction     = "SELECT usename, passwd FROM pg_shadow WHERE usename=$1;"
 )
 
+// finalWALArchiveTimeout is the maximum amount of time an old primary waits
+// for its last WAL file to be archived before being shut down
+const finalWALArchiveTimeout = 30 * time.Second
+
 // RetryUntilWalReceiverDown is the default retry configuration that is used
 // to wait for the WAL receiver process to be down
 var RetryUntilWalReceiverDown = wait.Backoff{
@@ -112,6 +120,7 @@ func (r *InstanceReconciler) Reconcile(
 	// Reconcile monitoring section
 	r.reconcileMetrics(cluster)
 	r.reconcileMonitoringQueries(ctx, cluster)
+	r.reconcileMetricsSecurity(ctx, cluster)
 
 	// Reconcile secrets and cryptographic material
 	// This doesn't need the PG connection, but it needs to reload it in case of changes
@@ -167,6 +176,12 @@ func (r *InstanceReconciler) Reconcile(
 
 	restarted = restarted || restartedFromOldPrimary
 
+	// The operator will label the Pod anyway, so we
+	// don't need to fail the reconciliation here
+	if err := r.reconcilePodRoleLabel(ctx, cluster); err != nil {
+		contextLogger.Info("Cannot update the role label of the Pod", "err", err)
+	}
+
 	if r.IsDBUp(ctx) != nil {
 		return reconcile.Result{RequeueAfter: time.Second}, nil
 	}
@@ -189,6 +204,10 @@ func (r *InstanceReconciler) Reconcile(
 		}
 	}
 
+	if err = r.reconcileWALReceiver(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("while reconnecting to the new primary: %w", err)
+	}
+
 	if err = r.refreshCredentialsFromSecret(ctx, cluster); err != nil {
 		return reconcile.Result{}, fmt.Errorf("while updating database owner password: %w", err)
 	}
@@ -197,6 +216,14 @@ func (r *InstanceReconciler) Reconcile(
 		return reconcile.Result{}, fmt.Errorf("cannot reconcile database configurations: %w", err)
 	}
 
+	if err := r.reconcileManagedExtensions(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot reconcile the managed extensions: %w", err)
+	}
+
+	if err := r.reconcileManagedRoles(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot reconcile the managed roles: %w", err)
+	}
+
 	// Extremely important.
 	// It could happen that current primary is reconciled before all the topology is extracted by the operator.
 	// We should detect that and schedule the instance manager for another run otherwise we will end up having
@@ -242,12 +269,30 @@ func (r *InstanceReconciler) refreshConfigurationFiles(
 		return false, err
 	}
 
+	// The user-provided configuration files must be in place
+	// before being included by the PostgreSQL configuration
+	reloadUserConfig := r.refreshUserConfigurationFiles(ctx, cluster)
+	reloadNeeded = reloadNeeded || reloadUserConfig
+
 	// Reconcile PostgreSQL configuration
 	// This doesn't need the PG connection, but it needs to reload it in case of changes
 	reloadConfig, err := r.instance.RefreshConfigurationFilesFromCluster(cluster)
+	condition := getSharedPreloadLibrariesCondition(
+		meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionSharedPreloadLibraries)),
+		r.instance.PodName,
+		err)
+	if errors.Is(err, postgresManagement.ErrMissingSharedPreloadLibraries) {
+		// We keep the current configuration, which PostgreSQL is able
+		// to load, until the missing libraries are available
+		log.FromContext(ctx).Error(err, "Cannot apply the new PostgreSQL configuration")
+		reloadConfig, err = false, nil
+	}
 	if err != nil {
 		return false, err
 	}
+	if err := manager.UpdateCondition(ctx, r.client, cluster, condition); err != nil {
+		return false, err
+	}
 	reloadNeeded = reloadNeeded || reloadConfig
 
 	reloadReplicaConfig, err := r.refreshReplicaConfiguration(ctx, cluster)
@@ -258,6 +303,80 @@ func (r *InstanceReconciler) refreshConfigurationFiles(
 	return reloadNeeded, nil
 }
 
+// getSharedPreloadLibrariesCondition gets the condition reporting that the
+// instance can't apply the PostgreSQL configuration because some shared
+// preload libraries are missing, given the error raised refreshing the
+// configuration. The condition is cleared only by the same instance
+func getSharedPreloadLibrariesCondition(
+	existing *metav1.Condition,
+	podName string,
+	refreshErr error,
+) *metav1.Condition {
+	if errors.Is(refreshErr, postgresManagement.ErrMissingSharedPreloadLibraries) {
+		return &metav1.Condition{
+			Type:   string(apiv1.ConditionSharedPreloadLibraries),
+			Status: metav1.ConditionFalse,
+			Reason: string(apiv1.ConditionReasonSharedPreloadLibrariesMissing),
+			Message: fmt.Sprintf("%s: the PostgreSQL configuration cannot be applied: %s",
+				podName, refreshErr.Error()),
+		}
+	}
+
+	if refreshErr != nil || existing == nil || existing.Status != metav1.ConditionFalse ||
+		!strings.HasPrefix(existing.Message, podName+":") {
+		return nil
+	}
+
+	return &metav1.Condition{
+		Type:    string(apiv1.ConditionSharedPreloadLibraries),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonSharedPreloadLibrariesAvailable),
+		Message: fmt.Sprintf("%s: the shared preload libraries are available", podName),
+	}
+}
+
+// refreshUserConfigurationFiles installs the configuration files taken
+// from the ConfigMaps referenced by the cluster, returning true if they
+// have been changed. If a ConfigMap can't be read, the current files are
+// kept as they are
+func (r *InstanceReconciler) refreshUserConfigurationFiles(ctx context.Context, cluster *apiv1.Cluster) bool {
+	contextLogger := log.FromContext(ctx)
+
+	references := cluster.Spec.PostgresConfiguration.ConfigurationFiles
+	files := make([]postgresManagement.ConfigurationFile, 0, len(references))
+	for _, reference := range references {
+		var configMap corev1.ConfigMap
+		err := r.GetClient().Get(
+			ctx,
+			client.ObjectKey{Namespace: r.instance.Namespace, Name: reference.Name},
+			&configMap)
+		if err != nil {
+			contextLogger.Warning("Unable to get configMap containing a configuration file",
+				"reference", reference,
+				"error", err.Error())
+			return false
+		}
+
+		content, ok := configMap.Data[reference.Key]
+		if !ok {
+			contextLogger.Warning("Missing key in configMap",
+				"reference", reference)
+			return false
+		}
+
+		files = append(files, postgresManagement.ConfigurationFile{
+			Source:  fmt.Sprintf("%s-%s", reference.Name, reference.Key),
+			Content: content,
+		})
+	}
+
+	changed, err := r.instance.RefreshUserConfigurationFiles(files, cluster.GetSectionManagedParameters())
+	if err != nil {
+		contextLogger.Error(err, "Cannot install the configuration files")
+	}
+	return changed
+}
+
 func (r *InstanceReconciler) reconcileFencing(cluster *apiv1.Cluster) *reconcile.Result {
 	fencingRequired := cluster.IsInstanceFenced(r.instance.PodName)
 	isFenced := r.instance.IsFenced()
@@ -295,6 +414,10 @@ func (r *InstanceReconciler) initialize(ctx context.Context, cluster *apiv1.Clus
 		return err
 	}
 
+	if err := r.verifyPgDataCoherenceForReplica(ctx, cluster); err != nil {
+		return err
+	}
+
 	r.instance.SetFencing(cluster.IsInstanceFenced(r.instance.PodName))
 
 	return nil
@@ -328,7 +451,7 @@ func (r *InstanceReconciler) verifyParametersForFollower(cluster *apiv1.Cluster)
 		return err
 	}
 
-	clusterParams := cluster.Spec.PostgresConfiguration.Parameters
+	clusterParams := cluster.GetPostgresParameters()
 	options := make(map[string]string)
 	for key, enforcedparam := range enforcedParams {
 		clusterparam, found := clusterParams[key]
@@ -392,6 +515,13 @@ func (r *InstanceReconciler) reconcileOldPrimary(
 		}
 	}
 
+	// During a failover the old primary may still be accepting writes, so it
+	// must be shut down as soon as possible: the last WAL file is archived
+	// only during a switchover, which is explicitly marked by the operator
+	if cluster.IsSwitchoverInProgress() {
+		r.archiveFinalWAL(ctx, cluster)
+	}
+
 	contextLogger.Info("This is an old primary node. Shutting it down to get it demoted to a replica")
 
 	// Here we need to invoke a fast shutdown on the instance, and wait the instance
@@ -409,6 +539,38 @@ func (r *InstanceReconciler) reconcileOldPrimary(
 	return true, nil
 }
 
+// archiveFinalWAL fences the writes of an old primary being switched over,
+// then switches and archives its last WAL file before it is shut down, so
+// that the archive contains every transaction it committed, and records the
+// final LSN in the status of the cluster.
+// Errors are only logged, as they must not prevent the demotion
+func (r *InstanceReconciler) archiveFinalWAL(ctx context.Context, cluster *apiv1.Cluster) {
+	contextLogger := log.FromContext(ctx)
+
+	if err := r.instance.FenceWrites(); err != nil {
+		contextLogger.Error(err, "Cannot fence the writes before demotion, skipping the WAL switch")
+		return
+	}
+
+	lsn, archived, err := r.instance.SwitchAndArchiveWAL(finalWALArchiveTimeout)
+	if err != nil {
+		contextLogger.Error(err, "Cannot switch the WAL file before demotion")
+		return
+	}
+	if !archived {
+		contextLogger.Warning("The last WAL file has not been archived before demotion",
+			"lsn", lsn,
+			"timeout", finalWALArchiveTimeout)
+	}
+
+	contextLogger.Info("Recording the final LSN of the old primary", "lsn", lsn)
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.DemotedPrimaryLSN = lsn
+	if err := r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster)); err != nil {
+		contextLogger.Error(err, "Cannot record the final LSN of the old primary")
+	}
+}
+
 // IsDBUp checks whether the superuserdb is reachable and returns an error if that's not the case
 func (r *InstanceReconciler) IsDBUp(ctx context.Context) error {
 	contextLogger := log.FromContext(ctx)
@@ -439,9 +601,12 @@ func (r *InstanceReconciler) reconcileDatabases(ctx context.Context, cluster *ap
 		return fmt.Errorf("getting the superuserdb: %w", err)
 	}
 
+	userSettings := cluster.GetPostgresParameters()
+	enabledExtensions := cluster.Spec.PostgresConfiguration.GetEnabledExtensions()
+
 	extensionStatusChanged := false
 	for _, extension := range postgres.ManagedExtensions {
-		extensionIsUsed := extension.IsUsed(cluster.Spec.PostgresConfiguration.Parameters)
+		extensionIsUsed := extension.IsEnabled(userSettings, enabledExtensions)
 		if lastStatus, ok := r.extensionStatus[extension.Name]; !ok || lastStatus != extensionIsUsed {
 			extensionStatusChanged = true
 			break
@@ -457,7 +622,7 @@ func (r *InstanceReconciler) reconcileDatabases(ctx context.Context, cluster *ap
 			continue
 		}
 		if extensionStatusChanged {
-			if err = r.reconcileExtensions(ctx, db, cluster.Spec.PostgresConfiguration.Parameters); err != nil {
+			if err = r.reconcileExtensions(ctx, db, userSettings, enabledExtensions); err != nil {
 				errors = append(errors,
 					fmt.Errorf("could not reconcile extensions for database %s: %w", databaseName, err))
 			}
@@ -472,7 +637,7 @@ func (r *InstanceReconciler) reconcileDatabases(ctx context.Context, cluster *ap
 	}
 
 	for _, extension := range postgres.ManagedExtensions {
-		extensionIsUsed := extension.IsUsed(cluster.Spec.PostgresConfiguration.Parameters)
+		extensionIsUsed := extension.IsEnabled(userSettings, enabledExtensions)
 		r.extensionStatus[extension.Name] = extensionIsUsed
 	}
 
@@ -505,7 +670,7 @@ func (r *InstanceReconciler) getAllAccessibleDatabases(
 // ReconcileExtensions reconciles the expected extensions for this
 // PostgreSQL instance
 func (r *InstanceReconciler) reconcileExtensions(
-	ctx context.Context, db *sql.DB, userSettings map[string]string,
+	ctx context.Context, db *sql.DB, userSettings map[string]string, enabledExtensions []string,
 ) (err error) {
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
@@ -522,7 +687,7 @@ func (r *InstanceReconciler) reconcileExtensions(
 	}
 
 	for _, extension := range postgres.ManagedExtensions {
-		extensionIsUsed := extension.IsUsed(userSettings)
+		extensionIsUsed := extension.IsEnabled(userSettings, enabledExtensions)
 
 		row := tx.QueryRow("SELECT COUNT(*) > 0 FROM pg_extension WHERE extname = $1", extension.Name)
 		err = row.Err()
@@ -706,6 +871,10 @@ func (r *InstanceReconciler) reconcileMonitoringQueries(
 		return
 	}
 
+	if discovery := cluster.Spec.Monitoring.DatabaseDiscovery; discovery != nil {
+		queriesCollector.SetDatabaseDiscovery(discovery.Include, discovery.Exclude)
+	}
+
 	for _, reference := range cluster.Spec.Monitoring.CustomQueriesConfigMap {
 		var configMap corev1.ConfigMap
 		err := r.GetClient().Get(
@@ -764,6 +933,44 @@ func (r *InstanceReconciler) reconcileMonitoringQueries(
 	r.metricsServerExporter.SetCustomQueries(queriesCollector)
 }
 
+// reconcileMetricsSecurity applies the TLS and authentication
+// configuration to the metrics web server
+func (r *InstanceReconciler) reconcileMetricsSecurity(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) {
+	contextLogger := log.FromContext(ctx)
+
+	monitoring := cluster.Spec.Monitoring
+	config := metricserver.SecurityConfiguration{
+		TLS:                      monitoring.IsTLSEnabled(),
+		RequireClientCertificate: monitoring.IsClientCertificateRequired(),
+	}
+
+	if monitoring != nil && monitoring.BearerTokenSecret != nil {
+		reference := monitoring.BearerTokenSecret
+		secret, err := r.GetSecret(ctx, reference.Name)
+		if err != nil {
+			// The previous configuration is kept, as the metrics
+			// must never be exposed without the required token
+			contextLogger.Warning("Unable to get the secret containing the metrics bearer token",
+				"reference", reference,
+				"error", err.Error())
+			return
+		}
+
+		token, ok := secret.Data[reference.Key]
+		if !ok || len(token) == 0 {
+			contextLogger.Warning("Missing key in the secret containing the metrics bearer token",
+				"reference", reference)
+			return
+		}
+		config.BearerToken = strings.TrimSpace(string(token))
+	}
+
+	r.metricsServer.SetSecurityConfiguration(config)
+}
+
 // RefreshSecrets is called when the PostgreSQL secrets are changed
 // and will refresh the contents of the file inside the Pod, without
 // reloading the actual PostgreSQL instance.
@@ -835,8 +1042,16 @@ func (r *InstanceReconciler) RefreshSecrets(
 // reconcileInstance sets PostgreSQL instance parameters to current values
 func (r *InstanceReconciler) reconcileInstance(cluster *apiv1.Cluster) {
 	r.instance.PgCtlTimeoutForPromotion = cluster.GetPgCtlTimeoutForPromotion()
+	r.instance.PromotionTimeoutAction = cluster.GetPromotionTimeoutAction()
 	r.instance.MaxSwitchoverDelay = cluster.GetMaxSwitchoverDelay()
 	r.instance.MaxStopDelay = cluster.GetMaxStopDelay()
+	r.instance.MaximumReplayLag = cluster.GetMaximumReplayLag()
+	r.instance.IsDesignatedPrimary = cluster.IsReplica() && cluster.Status.CurrentPrimary == r.instance.PodName
+
+	// Apply the log level of the cluster without restarting the instance manager
+	if cluster.Spec.LogLevel != "" {
+		log.SetLevel(log.ParseLevel(cluster.Spec.LogLevel))
+	}
 }
 
 // waitForConfigurationReload waits for the db to be up and
@@ -1059,11 +1274,18 @@ func (r *InstanceReconciler) promoteAndWait(ctx context.Context, cluster *apiv1.
 	}
 
 	contextLogger.Info("I'm the target primary, applying WALs and promoting my instance")
+	r.updatePromotionCondition(ctx, cluster, apiv1.ConditionReasonPromotionInProgress)
+
 	// I must promote my instance here
-	err := r.instance.PromoteAndWait()
+	err := r.instance.PromoteAndWait(ctx)
+	if errors.Is(err, postgresManagement.ErrPromotionTimedOut) {
+		r.updatePromotionCondition(ctx, cluster, apiv1.ConditionReasonPromotionTimedOut)
+	}
 	if err != nil {
 		return fmt.Errorf("error promoting instance: %w", err)
 	}
+
+	r.updatePromotionCondition(ctx, cluster, apiv1.ConditionReasonPromotionSucceeded)
 	return nil
 }
 
@@ -1148,12 +1370,12 @@ func (r *InstanceReconciler) refreshCredentialsFromSecret(
 	}
 
 	if cluster.GetEnableSuperuserAccess() {
-		err = r.reconcileUser(ctx, "postgres", cluster.GetSuperuserSecretName(), tx)
+		err = r.reconcileUser(ctx, cluster.GetSuperuserName(), cluster.GetSuperuserSecretName(), tx)
 		if err != nil {
 			return err
 		}
 	} else {
-		err = r.disableSuperuserPassword(tx)
+		err = r.disableSuperuserPassword(tx, cluster.GetSuperuserName())
 		if err != nil {
 			return err
 		}
@@ -1207,8 +1429,8 @@ func (r *InstanceReconciler) reconcileUser(ctx context.Context, username string,
 	return err
 }
 
-func (r *InstanceReconciler) disableSuperuserPassword(tx *sql.Tx) error {
-	_, err := tx.Exec("ALTER ROLE postgres WITH PASSWORD NULL")
+func (r *InstanceReconciler) disableSuperuserPassword(tx *sql.Tx, superuser string) error {
+	_, err := tx.Exec(fmt.Sprintf("ALTER ROLE %s WITH PASSWORD NULL", pgx.Identifier{superuser}.Sanitize()))
 	return err
 }
 
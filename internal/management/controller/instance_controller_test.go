@@ -0,0 +1,56 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"errors"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	postgresManagement "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("shared preload libraries condition", func() {
+	missingErr := fmt.Errorf("%w: pg_failover_slots", postgresManagement.ErrMissingSharedPreloadLibraries)
+
+	It("reports the missing libraries", func() {
+		condition := getSharedPreloadLibrariesCondition(nil, "cluster-example-1", missingErr)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonSharedPreloadLibrariesMissing)))
+		Expect(condition.Message).To(HavePrefix("cluster-example-1:"))
+		Expect(condition.Message).To(ContainSubstring("pg_failover_slots"))
+	})
+
+	It("doesn't change the condition when no library was missing", func() {
+		Expect(getSharedPreloadLibrariesCondition(nil, "cluster-example-1", nil)).To(BeNil())
+		Expect(getSharedPreloadLibrariesCondition(nil, "cluster-example-1", errors.New("disk full"))).To(BeNil())
+	})
+
+	It("clears the condition only from the instance which reported it", func() {
+		existing := getSharedPreloadLibrariesCondition(nil, "cluster-example-1", missingErr)
+		Expect(getSharedPreloadLibrariesCondition(existing, "cluster-example-2", nil)).To(BeNil())
+
+		condition := getSharedPreloadLibrariesCondition(existing, "cluster-example-1", nil)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonSharedPreloadLibrariesAvailable)))
+	})
+})
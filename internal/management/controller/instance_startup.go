@@ -27,6 +27,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/controllers"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	pkgUtils "github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -245,7 +246,18 @@ func (r *InstanceReconciler) verifyPgDataCoherenceForPrimary(
 			// Then let's go back to the point of the new primary
 			err = r.instance.Rewind(pgMajorVersion)
 			if err != nil {
-				return err
+				if cluster.Spec.RewindFailurePolicy != apiv1.RewindFailurePolicyReclone {
+					return err
+				}
+
+				// pg_rewind cannot be used on this data directory, e.g. because
+				// data checksums and wal_log_hints are both disabled. We
+				// clone it again from the new primary, and we are already
+				// configured as a replica
+				contextLogger.Info(
+					"pg_rewind failed again, cloning the data directory from the primary",
+					"err", err)
+				return r.instance.Reclone(ctx, cluster.Spec.ReplicaClone)
 			}
 		}
 
@@ -253,3 +265,85 @@ func (r *InstanceReconciler) verifyPgDataCoherenceForPrimary(
 		return r.instance.Demote()
 	}
 }
+
+// verifyPgDataCoherenceForReplica aligns the data directory of a replica
+// with the current primary when its PVCs have been retained during a
+// scale-down and then reattached, and the WAL it replayed is on an older
+// timeline. If the primary is not reachable the replica starts on the
+// current data directory without waiting, and PostgreSQL will try to follow
+// the timeline switch by itself. If pg_rewind is not able to align it, the
+// data directory is cloned again when required by the rewind failure policy
+func (r *InstanceReconciler) verifyPgDataCoherenceForReplica(
+	ctx context.Context, cluster *apiv1.Cluster,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+	if isPrimary || cluster.Status.TimelineID == 0 {
+		return nil
+	}
+
+	var pod corev1.Pod
+	if err := r.GetClient().Get(
+		ctx,
+		client.ObjectKey{Namespace: r.instance.Namespace, Name: r.instance.PodName},
+		&pod,
+	); err != nil {
+		contextLogger.Warning("Cannot detect whether the PVCs have been reattached, skipping the timeline check",
+			"err", err)
+		return nil
+	}
+	if _, ok := pod.Annotations[specs.ReattachedAnnotationName]; !ok {
+		return nil
+	}
+
+	timeline, err := r.instance.GetReplayedTimeline()
+	if err != nil {
+		return err
+	}
+	if timeline >= cluster.Status.TimelineID {
+		return nil
+	}
+
+	contextLogger.Info("The data directory is on an older timeline, aligning it with the primary",
+		"timeline", timeline,
+		"clusterTimeline", cluster.Status.TimelineID)
+
+	if err := r.instance.PingPrimary(); err != nil {
+		contextLogger.Warning(
+			"The primary is not reachable, starting the replica on the current data directory",
+			"err", err)
+		return nil
+	}
+
+	tag := pkgUtils.GetImageTag(cluster.GetImageName())
+	pgMajorVersion, err := postgresSpec.GetPostgresMajorVersionFromTag(tag)
+	if err != nil {
+		return err
+	}
+
+	if err := r.instance.CleanUpStalePid(); err != nil {
+		return err
+	}
+
+	if err := r.instance.Rewind(pgMajorVersion); err != nil {
+		if cluster.Spec.RewindFailurePolicy == apiv1.RewindFailurePolicyReclone {
+			contextLogger.Info(
+				"pg_rewind failed, cloning the data directory from the primary",
+				"err", err)
+			return r.instance.Reclone(ctx, cluster.Spec.ReplicaClone)
+		}
+
+		contextLogger.Warning(
+			"pg_rewind failed, starting the replica on the current data directory",
+			"err", err)
+		return nil
+	}
+
+	// pg_rewind may have changed the recovery configuration,
+	// let's make sure we are still following the primary
+	return r.instance.Demote()
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver/metricserver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 )
 
 // InstanceReconciler can reconcile the status of the PostgreSQL cluster with
@@ -43,9 +44,22 @@ type InstanceReconciler struct {
 	secretVersions  map[string]string
 	extensionStatus map[string]bool
 
+	// additionalObjectStores contains the names of the additional
+	// object stores whose credentials are in the cache
+	additionalObjectStores *stringset.Data
+
+	// walPluginCandidates contains the names of the plugins of the
+	// cluster when the plugin with the wal capability has been found
+	walPluginCandidates []string
+
+	// lastKnownPrimary is the current primary seen in the previous
+	// reconciliation loop, used to detect failovers and switchovers
+	lastKnownPrimary string
+
 	systemInitialization  *concurrency.Executed
 	firstReconcileDone    atomic.Bool
 	metricsServerExporter *metricserver.Exporter
+	metricsServer         *metricserver.MetricsServer
 }
 
 // NewInstanceReconciler creates a new instance reconciler
@@ -61,6 +75,7 @@ func NewInstanceReconciler(
 		extensionStatus:       make(map[string]bool),
 		systemInitialization:  concurrency.NewExecuted(),
 		metricsServerExporter: server.GetExporter(),
+		metricsServer:         server,
 	}
 }
 
@@ -0,0 +1,243 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// objectStoreGCCheckPeriod is the interval between two checks
+// of the schedule of the object store garbage collection
+const objectStoreGCCheckPeriod = 30 * time.Second
+
+// ObjectStoreGarbageCollector implements the Runnable interface and, on the
+// primary instance, periodically reconciles the content of the object store
+// against the Backup resources following the schedule defined in the cluster.
+//
+// The base backups which are not referenced by any Backup resource, and the
+// WAL files needed only by them, are reported in the status of the cluster.
+// When requested, the retention policy is applied too, removing the Backup
+// resources whose base backup has been deleted.
+type ObjectStoreGarbageCollector struct {
+	instance *postgres.Instance
+	client   ctrl.Client
+	recorder record.EventRecorder
+
+	// schedule is the schedule used to compute the next run
+	schedule string
+
+	// nextRun is when the next reconciliation is due
+	nextRun time.Time
+}
+
+// NewObjectStoreGarbageCollector creates a new ObjectStoreGarbageCollector for an instance
+func NewObjectStoreGarbageCollector(
+	instance *postgres.Instance,
+	client ctrl.Client,
+	recorder record.EventRecorder,
+) *ObjectStoreGarbageCollector {
+	return &ObjectStoreGarbageCollector{
+		instance: instance,
+		client:   client,
+		recorder: recorder,
+	}
+}
+
+// Start starts checking the schedule of the garbage collection
+func (gc *ObjectStoreGarbageCollector) Start(ctx context.Context) error {
+	return runPeriodically(ctx, objectStoreGCCheckPeriod,
+		"Cannot reconcile the object store", func(ctx context.Context) error {
+			return gc.checkSchedule(ctx, time.Now())
+		})
+}
+
+// checkSchedule reconciles the object store when it is due
+func (gc *ObjectStoreGarbageCollector) checkSchedule(ctx context.Context, now time.Time) error {
+	if isPrimary, err := gc.instance.IsPrimary(); err != nil || !isPrimary {
+		// The schedule is computed again when the instance is promoted
+		gc.schedule = ""
+		return err
+	}
+
+	var cluster apiv1.Cluster
+	if err := gc.client.Get(
+		ctx,
+		ctrl.ObjectKey{Namespace: gc.instance.Namespace, Name: gc.instance.ClusterName},
+		&cluster,
+	); err != nil {
+		return err
+	}
+
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil ||
+		cluster.Spec.Backup.GarbageCollection == nil {
+		gc.schedule = ""
+		return nil
+	}
+
+	if !gc.isRunDue(cluster.Spec.Backup.GarbageCollection.Schedule, now) {
+		return nil
+	}
+
+	report, err := gc.reconcileObjectStore(ctx, &cluster)
+	if err != nil {
+		return err
+	}
+	report.LastCheckTime = now.Format(time.RFC3339)
+
+	return gc.reportResult(ctx, &cluster, report)
+}
+
+// isRunDue checks if the reconciliation is due, scheduling the next
+// one. The first reconciliation is scheduled at the first occurrence
+// of the schedule after the garbage collector started or the schedule
+// changed
+func (gc *ObjectStoreGarbageCollector) isRunDue(schedule string, now time.Time) bool {
+	parsedSchedule, err := cron.Parse(schedule)
+	if err != nil {
+		return false
+	}
+
+	if schedule != gc.schedule {
+		gc.schedule = schedule
+		gc.nextRun = parsedSchedule.Next(now)
+		return false
+	}
+
+	if now.Before(gc.nextRun) {
+		return false
+	}
+
+	gc.nextRun = parsedSchedule.Next(now)
+	return true
+}
+
+// reconcileObjectStore applies the retention policy, when requested,
+// and finds the base backups not referenced by any Backup resource
+func (gc *ObjectStoreGarbageCollector) reconcileObjectStore(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (*apiv1.ObjectStoreReport, error) {
+	contextLogger := log.FromContext(ctx)
+
+	backupConfig := cluster.Spec.Backup
+	serverName := cluster.Name
+	if backupConfig.BarmanObjectStore.ServerName != "" {
+		serverName = backupConfig.BarmanObjectStore.ServerName
+	}
+
+	env, err := barmanCredentials.EnvSetBackupCloudCredentials(
+		ctx,
+		gc.client,
+		cluster.Namespace,
+		backupConfig.BarmanObjectStore,
+		os.Environ())
+	if err != nil {
+		return nil, fmt.Errorf("cannot recover the object store credentials: %w", err)
+	}
+
+	backupList, err := barman.GetBackupList(backupConfig.BarmanObjectStore, serverName, env)
+	if err != nil {
+		return nil, fmt.Errorf("while listing the backups in the object store: %w", err)
+	}
+
+	report := &apiv1.ObjectStoreReport{}
+	if backupConfig.GarbageCollection.ApplyRetentionPolicy && backupConfig.RetentionPolicy != "" {
+		contextLogger.Info("Applying backup retention policy",
+			"retentionPolicy", backupConfig.RetentionPolicy)
+		if err := barman.DeleteBackupsByPolicy(backupConfig, serverName, env); err != nil {
+			// Proper logging already happened inside DeleteBackupsByPolicy
+			gc.recorder.Event(cluster, "Warning", "RetentionPolicyFailed", "Retention policy failed")
+			return nil, err
+		}
+
+		currentBackupList, err := barman.GetBackupList(backupConfig.BarmanObjectStore, serverName, env)
+		if err != nil {
+			return nil, fmt.Errorf("while listing the backups in the object store: %w", err)
+		}
+		report.DeletedBackups = getDeletedBackups(backupList, currentBackupList)
+		backupList = currentBackupList
+
+		if err := barman.DeleteBackupsNotInCatalog(ctx, gc.client, cluster, backupList); err != nil {
+			return nil, err
+		}
+	}
+
+	report.OrphanedBackups, report.OrphanedWALRange, err = barman.FindOrphanedBackups(
+		ctx, gc.client, cluster, backupList)
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// reportResult emits the events describing the result of the
+// reconciliation and stores it in the status of the cluster
+func (gc *ObjectStoreGarbageCollector) reportResult(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	report *apiv1.ObjectStoreReport,
+) error {
+	if len(report.DeletedBackups) > 0 {
+		gc.recorder.Eventf(cluster, "Normal", "BackupsDeleted",
+			"Deleted by the retention policy: %s", strings.Join(report.DeletedBackups, ", "))
+	}
+	if len(report.OrphanedBackups) > 0 {
+		log.FromContext(ctx).Info("Found base backups not referenced by any Backup resource",
+			"orphanedBackups", report.OrphanedBackups,
+			"orphanedWALRange", report.OrphanedWALRange)
+		gc.recorder.Eventf(cluster, "Warning", "OrphanedBackupsFound",
+			"Base backups not referenced by any Backup resource: %s",
+			strings.Join(report.OrphanedBackups, ", "))
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.ObjectStoreReport = report
+	return gc.client.Status().Patch(ctx, cluster, ctrl.MergeFrom(oldCluster))
+}
+
+// getDeletedBackups gets the IDs of the backups which
+// are in the first catalog but not in the second one
+func getDeletedBackups(before, after *catalog.Catalog) []string {
+	currentBackups := make(map[string]bool, len(after.List))
+	for _, backup := range after.List {
+		currentBackups[backup.ID] = true
+	}
+
+	var deletedBackups []string
+	for _, backup := range before.List {
+		if !currentBackups[backup.ID] {
+			deletedBackups = append(deletedBackups, backup.ID)
+		}
+	}
+	return deletedBackups
+}
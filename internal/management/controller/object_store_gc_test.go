@@ -0,0 +1,63 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Object store garbage collection", func() {
+	It("runs following the schedule", func() {
+		gc := NewObjectStoreGarbageCollector(postgres.NewInstance(), nil, nil)
+		now := time.Date(2022, 10, 10, 12, 0, 0, 0, time.UTC)
+
+		// The first run is scheduled at the next occurrence
+		Expect(gc.isRunDue("0 0 0 * * *", now)).To(BeFalse())
+		Expect(gc.isRunDue("0 0 0 * * *", now.Add(6*time.Hour))).To(BeFalse())
+		Expect(gc.isRunDue("0 0 0 * * *", now.Add(12*time.Hour))).To(BeTrue())
+		Expect(gc.isRunDue("0 0 0 * * *", now.Add(13*time.Hour))).To(BeFalse())
+
+		// Changing the schedule reschedules the run
+		Expect(gc.isRunDue("0 0 * * * *", now.Add(14*time.Hour))).To(BeFalse())
+		Expect(gc.isRunDue("0 0 * * * *", now.Add(15*time.Hour))).To(BeTrue())
+	})
+
+	It("ignores invalid schedules", func() {
+		gc := NewObjectStoreGarbageCollector(postgres.NewInstance(), nil, nil)
+		Expect(gc.isRunDue("invalid", time.Now())).To(BeFalse())
+		Expect(gc.isRunDue("invalid", time.Now().Add(time.Hour))).To(BeFalse())
+	})
+
+	It("finds the backups deleted by the retention policy", func() {
+		before := catalog.NewCatalog([]catalog.BarmanBackup{
+			{ID: "202101011200"},
+			{ID: "202101021200"},
+			{ID: "202101031200"},
+		})
+		after := catalog.NewCatalog([]catalog.BarmanBackup{
+			{ID: "202101031200"},
+		})
+		Expect(getDeletedBackups(before, after)).To(ConsistOf("202101011200", "202101021200"))
+		Expect(getDeletedBackups(after, after)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,59 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// errStopPeriodicCheck is returned by a periodic check which doesn't
+// need to be run anymore
+var errStopPeriodicCheck = errors.New("stop the periodic check")
+
+// runPeriodically runs the passed check every period, until the context
+// is cancelled or the check returns errStopPeriodicCheck. Any other error
+// returned by the check is logged with the passed message, and the check
+// is run again after the next period
+func runPeriodically(
+	ctx context.Context,
+	period time.Duration,
+	errorMessage string,
+	check func(context.Context) error,
+) error {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		err := check(ctx)
+		switch {
+		case errors.Is(err, errStopPeriodicCheck):
+			return nil
+		case err != nil:
+			log.FromContext(ctx).Info(errorMessage, "err", err)
+		}
+	}
+}
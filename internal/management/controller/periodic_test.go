@@ -0,0 +1,58 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("periodic checks", func() {
+	It("runs the check until it asks to stop, even after an error", func() {
+		runs := 0
+		err := runPeriodically(context.Background(), time.Millisecond, "test",
+			func(context.Context) error {
+				runs++
+				switch runs {
+				case 1:
+					return errors.New("transient error")
+				case 3:
+					return errStopPeriodicCheck
+				}
+				return nil
+			})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(runs).To(Equal(3))
+	})
+
+	It("stops when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		runs := 0
+		err := runPeriodically(ctx, time.Hour, "test", func(context.Context) error {
+			runs++
+			return nil
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(runs).To(BeZero())
+	})
+})
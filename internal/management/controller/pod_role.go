@@ -0,0 +1,91 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// reconcilePodRoleLabel labels the Pod of the instance with its role, without
+// waiting for the operator to do it. This allows the Services selecting the
+// primary to point to the new one as soon as it has been promoted
+func (r *InstanceReconciler) reconcilePodRoleLabel(ctx context.Context, cluster *apiv1.Cluster) error {
+	var pod corev1.Pod
+	if err := r.client.Get(
+		ctx,
+		client.ObjectKey{Namespace: r.instance.Namespace, Name: r.instance.PodName},
+		&pod,
+	); err != nil {
+		return err
+	}
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+
+	origPod := pod.DeepCopy()
+	role := getInstanceRole(cluster, r.instance.PodName, isPrimary)
+	if !setPodRoleLabel(&pod, role) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Updating the role label of the Pod", "role", role)
+	return r.client.Patch(ctx, &pod, client.MergeFrom(origPod))
+}
+
+// getInstanceRole gets the role of an instance, as reported by the
+// instance itself, so that the label doesn't lag behind a promotion.
+// An instance is labeled as primary only while it is the target primary,
+// so that a former primary which has not been demoted yet is never
+// selected by the Services. The designated primary of a replica cluster
+// is in recovery, and is labeled as primary when it is the current one
+func getInstanceRole(cluster *apiv1.Cluster, podName string, isPrimary bool) string {
+	if cluster.IsReplica() {
+		if cluster.Status.CurrentPrimary == podName {
+			return specs.ClusterRoleLabelPrimary
+		}
+		return specs.ClusterRoleLabelReplica
+	}
+
+	if isPrimary && cluster.Status.TargetPrimary == podName {
+		return specs.ClusterRoleLabelPrimary
+	}
+
+	return specs.ClusterRoleLabelReplica
+}
+
+// setPodRoleLabel sets the role label of a Pod, returning
+// true when it has been changed
+func setPodRoleLabel(pod *corev1.Pod, role string) bool {
+	if pod.Labels[specs.ClusterRoleLabelName] == role {
+		return false
+	}
+
+	if pod.Labels == nil {
+		pod.Labels = make(map[string]string)
+	}
+	pod.Labels[specs.ClusterRoleLabelName] = role
+	return true
+}
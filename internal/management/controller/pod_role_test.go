@@ -0,0 +1,61 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pod role label", func() {
+	cluster := &apiv1.Cluster{
+		Status: apiv1.ClusterStatus{
+			CurrentPrimary: "cluster-example-1",
+			TargetPrimary:  "cluster-example-2",
+		},
+	}
+
+	It("labels the promoted target primary as primary", func() {
+		Expect(getInstanceRole(cluster, "cluster-example-2", true)).To(Equal(specs.ClusterRoleLabelPrimary))
+		Expect(getInstanceRole(cluster, "cluster-example-2", false)).To(Equal(specs.ClusterRoleLabelReplica))
+	})
+
+	It("labels the former primary as replica", func() {
+		Expect(getInstanceRole(cluster, "cluster-example-1", true)).To(Equal(specs.ClusterRoleLabelReplica))
+	})
+
+	It("labels the current designated primary of a replica cluster as primary", func() {
+		replicaCluster := cluster.DeepCopy()
+		replicaCluster.Spec.ReplicaCluster = &apiv1.ReplicaClusterConfiguration{Enabled: true}
+		Expect(getInstanceRole(replicaCluster, "cluster-example-1", false)).To(Equal(specs.ClusterRoleLabelPrimary))
+		Expect(getInstanceRole(replicaCluster, "cluster-example-2", false)).To(Equal(specs.ClusterRoleLabelReplica))
+	})
+
+	It("sets the label only when it changes", func() {
+		pod := &corev1.Pod{}
+		Expect(setPodRoleLabel(pod, specs.ClusterRoleLabelReplica)).To(BeTrue())
+		Expect(pod.Labels).To(HaveKeyWithValue(specs.ClusterRoleLabelName, specs.ClusterRoleLabelReplica))
+		Expect(setPodRoleLabel(pod, specs.ClusterRoleLabelReplica)).To(BeFalse())
+		Expect(setPodRoleLabel(pod, specs.ClusterRoleLabelPrimary)).To(BeTrue())
+		Expect(pod.Labels).To(HaveKeyWithValue(specs.ClusterRoleLabelName, specs.ClusterRoleLabelPrimary))
+	})
+})
@@ -0,0 +1,255 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+const (
+	// primaryHeartbeatPeriod is the interval between two renewals
+	// of the heartbeat lease
+	primaryHeartbeatPeriod = 5 * time.Second
+
+	// primaryHeartbeatCheckTimeout is the maximum time the check
+	// of PostgreSQL can take
+	primaryHeartbeatCheckTimeout = 5 * time.Second
+
+	// primaryHeartbeatFileName is the name of the file written at every
+	// heartbeat to check that the storage is responding. It is written
+	// in the volume of PGDATA, but outside of it, so that it is not
+	// copied by backups and by the replicas
+	primaryHeartbeatFileName = "cnpg_heartbeat"
+)
+
+// PrimaryHeartbeatWatchdog implements the Runnable interface and, on the
+// primary instance, periodically renews the heartbeat lease of the cluster.
+// The lease is renewed only when PostgreSQL is answering queries and the
+// storage is accepting writes, so that the operator can detect a primary
+// that is hung even if its Pod is running and ready.
+//
+// As the operator promotes another instance once the lease has expired,
+// a primary which has not renewed it shuts itself down shortly before,
+// to stop accepting writes. The check runs separately from the renewal,
+// which may be blocked by a storage which is not responding.
+type PrimaryHeartbeatWatchdog struct {
+	instance *postgres.Instance
+	client   ctrl.Client
+
+	// mutex protects the following fields, written while renewing
+	// the lease and read while checking it
+	mutex sync.Mutex
+
+	// lastRenewal is the last time the lease has been renewed, or has
+	// been found not to be required from this instance
+	lastRenewal time.Time
+
+	// isHolder is true when this instance is required to renew the lease
+	isHolder bool
+
+	// timeout is the heartbeat timeout, as read from the cluster
+	timeout time.Duration
+}
+
+// NewPrimaryHeartbeatWatchdog creates a new PrimaryHeartbeatWatchdog
+// for an instance
+func NewPrimaryHeartbeatWatchdog(instance *postgres.Instance, client ctrl.Client) *PrimaryHeartbeatWatchdog {
+	return &PrimaryHeartbeatWatchdog{
+		instance: instance,
+		client:   client,
+	}
+}
+
+// Start starts renewing the heartbeat lease, and checking that it
+// has been renewed in time
+func (w *PrimaryHeartbeatWatchdog) Start(ctx context.Context) error {
+	w.recordRenewal(time.Now())
+	go func() {
+		_ = runPeriodically(ctx, primaryHeartbeatPeriod,
+			"Cannot renew the primary heartbeat lease", w.beat)
+	}()
+
+	return runPeriodically(ctx, primaryHeartbeatPeriod,
+		"Cannot check the primary heartbeat lease", w.check)
+}
+
+// check shuts down the primary when the heartbeat lease
+// has not been renewed in time
+func (w *PrimaryHeartbeatWatchdog) check(ctx context.Context) error {
+	lastRenewal, expired := w.isSelfFencingRequired(time.Now())
+	if !expired || w.instance.IsFenced() {
+		return nil
+	}
+
+	log.FromContext(ctx).Warning("The primary heartbeat lease has not been renewed for too long, "+
+		"shutting down the primary instance to prevent a split-brain",
+		"lastRenewal", lastRenewal)
+	w.instance.RequestFastImmediateShutdown()
+	return errStopPeriodicCheck
+}
+
+// beat renews the heartbeat lease if the heartbeat is enabled, this
+// instance is the working primary and both PostgreSQL and its storage
+// are responding. A storage which is not responding will block the
+// heartbeat, letting the lease expire
+func (w *PrimaryHeartbeatWatchdog) beat(ctx context.Context) error {
+	var cluster apiv1.Cluster
+	if err := w.client.Get(
+		ctx,
+		ctrl.ObjectKey{Namespace: w.instance.Namespace, Name: w.instance.ClusterName},
+		&cluster,
+	); err != nil {
+		return err
+	}
+
+	isHolder, err := w.isHeartbeatRequired(&cluster)
+	if err != nil {
+		return err
+	}
+	w.recordHolder(isHolder, cluster.GetPrimaryHeartbeatTimeout())
+	if !isHolder {
+		w.recordRenewal(time.Now())
+		return nil
+	}
+
+	if err := w.checkPostgres(ctx); err != nil {
+		return fmt.Errorf("while checking PostgreSQL: %w", err)
+	}
+
+	now := time.Now()
+	if _, err := fileutils.WriteFileAtomic(
+		filepath.Join(filepath.Dir(w.instance.PgData), primaryHeartbeatFileName),
+		[]byte(now.Format(time.RFC3339Nano)),
+		0o600,
+	); err != nil {
+		return fmt.Errorf("while writing the heartbeat file: %w", err)
+	}
+
+	var lease coordinationv1.Lease
+	if err := w.client.Get(
+		ctx,
+		ctrl.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetPrimaryHeartbeatLeaseName()},
+		&lease,
+	); err != nil {
+		return err
+	}
+
+	renewHeartbeatLease(&lease, w.instance.PodName, cluster.Spec.PrimaryHeartbeatTimeout, now)
+	if err := w.client.Update(ctx, &lease); err != nil {
+		return err
+	}
+
+	w.recordRenewal(now)
+	return nil
+}
+
+// isHeartbeatRequired checks if this instance is required to renew the
+// heartbeat lease, being the working primary of a cluster having the
+// heartbeat enabled
+func (w *PrimaryHeartbeatWatchdog) isHeartbeatRequired(cluster *apiv1.Cluster) (bool, error) {
+	if cluster.Spec.PrimaryHeartbeatTimeout == 0 || cluster.Status.CurrentPrimary != w.instance.PodName {
+		return false, nil
+	}
+	if w.instance.IsFenced() {
+		return false, nil
+	}
+
+	return w.instance.IsPrimary()
+}
+
+// recordHolder records if this instance is required to renew the
+// lease, and the heartbeat timeout
+func (w *PrimaryHeartbeatWatchdog) recordHolder(isHolder bool, timeout time.Duration) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.isHolder = isHolder
+	w.timeout = timeout
+}
+
+// recordRenewal records the time of the last renewal of the lease
+func (w *PrimaryHeartbeatWatchdog) recordRenewal(now time.Time) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.lastRenewal = now
+}
+
+// isSelfFencingRequired checks if, at the passed time, this instance
+// holds the lease and has not renewed it for long enough to be considered
+// failed by the operator within the next heartbeat period. It returns
+// the time of the last renewal too
+func (w *PrimaryHeartbeatWatchdog) isSelfFencingRequired(now time.Time) (time.Time, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if !w.isHolder || w.timeout == 0 {
+		return w.lastRenewal, false
+	}
+
+	return w.lastRenewal, now.Sub(w.lastRenewal) > w.timeout-primaryHeartbeatPeriod
+}
+
+// checkPostgres checks that PostgreSQL is answering queries
+func (w *PrimaryHeartbeatWatchdog) checkPostgres(ctx context.Context) error {
+	checkCtx, cancel := context.WithTimeout(ctx, primaryHeartbeatCheckTimeout)
+	defer cancel()
+
+	superUserDB, err := w.instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	var result int
+	return superUserDB.QueryRowContext(checkCtx, "SELECT 1").Scan(&result)
+}
+
+// renewHeartbeatLease marks the lease as held by the passed instance
+// and renewed at the passed time
+func renewHeartbeatLease(lease *coordinationv1.Lease, podName string, timeout int32, now time.Time) {
+	renewTime := metav1.NewMicroTime(now)
+
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != podName {
+		if lease.Spec.HolderIdentity != nil {
+			transitions := int32(1)
+			if lease.Spec.LeaseTransitions != nil {
+				transitions += *lease.Spec.LeaseTransitions
+			}
+			lease.Spec.LeaseTransitions = &transitions
+		}
+		holder := podName
+		lease.Spec.HolderIdentity = &holder
+		lease.Spec.AcquireTime = &renewTime
+	}
+
+	duration := timeout
+	lease.Spec.LeaseDurationSeconds = &duration
+	lease.Spec.RenewTime = &renewTime
+}
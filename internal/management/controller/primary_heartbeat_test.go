@@ -0,0 +1,93 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("primary heartbeat watchdog", func() {
+	now := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	It("acquires a lease which has never been held", func() {
+		var lease coordinationv1.Lease
+		renewHeartbeatLease(&lease, "cluster-example-1", 30, now)
+
+		Expect(*lease.Spec.HolderIdentity).To(Equal("cluster-example-1"))
+		Expect(*lease.Spec.LeaseDurationSeconds).To(BeEquivalentTo(30))
+		Expect(lease.Spec.RenewTime.Time).To(Equal(now))
+		Expect(lease.Spec.AcquireTime.Time).To(Equal(now))
+		Expect(lease.Spec.LeaseTransitions).To(BeNil())
+	})
+
+	It("renews a lease held by the same instance", func() {
+		var lease coordinationv1.Lease
+		renewHeartbeatLease(&lease, "cluster-example-1", 30, now)
+		renewHeartbeatLease(&lease, "cluster-example-1", 30, now.Add(5*time.Second))
+
+		Expect(lease.Spec.RenewTime.Time).To(Equal(now.Add(5 * time.Second)))
+		Expect(lease.Spec.AcquireTime.Time).To(Equal(now))
+		Expect(lease.Spec.LeaseTransitions).To(BeNil())
+	})
+
+	It("takes over a lease held by the former primary", func() {
+		lease := coordinationv1.Lease{
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:   pointer.String("cluster-example-1"),
+				LeaseTransitions: pointer.Int32(1),
+			},
+		}
+		renewHeartbeatLease(&lease, "cluster-example-2", 30, now)
+
+		Expect(*lease.Spec.HolderIdentity).To(Equal("cluster-example-2"))
+		Expect(lease.Spec.AcquireTime.Time).To(Equal(now))
+		Expect(*lease.Spec.LeaseTransitions).To(BeEquivalentTo(2))
+	})
+
+	It("requires the self-fencing of a holder which has not renewed the lease in time", func() {
+		watchdog := PrimaryHeartbeatWatchdog{}
+		watchdog.recordRenewal(now)
+		watchdog.recordHolder(true, 30*time.Second)
+
+		_, required := watchdog.isSelfFencingRequired(now.Add(20 * time.Second))
+		Expect(required).To(BeFalse())
+
+		// The primary stops before the operator considers it failed
+		lastRenewal, required := watchdog.isSelfFencingRequired(now.Add(26 * time.Second))
+		Expect(required).To(BeTrue())
+		Expect(lastRenewal).To(Equal(now))
+	})
+
+	It("doesn't require the self-fencing of an instance not holding the lease", func() {
+		watchdog := PrimaryHeartbeatWatchdog{}
+		watchdog.recordRenewal(now)
+		watchdog.recordHolder(false, 30*time.Second)
+
+		_, required := watchdog.isSelfFencingRequired(now.Add(time.Hour))
+		Expect(required).To(BeFalse())
+
+		watchdog.recordHolder(true, 0)
+		_, required = watchdog.isSelfFencingRequired(now.Add(time.Hour))
+		Expect(required).To(BeFalse())
+	})
+})
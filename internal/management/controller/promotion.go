@@ -0,0 +1,65 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// updatePromotionCondition reports the progress of the promotion of
+// this instance in the Promotion condition of the cluster. A failure
+// in updating the condition doesn't stop the promotion
+func (r *InstanceReconciler) updatePromotionCondition(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	reason apiv1.ConditionReason,
+) {
+	condition := getPromotionCondition(r.instance.PodName, reason)
+	if err := manager.UpdateCondition(ctx, r.client, cluster, condition); err != nil {
+		log.FromContext(ctx).Error(err, "Cannot update the promotion condition", "reason", reason)
+	}
+}
+
+// getPromotionCondition gets the Promotion condition describing
+// the passed step of the promotion of an instance
+func getPromotionCondition(podName string, reason apiv1.ConditionReason) *metav1.Condition {
+	condition := &metav1.Condition{
+		Type:   string(apiv1.ConditionPromotion),
+		Status: metav1.ConditionFalse,
+		Reason: string(reason),
+	}
+
+	switch reason {
+	case apiv1.ConditionReasonPromotionSucceeded:
+		condition.Status = metav1.ConditionTrue
+		condition.Message = fmt.Sprintf("Instance %s has been promoted", podName)
+	case apiv1.ConditionReasonPromotionTimedOut:
+		condition.Message = fmt.Sprintf(
+			"The promotion of instance %s exceeded the timeout, it will be checked again", podName)
+	default:
+		condition.Message = fmt.Sprintf("Promoting instance %s", podName)
+	}
+
+	return condition
+}
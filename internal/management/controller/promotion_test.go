@@ -0,0 +1,47 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Promotion condition", func() {
+	It("reports the promotion in progress", func() {
+		condition := getPromotionCondition("cluster-example-2", apiv1.ConditionReasonPromotionInProgress)
+		Expect(condition.Type).To(Equal(string(apiv1.ConditionPromotion)))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("cluster-example-2"))
+	})
+
+	It("reports the completed promotion", func() {
+		condition := getPromotionCondition("cluster-example-2", apiv1.ConditionReasonPromotionSucceeded)
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonPromotionSucceeded)))
+	})
+
+	It("reports the timed out promotion", func() {
+		condition := getPromotionCondition("cluster-example-2", apiv1.ConditionReasonPromotionTimedOut)
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("timeout"))
+	})
+})
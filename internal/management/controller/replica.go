@@ -22,6 +22,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 )
 
@@ -84,3 +85,45 @@ func (r *InstanceReconciler) writeReplicaConfigurationForDesignatedPrimary(
 
 	return postgres.UpdateReplicaConfigurationForPrimary(r.instance.PgData, connectionString)
 }
+
+// reconcileWALReceiver makes the WAL receiver of a replica connect again
+// when the primary changes, so that it streams from the new primary without
+// waiting for the connection to the old one to time out, and without
+// restarting the instance
+func (r *InstanceReconciler) reconcileWALReceiver(ctx context.Context, cluster *apiv1.Cluster) error {
+	lastKnownPrimary := r.lastKnownPrimary
+	r.lastKnownPrimary = cluster.Status.CurrentPrimary
+	if !isPrimaryChanged(lastKnownPrimary, cluster, r.instance.PodName) {
+		return nil
+	}
+
+	primary, err := r.instance.IsPrimary()
+	if err != nil || primary {
+		return err
+	}
+
+	log.FromContext(ctx).Info("The primary instance changed, reconnecting to the new one",
+		"oldPrimary", lastKnownPrimary,
+		"newPrimary", cluster.Status.CurrentPrimary)
+	_, err = r.instance.RestartWALReceiver()
+	return err
+}
+
+// isPrimaryChanged checks if the current primary of the cluster is
+// different from the one known by the instance. The designated primary of
+// a replica cluster is not affected, as it streams from the source cluster
+func isPrimaryChanged(lastKnownPrimary string, cluster *apiv1.Cluster, podName string) bool {
+	if lastKnownPrimary == "" || cluster.Status.CurrentPrimary == "" {
+		return false
+	}
+
+	if lastKnownPrimary == cluster.Status.CurrentPrimary {
+		return false
+	}
+
+	if cluster.Status.CurrentPrimary == podName || cluster.Status.TargetPrimary == podName {
+		return false
+	}
+
+	return cluster.Status.CurrentPrimary == cluster.Status.TargetPrimary
+}
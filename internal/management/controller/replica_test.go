@@ -0,0 +1,67 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Primary change detection", func() {
+	It("detects a completed switchover", func() {
+		cluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-2",
+				TargetPrimary:  "cluster-example-2",
+			},
+		}
+		Expect(isPrimaryChanged("cluster-example-1", cluster, "cluster-example-3")).To(BeTrue())
+	})
+
+	It("ignores the primary being unchanged or unknown", func() {
+		cluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		Expect(isPrimaryChanged("cluster-example-1", cluster, "cluster-example-3")).To(BeFalse())
+		Expect(isPrimaryChanged("", cluster, "cluster-example-3")).To(BeFalse())
+	})
+
+	It("waits for the switchover to be completed", func() {
+		cluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-2",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		Expect(isPrimaryChanged("cluster-example-1", cluster, "cluster-example-3")).To(BeFalse())
+	})
+
+	It("ignores the new primary itself", func() {
+		cluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-2",
+				TargetPrimary:  "cluster-example-2",
+			},
+		}
+		Expect(isPrimaryChanged("cluster-example-1", cluster, "cluster-example-2")).To(BeFalse())
+	})
+})
@@ -0,0 +1,197 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/lib/pq"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// existingRole is the state of a role in the instance
+type existingRole struct {
+	ConnectionLimit int32
+	Parameters      map[string]string
+}
+
+// reconcileManagedRoles applies the connection limits and the default
+// settings of the roles listed in the managed section of the cluster,
+// reporting the result in the status. It runs only on the primary instance
+func (r *InstanceReconciler) reconcileManagedRoles(ctx context.Context, cluster *apiv1.Cluster) error {
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil || !isPrimary {
+		return err
+	}
+
+	var roles []apiv1.ManagedRole
+	if cluster.Spec.Managed != nil {
+		roles = cluster.Spec.Managed.Roles
+	}
+	if len(roles) == 0 && cluster.Status.ManagedRolesStatus == nil {
+		return nil
+	}
+
+	status := &apiv1.ManagedRolesStatus{}
+	for _, role := range roles {
+		if err := r.reconcileManagedRole(ctx, role); err != nil {
+			log.FromContext(ctx).Info("Cannot reconcile a managed role",
+				"role", role.Name,
+				"err", err)
+			if status.CannotReconcile == nil {
+				status.CannotReconcile = make(map[string]string)
+			}
+			status.CannotReconcile[role.Name] = err.Error()
+			continue
+		}
+		status.Reconciled = append(status.Reconciled, role.Name)
+	}
+	sort.Strings(status.Reconciled)
+
+	if len(roles) == 0 {
+		status = nil
+	}
+	if reflect.DeepEqual(status, cluster.Status.ManagedRolesStatus) {
+		return nil
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.ManagedRolesStatus = status
+	return r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster))
+}
+
+// reconcileManagedRole applies the connection limit and
+// the default settings of a role
+func (r *InstanceReconciler) reconcileManagedRole(ctx context.Context, role apiv1.ManagedRole) error {
+	superUserDB, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	current, err := getExistingRole(ctx, superUserDB, role.Name)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return fmt.Errorf("role %s not found", role.Name)
+	}
+
+	statements := getManagedRoleStatements(role, *current)
+	if len(statements) == 0 {
+		return nil
+	}
+
+	tx, err := superUserDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// This is a no-op when the transaction is committed
+		_ = tx.Rollback()
+	}()
+
+	if _, err := tx.ExecContext(ctx, "SET LOCAL synchronous_commit TO local"); err != nil {
+		return err
+	}
+	for _, statement := range statements {
+		log.FromContext(ctx).Info("Reconciling a managed role",
+			"role", role.Name,
+			"statement", statement)
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// getExistingRole gets the connection limit and the default settings
+// of a role, valid in every database, or nil if it doesn't exist
+func getExistingRole(ctx context.Context, db *sql.DB, name string) (*existingRole, error) {
+	var connectionLimit int32
+	var settings []string
+	row := db.QueryRowContext(ctx,
+		`SELECT r.rolconnlimit, COALESCE(s.setconfig, '{}')
+		FROM pg_roles r LEFT JOIN pg_db_role_setting s ON s.setrole = r.oid AND s.setdatabase = 0
+		WHERE r.rolname = $1`, name)
+	err := row.Scan(&connectionLimit, pq.Array(&settings))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &existingRole{
+		ConnectionLimit: connectionLimit,
+		Parameters:      parseRoleSettings(settings),
+	}, nil
+}
+
+// parseRoleSettings parses the settings of a role,
+// which are stored in the `name=value` format
+func parseRoleSettings(settings []string) map[string]string {
+	result := make(map[string]string, len(settings))
+	for _, setting := range settings {
+		name, value, found := strings.Cut(setting, "=")
+		if found {
+			result[name] = value
+		}
+	}
+
+	return result
+}
+
+// getManagedRoleStatements gets the statements needed to bring a
+// role from its current state to the requested one
+func getManagedRoleStatements(role apiv1.ManagedRole, current existingRole) []string {
+	name := pgx.Identifier{role.Name}.Sanitize()
+
+	var statements []string
+	if role.ConnectionLimit != nil && *role.ConnectionLimit != current.ConnectionLimit {
+		statements = append(statements,
+			fmt.Sprintf("ALTER ROLE %s CONNECTION LIMIT %d", name, *role.ConnectionLimit))
+	}
+
+	parameterNames := make([]string, 0, len(role.Parameters))
+	for parameterName := range role.Parameters {
+		parameterNames = append(parameterNames, parameterName)
+	}
+	sort.Strings(parameterNames)
+
+	for _, parameterName := range parameterNames {
+		value := role.Parameters[parameterName]
+		if currentValue, ok := current.Parameters[parameterName]; ok && currentValue == value {
+			continue
+		}
+		statements = append(statements,
+			fmt.Sprintf("ALTER ROLE %s SET %s TO %s",
+				name, pgx.Identifier{parameterName}.Sanitize(), pq.QuoteLiteral(value)))
+	}
+
+	return statements
+}
@@ -0,0 +1,73 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"k8s.io/utils/pointer"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("managed roles", func() {
+	It("parses the settings of a role", func() {
+		Expect(parseRoleSettings([]string{"statement_timeout=30s", "search_path=app, public"})).To(Equal(
+			map[string]string{
+				"statement_timeout": "30s",
+				"search_path":       "app, public",
+			}))
+	})
+
+	It("changes the connection limit and the settings", func() {
+		role := apiv1.ManagedRole{
+			Name:            "app",
+			ConnectionLimit: pointer.Int32(10),
+			Parameters: map[string]string{
+				"statement_timeout":                   "30s",
+				"idle_in_transaction_session_timeout": "1min",
+			},
+		}
+		Expect(getManagedRoleStatements(role, existingRole{
+			ConnectionLimit: -1,
+			Parameters:      map[string]string{"statement_timeout": "10s"},
+		})).To(Equal([]string{
+			`ALTER ROLE "app" CONNECTION LIMIT 10`,
+			`ALTER ROLE "app" SET "idle_in_transaction_session_timeout" TO '1min'`,
+			`ALTER ROLE "app" SET "statement_timeout" TO '30s'`,
+		}))
+	})
+
+	It("doesn't touch a role in the requested state", func() {
+		role := apiv1.ManagedRole{
+			Name:            "app",
+			ConnectionLimit: pointer.Int32(10),
+			Parameters:      map[string]string{"statement_timeout": "30s"},
+		}
+		Expect(getManagedRoleStatements(role, existingRole{
+			ConnectionLimit: 10,
+			Parameters:      map[string]string{"statement_timeout": "30s", "work_mem": "64MB"},
+		})).To(BeEmpty())
+	})
+
+	It("doesn't manage the connection limit when not specified", func() {
+		Expect(getManagedRoleStatements(apiv1.ManagedRole{Name: "app"}, existingRole{
+			ConnectionLimit: 5,
+		})).To(BeEmpty())
+	})
+})
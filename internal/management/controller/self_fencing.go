@@ -0,0 +1,130 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+const (
+	// selfFencingCheckPeriod is the interval between two checks
+	// of the connection to the API server
+	selfFencingCheckPeriod = 5 * time.Second
+
+	// selfFencingCheckTimeout is the maximum time a check of the
+	// connection to the API server can take
+	selfFencingCheckTimeout = 5 * time.Second
+)
+
+// SelfFencingWatchdog implements the Runnable interface and shuts down the
+// primary instance when the API server has not been reachable for longer than
+// the self-fencing timeout of the cluster. In that case the operator may
+// have promoted another instance, and we must stop accepting writes.
+//
+// The instance manager will then be restarted, and PostgreSQL will be started
+// again only after the API server is reachable, demoting the instance if
+// it is not the primary anymore.
+type SelfFencingWatchdog struct {
+	instance *postgres.Instance
+	reader   ctrl.Reader
+
+	// lastContact is the last time the API server has been reached
+	lastContact time.Time
+
+	// timeout is the self-fencing timeout, as read from the cluster
+	timeout time.Duration
+}
+
+// NewSelfFencingWatchdog creates a new SelfFencingWatchdog for an instance,
+// using the passed reader to reach the API server without using any cache
+func NewSelfFencingWatchdog(instance *postgres.Instance, reader ctrl.Reader) *SelfFencingWatchdog {
+	return &SelfFencingWatchdog{
+		instance: instance,
+		reader:   reader,
+	}
+}
+
+// Start starts checking the connection to the API server
+func (w *SelfFencingWatchdog) Start(ctx context.Context) error {
+	w.lastContact = time.Now()
+	return runPeriodically(ctx, selfFencingCheckPeriod,
+		"Cannot check the connection to the API server", w.check)
+}
+
+// check checks the connection to the API server, shutting down the
+// primary when the API server has not been reachable for too long
+func (w *SelfFencingWatchdog) check(ctx context.Context) error {
+	w.checkAPIServer(ctx)
+	if !w.isTimeoutExpired(time.Now()) {
+		return nil
+	}
+
+	if isPrimary, err := w.instance.IsPrimary(); err != nil || !isPrimary {
+		return nil
+	}
+	if w.instance.IsFenced() || w.instance.IsServerHealthy() != nil {
+		return nil
+	}
+
+	log.FromContext(ctx).Warning("The API server has not been reachable for too long, "+
+		"shutting down the primary instance to prevent a split-brain",
+		"lastContact", w.lastContact,
+		"selfFencingTimeout", w.timeout)
+	w.instance.RequestFastImmediateShutdown()
+	return errStopPeriodicCheck
+}
+
+// checkAPIServer tries to reach the API server, updating the time of the
+// last contact and the self-fencing timeout on success
+func (w *SelfFencingWatchdog) checkAPIServer(ctx context.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx, selfFencingCheckTimeout)
+	defer cancel()
+
+	var cluster apiv1.Cluster
+	err := w.reader.Get(
+		checkCtx,
+		ctrl.ObjectKey{Namespace: w.instance.Namespace, Name: w.instance.ClusterName},
+		&cluster,
+	)
+	switch {
+	case err == nil:
+		w.timeout = time.Duration(cluster.Spec.SelfFencingTimeout) * time.Second
+	case apierrors.IsNotFound(err):
+		// The API server answered, even if the cluster is being deleted
+	default:
+		log.FromContext(ctx).Info("Cannot reach the API server",
+			"err", err,
+			"lastContact", w.lastContact)
+		return
+	}
+
+	w.lastContact = time.Now()
+}
+
+// isTimeoutExpired checks if, at the passed time, the API server has not
+// been reachable for longer than the self-fencing timeout
+func (w *SelfFencingWatchdog) isTimeoutExpired(now time.Time) bool {
+	return w.timeout > 0 && now.Sub(w.lastContact) > w.timeout
+}
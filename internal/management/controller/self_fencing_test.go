@@ -0,0 +1,40 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("self-fencing watchdog", func() {
+	lastContact := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	It("never expires when the self-fencing is disabled", func() {
+		watchdog := &SelfFencingWatchdog{lastContact: lastContact}
+		Expect(watchdog.isTimeoutExpired(lastContact.Add(time.Hour))).To(BeFalse())
+	})
+
+	It("expires when the API server has not been reached for longer than the timeout", func() {
+		watchdog := &SelfFencingWatchdog{lastContact: lastContact, timeout: 30 * time.Second}
+		Expect(watchdog.isTimeoutExpired(lastContact.Add(10 * time.Second))).To(BeFalse())
+		Expect(watchdog.isTimeoutExpired(lastContact.Add(30 * time.Second))).To(BeFalse())
+		Expect(watchdog.isTimeoutExpired(lastContact.Add(31 * time.Second))).To(BeTrue())
+	})
+})
@@ -0,0 +1,212 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notifications sends the lifecycle events of the clusters to
+// the webhook configured in the operator configuration, allowing
+// teams to be alerted without watching the Kubernetes events
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// EventType is the type of lifecycle event being notified
+type EventType string
+
+const (
+	// EventFailover is notified when a failover is started
+	EventFailover = EventType("Failover")
+
+	// EventSwitchover is notified when a switchover is started
+	EventSwitchover = EventType("Switchover")
+
+	// EventBackupFailed is notified when a backup fails
+	EventBackupFailed = EventType("BackupFailed")
+
+	// EventCertificateExpiring is notified when a certificate
+	// which cannot be renewed by the operator is expiring
+	EventCertificateExpiring = EventType("CertificateExpiring")
+
+	// EventSplitBrainSuspected is notified when the instances
+	// don't agree on the primary or on its timeline
+	EventSplitBrainSuspected = EventType("SplitBrainSuspected")
+)
+
+// DefaultPayloadTemplate is the template used to build the payload
+// of the notifications when no template is configured
+const DefaultPayloadTemplate = `{"type":{{json .Type}},"namespace":{{json .Namespace}},` +
+	`"cluster":{{json .Cluster}},"message":{{json .Message}},"time":{{json .Time}}}`
+
+// requestTimeout is the maximum duration of a request to the webhook
+const requestTimeout = 10 * time.Second
+
+// throttlePeriods contains, for the events which are detected at every
+// reconciliation loop, the period in which an identical notification
+// is not sent again
+var throttlePeriods = map[EventType]time.Duration{
+	EventCertificateExpiring: 24 * time.Hour,
+}
+
+// Event is a lifecycle event of a cluster
+type Event struct {
+	// The type of the event
+	Type EventType
+
+	// The namespace of the cluster
+	Namespace string
+
+	// The name of the cluster
+	Cluster string
+
+	// A human-readable description of the event
+	Message string
+
+	// The time when the event happened
+	Time time.Time
+}
+
+// Notifier sends the events to the configured webhook
+type Notifier struct {
+	client *http.Client
+
+	mu sync.Mutex
+	// lastSent contains the time when each throttled event was last sent
+	lastSent map[string]time.Time
+}
+
+// defaultNotifier is the notifier used by the operator
+var defaultNotifier = NewNotifier(&http.Client{Timeout: requestTimeout})
+
+// NewNotifier creates a new notifier using the passed HTTP client
+func NewNotifier(client *http.Client) *Notifier {
+	return &Notifier{
+		client:   client,
+		lastSent: make(map[string]time.Time),
+	}
+}
+
+// Notify sends an event to the webhook configured in the
+// operator configuration, if any, using the default notifier
+func Notify(ctx context.Context, eventType EventType, namespace, cluster, message string) {
+	defaultNotifier.Notify(ctx, configuration.Current(), Event{
+		Type:      eventType,
+		Namespace: namespace,
+		Cluster:   cluster,
+		Message:   message,
+		Time:      time.Now(),
+	})
+}
+
+// Notify sends an event to the webhook configured in the passed
+// operator configuration, if any. The request is executed in
+// background, and its failures are only logged
+func (n *Notifier) Notify(ctx context.Context, config *configuration.Data, event Event) {
+	if config.NotificationWebhookURL == "" || n.isThrottled(event) {
+		return
+	}
+
+	contextLogger := log.FromContext(ctx)
+	payload, err := buildPayload(config.NotificationPayloadTemplate, event)
+	if err != nil {
+		contextLogger.Error(err, "Cannot build the notification payload", "type", event.Type)
+		return
+	}
+
+	go func() {
+		if err := n.send(context.Background(), config.NotificationWebhookURL, payload); err != nil {
+			contextLogger.Error(err, "Cannot send the notification", "type", event.Type)
+		}
+	}()
+}
+
+// isThrottled checks if an identical event has been sent recently,
+// recording the current one otherwise
+func (n *Notifier) isThrottled(event Event) bool {
+	period, ok := throttlePeriods[event.Type]
+	if !ok {
+		return false
+	}
+
+	key := strings.Join([]string{string(event.Type), event.Namespace, event.Cluster, event.Message}, "/")
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if lastSent, found := n.lastSent[key]; found && event.Time.Sub(lastSent) < period {
+		return true
+	}
+	n.lastSent[key] = event.Time
+	return false
+}
+
+// send posts the payload to the webhook
+func (n *Notifier) send(ctx context.Context, url string, payload []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("the webhook returned the status %s", resp.Status)
+	}
+	return nil
+}
+
+// buildPayload builds the payload of a notification from
+// the template, using the default one when empty
+func buildPayload(payloadTemplate string, event Event) ([]byte, error) {
+	if payloadTemplate == "" {
+		payloadTemplate = DefaultPayloadTemplate
+	}
+
+	tmpl, err := template.New("payload").Funcs(template.FuncMap{
+		"json": func(value interface{}) (string, error) {
+			result, err := json.Marshal(value)
+			return string(result), err
+		},
+	}).Parse(payloadTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid notification payload template: %w", err)
+	}
+
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, event); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,125 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Notification payload", func() {
+	event := Event{
+		Type:      EventFailover,
+		Namespace: "default",
+		Cluster:   "cluster-example",
+		Message:   `Failing over from "cluster-example-1"`,
+		Time:      time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	It("uses a JSON object by default", func() {
+		payload, err := buildPayload("", event)
+		Expect(err).ToNot(HaveOccurred())
+
+		var result map[string]string
+		Expect(json.Unmarshal(payload, &result)).To(Succeed())
+		Expect(result).To(Equal(map[string]string{
+			"type":      "Failover",
+			"namespace": "default",
+			"cluster":   "cluster-example",
+			"message":   `Failing over from "cluster-example-1"`,
+			"time":      "2022-01-01T00:00:00Z",
+		}))
+	})
+
+	It("uses the configured template", func() {
+		payload, err := buildPayload(`{"text":{{printf "%s/%s: %s" .Namespace .Cluster .Message | json}}}`, event)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(payload)).To(Equal(
+			`{"text":"default/cluster-example: Failing over from \"cluster-example-1\""}`))
+	})
+
+	It("complains about invalid templates", func() {
+		_, err := buildPayload(`{{.Unknown`, event)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Notifier", func() {
+	var (
+		server   *httptest.Server
+		payloads chan string
+	)
+
+	BeforeEach(func() {
+		payloads = make(chan string, 10)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			payloads <- r.Header.Get("Content-Type") + " " + string(body)
+		}))
+		DeferCleanup(server.Close)
+	})
+
+	It("doesn't send anything when no webhook is configured", func() {
+		notifier := NewNotifier(server.Client())
+		notifier.Notify(context.Background(), &configuration.Data{}, Event{Type: EventFailover})
+		Consistently(payloads, 200*time.Millisecond).ShouldNot(Receive())
+	})
+
+	It("posts the payload to the webhook", func() {
+		notifier := NewNotifier(server.Client())
+		config := &configuration.Data{
+			NotificationWebhookURL:      server.URL,
+			NotificationPayloadTemplate: "{{.Type}} {{.Cluster}}",
+		}
+		notifier.Notify(context.Background(), config, Event{Type: EventBackupFailed, Cluster: "cluster-example"})
+		Eventually(payloads).Should(Receive(Equal("application/json BackupFailed cluster-example")))
+	})
+
+	It("throttles the identical certificate expiration events", func() {
+		notifier := NewNotifier(server.Client())
+		now := time.Now()
+		event := Event{Type: EventCertificateExpiring, Cluster: "cluster-example", Time: now}
+		Expect(notifier.isThrottled(event)).To(BeFalse())
+		Expect(notifier.isThrottled(event)).To(BeTrue())
+
+		event.Time = now.Add(25 * time.Hour)
+		Expect(notifier.isThrottled(event)).To(BeFalse())
+
+		failover := Event{Type: EventFailover, Cluster: "cluster-example", Time: now}
+		Expect(notifier.isThrottled(failover)).To(BeFalse())
+		Expect(notifier.isThrottled(failover)).To(BeFalse())
+	})
+
+	It("reports the errors returned by the webhook", func() {
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer failing.Close()
+
+		notifier := NewNotifier(failing.Client())
+		Expect(notifier.send(context.Background(), failing.URL, []byte("{}"))).ToNot(Succeed())
+	})
+})
@@ -38,6 +38,10 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
+// OperatorCASecretName is the name of the secret, in the operator
+// namespace, which is hosting the operator CA
+const OperatorCASecretName = "cnpg-ca-secret" // #nosec
+
 var (
 	pkiLog = log.WithName("pki")
 
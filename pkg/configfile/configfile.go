@@ -21,6 +21,7 @@ package configfile
 import (
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 
 	"github.com/lib/pq"
@@ -120,3 +121,59 @@ func RemoveOptionFromConfigurationContents(content string, option string) string
 
 	return strings.Join(resultContent, "\n") + "\n"
 }
+
+// RemoveUnknownOptionsFromConfigurationContents deletes the lines containing
+// any option not included in the passed list from a configuration file
+// whose content is passed. The names of the removed options are returned too
+func RemoveUnknownOptionsFromConfigurationContents(
+	content string,
+	knownOptions []string,
+) (result string, removedOptions []string) {
+	known := stringset.From(knownOptions)
+	return RemoveMatchingOptionsFromConfigurationContents(content, func(option string) bool {
+		return !known.Has(option)
+	})
+}
+
+// RemoveMatchingOptionsFromConfigurationContents deletes the lines containing
+// the options matched by the passed function from a configuration file
+// whose content is passed. The names of the removed options are returned too
+func RemoveMatchingOptionsFromConfigurationContents(
+	content string,
+	matches func(option string) bool,
+) (result string, removedOptions []string) {
+	removed := stringset.New()
+	resultContent := []string{}
+
+	for _, line := range splitLines(content) {
+		// Keep empty lines and comments
+		trimLine := strings.TrimSpace(line)
+		if len(trimLine) == 0 || trimLine[0] == '#' {
+			resultContent = append(resultContent, line)
+			continue
+		}
+
+		// If we find a line containing a matching option,
+		// we skip it
+		if option := getOptionName(trimLine); matches(option) {
+			removed.Put(option)
+			continue
+		}
+
+		resultContent = append(resultContent, line)
+	}
+
+	removedOptions = removed.ToList()
+	sort.Strings(removedOptions)
+	return strings.Join(resultContent, "\n") + "\n", removedOptions
+}
+
+// getOptionName gets the name of the option contained in a line of a
+// configuration file, which can be separated from its value by an equal
+// sign or by whitespace
+func getOptionName(line string) string {
+	if index := strings.IndexAny(line, "= \t"); index >= 0 {
+		return line[:index]
+	}
+	return line
+}
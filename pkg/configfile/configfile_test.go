@@ -150,3 +150,40 @@ var _ = Describe("Remove configuration files option", func() {
 		Expect(updatedContent).To(Equal(wantedContent))
 	})
 })
+
+var _ = Describe("Remove unknown configuration files options", func() {
+	knownOptions := []string{"primary_conninfo", "recovery_target_timeline"}
+
+	It("keeps the initial input if there are only known options", func() {
+		initialContent := "# Do not edit this file manually!\n" +
+			"# It will be overwritten by the ALTER SYSTEM command.\n" +
+			"primary_conninfo = 'host=someHost user=someUser application_name=nodeName'\n" +
+			"recovery_target_timeline = 'latest'\n"
+
+		updatedContent, removedOptions := RemoveUnknownOptionsFromConfigurationContents(
+			initialContent, knownOptions)
+
+		Expect(updatedContent).To(Equal(initialContent))
+		Expect(removedOptions).To(BeEmpty())
+	})
+
+	It("must delete the lines with unknown options", func() {
+		initialContent := "# Do not edit this file manually!\n" +
+			"# It will be overwritten by the ALTER SYSTEM command.\n" +
+			"work_mem = '64MB'\n" +
+			"primary_conninfo = 'host=someHost user=someUser application_name=nodeName'\n" +
+			"recovery_target_timeline = 'latest'\n" +
+			"archive_timeout = '10min'\n"
+
+		updatedContent, removedOptions := RemoveUnknownOptionsFromConfigurationContents(
+			initialContent, knownOptions)
+
+		wantedContent := "# Do not edit this file manually!\n" +
+			"# It will be overwritten by the ALTER SYSTEM command.\n" +
+			"primary_conninfo = 'host=someHost user=someUser application_name=nodeName'\n" +
+			"recovery_target_timeline = 'latest'\n"
+
+		Expect(updatedContent).To(Equal(wantedContent))
+		Expect(removedOptions).To(Equal([]string{"archive_timeout", "work_mem"}))
+	})
+})
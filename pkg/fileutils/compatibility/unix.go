@@ -33,3 +33,15 @@ func CreateFifo(fileName string) error {
 	}
 	return nil
 }
+
+// GetFilesystemUsage gets the total and the available size in bytes
+// of the filesystem containing the passed path
+func GetFilesystemUsage(path string) (total uint64, available uint64, err error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+
+	blockSize := uint64(stat.Bsize)
+	return stat.Blocks * blockSize, stat.Bavail * blockSize, nil
+}
@@ -23,6 +23,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"time"
@@ -349,6 +350,53 @@ func MoveDirectoryContent(sourceDirectory, destinationDirectory string) error {
 	return RemoveDirectoryContent(sourceDirectory)
 }
 
+// CopyDirectory copies the content of a directory into the destination
+// one, which is created when needed, preserving the permissions of the
+// files and the symbolic links. Existing files are overwritten
+func CopyDirectory(sourceDirectory, destinationDirectory string) error {
+	return filepath.WalkDir(sourceDirectory, func(sourcePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relativePath, err := filepath.Rel(sourceDirectory, sourcePath)
+		if err != nil {
+			return err
+		}
+		destinationPath := filepath.Join(destinationDirectory, relativePath)
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case entry.IsDir():
+			return os.MkdirAll(destinationPath, info.Mode().Perm()|0o700)
+
+		case info.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(sourcePath)
+			if err != nil {
+				return err
+			}
+			if err := RemoveFile(destinationPath); err != nil {
+				return err
+			}
+			return os.Symlink(target, destinationPath)
+
+		default:
+			// Read-only files cannot be overwritten
+			if err := RemoveFile(destinationPath); err != nil {
+				return err
+			}
+			if err := CopyFile(sourcePath, destinationPath); err != nil {
+				return err
+			}
+			return os.Chmod(destinationPath, info.Mode().Perm())
+		}
+	})
+}
+
 // GetFileSize returns the size of a file or an error
 func GetFileSize(fileName string) (int64, error) {
 	stat, err := os.Stat(fileName)
@@ -144,3 +144,36 @@ var _ = Describe("function GetDirectoryContent", func() {
 		Expect(files).Should(ConsistOf(testFiles))
 	})
 })
+
+var _ = Describe("function CopyDirectory", func() {
+	It("copies the content of a directory", func() {
+		source := path.Join(tempDir1, "copy-source")
+		destination := path.Join(tempDir1, "copy-destination")
+
+		Expect(os.MkdirAll(path.Join(source, "extension"), 0o700)).To(Succeed())
+		Expect(os.WriteFile(path.Join(source, "extension", "vector.control"), []byte("control"), 0o444)).
+			To(Succeed())
+		Expect(os.WriteFile(path.Join(source, "vector.so"), []byte("library"), 0o755)).To(Succeed())
+		Expect(os.Symlink("vector.so", path.Join(source, "vector-latest.so"))).To(Succeed())
+
+		// The destination files are overwritten, even when they are read-only
+		Expect(CopyDirectory(source, destination)).To(Succeed())
+		Expect(CopyDirectory(source, destination)).To(Succeed())
+
+		content, err := os.ReadFile(path.Join(destination, "extension", "vector.control"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("control"))
+
+		info, err := os.Stat(path.Join(destination, "vector.so"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o755)))
+
+		target, err := os.Readlink(path.Join(destination, "vector-latest.so"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(target).To(Equal("vector.so"))
+	})
+
+	It("fails when the source directory doesn't exist", func() {
+		Expect(CopyDirectory(path.Join(tempDir1, "missing"), path.Join(tempDir1, "target"))).ToNot(Succeed())
+	})
+})
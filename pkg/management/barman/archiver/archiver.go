@@ -45,6 +45,22 @@ type WALArchiver struct {
 
 	// The environment that should be used to invoke barman-cloud-wal-archive
 	env []string
+
+	// The additional object stores where the WAL files are archived too
+	destinations []Destination
+}
+
+// Destination is an additional object store where the WAL files are
+// archived after the main one
+type Destination struct {
+	// The name of the additional object store
+	Name string
+
+	// The environment that should be used to invoke barman-cloud-wal-archive
+	Env []string
+
+	// The options to be passed to barman-cloud-wal-archive
+	Options []string
 }
 
 // WALArchiverResult contains the result of the archival of one WAL
@@ -55,6 +71,10 @@ type WALArchiverResult struct {
 	// If not nil, this is the error that has been detected
 	Err error
 
+	// The errors raised by the additional object stores, indexed by their
+	// name. They don't make the archival fail, and are only reported
+	DestinationErrors map[string]error
+
 	// The time when we started barman-cloud-wal-archive
 	StartTime time.Time
 
@@ -85,6 +105,12 @@ func New(
 	return archiver, nil
 }
 
+// AddDestination adds an additional object store where the WAL files
+// will be archived
+func (archiver *WALArchiver) AddDestination(destination Destination) {
+	archiver.destinations = append(archiver.destinations, destination)
+}
+
 // DeleteFromSpool checks if a WAL file is in the spool and, if it is, remove it
 func (archiver *WALArchiver) DeleteFromSpool(walName string) (hasBeenDeleted bool, err error) {
 	var isContained bool
@@ -115,7 +141,7 @@ func (archiver *WALArchiver) ArchiveList(
 			walStatus := &result[walIndex]
 			walStatus.WalName = walNames[walIndex]
 			walStatus.StartTime = time.Now()
-			walStatus.Err = archiver.Archive(walNames[walIndex], options)
+			walStatus.DestinationErrors, walStatus.Err = archiver.Archive(walNames[walIndex], options)
 			walStatus.EndTime = time.Now()
 			if walStatus.Err == nil && walIndex != 0 {
 				walStatus.Err = archiver.spool.Touch(walNames[walIndex])
@@ -147,9 +173,46 @@ func (archiver *WALArchiver) ArchiveList(
 	return result
 }
 
-// Archive archives a certain WAL file using barman-cloud-wal-archive.
+// Archive archives a certain WAL file using barman-cloud-wal-archive,
+// in the main object store and then in every additional one.
+// Only the main object store can make the archival fail: the errors of
+// the additional ones are returned indexed by their name, as a failing
+// secondary object store must never block the WAL archiving.
 // See archiveWALFileList for the meaning of the parameters
-func (archiver *WALArchiver) Archive(walName string, baseOptions []string) error {
+func (archiver *WALArchiver) Archive(
+	walName string,
+	baseOptions []string,
+) (destinationErrors map[string]error, err error) {
+	if err := archiver.archive(walName, archiver.env, baseOptions); err != nil {
+		return nil, err
+	}
+
+	for _, destination := range archiver.destinations {
+		if err := archiver.archive(walName, destination.Env, destination.Options); err != nil {
+			log.Warning("Failed archiving WAL in an additional object store",
+				"walName", walName,
+				"objectStore", destination.Name,
+				"error", err)
+			if destinationErrors == nil {
+				destinationErrors = make(map[string]error)
+			}
+			destinationErrors[destination.Name] = err
+		}
+	}
+
+	return destinationErrors, nil
+}
+
+// GetDestinationNames gets the names of the additional object stores
+func (archiver *WALArchiver) GetDestinationNames() []string {
+	names := make([]string, len(archiver.destinations))
+	for idx, destination := range archiver.destinations {
+		names[idx] = destination.Name
+	}
+	return names
+}
+
+func (archiver *WALArchiver) archive(walName string, env []string, baseOptions []string) error {
 	optionsLength := len(baseOptions)
 	if optionsLength >= math.MaxInt-1 {
 		return fmt.Errorf("can't archive wal file %v, options too long", walName)
@@ -166,7 +229,7 @@ func (archiver *WALArchiver) Archive(walName string, baseOptions []string) error
 	)
 
 	barmanCloudWalArchiveCmd := exec.Command(barmanCapabilities.BarmanCloudWalArchive, options...) // #nosec G204
-	barmanCloudWalArchiveCmd.Env = archiver.env
+	barmanCloudWalArchiveCmd.Env = env
 
 	err := execlog.RunStreaming(barmanCloudWalArchiveCmd, barmanCapabilities.BarmanCloudWalArchive)
 	if err != nil {
@@ -159,6 +159,40 @@ func DeleteBackupsNotInCatalog(
 	return nil
 }
 
+// FindOrphanedBackups gets the IDs of the completed backups of the catalog
+// which are not referenced by any Backup object of the given cluster, and
+// the range of WAL files needed only to recover from them
+func FindOrphanedBackups(
+	ctx context.Context,
+	cli client.Client,
+	cluster *v1.Cluster,
+	catalog *catalog.Catalog,
+) ([]string, *v1.WALRange, error) {
+	backups := v1.BackupList{}
+	err := cli.List(ctx, &backups, client.InNamespace(cluster.GetNamespace()))
+	if err != nil {
+		return nil, nil, fmt.Errorf("while getting backups: %w", err)
+	}
+
+	referencedBackups := make(map[string]bool, len(backups.Items))
+	for idx := range backups.Items {
+		backup := &backups.Items[idx]
+		// Backups which are still running are referenced too, as their
+		// status may have not been updated yet
+		if backup.Spec.Cluster.Name != cluster.GetName() ||
+			backup.Status.BackupID == "" ||
+			!useSameBackupLocation(&backup.Status, cluster) {
+			continue
+		}
+		referencedBackups[backup.Status.BackupID] = true
+	}
+
+	orphanedBackups, walRange := catalog.FindOrphanedBackups(func(backupID string) bool {
+		return referencedBackups[backupID]
+	})
+	return orphanedBackups, walRange, nil
+}
+
 // useSameBackupLocation checks whether the given backup was taken using the same configuration as provided
 func useSameBackupLocation(backup *v1.BackupStatus, cluster *v1.Cluster) bool {
 	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
@@ -47,6 +47,10 @@ func Detect() (*Capabilities, error) {
 	newCapabilities.Version = version
 
 	switch {
+	case version.GE(semver.Version{Major: 2, Minor: 19}):
+		// Upload bandwidth limit for base backups, added in Barman >= 2.19
+		newCapabilities.HasMaxBandwidth = true
+		fallthrough
 	case version.GE(semver.Version{Major: 2, Minor: 18}):
 		// Tags, added in Barman >= 2.18
 		newCapabilities.HasTags = true
@@ -32,5 +32,6 @@ type Capabilities struct {
 	HasSnappy                  bool
 	HasErrorCodesForWALRestore bool
 	HasAzureManagedIdentity    bool
+	HasMaxBandwidth            bool
 	Version                    *semver.Version
 }
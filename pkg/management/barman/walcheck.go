@@ -0,0 +1,108 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barman
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+
+	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+)
+
+// CheckBackupWALs checks that the WAL files where the backup with the
+// given ID started and ended can be retrieved from the object store.
+// The WAL files are downloaded into the scratch directory, and removed
+// right after
+func CheckBackupWALs(
+	barmanConfiguration *v1.BarmanObjectStoreConfiguration,
+	backupList *catalog.Catalog,
+	backupID string,
+	serverName string,
+	scratchDirectory string,
+	env []string,
+) error {
+	backup, err := backupList.FindBackupFromID(backupID)
+	if err != nil {
+		return err
+	}
+
+	var options []string
+	if barmanConfiguration.EndpointURL != "" {
+		options = append(options, "--endpoint-url", barmanConfiguration.EndpointURL)
+	}
+
+	options, err = AppendCloudProviderOptionsFromConfiguration(options, barmanConfiguration)
+	if err != nil {
+		return err
+	}
+
+	options = append(options, barmanConfiguration.DestinationPath, serverName)
+
+	for _, walName := range requiredWALFiles(backup) {
+		if err := fetchWALFile(options, walName, scratchDirectory, env); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// requiredWALFiles gets the WAL files that must be available in the
+// object store for a backup to be restored
+func requiredWALFiles(backup *catalog.BarmanBackup) []string {
+	var result []string
+	if backup.BeginWal != "" {
+		result = append(result, backup.BeginWal)
+	}
+	if backup.EndWal != "" && backup.EndWal != backup.BeginWal {
+		result = append(result, backup.EndWal)
+	}
+	return result
+}
+
+// fetchWALFile downloads a WAL file from the object store into the
+// scratch directory, and then removes it
+func fetchWALFile(baseOptions []string, walName string, scratchDirectory string, env []string) error {
+	destinationPath := path.Join(scratchDirectory, walName)
+	options := make([]string, len(baseOptions), len(baseOptions)+2)
+	copy(options, baseOptions)
+	options = append(options, walName, destinationPath)
+
+	var stdoutBuffer bytes.Buffer
+	var stderrBuffer bytes.Buffer
+	cmd := exec.Command(barmanCapabilities.BarmanCloudWalRestore, options...) // #nosec G204
+	cmd.Env = env
+	cmd.Stdout = &stdoutBuffer
+	cmd.Stderr = &stderrBuffer
+	err := cmd.Run()
+	if err != nil {
+		barmanLog.Error(err,
+			"Can't retrieve WAL file using "+barmanCapabilities.BarmanCloudWalRestore,
+			"walName", walName,
+			"options", options,
+			"stdout", stdoutBuffer.String(),
+			"stderr", stderrBuffer.String())
+		return fmt.Errorf("while retrieving WAL file %s: %w", walName, err)
+	}
+
+	return os.Remove(destinationPath)
+}
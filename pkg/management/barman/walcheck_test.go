@@ -0,0 +1,45 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barman
+
+import (
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("requiredWALFiles", func() {
+	It("requires the WAL files where the backup started and ended", func() {
+		backup := &catalog.BarmanBackup{
+			BeginWal: "000000010000000000000006",
+			EndWal:   "000000010000000000000008",
+		}
+		Expect(requiredWALFiles(backup)).To(Equal([]string{
+			"000000010000000000000006",
+			"000000010000000000000008",
+		}))
+	})
+
+	It("requires the same WAL file only once", func() {
+		backup := &catalog.BarmanBackup{
+			BeginWal: "000000010000000000000006",
+			EndWal:   "000000010000000000000006",
+		}
+		Expect(requiredWALFiles(backup)).To(Equal([]string{"000000010000000000000006"}))
+	})
+})
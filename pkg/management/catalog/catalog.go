@@ -81,7 +81,7 @@ func (catalog *Catalog) FindBackupInfo(recoveryTarget *v1.RecoveryTarget) (*Barm
 	// Check that BackupID is not empty. In such case, always use the
 	// backup ID provided by the user.
 	if recoveryTarget.BackupID != "" {
-		return catalog.findBackupFromID(recoveryTarget.BackupID)
+		return catalog.FindBackupFromID(recoveryTarget.BackupID)
 	}
 
 	// The user has not specified any backup ID. As a result we need
@@ -170,7 +170,8 @@ func (catalog *Catalog) findlatestBackupFromTimeline(targetTLI string) *BarmanBa
 	return nil
 }
 
-func (catalog *Catalog) findBackupFromID(backupID string) (*BarmanBackup, error) {
+// FindBackupFromID finds the completed backup with the given ID
+func (catalog *Catalog) FindBackupFromID(backupID string) (*BarmanBackup, error) {
 	if backupID == "" {
 		return nil, fmt.Errorf("no backupID provided")
 	}
@@ -185,6 +186,41 @@ func (catalog *Catalog) findBackupFromID(backupID string) (*BarmanBackup, error)
 	return nil, fmt.Errorf("no backup found with ID %s", backupID)
 }
 
+// FindOrphanedBackups gets the IDs of the completed backups which are not
+// referenced, together with the range of WAL files needed only to recover
+// from the orphaned backups preceding the first referenced one. The range
+// is nil when no referenced backup is preceded by an orphaned one
+func (catalog *Catalog) FindOrphanedBackups(isReferenced func(backupID string) bool) ([]string, *v1.WALRange) {
+	var orphanedBackups []string
+	var walRange *v1.WALRange
+	var firstReferencedFound bool
+
+	for _, barmanBackup := range catalog.List {
+		if !barmanBackup.isBackupDone() {
+			continue
+		}
+
+		if isReferenced(barmanBackup.ID) {
+			if !firstReferencedFound && walRange != nil {
+				walRange.End = barmanBackup.BeginWal
+			}
+			firstReferencedFound = true
+			continue
+		}
+
+		if !firstReferencedFound && walRange == nil {
+			walRange = &v1.WALRange{Begin: barmanBackup.BeginWal}
+		}
+		orphanedBackups = append(orphanedBackups, barmanBackup.ID)
+	}
+
+	if !firstReferencedFound {
+		walRange = nil
+	}
+
+	return orphanedBackups, walRange
+}
+
 // BarmanBackup represent a backup as created
 // by Barman
 type BarmanBackup struct {
@@ -47,6 +47,46 @@ var _ = Describe("Backup catalog", func() {
 		},
 	})
 
+	It("finds the orphaned backups and the WAL files needed only by them", func() {
+		referencedBackups := map[string]bool{"202101021200": true}
+		orphanedBackups, walRange := NewCatalog([]BarmanBackup{
+			{
+				ID:        "202101011200",
+				BeginTime: time.Date(2021, 1, 1, 12, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(2021, 1, 1, 12, 30, 0, 0, time.UTC),
+				BeginWal:  "000000010000000000000002",
+			},
+			{
+				ID:        "202101021200",
+				BeginTime: time.Date(2021, 1, 2, 12, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(2021, 1, 2, 12, 30, 0, 0, time.UTC),
+				BeginWal:  "000000010000000000000008",
+			},
+			{
+				ID:        "202101031200",
+				BeginTime: time.Date(2021, 1, 3, 12, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(2021, 1, 3, 12, 30, 0, 0, time.UTC),
+				BeginWal:  "00000001000000000000000F",
+			},
+			{
+				ID:        "202101041200",
+				BeginTime: time.Date(2021, 1, 4, 12, 0, 0, 0, time.UTC),
+				BeginWal:  "000000010000000000000014",
+			},
+		}).FindOrphanedBackups(func(backupID string) bool { return referencedBackups[backupID] })
+		Expect(orphanedBackups).To(Equal([]string{"202101011200", "202101031200"}))
+		Expect(walRange).To(Equal(&v1.WALRange{
+			Begin: "000000010000000000000002",
+			End:   "000000010000000000000008",
+		}))
+	})
+
+	It("doesn't report a WAL range when no backup is referenced", func() {
+		orphanedBackups, walRange := catalog.FindOrphanedBackups(func(string) bool { return false })
+		Expect(orphanedBackups).To(HaveLen(3))
+		Expect(walRange).To(BeNil())
+	})
+
 	It("contains sorted data", func() {
 		Expect(len(catalog.List)).To(Equal(3))
 		Expect(catalog.List[0].ID).To(Equal("202101011200"))
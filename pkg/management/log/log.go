@@ -25,6 +25,7 @@ import (
 
 	"github.com/go-logr/logr"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	ctrl "sigs.k8s.io/controller-runtime"
 	ctrlLog "sigs.k8s.io/controller-runtime/pkg/log"
@@ -74,6 +75,39 @@ type logger struct {
 // Log is the logger that will be used in this package
 var log = &logger{Logger: ctrl.Log}
 
+// level is the level of the logger, which can be changed at runtime
+var level = zap.NewAtomicLevelAt(DefaultLevel)
+
+// GetLevel returns the level of the logger, to be used when creating the
+// logging backend, which can be changed at runtime with SetLevel
+func GetLevel() zap.AtomicLevel {
+	return level
+}
+
+// SetLevel changes the level of the logger at runtime
+func SetLevel(l zapcore.Level) {
+	level.SetLevel(l)
+}
+
+// ParseLevel gets the level corresponding to the passed string
+// representation, returning the default level if it is not valid
+func ParseLevel(l string) zapcore.Level {
+	switch l {
+	case ErrorLevelString:
+		return ErrorLevel
+	case WarningLevelString:
+		return WarningLevel
+	case InfoLevelString:
+		return InfoLevel
+	case DebugLevelString:
+		return DebugLevel
+	case TraceLevelString:
+		return TraceLevel
+	default:
+		return DefaultLevel
+	}
+}
+
 // GetLogger returns the default logger
 func GetLogger() Logger {
 	return log
@@ -112,6 +146,10 @@ func (l *logger) enrich(forceCaller bool) logr.Logger {
 		cl = cl.WithValues("logging_pod", podName)
 	}
 
+	if clusterName := os.Getenv("CLUSTER_NAME"); clusterName != "" {
+		cl = cl.WithValues("logging_cluster", clusterName)
+	}
+
 	return cl
 }
 
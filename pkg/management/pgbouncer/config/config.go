@@ -69,7 +69,7 @@ const (
 
 	pgBouncerIniTemplateString = `
 [databases]
-* = host={{.Pooler.Spec.Cluster.Name}}-{{.Pooler.Spec.Type}}
+* = host={{.Pooler.Spec.Cluster.Name}}-{{.Pooler.Spec.Type}}{{ .DatabaseOptions }}
 
 [pgbouncer]
 pool_mode = {{ .Pooler.Spec.PgBouncer.PoolMode }}
@@ -80,7 +80,9 @@ auth_query = {{ .AuthQuery }}
 `
 	pgbouncerHBAFileTemplateString = `
 local pgbouncer pgbouncer peer
-host all all 0.0.0.0/0 md5
+{{ range .HBARules -}}
+host {{ .Databases }} {{ .Users }} 0.0.0.0/0 md5
+{{ end -}}
 `
 
 	pgBouncerUserListTemplateString = `
@@ -110,7 +112,6 @@ var (
 		"unix_socket_dir":      PgBouncerSocketDir,
 		"listen_port":          "5432",
 		"listen_addr":          "*",
-		"admin_users":          PgBouncerAdminUser,
 		"auth_type":            "hba",
 		"auth_hba_file":        ConfigsDir + "/pg_hba.conf",
 		"server_tls_sslmode":   "verify-ca",
@@ -174,12 +175,19 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		parameters["auth_file"] = authFilePath
 	}
 
+	parameters["admin_users"] = buildAdminUsers(pooler.Spec.PgBouncer.AdminUsers)
+	if pooler.Spec.PgBouncer.DefaultTransactionReadOnly {
+		setReadOnlyResetQuery(parameters, pooler.Spec.PgBouncer.Parameters)
+	}
+
 	templateData := struct {
 		Pooler            *apiv1.Pooler
 		AuthQuery         string
 		AuthQueryUser     string
 		AuthQueryPassword string
 		Parameters        string
+		DatabaseOptions   string
+		HBARules          []hbaRule
 	}{
 		Pooler:            pooler,
 		AuthQuery:         pooler.GetAuthQuery(),
@@ -192,7 +200,9 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		//
 		// Also, we want the list of parameters inside the PgBouncer configuration
 		// to be stable.
-		Parameters: stringifyPgBouncerParameters(parameters),
+		Parameters:      stringifyPgBouncerParameters(parameters),
+		DatabaseOptions: buildDatabaseOptions(pooler.Spec.PgBouncer),
+		HBARules:        buildHBARules(pooler.Spec.PgBouncer.AccessRules),
 	}
 
 	err = pgBouncerIniTemplate.Execute(&pgbouncerIni, templateData)
@@ -21,6 +21,8 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 )
 
 // stringifyPgBouncerParameters will take map of PgBouncer parameters and emit
@@ -80,3 +82,88 @@ func cleanupPgBouncerValue(parameter string) (escaped string) {
 	// so we are just removing from the value
 	return newlineRegexp.ReplaceAllString(parameter, "")
 }
+
+const (
+	// readOnlyConnectQuery is the query executed by PgBouncer on the server
+	// connections of the poolers whose transactions are read-only by default
+	readOnlyConnectQuery = "SET default_transaction_read_only = on"
+
+	// readOnlyServerResetQuery replaces the default `DISCARD ALL` reset
+	// query, which would restore `default_transaction_read_only`, with the
+	// statements it is equivalent to, followed by the connect query.
+	// `DISCARD ALL` itself cannot be used, as it cannot be executed in the
+	// implicit transaction of a multi-statement query
+	readOnlyServerResetQuery = "CLOSE ALL; SET SESSION AUTHORIZATION DEFAULT; RESET ALL; " +
+		"DEALLOCATE ALL; UNLISTEN *; SELECT pg_advisory_unlock_all(); " +
+		"DISCARD PLANS; DISCARD TEMP; DISCARD SEQUENCES; " + readOnlyConnectQuery
+
+	serverResetQueryKey = "server_reset_query"
+)
+
+// buildDatabaseOptions builds the options added to the definition
+// of the databases served by PgBouncer
+func buildDatabaseOptions(pgbouncer *apiv1.PgBouncerSpec) string {
+	if pgbouncer == nil || !pgbouncer.DefaultTransactionReadOnly {
+		return ""
+	}
+
+	return fmt.Sprintf(" connect_query='%s'", readOnlyConnectQuery)
+}
+
+// setReadOnlyResetQuery sets the query resetting the server connections
+// to one keeping the transactions read-only by default, unless the user
+// has chosen a reset query
+func setReadOnlyResetQuery(parameters map[string]string, userParameters map[string]string) {
+	if _, ok := userParameters[serverResetQueryKey]; ok {
+		return
+	}
+
+	parameters[serverResetQueryKey] = readOnlyServerResetQuery
+}
+
+// hbaRule is a host-based authentication rule of PgBouncer, having
+// the databases and the users already rendered as comma-separated lists
+type hbaRule struct {
+	Databases string
+	Users     string
+}
+
+// buildHBARules converts the access rules of a pooler into the
+// host-based authentication rules of PgBouncer. When no access rule
+// is specified, every user can connect to every database
+func buildHBARules(accessRules []apiv1.PgBouncerAccessRule) []hbaRule {
+	if len(accessRules) == 0 {
+		return []hbaRule{{Databases: "all", Users: "all"}}
+	}
+
+	joinOrAll := func(names []string) string {
+		if len(names) == 0 {
+			return "all"
+		}
+		return strings.Join(names, ",")
+	}
+
+	rules := make([]hbaRule, 0, len(accessRules))
+	for _, accessRule := range accessRules {
+		rules = append(rules, hbaRule{
+			Databases: joinOrAll(accessRule.Databases),
+			Users:     joinOrAll(accessRule.Users),
+		})
+	}
+
+	return rules
+}
+
+// buildAdminUsers returns the list of the users allowed to connect
+// to the PgBouncer administration console. The user used by the
+// operator is always the first one
+func buildAdminUsers(adminUsers []string) string {
+	users := []string{PgBouncerAdminUser}
+	for _, user := range adminUsers {
+		if user != PgBouncerAdminUser {
+			users = append(users, user)
+		}
+	}
+
+	return strings.Join(users, ",")
+}
@@ -17,6 +17,10 @@ limitations under the License.
 package config
 
 import (
+	"bytes"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -44,4 +48,56 @@ var _ = Describe("PgBouncer configuration", func() {
 		Expect(params).NotTo(MatchRegexp("^pool_mode.*"))
 		Expect(params).NotTo(MatchRegexp("^pid_file.*"))
 	})
+
+	It("allows every user to connect to every database by default", func() {
+		Expect(buildHBARules(nil)).To(Equal([]hbaRule{{Databases: "all", Users: "all"}}))
+
+		var hba bytes.Buffer
+		Expect(pgBouncerHBATemplate.Execute(&hba, struct{ HBARules []hbaRule }{
+			HBARules: buildHBARules(nil),
+		})).To(Succeed())
+		Expect(hba.String()).To(Equal("\nlocal pgbouncer pgbouncer peer\nhost all all 0.0.0.0/0 md5\n"))
+	})
+
+	It("renders the access rules", func() {
+		rules := buildHBARules([]apiv1.PgBouncerAccessRule{
+			{Databases: []string{"app", "reports"}, Users: []string{"app"}},
+			{Users: []string{"analyst"}},
+		})
+		Expect(rules).To(Equal([]hbaRule{
+			{Databases: "app,reports", Users: "app"},
+			{Databases: "all", Users: "analyst"},
+		}))
+
+		var hba bytes.Buffer
+		Expect(pgBouncerHBATemplate.Execute(&hba, struct{ HBARules []hbaRule }{
+			HBARules: rules,
+		})).To(Succeed())
+		Expect(hba.String()).To(Equal("\nlocal pgbouncer pgbouncer peer\n" +
+			"host app,reports app 0.0.0.0/0 md5\n" +
+			"host all analyst 0.0.0.0/0 md5\n"))
+	})
+
+	It("always allows the operator to use the administration console", func() {
+		Expect(buildAdminUsers(nil)).To(Equal(PgBouncerAdminUser))
+		Expect(buildAdminUsers([]string{"admin", PgBouncerAdminUser})).
+			To(Equal(PgBouncerAdminUser + ",admin"))
+	})
+	It("makes the transactions read-only by default when requested", func() {
+		Expect(buildDatabaseOptions(&apiv1.PgBouncerSpec{})).To(BeEmpty())
+		Expect(buildDatabaseOptions(&apiv1.PgBouncerSpec{DefaultTransactionReadOnly: true})).
+			To(Equal(" connect_query='SET default_transaction_read_only = on'"))
+
+		params := buildPgBouncerParameters(nil)
+		setReadOnlyResetQuery(params, nil)
+		Expect(params[serverResetQueryKey]).To(HaveSuffix("; " + readOnlyConnectQuery))
+		Expect(params[serverResetQueryKey]).ToNot(ContainSubstring("DISCARD ALL"))
+	})
+
+	It("keeps the reset query chosen by the user", func() {
+		userParams := map[string]string{serverResetQueryKey: "DEALLOCATE ALL"}
+		params := buildPgBouncerParameters(userParams)
+		setReadOnlyResetQuery(params, userParams)
+		Expect(params[serverResetQueryKey]).To(Equal("DEALLOCATE ALL"))
+	})
 })
@@ -0,0 +1,248 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
+)
+
+// postgresVersionRegex matches the major version in the output
+// of `postgres -V`, i.e. "postgres (PostgreSQL) 15.2"
+var postgresVersionRegex = regexp.MustCompile(`\(PostgreSQL\) (\d+)(\.\d+)?`)
+
+// Adopt configures an existing PGDATA, found in the volumes of the
+// instance, to be used as the primary instance of the cluster
+func (info InitInfo) Adopt(ctx context.Context) error {
+	typedClient, err := management.NewControllerRuntimeClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := info.loadCluster(ctx, typedClient)
+	if err != nil {
+		return err
+	}
+
+	if err := info.checkAdoptablePgData(); err != nil {
+		return err
+	}
+
+	instance := info.GetInstance()
+	if err := instance.CleanUpStalePid(); err != nil {
+		return fmt.Errorf("while removing the stale PID file: %w", err)
+	}
+
+	if err := info.WriteInitialPostgresqlConf(cluster); err != nil {
+		return err
+	}
+
+	if err := info.WriteRestoreHbaConf(); err != nil {
+		return err
+	}
+
+	isPrimary, err := instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+
+	if !isPrimary {
+		if err := info.promoteAdoptedStandby(ctx, instance); err != nil {
+			return err
+		}
+	}
+
+	return info.ConfigureInstanceAfterRestore(nil)
+}
+
+// checkAdoptablePgData checks that the PGDATA we are going to adopt
+// has been created by the same major version of PostgreSQL we are
+// running, and that the WAL location is coherent with the cluster
+// definition
+func (info InitInfo) checkAdoptablePgData() error {
+	pgDataExists, err := fileutils.FileExists(path.Join(info.PgData, "PG_VERSION"))
+	if err != nil {
+		return err
+	}
+	if !pgDataExists {
+		return fmt.Errorf("no PostgreSQL data directory found in %s", info.PgData)
+	}
+
+	dataMajorVersion, err := postgresutils.GetMajorVersion(info.PgData)
+	if err != nil {
+		return fmt.Errorf("cannot detect the major version of the data directory: %w", err)
+	}
+
+	binaryMajorVersion, err := getPostgresBinaryMajorVersion()
+	if err != nil {
+		return fmt.Errorf("cannot detect the major version of PostgreSQL: %w", err)
+	}
+
+	if dataMajorVersion != binaryMajorVersion {
+		return fmt.Errorf(
+			"the data directory has been created by PostgreSQL %d and cannot be used by PostgreSQL %d",
+			dataMajorVersion, binaryMajorVersion)
+	}
+
+	// pg_controldata fails or complains if the control file is not
+	// compatible with the binaries we are running
+	controlData, err := getPgControldataOutput(info.PgData)
+	if err != nil {
+		return fmt.Errorf("the control file of the data directory cannot be read: %w", err)
+	}
+	if strings.Contains(controlData, "WARNING:") {
+		return fmt.Errorf("the control file of the data directory is not compatible: %s", controlData)
+	}
+	log.Info("Adopting an existing data directory",
+		"pgdata", info.PgData,
+		"majorVersion", dataMajorVersion,
+		"clusterState", getPgControldataValue(controlData, "Database cluster state"))
+
+	return info.checkAdoptablePgWal()
+}
+
+// checkAdoptablePgWal checks that pg_wal is a symbolic link to the WAL
+// volume when the cluster has a separate WAL storage, and a plain directory
+// otherwise
+func (info InitInfo) checkAdoptablePgWal() error {
+	pgWalInfo, err := os.Lstat(path.Join(info.PgData, "pg_wal"))
+	if err != nil {
+		return fmt.Errorf("while checking the WAL directory: %w", err)
+	}
+	isSymlink := pgWalInfo.Mode()&os.ModeSymlink != 0
+
+	if info.PgWal == "" {
+		if isSymlink {
+			return fmt.Errorf("pg_wal is a symbolic link, but the cluster has no WAL storage")
+		}
+		return nil
+	}
+
+	if !isSymlink {
+		return fmt.Errorf("pg_wal is not a symbolic link to %s, but the cluster has a WAL storage", info.PgWal)
+	}
+
+	walTarget, err := os.Readlink(path.Join(info.PgData, "pg_wal"))
+	if err != nil {
+		return fmt.Errorf("while reading the pg_wal symbolic link: %w", err)
+	}
+	if path.Clean(walTarget) != path.Clean(info.PgWal) {
+		return fmt.Errorf("pg_wal points to %s, while the cluster expects %s", walTarget, info.PgWal)
+	}
+
+	return nil
+}
+
+// promoteAdoptedStandby promotes the data directory of an adopted standby,
+// so that it can be used as the primary of the cluster
+func (info InitInfo) promoteAdoptedStandby(ctx context.Context, instance *Instance) error {
+	log.Info("The adopted data directory belongs to a standby, promoting it")
+
+	// A standby can start only if its hot standby sensible parameters
+	// are not lower than the ones of its primary
+	enforcedParams, err := GetEnforcedParametersThroughPgControldata(info.PgData)
+	if err != nil {
+		return err
+	}
+	if _, err := configfile.UpdatePostgresConfigurationFile(
+		path.Join(info.PgData, constants.PostgresqlCustomConfigurationFile),
+		enforcedParams,
+	); err != nil {
+		return fmt.Errorf("cannot write enforced parameters: %w", err)
+	}
+
+	return instance.WithActiveInstance(func() error {
+		return instance.PromoteAndWait(ctx)
+	})
+}
+
+// getPostgresBinaryMajorVersion gets the major version of the
+// PostgreSQL binaries we are running
+func getPostgresBinaryMajorVersion() (int, error) {
+	var stdoutBuffer bytes.Buffer
+	postgresCmd := exec.Command(GetPostmasterName(), "-V") // #nosec G204
+	postgresCmd.Stdout = &stdoutBuffer
+	if err := postgresCmd.Run(); err != nil {
+		return 0, err
+	}
+
+	return parsePostgresMajorVersion(stdoutBuffer.String())
+}
+
+// parsePostgresMajorVersion parses the output of `postgres -V`, returning
+// the major version
+func parsePostgresMajorVersion(versionOutput string) (int, error) {
+	matches := postgresVersionRegex.FindStringSubmatch(versionOutput)
+	if len(matches) < 3 {
+		return 0, fmt.Errorf("cannot parse the PostgreSQL version from %q", strings.TrimSpace(versionOutput))
+	}
+
+	major, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, err
+	}
+
+	// Before PostgreSQL 10 the major version was composed by two numbers,
+	// and PG_VERSION contains both of them (i.e. "9.6")
+	if major < 10 {
+		return 0, fmt.Errorf("unsupported PostgreSQL version %s%s", matches[1], matches[2])
+	}
+
+	return major, nil
+}
+
+// getPgControldataOutput runs pg_controldata against the passed data directory
+func getPgControldataOutput(pgData string) (string, error) {
+	var stdoutBuffer bytes.Buffer
+	var stderrBuffer bytes.Buffer
+	pgControlDataCmd := exec.Command(pgControlDataName, "-D", pgData) // #nosec G204
+	pgControlDataCmd.Stdout = &stdoutBuffer
+	pgControlDataCmd.Stderr = &stderrBuffer
+	pgControlDataCmd.Env = append(pgControlDataCmd.Env, "LANG=C", "LC_MESSAGES=C")
+	if err := pgControlDataCmd.Run(); err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderrBuffer.String()))
+	}
+
+	return stdoutBuffer.String(), nil
+}
+
+// getPgControldataValue gets the value of the passed key from
+// the output of pg_controldata
+func getPgControldataValue(controlData, key string) string {
+	for _, line := range strings.Split(controlData, "\n") {
+		lineKey, value, found := strings.Cut(line, ":")
+		if found && strings.TrimSpace(lineKey) == key {
+			return strings.TrimSpace(value)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,89 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("adopting an existing data directory", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "adopt")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	It("parses the major version of the PostgreSQL binaries", func() {
+		major, err := parsePostgresMajorVersion("postgres (PostgreSQL) 15.2\n")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(major).To(Equal(15))
+
+		major, err = parsePostgresMajorVersion("postgres (PostgreSQL) 16beta1 (Debian 16~beta1-2)\n")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(major).To(Equal(16))
+
+		_, err = parsePostgresMajorVersion("postgres (PostgreSQL) 9.6.24\n")
+		Expect(err).To(HaveOccurred())
+
+		_, err = parsePostgresMajorVersion("command not found")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("reads values from the output of pg_controldata", func() {
+		controlData := "pg_control version number:            1300\n" +
+			"Database cluster state:               shut down in recovery\n"
+		Expect(getPgControldataValue(controlData, "Database cluster state")).To(Equal("shut down in recovery"))
+		Expect(getPgControldataValue(controlData, "Missing key")).To(BeEmpty())
+	})
+
+	It("refuses a volume without a data directory", func() {
+		info := InitInfo{PgData: path.Join(tempDir, "pgdata")}
+		Expect(info.checkAdoptablePgData()).To(MatchError(ContainSubstring("no PostgreSQL data directory")))
+	})
+
+	It("accepts a pg_wal directory when the cluster has no WAL storage", func() {
+		pgData := path.Join(tempDir, "pgdata")
+		Expect(os.MkdirAll(path.Join(pgData, "pg_wal"), 0o700)).To(Succeed())
+
+		Expect(InitInfo{PgData: pgData}.checkAdoptablePgWal()).To(Succeed())
+		Expect(InitInfo{PgData: pgData, PgWal: path.Join(tempDir, "wal")}.checkAdoptablePgWal()).
+			ToNot(Succeed())
+	})
+
+	It("requires pg_wal to point to the WAL storage when the cluster has one", func() {
+		pgData := path.Join(tempDir, "pgdata")
+		pgWal := path.Join(tempDir, "wal", "pg_wal")
+		Expect(os.MkdirAll(pgData, 0o700)).To(Succeed())
+		Expect(os.MkdirAll(pgWal, 0o700)).To(Succeed())
+		Expect(os.Symlink(pgWal, path.Join(pgData, "pg_wal"))).To(Succeed())
+
+		Expect(InitInfo{PgData: pgData, PgWal: pgWal}.checkAdoptablePgWal()).To(Succeed())
+		Expect(InitInfo{PgData: pgData, PgWal: path.Join(tempDir, "other")}.checkAdoptablePgWal()).
+			ToNot(Succeed())
+		Expect(InitInfo{PgData: pgData}.checkAdoptablePgWal()).ToNot(Succeed())
+	})
+})
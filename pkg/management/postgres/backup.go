@@ -22,10 +22,10 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"strconv"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -40,7 +40,6 @@ import (
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
 	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/catalog"
-	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -126,6 +125,16 @@ func getDataConfiguration(
 			strconv.Itoa(int(*configuration.Data.Jobs)))
 	}
 
+	if len(configuration.Data.MaxBandwidth) != 0 {
+		if !capabilities.HasMaxBandwidth {
+			return nil, fmt.Errorf("backup bandwidth limit is not supported in Barman %v", capabilities.Version)
+		}
+		options = append(
+			options,
+			"--max-bandwidth",
+			configuration.Data.MaxBandwidth)
+	}
+
 	return options, nil
 }
 
@@ -141,7 +150,7 @@ func (b *BackupCommand) getBarmanCloudBackupOptions(
 	}
 
 	options := []string{
-		"--user", "postgres",
+		"--user", GetSuperUser(),
 	}
 
 	options, err = getDataConfiguration(options, configuration, capabilities)
@@ -181,9 +190,10 @@ func (b *BackupCommand) getBarmanCloudBackupOptions(
 func waitForWalArchiveWorking() error {
 	db, err := sql.Open(
 		"pgx",
-		fmt.Sprintf("host=%s port=%v dbname=postgres user=postgres sslmode=disable",
+		fmt.Sprintf("host=%s port=%v dbname=postgres user=%s sslmode=disable",
 			GetSocketDir(),
-			GetServerPort()),
+			GetServerPort(),
+			GetSuperUser()),
 	)
 	if err != nil {
 		log.Error(err, "can not open postgres database")
@@ -231,6 +241,10 @@ func waitForWalArchiveWorking() error {
 // Start initiates a backup for this instance using
 // barman-cloud-backup
 func (b *BackupCommand) Start(ctx context.Context) error {
+	if b.Backup.Spec.GetMethod() == apiv1.BackupMethodPlugin {
+		return b.startPluginBackup(ctx)
+	}
+
 	if err := b.ensureBarmanCompatibility(); err != nil {
 		return err
 	}
@@ -242,7 +256,7 @@ func (b *BackupCommand) Start(ctx context.Context) error {
 		return fmt.Errorf("can't set backup as running: %v", err)
 	}
 
-	err = waitForWalArchiveWorking()
+	err = b.ensureWalArchiveWorking()
 	if err != nil {
 		log.Info("WAL archiving is not working")
 		b.Backup.GetStatus().Phase = apiv1.BackupPhaseWalArchivingFailing
@@ -273,6 +287,26 @@ func (b *BackupCommand) Start(ctx context.Context) error {
 	return nil
 }
 
+// ensureWalArchiveWorking checks that WAL archiving is working before taking
+// a backup. Standby instances don't archive WAL files, so we rely on the
+// status of WAL archiving reported by the primary in the cluster conditions
+func (b *BackupCommand) ensureWalArchiveWorking() error {
+	isPrimary, err := b.Instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+
+	if isPrimary {
+		return waitForWalArchiveWorking()
+	}
+
+	if meta.IsStatusConditionFalse(b.Cluster.Status.Conditions, string(apiv1.ConditionContinuousArchiving)) {
+		return errors.New("wal-archive not working on the primary")
+	}
+
+	return nil
+}
+
 func (b *BackupCommand) ensureBarmanCompatibility() error {
 	postgresVers, err := b.Instance.GetPgVersion()
 	if err != nil {
@@ -326,10 +360,18 @@ func (b *BackupCommand) run(ctx context.Context) {
 		return
 	}
 
-	cmd := exec.Command(barmanCapabilities.BarmanCloudBackup, options...) // #nosec G204
-	cmd.Env = b.Env
-	cmd.Env = append(cmd.Env, "TMPDIR="+postgres.BackupTemporaryDirectory)
-	err = execlog.RunStreaming(cmd, barmanCapabilities.BarmanCloudBackup)
+	err = b.runPreBackupHooks(ctx)
+	if err == nil {
+		err = b.runBarmanCloudBackup(ctx, options)
+	}
+	if err == nil {
+		b.runAdditionalBackups(ctx)
+	}
+
+	// The post-backup hooks are executed even when the backup failed,
+	// as they usually revert the actions of the pre-backup ones
+	b.runPostBackupHooks(ctx)
+
 	if err != nil {
 		// Set the status to failed and exit
 		b.Log.Error(err, "Backup failed")
@@ -349,6 +391,9 @@ func (b *BackupCommand) run(ctx context.Context) {
 		if err := UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
 			b.Log.Error(err, "Can't mark backup as failed")
 		}
+		if err := b.setClusterBackupTimestamp(ctx, false); err != nil {
+			b.Log.Error(err, "Can't update the last failed backup time")
+		}
 		return
 	}
 
@@ -404,6 +449,9 @@ func (b *BackupCommand) run(ctx context.Context) {
 	if err = UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
 		b.Log.Error(err, "Can't set backup status as completed")
 	}
+	if err = b.setClusterBackupTimestamp(ctx, true); err != nil {
+		b.Log.Error(err, "Can't update the last successful backup time")
+	}
 
 	// Set the first recoverability point
 	if ts := backupList.FirstRecoverabilityPoint(); ts != nil {
@@ -413,6 +461,57 @@ func (b *BackupCommand) run(ctx context.Context) {
 			b.Log.Error(err, "Can't update the first recoverability point")
 		}
 	}
+
+	if b.Backup.Spec.WALCheck {
+		b.checkWALs(ctx, backupList)
+	}
+}
+
+// checkWALs checks that the WAL files where the backup that has just been
+// taken started and ended can be retrieved, and records the result in the
+// Backup and Cluster status
+func (b *BackupCommand) checkWALs(ctx context.Context, backupList *catalog.Catalog) {
+	backupStatus := b.Backup.GetStatus()
+	walCheck := &apiv1.BackupWALCheckStatus{
+		Phase:     apiv1.BackupWALCheckPhaseRunning,
+		StartedAt: &metav1.Time{Time: time.Now()},
+	}
+	backupStatus.WALCheck = walCheck
+	if err := UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		b.Log.Error(err, "Can't set backup WAL check as running")
+	}
+
+	b.Log.Info("Backup WAL check started", "backupID", backupStatus.BackupID)
+	err := barman.CheckBackupWALs(
+		b.Cluster.Spec.Backup.BarmanObjectStore,
+		backupList,
+		backupStatus.BackupID,
+		backupStatus.ServerName,
+		postgres.BackupTemporaryDirectory,
+		b.Env)
+	walCheck.StoppedAt = &metav1.Time{Time: time.Now()}
+	if err != nil {
+		b.Log.Error(err, "Backup WAL check failed")
+		walCheck.Phase = apiv1.BackupWALCheckPhaseFailed
+		walCheck.Error = err.Error()
+		b.Recorder.Event(b.Backup, "Warning", "WALCheckFailed", "Backup WAL check failed")
+	} else {
+		b.Log.Info("Backup WAL check passed")
+		walCheck.Phase = apiv1.BackupWALCheckPhasePassed
+		b.Recorder.Event(b.Backup, "Normal", "WALCheckPassed", "Backup WAL check passed")
+	}
+
+	if err := UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		b.Log.Error(err, "Can't set backup WAL check result")
+	}
+
+	if walCheck.Phase != apiv1.BackupWALCheckPhasePassed {
+		return
+	}
+
+	if err := b.setClusterLastWALCheckedBackup(ctx, walCheck.StoppedAt.Format(time.RFC3339)); err != nil {
+		b.Log.Error(err, "Can't update the last WAL checked backup")
+	}
 }
 
 // UpdateBackupStatusAndRetry updates a certain backup's status in the k8s database,
@@ -458,6 +557,51 @@ func (b *BackupCommand) setClusterFirstRecoverabilityPoint(
 	})
 }
 
+// setClusterBackupTimestamp records the time when this backup was
+// terminated as the last successful or failed backup of the cluster
+func (b *BackupCommand) setClusterBackupTimestamp(ctx context.Context, succeeded bool) error {
+	timestamp := time.Now().Format(time.RFC3339)
+	if stoppedAt := b.Backup.GetStatus().StoppedAt; succeeded && stoppedAt != nil {
+		timestamp = stoppedAt.Format(time.RFC3339)
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		newCluster := &apiv1.Cluster{}
+		namespacedName := types.NamespacedName{Namespace: b.Cluster.GetNamespace(), Name: b.Cluster.GetName()}
+		err := b.Client.Get(ctx, namespacedName, newCluster)
+		if err != nil {
+			return err
+		}
+
+		if succeeded {
+			newCluster.Status.LastSuccessfulBackup = timestamp
+		} else {
+			newCluster.Status.LastFailedBackup = timestamp
+		}
+		return b.Client.Status().Update(ctx, newCluster)
+	})
+}
+
+// setClusterLastWALCheckedBackup records this backup as the latest one
+// which passed the WAL check
+func (b *BackupCommand) setClusterLastWALCheckedBackup(
+	ctx context.Context,
+	walCheckTime string,
+) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		newCluster := &apiv1.Cluster{}
+		namespacedName := types.NamespacedName{Namespace: b.Cluster.GetNamespace(), Name: b.Cluster.GetName()}
+		err := b.Client.Get(ctx, namespacedName, newCluster)
+		if err != nil {
+			return err
+		}
+
+		newCluster.Status.LastWALCheckedBackup = b.Backup.GetName()
+		newCluster.Status.LastSuccessfulBackupWALCheck = walCheckTime
+		return b.Client.Status().Update(ctx, newCluster)
+	})
+}
+
 // setupBackupStatus configures the backup's status from the provided configuration and instance
 func (b *BackupCommand) setupBackupStatus() {
 	barmanConfiguration := b.Cluster.Spec.Backup.BarmanObjectStore
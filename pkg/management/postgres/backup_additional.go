@@ -0,0 +1,158 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// runAdditionalBackups uploads the backup to the additional object stores
+// of the cluster receiving the base backups, recording the result of each
+// upload in the backup and in the cluster status. The failures are only
+// reported, as the backup is already available in the main object store
+func (b *BackupCommand) runAdditionalBackups(ctx context.Context) {
+	backupStatus := b.Backup.GetStatus()
+	backupStatus.AdditionalBackups = nil
+
+	for i := range b.Cluster.Spec.Backup.AdditionalObjectStores {
+		store := &b.Cluster.Spec.Backup.AdditionalObjectStores[i]
+		if !store.AreBaseBackupsEnabled() {
+			continue
+		}
+
+		result := apiv1.AdditionalBackupStatus{
+			Name:            store.Name,
+			DestinationPath: store.BarmanObjectStore.DestinationPath,
+			ServerName:      store.GetServerName(b.Cluster.Name),
+		}
+		backupID, err := b.runAdditionalBackup(ctx, store, result.ServerName)
+		if err != nil {
+			b.Log.Error(err, "Backup to additional object store failed", "objectStore", store.Name)
+			b.Recorder.Eventf(b.Backup, "Warning", "AdditionalBackupFailed",
+				"Backup to the additional object store %s failed: %v", store.Name, err)
+			result.Error = err.Error()
+		} else {
+			b.Log.Info("Backup to additional object store completed", "objectStore", store.Name)
+			result.BackupID = backupID
+		}
+		backupStatus.AdditionalBackups = append(backupStatus.AdditionalBackups, result)
+	}
+
+	if err := b.setAdditionalObjectStoresBackupTimestamps(ctx); err != nil {
+		b.Log.Error(err, "Can't update the backup times of the additional object stores")
+	}
+}
+
+// runAdditionalBackup uploads the backup to an additional object
+// store, applying its retention policy, and returns its ID
+func (b *BackupCommand) runAdditionalBackup(
+	ctx context.Context,
+	store *apiv1.AdditionalObjectStore,
+	serverName string,
+) (string, error) {
+	env, err := barmanCredentials.EnvSetBackupCloudCredentials(
+		ctx,
+		b.Client,
+		b.Cluster.Namespace,
+		&store.BarmanObjectStore,
+		os.Environ())
+	if err != nil {
+		return "", fmt.Errorf("cannot recover credentials: %w", err)
+	}
+
+	options, err := b.getBarmanCloudBackupOptions(&store.BarmanObjectStore, serverName)
+	if err != nil {
+		return "", fmt.Errorf("while getting barman-cloud-backup options: %w", err)
+	}
+
+	b.Log.Info("Backup to additional object store started", "objectStore", store.Name, "options", options)
+	cmd := exec.Command(barmanCapabilities.BarmanCloudBackup, options...) // #nosec G204
+	cmd.Env = env
+	cmd.Env = append(cmd.Env, "TMPDIR="+postgres.BackupTemporaryDirectory)
+	if err := execlog.RunStreaming(cmd, barmanCapabilities.BarmanCloudBackup); err != nil {
+		return "", err
+	}
+
+	backupList, err := barman.GetBackupList(&store.BarmanObjectStore, serverName, env)
+	if err != nil {
+		return "", fmt.Errorf("while listing the backups: %w", err)
+	}
+	if backupList.Len() == 0 {
+		return "", fmt.Errorf("empty backup list")
+	}
+	backupID := backupList.LatestBackupInfo().ID
+
+	if store.RetentionPolicy != "" {
+		b.Log.Info("Applying backup retention policy to additional object store",
+			"objectStore", store.Name,
+			"retentionPolicy", store.RetentionPolicy)
+		retentionConfiguration := &apiv1.BackupConfiguration{
+			BarmanObjectStore: &store.BarmanObjectStore,
+			RetentionPolicy:   store.RetentionPolicy,
+		}
+		if err := barman.DeleteBackupsByPolicy(retentionConfiguration, serverName, env); err != nil {
+			// Proper logging already happened inside DeleteBackupsByPolicy
+			b.Recorder.Event(b.Cluster, "Warning", "RetentionPolicyFailed",
+				fmt.Sprintf("Retention policy failed on additional object store %s", store.Name))
+		}
+	}
+
+	return backupID, nil
+}
+
+// setAdditionalObjectStoresBackupTimestamps records the time when the
+// uploads to the additional object stores terminated in the cluster status
+func (b *BackupCommand) setAdditionalObjectStoresBackupTimestamps(ctx context.Context) error {
+	additionalBackups := b.Backup.GetStatus().AdditionalBackups
+	if len(additionalBackups) == 0 {
+		return nil
+	}
+
+	timestamp := time.Now().Format(time.RFC3339)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		newCluster := &apiv1.Cluster{}
+		namespacedName := types.NamespacedName{Namespace: b.Cluster.GetNamespace(), Name: b.Cluster.GetName()}
+		err := b.Client.Get(ctx, namespacedName, newCluster)
+		if err != nil {
+			return err
+		}
+
+		for _, result := range additionalBackups {
+			storeStatus := newCluster.Status.GetAdditionalObjectStoreStatus(result.Name)
+			if result.Error != "" {
+				storeStatus.LastFailedBackup = timestamp
+			} else {
+				storeStatus.LastSuccessfulBackup = timestamp
+			}
+		}
+		return b.Client.Status().Update(ctx, newCluster)
+	})
+}
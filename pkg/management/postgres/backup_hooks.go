@@ -0,0 +1,101 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+)
+
+// runPreBackupHooks runs the hooks configured to be executed before the
+// backup, returning an error when one of them failed and the failure
+// policy requires the backup to fail
+func (b *BackupCommand) runPreBackupHooks(ctx context.Context) error {
+	hooks := b.Backup.Spec.Hooks
+	if hooks.IsEmpty() {
+		return nil
+	}
+
+	// The webhook already checks this, but the cluster
+	// owner may have disabled the hooks in the meantime
+	if !b.Cluster.Spec.Backup.AreHooksAllowed() {
+		b.Recorder.Event(b.Backup, "Warning", "BackupHooksNotAllowed",
+			"The cluster doesn't allow backup hooks")
+		return fmt.Errorf("the cluster doesn't allow backup hooks")
+	}
+
+	for idx, hook := range hooks.PreBackup {
+		err := b.runBackupHook(ctx, hook)
+		if err == nil {
+			continue
+		}
+
+		b.Log.Error(err, "Pre-backup hook failed", "index", idx)
+		b.Recorder.Eventf(b.Backup, "Warning", "PreBackupHookFailed",
+			"Pre-backup hook %d failed: %v", idx, err)
+		if !hooks.IsFailureIgnored() {
+			return fmt.Errorf("pre-backup hook %d failed: %w", idx, err)
+		}
+	}
+
+	return nil
+}
+
+// runPostBackupHooks runs the hooks configured to be executed after the
+// backup. Failures are only reported, as the backup is already terminated
+func (b *BackupCommand) runPostBackupHooks(ctx context.Context) {
+	if b.Backup.Spec.Hooks.IsEmpty() || !b.Cluster.Spec.Backup.AreHooksAllowed() {
+		return
+	}
+
+	for idx, hook := range b.Backup.Spec.Hooks.PostBackup {
+		if err := b.runBackupHook(ctx, hook); err != nil {
+			b.Log.Error(err, "Post-backup hook failed", "index", idx)
+			b.Recorder.Eventf(b.Backup, "Warning", "PostBackupHookFailed",
+				"Post-backup hook %d failed: %v", idx, err)
+		}
+	}
+}
+
+// runBackupHook runs a single backup hook, either a SQL
+// statement or a command
+func (b *BackupCommand) runBackupHook(ctx context.Context, hook apiv1.BackupHook) error {
+	ctx, cancel := context.WithTimeout(ctx, hook.GetTimeout())
+	defer cancel()
+
+	if len(hook.Command) > 0 {
+		b.Log.Info("Running backup hook command", "command", hook.Command)
+		cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...) // #nosec G204
+		// The credentials of the object store are
+		// not shared with the hook
+		cmd.Env = os.Environ()
+		return execlog.RunStreaming(cmd, hook.Command[0])
+	}
+
+	b.Log.Info("Running backup hook SQL statement", "database", hook.GetDatabase())
+	db, err := b.Instance.ConnectionPool().Connection(hook.GetDatabase())
+	if err != nil {
+		return err
+	}
+	_, err = db.ExecContext(ctx, hook.SQL)
+	return err
+}
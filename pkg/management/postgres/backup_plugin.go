@@ -0,0 +1,125 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/plugin"
+)
+
+// startPluginBackup starts a backup delegating it to the plugin
+// specified in the backup, which runs as a sidecar of the instance
+func (b *BackupCommand) startPluginBackup(ctx context.Context) error {
+	backupStatus := b.Backup.GetStatus()
+	backupStatus.Phase = apiv1.BackupPhaseRunning
+	backupStatus.StartedAt = &metav1.Time{Time: time.Now()}
+	if err := UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		return fmt.Errorf("can't set backup as running: %v", err)
+	}
+
+	go b.runPluginBackup(ctx)
+
+	return nil
+}
+
+// runPluginBackup calls the plugin taking the backup and updates the
+// status. This method is supposed to run inside a dedicated goroutine
+func (b *BackupCommand) runPluginBackup(ctx context.Context) {
+	pluginConfiguration := b.Backup.Spec.PluginConfiguration
+	b.Log.Info("Backup started", "plugin", pluginConfiguration.Name)
+	b.Recorder.Event(b.Backup, "Normal", "Starting", "Backup started")
+
+	err := b.runPreBackupHooks(ctx)
+	var result *plugin.BackupResult
+	if err == nil {
+		result, err = b.callBackupPlugin(ctx, pluginConfiguration)
+	}
+
+	// The post-backup hooks are executed even when the backup failed,
+	// as they usually revert the actions of the pre-backup ones
+	b.runPostBackupHooks(ctx)
+
+	succeeded := err == nil
+	backupStatus := b.Backup.GetStatus()
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionBackup),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonLastBackupSucceeded),
+		Message: "Backup has successful",
+	}
+	if !succeeded {
+		b.Log.Error(err, "Backup failed", "plugin", pluginConfiguration.Name)
+		backupStatus.SetAsFailed(err)
+		b.Recorder.Event(b.Backup, "Normal", "Failed", "Backup failed")
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(apiv1.ConditionReasonLastBackupFailed)
+		condition.Message = err.Error()
+	} else {
+		b.Log.Info("Backup completed", "plugin", pluginConfiguration.Name)
+		backupStatus.SetAsCompleted()
+		backupStatus.BackupID = result.BackupID
+		backupStatus.BeginWal = result.BeginWal
+		backupStatus.EndWal = result.EndWal
+		backupStatus.BeginLSN = result.BeginLSN
+		backupStatus.EndLSN = result.EndLSN
+		backupStatus.DestinationPath = result.DestinationPath
+		b.Recorder.Event(b.Backup, "Normal", "Completed", "Backup completed")
+	}
+	backupStatus.StoppedAt = &metav1.Time{Time: time.Now()}
+
+	if condErr := manager.UpdateCondition(ctx, b.Client, b.Cluster, &condition); condErr != nil {
+		b.Log.Error(condErr, "Error changing backup condition")
+	}
+	if err := UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		b.Log.Error(err, "Can't update the backup status")
+	}
+	if err := b.setClusterBackupTimestamp(ctx, succeeded); err != nil {
+		b.Log.Error(err, "Can't update the last backup time")
+	}
+}
+
+// callBackupPlugin asks a plugin to take the backup
+func (b *BackupCommand) callBackupPlugin(
+	ctx context.Context,
+	pluginConfiguration *apiv1.PluginConfiguration,
+) (*plugin.BackupResult, error) {
+	pluginClient, err := plugin.DialLocal(ctx, pluginConfiguration.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = pluginClient.Close()
+	}()
+
+	result, err := pluginClient.Backup(ctx, &plugin.BackupRequest{
+		Cluster:    b.Cluster,
+		Backup:     b.Backup,
+		Parameters: pluginConfiguration.Parameters,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("while taking the backup with plugin %s: %w", pluginConfiguration.Name, err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,53 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"github.com/blang/semver"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("getDataConfiguration", func() {
+	configuration := &apiv1.BarmanObjectStoreConfiguration{
+		Data: &apiv1.DataBackupConfiguration{
+			MaxBandwidth: "50M",
+		},
+	}
+
+	It("limits the upload bandwidth when Barman supports it", func() {
+		capabilities := &barmanCapabilities.Capabilities{
+			Version:         &semver.Version{Major: 2, Minor: 19},
+			HasMaxBandwidth: true,
+		}
+		options, err := getDataConfiguration([]string{}, configuration, capabilities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(Equal([]string{"--max-bandwidth", "50M"}))
+	})
+
+	It("fails when Barman doesn't support limiting the upload bandwidth", func() {
+		capabilities := &barmanCapabilities.Capabilities{
+			Version: &semver.Version{Major: 2, Minor: 18},
+		}
+		_, err := getDataConfiguration([]string{}, configuration, capabilities)
+		Expect(err).To(HaveOccurred())
+	})
+})
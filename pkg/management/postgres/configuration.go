@@ -17,21 +17,33 @@ limitations under the License.
 package postgres
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
 	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
-	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
+// ErrMissingSharedPreloadLibraries is raised when the PostgreSQL configuration
+// requires shared preload libraries which are not available in the image
+var ErrMissingSharedPreloadLibraries = errors.New("shared preload libraries not available")
+
+// pkgLibDir is the cached location of the PostgreSQL dynamically loadable modules
+var pkgLibDir string
+
 // InstallPgDataFileContent installs a file in PgData, returning true/false if
 // the file has been changed and an error state
 func InstallPgDataFileContent(pgdata, contents, destinationFile string) (bool, error) {
@@ -57,11 +69,30 @@ func InstallPgDataFileContent(pgdata, contents, destinationFile string) (bool, e
 func (instance *Instance) RefreshConfigurationFilesFromCluster(
 	cluster *apiv1.Cluster,
 ) (bool, error) {
-	postgresConfiguration, sha256, err := createPostgresqlConfiguration(cluster)
+	pgConfiguration, err := CreatePostgresqlConfiguration(cluster)
 	if err != nil {
 		return false, err
 	}
 
+	// Installing a configuration requiring a missing library would prevent
+	// PostgreSQL from starting after the next restart
+	if err := checkSharedPreloadLibraries(pgConfiguration); err != nil {
+		return false, err
+	}
+
+	postgresConfiguration, sha256 := postgres.CreatePostgresqlConfFile(pgConfiguration)
+
+	// The user-provided configuration files are read after the
+	// configuration generated by the operator
+	if len(cluster.Spec.PostgresConfiguration.ConfigurationFiles) > 0 {
+		if err := fileutils.EnsureDirectoryExist(
+			path.Join(instance.PgData, constants.PostgresqlCustomConfigurationDirectory)); err != nil {
+			return false, err
+		}
+		postgresConfiguration += fmt.Sprintf("\ninclude_dir '%s'\n",
+			constants.PostgresqlCustomConfigurationDirectory)
+	}
+
 	postgresConfigurationChanged, err := InstallPgDataFileContent(
 		instance.PgData,
 		postgresConfiguration,
@@ -189,117 +220,140 @@ func UpdateReplicaConfiguration(pgData string, clusterName string, podName strin
 // UpdateReplicaConfigurationForPrimary updates the postgresql.auto.conf or recovery.conf file for the proper version
 // of PostgreSQL, using the specified connection string to connect to the primary server
 func UpdateReplicaConfigurationForPrimary(pgData string, primaryConnInfo string) (changed bool, err error) {
-	major, err := postgresutils.GetMajorVersion(pgData)
+	recoveryConfiguration, err := NewRecoveryConfiguration(pgData)
 	if err != nil {
 		return false, err
 	}
 
-	if major < 12 {
-		return configureRecoveryConfFile(pgData, primaryConnInfo)
-	}
+	return recoveryConfiguration.ConfigureStandby(primaryConnInfo)
+}
 
-	if err := createStandbySignal(pgData); err != nil {
-		return false, err
+// RemoveArchiveModeFromPostgresAutoConf removes the "archive_mode" option from "postgresql.auto.conf"
+func RemoveArchiveModeFromPostgresAutoConf(pgData string) (changed bool, err error) {
+	targetFile := path.Join(pgData, "postgresql.auto.conf")
+	currentContent, err := fileutils.ReadFile(targetFile)
+	if err != nil {
+		return false, fmt.Errorf("error while reading content of %v: %w", targetFile, err)
 	}
 
-	return configurePostgresAutoConfFile(pgData, primaryConnInfo)
+	updatedContent := configfile.RemoveOptionFromConfigurationContents(string(currentContent), "archive_mode")
+	return fileutils.WriteStringToFile(targetFile, updatedContent)
 }
 
-// configureRecoveryConfFile configures replication in the recovery.conf file
-// for PostgreSQL 11 and earlier
-func configureRecoveryConfFile(pgData string, primaryConnInfo string) (changed bool, err error) {
-	targetFile := path.Join(pgData, "recovery.conf")
+// recoveryMinApplyDelayOption is the option used to inject
+// replication lag in the chaos experiments
+const recoveryMinApplyDelayOption = "recovery_min_apply_delay"
 
-	options := map[string]string{
-		"standby_mode": "on",
-		"restore_command": fmt.Sprintf(
-			"/controller/manager wal-restore --log-destination %s/%s.json %%f %%p",
-			postgres.LogPath, postgres.LogFileName),
-		"recovery_target_timeline": "latest",
-	}
+// getManagedAutoConfOptions gets the options written by the instance
+// manager in the "postgresql.auto.conf" file, which are the ones written
+// to follow another server and the one injecting replication lag
+func getManagedAutoConfOptions() []string {
+	// A connection string is passed to include all the options
+	replicationOptions := buildReplicationOptions("managed")
 
-	if primaryConnInfo != "" {
-		options["primary_conninfo"] = primaryConnInfo
+	options := make([]string, 0, len(replicationOptions)+1)
+	for name := range replicationOptions {
+		options = append(options, name)
 	}
+	options = append(options, recoveryMinApplyDelayOption)
+	sort.Strings(options)
 
-	changed, err = configfile.UpdatePostgresConfigurationFile(targetFile, options)
+	return options
+}
+
+// SetRecoveryMinApplyDelay sets the "recovery_min_apply_delay" option of
+// a standby, removing it when the delay is zero
+func SetRecoveryMinApplyDelay(pgData string, delay time.Duration) (changed bool, err error) {
+	recoveryConfiguration, err := NewRecoveryConfiguration(pgData)
 	if err != nil {
 		return false, err
 	}
-	if changed {
-		log.Info("Updated replication settings in recovery.conf file")
+
+	if delay > 0 {
+		return recoveryConfiguration.SetOption(
+			recoveryMinApplyDelayOption, fmt.Sprintf("%dms", delay.Milliseconds()))
 	}
 
-	return changed, nil
+	return recoveryConfiguration.RemoveOption(recoveryMinApplyDelayOption)
 }
 
-// configurePostgresAutoConfFile configures replication a in the postgresql.auto.conf file
-// for PostgreSQL 12 and newer
-func configurePostgresAutoConfFile(pgData string, primaryConnInfo string) (changed bool, err error) {
+// RemoveAlterSystemSettingsFromPostgresAutoConf removes the options set
+// with ALTER SYSTEM from "postgresql.auto.conf", keeping only the ones
+// managed by the instance manager. The file is replaced atomically, and
+// must not be changed by PostgreSQL in the meantime: while PostgreSQL is
+// running, use ResetAlterSystemSettings instead. The names of the removed
+// options are returned
+func RemoveAlterSystemSettingsFromPostgresAutoConf(pgData string) (removedOptions []string, err error) {
 	targetFile := path.Join(pgData, "postgresql.auto.conf")
+	currentContent, err := fileutils.ReadFile(targetFile)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading content of %v: %w", targetFile, err)
+	}
 
-	options := map[string]string{
-		"restore_command": fmt.Sprintf(
-			"/controller/manager wal-restore --log-destination %s/%s.json %%f %%p",
-			postgres.LogPath, postgres.LogFileName),
-		"recovery_target_timeline": "latest",
+	updatedContent, removedOptions := configfile.RemoveUnknownOptionsFromConfigurationContents(
+		string(currentContent), getManagedAutoConfOptions())
+	if len(removedOptions) == 0 {
+		return nil, nil
 	}
 
-	if primaryConnInfo != "" {
-		options["primary_conninfo"] = primaryConnInfo
+	if _, err = fileutils.WriteFileAtomic(targetFile, []byte(updatedContent), 0o600); err != nil {
+		return nil, err
 	}
+	return removedOptions, nil
+}
 
-	changed, err = configfile.UpdatePostgresConfigurationFile(targetFile, options)
+// ResetAlterSystemSettings resets the options set with ALTER SYSTEM in
+// the running instance, keeping only the ones managed by the instance
+// manager. PostgreSQL rewrites "postgresql.auto.conf" atomically, while
+// holding the lock serializing the ALTER SYSTEM commands, so that no
+// concurrent change is lost. The names of the reset options are returned
+func (instance *Instance) ResetAlterSystemSettings(ctx context.Context) (resetOptions []string, err error) {
+	targetFile := path.Join(instance.PgData, "postgresql.auto.conf")
+	currentContent, err := fileutils.ReadFile(targetFile)
 	if err != nil {
-		return false, err
+		return nil, fmt.Errorf("error while reading content of %v: %w", targetFile, err)
 	}
 
-	if changed {
-		log.Info("Updated replication settings in postgresql.auto.conf file")
+	_, resetOptions = configfile.RemoveUnknownOptionsFromConfigurationContents(
+		string(currentContent), getManagedAutoConfOptions())
+	if len(resetOptions) == 0 {
+		return nil, nil
 	}
 
-	return changed, nil
-}
-
-// createStandbySignal creates a standby.signal file for PostgreSQL 12 and beyond
-func createStandbySignal(pgData string) error {
-	emptyFile, err := os.Create(filepath.Clean(filepath.Join(pgData, "standby.signal")))
-	if emptyFile != nil {
-		_ = emptyFile.Close()
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return nil, err
 	}
 
-	return err
-}
-
-// RemoveArchiveModeFromPostgresAutoConf removes the "archive_mode" option from "postgresql.auto.conf"
-func RemoveArchiveModeFromPostgresAutoConf(pgData string) (changed bool, err error) {
-	targetFile := path.Join(pgData, "postgresql.auto.conf")
-	currentContent, err := fileutils.ReadFile(targetFile)
-	if err != nil {
-		return false, fmt.Errorf("error while reading content of %v: %w", targetFile, err)
+	for _, option := range resetOptions {
+		if _, err := superUserDB.ExecContext(ctx,
+			fmt.Sprintf("ALTER SYSTEM RESET %s", pgx.Identifier{option}.Sanitize())); err != nil {
+			return nil, fmt.Errorf("while resetting %s: %w", option, err)
+		}
 	}
 
-	updatedContent := configfile.RemoveOptionFromConfigurationContents(string(currentContent), "archive_mode")
-	return fileutils.WriteStringToFile(targetFile, updatedContent)
+	return resetOptions, nil
 }
 
-// createPostgresqlConfiguration creates the PostgreSQL configuration to be
-// used for this cluster and return it and its sha256 checksum
-func createPostgresqlConfiguration(cluster *apiv1.Cluster) (string, string, error) {
+// CreatePostgresqlConfiguration creates the PostgreSQL configuration to be
+// used for this cluster
+func CreatePostgresqlConfiguration(cluster *apiv1.Cluster) (*postgres.PgConfiguration, error) {
 	// Extract the PostgreSQL major version
 	fromVersion, err := cluster.GetPostgresqlVersion()
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
 	info := postgres.ConfigurationInfo{
 		Settings:                         postgres.CnpgConfigurationSettings,
 		MajorVersion:                     fromVersion,
-		UserSettings:                     cluster.Spec.PostgresConfiguration.Parameters,
+		UserSettings:                     cluster.GetPostgresParameters(),
 		IncludingMandatory:               true,
 		IncludingSharedPreloadLibraries:  true,
 		AdditionalSharedPreloadLibraries: cluster.Spec.PostgresConfiguration.AdditionalLibraries,
+		EnabledExtensions:                cluster.Spec.PostgresConfiguration.GetEnabledExtensions(),
 		IsReplicaCluster:                 cluster.IsReplica(),
+		Flavor:                           cluster.GetPostgresFlavor(),
 	}
 
 	// Compute the actual number of sync replicas
@@ -313,6 +367,70 @@ func createPostgresqlConfiguration(cluster *apiv1.Cluster) (string, string, erro
 	// Set cluster name
 	info.ClusterName = cluster.Name
 
-	conf, sha256 := postgres.CreatePostgresqlConfFile(postgres.CreatePostgresqlConfiguration(info))
-	return conf, sha256, nil
+	configuration := postgres.CreatePostgresqlConfiguration(info)
+
+	// The encrypted data files can only be read
+	// after unwrapping the data encryption key
+	if tde := cluster.Spec.PostgresConfiguration.TDE; tde.IsEnabled() {
+		configuration.OverwriteConfig(postgres.DataEncryptionKeyUnwrapCommand, tde.GetUnwrapCommand())
+	}
+
+	return configuration, nil
+}
+
+// checkSharedPreloadLibraries checks if the shared preload libraries
+// required by the passed configuration are available
+func checkSharedPreloadLibraries(configuration *postgres.PgConfiguration) error {
+	// We can only check the libraries in the default location
+	if libraryPath := configuration.GetConfig("dynamic_library_path"); libraryPath != "" &&
+		libraryPath != "$libdir" {
+		return nil
+	}
+
+	libraries := configuration.GetConfig(postgres.SharedPreloadLibraries)
+	if libraries == "" {
+		return nil
+	}
+
+	if pkgLibDir == "" {
+		out, err := exec.Command("pg_config", "--pkglibdir").Output() // #nosec
+		if err != nil {
+			log.Warning("Cannot detect the location of the PostgreSQL libraries, skipping their check",
+				"err", err)
+			return nil
+		}
+		pkgLibDir = strings.TrimSpace(string(out))
+	}
+
+	missingLibraries := getMissingLibraries(pkgLibDir, strings.Split(libraries, ","))
+	if len(missingLibraries) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingSharedPreloadLibraries, strings.Join(missingLibraries, ", "))
+	}
+
+	return nil
+}
+
+// getMissingLibraries gets the libraries that are not available in
+// the passed directory. Like PostgreSQL does, the library is looked
+// for with and without the ".so" suffix. Libraries specified with
+// a path other than "$libdir" are not checked
+func getMissingLibraries(libDir string, libraries []string) []string {
+	var missingLibraries []string
+	for _, library := range libraries {
+		name := strings.TrimPrefix(strings.TrimSpace(library), "$libdir/")
+		if name == "" || strings.Contains(name, "/") {
+			continue
+		}
+
+		if exists, _ := fileutils.FileExists(filepath.Join(libDir, name)); exists {
+			continue
+		}
+		if exists, _ := fileutils.FileExists(filepath.Join(libDir, name+".so")); exists {
+			continue
+		}
+
+		missingLibraries = append(missingLibraries, name)
+	}
+
+	return missingLibraries
 }
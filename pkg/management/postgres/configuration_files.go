@@ -0,0 +1,144 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// ConfigurationFile is a user-provided PostgreSQL configuration file
+type ConfigurationFile struct {
+	// Source describes where the file comes from, i.e. the name
+	// of the ConfigMap and of its key
+	Source string
+
+	// Content is the content of the file
+	Content string
+}
+
+// GetFileName gets the name of the file in the configuration directory,
+// given its position in the list of the configuration files. PostgreSQL
+// reads the files in the order of their names
+func (file ConfigurationFile) GetFileName(index int) string {
+	return fmt.Sprintf("%03d-%s.conf", index, strings.TrimSuffix(file.Source, ".conf"))
+}
+
+// isForbiddenInConfigurationFile checks if a line starting with the passed
+// name can't be used in a user-provided configuration file: the parameters
+// which can't be set in the Cluster can't be set in a configuration file
+// either, as well as the ones managed by the sections of the PostgreSQL
+// configuration in use, and a configuration file can't include other files
+func isForbiddenInConfigurationFile(name string, sectionManagedParameters []string) bool {
+	name = strings.ToLower(name)
+	if _, isFixed := postgres.FixedConfigurationParameters[name]; isFixed {
+		return true
+	}
+
+	switch name {
+	case "include", "include_dir", "include_if_exists", postgres.CNPGConfigSha256:
+		return true
+	}
+
+	for _, parameter := range sectionManagedParameters {
+		if name == parameter {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SanitizeConfigurationFile removes the forbidden parameters from the
+// content of a user-provided configuration file, returning the names
+// of the removed ones
+func SanitizeConfigurationFile(content string, sectionManagedParameters []string) (string, []string) {
+	return configfile.RemoveMatchingOptionsFromConfigurationContents(content, func(name string) bool {
+		return isForbiddenInConfigurationFile(name, sectionManagedParameters)
+	})
+}
+
+// RefreshUserConfigurationFiles installs the user-provided configuration files
+// in the directory included after the configuration generated by the
+// operator, removing the forbidden parameters, including the passed ones
+// managed by the sections of the PostgreSQL configuration, and the files
+// which are not used anymore. This function will return "true" if the
+// content of the directory has been really changed.
+func (instance *Instance) RefreshUserConfigurationFiles(
+	files []ConfigurationFile,
+	sectionManagedParameters []string,
+) (bool, error) {
+	directory := path.Join(instance.PgData, constants.PostgresqlCustomConfigurationDirectory)
+	if len(files) == 0 {
+		// There's nothing to clean up if the directory has never been created
+		if exists, err := fileutils.FileExists(directory); err != nil || !exists {
+			return false, err
+		}
+	}
+	if err := fileutils.EnsureDirectoryExist(directory); err != nil {
+		return false, err
+	}
+
+	changed := false
+	fileNames := make(map[string]bool, len(files))
+	for idx, file := range files {
+		content, removedParameters := SanitizeConfigurationFile(file.Content, sectionManagedParameters)
+		if len(removedParameters) > 0 {
+			log.Warning("Ignoring the forbidden parameters of a configuration file",
+				"source", file.Source,
+				"parameters", removedParameters)
+		}
+
+		fileName := file.GetFileName(idx)
+		fileNames[fileName] = true
+		fileChanged, err := fileutils.WriteStringToFile(filepath.Join(directory, fileName), content)
+		if err != nil {
+			return changed, fmt.Errorf("installing configuration file %s: %w", fileName, err)
+		}
+		changed = changed || fileChanged
+	}
+
+	existingFiles, err := fileutils.GetDirectoryContent(directory)
+	if err != nil {
+		return changed, err
+	}
+	for _, fileName := range existingFiles {
+		if fileNames[fileName] {
+			continue
+		}
+		if err := fileutils.RemoveFile(filepath.Join(directory, fileName)); err != nil {
+			return changed, fmt.Errorf("removing configuration file %s: %w", fileName, err)
+		}
+		changed = true
+	}
+
+	if changed {
+		log.Info("Installed the user-provided configuration files",
+			"pgdata", instance.PgData,
+			"files", len(files))
+	}
+
+	return changed, nil
+}
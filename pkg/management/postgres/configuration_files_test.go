@@ -0,0 +1,111 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("user-provided configuration files", func() {
+	var instance Instance
+
+	BeforeEach(func() {
+		tempDir, err := os.MkdirTemp("", "configuration-files")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			Expect(os.RemoveAll(tempDir)).To(Succeed())
+		})
+		instance = Instance{PgData: tempDir}
+	})
+
+	readDirectory := func() []string {
+		files, err := fileutils.GetDirectoryContent(
+			filepath.Join(instance.PgData, constants.PostgresqlCustomConfigurationDirectory))
+		Expect(err).ToNot(HaveOccurred())
+		return files
+	}
+
+	It("removes the forbidden parameters and the includes", func() {
+		content, removed := SanitizeConfigurationFile(
+			"work_mem = '64MB'\n"+
+				"port = 5433\n"+
+				"Data_Directory '/tmp'\n"+
+				"include '/etc/passwd'\n"+
+				"# archive_mode = off\n"+
+				"jit = off\n",
+			nil)
+		Expect(content).To(Equal("work_mem = '64MB'\n# archive_mode = off\njit = off\n"))
+		Expect(removed).To(Equal([]string{"Data_Directory", "include", "port"}))
+	})
+
+	It("removes the parameters managed by the sections of the configuration", func() {
+		content, removed := SanitizeConfigurationFile(
+			"work_mem = '64MB'\n"+
+				"MAX_CONNECTIONS = 500\n"+
+				"timezone = 'Europe/Rome'\n",
+			[]string{"max_connections", "timezone"})
+		Expect(content).To(Equal("work_mem = '64MB'\n"))
+		Expect(removed).To(Equal([]string{"MAX_CONNECTIONS", "timezone"}))
+	})
+
+	It("doesn't create the directory when there are no files", func() {
+		changed, err := instance.RefreshUserConfigurationFiles(nil, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+
+		exists, err := fileutils.FileExists(
+			filepath.Join(instance.PgData, constants.PostgresqlCustomConfigurationDirectory))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exists).To(BeFalse())
+	})
+
+	It("installs the files in order and removes the ones not used anymore", func() {
+		files := []ConfigurationFile{
+			{Source: "first-tuning.conf", Content: "work_mem = '64MB'\n"},
+			{Source: "second-tuning.conf", Content: "jit = off\n"},
+		}
+		changed, err := instance.RefreshUserConfigurationFiles(files, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(readDirectory()).To(ConsistOf("000-first-tuning.conf", "001-second-tuning.conf"))
+
+		By("not reporting changes when the files are the same", func() {
+			changed, err := instance.RefreshUserConfigurationFiles(files, nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeFalse())
+		})
+
+		By("removing the files which are not referenced anymore", func() {
+			changed, err := instance.RefreshUserConfigurationFiles(files[1:], nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(readDirectory()).To(ConsistOf("000-second-tuning.conf"))
+
+			content, err := fileutils.ReadFile(filepath.Join(instance.PgData,
+				constants.PostgresqlCustomConfigurationDirectory, "000-second-tuning.conf"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(Equal("jit = off\n"))
+		})
+	})
+})
@@ -18,6 +18,9 @@ package postgres
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -98,3 +101,57 @@ var _ = Describe("testing the building of the ldap config string", func() {
 			"ldaptls=1 ldapprefix=\"%s\" ldapsuffix=\"%s\"", ldapServer, ldapPort, ldapScheme, ldapPrefix, ldapSuffix)))
 	})
 })
+
+var _ = Describe("shared preload libraries check", func() {
+	var libDir string
+
+	BeforeEach(func() {
+		libDir = GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(libDir, "pg_stat_statements.so"), nil, 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(libDir, "custom"), nil, 0o600)).To(Succeed())
+	})
+
+	It("finds the libraries with and without the .so suffix", func() {
+		Expect(getMissingLibraries(libDir, []string{"pg_stat_statements", "custom", "$libdir/custom"})).
+			To(BeEmpty())
+	})
+
+	It("reports the missing libraries", func() {
+		Expect(getMissingLibraries(libDir, []string{"pg_stat_statements", "pgaudit", " timescaledb"})).
+			To(Equal([]string{"pgaudit", "timescaledb"}))
+	})
+
+	It("ignores empty names and libraries outside of the default location", func() {
+		Expect(getMissingLibraries(libDir, []string{"", "/opt/lib/custom", "$libdir/plugins/other"})).
+			To(BeEmpty())
+	})
+})
+
+var _ = Describe("replication lag in postgresql.auto.conf", func() {
+	It("sets and removes recovery_min_apply_delay", func() {
+		pgData := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(pgData, "PG_VERSION"), []byte("14\n"), 0o600)).To(Succeed())
+		autoConf := filepath.Join(pgData, "postgresql.auto.conf")
+		Expect(os.WriteFile(autoConf, []byte("primary_conninfo = 'host=primary'\n"), 0o600)).To(Succeed())
+
+		changed, err := SetRecoveryMinApplyDelay(pgData, 30*time.Second)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(os.ReadFile(autoConf)).To(BeEquivalentTo(
+			"primary_conninfo = 'host=primary'\nrecovery_min_apply_delay = '30000ms'\n"))
+
+		changed, err = SetRecoveryMinApplyDelay(pgData, 30*time.Second)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+
+		// The option is not reverted as a setting changed with ALTER SYSTEM
+		removedOptions, err := RemoveAlterSystemSettingsFromPostgresAutoConf(pgData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(removedOptions).To(BeEmpty())
+
+		changed, err = SetRecoveryMinApplyDelay(pgData, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+		Expect(os.ReadFile(autoConf)).To(BeEquivalentTo("primary_conninfo = 'host=primary'\n"))
+	})
+})
@@ -18,6 +18,7 @@ package postgres
 
 import (
 	"fmt"
+	"os"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
@@ -35,6 +36,22 @@ func buildPrimaryConnInfo(primaryHostname, applicationName string) string {
 		fmt.Sprintf("sslcert=%v ", postgres.StreamingReplicaCertificateLocation) +
 		fmt.Sprintf("sslrootcert=%v ", postgres.ServerCACertificateLocation) +
 		fmt.Sprintf("application_name=%v ", applicationName) +
-		"sslmode=verify-ca"
+		fmt.Sprintf("sslmode=%v", getReplicationSSLMode())
+
+	// The channel_binding is added only when requested, as it
+	// is not supported before PostgreSQL 13
+	if channelBinding := os.Getenv(postgres.ReplicationChannelBindingEnvironmentVariable); channelBinding != "" {
+		primaryConnInfo += fmt.Sprintf(" channel_binding=%v", channelBinding)
+	}
 	return primaryConnInfo
 }
+
+// getReplicationSSLMode gets the sslmode of the streaming replication
+// connections using the environment variable or, when empty, the default one
+func getReplicationSSLMode() string {
+	sslMode := os.Getenv(postgres.ReplicationSSLModeEnvironmentVariable)
+	if sslMode == "" {
+		return string(apiv1.ReplicationSSLModeVerifyCA)
+	}
+	return sslMode
+}
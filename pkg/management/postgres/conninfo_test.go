@@ -0,0 +1,47 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("primary_conninfo generation", func() {
+	AfterEach(func() {
+		Expect(os.Unsetenv(postgres.ReplicationSSLModeEnvironmentVariable)).To(Succeed())
+		Expect(os.Unsetenv(postgres.ReplicationChannelBindingEnvironmentVariable)).To(Succeed())
+	})
+
+	It("verifies the CA of the primary by default", func() {
+		connInfo := buildPrimaryConnInfo("cluster-example-rw", "cluster-example-2")
+		Expect(connInfo).To(HaveSuffix("application_name=cluster-example-2 sslmode=verify-ca"))
+		Expect(connInfo).ToNot(ContainSubstring("channel_binding"))
+	})
+
+	It("uses the sslmode and channel_binding passed by the operator", func() {
+		Expect(os.Setenv(postgres.ReplicationSSLModeEnvironmentVariable, "verify-full")).To(Succeed())
+		Expect(os.Setenv(postgres.ReplicationChannelBindingEnvironmentVariable, "disable")).To(Succeed())
+
+		connInfo := buildPrimaryConnInfo("cluster-example-rw", "cluster-example-2")
+		Expect(connInfo).To(HaveSuffix("sslmode=verify-full channel_binding=disable"))
+	})
+})
@@ -23,6 +23,11 @@ const (
 	// operator
 	PostgresqlCustomConfigurationFile = "custom.conf"
 
+	// PostgresqlCustomConfigurationDirectory is the name of the directory
+	// containing the user-provided configuration files, which is included
+	// after the configuration generated by the operator
+	PostgresqlCustomConfigurationDirectory = "custom.conf.d"
+
 	// PostgresqlHBARulesFile is the name of the file which contains
 	// the host-based access rules
 	PostgresqlHBARulesFile = "pg_hba.conf"
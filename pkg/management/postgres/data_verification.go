@@ -0,0 +1,288 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgconn"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
+)
+
+const (
+	pgChecksumsName = "pg_checksums"
+
+	// DataVerificationMethodAmcheck is the name of the online verification
+	// using the amcheck extension
+	DataVerificationMethodAmcheck = "amcheck"
+
+	// DataVerificationMethodChecksums is the name of the offline verification
+	// using pg_checksums
+	DataVerificationMethodChecksums = "checksums"
+)
+
+// DataVerificationResult is the outcome of a verification of the data of the instance
+type DataVerificationResult struct {
+	// The verification method, either `amcheck` or `checksums`
+	Method string
+
+	// When the verification completed
+	Time time.Time
+
+	// The corruptions which have been found
+	Findings []string
+}
+
+// IsCorrupted is true when the verification found a corruption
+func (result *DataVerificationResult) IsCorrupted() bool {
+	return len(result.Findings) > 0
+}
+
+// SetDataVerificationResult stores the result of the last data verification
+func (instance *Instance) SetDataVerificationResult(result *DataVerificationResult) {
+	instance.dataVerificationResult.Store(result)
+}
+
+// GetDataVerificationResult gets the result of the last data verification,
+// or nil if the data has never been verified
+func (instance *Instance) GetDataVerificationResult() *DataVerificationResult {
+	result, _ := instance.dataVerificationResult.Load().(*DataVerificationResult)
+	return result
+}
+
+// VerifyDataChecksums verifies the data checksums using pg_checksums.
+// The instance must be shut down
+func (instance *Instance) VerifyDataChecksums(ctx context.Context) (*DataVerificationResult, error) {
+	var stdoutBuffer bytes.Buffer
+	var stderrBuffer bytes.Buffer
+	cmd := exec.CommandContext(ctx, pgChecksumsName, "--check", "-D", instance.PgData) // #nosec G204
+	cmd.Stdout = &stdoutBuffer
+	cmd.Stderr = &stderrBuffer
+	cmd.Env = append(cmd.Env, "LANG=C", "LC_MESSAGES=C")
+	err := cmd.Run()
+
+	log.FromContext(ctx).Debug("pg_checksums output",
+		"stdout", stdoutBuffer.String(),
+		"stderr", stderrBuffer.String())
+
+	result := &DataVerificationResult{
+		Method:   DataVerificationMethodChecksums,
+		Time:     time.Now(),
+		Findings: parsePgChecksumsFindings(stderrBuffer.String()),
+	}
+
+	// pg_checksums exits with an error code when it finds a corruption,
+	// but also when it cannot verify the data directory
+	if err != nil && !result.IsCorrupted() {
+		return nil, fmt.Errorf("%w: %s", err, strings.TrimSpace(stderrBuffer.String()))
+	}
+
+	return result, nil
+}
+
+// parsePgChecksumsFindings extracts the checksum failures from
+// the error output of pg_checksums
+func parsePgChecksumsFindings(output string) []string {
+	var findings []string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "checksum verification failed") {
+			findings = append(findings, strings.TrimSpace(strings.TrimPrefix(line, "pg_checksums: error:")))
+		}
+	}
+
+	return findings
+}
+
+// VerifyDataWithAmcheck verifies the B-tree indexes of every database
+// using the amcheck extension and, from PostgreSQL 14, the tables too.
+// The databases where amcheck is not installed are skipped
+func (instance *Instance) VerifyDataWithAmcheck(ctx context.Context) (*DataVerificationResult, error) {
+	contextLogger := log.FromContext(ctx)
+
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := superUserDB.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	databases, errs := utils.GetAllAccessibleDatabases(tx, "datallowconn AND NOT datistemplate")
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	if errs != nil {
+		return nil, fmt.Errorf("while listing the databases to be verified: %v", errs)
+	}
+
+	version, err := instance.GetPgVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DataVerificationResult{Method: DataVerificationMethodAmcheck}
+	for _, database := range databases {
+		db, err := instance.ConnectionPool().Connection(database)
+		if err != nil {
+			return nil, err
+		}
+
+		var installed bool
+		row := db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'amcheck')")
+		if err := row.Scan(&installed); err != nil {
+			return nil, err
+		}
+		if !installed {
+			contextLogger.Info("Skipping the verification of a database without the amcheck extension",
+				"database", database)
+			continue
+		}
+
+		findings, err := verifyDatabaseWithAmcheck(ctx, db, version.Major >= 14)
+		if err != nil {
+			return nil, fmt.Errorf("while verifying database %s: %w", database, err)
+		}
+		for _, finding := range findings {
+			result.Findings = append(result.Findings, fmt.Sprintf("%s: %s", database, finding))
+		}
+	}
+
+	result.Time = time.Now()
+	return result, nil
+}
+
+// verifyDatabaseWithAmcheck verifies the B-tree indexes and,
+// if requested, the tables of the database
+func verifyDatabaseWithAmcheck(ctx context.Context, db *sql.DB, checkHeap bool) ([]string, error) {
+	indexes, err := getRelationsToVerify(ctx, db,
+		`SELECT c.oid::regclass::text
+		FROM pg_index i
+		JOIN pg_class c ON i.indexrelid = c.oid
+		JOIN pg_am am ON c.relam = am.oid
+		WHERE am.amname = 'btree' AND c.relpersistence <> 't'
+		AND i.indisready AND i.indisvalid`)
+	if err != nil {
+		return nil, err
+	}
+
+	var findings []string
+	for _, index := range indexes {
+		_, err := db.ExecContext(ctx, "SELECT bt_index_check($1::regclass)", index)
+		if isCorruptionError(err) {
+			findings = append(findings, fmt.Sprintf("index %s: %s", index, err.Error()))
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !checkHeap {
+		return findings, nil
+	}
+
+	tables, err := getRelationsToVerify(ctx, db,
+		`SELECT c.oid::regclass::text
+		FROM pg_class c
+		WHERE c.relkind IN ('r', 'm', 't') AND c.relpersistence <> 't'`)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range tables {
+		tableFindings, err := verifyTableWithAmcheck(ctx, db, table)
+		if err != nil {
+			return nil, err
+		}
+		findings = append(findings, tableFindings...)
+	}
+
+	return findings, nil
+}
+
+// verifyTableWithAmcheck verifies a table using verify_heapam
+func verifyTableWithAmcheck(ctx context.Context, db *sql.DB, table string) (findings []string, err error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT blkno, COALESCE(offnum, 0), COALESCE(msg, '') FROM verify_heapam($1::regclass)", table)
+	if isCorruptionError(err) {
+		return []string{fmt.Sprintf("table %s: %s", table, err.Error())}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	for rows.Next() {
+		var block, offset int64
+		var message string
+		if err := rows.Scan(&block, &offset, &message); err != nil {
+			return nil, err
+		}
+		findings = append(findings, fmt.Sprintf("table %s, block %d, offset %d: %s", table, block, offset, message))
+	}
+
+	return findings, rows.Err()
+}
+
+// getRelationsToVerify gets the names of the relations returned by the passed query
+func getRelationsToVerify(ctx context.Context, db *sql.DB, query string) (relations []string, err error) {
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	for rows.Next() {
+		var relation string
+		if err := rows.Scan(&relation); err != nil {
+			return nil, err
+		}
+		relations = append(relations, relation)
+	}
+
+	return relations, rows.Err()
+}
+
+// isCorruptionError checks if the passed error has been raised
+// by PostgreSQL because of corrupted data
+func isCorruptionError(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+
+	// data_corrupted and index_corrupted
+	return pgErr.Code == "XX001" || pgErr.Code == "XX002"
+}
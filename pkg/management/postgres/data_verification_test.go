@@ -0,0 +1,57 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("data verification", func() {
+	It("extracts the checksum failures from the output of pg_checksums", func() {
+		output := `pg_checksums: error: checksum verification failed in file "/var/lib/postgresql/data/pgdata/base/5/16384", ` +
+			`block 0: calculated checksum 8E5F but block contains 5A8F
+pg_checksums: error: checksum verification failed in file "/var/lib/postgresql/data/pgdata/base/5/16390", ` +
+			`block 3: calculated checksum 1A2B but block contains 3C4D
+`
+		findings := parsePgChecksumsFindings(output)
+		Expect(findings).To(HaveLen(2))
+		Expect(findings[0]).To(HavePrefix("checksum verification failed in file"))
+		Expect(findings[1]).To(ContainSubstring("block 3"))
+	})
+
+	It("doesn't report other errors of pg_checksums as findings", func() {
+		Expect(parsePgChecksumsFindings("pg_checksums: error: cluster must be shut down\n")).To(BeEmpty())
+		Expect(parsePgChecksumsFindings("")).To(BeEmpty())
+	})
+
+	It("stores the result of the last verification", func() {
+		instance := NewInstance()
+		Expect(instance.GetDataVerificationResult()).To(BeNil())
+
+		result := &DataVerificationResult{
+			Method:   DataVerificationMethodAmcheck,
+			Time:     time.Now(),
+			Findings: []string{"app: index public.idx: corrupted"},
+		}
+		instance.SetDataVerificationResult(result)
+		Expect(instance.GetDataVerificationResult()).To(Equal(result))
+		Expect(instance.GetDataVerificationResult().IsCorrupted()).To(BeTrue())
+	})
+})
@@ -0,0 +1,97 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils/compatibility"
+)
+
+const (
+	// DataVolumeName is the name used to report the usage of
+	// the volume containing the data directory
+	DataVolumeName = "data"
+
+	// WalVolumeName is the name used to report the usage of the
+	// volume containing the WAL files, when it is a separate one
+	WalVolumeName = "wal"
+)
+
+// VolumeUsage is the disk space usage of a volume of the instance
+type VolumeUsage struct {
+	// The name of the volume, either `data` or `wal`
+	Name string
+
+	// The path of the directory inside the volume
+	Path string
+
+	TotalBytes     uint64
+	AvailableBytes uint64
+}
+
+// GetUsedBytes gets the used space of the volume in bytes
+func (usage VolumeUsage) GetUsedBytes() uint64 {
+	if usage.AvailableBytes > usage.TotalBytes {
+		return 0
+	}
+	return usage.TotalBytes - usage.AvailableBytes
+}
+
+// GetUsedPercentage gets the percentage of used space of the volume
+func (usage VolumeUsage) GetUsedPercentage() float64 {
+	if usage.TotalBytes == 0 {
+		return 0
+	}
+	return float64(usage.GetUsedBytes()) * 100 / float64(usage.TotalBytes)
+}
+
+// GetVolumesUsage gets the disk space usage of the volume containing the
+// data directory and, when the WAL files are stored in a separate volume,
+// of the WAL volume
+func (instance *Instance) GetVolumesUsage() ([]VolumeUsage, error) {
+	paths := map[string]string{DataVolumeName: instance.PgData}
+
+	// When the WAL files are stored in a separate volume, pg_wal
+	// is a symbolic link to a directory inside it
+	walPath := path.Join(instance.PgData, "pg_wal")
+	if info, err := os.Lstat(walPath); err == nil && info.Mode()&os.ModeSymlink != 0 {
+		paths[WalVolumeName] = walPath
+	}
+
+	result := make([]VolumeUsage, 0, len(paths))
+	for _, name := range []string{DataVolumeName, WalVolumeName} {
+		volumePath, ok := paths[name]
+		if !ok {
+			continue
+		}
+
+		total, available, err := compatibility.GetFilesystemUsage(volumePath)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, VolumeUsage{
+			Name:           name,
+			Path:           volumePath,
+			TotalBytes:     total,
+			AvailableBytes: available,
+		})
+	}
+
+	return result, nil
+}
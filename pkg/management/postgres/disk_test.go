@@ -0,0 +1,80 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("volume usage", func() {
+	It("computes the used space", func() {
+		usage := VolumeUsage{TotalBytes: 1000, AvailableBytes: 250}
+		Expect(usage.GetUsedBytes()).To(BeEquivalentTo(750))
+		Expect(usage.GetUsedPercentage()).To(BeNumerically("==", 75))
+	})
+
+	It("handles empty volumes", func() {
+		usage := VolumeUsage{}
+		Expect(usage.GetUsedBytes()).To(BeZero())
+		Expect(usage.GetUsedPercentage()).To(BeZero())
+	})
+
+	When("getting the usage of the volumes of the instance", func() {
+		var pgdata, walDir string
+
+		BeforeEach(func() {
+			var err error
+			pgdata, err = os.MkdirTemp("", "volume-usage-pgdata-")
+			Expect(err).NotTo(HaveOccurred())
+			walDir, err = os.MkdirTemp("", "volume-usage-wal-")
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(pgdata)).To(Succeed())
+			Expect(os.RemoveAll(walDir)).To(Succeed())
+		})
+
+		It("only reports the data volume when pg_wal is a directory", func() {
+			Expect(os.Mkdir(filepath.Join(pgdata, "pg_wal"), 0o700)).To(Succeed())
+			instance := NewInstance()
+			instance.PgData = pgdata
+
+			usage, err := instance.GetVolumesUsage()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usage).To(HaveLen(1))
+			Expect(usage[0].Name).To(Equal(DataVolumeName))
+			Expect(usage[0].TotalBytes).To(BeNumerically(">", 0))
+		})
+
+		It("reports the WAL volume when pg_wal is a symbolic link", func() {
+			Expect(os.Symlink(walDir, filepath.Join(pgdata, "pg_wal"))).To(Succeed())
+			instance := NewInstance()
+			instance.PgData = pgdata
+
+			usage, err := instance.GetVolumesUsage()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usage).To(HaveLen(2))
+			Expect(usage[0].Name).To(Equal(DataVolumeName))
+			Expect(usage[1].Name).To(Equal(WalVolumeName))
+		})
+	})
+})
@@ -0,0 +1,85 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// getHealth classifies the instance as ready or degraded, given its
+// status. The lag of a replica is measured between the end of the WAL
+// of its source, as reported by the WAL sender, and the replay LSN.
+// The designated primary of a replica cluster may be recovering from
+// the WAL archive only, and is never considered degraded
+func getHealth(
+	status *postgres.PostgresqlStatus,
+	isDesignatedPrimary bool,
+	maximumLag int64,
+) (apiv1.InstanceHealth, string) {
+	if status.IsPrimary {
+		if status.LastFailedWAL != "" && !status.IsArchivingWAL {
+			return apiv1.InstanceHealthDegraded,
+				fmt.Sprintf("the archiving of the WAL file %s is failing", status.LastFailedWAL)
+		}
+		return apiv1.InstanceHealthReady, ""
+	}
+
+	if isDesignatedPrimary {
+		return apiv1.InstanceHealthReady, ""
+	}
+
+	if status.ReplayPaused {
+		return apiv1.InstanceHealthDegraded, "the WAL replay is paused"
+	}
+
+	if lag, ok := getReplayLag(status); ok && maximumLag > 0 && lag > maximumLag {
+		return apiv1.InstanceHealthDegraded, fmt.Sprintf("the replay lag is %d bytes", lag)
+	}
+
+	// This is checked last, as every replica stops streaming
+	// when the primary fails, and the operator ignores this
+	// reason while choosing the instance to be promoted
+	if !status.IsWalReceiverActive {
+		return apiv1.InstanceHealthDegraded, postgres.HealthReasonNotStreaming
+	}
+
+	return apiv1.InstanceHealthReady, ""
+}
+
+// getReplayLag gets the distance, in bytes, between the end of the WAL
+// of the source of a replica, as reported by the WAL sender, and its
+// replay LSN
+func getReplayLag(status *postgres.PostgresqlStatus) (int64, bool) {
+	if status.WalReceiverInfo == nil {
+		return 0, false
+	}
+
+	latestEndLSN, err := status.WalReceiverInfo.LatestEndLsn.Parse()
+	if err != nil {
+		return 0, false
+	}
+
+	replayLSN, err := status.ReplayLsn.Parse()
+	if err != nil {
+		return 0, false
+	}
+
+	return latestEndLSN - replayLSN, true
+}
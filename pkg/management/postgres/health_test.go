@@ -0,0 +1,84 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Instance health", func() {
+	primary := postgres.PostgresqlStatus{
+		IsPrimary:  true,
+		CurrentLsn: "0/30000000",
+	}
+	replica := postgres.PostgresqlStatus{
+		IsWalReceiverActive: true,
+		ReplayLsn:           "0/20000000",
+		WalReceiverInfo: &postgres.PgStatWalReceiver{
+			LatestEndLsn: "0/30000000",
+		},
+	}
+
+	It("considers ready a working primary and a streaming replica", func() {
+		health, _ := getHealth(&primary, false, 0)
+		Expect(health).To(Equal(apiv1.InstanceHealthReady))
+		health, _ = getHealth(&replica, false, 0)
+		Expect(health).To(Equal(apiv1.InstanceHealthReady))
+	})
+
+	It("considers degraded a primary failing to archive WAL", func() {
+		status := primary
+		status.LastFailedWAL = "000000010000000000000003"
+		health, reason := getHealth(&status, false, 0)
+		Expect(health).To(Equal(apiv1.InstanceHealthDegraded))
+		Expect(reason).To(ContainSubstring("000000010000000000000003"))
+	})
+
+	It("considers degraded a replica not streaming WAL, unless it is the designated primary", func() {
+		status := replica
+		status.IsWalReceiverActive = false
+		status.WalReceiverInfo = nil
+		health, reason := getHealth(&status, false, 0)
+		Expect(health).To(Equal(apiv1.InstanceHealthDegraded))
+		Expect(reason).To(Equal(postgres.HealthReasonNotStreaming))
+
+		health, _ = getHealth(&status, true, 0)
+		Expect(health).To(Equal(apiv1.InstanceHealthReady))
+	})
+
+	It("reports a paused replay before the missing streaming", func() {
+		status := replica
+		status.IsWalReceiverActive = false
+		status.ReplayPaused = true
+		health, reason := getHealth(&status, false, 0)
+		Expect(health).To(Equal(apiv1.InstanceHealthDegraded))
+		Expect(reason).To(Equal("the WAL replay is paused"))
+	})
+
+	It("considers degraded a replica exceeding the maximum lag behind its source", func() {
+		health, _ := getHealth(&replica, false, 512*1024*1024)
+		Expect(health).To(Equal(apiv1.InstanceHealthReady))
+
+		health, reason := getHealth(&replica, false, 128*1024*1024)
+		Expect(health).To(Equal(apiv1.InstanceHealthDegraded))
+		Expect(reason).To(Equal("the replay lag is 268435456 bytes"))
+	})
+})
@@ -27,7 +27,7 @@ import (
 )
 
 // WritePostgresUserMaps creates a pg_ident.conf file containing only one map called "local" that
-// maps the current user to "postgres" user.
+// maps the current user to the superuser of the instance.
 func WritePostgresUserMaps(pgData string) error {
 	var username string
 
@@ -40,7 +40,7 @@ func WritePostgresUserMaps(pgData string) error {
 	}
 
 	_, err = fileutils.WriteStringToFile(filepath.Join(pgData, constants.PostgresqlIdentFile),
-		fmt.Sprintf("local %s postgres\n", username))
+		fmt.Sprintf("local %s %s\n", username, GetSuperUser()))
 	if err != nil {
 		return err
 	}
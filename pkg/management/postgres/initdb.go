@@ -91,6 +91,24 @@ type InitInfo struct {
 	// PostInitApplicationSQLRefsFolder is the folder which contains a bunch
 	// of SQL files to be executed just after having configured a new instance
 	PostInitApplicationSQLRefsFolder string
+
+	// PostInitSQLRefsFolder is the folder which contains a bunch of SQL files
+	// to be executed in the `postgres` database just after having configured
+	// a new instance
+	PostInitSQLRefsFolder string
+
+	// PostInitTemplateSQLRefsFolder is the folder which contains a bunch of SQL
+	// files to be executed in the `template1` database just after having
+	// configured a new instance
+	PostInitTemplateSQLRefsFolder string
+
+	// RenamedRoles maps the roles to be renamed once a recovery is
+	// completed to their new names
+	RenamedRoles map[string]string
+
+	// CloneConfiguration contains the options of pg_basebackup
+	// used when joining a cluster by cloning the primary
+	CloneConfiguration *apiv1.CloneConfiguration
 }
 
 // VerifyPGData verifies if the passed configuration is OK, otherwise it returns an error
@@ -113,7 +131,7 @@ func (info InitInfo) CreateDataDirectory() error {
 	// Invoke initdb to generate a data directory
 	options := []string{
 		"--username",
-		"postgres",
+		GetSuperUser(),
 		"-D",
 		info.PgData,
 	}
@@ -203,6 +221,10 @@ func (info InitInfo) ConfigureNewInstance(instance *Instance) error {
 		return err
 	}
 
+	if err = info.executeSQLRefs(dbSuperUser, info.PostInitSQLRefsFolder); err != nil {
+		return fmt.Errorf("could not execute post init SQL refs: %w", err)
+	}
+
 	dbTemplate, err := instance.GetTemplateDB()
 	if err != nil {
 		return fmt.Errorf("while getting template database: %w", err)
@@ -213,6 +235,10 @@ func (info InitInfo) ConfigureNewInstance(instance *Instance) error {
 		return fmt.Errorf("could not execute init Template queries: %w", err)
 	}
 
+	if err = info.executeSQLRefs(dbTemplate, info.PostInitTemplateSQLRefsFolder); err != nil {
+		return fmt.Errorf("could not execute post init template SQL refs: %w", err)
+	}
+
 	if info.ApplicationDatabase == "" {
 		return nil
 	}
@@ -243,26 +269,28 @@ func (info InitInfo) ConfigureNewInstance(instance *Instance) error {
 		return fmt.Errorf("could not execute init Application queries: %w", err)
 	}
 
-	if err = info.executePostInitApplicationSQLRefs(appDB); err != nil {
+	if err = info.executeSQLRefs(appDB, info.PostInitApplicationSQLRefsFolder); err != nil {
 		return fmt.Errorf("could not execute post init application SQL refs: %w", err)
 	}
 
 	return nil
 }
 
-func (info InitInfo) executePostInitApplicationSQLRefs(sqlUser *sql.DB) error {
-	if info.PostInitApplicationSQLRefsFolder == "" {
+// executeSQLRefs runs, in alphabetical order, the SQL files contained in the
+// passed folder using the provided database connection
+func (info InitInfo) executeSQLRefs(sqlUser *sql.DB, directory string) error {
+	if directory == "" {
 		return nil
 	}
 
-	if err := fileutils.EnsureDirectoryExist(info.PostInitApplicationSQLRefsFolder); err != nil {
-		return fmt.Errorf("could not find directory: %s, err: %w", info.PostInitApplicationSQLRefsFolder, err)
+	if err := fileutils.EnsureDirectoryExist(directory); err != nil {
+		return fmt.Errorf("could not find directory: %s, err: %w", directory, err)
 	}
 
-	files, err := fileutils.GetDirectoryContent(info.PostInitApplicationSQLRefsFolder)
+	files, err := fileutils.GetDirectoryContent(directory)
 	if err != nil {
 		return fmt.Errorf("could not get directory content from: %s, err: %w",
-			info.PostInitApplicationSQLRefsFolder, err)
+			directory, err)
 	}
 
 	// Sorting ensures that we execute the files in the correct order.
@@ -270,9 +298,9 @@ func (info InitInfo) executePostInitApplicationSQLRefs(sqlUser *sql.DB) error {
 	sort.Strings(files)
 
 	for _, file := range files {
-		sql, ioErr := fileutils.ReadFile(path.Join(info.PostInitApplicationSQLRefsFolder, file))
+		sql, ioErr := fileutils.ReadFile(path.Join(directory, file))
 		if ioErr != nil {
-			return fmt.Errorf("could not read file: %s, err; %w", file, err)
+			return fmt.Errorf("could not read file: %s, err; %w", file, ioErr)
 		}
 
 		if err = info.executeQueries(sqlUser, []string{string(sql)}); err != nil {
@@ -328,9 +356,11 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 	configurationInfo := postgres.ConfigurationInfo{
 		Settings:                         postgres.CnpgConfigurationSettings,
 		MajorVersion:                     postgresVersion,
-		UserSettings:                     cluster.Spec.PostgresConfiguration.Parameters,
+		UserSettings:                     cluster.GetPostgresParameters(),
 		AdditionalSharedPreloadLibraries: cluster.Spec.PostgresConfiguration.AdditionalLibraries,
+		EnabledExtensions:                cluster.Spec.PostgresConfiguration.GetEnabledExtensions(),
 		IsReplicaCluster:                 cluster.IsReplica(),
+		Flavor:                           cluster.GetPostgresFlavor(),
 		IncludingSharedPreloadLibraries:  true,
 		PreserveFixedSettingsFromUser:    true,
 	}
@@ -342,12 +372,14 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 		instance.StartupOptions = append(instance.StartupOptions, libsConfig)
 	}
 
-	if postgresVersion >= 120000 {
-		primaryConnInfo := buildPrimaryConnInfo(info.ClusterName, info.PodName)
-		_, err = configurePostgresAutoConfFile(info.PgData, primaryConnInfo)
-		if err != nil {
-			return fmt.Errorf("while configuring replica: %w", err)
-		}
+	recoveryConfiguration, err := NewRecoveryConfiguration(info.PgData)
+	if err != nil {
+		return err
+	}
+
+	primaryConnInfo := buildPrimaryConnInfo(info.ClusterName, info.PodName)
+	if _, err = recoveryConfiguration.PrepareReplication(primaryConnInfo); err != nil {
+		return fmt.Errorf("while configuring replica: %w", err)
 	}
 
 	return instance.WithActiveInstance(func() error {
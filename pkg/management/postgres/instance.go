@@ -33,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -48,13 +49,13 @@ import (
 )
 
 const (
-	postgresName      = "postgres"
-	pgCtlName         = "pg_ctl"
-	pgRewindName      = "pg_rewind"
-	pgBaseBackupName  = "pg_basebackup"
-	pgIsReady         = "pg_isready"
-	pgCtlTimeout      = "40000000" // greater than one year in seconds, big enough to simulate an infinite timeout
-	pgControlDataName = "pg_controldata"
+	pgCtlName          = "pg_ctl"
+	pgRewindName       = "pg_rewind"
+	pgBaseBackupName   = "pg_basebackup"
+	pgVerifyBackupName = "pg_verifybackup"
+	pgIsReady          = "pg_isready"
+	pgCtlTimeout       = "40000000" // greater than one year in seconds, big enough to simulate an infinite timeout
+	pgControlDataName  = "pg_controldata"
 
 	pqPingOk         = 0 // server is accepting connections
 	pqPingReject     = 1 // server is alive but rejecting connections
@@ -145,9 +146,21 @@ type Instance struct {
 	// PgCtlTimeoutForPromotion specifies the maximum number of seconds to wait when waiting for promotion to complete
 	PgCtlTimeoutForPromotion int32
 
+	// PromotionTimeoutAction is the action to be taken when the
+	// promotion doesn't complete within PgCtlTimeoutForPromotion seconds
+	PromotionTimeoutAction apiv1.PromotionTimeoutAction
+
 	// specifies the maximum number of seconds to wait when shutting down for a switchover
 	MaxSwitchoverDelay int32
 
+	// MaximumReplayLag is the replay lag, in bytes, above which the
+	// instance is reported as degraded. Zero means no limit
+	MaximumReplayLag int64
+
+	// IsDesignatedPrimary is true when the instance is the designated
+	// primary of a replica cluster, which may not be streaming WAL
+	IsDesignatedPrimary bool
+
 	// pgVersion is the PostgreSQL version
 	pgVersion *semver.Version
 
@@ -157,6 +170,10 @@ type Instance struct {
 	// InstanceManagerIsUpgrading tells if there is an instance manager upgrade in process
 	InstanceManagerIsUpgrading atomic.Bool
 
+	// PhysicalBackupIsRunning tells if there is a hot physical backup
+	// being streamed by the instance manager
+	PhysicalBackupIsRunning atomic.Bool
+
 	// PgRewindIsRunning tells if there is a `pg_rewind` process running
 	PgRewindIsRunning bool
 
@@ -168,12 +185,20 @@ type Instance struct {
 	// it's used by the readiness probe to know whether it should be short-circuited
 	canCheckReadiness atomic.Bool
 
+	// replayLagExceeded specifies whether the replay lag of the instance
+	// exceeded the maximum allowed by the readiness probe, and didn't
+	// go back below the recovery threshold yet
+	replayLagExceeded atomic.Bool
+
 	// mightBeUnavailable specifies whether we expect the instance to be down
 	mightBeUnavailable atomic.Bool
 
 	// fenced specifies whether fencing is on for the instance
 	// fenced entails mightBeUnavailable ( entails as in logical consequence)
 	fenced atomic.Bool
+
+	// dataVerificationResult is the result of the last data verification
+	dataVerificationResult atomic.Value
 }
 
 // IsFenced checks whether the instance is marked as fenced
@@ -277,6 +302,18 @@ func GetServerPort() int {
 	return result
 }
 
+// GetSuperUser gets the name of the superuser of the instance,
+// which depends on the flavor of PostgreSQL
+func GetSuperUser() string {
+	return postgres.GetFlavorFromEnvironment().GetInfo().SuperUser
+}
+
+// GetPostmasterName gets the name of the postmaster executable,
+// which depends on the flavor of PostgreSQL
+func GetPostmasterName() string {
+	return postgres.GetFlavorFromEnvironment().GetInfo().PostmasterName
+}
+
 // Startup starts up a PostgreSQL instance and wait for the instance to be
 // started
 func (instance *Instance) Startup() error {
@@ -399,7 +436,8 @@ func (instance *Instance) Reload() error {
 // Run this instance returning an OS process needed
 // to control the instance execution
 func (instance *Instance) Run() (*execlog.StreamingCmd, error) {
-	process, err := instance.CheckForExistingPostmaster(postgresName)
+	postmasterName := GetPostmasterName()
+	process, err := instance.CheckForExistingPostmaster(postmasterName)
 	if err != nil {
 		return nil, err
 	}
@@ -426,11 +464,11 @@ func (instance *Instance) Run() (*execlog.StreamingCmd, error) {
 		"-D", instance.PgData,
 	}
 
-	postgresCmd := exec.Command(postgresName, options...) // #nosec
+	postgresCmd := exec.Command(postmasterName, options...) // #nosec
 	postgresCmd.Env = instance.Env
 	compatibility.AddInstanceRunCommands(postgresCmd)
 
-	streamingCmd, err := execlog.RunStreamingNoWait(postgresCmd, postgresName)
+	streamingCmd, err := execlog.RunStreamingNoWait(postgresCmd, postmasterName)
 	if err != nil {
 		return nil, err
 	}
@@ -500,17 +538,20 @@ func (instance *Instance) GetPgVersion() (semver.Version, error) {
 	return *parsedVersion, nil
 }
 
+// instanceManagerApplicationName is the application name
+// of the connections opened by the instance manager
+const instanceManagerApplicationName = "cnpg-instance-manager"
+
 // ConnectionPool gets or initializes the connection pool for this instance
 func (instance *Instance) ConnectionPool() *pool.ConnectionPool {
-	const applicationName = "cnpg-instance-manager"
 	if instance.pool == nil {
 		socketDir := GetSocketDir()
 		dsn := fmt.Sprintf(
 			"host=%s port=%v user=%v sslmode=disable application_name=%v",
 			socketDir,
 			GetServerPort(),
-			"postgres",
-			applicationName,
+			GetSuperUser(),
+			instanceManagerApplicationName,
 		)
 
 		instance.pool = pool.NewConnectionPool(dsn)
@@ -571,6 +612,23 @@ func (instance *Instance) WaitForPrimaryAvailable() error {
 	return waitForConnectionAvailable(db)
 }
 
+// PingPrimary checks, with a single attempt, whether we can
+// connect to the primary
+func (instance *Instance) PingPrimary() error {
+	primaryConnInfo := buildPrimaryConnInfo(
+		instance.ClusterName+"-rw", instance.PodName) + " dbname=postgres connect_timeout=5"
+
+	db, err := sql.Open("pgx", primaryConnInfo)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	return db.Ping()
+}
+
 // CompleteCrashRecovery temporary starts up the server and wait for it
 // to be fully available for queries. This will ensure that the crash recovery
 // is fully done.
@@ -725,17 +783,7 @@ func (instance *Instance) Rewind(postgresMajorVersion int) error {
 	instance.LogPgControldata("before pg_rewind")
 
 	primaryConnInfo := buildPrimaryConnInfo(instance.ClusterName+"-rw", instance.PodName)
-	options := []string{
-		"-P",
-		"--source-server", primaryConnInfo + " dbname=postgres",
-		"--target-pgdata", instance.PgData,
-	}
-
-	// As PostgreSQL 13 introduces support of restore from the WAL archive in pg_rewind,
-	// let’s automatically use it, if possible
-	if postgresMajorVersion >= 13 {
-		options = append(options, "--restore-target-wal")
-	}
+	options := buildPgRewindOptions(primaryConnInfo, instance.PgData, postgresMajorVersion)
 
 	// Make sure PostgreSQL control file is not empty
 	err := instance.managePgControlFileBackup()
@@ -764,12 +812,32 @@ func (instance *Instance) Rewind(postgresMajorVersion int) error {
 	return nil
 }
 
+// buildPgRewindOptions builds the options needed to run pg_rewind against
+// the passed data directory, using the primary as the source server.
+// The connection is made with the streaming replication user, which has
+// been granted the privileges needed to run pg_rewind
+func buildPgRewindOptions(primaryConnInfo, pgData string, postgresMajorVersion int) []string {
+	options := []string{
+		"-P",
+		"--source-server", primaryConnInfo + " dbname=postgres",
+		"--target-pgdata", pgData,
+	}
+
+	// As PostgreSQL 13 introduces support of restore from the WAL archive in pg_rewind,
+	// let’s automatically use it, if possible
+	if postgresMajorVersion >= 13 {
+		options = append(options, "--restore-target-wal")
+	}
+
+	return options
+}
+
 // PgIsReady gets the status from the pg_isready command
 func (instance *Instance) PgIsReady() error {
 	// We just use the environment variables we already have
 	// to pass the connection parameters
 	options := []string{
-		"-U", "postgres",
+		"-U", GetSuperUser(),
 		"-d", "postgres",
 		"-q",
 	}
@@ -818,6 +886,53 @@ func (instance *Instance) LogPgControldata(reason string) {
 	}
 }
 
+// parseCheckpointTimeline extracts the timeline of the latest
+// checkpoint from the output of pg_controldata
+func parseCheckpointTimeline(controlData string) (int, error) {
+	value := getPgControldataValue(controlData, "Latest checkpoint's TimeLineID")
+	if value == "" {
+		return 0, fmt.Errorf("cannot find the timeline of the latest checkpoint in pg_controldata output")
+	}
+
+	return strconv.Atoi(value)
+}
+
+// GetReplayedTimeline gets the timeline of the WAL replayed by the
+// instance, reading it from pg_controldata
+func (instance *Instance) GetReplayedTimeline() (int, error) {
+	controlData, err := getPgControldataOutput(instance.PgData)
+	if err != nil {
+		return 0, err
+	}
+
+	return parseReplayedTimeline(controlData)
+}
+
+// parseReplayedTimeline extracts the timeline of the replayed WAL from
+// the output of pg_controldata. A standby may have replayed WAL past its
+// latest restartpoint, up to the minimum recovery ending location, whose
+// timeline is zero when the instance is not in recovery
+func parseReplayedTimeline(controlData string) (int, error) {
+	timeline, err := parseCheckpointTimeline(controlData)
+	if err != nil {
+		return 0, err
+	}
+
+	value := getPgControldataValue(controlData, "Min recovery ending loc's timeline")
+	if value == "" {
+		return timeline, nil
+	}
+	minRecoveryTimeline, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, err
+	}
+	if minRecoveryTimeline > timeline {
+		return minRecoveryTimeline, nil
+	}
+
+	return timeline, nil
+}
+
 // GetInstanceCommandChan is the channel where the lifecycle manager will
 // wait for the operations requested on the instance
 func (instance *Instance) GetInstanceCommandChan() <-chan InstanceCommand {
@@ -885,13 +1000,7 @@ func (instance *Instance) waitForInstanceRestarted(after time.Time) error {
 		return true
 	}
 	return retry.OnError(RetryUntilServerAvailable, retryOnEveryError, func() error {
-		db, err := instance.GetSuperUserDB()
-		if err != nil {
-			return err
-		}
-		var startTime time.Time
-		row := db.QueryRow("SELECT pg_postmaster_start_time()")
-		err = row.Scan(&startTime)
+		startTime, err := instance.GetPostmasterStartTime()
 		if err != nil {
 			return err
 		}
@@ -901,3 +1010,17 @@ func (instance *Instance) waitForInstanceRestarted(after time.Time) error {
 		return nil
 	})
 }
+
+// GetPostmasterStartTime gets the time when the running PostgreSQL server started
+func (instance *Instance) GetPostmasterStartTime() (time.Time, error) {
+	var startTime time.Time
+
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return startTime, err
+	}
+
+	row := db.QueryRow("SELECT pg_postmaster_start_time()")
+	err = row.Scan(&startTime)
+	return startTime, err
+}
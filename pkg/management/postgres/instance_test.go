@@ -17,6 +17,7 @@ limitations under the License.
 package postgres
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -156,6 +157,27 @@ var _ = Describe("testing replica instance methods", Ordered, func() {
 		Expect(isPrimary).To(BeFalse())
 	})
 
+	It("should stop waiting for a promotion exceeding the promotion timeout", func() {
+		timedOutInstance := instance
+		timedOutInstance.PgCtlTimeoutForPromotion = 1
+		err := timedOutInstance.waitForPromotion(context.Background())
+		Expect(err).To(Equal(ErrPromotionTimedOut))
+	})
+
+	It("should stop waiting for a promotion when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := instance.waitForPromotion(ctx)
+		Expect(err).To(Equal(context.Canceled))
+	})
+
+	It("should complete waiting for a promotion when the standby.signal file is removed", func() {
+		err := os.Remove(signalPath)
+		Expect(err).ToNot(HaveOccurred())
+		err = instance.waitForPromotion(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
 	AfterAll(func() {
 		err := fileutils.RemoveDirectoryContent(tempDir)
 		Expect(err).ToNot(HaveOccurred())
@@ -223,3 +245,58 @@ var _ = Describe("check atomic bool", func() {
 		Expect(unAvailable).To(BeTrue())
 	})
 })
+
+var _ = Describe("pg_rewind options", func() {
+	It("uses the primary as the source server", func() {
+		options := buildPgRewindOptions("host=cluster-example-rw user=streaming_replica", "/pgdata", 12)
+		Expect(options).To(Equal([]string{
+			"-P",
+			"--source-server", "host=cluster-example-rw user=streaming_replica dbname=postgres",
+			"--target-pgdata", "/pgdata",
+		}))
+	})
+
+	It("restores the missing WAL files from the archive since PostgreSQL 13", func() {
+		options := buildPgRewindOptions("host=cluster-example-rw", "/pgdata", 13)
+		Expect(options).To(ContainElement("--restore-target-wal"))
+	})
+})
+
+var _ = Describe("checkpoint timeline", func() {
+	It("parses the timeline of the latest checkpoint", func() {
+		controlData := "Latest checkpoint's REDO location:    0/3000028\n" +
+			"Latest checkpoint's TimeLineID:       3\n" +
+			"Latest checkpoint's PrevTimeLineID:   2\n"
+		timeline, err := parseCheckpointTimeline(controlData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(timeline).To(Equal(3))
+	})
+
+	It("fails when the timeline is missing", func() {
+		_, err := parseCheckpointTimeline("Database cluster state: in production\n")
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("replayed timeline", func() {
+	It("uses the checkpoint timeline when the instance is not in recovery", func() {
+		controlData := "Latest checkpoint's TimeLineID:       3\n" +
+			"Min recovery ending loc's timeline:   0\n"
+		timeline, err := parseReplayedTimeline(controlData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(timeline).To(Equal(3))
+	})
+
+	It("uses the timeline of the minimum recovery point when it is newer", func() {
+		controlData := "Latest checkpoint's TimeLineID:       3\n" +
+			"Min recovery ending loc's timeline:   4\n"
+		timeline, err := parseReplayedTimeline(controlData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(timeline).To(Equal(4))
+	})
+
+	It("fails when the checkpoint timeline is missing", func() {
+		_, err := parseReplayedTimeline("Min recovery ending loc's timeline:   4\n")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -17,9 +17,19 @@ limitations under the License.
 package postgres
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path"
 
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -29,8 +39,14 @@ import (
 )
 
 // ClonePgData clones an existing server, given its connection string,
-// to a certain data directory
-func ClonePgData(connectionString, targetPgData, walDir string) error {
+// to a certain data directory. The transfer rate, compression and
+// verification options of pg_basebackup are given by the passed
+// configuration, which may be nil
+func ClonePgData(
+	ctx context.Context,
+	connectionString, targetPgData, walDir string,
+	configuration *apiv1.CloneConfiguration,
+) error {
 	// To initiate streaming replication, the frontend sends the replication parameter
 	// in the startup message. A Boolean value of true (or on, yes, 1) tells the backend
 	// to go into physical replication walsender mode, wherein a small set of replication
@@ -53,6 +69,32 @@ func ClonePgData(connectionString, targetPgData, walDir string) error {
 		return fmt.Errorf("source server not available: %v", connectionString)
 	}
 
+	options := buildPgBaseBackupOptions(connectionString, targetPgData, walDir, configuration)
+	stopTracking := trackDirectoryCopy(ctx, targetPgData, 0, logCloneProgress)
+	pgBaseBackupCmd := exec.Command(pgBaseBackupName, options...) // #nosec
+	err = execlog.RunStreaming(pgBaseBackupCmd, pgBaseBackupName)
+	stopTracking()
+	if err != nil {
+		return fmt.Errorf("error in pg_basebackup, %w", err)
+	}
+
+	if configuration != nil && configuration.VerifyBackup {
+		log.Info("Verifying the clone against its backup manifest", "pgdata", targetPgData)
+		pgVerifyBackupCmd := exec.Command(pgVerifyBackupName, targetPgData) // #nosec
+		if err := execlog.RunStreaming(pgVerifyBackupCmd, pgVerifyBackupName); err != nil {
+			return fmt.Errorf("error in pg_verifybackup, %w", err)
+		}
+	}
+
+	return nil
+}
+
+// buildPgBaseBackupOptions builds the options of pg_basebackup
+// used to clone a server into the target data directory
+func buildPgBaseBackupOptions(
+	connectionString, targetPgData, walDir string,
+	configuration *apiv1.CloneConfiguration,
+) []string {
 	options := []string{
 		"-D", targetPgData,
 		"-v",
@@ -64,20 +106,34 @@ func ClonePgData(connectionString, targetPgData, walDir string) error {
 		options = append(options, "--waldir", walDir)
 	}
 
-	pgBaseBackupCmd := exec.Command(pgBaseBackupName, options...) // #nosec
-	err = execlog.RunStreaming(pgBaseBackupCmd, pgBaseBackupName)
-	if err != nil {
-		return fmt.Errorf("error in pg_basebackup, %w", err)
+	if maxRate := configuration.GetMaxRate(); maxRate != "" {
+		options = append(options, "--max-rate", string(maxRate))
 	}
 
-	return nil
+	if !configuration.ShouldVerifyChecksums() {
+		options = append(options, "--no-verify-checksums")
+	}
+
+	if configuration == nil || configuration.Compression == "" {
+		return options
+	}
+
+	compression := "server-" + string(configuration.Compression)
+	if configuration.CompressionLevel != 0 {
+		compression += fmt.Sprintf(":%d", configuration.CompressionLevel)
+	}
+	return append(options, "--compress", compression)
 }
 
+// errNoBackupAvailable is returned when the cluster has no backup
+// which can be used to create a replica
+var errNoBackupAvailable = errors.New("no backup available")
+
 // Join creates a new instance joined to an existing PostgreSQL cluster
-func (info InitInfo) Join() error {
+func (info InitInfo) Join(ctx context.Context) error {
 	primaryConnInfo := buildPrimaryConnInfo(info.ParentNode, info.PodName) + " dbname=postgres connect_timeout=5"
 
-	err := ClonePgData(primaryConnInfo, info.PgData, info.PgWal)
+	err := ClonePgData(ctx, primaryConnInfo, info.PgData, info.PgWal, info.CloneConfiguration)
 	if err != nil {
 		return err
 	}
@@ -85,3 +141,168 @@ func (info InitInfo) Join() error {
 	_, err = UpdateReplicaConfiguration(info.PgData, info.ClusterName, info.PodName)
 	return err
 }
+
+// JoinFromBackup creates a new instance joined to an existing PostgreSQL
+// cluster restoring the latest backup of the cluster from the object store,
+// so that the new replica catches up with the primary via the WAL archive
+// and streaming replication without being cloned from the primary.
+// When there's no backup to restore, the primary is cloned with pg_basebackup,
+// while any other error, such as invalid credentials, is returned
+func (info InitInfo) JoinFromBackup(ctx context.Context, cli client.Client, cluster *apiv1.Cluster) error {
+	backup, env, err := loadLatestClusterBackup(ctx, cli, cluster)
+	if errors.Is(err, errNoBackupAvailable) {
+		log.Warning("Cannot find a backup to create the replica, cloning the primary",
+			"error", err.Error())
+		return info.Join(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("while looking for a backup to create the replica: %w", err)
+	}
+
+	log.Info("Creating the replica from a backup", "backupID", backup.Status.BackupID)
+	if err := info.restoreDataDir(ctx, cluster, backup, env); err != nil {
+		return err
+	}
+
+	if _, err := info.restoreCustomWalDir(ctx); err != nil {
+		return err
+	}
+
+	_, err = UpdateReplicaConfiguration(info.PgData, cluster.Name, info.PodName)
+	return err
+}
+
+// loadLatestClusterBackup finds the latest backup of the cluster in
+// its object store, together with the environment needed to restore it
+func loadLatestClusterBackup(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+) (*apiv1.Backup, []string, error) {
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+		return nil, nil, fmt.Errorf("%w: backup not configured", errNoBackupAvailable)
+	}
+	configuration := cluster.Spec.Backup.BarmanObjectStore
+
+	serverName := cluster.Name
+	if configuration.ServerName != "" {
+		serverName = configuration.ServerName
+	}
+
+	// The instance manager stores the endpoint CA of the backup object
+	// store while refreshing the secrets
+	env, err := barmanCredentials.EnvSetBackupCloudCredentials(
+		ctx,
+		cli,
+		cluster.Namespace,
+		configuration,
+		os.Environ())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	backupCatalog, err := barman.GetBackupList(configuration, serverName, env)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	latestBackup := backupCatalog.LatestBackupInfo()
+	if latestBackup == nil {
+		return nil, nil, fmt.Errorf("%w: no backup found in the object store", errNoBackupAvailable)
+	}
+
+	return newBackupFromCatalog(configuration, serverName, latestBackup), env, nil
+}
+
+// Reclone wipes the data directory of this instance and clones it again
+// from the current primary, configuring it as a replica. It is used
+// when a former primary cannot be aligned to the new one with pg_rewind.
+// The passed configuration, which may be nil, contains the options of
+// pg_basebackup
+func (instance *Instance) Reclone(ctx context.Context, configuration *apiv1.CloneConfiguration) error {
+	// Signal the liveness probe that we are recovering the data directory
+	// before starting postgres
+	instance.PgRewindIsRunning = true
+	defer func() {
+		instance.PgRewindIsRunning = false
+	}()
+
+	walDir, err := getSeparateWalDirectory(instance.PgData)
+	if err != nil {
+		return err
+	}
+
+	// The data directory is removed only when the primary is able to
+	// stream it again, otherwise we would be left with nothing
+	primaryConnInfo := buildPrimaryConnInfo(instance.ClusterName+"-rw", instance.PodName) +
+		" dbname=postgres connect_timeout=5"
+	if err := identifySystem(primaryConnInfo); err != nil {
+		return fmt.Errorf("the primary is not available, keeping the data directory: %w", err)
+	}
+
+	log.Info("Removing the content of the data directory before cloning it from the primary",
+		"pgdata", instance.PgData,
+		"walDir", walDir)
+	if err := fileutils.RemoveDirectoryContent(instance.PgData); err != nil {
+		return fmt.Errorf("while removing the content of the data directory: %w", err)
+	}
+	if walDir != "" {
+		if err := fileutils.RemoveDirectoryContent(walDir); err != nil {
+			return fmt.Errorf("while removing the content of the WAL directory: %w", err)
+		}
+	}
+
+	if err := ClonePgData(ctx, primaryConnInfo, instance.PgData, walDir, configuration); err != nil {
+		return err
+	}
+
+	_, err = UpdateReplicaConfiguration(instance.PgData, instance.ClusterName, instance.PodName)
+	return err
+}
+
+// identifySystem checks, with a single attempt, whether the server with
+// the passed connection string accepts streaming replication connections
+func identifySystem(connectionString string) error {
+	db, err := utils.NewSimpleDBConnection(connectionString + " replication=1")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+
+	rows, err := db.Query("IDENTIFY_SYSTEM")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	return rows.Err()
+}
+
+// getSeparateWalDirectory returns the target of the pg_wal symbolic link
+// when the WAL files are stored in a separate volume, an empty
+// string otherwise
+func getSeparateWalDirectory(pgData string) (string, error) {
+	pgWal := path.Join(pgData, "pg_wal")
+	pgWalInfo, err := os.Lstat(pgWal)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("while checking the WAL directory: %w", err)
+	}
+
+	if pgWalInfo.Mode()&os.ModeSymlink == 0 {
+		return "", nil
+	}
+
+	walDir, err := os.Readlink(pgWal)
+	if err != nil {
+		return "", fmt.Errorf("while reading the pg_wal symbolic link: %w", err)
+	}
+
+	return walDir, nil
+}
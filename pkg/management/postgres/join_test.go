@@ -0,0 +1,110 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"path"
+
+	"k8s.io/utils/pointer"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("detecting the WAL directory of a data directory", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		var err error
+		tempDir, err = os.MkdirTemp("", "join")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.Mkdir(path.Join(tempDir, "pgdata"), 0o700)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tempDir)).To(Succeed())
+	})
+
+	It("returns an empty string when pg_wal is a directory", func() {
+		Expect(os.Mkdir(path.Join(tempDir, "pgdata", "pg_wal"), 0o700)).To(Succeed())
+		Expect(getSeparateWalDirectory(path.Join(tempDir, "pgdata"))).To(BeEmpty())
+	})
+
+	It("returns an empty string when pg_wal does not exist", func() {
+		Expect(getSeparateWalDirectory(path.Join(tempDir, "pgdata"))).To(BeEmpty())
+	})
+
+	It("returns the target of pg_wal when it is a symbolic link", func() {
+		walDir := path.Join(tempDir, "wal")
+		Expect(os.Mkdir(walDir, 0o700)).To(Succeed())
+		Expect(os.Symlink(walDir, path.Join(tempDir, "pgdata", "pg_wal"))).To(Succeed())
+		Expect(getSeparateWalDirectory(path.Join(tempDir, "pgdata"))).To(Equal(walDir))
+	})
+})
+
+var _ = Describe("pg_basebackup options", func() {
+	It("uses the defaults of pg_basebackup without a configuration", func() {
+		Expect(buildPgBaseBackupOptions("host=source", "/pgdata", "", nil)).To(Equal([]string{
+			"-D", "/pgdata", "-v", "-w", "-d", "host=source",
+		}))
+	})
+
+	It("limits the transfer rate and moves the WAL files", func() {
+		Expect(buildPgBaseBackupOptions("host=source", "/pgdata", "/pgwal", &apiv1.CloneConfiguration{
+			MaxRate: "32M",
+		})).To(Equal([]string{
+			"-D", "/pgdata", "-v", "-w", "-d", "host=source",
+			"--waldir", "/pgwal", "--max-rate", "32M",
+		}))
+	})
+
+	It("compresses the data on the source server", func() {
+		Expect(buildPgBaseBackupOptions("host=source", "/pgdata", "", &apiv1.CloneConfiguration{
+			Compression: apiv1.CloneCompressionZstd,
+		})).To(Equal([]string{
+			"-D", "/pgdata", "-v", "-w", "-d", "host=source", "--compress", "server-zstd",
+		}))
+
+		Expect(buildPgBaseBackupOptions("host=source", "/pgdata", "", &apiv1.CloneConfiguration{
+			Compression:      apiv1.CloneCompressionGzip,
+			CompressionLevel: 5,
+		})).To(Equal([]string{
+			"-D", "/pgdata", "-v", "-w", "-d", "host=source", "--compress", "server-gzip:5",
+		}))
+	})
+
+	It("can skip the verification of the data checksums", func() {
+		Expect(buildPgBaseBackupOptions("host=source", "/pgdata", "", &apiv1.CloneConfiguration{
+			VerifyChecksums: pointer.Bool(true),
+		})).ToNot(ContainElement("--no-verify-checksums"))
+
+		Expect(buildPgBaseBackupOptions("host=source", "/pgdata", "", &apiv1.CloneConfiguration{
+			VerifyChecksums: pointer.Bool(false),
+		})).To(ContainElement("--no-verify-checksums"))
+	})
+})
+
+var _ = Describe("loading the latest backup of a cluster", func() {
+	It("reports that no backup is available when backups are not configured", func() {
+		_, _, err := loadLatestClusterBackup(context.TODO(), nil, &apiv1.Cluster{})
+		Expect(err).To(MatchError(errNoBackupAvailable))
+	})
+})
@@ -143,7 +143,7 @@ func (ds *databaseSnapshotter) importDatabases(
 			}
 
 			alwaysPresentOptions := []string{
-				"-U", "postgres",
+				"-U", ds.cluster.GetSuperuserName(),
 				"-d", targetDatabase,
 				"--section", section,
 				generateFileNameForDatabase(database),
@@ -198,7 +198,7 @@ func (ds *databaseSnapshotter) importDatabaseContent(
 		)
 
 		options := []string{
-			"-U", "postgres",
+			"-U", ds.cluster.GetSuperuserName(),
 			"--no-owner",
 			"--no-privileges",
 			fmt.Sprintf("--role=%s", owner),
@@ -37,7 +37,7 @@ func Microservice(
 	contextLogger.Info("starting microservice clone process")
 
 	if err := createDumpsDirectory(); err != nil {
-		return nil
+		return err
 	}
 
 	if err := ds.exportDatabases(ctx, origin, databases); err != nil {
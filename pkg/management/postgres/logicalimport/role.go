@@ -190,7 +190,7 @@ func (rs *roleManager) getRoles(ctx context.Context) ([]Role, error) {
 
 	rolesToImport := rs.cluster.Spec.Bootstrap.InitDB.Import.Roles
 	rolesToSkip := []string{
-		"postgres",
+		rs.cluster.GetSuperuserName(),
 		apiv1.StreamingReplicationUser,
 		apiv1.PGBouncerPoolerUserName,
 		rs.cluster.Spec.Bootstrap.InitDB.Owner,
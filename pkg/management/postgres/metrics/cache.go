@@ -0,0 +1,74 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// resultCache contains the metrics generated by the queries specifying
+// the cache_seconds option, avoiding to execute them at every scrape
+type resultCache struct {
+	mu      sync.Mutex
+	entries map[string]resultCacheEntry
+}
+
+// resultCacheEntry is the result of a query in a database
+type resultCacheEntry struct {
+	metrics    []prometheus.Metric
+	expiration time.Time
+}
+
+// newResultCache creates a new empty cache
+func newResultCache() *resultCache {
+	return &resultCache{
+		entries: make(map[string]resultCacheEntry),
+	}
+}
+
+// get gets the metrics stored for a key, if they are not expired
+func (cache *resultCache) get(key string, now time.Time) ([]prometheus.Metric, bool) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	entry, ok := cache.entries[key]
+	if !ok || !now.Before(entry.expiration) {
+		return nil, false
+	}
+	return entry.metrics, true
+}
+
+// set stores the metrics for a key until the expiration time,
+// removing the expired entries
+func (cache *resultCache) set(key string, metrics []prometheus.Metric, now, expiration time.Time) {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+
+	for entryKey, entry := range cache.entries {
+		if !now.Before(entry.expiration) {
+			delete(cache.entries, entryKey)
+		}
+	}
+
+	cache.entries[key] = resultCacheEntry{
+		metrics:    metrics,
+		expiration: expiration,
+	}
+}
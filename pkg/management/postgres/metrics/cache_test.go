@@ -0,0 +1,83 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Query results cache", func() {
+	desc := prometheus.NewDesc("cnpg_test", "test metric", nil, nil)
+	metric := prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, 1)
+	now := time.Now()
+
+	It("returns the results until they expire", func() {
+		cache := newResultCache()
+		_, ok := cache.get("key", now)
+		Expect(ok).To(BeFalse())
+
+		cache.set("key", []prometheus.Metric{metric}, now, now.Add(time.Minute))
+		result, ok := cache.get("key", now.Add(30*time.Second))
+		Expect(ok).To(BeTrue())
+		Expect(result).To(ConsistOf(metric))
+
+		_, ok = cache.get("key", now.Add(time.Minute))
+		Expect(ok).To(BeFalse())
+	})
+
+	It("removes the expired entries", func() {
+		cache := newResultCache()
+		cache.set("old", []prometheus.Metric{metric}, now, now.Add(time.Second))
+		cache.set("new", []prometheus.Metric{metric}, now.Add(time.Minute), now.Add(2*time.Minute))
+		Expect(cache.entries).To(HaveLen(1))
+		Expect(cache.entries).To(HaveKey("new"))
+	})
+
+	It("sends the cached results of a query", func() {
+		q := NewQueriesCollector("test", nil, "db")
+		userQuery := UserQuery{Query: "SELECT 1", CacheSeconds: 60}
+		Expect(q.collectFromCache("query", userQuery, "app", nil)).To(BeFalse())
+
+		q.cache.set(getCacheKey("query", userQuery, "app"), []prometheus.Metric{metric}, now, now.Add(time.Minute))
+		ch := make(chan prometheus.Metric, 1)
+		Expect(q.collectFromCache("query", userQuery, "app", ch)).To(BeTrue())
+		Expect(ch).To(Receive(Equal(metric)))
+
+		By("invalidating the results when the query changes", func() {
+			userQuery.Query = "SELECT 2"
+			Expect(q.collectFromCache("query", userQuery, "app", ch)).To(BeFalse())
+		})
+	})
+
+	It("preserves the cache when the queries are reloaded", func() {
+		previous := NewQueriesCollector("test", nil, "db")
+		previous.cache.set("key", []prometheus.Metric{metric}, now, now.Add(time.Minute))
+
+		q := NewQueriesCollector("test", nil, "db")
+		q.InheritCache(previous)
+		_, ok := q.cache.get("key", now)
+		Expect(ok).To(BeTrue())
+
+		q.InheritCache(nil)
+		Expect(q.cache).To(BeIdenticalTo(previous.cache))
+	})
+})
@@ -59,3 +59,32 @@ var _ = Describe("Set default queries", func() {
 		Expect(q.variableLabels["collector"]).To(BeEquivalentTo(VariableSet{"test"}))
 	})
 })
+
+var _ = Describe("Target databases expansion", func() {
+	allDatabases := []string{"postgres", "app", "app_test", "reporting"}
+
+	It("includes the explicitly listed databases", func() {
+		q := NewQueriesCollector("test", nil, "db")
+		Expect(q.expandTargetDatabases([]string{"app", "other"}, nil, allDatabases)).
+			To(Equal(map[string]bool{"app": true, "other": true}))
+	})
+
+	It("expands the patterns", func() {
+		q := NewQueriesCollector("test", nil, "db")
+		Expect(q.expandTargetDatabases([]string{"app*"}, nil, allDatabases)).
+			To(Equal(map[string]bool{"app": true, "app_test": true}))
+	})
+
+	It("skips the databases excluded by the query", func() {
+		q := NewQueriesCollector("test", nil, "db")
+		Expect(q.expandTargetDatabases([]string{"*", "app_test"}, []string{"*_test"}, allDatabases)).
+			To(Equal(map[string]bool{"postgres": true, "app": true, "reporting": true}))
+	})
+
+	It("restricts the discovered databases", func() {
+		q := NewQueriesCollector("test", nil, "db")
+		q.SetDatabaseDiscovery([]string{"app*", "reporting"}, []string{"*_test"})
+		Expect(q.expandTargetDatabases([]string{"*", "postgres"}, nil, allDatabases)).
+			To(Equal(map[string]bool{"postgres": true, "app": true, "reporting": true}))
+	})
+})
@@ -21,9 +21,11 @@ package metrics
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"path"
 	"regexp"
+	"time"
 
 	"github.com/blang/semver"
 	"github.com/prometheus/client_golang/prometheus"
@@ -45,6 +47,16 @@ type QueriesCollector struct {
 	mappings       map[string]MetricMapSet
 	variableLabels map[string]VariableSet
 
+	// discoveryInclude and discoveryExclude contain the shell-like patterns
+	// restricting the databases discovered through the patterns
+	// in the target_databases option of the queries
+	discoveryInclude []string
+	discoveryExclude []string
+
+	// cache contains the results of the queries
+	// specifying the cache_seconds option
+	cache *resultCache
+
 	errorUserQueries      *prometheus.CounterVec
 	errorUserQueriesGauge prometheus.Gauge
 }
@@ -118,15 +130,40 @@ func (q *QueriesCollector) collectUserQueries(ch chan<- prometheus.Metric) error
 			}
 		}
 
-		allTargetDatabases := q.expandTargetDatabases(targetDatabases, allAccessibleDatabasesCache)
+		allTargetDatabases := q.expandTargetDatabases(
+			targetDatabases, userQuery.ExcludeDatabases, allAccessibleDatabasesCache)
 		for targetDatabase := range allTargetDatabases {
+			if userQuery.CacheSeconds > 0 && q.collectFromCache(name, userQuery, targetDatabase, ch) {
+				queryLogger.Debug("Using the cached results", "targetDatabase", targetDatabase)
+				continue
+			}
+
 			conn, err := q.instance.ConnectionPool().Connection(targetDatabase)
 			if err != nil {
 				q.reportUserQueryErrorMetric(name + ": " + err.Error())
 				continue
 			}
 
-			err = collector.collect(conn, ch)
+			if userQuery.PredicateQuery != "" {
+				enabled, err := collector.checkPredicate(conn)
+				if err != nil {
+					queryLogger.Error(err, "Error checking the predicate of the user query",
+						"targetDatabase", targetDatabase)
+					q.reportUserQueryErrorMetric(name + " predicate on db " + targetDatabase + ": " + err.Error())
+					continue
+				}
+				if !enabled {
+					queryLogger.Debug("Skipping because the predicate is not satisfied",
+						"targetDatabase", targetDatabase)
+					continue
+				}
+			}
+
+			if userQuery.CacheSeconds > 0 {
+				err = q.collectAndCache(collector, conn, targetDatabase, ch)
+			} else {
+				err = collector.collect(conn, ch)
+			}
 			if err != nil {
 				queryLogger.Error(err, "Error collecting user query",
 					"targetDatabase", targetDatabase)
@@ -138,6 +175,78 @@ func (q *QueriesCollector) collectUserQueries(ch chan<- prometheus.Metric) error
 	return nil
 }
 
+// getCacheKey gets the key of the results of a query in a database.
+// The key contains the query, so that a change in its definition
+// invalidates the cached results
+func getCacheKey(name string, userQuery UserQuery, database string) string {
+	return fmt.Sprintf("%s\x00%s\x00%s", name, database, userQuery.Query)
+}
+
+// collectFromCache sends the cached results of a query in a database,
+// returning false when they are not available or expired
+func (q QueriesCollector) collectFromCache(
+	name string,
+	userQuery UserQuery,
+	database string,
+	ch chan<- prometheus.Metric,
+) bool {
+	cachedMetrics, ok := q.cache.get(getCacheKey(name, userQuery, database), time.Now())
+	if !ok {
+		return false
+	}
+
+	for _, metric := range cachedMetrics {
+		ch <- metric
+	}
+	return true
+}
+
+// collectAndCache executes a query in a database, sending the
+// results and storing them in the cache when successful
+func (q QueriesCollector) collectAndCache(
+	collector QueryCollector,
+	conn *sql.DB,
+	database string,
+	ch chan<- prometheus.Metric,
+) error {
+	metricsChannel := make(chan prometheus.Metric)
+	done := make(chan struct{})
+	var collectedMetrics []prometheus.Metric
+	go func() {
+		defer close(done)
+		for metric := range metricsChannel {
+			collectedMetrics = append(collectedMetrics, metric)
+		}
+	}()
+
+	err := collector.collect(conn, metricsChannel)
+	close(metricsChannel)
+	<-done
+
+	for _, metric := range collectedMetrics {
+		ch <- metric
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	q.cache.set(
+		getCacheKey(collector.namespace, collector.userQuery, database),
+		collectedMetrics,
+		now,
+		now.Add(time.Duration(collector.userQuery.CacheSeconds)*time.Second))
+	return nil
+}
+
+// InheritCache makes the collector use the cached results of another
+// collector, preserving them when the queries are reloaded
+func (q *QueriesCollector) InheritCache(other *QueriesCollector) {
+	if other != nil && other.cache != nil {
+		q.cache = other.cache
+	}
+}
+
 func (q QueriesCollector) toBeChecked(name string, userQuery UserQuery, isPrimary bool, queryLogger log.Logger) bool {
 	if (userQuery.Primary || userQuery.Master) && !isPrimary { // wokeignore:rule=master
 		queryLogger.Debug("Skipping because runs only on primary")
@@ -186,26 +295,63 @@ func (q QueriesCollector) checkRunOnServerMatches(runOnServer string, name strin
 	return isVersionInRange(pgVersion), nil
 }
 
+// expandTargetDatabases gets the databases where a query is executed. The
+// databases explicitly listed are always included, while the discovered
+// ones are restricted by the database discovery configuration. The
+// excluded databases are never included
 func (q QueriesCollector) expandTargetDatabases(
 	targetDatabases []string,
+	excludeDatabases []string,
 	allAccessibleDatabasesCache []string,
 ) (allTargetDatabases map[string]bool) {
 	allTargetDatabases = make(map[string]bool)
 	for _, targetDatabase := range targetDatabases {
 		if !isPathPattern.MatchString(targetDatabase) {
-			allTargetDatabases[targetDatabase] = true
+			if !matchesAnyPattern(excludeDatabases, targetDatabase) {
+				allTargetDatabases[targetDatabase] = true
+			}
 			continue
 		}
 		for _, database := range allAccessibleDatabasesCache {
 			matched, err := path.Match(targetDatabase, database)
-			if err == nil && matched {
-				allTargetDatabases[database] = true
+			if err != nil || !matched || !q.isDatabaseDiscoverable(database) ||
+				matchesAnyPattern(excludeDatabases, database) {
+				continue
 			}
+			allTargetDatabases[database] = true
 		}
 	}
 	return allTargetDatabases
 }
 
+// SetDatabaseDiscovery sets the shell-like patterns restricting the databases
+// discovered through the patterns in the target_databases option of the
+// queries. An empty list of included patterns allows every database
+func (q *QueriesCollector) SetDatabaseDiscovery(include, exclude []string) {
+	q.discoveryInclude = include
+	q.discoveryExclude = exclude
+}
+
+// isDatabaseDiscoverable checks if a database can be discovered according
+// to the database discovery configuration
+func (q QueriesCollector) isDatabaseDiscoverable(database string) bool {
+	if len(q.discoveryInclude) > 0 && !matchesAnyPattern(q.discoveryInclude, database) {
+		return false
+	}
+	return !matchesAnyPattern(q.discoveryExclude, database)
+}
+
+// matchesAnyPattern checks if a database name matches
+// one of the passed shell-like patterns
+func matchesAnyPattern(patterns []string, database string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, database); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
 func (q QueriesCollector) getAllAccessibleDatabases() ([]string, error) {
 	conn, err := q.instance.ConnectionPool().Connection(q.defaultDBName)
 	if err != nil {
@@ -258,6 +404,7 @@ func NewQueriesCollector(
 		variableLabels: make(map[string]VariableSet),
 		userQueries:    make(UserQueries),
 		defaultDBName:  defaultDBName,
+		cache:          newResultCache(),
 		errorUserQueries: prometheus.NewCounterVec(prometheus.CounterOpts{
 			Namespace: name,
 			Name:      "errors_total",
@@ -317,6 +464,33 @@ type QueryCollector struct {
 	variableLabels VariableSet
 }
 
+// checkPredicate executes the predicate query of the user query, checking
+// if the latter should be executed. A NULL value or an empty result
+// are considered false
+func (c QueryCollector) checkPredicate(conn *sql.DB) (bool, error) {
+	tx, err := createMonitoringTx(conn)
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		if err := tx.Commit(); err != nil {
+			log.Error(err, "Error while committing the predicate query")
+		}
+	}()
+
+	var result sql.NullBool
+	err = tx.QueryRow(c.userQuery.PredicateQuery).Scan(&result)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return result.Valid && result.Bool, nil
+}
+
 // collect retrieves metrics from query and exposes them to prometheus
 func (c QueryCollector) collect(conn *sql.DB, ch chan<- prometheus.Metric) error {
 	tx, err := createMonitoringTx(conn)
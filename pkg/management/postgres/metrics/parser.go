@@ -35,6 +35,14 @@ type UserQuery struct {
 	CacheSeconds    uint64    `yaml:"cache_seconds"`
 	RunOnServer     string    `yaml:"runonserver"`
 	TargetDatabases []string  `yaml:"target_databases"`
+
+	// ExcludeDatabases contains the shell-like patterns of the
+	// databases where the query is never executed
+	ExcludeDatabases []string `yaml:"exclude_databases"`
+
+	// PredicateQuery is a SQL query returning a single boolean value.
+	// The query is executed only when the value is true
+	PredicateQuery string `yaml:"predicate_query"`
 }
 
 // Mapping decide how a certain field, extracted from the query's result, should be used
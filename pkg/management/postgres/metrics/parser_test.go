@@ -59,6 +59,9 @@ some_query:
   target_databases:
   - test
   - app
+  exclude_databases:
+  - "*_test"
+  predicate_query: "SELECT true"
 `))
 		Expect(err).To(BeNil())
 
@@ -69,7 +72,9 @@ some_query:
 			" as rows FROM some_table\n"))
 		Expect(result["some_query"].Primary).To(BeFalse())
 		Expect(result["some_query"].TargetDatabases).To(ContainElements("test", "app"))
+		Expect(result["some_query"].ExcludeDatabases).To(ConsistOf("*_test"))
 		Expect(result["some_query"].CacheSeconds).To(BeEquivalentTo(100))
+		Expect(result["some_query"].PredicateQuery).To(Equal("SELECT true"))
 		Expect(result["some_query"].Master).To(BeFalse()) // wokeignore:rule=master
 		Expect(len(result["some_query"].Metrics)).To(Equal(2))
 		Expect(result["some_query"].Metrics[0]["datname"].Usage).To(Equal(ColumnUsage("LABEL")))
@@ -0,0 +1,490 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// physicalBackupSlotPrefix is the prefix of the temporary replication slot
+// retaining the WAL files needed by a hot physical backup
+const physicalBackupSlotPrefix = "cnpg_physical_backup"
+
+// physicalBackupExcludedDirContents are the directories whose content is
+// not part of a physical backup, as it is recreated when PostgreSQL starts.
+// The content of pg_wal is added at the end of the backup
+var physicalBackupExcludedDirContents = []string{
+	"pg_wal",
+	"pg_stat_tmp",
+	"pg_replslot",
+	"pg_dynshmem",
+	"pg_notify",
+	"pg_serial",
+	"pg_snapshots",
+	"pg_subtrans",
+}
+
+// physicalBackupExcludedFiles are the files which are not part of a
+// physical backup
+var physicalBackupExcludedFiles = []string{
+	"postmaster.pid",
+	"postmaster.opts",
+	"backup_label",
+	"tablespace_map",
+	"backup_manifest",
+	"postgresql.auto.conf.tmp",
+	"current_logfiles.tmp",
+	// A backup taken from a standby must start as a standalone server
+	"standby.signal",
+	"recovery.signal",
+}
+
+// physicalBackupControlFile is the control file of the instance. Like
+// pg_basebackup does, it is added after every other file of the data
+// directory
+const physicalBackupControlFile = "global/pg_control"
+
+// physicalBackupTablespacesDirectory is the directory containing the
+// symbolic links to the tablespaces
+const physicalBackupTablespacesDirectory = "pg_tblspc"
+
+// physicalBackupExcludedPrefixes are the prefixes of the files and
+// directories which are not part of a physical backup
+var physicalBackupExcludedPrefixes = []string{
+	"pg_internal.init",
+	"pgsql_tmp",
+}
+
+var (
+	walFileNameRegex    = regexp.MustCompile(`^[0-9A-F]{24}$`)
+	startWALLabelRegex  = regexp.MustCompile(`START WAL LOCATION: \S+ \(file ([0-9A-F]{24})\)`)
+	errMissingStartWAL  = errors.New("missing start WAL location in backup_label")
+	errBackupInProgress = errors.New("a physical backup is already in progress")
+)
+
+// PhysicalBackupOptions are the options of a hot physical backup
+type PhysicalBackupOptions struct {
+	// Label is the label of the backup
+	Label string
+
+	// Fast requests an immediate checkpoint instead of a spread one
+	Fast bool
+}
+
+// PhysicalBackupResult describes a completed hot physical backup
+type PhysicalBackupResult struct {
+	// BeginLSN is the LSN where the backup started
+	BeginLSN string
+
+	// EndLSN is the LSN where the backup ended
+	EndLSN string
+
+	// BeginWAL is the first WAL file needed to restore the backup
+	BeginWAL string
+}
+
+// IsErrPhysicalBackupInProgress checks if the passed error is caused by
+// another physical backup running on the same instance
+func IsErrPhysicalBackupInProgress(err error) bool {
+	return errors.Is(err, errBackupInProgress)
+}
+
+// StreamPhysicalBackup takes a hot physical backup of the instance with
+// the non-exclusive backup API, writing to the passed writer a tarball
+// of PGDATA containing the WAL files needed to reach a consistent state.
+// Only one physical backup can run at a time. The content of the
+// tablespaces is archived in place, in the pg_tblspc directory, and
+// the tablespace map is not included in the tarball.
+// When taken from a standby, the tarball doesn't contain standby.signal
+// and starts as a standalone server
+func (instance *Instance) StreamPhysicalBackup(
+	ctx context.Context,
+	w io.Writer,
+	options PhysicalBackupOptions,
+) (*PhysicalBackupResult, error) {
+	if !instance.PhysicalBackupIsRunning.CompareAndSwap(false, true) {
+		return nil, errBackupInProgress
+	}
+	defer instance.PhysicalBackupIsRunning.Store(false)
+
+	version, err := instance.GetPgVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	// We use a dedicated connection instead of the pool, as the backup
+	// needs to be started and stopped in the same session, which is held
+	// for the whole duration of the backup. Closing the session aborts
+	// the backup and drops the temporary replication slot
+	db, err := utils.NewSimpleDBConnection(instance.ConnectionPool().GetDsn("postgres"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	db.SetMaxIdleConns(0)
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	// The temporary replication slot retains the WAL files written
+	// during the backup, so that we can add them to the tarball
+	slotName := fmt.Sprintf("%s_%d", physicalBackupSlotPrefix, time.Now().UnixNano())
+	if _, err := conn.ExecContext(ctx,
+		"SELECT pg_create_physical_replication_slot($1, true, true)", slotName); err != nil {
+		return nil, fmt.Errorf("while creating the temporary replication slot: %w", err)
+	}
+
+	startQuery := "SELECT pg_start_backup($1, $2, false)::text"
+	stopQuery := "SELECT lsn::text, labelfile, spcmapfile FROM pg_stop_backup(false, false)"
+	if version.Major >= 15 {
+		startQuery = "SELECT pg_backup_start($1, $2)::text"
+		stopQuery = "SELECT lsn::text, labelfile, spcmapfile FROM pg_backup_stop(false)"
+	}
+
+	var result PhysicalBackupResult
+	if err := conn.QueryRowContext(ctx, startQuery, options.Label, options.Fast).Scan(&result.BeginLSN); err != nil {
+		return nil, fmt.Errorf("while starting the backup: %w", err)
+	}
+	log.Info("Physical backup started",
+		"label", options.Label,
+		"beginLSN", result.BeginLSN)
+
+	archiver := newPhysicalBackupArchiver(instance.PgData, w)
+	if err := archiver.addDataDirectory(); err != nil {
+		return nil, fmt.Errorf("while archiving the data directory: %w", err)
+	}
+
+	// The tablespace map is discarded, as the tablespaces are in the
+	// tarball as directories and not as symbolic links
+	var labelFile, tablespaceMap string
+	if err := conn.QueryRowContext(ctx, stopQuery).Scan(&result.EndLSN, &labelFile, &tablespaceMap); err != nil {
+		return nil, fmt.Errorf("while stopping the backup: %w", err)
+	}
+
+	result.BeginWAL, err = getStartWALFromBackupLabel(labelFile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := archiver.addWALFiles(result.BeginWAL); err != nil {
+		return nil, fmt.Errorf("while archiving the WAL files: %w", err)
+	}
+	if err := archiver.addFile("backup_label", []byte(labelFile)); err != nil {
+		return nil, err
+	}
+	if err := archiver.close(); err != nil {
+		return nil, err
+	}
+
+	log.Info("Physical backup completed",
+		"label", options.Label,
+		"beginLSN", result.BeginLSN,
+		"endLSN", result.EndLSN,
+		"beginWAL", result.BeginWAL)
+
+	return &result, nil
+}
+
+// getStartWALFromBackupLabel extracts the name of the first WAL file
+// needed by a backup from the content of its backup_label file
+func getStartWALFromBackupLabel(labelFile string) (string, error) {
+	matches := startWALLabelRegex.FindStringSubmatch(labelFile)
+	if matches == nil {
+		return "", errMissingStartWAL
+	}
+
+	return matches[1], nil
+}
+
+// physicalBackupArchiver writes the content of a data directory
+// to a tarball
+type physicalBackupArchiver struct {
+	pgData string
+	writer *tar.Writer
+}
+
+func newPhysicalBackupArchiver(pgData string, w io.Writer) *physicalBackupArchiver {
+	return &physicalBackupArchiver{
+		pgData: pgData,
+		writer: tar.NewWriter(w),
+	}
+}
+
+// addDataDirectory adds the content of the data directory to the tarball,
+// skipping the files which are not needed to restore the backup
+func (archiver *physicalBackupArchiver) addDataDirectory() error {
+	err := filepath.WalkDir(archiver.pgData, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			// Files may be removed by PostgreSQL while we walk the data directory
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		name, err := filepath.Rel(archiver.pgData, path)
+		if err != nil {
+			return err
+		}
+		if name == "." {
+			return nil
+		}
+		name = filepath.ToSlash(name)
+
+		if name == physicalBackupControlFile || isExcludedFromPhysicalBackup(entry.Name()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if utils.StringInSlice(physicalBackupExcludedDirContents, name) {
+			// pg_wal may be a symbolic link to a separate volume, but
+			// it is restored as a directory together with its content
+			if err := archiver.addDirectory(name); err != nil {
+				return err
+			}
+			if name == "pg_wal" {
+				if err := archiver.addDirectory("pg_wal/archive_status"); err != nil {
+					return err
+				}
+			}
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if strings.HasPrefix(name, physicalBackupTablespacesDirectory+"/") && entry.Type()&fs.ModeSymlink != 0 {
+			return archiver.addTablespace(path, name)
+		}
+
+		return archiver.addEntry(path, name)
+	})
+	if err != nil {
+		return err
+	}
+
+	return archiver.addEntry(filepath.Join(archiver.pgData, physicalBackupControlFile), physicalBackupControlFile)
+}
+
+// addTablespace adds to the tarball the content of the tablespace linked
+// by the passed symbolic link, as a directory with the name of the link.
+// Like the data directory, the temporary files are skipped
+func (archiver *physicalBackupArchiver) addTablespace(link, name string) error {
+	location, err := filepath.EvalSymlinks(link)
+	if err != nil {
+		return err
+	}
+
+	return filepath.WalkDir(location, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		relativeName, err := filepath.Rel(location, path)
+		if err != nil {
+			return err
+		}
+
+		if relativeName != "." && isExcludedFromPhysicalBackup(entry.Name()) {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		return archiver.addEntry(path, filepath.ToSlash(filepath.Join(name, relativeName)))
+	})
+}
+
+// addWALFiles adds to the tarball the WAL files starting from the passed
+// one, together with the timeline history files
+func (archiver *physicalBackupArchiver) addWALFiles(beginWAL string) error {
+	walDirectory := filepath.Join(archiver.pgData, "pg_wal")
+	entries, err := os.ReadDir(walDirectory)
+	if err != nil {
+		return err
+	}
+
+	// os.ReadDir returns the entries sorted by file name
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+
+		fileName := entry.Name()
+		isNeededWAL := walFileNameRegex.MatchString(fileName) && fileName >= beginWAL
+		if !isNeededWAL && !strings.HasSuffix(fileName, ".history") {
+			continue
+		}
+
+		if err := archiver.addEntry(filepath.Join(walDirectory, fileName), "pg_wal/"+fileName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addEntry adds a file, a directory or a symbolic link to the tarball.
+// Other file types, like the log FIFOs, are skipped
+func (archiver *physicalBackupArchiver) addEntry(path, name string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.IsDir():
+		return archiver.addDirectory(name)
+
+	case info.Mode()&fs.ModeSymlink != 0:
+		target, err := os.Readlink(path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, target)
+		if err != nil {
+			return err
+		}
+		header.Name = name
+		return archiver.writer.WriteHeader(header)
+
+	case info.Mode().IsRegular():
+		return archiver.addRegularFile(path, name, info)
+
+	default:
+		return nil
+	}
+}
+
+// addRegularFile adds a regular file to the tarball. The file may be
+// changed by PostgreSQL while we read it: this is fine, as the changes
+// are replayed from the WAL files, but the size written in the tar header
+// must be respected. Like pg_basebackup does, we pad the file with zeros
+// when it has been truncated
+func (archiver *physicalBackupArchiver) addRegularFile(path, name string, info fs.FileInfo) error {
+	file, err := os.Open(path) // #nosec G304
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	if err := archiver.writer.WriteHeader(header); err != nil {
+		return err
+	}
+
+	copied, err := io.CopyN(archiver.writer, file, header.Size)
+	if errors.Is(err, io.EOF) {
+		_, err = io.CopyN(archiver.writer, zeroReader{}, header.Size-copied)
+	}
+	return err
+}
+
+// addDirectory adds a directory to the tarball
+func (archiver *physicalBackupArchiver) addDirectory(name string) error {
+	return archiver.writer.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeDir,
+		Name:     name + "/",
+		Mode:     0o700,
+		ModTime:  time.Now(),
+	})
+}
+
+// addFile adds a file with the passed content to the tarball
+func (archiver *physicalBackupArchiver) addFile(name string, content []byte) error {
+	if err := archiver.writer.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o600,
+		Size:     int64(len(content)),
+		ModTime:  time.Now(),
+	}); err != nil {
+		return err
+	}
+
+	_, err := archiver.writer.Write(content)
+	return err
+}
+
+// close writes the tarball footer
+func (archiver *physicalBackupArchiver) close() error {
+	return archiver.writer.Close()
+}
+
+// isExcludedFromPhysicalBackup checks if a file or a directory, given
+// its name, is not part of a physical backup
+func isExcludedFromPhysicalBackup(fileName string) bool {
+	if utils.StringInSlice(physicalBackupExcludedFiles, fileName) {
+		return true
+	}
+
+	for _, prefix := range physicalBackupExcludedPrefixes {
+		if strings.HasPrefix(fileName, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// zeroReader is an infinite source of zeros
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
@@ -0,0 +1,180 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Physical backup", func() {
+	readTarball := func(content *bytes.Buffer) (map[string]*tar.Header, []string) {
+		headers := make(map[string]*tar.Header)
+		var names []string
+		reader := tar.NewReader(content)
+		for {
+			header, err := reader.Next()
+			if errors.Is(err, io.EOF) {
+				return headers, names
+			}
+			Expect(err).ToNot(HaveOccurred())
+			headers[header.Name] = header
+			names = append(names, header.Name)
+		}
+	}
+
+	It("extracts the first WAL file from the backup label", func() {
+		labelFile := "START WAL LOCATION: 0/2000028 (file 000000010000000000000002)\n" +
+			"CHECKPOINT LOCATION: 0/2000060\n"
+		Expect(getStartWALFromBackupLabel(labelFile)).To(Equal("000000010000000000000002"))
+
+		_, err := getStartWALFromBackupLabel("CHECKPOINT LOCATION: 0/2000060\n")
+		Expect(err).To(Equal(errMissingStartWAL))
+	})
+
+	It("archives the data directory and the needed WAL files", func() {
+		tempDir := GinkgoT().TempDir()
+		pgData := filepath.Join(tempDir, "pgdata")
+		walDir := filepath.Join(tempDir, "wal")
+		Expect(os.MkdirAll(filepath.Join(pgData, "base", "1"), 0o700)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(pgData, "global"), 0o700)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(pgData, "pg_stat_tmp"), 0o700)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(pgData, "log"), 0o700)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(walDir, "archive_status"), 0o700)).To(Succeed())
+		Expect(os.Symlink(walDir, filepath.Join(pgData, "pg_wal"))).To(Succeed())
+		Expect(syscall.Mkfifo(filepath.Join(pgData, "log", "postgres.csv"), 0o600)).To(Succeed())
+
+		files := map[string]string{
+			filepath.Join(pgData, "PG_VERSION"):                                       "16\n",
+			filepath.Join(pgData, "base", "1", "1259"):                                "data",
+			filepath.Join(pgData, "base", "1", "pg_internal.init"):                    "cache",
+			filepath.Join(pgData, "postmaster.pid"):                                   "42",
+			filepath.Join(pgData, "standby.signal"):                                   "",
+			filepath.Join(pgData, "global", "pg_control"):                             "control",
+			filepath.Join(pgData, "global", "1262"):                                   "data",
+			filepath.Join(pgData, "pg_stat_tmp", "global.stat"):                       "stats",
+			filepath.Join(walDir, "000000010000000000000001"):                         "old",
+			filepath.Join(walDir, "000000010000000000000002"):                         "begin",
+			filepath.Join(walDir, "000000010000000000000003"):                         "next",
+			filepath.Join(walDir, "00000002.history"):                                 "history",
+			filepath.Join(walDir, "archive_status", "000000010000000000000002.ready"): "",
+		}
+		for path, content := range files {
+			Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+		}
+
+		var content bytes.Buffer
+		archiver := newPhysicalBackupArchiver(pgData, &content)
+		Expect(archiver.addDataDirectory()).To(Succeed())
+		Expect(archiver.addWALFiles("000000010000000000000002")).To(Succeed())
+		Expect(archiver.addFile("backup_label", []byte("label"))).To(Succeed())
+		Expect(archiver.close()).To(Succeed())
+
+		headers, names := readTarball(&content)
+		Expect(headers).To(HaveKey("PG_VERSION"))
+		Expect(headers).To(HaveKey("global/1262"))
+		Expect(headers).To(HaveKey("base/1/1259"))
+		Expect(headers).To(HaveKey("pg_stat_tmp/"))
+		Expect(headers).To(HaveKey("pg_wal/"))
+		Expect(headers).To(HaveKey("pg_wal/archive_status/"))
+		Expect(headers).To(HaveKey("pg_wal/000000010000000000000002"))
+		Expect(headers).To(HaveKey("pg_wal/000000010000000000000003"))
+		Expect(headers).To(HaveKey("pg_wal/00000002.history"))
+		Expect(headers).To(HaveKey("backup_label"))
+		Expect(headers["pg_wal/"].Typeflag).To(BeEquivalentTo(tar.TypeDir))
+
+		Expect(headers).ToNot(HaveKey("base/1/pg_internal.init"))
+		Expect(headers).ToNot(HaveKey("postmaster.pid"))
+		Expect(headers).ToNot(HaveKey("standby.signal"))
+		Expect(headers).ToNot(HaveKey("pg_stat_tmp/global.stat"))
+		Expect(headers).ToNot(HaveKey("log/postgres.csv"))
+		Expect(headers).ToNot(HaveKey("pg_wal/000000010000000000000001"))
+		Expect(headers).ToNot(HaveKey("pg_wal/archive_status/000000010000000000000002.ready"))
+
+		// The control file is the last file of the data directory,
+		// followed only by the WAL files and the backup label
+		Expect(names).To(HaveLen(len(headers)))
+		Expect(names[len(names)-5:]).To(Equal([]string{
+			"global/pg_control",
+			"pg_wal/000000010000000000000002",
+			"pg_wal/000000010000000000000003",
+			"pg_wal/00000002.history",
+			"backup_label",
+		}))
+	})
+
+	It("archives the content of the tablespaces in place", func() {
+		tempDir := GinkgoT().TempDir()
+		pgData := filepath.Join(tempDir, "pgdata")
+		location := filepath.Join(tempDir, "tablespaces", "atablespace", "data")
+		Expect(os.MkdirAll(filepath.Join(pgData, "global"), 0o700)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(pgData, "pg_tblspc"), 0o700)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(location, "PG_16_202307071", "16385", "pgsql_tmp"), 0o700)).To(Succeed())
+		Expect(os.Symlink(location, filepath.Join(pgData, "pg_tblspc", "16384"))).To(Succeed())
+
+		files := map[string]string{
+			filepath.Join(pgData, "global", "pg_control"):                              "control",
+			filepath.Join(location, "PG_16_202307071", "16385", "16386"):               "data",
+			filepath.Join(location, "PG_16_202307071", "16385", "pgsql_tmp", "tmp0.0"): "temp",
+		}
+		for path, content := range files {
+			Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+		}
+
+		var content bytes.Buffer
+		archiver := newPhysicalBackupArchiver(pgData, &content)
+		Expect(archiver.addDataDirectory()).To(Succeed())
+		Expect(archiver.close()).To(Succeed())
+
+		headers, _ := readTarball(&content)
+		Expect(headers).To(HaveKey("pg_tblspc/16384/"))
+		Expect(headers["pg_tblspc/16384/"].Typeflag).To(BeEquivalentTo(tar.TypeDir))
+		Expect(headers).To(HaveKey("pg_tblspc/16384/PG_16_202307071/16385/16386"))
+		Expect(headers).ToNot(HaveKey("pg_tblspc/16384/PG_16_202307071/16385/pgsql_tmp/"))
+		Expect(headers).ToNot(HaveKey("pg_tblspc/16384/PG_16_202307071/16385/pgsql_tmp/tmp0.0"))
+	})
+
+	It("pads the files truncated while they are archived", func() {
+		pgData := GinkgoT().TempDir()
+		path := filepath.Join(pgData, "1259")
+		Expect(os.WriteFile(path, []byte("12345678"), 0o600)).To(Succeed())
+		info, err := os.Stat(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.Truncate(path, 3)).To(Succeed())
+
+		var content bytes.Buffer
+		archiver := newPhysicalBackupArchiver(pgData, &content)
+		Expect(archiver.addRegularFile(path, "base/1/1259", info)).To(Succeed())
+		Expect(archiver.close()).To(Succeed())
+
+		reader := tar.NewReader(&content)
+		header, err := reader.Next()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(header.Size).To(BeEquivalentTo(8))
+		data, err := io.ReadAll(reader)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(data).To(Equal([]byte{'1', '2', '3', 0, 0, 0, 0, 0}))
+	})
+})
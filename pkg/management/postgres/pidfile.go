@@ -17,10 +17,12 @@ limitations under the License.
 package postgres
 
 import (
+	"fmt"
 	"os"
 	"path"
 	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/mitchellh/go-ps"
 	"k8s.io/utils/strings/slices"
@@ -99,6 +101,21 @@ func (instance *Instance) CleanUpStalePid() error {
 	return nil
 }
 
+// KillPostmaster terminates the running PostgreSQL server with SIGKILL,
+// simulating a crash
+func (instance *Instance) KillPostmaster() error {
+	_, pid, err := instance.GetPostmasterPidFromFile(path.Join(instance.PgData, PostgresqlPidFile))
+	if err != nil {
+		return fmt.Errorf("while reading the PID of PostgreSQL: %w", err)
+	}
+
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return process.Signal(syscall.SIGKILL)
+}
+
 // GetPostmasterPidFromFile reads the given postmaster pid file, parse it and return its content and the actual pid
 func (instance *Instance) GetPostmasterPidFromFile(pidFile string) ([]byte, int, error) {
 	pidFileExists, err := fileutils.FileExists(pidFile)
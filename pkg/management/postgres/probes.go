@@ -62,6 +62,64 @@ func (instance *Instance) IsServerReady() error {
 	return superUserDB.Ping()
 }
 
+// IsServerReadyAndStreaming check if the instance can accept connections
+// and, if it is a replica, if it is streaming WAL from its source.
+// If maximumLag is positive, a replica is also required to have a replay
+// lag, in bytes, not exceeding it. As in the health of the instance, the
+// lag is measured between the end of the WAL of the source, as reported
+// by the WAL sender, and the replay LSN
+func (instance *Instance) IsServerReadyAndStreaming(maximumLag int64) error {
+	if err := instance.IsServerReady(); err != nil {
+		return err
+	}
+
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	var isInRecovery, isStreaming bool
+	var replayLag int64
+	row := superUserDB.QueryRow(
+		"SELECT pg_is_in_recovery(), " +
+			"EXISTS (SELECT 1 FROM pg_stat_wal_receiver WHERE status = 'streaming'), " +
+			"COALESCE(pg_wal_lsn_diff(" +
+			"(SELECT latest_end_lsn FROM pg_stat_wal_receiver), pg_last_wal_replay_lsn()), 0)::bigint")
+	if err := row.Scan(&isInRecovery, &isStreaming, &replayLag); err != nil {
+		return err
+	}
+
+	if !isInRecovery {
+		return nil
+	}
+
+	if !isStreaming {
+		return fmt.Errorf("the replica is not streaming from its source")
+	}
+
+	lagExceeded := isReplayLagExceeded(replayLag, maximumLag, instance.replayLagExceeded.Load())
+	instance.replayLagExceeded.Store(lagExceeded)
+	if lagExceeded {
+		return fmt.Errorf("the replay lag of the replica is too high: %d bytes", replayLag)
+	}
+
+	return nil
+}
+
+// isReplayLagExceeded checks if the replay lag exceeds the passed maximum.
+// To avoid flapping, a replica whose lag was already exceeding the maximum
+// is considered lagging until its lag goes below half of the maximum
+func isReplayLagExceeded(replayLag, maximumLag int64, wasExceeded bool) bool {
+	switch {
+	case maximumLag <= 0:
+		return false
+	case wasExceeded:
+		return replayLag > maximumLag/2
+	default:
+		return replayLag > maximumLag
+	}
+}
+
 // GetStatus Extract the status of this PostgreSQL database
 func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err error) {
 	result = &postgres.PostgresqlStatus{
@@ -101,18 +159,31 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 	row := superUserDB.QueryRow(
 		`SELECT
 			(pg_control_system()).system_identifier,
+			-- The LSN of the latest checkpoint
+			(pg_control_checkpoint()).checkpoint_lsn,
 			-- True if this is a primary instance
 			NOT pg_is_in_recovery() as primary,
 			-- True if at least one column requires a restart
 			EXISTS(SELECT 1 FROM pg_settings WHERE pending_restart),
 			-- The size of database in human readable format
 			(SELECT pg_size_pretty(SUM(pg_database_size(oid))) FROM pg_database)`)
-	err = row.Scan(&result.SystemID, &result.IsPrimary, &result.PendingRestart, &result.TotalInstanceSize)
+	err = row.Scan(
+		&result.SystemID,
+		&result.LatestCheckpointLSN,
+		&result.IsPrimary,
+		&result.PendingRestart,
+		&result.TotalInstanceSize,
+	)
 	if err != nil {
 		return result, err
 	}
 
 	if result.PendingRestart {
+		result.PendingRestartParameters, err = getPendingRestartParameters(superUserDB)
+		if err != nil {
+			return result, err
+		}
+
 		err = updateResultForDecrease(instance, superUserDB, result)
 		if err != nil {
 			return result, err
@@ -124,6 +195,9 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 		return result, err
 	}
 
+	health, healthReason := getHealth(result, instance.IsDesignatedPrimary, instance.MaximumReplayLag)
+	result.Health, result.HealthReason = string(health), healthReason
+
 	result.InstanceArch = runtime.GOARCH
 
 	result.ExecutableHash, err = executablehash.Get()
@@ -136,6 +210,32 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 	return result, nil
 }
 
+// getPendingRestartParameters gets the sorted list of the parameters
+// whose new value will be applied only after a restart
+func getPendingRestartParameters(superUserDB *sql.DB) (parameters []string, err error) {
+	rows, err := superUserDB.Query(
+		"SELECT name FROM pg_settings WHERE pending_restart ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		exitErr := rows.Close()
+		if exitErr != nil {
+			err = exitErr
+		}
+	}()
+
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		parameters = append(parameters, name)
+	}
+
+	return parameters, rows.Err()
+}
+
 // updateResultForDecrease updates the given postgres.PostgresqlStatus
 // in case of pending restart, by checking whether the restart is due to hot standby
 // sensible parameters being decreased
@@ -301,12 +401,64 @@ func (instance *Instance) fillWalStatus(result *postgres.PostgresqlStatus) error
 		return nil
 	}
 	var err error
-	var replicationInfo postgres.PgStatReplicationList
+	result.ReplicationInfo, err = instance.GetPgStatReplication()
+	if err != nil {
+		return err
+	}
+
+	result.ReadyWALFiles, _, err = GetWALArchiveCounters()
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
 
+// fillStatusFromReplica get WAL information for replica servers
+func (instance *Instance) fillStatusFromReplica(result *postgres.PostgresqlStatus) error {
 	superUserDB, err := instance.GetSuperUserDB()
 	if err != nil {
 		return err
 	}
+
+	// pg_last_wal_receive_lsn may be NULL when using non-streaming
+	// replicas
+	row := superUserDB.QueryRow(
+		"SELECT " +
+			"(SELECT timeline_id FROM pg_control_checkpoint()), " +
+			"COALESCE(pg_last_wal_receive_lsn()::varchar, ''), " +
+			"COALESCE(pg_last_wal_replay_lsn()::varchar, ''), " +
+			"pg_is_wal_replay_paused()")
+	if err := row.Scan(&result.TimeLineID, &result.ReceivedLsn, &result.ReplayLsn, &result.ReplayPaused); err != nil {
+		return err
+	}
+
+	// Sometimes pg_last_wal_replay_lsn is getting evaluated after
+	// pg_last_wal_receive_lsn and this, if other WALs are received,
+	// can result in a replay being greater then received. Since
+	// we can't force the planner to execute functions in a required
+	// order, we fix the result here
+	if result.ReceivedLsn.Less(result.ReplayLsn) {
+		result.ReceivedLsn = result.ReplayLsn
+	}
+
+	result.WalReceiverInfo, err = instance.GetPgStatWalReceiver()
+	if err != nil {
+		return err
+	}
+	result.IsWalReceiverActive = result.WalReceiverInfo != nil
+	return nil
+}
+
+// GetPgStatReplication gets the WAL senders of the primary which are
+// streaming to the instances of the cluster
+func (instance *Instance) GetPgStatReplication() (postgres.PgStatReplicationList, error) {
+	var replicationInfo postgres.PgStatReplicationList
+
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return nil, err
+	}
 	rows, err := superUserDB.Query(
 		`SELECT
 			application_name,
@@ -325,10 +477,11 @@ func (instance *Instance) fillWalStatus(result *postgres.PostgresqlStatus) error
 		fmt.Sprintf("%s-%%", instance.ClusterName),
 		v1.StreamingReplicationUser,
 	)
+	if err != nil {
+		return nil, err
+	}
 	defer func() {
-		if closeErr := rows.Close(); closeErr != nil && err == nil {
-			err = closeErr
-		}
+		_ = rows.Close()
 	}()
 
 	for rows.Next() {
@@ -347,57 +500,63 @@ func (instance *Instance) fillWalStatus(result *postgres.PostgresqlStatus) error
 			&pgr.SyncPriority,
 		)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		replicationInfo = append(replicationInfo, pgr)
 	}
-	result.ReplicationInfo = replicationInfo
 
-	if err = rows.Err(); err != nil {
-		return err
-	}
+	return replicationInfo, rows.Err()
+}
 
-	result.ReadyWALFiles, _, err = GetWALArchiveCounters()
+// GetPgStatWalReceiver gets the status of the WAL receiver of a standby,
+// or nil if the WAL receiver is not running
+func (instance *Instance) GetPgStatWalReceiver() (*postgres.PgStatWalReceiver, error) {
+	version, err := instance.GetPgVersion()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
-}
-
-// fillStatusFromReplica get WAL information for replica servers
-func (instance *Instance) fillStatusFromReplica(result *postgres.PostgresqlStatus) error {
 	superUserDB, err := instance.GetSuperUserDB()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	// pg_last_wal_receive_lsn may be NULL when using non-streaming
-	// replicas
-	row := superUserDB.QueryRow(
-		"SELECT " +
-			"(SELECT timeline_id FROM pg_control_checkpoint()), " +
-			"COALESCE(pg_last_wal_receive_lsn()::varchar, ''), " +
-			"COALESCE(pg_last_wal_replay_lsn()::varchar, ''), " +
-			"pg_is_wal_replay_paused()")
-	if err := row.Scan(&result.TimeLineID, &result.ReceivedLsn, &result.ReplayLsn, &result.ReplayPaused); err != nil {
-		return err
+	// The sender_host column has been added in PostgreSQL 11
+	senderHost := "''"
+	if version.Major >= 11 {
+		senderHost = "coalesce(sender_host, '')"
 	}
 
-	// Sometimes pg_last_wal_replay_lsn is getting evaluated after
-	// pg_last_wal_receive_lsn and this, if other WALs are received,
-	// can result in a replay being greater then received. Since
-	// we can't force the planner to execute functions in a required
-	// order, we fix the result here
-	if result.ReceivedLsn.Less(result.ReplayLsn) {
-		result.ReceivedLsn = result.ReplayLsn
+	var result postgres.PgStatWalReceiver
+	var lastMsgReceiptTime sql.NullTime
+	row := superUserDB.QueryRow(
+		`SELECT
+			status,
+			` + senderHost + `,
+			coalesce(slot_name, ''),
+			coalesce(pg_catalog.pg_last_wal_receive_lsn()::text, ''),
+			coalesce(latest_end_lsn::text, ''),
+			last_msg_receipt_time
+		FROM pg_catalog.pg_stat_wal_receiver`)
+	err = row.Scan(
+		&result.Status,
+		&result.SenderHost,
+		&result.SlotName,
+		&result.ReceivedLsn,
+		&result.LatestEndLsn,
+		&lastMsgReceiptTime,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
 	}
-
-	result.IsWalReceiverActive, err = instance.IsWALReceiverActive()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
+
+	if lastMsgReceiptTime.Valid {
+		result.LastMsgReceiptTime = &lastMsgReceiptTime.Time
+	}
+	return &result, nil
 }
 
 // IsWALReceiverActive check if the WAL receiver process is active by looking
@@ -510,3 +669,115 @@ func GetReadyWALFiles() (fileNames []string, err error) {
 
 	return fileNames, nil
 }
+
+// PgStatStatementsQuery is the representation of a query
+// tracked by the pg_stat_statements extension
+type PgStatStatementsQuery struct {
+	DatabaseName         string
+	UserName             string
+	QueryID              string
+	Calls                int64
+	TotalExecTimeSeconds float64
+	Rows                 int64
+}
+
+// TryGetPgStatStatementsTopQueries gets the queries having the highest
+// total execution time from pg_stat_statements. It returns nil when the
+// extension is not installed in the database of the superuser
+func (instance *Instance) TryGetPgStatStatementsTopQueries(limit int) ([]PgStatStatementsQuery, error) {
+	version, err := instance.GetPgVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var isInstalled bool
+	row := superUserDB.QueryRow(
+		"SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_stat_statements')")
+	if err := row.Scan(&isInstalled); err != nil || !isInstalled {
+		return nil, err
+	}
+
+	// The total_time column has been renamed to total_exec_time in PostgreSQL 13
+	totalTimeColumn := "total_exec_time"
+	if version.Major < 13 {
+		totalTimeColumn = "total_time"
+	}
+
+	rows, err := superUserDB.Query(fmt.Sprintf(
+		`SELECT d.datname, r.rolname, COALESCE(s.queryid::text, ''),
+		SUM(s.calls)::bigint, SUM(s.%[1]s) / 1000, SUM(s.rows)::bigint
+		FROM pg_stat_statements s
+		JOIN pg_database d ON d.oid = s.dbid
+		JOIN pg_roles r ON r.oid = s.userid
+		GROUP BY d.datname, r.rolname, s.queryid
+		ORDER BY SUM(s.%[1]s) DESC
+		LIMIT $1`, totalTimeColumn), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var result []PgStatStatementsQuery
+	for rows.Next() {
+		var query PgStatStatementsQuery
+		if err := rows.Scan(
+			&query.DatabaseName,
+			&query.UserName,
+			&query.QueryID,
+			&query.Calls,
+			&query.TotalExecTimeSeconds,
+			&query.Rows,
+		); err != nil {
+			return nil, err
+		}
+		result = append(result, query)
+	}
+
+	return result, rows.Err()
+}
+
+// ArchiverStatus is the status of the WAL archiver, as reported
+// by pg_stat_archiver
+type ArchiverStatus struct {
+	ArchivedCount  int64
+	FailedCount    int64
+	LastFailedWAL  string
+	LastFailedTime string
+
+	// IsFailing is true when the last archiving attempt failed
+	IsFailing bool
+}
+
+// GetArchiverStatus gets the status of the WAL archiver
+func (instance *Instance) GetArchiverStatus() (*ArchiverStatus, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return nil, err
+	}
+
+	var result ArchiverStatus
+	row := superUserDB.QueryRow(
+		"SELECT archived_count, failed_count, " +
+			"COALESCE(last_failed_wal, ''), " +
+			"COALESCE(last_failed_time::text, ''), " +
+			"COALESCE(last_failed_time, '-infinity') > COALESCE(last_archived_time, '-infinity') " +
+			"FROM pg_catalog.pg_stat_archiver")
+	if err := row.Scan(
+		&result.ArchivedCount,
+		&result.FailedCount,
+		&result.LastFailedWAL,
+		&result.LastFailedTime,
+		&result.IsFailing,
+	); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
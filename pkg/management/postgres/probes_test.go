@@ -0,0 +1,40 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("replay lag of the readiness probe", func() {
+	It("never considers the lag exceeded without a maximum", func() {
+		Expect(isReplayLagExceeded(1024*1024, 0, false)).To(BeFalse())
+		Expect(isReplayLagExceeded(1024*1024, 0, true)).To(BeFalse())
+	})
+
+	It("considers the lag exceeded when over the maximum", func() {
+		Expect(isReplayLagExceeded(1000, 1000, false)).To(BeFalse())
+		Expect(isReplayLagExceeded(1001, 1000, false)).To(BeTrue())
+	})
+
+	It("waits for the lag to go below half of the maximum to recover", func() {
+		Expect(isReplayLagExceeded(900, 1000, true)).To(BeTrue())
+		Expect(isReplayLagExceeded(501, 1000, true)).To(BeTrue())
+		Expect(isReplayLagExceeded(500, 1000, true)).To(BeFalse())
+	})
+})
@@ -0,0 +1,261 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/progress"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// procDir is where the proc filesystem is mounted
+const procDir = "/proc"
+
+// backupProgressPeriod is the interval between two
+// updates of the progress of a running backup
+const backupProgressPeriod = 30 * time.Second
+
+// runBarmanCloudBackup runs barman-cloud-backup with the passed options,
+// tracking the progress of the upload in the status of the backup
+func (b *BackupCommand) runBarmanCloudBackup(ctx context.Context, options []string) error {
+	totalBytes, err := progress.DirectorySize(b.Instance.PgData, "pg_wal")
+	if err != nil {
+		b.Log.Info("Cannot estimate the size of the backup", "err", err)
+		totalBytes = 0
+	}
+
+	cmd := exec.Command(barmanCapabilities.BarmanCloudBackup, options...) // #nosec G204
+	cmd.Env = b.Env
+	cmd.Env = append(cmd.Env, "TMPDIR="+postgres.BackupTemporaryDirectory)
+	startTime := time.Now()
+	streamingCmd, err := execlog.RunStreamingNoWait(cmd, barmanCapabilities.BarmanCloudBackup)
+	if err != nil {
+		return err
+	}
+
+	stopTracking := trackProgress(
+		ctx,
+		progress.NewTracker(progress.ProcessReadSampler(procDir, cmd.Process.Pid, b.Instance.PgData), totalBytes),
+		backupProgressPeriod,
+		func(snapshot *progress.Snapshot, milestone bool) {
+			b.reportProgress(ctx, snapshot, milestone)
+		})
+
+	err = streamingCmd.Wait()
+	stopTracking()
+	if err != nil {
+		return err
+	}
+
+	b.Backup.Status.Progress = getCompletedBackupProgress(b.Backup.Status.Progress, totalBytes, startTime, time.Now())
+	return nil
+}
+
+// reportProgress stores the progress of the backup in its status,
+// raising an event when a new milestone is reached
+func (b *BackupCommand) reportProgress(ctx context.Context, snapshot *progress.Snapshot, milestone bool) {
+	b.Log.Info("Backup progress",
+		"bytesCopied", snapshot.BytesCopied,
+		"totalBytes", snapshot.TotalBytes,
+		"percentage", snapshot.Percentage(),
+		"bytesPerSecond", snapshot.BytesPerSecond,
+		"currentFile", snapshot.CurrentFile)
+	if milestone {
+		b.Recorder.Event(b.Backup, "Normal", "Progress", "Backup progress: "+snapshot.String())
+	}
+
+	b.Backup.Status.Progress = &apiv1.BackupProgress{
+		BytesCopied:    snapshot.BytesCopied,
+		TotalBytes:     snapshot.TotalBytes,
+		Percentage:     snapshot.Percentage(),
+		CurrentFile:    snapshot.CurrentFile,
+		BytesPerSecond: snapshot.BytesPerSecond,
+		LastUpdateTime: time.Now().Format(time.RFC3339),
+	}
+	if err := UpdateBackupStatusAndRetry(ctx, b.Client, b.Backup); err != nil {
+		b.Log.Info("Cannot update the progress of the backup", "err", err)
+	}
+}
+
+// getCompletedBackupProgress gets the progress of a completed backup, given
+// the last one which has been reported. The whole data directory has been
+// read, even if the backup completed before its progress was sampled
+func getCompletedBackupProgress(
+	lastProgress *apiv1.BackupProgress,
+	totalBytes int64,
+	startTime time.Time,
+	now time.Time,
+) *apiv1.BackupProgress {
+	bytesCopied := totalBytes
+	if lastProgress != nil && lastProgress.BytesCopied > bytesCopied {
+		bytesCopied = lastProgress.BytesCopied
+	}
+
+	result := &apiv1.BackupProgress{
+		BytesCopied:    bytesCopied,
+		TotalBytes:     totalBytes,
+		Percentage:     100,
+		LastUpdateTime: now.Format(time.RFC3339),
+	}
+	if elapsed := now.Sub(startTime).Seconds(); elapsed >= 1 {
+		result.BytesPerSecond = int64(float64(bytesCopied) / elapsed)
+	}
+	return result
+}
+
+// copyProgressPeriod is the interval between two samples of the
+// progress of a restore or of a clone of the data directory
+const copyProgressPeriod = 30 * time.Second
+
+// trackProgress samples the progress of a copy with the passed tracker,
+// until the returned function is called
+func trackProgress(
+	ctx context.Context,
+	tracker *progress.Tracker,
+	period time.Duration,
+	report func(snapshot *progress.Snapshot, milestone bool),
+) (stop func()) {
+	progressCtx, cancel := context.WithCancel(ctx)
+	progressDone := make(chan struct{})
+	go func() {
+		defer close(progressDone)
+		progress.Run(progressCtx, tracker, period, report)
+	}()
+
+	return func() {
+		cancel()
+		<-progressDone
+	}
+}
+
+// trackDirectoryCopy samples, until the returned function is called, the
+// progress of a copy writing into the passed directory, given the
+// estimated total size which can be zero when unknown
+func trackDirectoryCopy(
+	ctx context.Context,
+	directory string,
+	totalBytes int64,
+	report func(snapshot *progress.Snapshot, milestone bool),
+) (stop func()) {
+	return trackProgress(ctx, progress.NewTracker(progress.DirectorySampler(directory), totalBytes),
+		copyProgressPeriod, report)
+}
+
+// throttlePeriod is the interval between two checks of
+// the transfer rate of a throttled copy
+const throttlePeriod = 1 * time.Second
+
+// throttleDirectoryCopy limits, until the returned function is called, the
+// average transfer rate of the process copying into the passed directory,
+// pausing it while the copy is faster than the allowed rate
+func throttleDirectoryCopy(
+	ctx context.Context,
+	process *os.Process,
+	directory string,
+	bytesPerSecond int64,
+) (stop func()) {
+	throttleCtx, cancel := context.WithCancel(ctx)
+	throttleDone := make(chan struct{})
+	go func() {
+		defer close(throttleDone)
+
+		paused := false
+		setPaused := func(pause bool) {
+			if pause == paused {
+				return
+			}
+			signal := syscall.SIGCONT
+			if pause {
+				signal = syscall.SIGSTOP
+			}
+			if err := process.Signal(signal); err != nil {
+				log.Info("Cannot throttle the copy", "err", err)
+				return
+			}
+			paused = pause
+		}
+		defer setPaused(false)
+
+		sampler := progress.DirectorySampler(directory)
+		startTime := time.Now()
+		ticker := time.NewTicker(throttlePeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-throttleCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			bytesCopied, _, err := sampler()
+			if err != nil {
+				continue
+			}
+			setPaused(isCopyFasterThan(bytesCopied, bytesPerSecond, time.Since(startTime)))
+		}
+	}()
+
+	return func() {
+		cancel()
+		<-throttleDone
+	}
+}
+
+// isCopyFasterThan checks whether a copy, having copied the passed amount
+// of bytes in the elapsed time, is faster than the allowed transfer rate
+func isCopyFasterThan(bytesCopied, bytesPerSecond int64, elapsed time.Duration) bool {
+	return float64(bytesCopied) > float64(bytesPerSecond)*elapsed.Seconds()
+}
+
+// newRestoreProgressReporter creates a function logging the progress of
+// a restore, and raising an event in the cluster when a milestone is reached
+func newRestoreProgressReporter(cluster *apiv1.Cluster) func(*progress.Snapshot, bool) {
+	recorder, err := management.NewEventRecorder()
+	if err != nil {
+		log.Info("Cannot create the event recorder, the restore progress will only be logged", "err", err)
+	}
+
+	return func(snapshot *progress.Snapshot, milestone bool) {
+		log.Info("Restore progress",
+			"bytesCopied", snapshot.BytesCopied,
+			"totalBytes", snapshot.TotalBytes,
+			"percentage", snapshot.Percentage(),
+			"bytesPerSecond", snapshot.BytesPerSecond,
+			"currentFile", snapshot.CurrentFile)
+		if milestone && recorder != nil {
+			recorder.Event(cluster, "Normal", "RestoreProgress", "Restore progress: "+snapshot.String())
+		}
+	}
+}
+
+// logCloneProgress logs the progress of the clone of a data directory
+func logCloneProgress(snapshot *progress.Snapshot, _ bool) {
+	log.Info("Clone progress",
+		"bytesCopied", snapshot.BytesCopied,
+		"bytesPerSecond", snapshot.BytesPerSecond,
+		"currentFile", snapshot.CurrentFile)
+}
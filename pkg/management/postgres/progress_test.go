@@ -0,0 +1,62 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backup progress", func() {
+	startTime := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	It("completes the progress reported while the backup was running", func() {
+		completedProgress := getCompletedBackupProgress(&apiv1.BackupProgress{
+			BytesCopied:    1500,
+			TotalBytes:     1000,
+			Percentage:     99,
+			CurrentFile:    "base/1/1259",
+			BytesPerSecond: 10,
+		}, 1000, startTime, startTime.Add(10*time.Second))
+		Expect(completedProgress).To(Equal(&apiv1.BackupProgress{
+			BytesCopied:    1500,
+			TotalBytes:     1000,
+			Percentage:     100,
+			BytesPerSecond: 150,
+			LastUpdateTime: "2023-01-01T10:00:10Z",
+		}))
+	})
+
+	It("uses the size of the data directory when the progress has never been sampled", func() {
+		completedProgress := getCompletedBackupProgress(nil, 1000, startTime, startTime.Add(500*time.Millisecond))
+		Expect(completedProgress.BytesCopied).To(BeEquivalentTo(1000))
+		Expect(completedProgress.Percentage).To(BeEquivalentTo(100))
+		Expect(completedProgress.BytesPerSecond).To(BeZero())
+	})
+})
+
+var _ = Describe("Copy throttling", func() {
+	It("detects a copy faster than the allowed transfer rate", func() {
+		Expect(isCopyFasterThan(2048, 1024, 1*time.Second)).To(BeTrue())
+		Expect(isCopyFasterThan(2048, 1024, 2*time.Second)).To(BeFalse())
+		Expect(isCopyFasterThan(0, 1024, 0)).To(BeFalse())
+	})
+})
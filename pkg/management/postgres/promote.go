@@ -17,36 +17,34 @@ limitations under the License.
 package postgres
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os/exec"
 	"strconv"
 	"time"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
 )
 
-// PromoteAndWait promotes this instance, and wait DefaultPgCtlTimeoutForPromotion
+// ErrPromotionTimedOut is raised when the promotion of the instance didn't
+// complete within the promotion timeout, and the instance manager stopped
+// waiting for it. The promotion itself can't be cancelled, and is still
+// in progress
+var ErrPromotionTimedOut = errors.New("the promotion of the instance timed out")
+
+// PromoteAndWait promotes this instance, and wait PgCtlTimeoutForPromotion
 // seconds for it to happen
-func (instance *Instance) PromoteAndWait() error {
+func (instance *Instance) PromoteAndWait(ctx context.Context) error {
 	instance.ShutdownConnections()
 
 	instance.LogPgControldata("promote")
 
-	options := []string{
-		"-D",
-		instance.PgData,
-		"-w",
-		"promote",
-		"-t " + strconv.Itoa(int(instance.PgCtlTimeoutForPromotion)),
-	}
-
-	log.Info("Promoting instance", "pgctl_options", options)
-
-	pgCtlCmd := exec.Command(pgCtlName, options...) // #nosec
-	err := execlog.RunStreaming(pgCtlCmd, pgCtlName)
-	if err != nil {
-		return fmt.Errorf("error promoting the PostgreSQL instance: %w", err)
+	if err := instance.promote(ctx); err != nil {
+		return err
 	}
 
 	timeLimit := time.Now().Add(1 * time.Minute)
@@ -86,3 +84,110 @@ func (instance *Instance) PromoteAndWait() error {
 
 	return nil
 }
+
+// promote requests the promotion of the instance with pg_promote(),
+// available since PostgreSQL 12, or with pg_ctl on older versions.
+// PostgreSQL can't cancel a requested promotion, so when pg_promote()
+// exceeds the timeout we either keep waiting for the promotion to
+// complete or stop waiting, depending on PromotionTimeoutAction
+func (instance *Instance) promote(ctx context.Context) error {
+	majorVersion, err := postgresutils.GetMajorVersion(instance.PgData)
+	if err != nil {
+		return fmt.Errorf("while detecting the PostgreSQL version: %w", err)
+	}
+
+	if majorVersion < 12 {
+		return instance.promoteWithPgCtl()
+	}
+
+	promoted, err := instance.promoteWithPgPromote()
+	if err != nil {
+		return err
+	}
+	if promoted {
+		return nil
+	}
+
+	if instance.PromotionTimeoutAction == apiv1.PromotionTimeoutActionRequeue {
+		log.Info("The promotion exceeded the timeout, checking it again in the next reconciliation loop",
+			"timeout", instance.PgCtlTimeoutForPromotion)
+		return ErrPromotionTimedOut
+	}
+
+	log.Info("The promotion exceeded the timeout, waiting for it to complete",
+		"timeout", instance.PgCtlTimeoutForPromotion)
+	return instance.waitForPromotion(ctx)
+}
+
+// waitForPromotion waits for the requested promotion of the instance to
+// complete, for at most another PgCtlTimeoutForPromotion seconds
+func (instance *Instance) waitForPromotion(ctx context.Context) error {
+	timeout := time.Duration(instance.PgCtlTimeoutForPromotion) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		isPrimary, err := instance.IsPrimary()
+		if err != nil {
+			return fmt.Errorf("while checking the promotion of the instance: %w", err)
+		}
+		if isPrimary {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return ErrPromotionTimedOut
+			}
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// promoteWithPgPromote promotes the instance with pg_promote(), waiting
+// for PgCtlTimeoutForPromotion seconds. Returns false if the promotion
+// didn't complete in time
+func (instance *Instance) promoteWithPgPromote() (bool, error) {
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return false, err
+	}
+
+	log.Info("Promoting instance", "function", "pg_promote",
+		"timeout", instance.PgCtlTimeoutForPromotion)
+
+	var promoted bool
+	row := db.QueryRow("SELECT pg_promote(true, $1)", instance.PgCtlTimeoutForPromotion)
+	if err := row.Scan(&promoted); err != nil {
+		return false, fmt.Errorf("error promoting the PostgreSQL instance: %w", err)
+	}
+
+	return promoted, nil
+}
+
+// promoteWithPgCtl promotes the instance with pg_ctl, waiting
+// for PgCtlTimeoutForPromotion seconds
+func (instance *Instance) promoteWithPgCtl() error {
+	options := []string{
+		"-D",
+		instance.PgData,
+		"-w",
+		"promote",
+		"-t " + strconv.Itoa(int(instance.PgCtlTimeoutForPromotion)),
+	}
+
+	log.Info("Promoting instance", "pgctl_options", options)
+
+	pgCtlCmd := exec.Command(pgCtlName, options...) // #nosec
+	err := execlog.RunStreaming(pgCtlCmd, pgCtlName)
+	if err != nil {
+		return fmt.Errorf("error promoting the PostgreSQL instance: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,259 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// recoveryConfFileName is the file containing the recovery
+	// settings up to PostgreSQL 11, whose presence makes the
+	// instance start in recovery
+	recoveryConfFileName = "recovery.conf"
+
+	// postgresAutoConfFileName is the file containing the recovery
+	// settings since PostgreSQL 12
+	postgresAutoConfFileName = "postgresql.auto.conf"
+
+	// standbySignalFileName is the file making the instance start
+	// as a standby since PostgreSQL 12
+	standbySignalFileName = "standby.signal"
+
+	// recoverySignalFileName is the file making the instance start a
+	// targeted recovery since PostgreSQL 12
+	recoverySignalFileName = "recovery.signal"
+
+	// recoveryConfMaxMajorVersion is the last major version of
+	// PostgreSQL using the recovery.conf file
+	recoveryConfMaxMajorVersion = 11
+)
+
+// recoveryOptions are the settings making an instance follow another
+// server or replay WAL files, which are kept in "postgresql.auto.conf"
+// since PostgreSQL 12
+var recoveryOptions = []string{
+	"primary_conninfo",
+	"primary_slot_name",
+	"restore_command",
+	"archive_cleanup_command",
+	"recovery_end_command",
+	"recovery_min_apply_delay",
+	"recovery_target",
+	"recovery_target_action",
+	"recovery_target_inclusive",
+	"recovery_target_lsn",
+	"recovery_target_name",
+	"recovery_target_time",
+	"recovery_target_timeline",
+	"recovery_target_xid",
+}
+
+// RecoveryConfiguration writes the recovery settings of an instance where
+// its major version of PostgreSQL expects them: in the "recovery.conf" file
+// up to PostgreSQL 11, and in the "postgresql.auto.conf" file, together with
+// the "standby.signal" or "recovery.signal" files, since PostgreSQL 12.
+// This allows the same cluster definition to work with every supported
+// version, which is detected from the data directory at runtime
+type RecoveryConfiguration struct {
+	pgData       string
+	majorVersion int
+}
+
+// NewRecoveryConfiguration creates the recovery configuration of the
+// passed data directory, detecting its major version of PostgreSQL
+func NewRecoveryConfiguration(pgData string) (*RecoveryConfiguration, error) {
+	majorVersion, err := postgresutils.GetMajorVersion(pgData)
+	if err != nil {
+		return nil, fmt.Errorf("cannot detect major version: %w", err)
+	}
+
+	return &RecoveryConfiguration{pgData: pgData, majorVersion: majorVersion}, nil
+}
+
+// UsesRecoveryConf returns true when the recovery settings are stored
+// in the "recovery.conf" file, i.e. up to PostgreSQL 11
+func (config *RecoveryConfiguration) UsesRecoveryConf() bool {
+	return config.majorVersion <= recoveryConfMaxMajorVersion
+}
+
+// FileName returns the path of the file containing the recovery settings
+func (config *RecoveryConfiguration) FileName() string {
+	if config.UsesRecoveryConf() {
+		return path.Join(config.pgData, recoveryConfFileName)
+	}
+
+	return path.Join(config.pgData, postgresAutoConfFileName)
+}
+
+// IsStandby returns true when the instance is configured to start as a
+// standby. It also works when the instance is not running
+func (config *RecoveryConfiguration) IsStandby() (bool, error) {
+	if config.UsesRecoveryConf() {
+		return fileutils.FileExists(config.FileName())
+	}
+
+	return fileutils.FileExists(path.Join(config.pgData, standbySignalFileName))
+}
+
+// ConfigureStandby configures the instance to start as a standby following
+// the server reachable with the passed connection string, or only restoring
+// the WAL files from the archive when it is empty
+func (config *RecoveryConfiguration) ConfigureStandby(primaryConnInfo string) (changed bool, err error) {
+	options := buildReplicationOptions(primaryConnInfo)
+	if config.UsesRecoveryConf() {
+		options["standby_mode"] = "on"
+	} else if err := createEmptyFile(path.Join(config.pgData, standbySignalFileName)); err != nil {
+		return false, err
+	}
+
+	changed, err = configfile.UpdatePostgresConfigurationFile(config.FileName(), options)
+	if err != nil {
+		return false, err
+	}
+	if changed {
+		log.Info("Updated replication settings", "file", config.FileName())
+	}
+
+	return changed, nil
+}
+
+// PrepareReplication writes the settings needed to follow the server
+// reachable with the passed connection string without making the instance
+// start as a standby, so that they are already in place when the instance
+// is demoted. Up to PostgreSQL 11 the settings can only be written in the
+// "recovery.conf" file, which would make the instance start as a standby,
+// so this does nothing
+func (config *RecoveryConfiguration) PrepareReplication(primaryConnInfo string) (changed bool, err error) {
+	if config.UsesRecoveryConf() {
+		return false, nil
+	}
+
+	return configfile.UpdatePostgresConfigurationFile(config.FileName(), buildReplicationOptions(primaryConnInfo))
+}
+
+// SetOption sets a recovery setting of a standby. Up to PostgreSQL 11 the
+// instance must already be a standby, as creating the "recovery.conf"
+// file would make a primary start in recovery
+func (config *RecoveryConfiguration) SetOption(name, value string) (changed bool, err error) {
+	if config.UsesRecoveryConf() {
+		isStandby, err := config.IsStandby()
+		if err != nil {
+			return false, err
+		}
+		if !isStandby {
+			return false, fmt.Errorf("cannot set %s: the instance is not a standby", name)
+		}
+	}
+
+	return configfile.UpdatePostgresConfigurationFile(config.FileName(), map[string]string{name: value})
+}
+
+// RemoveOption removes a recovery setting, if present
+func (config *RecoveryConfiguration) RemoveOption(name string) (changed bool, err error) {
+	currentContent, err := fileutils.ReadFile(config.FileName())
+	if err != nil {
+		return false, fmt.Errorf("error while reading content of %v: %w", config.FileName(), err)
+	}
+	if currentContent == nil {
+		return false, nil
+	}
+
+	updatedContent := configfile.RemoveOptionFromConfigurationContents(string(currentContent), name)
+	return fileutils.WriteStringToFile(config.FileName(), updatedContent)
+}
+
+// ConfigureTargetedRecovery configures the instance to start a recovery
+// with the passed settings, ending when the recovery target is reached.
+// Since PostgreSQL 12 the settings are appended to the passed configuration
+// file, as the "postgresql.auto.conf" file is erased to remove the settings
+// of the original server
+func (config *RecoveryConfiguration) ConfigureTargetedRecovery(
+	recoverySettings string,
+	configurationFile string,
+) error {
+	if config.UsesRecoveryConf() {
+		return os.WriteFile(config.FileName(), []byte(recoverySettings), 0o600)
+	}
+
+	if err := fileutils.AppendStringToFile(configurationFile, recoverySettings); err != nil {
+		return fmt.Errorf("cannot write recovery config: %w", err)
+	}
+
+	if err := os.WriteFile(config.FileName(), []byte(""), 0o600); err != nil {
+		return fmt.Errorf("cannot erase auto config: %w", err)
+	}
+
+	return createEmptyFile(path.Join(config.pgData, recoverySignalFileName))
+}
+
+// Clear removes the recovery settings and the signal files of the data
+// directory, such as the ones of the server whose storage has been cloned,
+// so that the instance doesn't start in recovery
+func (config *RecoveryConfiguration) Clear() error {
+	for _, fileName := range []string{standbySignalFileName, recoverySignalFileName} {
+		if err := fileutils.RemoveFile(path.Join(config.pgData, fileName)); err != nil {
+			return fmt.Errorf("cannot remove %s: %w", fileName, err)
+		}
+	}
+
+	if config.UsesRecoveryConf() {
+		if err := fileutils.RemoveFile(config.FileName()); err != nil {
+			return fmt.Errorf("cannot remove %s: %w", recoveryConfFileName, err)
+		}
+		return nil
+	}
+
+	for _, option := range recoveryOptions {
+		if _, err := config.RemoveOption(option); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// buildReplicationOptions builds the settings needed to
+// follow the server reachable with the passed connection string
+func buildReplicationOptions(primaryConnInfo string) map[string]string {
+	options := map[string]string{
+		"restore_command": fmt.Sprintf(
+			"/controller/manager wal-restore --log-destination %s/%s.json %%f %%p",
+			postgres.LogPath, postgres.LogFileName),
+		"recovery_target_timeline": "latest",
+	}
+
+	if primaryConnInfo != "" {
+		options["primary_conninfo"] = primaryConnInfo
+	}
+
+	return options
+}
+
+// createEmptyFile creates an empty file, used to signal PostgreSQL
+// to start in recovery
+func createEmptyFile(fileName string) error {
+	return os.WriteFile(fileName, []byte(""), 0o600)
+}
@@ -0,0 +1,182 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("recovery configuration", func() {
+	It("fails without a data directory", func() {
+		_, err := NewRecoveryConfiguration(GinkgoT().TempDir())
+		Expect(err).To(HaveOccurred())
+	})
+
+	When("the instance runs PostgreSQL 11", func() {
+		var pgData string
+		var config *RecoveryConfiguration
+
+		BeforeEach(func() {
+			var err error
+			pgData = GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(pgData, "PG_VERSION"), []byte("11\n"), 0o600)).To(Succeed())
+			config, err = NewRecoveryConfiguration(pgData)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("configures a standby in recovery.conf", func() {
+			Expect(config.UsesRecoveryConf()).To(BeTrue())
+			Expect(config.IsStandby()).To(BeFalse())
+
+			changed, err := config.ConfigureStandby("host=cluster-example-rw")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(config.IsStandby()).To(BeTrue())
+
+			content, err := os.ReadFile(filepath.Join(pgData, "recovery.conf"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("standby_mode = 'on'"))
+			Expect(string(content)).To(ContainSubstring("primary_conninfo = 'host=cluster-example-rw'"))
+			Expect(filepath.Join(pgData, "standby.signal")).ToNot(BeAnExistingFile())
+			Expect(filepath.Join(pgData, "postgresql.auto.conf")).ToNot(BeAnExistingFile())
+		})
+
+		It("doesn't prepare the replication of a primary", func() {
+			changed, err := config.PrepareReplication("host=cluster-example-rw")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeFalse())
+			Expect(config.IsStandby()).To(BeFalse())
+		})
+
+		It("sets the recovery settings only on standbys", func() {
+			_, err := config.SetOption("recovery_min_apply_delay", "1000ms")
+			Expect(err).To(HaveOccurred())
+			Expect(config.IsStandby()).To(BeFalse())
+
+			changed, err := config.RemoveOption("recovery_min_apply_delay")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeFalse())
+			Expect(config.IsStandby()).To(BeFalse())
+
+			_, err = config.ConfigureStandby("")
+			Expect(err).ToNot(HaveOccurred())
+			changed, err = config.SetOption("recovery_min_apply_delay", "1000ms")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(os.ReadFile(filepath.Join(pgData, "recovery.conf"))).To(
+				ContainSubstring("recovery_min_apply_delay = '1000ms'"))
+		})
+
+		It("writes a targeted recovery in recovery.conf", func() {
+			customConf := filepath.Join(pgData, "custom.conf")
+			Expect(config.ConfigureTargetedRecovery("recovery_target_action = promote\n", customConf)).To(Succeed())
+			Expect(os.ReadFile(filepath.Join(pgData, "recovery.conf"))).To(
+				BeEquivalentTo("recovery_target_action = promote\n"))
+			Expect(customConf).ToNot(BeAnExistingFile())
+			Expect(filepath.Join(pgData, "recovery.signal")).ToNot(BeAnExistingFile())
+		})
+
+		It("clears the recovery settings", func() {
+			_, err := config.ConfigureStandby("host=cluster-example-rw")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(config.Clear()).To(Succeed())
+			Expect(config.IsStandby()).To(BeFalse())
+			Expect(filepath.Join(pgData, "recovery.conf")).ToNot(BeAnExistingFile())
+		})
+	})
+
+	When("the instance runs PostgreSQL 12 or newer", func() {
+		var pgData string
+		var config *RecoveryConfiguration
+
+		BeforeEach(func() {
+			var err error
+			pgData = GinkgoT().TempDir()
+			Expect(os.WriteFile(filepath.Join(pgData, "PG_VERSION"), []byte("15\n"), 0o600)).To(Succeed())
+			config, err = NewRecoveryConfiguration(pgData)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("configures a standby with standby.signal", func() {
+			Expect(config.UsesRecoveryConf()).To(BeFalse())
+
+			changed, err := config.ConfigureStandby("host=cluster-example-rw")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(config.IsStandby()).To(BeTrue())
+
+			content, err := os.ReadFile(filepath.Join(pgData, "postgresql.auto.conf"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).ToNot(ContainSubstring("standby_mode"))
+			Expect(string(content)).To(ContainSubstring("primary_conninfo = 'host=cluster-example-rw'"))
+			Expect(filepath.Join(pgData, "recovery.conf")).ToNot(BeAnExistingFile())
+		})
+
+		It("prepares the replication without making the instance a standby", func() {
+			changed, err := config.PrepareReplication("host=cluster-example-rw")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(config.IsStandby()).To(BeFalse())
+			Expect(os.ReadFile(filepath.Join(pgData, "postgresql.auto.conf"))).To(
+				ContainSubstring("primary_conninfo = 'host=cluster-example-rw'"))
+		})
+
+		It("writes a targeted recovery with recovery.signal", func() {
+			autoConf := filepath.Join(pgData, "postgresql.auto.conf")
+			Expect(os.WriteFile(autoConf, []byte("work_mem = '1GB'\n"), 0o600)).To(Succeed())
+
+			customConf := filepath.Join(pgData, "custom.conf")
+			Expect(os.WriteFile(customConf, []byte("shared_buffers = '1GB'\n"), 0o600)).To(Succeed())
+			Expect(config.ConfigureTargetedRecovery("recovery_target_action = promote\n", customConf)).To(Succeed())
+			Expect(os.ReadFile(customConf)).To(
+				BeEquivalentTo("shared_buffers = '1GB'\n\nrecovery_target_action = promote\n"))
+			Expect(os.ReadFile(autoConf)).To(BeEmpty())
+			Expect(filepath.Join(pgData, "recovery.signal")).To(BeAnExistingFile())
+			Expect(filepath.Join(pgData, "recovery.conf")).ToNot(BeAnExistingFile())
+		})
+
+		It("clears the recovery settings, keeping the other ones", func() {
+			autoConf := filepath.Join(pgData, "postgresql.auto.conf")
+			Expect(os.WriteFile(autoConf, []byte("work_mem = '1GB'\n"), 0o600)).To(Succeed())
+			_, err := config.ConfigureStandby("host=cluster-example-rw")
+			Expect(err).ToNot(HaveOccurred())
+			_, err = config.SetOption("primary_slot_name", "_cnpg_cluster_example_1")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(os.WriteFile(filepath.Join(pgData, "recovery.signal"), nil, 0o600)).To(Succeed())
+
+			Expect(config.Clear()).To(Succeed())
+			Expect(config.IsStandby()).To(BeFalse())
+			Expect(filepath.Join(pgData, "recovery.signal")).ToNot(BeAnExistingFile())
+			content, err := os.ReadFile(autoConf)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(content)).To(ContainSubstring("work_mem = '1GB'"))
+			Expect(string(content)).ToNot(ContainSubstring("primary_conninfo"))
+			Expect(string(content)).ToNot(ContainSubstring("primary_slot_name"))
+			Expect(string(content)).ToNot(ContainSubstring("restore_command"))
+			Expect(string(content)).ToNot(ContainSubstring("recovery_target_timeline"))
+		})
+
+		It("clears a data directory without recovery settings", func() {
+			Expect(config.Clear()).To(Succeed())
+			Expect(config.IsStandby()).To(BeFalse())
+		})
+	})
+})
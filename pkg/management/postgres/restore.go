@@ -26,9 +26,11 @@ import (
 	"os/exec"
 	"path"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v4"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
@@ -48,7 +50,6 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
-	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
 	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
@@ -91,6 +92,9 @@ func (info InitInfo) Restore(ctx context.Context) error {
 		info.ApplicationUser = cluster.GetApplicationDatabaseOwner()
 		info.ApplicationDatabase = cluster.GetApplicationDatabaseName()
 	}
+	if remapping := cluster.GetRecoveryRemapping(); remapping != nil {
+		info.RenamedRoles = remapping.Roles
+	}
 
 	// Before starting the restore we check if the archive destination is safe to use
 	// otherwise, we stop creating the cluster
@@ -99,13 +103,36 @@ func (info InitInfo) Restore(ctx context.Context) error {
 		return err
 	}
 
-	backup, env, err := info.loadBackup(ctx, typedClient, cluster)
-	if err != nil {
-		return err
-	}
+	var restoreCommand string
+	var env []string
+	pluginConfiguration := getRecoveryPluginConfiguration(cluster)
+	switch {
+	case cluster.Spec.Bootstrap.Recovery.VolumeSnapshots != nil:
+		restoreCommand, env, err = info.prepareDataDirFromSnapshots(ctx, typedClient, cluster)
+		if err != nil {
+			return err
+		}
+	case pluginConfiguration != nil:
+		env = os.Environ()
+		restoreCommand, err = info.restoreDataDirWithPlugin(ctx, cluster, pluginConfiguration)
+		if err != nil {
+			return err
+		}
+	default:
+		var backup *apiv1.Backup
+		backup, env, err = info.loadBackup(ctx, typedClient, cluster)
+		if err != nil {
+			return err
+		}
 
-	if err := info.restoreDataDir(backup, env); err != nil {
-		return err
+		if err := info.restoreDataDir(ctx, cluster, backup, env); err != nil {
+			return err
+		}
+
+		restoreCommand, err = getBarmanRestoreCommand(backup)
+		if err != nil {
+			return err
+		}
 	}
 
 	if _, err := info.restoreCustomWalDir(ctx); err != nil {
@@ -136,8 +163,12 @@ func (info InitInfo) Restore(ctx context.Context) error {
 		return err
 	}
 
-	if err := info.writeRestoreWalConfig(backup, cluster); err != nil {
-		return err
+	// Without a WAL archive, the volume snapshots are recovered
+	// replaying only the WAL files they contain
+	if restoreCommand != "" {
+		if err := info.writeRestoreWalConfig(restoreCommand, cluster); err != nil {
+			return err
+		}
 	}
 
 	return info.ConfigureInstanceAfterRestore(env)
@@ -179,8 +210,14 @@ func (info InitInfo) restoreCustomWalDir(ctx context.Context) (bool, error) {
 	return true, os.Symlink(info.PgWal, pgDataWal)
 }
 
-// restoreDataDir restores PGDATA from an existing backup
-func (info InitInfo) restoreDataDir(backup *apiv1.Backup, env []string) error {
+// restoreDataDir restores PGDATA from an existing backup, reporting
+// the progress of the restore as events of the cluster
+func (info InitInfo) restoreDataDir(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	backup *apiv1.Backup,
+	env []string,
+) error {
 	var options []string
 
 	if backup.Status.EndpointURL != "" {
@@ -200,9 +237,35 @@ func (info InitInfo) restoreDataDir(backup *apiv1.Backup, env []string) error {
 	log.Info("Starting barman-cloud-restore",
 		"options", options)
 
+	var totalBytes int64
+	if backup.Status.Progress != nil {
+		totalBytes = backup.Status.Progress.BytesCopied
+	}
+	bytesPerSecond, err := cluster.Spec.Bootstrap.Recovery.MaxRate.GetBytesPerSecond()
+	if err != nil {
+		return err
+	}
+
+	stopTracking := trackDirectoryCopy(ctx, info.PgData, totalBytes, newRestoreProgressReporter(cluster))
+
 	cmd := exec.Command(barmanCapabilities.BarmanCloudRestore, options...) // #nosec G204
 	cmd.Env = env
-	err = execlog.RunStreaming(cmd, barmanCapabilities.BarmanCloudRestore)
+	streamingCmd, err := execlog.RunStreamingNoWait(cmd, barmanCapabilities.BarmanCloudRestore)
+	if err != nil {
+		stopTracking()
+		return err
+	}
+
+	stopThrottling := func() {}
+	if bytesPerSecond != 0 {
+		log.Info("Limiting the transfer rate of the restore",
+			"maxRate", cluster.Spec.Bootstrap.Recovery.MaxRate)
+		stopThrottling = throttleDirectoryCopy(ctx, cmd.Process, info.PgData, bytesPerSecond)
+	}
+
+	err = streamingCmd.Wait()
+	stopThrottling()
+	stopTracking()
 	if err != nil {
 		log.Error(err, "Can't restore backup")
 		return err
@@ -290,6 +353,16 @@ func (info InitInfo) loadBackupObjectFromExternalCluster(
 
 	log.Info("Target backup found", "backup", targetBackup)
 
+	return newBackupFromCatalog(server.BarmanObjectStore, serverName, targetBackup), env, nil
+}
+
+// newBackupFromCatalog generates an in-memory Backup structure given a
+// backup found in the catalog of an object store
+func newBackupFromCatalog(
+	configuration *apiv1.BarmanObjectStoreConfiguration,
+	serverName string,
+	targetBackup *catalog.BarmanBackup,
+) *apiv1.Backup {
 	return &apiv1.Backup{
 		Spec: apiv1.BackupSpec{
 			Cluster: apiv1.LocalObjectReference{
@@ -297,10 +370,10 @@ func (info InitInfo) loadBackupObjectFromExternalCluster(
 			},
 		},
 		Status: apiv1.BackupStatus{
-			BarmanCredentials: server.BarmanObjectStore.BarmanCredentials,
-			EndpointCA:        server.BarmanObjectStore.EndpointCA,
-			EndpointURL:       server.BarmanObjectStore.EndpointURL,
-			DestinationPath:   server.BarmanObjectStore.DestinationPath,
+			BarmanCredentials: configuration.BarmanCredentials,
+			EndpointCA:        configuration.EndpointCA,
+			EndpointURL:       configuration.EndpointURL,
+			DestinationPath:   configuration.DestinationPath,
 			ServerName:        serverName,
 			BackupID:          targetBackup.ID,
 			Phase:             apiv1.BackupPhaseCompleted,
@@ -314,7 +387,7 @@ func (info InitInfo) loadBackupObjectFromExternalCluster(
 			CommandOutput:     "",
 			CommandError:      "",
 		},
-	}, env, nil
+	}
 }
 
 // loadBackupFromReference loads a backup object and the required credentials given the backup object resource
@@ -332,6 +405,8 @@ func (info InitInfo) loadBackupFromReference(
 		return nil, nil, err
 	}
 
+	cluster.GetRecoveryRemapping().ApplyToBackup(&backup)
+
 	env, err := barmanCredentials.EnvSetRestoreCloudCredentials(
 		ctx,
 		typedClient,
@@ -352,17 +427,9 @@ func (info InitInfo) loadBackupFromReference(
 	return &backup, env, nil
 }
 
-// writeRestoreWalConfig writes a `custom.conf` allowing PostgreSQL
-// to complete the WAL recovery from the object storage and then start
-// as a new primary
-func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.Cluster) error {
-	// Ensure restore_command is used to correctly recover WALs
-	// from the object storage
-	major, err := postgresutils.GetMajorVersion(info.PgData)
-	if err != nil {
-		return fmt.Errorf("cannot detect major version: %w", err)
-	}
-
+// getBarmanRestoreCommand gets the restore_command used to recover
+// the WAL files of a backup from the object storage
+func getBarmanRestoreCommand(backup *apiv1.Backup) (string, error) {
 	const barmanCloudWalRestoreName = "barman-cloud-wal-restore"
 
 	cmd := []string{barmanCloudWalRestoreName}
@@ -372,18 +439,29 @@ func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.
 	cmd = append(cmd, backup.Status.DestinationPath)
 	cmd = append(cmd, backup.Status.ServerName)
 
-	cmd, err = barman.AppendCloudProviderOptionsFromBackup(cmd, backup)
+	cmd, err := barman.AppendCloudProviderOptionsFromBackup(cmd, backup)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	cmd = append(cmd, "%f", "%p")
+	return strings.Join(cmd, " "), nil
+}
+
+// writeRestoreWalConfig writes a `custom.conf` allowing PostgreSQL
+// to complete the WAL recovery using the passed restore_command
+// and then start as a new primary
+func (info InitInfo) writeRestoreWalConfig(restoreCommand string, cluster *apiv1.Cluster) error {
+	recoveryConfiguration, err := NewRecoveryConfiguration(info.PgData)
+	if err != nil {
+		return err
+	}
 
 	recoveryFileContents := fmt.Sprintf(
 		"recovery_target_action = promote\n"+
 			"restore_command = '%s'\n"+
 			"%s",
-		strings.Join(cmd, " "),
+		restoreCommand,
 		cluster.Spec.Bootstrap.Recovery.RecoveryTarget.BuildPostgresOptions())
 
 	log.Info("Generated recovery configuration", "configuration", recoveryFileContents)
@@ -412,36 +490,9 @@ func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.
 		}
 	}
 
-	if major >= 12 {
-		// Append restore_command to the end of the
-		// custom configs file
-		err = fileutils.AppendStringToFile(
-			path.Join(info.PgData, constants.PostgresqlCustomConfigurationFile),
-			recoveryFileContents)
-		if err != nil {
-			return fmt.Errorf("cannot write recovery config: %w", err)
-		}
-
-		err = os.WriteFile(
-			path.Join(info.PgData, "postgresql.auto.conf"),
-			[]byte(""),
-			0o600)
-		if err != nil {
-			return fmt.Errorf("cannot erase auto config: %w", err)
-		}
-
-		// Create recovery signal file
-		return os.WriteFile(
-			path.Join(info.PgData, "recovery.signal"),
-			[]byte(""),
-			0o600)
-	}
-
-	// We need to generate a recovery.conf
-	return os.WriteFile(
-		path.Join(info.PgData, "recovery.conf"),
-		[]byte(recoveryFileContents),
-		0o600)
+	return recoveryConfiguration.ConfigureTargetedRecovery(
+		recoveryFileContents,
+		path.Join(info.PgData, constants.PostgresqlCustomConfigurationFile))
 }
 
 // GetEnforcedParametersThroughPgControldata will parse the output of pg_controldata in order to get
@@ -534,6 +585,15 @@ func (info InitInfo) WriteInitialPostgresqlConf(cluster *apiv1.Cluster) error {
 		return fmt.Errorf("while creating custom.conf: %w", err)
 	}
 
+	// The directory of the user-provided configuration files is
+	// included by custom.conf, and it will be filled by the instance
+	// manager
+	err = fileutils.EnsureDirectoryExist(
+		path.Join(info.PgData, constants.PostgresqlCustomConfigurationDirectory))
+	if err != nil {
+		return fmt.Errorf("while creating %s: %w", constants.PostgresqlCustomConfigurationDirectory, err)
+	}
+
 	err = fileutils.CopyFile(
 		path.Join(temporaryInitInfo.PgData, "postgresql.auto.conf"),
 		path.Join(info.PgData, "postgresql.auto.conf"))
@@ -576,9 +636,9 @@ func (info InitInfo) ConfigureInstanceAfterRestore(env []string) error {
 	instance := info.GetInstance()
 	instance.Env = env
 
-	majorVersion, err := postgresutils.GetMajorVersion(info.PgData)
+	recoveryConfiguration, err := NewRecoveryConfiguration(info.PgData)
 	if err != nil {
-		return fmt.Errorf("cannot detect major version: %w", err)
+		return err
 	}
 
 	// This will start the recovery of WALs taken during the backup
@@ -595,17 +655,14 @@ func (info InitInfo) ConfigureInstanceAfterRestore(env []string) error {
 			return fmt.Errorf("while waiting for PostgreSQL to stop recovery mode: %w", err)
 		}
 
-		return nil
+		return renameRoles(db, info.RenamedRoles)
 	}); err != nil {
 		return err
 	}
 
-	if majorVersion >= 12 {
-		primaryConnInfo := buildPrimaryConnInfo(info.ClusterName, info.PodName)
-		_, err = configurePostgresAutoConfFile(info.PgData, primaryConnInfo)
-		if err != nil {
-			return fmt.Errorf("while configuring replica: %w", err)
-		}
+	primaryConnInfo := buildPrimaryConnInfo(info.ClusterName, info.PodName)
+	if _, err = recoveryConfiguration.PrepareReplication(primaryConnInfo); err != nil {
+		return fmt.Errorf("while configuring replica: %w", err)
 	}
 
 	if info.ApplicationUser == "" || info.ApplicationDatabase == "" {
@@ -667,6 +724,38 @@ func (info *InitInfo) checkBackupDestination(
 	return nil
 }
 
+// renameRoles renames the roles of the restored instance as requested
+// by the remapping of the recovery, skipping the ones not existing
+func renameRoles(db *sql.DB, roles map[string]string) error {
+	names := make([]string, 0, len(roles))
+	for name := range roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		var exists bool
+		row := db.QueryRow("SELECT COUNT(*) > 0 FROM pg_catalog.pg_roles WHERE rolname = $1", name)
+		if err := row.Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			log.Info("Role to be renamed not found, skipping it", "role", name)
+			continue
+		}
+
+		log.Info("Renaming role", "role", name, "newName", roles[name])
+		if _, err := db.Exec(fmt.Sprintf(
+			"ALTER ROLE %s RENAME TO %s",
+			pgx.Identifier{name}.Sanitize(),
+			pgx.Identifier{roles[name]}.Sanitize())); err != nil {
+			return fmt.Errorf("while renaming role %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
 // waitUntilRecoveryFinishes periodically checks the underlying
 // PostgreSQL connection and returns only when the recovery
 // mode is finished
@@ -0,0 +1,77 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/plugin"
+)
+
+// getRecoveryPluginConfiguration gets the configuration of the plugin
+// restoring the backup of the recovery source, or nil when the
+// backup is to be restored from an object store
+func getRecoveryPluginConfiguration(cluster *apiv1.Cluster) *apiv1.PluginConfiguration {
+	recovery := cluster.Spec.Bootstrap.Recovery
+	if recovery.Backup != nil || recovery.Source == "" {
+		return nil
+	}
+
+	server, found := cluster.ExternalCluster(recovery.Source)
+	if !found {
+		return nil
+	}
+
+	return server.PluginConfiguration
+}
+
+// restoreDataDirWithPlugin restores the data directory using a plugin
+// running as a sidecar, returning the restore_command to be used to
+// recover the WAL files
+func (info InitInfo) restoreDataDirWithPlugin(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pluginConfiguration *apiv1.PluginConfiguration,
+) (string, error) {
+	log.Info("Starting the restore with plugin", "plugin", pluginConfiguration.Name)
+
+	pluginClient, err := plugin.DialLocal(ctx, pluginConfiguration.Name)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = pluginClient.Close()
+	}()
+
+	result, err := pluginClient.Restore(ctx, &plugin.RestoreRequest{
+		Cluster:    cluster,
+		Parameters: pluginConfiguration.Parameters,
+		PgData:     info.PgData,
+	})
+	if err != nil {
+		return "", fmt.Errorf("while restoring with plugin %s: %w", pluginConfiguration.Name, err)
+	}
+	if result.RestoreCommand == "" {
+		return "", fmt.Errorf("plugin %s didn't return the restore_command", pluginConfiguration.Name)
+	}
+
+	log.Info("Restore completed", "plugin", pluginConfiguration.Name)
+	return result.RestoreCommand, nil
+}
@@ -0,0 +1,121 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// prepareDataDirFromSnapshots checks the data directory cloned from
+// the volume snapshots, returning the restore_command to be used to
+// replay the WAL files from the archive of the recovery source, or
+// an empty string when there's no source
+func (info InitInfo) prepareDataDirFromSnapshots(
+	ctx context.Context,
+	typedClient client.Client,
+	cluster *apiv1.Cluster,
+) (string, []string, error) {
+	volumeSnapshots := cluster.Spec.Bootstrap.Recovery.VolumeSnapshots
+	log.Info("Recovering from volume snapshots",
+		"storage", volumeSnapshots.Storage.Name,
+		"walStorage", volumeSnapshots.WalStorage)
+
+	// The storage of an instance with a WAL storage only contains a
+	// symbolic link to it, which is dangling without its snapshot
+	if volumeSnapshots.WalStorage == nil {
+		if target, err := os.Readlink(path.Join(info.PgData, "pg_wal")); err == nil {
+			return "", nil, fmt.Errorf(
+				"the pg_wal directory of the volume snapshot %s is a symbolic link to %s: "+
+					"the volume snapshot of the WAL storage is required",
+				volumeSnapshots.Storage.Name, target)
+		}
+	}
+
+	// The WAL files are moved to the WAL storage when the
+	// snapshot of the data directory contains them
+	if _, err := info.restoreCustomWalDir(ctx); err != nil {
+		return "", nil, err
+	}
+
+	if err := info.checkAdoptablePgData(); err != nil {
+		return "", nil, fmt.Errorf("while checking the data directory cloned from the volume snapshots: %w", err)
+	}
+
+	// The snapshots may have been taken while PostgreSQL was running
+	if err := info.GetInstance().CleanUpStalePid(); err != nil {
+		return "", nil, fmt.Errorf("while removing the stale PID file: %w", err)
+	}
+
+	// The snapshots may have been taken from a standby, whose recovery
+	// settings would make the new instance follow the original primary
+	recoveryConfiguration, err := NewRecoveryConfiguration(info.PgData)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := recoveryConfiguration.Clear(); err != nil {
+		return "", nil, fmt.Errorf("while removing the recovery settings of the volume snapshots: %w", err)
+	}
+
+	// The designated primary of a replica cluster follows its source,
+	// restoring the WAL files from its archive when needed
+	sourceName := cluster.Spec.Bootstrap.Recovery.Source
+	if sourceName == "" || cluster.IsReplica() {
+		return "", os.Environ(), nil
+	}
+
+	server, found := cluster.ExternalCluster(sourceName)
+	if !found {
+		return "", nil, fmt.Errorf("missing external cluster: %v", sourceName)
+	}
+	if server.BarmanObjectStore == nil {
+		return "", nil, fmt.Errorf("missing barmanObjectStore in external cluster: %v", sourceName)
+	}
+
+	env, err := barmanCredentials.EnvSetRestoreCloudCredentials(
+		ctx,
+		typedClient,
+		cluster.Namespace,
+		server.BarmanObjectStore,
+		os.Environ())
+	if err != nil {
+		return "", nil, err
+	}
+
+	restoreCommand, err := getBarmanRestoreCommand(&apiv1.Backup{
+		Status: apiv1.BackupStatus{
+			BarmanCredentials: server.BarmanObjectStore.BarmanCredentials,
+			EndpointCA:        server.BarmanObjectStore.EndpointCA,
+			EndpointURL:       server.BarmanObjectStore.EndpointURL,
+			DestinationPath:   server.BarmanObjectStore.DestinationPath,
+			ServerName:        server.GetServerName(),
+		},
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return restoreCommand, env, nil
+}
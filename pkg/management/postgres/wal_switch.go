@@ -0,0 +1,134 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"k8s.io/client-go/util/retry"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+)
+
+// fencedHBARules are the HBA rules installed by FenceWrites: only the local
+// connections, used by the instance manager, and the streaming replication
+// connections, needed by the standbys to receive the last WAL records,
+// are allowed
+const fencedHBARules = `
+local all all peer map=local
+hostssl replication streaming_replica all cert
+`
+
+// FenceWrites prevents the instance from accepting new writes, rejecting
+// every new client connection and terminating the current ones.
+// It is meant to be used only before shutting down a primary, as the HBA
+// rules are regenerated by the instance manager when the instance restarts
+func (instance *Instance) FenceWrites() error {
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	var loadTime time.Time
+	if err := db.QueryRow("SELECT pg_conf_load_time()").Scan(&loadTime); err != nil {
+		return err
+	}
+
+	if _, err := InstallPgDataFileContent(
+		instance.PgData,
+		fencedHBARules,
+		constants.PostgresqlHBARulesFile,
+	); err != nil {
+		return fmt.Errorf("while rejecting the client connections: %w", err)
+	}
+
+	if err := instance.Reload(); err != nil {
+		return err
+	}
+
+	// The new connections must be rejected
+	// before terminating the current ones
+	err = retry.OnError(retry.DefaultRetry, func(err error) bool { return err != nil }, func() error {
+		var reloaded bool
+		if err := db.QueryRow("SELECT pg_conf_load_time() > $1", loadTime).Scan(&reloaded); err != nil {
+			return err
+		}
+		if !reloaded {
+			return fmt.Errorf("configuration not yet reloaded")
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("while waiting for the client connections to be rejected: %w", err)
+	}
+
+	// The connections of the instance manager are kept, as they
+	// are needed to complete the shutdown
+	_, err = db.Exec(
+		"SELECT pg_terminate_backend(pid) FROM pg_stat_activity "+
+			"WHERE backend_type = 'client backend' AND application_name <> $1",
+		instanceManagerApplicationName)
+	if err != nil {
+		return fmt.Errorf("while terminating the client connections: %w", err)
+	}
+
+	return nil
+}
+
+// SwitchAndArchiveWAL switches the current WAL file, and waits up to the
+// passed timeout for it to be archived. Returns the LSN at which the WAL
+// file has been switched, and whether it has been archived in time
+func (instance *Instance) SwitchAndArchiveWAL(timeout time.Duration) (lsn string, archived bool, err error) {
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return "", false, err
+	}
+
+	var walName string
+	row := db.QueryRow("SELECT lsn::text, pg_walfile_name(lsn) FROM pg_switch_wal() lsn")
+	if err := row.Scan(&lsn, &walName); err != nil {
+		return "", false, fmt.Errorf("while switching the WAL file: %w", err)
+	}
+
+	log.Info("Waiting for the last WAL file to be archived",
+		"lsn", lsn,
+		"walName", walName,
+		"timeout", timeout)
+
+	readyFile := filepath.Join(instance.PgData, "pg_wal", "archive_status", walName+".ready")
+	timeLimit := time.Now().Add(timeout)
+	for {
+		// The ".ready" file is removed when the WAL file has been archived
+		ready, err := fileutils.FileExists(readyFile)
+		if err != nil {
+			return lsn, false, err
+		}
+		if !ready {
+			return lsn, true, nil
+		}
+
+		if time.Now().After(timeLimit) {
+			return lsn, false, nil
+		}
+
+		time.Sleep(1 * time.Second)
+	}
+}
@@ -0,0 +1,64 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// RestartWALReceiver terminates the WAL receiver process, if it is running.
+// The startup process will spawn a new one, connecting to the server
+// specified in primary_conninfo, without restarting the instance.
+// Returns true if a WAL receiver has been terminated
+func (instance *Instance) RestartWALReceiver() (bool, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return false, err
+	}
+
+	var pid sql.NullInt32
+	row := superUserDB.QueryRow("SELECT pid FROM pg_stat_wal_receiver")
+	err = row.Scan(&pid)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !pid.Valid) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	// The WAL receiver is an auxiliary process, and pg_terminate_backend
+	// doesn't work against it on every supported PostgreSQL version.
+	// Being it a child of the postmaster running in this container,
+	// we can signal it directly
+	process, err := os.FindProcess(int(pid.Int32))
+	if err != nil {
+		return false, err
+	}
+
+	log.Info("Restarting the WAL receiver", "pid", pid.Int32)
+	if err := process.Signal(syscall.SIGTERM); err != nil {
+		return false, fmt.Errorf("while terminating the WAL receiver: %w", err)
+	}
+
+	return true, nil
+}
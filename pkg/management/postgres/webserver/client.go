@@ -0,0 +1,221 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"os"
+	"strconv"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// PhysicalBackupStream is the tarball of a hot physical backup
+// streamed by an instance manager
+type PhysicalBackupStream struct {
+	response *http.Response
+}
+
+// Read reads the content of the tarball
+func (stream *PhysicalBackupStream) Read(p []byte) (int, error) {
+	return stream.response.Body.Read(p)
+}
+
+// Close closes the stream, aborting the backup if it is still running
+func (stream *PhysicalBackupStream) Close() error {
+	return stream.response.Body.Close()
+}
+
+// Result returns the WAL positions of the backup. They are available
+// only after the whole tarball has been read
+func (stream *PhysicalBackupStream) Result() (*postgres.PhysicalBackupResult, error) {
+	result := &postgres.PhysicalBackupResult{
+		BeginLSN: stream.response.Trailer.Get(url.PhysicalBackupBeginLSNTrailer),
+		EndLSN:   stream.response.Trailer.Get(url.PhysicalBackupEndLSNTrailer),
+		BeginWAL: stream.response.Trailer.Get(url.PhysicalBackupBeginWALTrailer),
+	}
+	if result.BeginLSN == "" || result.EndLSN == "" || result.BeginWAL == "" {
+		return nil, fmt.Errorf("the physical backup is not completed")
+	}
+
+	return result, nil
+}
+
+// RequestPhysicalBackup requests a hot physical backup to the instance
+// manager running in the same Pod, using its local webserver. The caller
+// is responsible for closing the returned stream
+func RequestPhysicalBackup(
+	ctx context.Context,
+	client *http.Client,
+	options postgres.PhysicalBackupOptions,
+) (*PhysicalBackupStream, error) {
+	return requestPhysicalBackup(
+		ctx,
+		client,
+		url.Local(url.PathPgPhysicalBackup, url.LocalPort),
+		options)
+}
+
+// ClientCredentials are the files containing the credentials used
+// to connect to the secure webserver of another instance
+type ClientCredentials struct {
+	// CertificateFile is the client certificate of the streaming
+	// replication user
+	CertificateFile string
+
+	// KeyFile is the private key of the client certificate
+	KeyFile string
+
+	// ServerCAFile is the CA verifying the certificate of the instance
+	ServerCAFile string
+}
+
+// NewClient creates an HTTP client for the secure webserver of the
+// instance managers using the credentials stored in the files
+func (credentials ClientCredentials) NewClient() (*http.Client, error) {
+	certificate, err := tls.LoadX509KeyPair(credentials.CertificateFile, credentials.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("while loading the client certificate: %w", err)
+	}
+
+	serverCA, err := os.ReadFile(credentials.ServerCAFile) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("while reading the server CA: %w", err)
+	}
+
+	return NewSecureClient(certificate, serverCA), nil
+}
+
+// NewSecureClient creates an HTTP client for the secure webserver of the
+// instance managers, authenticating with the passed certificate of the
+// streaming replication user and verifying the certificate of the
+// instances against the passed PEM encoded server CA
+func NewSecureClient(certificate tls.Certificate, serverCA []byte) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{certificate},
+		// The instances are reached by IP address, which is not in the
+		// server certificate: the chain is verified against the server
+		// CA below, like the `verify-ca` SSL mode of PostgreSQL does
+		InsecureSkipVerify: true, // #nosec G402
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			_, err := verifyCertificateChain(rawCerts, serverCA, x509.ExtKeyUsageServerAuth)
+			return err
+		},
+	}
+
+	return &http.Client{Transport: transport}
+}
+
+// RequestRemotePhysicalBackup requests a hot physical backup to the
+// instance manager running on the passed host, authenticating with the
+// certificate of the streaming replication user. The caller is
+// responsible for closing the returned stream
+func RequestRemotePhysicalBackup(
+	ctx context.Context,
+	host string,
+	credentials ClientCredentials,
+	options postgres.PhysicalBackupOptions,
+) (*PhysicalBackupStream, error) {
+	client, err := credentials.NewClient()
+	if err != nil {
+		return nil, err
+	}
+
+	return requestPhysicalBackup(
+		ctx,
+		client,
+		url.BuildSecure(host, url.PathPgPhysicalBackup, url.SecurePort),
+		options)
+}
+
+// RequestReload requests the instance manager running on the passed
+// host to reload the configuration of PostgreSQL
+func RequestReload(ctx context.Context, client *http.Client, host string) error {
+	return requestControl(ctx, client, url.BuildSecure(host, url.PathPgReload, url.SecurePort))
+}
+
+// RequestRestart requests the instance manager running on the passed
+// host to restart PostgreSQL, waiting for it to accept connections again
+func RequestRestart(ctx context.Context, client *http.Client, host string) error {
+	return requestControl(ctx, client, url.BuildSecure(host, url.PathPgRestart, url.SecurePort))
+}
+
+// requestControl posts a request controlling PostgreSQL to the passed URL
+func requestControl(ctx context.Context, client *http.Client, controlURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, controlURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return nil
+}
+
+func requestPhysicalBackup(
+	ctx context.Context,
+	client *http.Client,
+	backupURL string,
+	options postgres.PhysicalBackupOptions,
+) (*PhysicalBackupStream, error) {
+	query := neturl.Values{}
+	if options.Label != "" {
+		query.Set(url.PhysicalBackupLabelParameter, options.Label)
+	}
+	query.Set(url.PhysicalBackupFastParameter, strconv.FormatBool(options.Fast))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, backupURL+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("physical backup request failed with status %d: %s", resp.StatusCode, string(body))
+	}
+
+	return &PhysicalBackupStream{response: resp}, nil
+}
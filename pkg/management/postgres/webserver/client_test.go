@@ -0,0 +1,101 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Physical backup client", func() {
+	It("reads the tarball and the WAL positions of the backup", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Query().Get(url.PhysicalBackupLabelParameter)).To(Equal("test"))
+			Expect(r.URL.Query().Get(url.PhysicalBackupFastParameter)).To(Equal("true"))
+			w.Header().Set("Trailer", fmt.Sprintf("%s, %s, %s",
+				url.PhysicalBackupBeginLSNTrailer,
+				url.PhysicalBackupEndLSNTrailer,
+				url.PhysicalBackupBeginWALTrailer))
+			_, _ = fmt.Fprint(w, "tarball")
+			w.Header().Set(url.PhysicalBackupBeginLSNTrailer, "0/2000028")
+			w.Header().Set(url.PhysicalBackupEndLSNTrailer, "0/2000100")
+			w.Header().Set(url.PhysicalBackupBeginWALTrailer, "000000010000000000000002")
+		}))
+		defer server.Close()
+
+		stream, err := requestPhysicalBackup(context.TODO(), server.Client(), server.URL,
+			postgres.PhysicalBackupOptions{Label: "test", Fast: true})
+		Expect(err).ToNot(HaveOccurred())
+		defer func() {
+			_ = stream.Close()
+		}()
+
+		_, err = stream.Result()
+		Expect(err).To(HaveOccurred())
+
+		content, err := io.ReadAll(stream)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(Equal("tarball"))
+		Expect(stream.Result()).To(Equal(&postgres.PhysicalBackupResult{
+			BeginLSN: "0/2000028",
+			EndLSN:   "0/2000100",
+			BeginWAL: "000000010000000000000002",
+		}))
+	})
+
+	It("reports the errors raised before the backup started", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "a physical backup is already in progress", http.StatusConflict)
+		}))
+		defer server.Close()
+
+		_, err := requestPhysicalBackup(context.TODO(), server.Client(), server.URL,
+			postgres.PhysicalBackupOptions{})
+		Expect(err).To(MatchError(ContainSubstring("status 409")))
+	})
+})
+
+var _ = Describe("Control client", func() {
+	It("posts the request to the instance manager", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Method).To(Equal(http.MethodPost))
+			_, _ = fmt.Fprint(w, "OK")
+		}))
+		defer server.Close()
+
+		Expect(requestControl(context.TODO(), server.Client(), server.URL)).To(Succeed())
+	})
+
+	It("reports the errors of the instance manager", func() {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "Instance is fenced", http.StatusConflict)
+		}))
+		defer server.Close()
+
+		err := requestControl(context.TODO(), server.Client(), server.URL)
+		Expect(err).To(MatchError(ContainSubstring("Instance is fenced")))
+	})
+})
@@ -61,6 +61,7 @@ func NewLocalWebServer(instance *postgres.Instance) (*Webserver, error) {
 	serveMux := http.NewServeMux()
 	serveMux.HandleFunc(url.PathCache, endpoints.serveCache)
 	serveMux.HandleFunc(url.PathPgBackup, endpoints.requestBackup)
+	serveMux.HandleFunc(url.PathPgPhysicalBackup, physicalBackupHandler(instance))
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf("localhost:%d", url.LocalPort),
@@ -81,8 +82,8 @@ func (ws *localWebserverEndpoints) serveCache(w http.ResponseWriter, r *http.Req
 	log.Debug("Cached object request received")
 
 	var js []byte
-	switch requestedObject {
-	case cache.ClusterKey:
+	switch {
+	case requestedObject == cache.ClusterKey:
 		response, err := cache.LoadCluster()
 		if errors.Is(err, cache.ErrCacheMiss) {
 			w.WriteHeader(http.StatusNotFound)
@@ -99,7 +100,7 @@ func (ws *localWebserverEndpoints) serveCache(w http.ResponseWriter, r *http.Req
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
-	case cache.WALRestoreKey, cache.WALArchiveKey:
+	case cache.IsEnvKey(requestedObject):
 		response, err := cache.LoadEnv(requestedObject)
 		if errors.Is(err, cache.ErrCacheMiss) {
 			w.WriteHeader(http.StatusNotFound)
@@ -116,6 +117,23 @@ func (ws *localWebserverEndpoints) serveCache(w http.ResponseWriter, r *http.Req
 			w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+	case requestedObject == cache.WALPluginKey:
+		response, err := cache.LoadWALPlugin()
+		if errors.Is(err, cache.ErrCacheMiss) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		} else if err != nil {
+			log.Error(err, "while loading cached WAL plugin")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		js, err = json.Marshal(response)
+		if err != nil {
+			log.Error(err, "while unmarshalling cached WAL plugin")
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
 	default:
 		log.Debug("Unsupported cached object type")
 		w.WriteHeader(http.StatusNotFound)
@@ -161,7 +179,8 @@ func (ws *localWebserverEndpoints) requestBackup(w http.ResponseWriter, r *http.
 		return
 	}
 
-	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+	if backup.Spec.GetMethod() == apiv1.BackupMethodBarmanObjectStore &&
+		(cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil) {
 		http.Error(w, "Backup not configured in the cluster", http.StatusConflict)
 		return
 	}
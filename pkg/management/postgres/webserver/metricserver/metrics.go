@@ -17,25 +17,59 @@ limitations under the License.
 package metricserver
 
 import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"sync"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	postgresconf "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
+// SecurityConfiguration is the TLS and authentication
+// configuration of the metrics server
+type SecurityConfiguration struct {
+	// TLS is true when the metrics are served over TLS,
+	// using the server certificate of the cluster
+	TLS bool
+
+	// RequireClientCertificate is true when the clients are required to
+	// present a certificate signed by the client CA of the cluster
+	RequireClientCertificate bool
+
+	// BearerToken is the token the clients are required to present,
+	// when not empty
+	BearerToken string
+}
+
 // MetricsServer exposes the metrics of the postgres instance
 type MetricsServer struct {
-	*webserver.Webserver
+	// handler serves the metrics
+	handler http.Handler
 
 	// exporter is the exporter for predefined queries and for
 	// custom ones
 	exporter *Exporter
+
+	mu         sync.Mutex
+	security   SecurityConfiguration
+	configured bool
+
+	// restart is notified when the server needs to be
+	// (re)started to apply a new TLS configuration
+	restart chan struct{}
 }
 
 // New configure the web statusServer for a certain PostgreSQL instance, and
@@ -52,16 +86,10 @@ func New(serverInstance *postgres.Instance) (*MetricsServer, error) {
 	serveMux := http.NewServeMux()
 	serveMux.Handle(url.PathMetrics, promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
 
-	server := &http.Server{
-		Addr:              fmt.Sprintf(":%d", url.PostgresMetricsPort),
-		Handler:           serveMux,
-		ReadTimeout:       webserver.DefaultReadTimeout,
-		ReadHeaderTimeout: webserver.DefaultReadHeaderTimeout,
-	}
-
 	metricServer := &MetricsServer{
-		Webserver: webserver.NewWebServer(serverInstance, server),
-		exporter:  exporter,
+		handler:  serveMux,
+		exporter: exporter,
+		restart:  make(chan struct{}, 1),
 	}
 
 	return metricServer, nil
@@ -72,3 +100,145 @@ func New(serverInstance *postgres.Instance) (*MetricsServer, error) {
 func (ms *MetricsServer) GetExporter() *Exporter {
 	return ms.exporter
 }
+
+// SetSecurityConfiguration applies the TLS and authentication configuration
+// to the metrics server, restarting it when the TLS configuration changed.
+// The server starts only after the first configuration is applied, so that
+// the metrics are never exposed without the required protection
+func (ms *MetricsServer) SetSecurityConfiguration(config SecurityConfiguration) {
+	ms.mu.Lock()
+	needsRestart := !ms.configured ||
+		config.TLS != ms.security.TLS ||
+		config.RequireClientCertificate != ms.security.RequireClientCertificate
+	ms.security = config
+	ms.configured = true
+	ms.mu.Unlock()
+
+	if needsRestart {
+		select {
+		case ms.restart <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// getSecurityConfiguration gets the current security configuration
+func (ms *MetricsServer) getSecurityConfiguration() SecurityConfiguration {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return ms.security
+}
+
+// Start implements the runnable interface
+func (ms *MetricsServer) Start(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return nil
+	case <-ms.restart:
+	}
+
+	for {
+		server := ms.newServer()
+		errChan := make(chan error, 1)
+		go func() {
+			log.Info("Starting webserver", "address", server.Addr, "tls", server.TLSConfig != nil)
+
+			var err error
+			if server.TLSConfig != nil {
+				err = server.ListenAndServeTLS("", "")
+			} else {
+				err = server.ListenAndServe()
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errChan <- err
+			}
+		}()
+
+		select {
+		case err := <-errChan:
+			log.Error(err, "Error while starting the web server", "address", server.Addr)
+			return err
+		case <-ms.restart:
+			log.Info("Restarting the webserver to apply the new TLS configuration", "address", server.Addr)
+			if err := server.Shutdown(context.Background()); err != nil {
+				log.Error(err, "Error while shutting down the web server", "address", server.Addr)
+				return err
+			}
+		case <-ctx.Done():
+			if err := server.Shutdown(context.Background()); err != nil {
+				log.Error(err, "Error while shutting down the web server", "address", server.Addr)
+				return err
+			}
+			log.Info("Webserver exited", "address", server.Addr)
+			return nil
+		}
+	}
+}
+
+// newServer creates the HTTP server using the current security configuration
+func (ms *MetricsServer) newServer() *http.Server {
+	security := ms.getSecurityConfiguration()
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", url.PostgresMetricsPort),
+		Handler:           ms.authenticate(ms.handler),
+		ReadTimeout:       webserver.DefaultReadTimeout,
+		ReadHeaderTimeout: webserver.DefaultReadHeaderTimeout,
+	}
+	if security.TLS {
+		server.TLSConfig = newTLSConfig(security.RequireClientCertificate)
+	}
+
+	return server
+}
+
+// authenticate checks the bearer token of the requests, when required
+func (ms *MetricsServer) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := ms.getSecurityConfiguration().BearerToken
+		if token != "" &&
+			subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// newTLSConfig creates the TLS configuration of the server. The certificates
+// are loaded at every connection, as they are refreshed by the instance
+// manager when they are renewed
+func newTLSConfig(requireClientCertificate bool) *tls.Config {
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			certificate, err := tls.LoadX509KeyPair(
+				postgresconf.ServerCertificateLocation, postgresconf.ServerKeyLocation)
+			return &certificate, err
+		},
+	}
+
+	if requireClientCertificate {
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+		config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			caCertificate, err := os.ReadFile(postgresconf.ClientCACertificateLocation)
+			if err != nil {
+				return nil, err
+			}
+
+			clientCAs := x509.NewCertPool()
+			if !clientCAs.AppendCertsFromPEM(caCertificate) {
+				return nil, fmt.Errorf("invalid client CA certificate in %s",
+					postgresconf.ClientCACertificateLocation)
+			}
+
+			clientConfig := config.Clone()
+			clientConfig.GetConfigForClient = nil
+			clientConfig.ClientCAs = clientCAs
+			return clientConfig, nil
+		}
+	}
+
+	return config
+}
@@ -27,7 +27,9 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	m "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/metrics"
@@ -41,6 +43,10 @@ const PrometheusNamespace = "cnpg"
 
 var synchronousStandbyNamesRegex = regexp.MustCompile(`ANY ([0-9]+) \(.*\)`)
 
+// pgStatStatementsTopQueries is the number of queries tracked by
+// pg_stat_statements exposed as metrics
+const pgStatStatementsTopQueries = 10
+
 // The wal_segment_size value in bytes
 var walSegmentSize *int
 
@@ -63,11 +69,58 @@ type metrics struct {
 	SyncReplicas             *prometheus.GaugeVec
 	ReplicaCluster           prometheus.Gauge
 	PgWALArchiveStatus       *prometheus.GaugeVec
+	PgWALArchivingFailing    prometheus.Gauge
 	PgWALDirectory           *prometheus.GaugeVec
+	DiskSpace                *prometheus.GaugeVec
+	ServerCertificateExpiry  prometheus.Gauge
+	DataCorruptions          prometheus.Gauge
+	LastDataVerification     prometheus.Gauge
 	PgVersion                *prometheus.GaugeVec
 	FirstRecoverabilityPoint prometheus.Gauge
+	LastSuccessfulBackup     prometheus.Gauge
+	LastFailedBackup         prometheus.Gauge
+	AdditionalObjectStores   AdditionalObjectStoresMetrics
 	FencingOn                prometheus.Gauge
+	InstanceLocation         *prometheus.GaugeVec
 	PgStatWalMetrics         PgStatWalMetrics
+	PgStatStatementsMetrics  PgStatStatementsMetrics
+	WalSenderMetrics         WalSenderMetrics
+	WalReceiverMetrics       WalReceiverMetrics
+}
+
+// AdditionalObjectStoresMetrics describes the additional object
+// stores of the cluster, as reported in the cluster status
+type AdditionalObjectStoresMetrics struct {
+	WALArchivingFailing  *prometheus.GaugeVec
+	LastSuccessfulBackup *prometheus.GaugeVec
+	LastFailedBackup     *prometheus.GaugeVec
+}
+
+// WalSenderMetrics describes the WAL senders of the primary,
+// one for each standby of the cluster
+type WalSenderMetrics struct {
+	Streaming   *prometheus.GaugeVec
+	Synchronous *prometheus.GaugeVec
+	SentLsn     *prometheus.GaugeVec
+	WriteLsn    *prometheus.GaugeVec
+	FlushLsn    *prometheus.GaugeVec
+	ReplayLsn   *prometheus.GaugeVec
+}
+
+// WalReceiverMetrics describes the WAL receiver of a standby
+type WalReceiverMetrics struct {
+	Streaming          prometheus.Gauge
+	ReceivedLsn        prometheus.Gauge
+	LatestEndLsn       prometheus.Gauge
+	LastMsgReceiptTime prometheus.Gauge
+}
+
+// PgStatStatementsMetrics is available when the pg_stat_statements
+// extension is installed
+type PgStatStatementsMetrics struct {
+	Calls         *prometheus.GaugeVec
+	TotalExecTime *prometheus.GaugeVec
+	Rows          *prometheus.GaugeVec
 }
 
 // PgStatWalMetrics is available from PG14+
@@ -149,6 +202,36 @@ func newMetrics() *metrics {
 			Help: fmt.Sprintf("Number of WAL segments in the '%s' directory (ready, done)",
 				specs.PgWalArchiveStatusPath),
 		}, []string{"value"}),
+		PgWALArchivingFailing: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "wal_archiving_failing",
+			Help:      "1 if the last WAL archiving attempt of the primary failed, 0 otherwise",
+		}),
+		DiskSpace: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "disk_space_bytes",
+			Help:      "Disk space of the volumes of the instance (data, wal) in bytes (total, available, used)",
+		}, []string{"volume", "value"}),
+		ServerCertificateExpiry: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "server_certificate_expiration_timestamp",
+			Help:      "The expiration time of the server certificate as a unix timestamp",
+		}),
+		DataCorruptions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "data_corruptions",
+			Help:      "Number of corruptions found by the last verification of the data of the instance",
+		}),
+		LastDataVerification: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "last_data_verification_timestamp",
+			Help:      "The last verification of the data of the instance as a unix timestamp",
+		}),
 		PgVersion: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 			Namespace: PrometheusNamespace,
 			Subsystem: subsystem,
@@ -169,12 +252,50 @@ func newMetrics() *metrics {
 			Name:      "first_recoverability_point",
 			Help:      "The first point of recoverability for the cluster as a unix timestamp",
 		}),
+		LastSuccessfulBackup: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "last_successful_backup_timestamp",
+			Help:      "The last successful backup, as reported by lastSuccessfulBackup in the cluster status, as a unix timestamp",
+		}),
+		LastFailedBackup: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "last_failed_backup_timestamp",
+			Help:      "The last failed backup as a unix timestamp",
+		}),
+		AdditionalObjectStores: AdditionalObjectStoresMetrics{
+			WALArchivingFailing: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "additional_object_store_wal_archiving_failing",
+				Help:      "1 if the last WAL file couldn't be archived in the additional object store, 0 otherwise",
+			}, []string{"name"}),
+			LastSuccessfulBackup: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "additional_object_store_last_successful_backup_timestamp",
+				Help:      "The last backup uploaded to the additional object store as a unix timestamp",
+			}, []string{"name"}),
+			LastFailedBackup: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "additional_object_store_last_failed_backup_timestamp",
+				Help:      "The last backup which failed uploading to the additional object store as a unix timestamp",
+			}, []string{"name"}),
+		},
 		FencingOn: prometheus.NewGauge(prometheus.GaugeOpts{
 			Namespace: PrometheusNamespace,
 			Subsystem: subsystem,
 			Name:      "fencing_on",
 			Help:      "1 if the instance is fenced, 0 otherwise",
 		}),
+		InstanceLocation: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "instance_location",
+			Help:      "The node and the topology zone where the instance is running (always 1)",
+		}, []string{"node", "zone"}),
 		PgStatWalMetrics: PgStatWalMetrics{
 			WalRecords: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 				Namespace: PrometheusNamespace,
@@ -232,6 +353,93 @@ func newMetrics() *metrics {
 					"fsync_writethrough, otherwise zero). Only available on PG 14+",
 			}, []string{"stats_reset"}),
 		},
+		WalSenderMetrics: WalSenderMetrics{
+			Streaming: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "wal_sender_streaming",
+				Help:      "1 if the WAL sender of the standby is streaming, 0 otherwise. Only available on the primary",
+			}, []string{"application_name"}),
+			Synchronous: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "wal_sender_synchronous",
+				Help:      "1 if the standby is a synchronous one, 0 otherwise. Only available on the primary",
+			}, []string{"application_name"}),
+			SentLsn: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "wal_sender_sent_lsn",
+				Help:      "Last WAL location sent to the standby, in bytes. Only available on the primary",
+			}, []string{"application_name"}),
+			WriteLsn: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "wal_sender_write_lsn",
+				Help:      "Last WAL location written to disk by the standby, in bytes. Only available on the primary",
+			}, []string{"application_name"}),
+			FlushLsn: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "wal_sender_flush_lsn",
+				Help:      "Last WAL location flushed to disk by the standby, in bytes. Only available on the primary",
+			}, []string{"application_name"}),
+			ReplayLsn: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "wal_sender_replay_lsn",
+				Help:      "Last WAL location replayed by the standby, in bytes. Only available on the primary",
+			}, []string{"application_name"}),
+		},
+		WalReceiverMetrics: WalReceiverMetrics{
+			Streaming: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "wal_receiver_streaming",
+				Help:      "1 if the WAL receiver is streaming, 0 otherwise",
+			}),
+			ReceivedLsn: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "wal_receiver_received_lsn",
+				Help:      "Last WAL location received and flushed to disk by the WAL receiver, in bytes",
+			}),
+			LatestEndLsn: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "wal_receiver_latest_end_lsn",
+				Help:      "Last WAL location reported to the WAL sender, in bytes",
+			}),
+			LastMsgReceiptTime: prometheus.NewGauge(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: subsystem,
+				Name:      "wal_receiver_last_msg_receipt_timestamp",
+				Help:      "The receipt time of the last message from the WAL sender as a unix timestamp",
+			}),
+		},
+		PgStatStatementsMetrics: PgStatStatementsMetrics{
+			Calls: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: "pg_stat_statements",
+				Name:      "top_query_calls",
+				Help: "Number of times the query has been executed, for the queries with the highest " +
+					"total execution time. Only available when pg_stat_statements is installed",
+			}, []string{"datname", "usename", "queryid"}),
+			TotalExecTime: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: "pg_stat_statements",
+				Name:      "top_query_exec_time_seconds",
+				Help: "Total time spent executing the query, in seconds, for the queries with the highest " +
+					"total execution time. Only available when pg_stat_statements is installed",
+			}, []string{"datname", "usename", "queryid"}),
+			Rows: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+				Namespace: PrometheusNamespace,
+				Subsystem: "pg_stat_statements",
+				Name:      "top_query_rows",
+				Help: "Total number of rows retrieved or affected by the query, for the queries with the highest " +
+					"total execution time. Only available when pg_stat_statements is installed",
+			}, []string{"datname", "usename", "queryid"}),
+		},
 	}
 }
 
@@ -246,10 +454,34 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	e.Metrics.SyncReplicas.Describe(ch)
 	ch <- e.Metrics.ReplicaCluster.Desc()
 	e.Metrics.PgWALArchiveStatus.Describe(ch)
+	e.Metrics.PgWALArchivingFailing.Describe(ch)
 	e.Metrics.PgWALDirectory.Describe(ch)
+	e.Metrics.DiskSpace.Describe(ch)
+	ch <- e.Metrics.ServerCertificateExpiry.Desc()
+	ch <- e.Metrics.DataCorruptions.Desc()
+	ch <- e.Metrics.LastDataVerification.Desc()
 	e.Metrics.PgVersion.Describe(ch)
 	e.Metrics.FirstRecoverabilityPoint.Describe(ch)
+	e.Metrics.LastSuccessfulBackup.Describe(ch)
+	e.Metrics.LastFailedBackup.Describe(ch)
+	e.Metrics.AdditionalObjectStores.WALArchivingFailing.Describe(ch)
+	e.Metrics.AdditionalObjectStores.LastSuccessfulBackup.Describe(ch)
+	e.Metrics.AdditionalObjectStores.LastFailedBackup.Describe(ch)
 	e.Metrics.FencingOn.Describe(ch)
+	e.Metrics.InstanceLocation.Describe(ch)
+	e.Metrics.PgStatStatementsMetrics.Calls.Describe(ch)
+	e.Metrics.PgStatStatementsMetrics.TotalExecTime.Describe(ch)
+	e.Metrics.PgStatStatementsMetrics.Rows.Describe(ch)
+	e.Metrics.WalSenderMetrics.Streaming.Describe(ch)
+	e.Metrics.WalSenderMetrics.Synchronous.Describe(ch)
+	e.Metrics.WalSenderMetrics.SentLsn.Describe(ch)
+	e.Metrics.WalSenderMetrics.WriteLsn.Describe(ch)
+	e.Metrics.WalSenderMetrics.FlushLsn.Describe(ch)
+	e.Metrics.WalSenderMetrics.ReplayLsn.Describe(ch)
+	ch <- e.Metrics.WalReceiverMetrics.Streaming.Desc()
+	ch <- e.Metrics.WalReceiverMetrics.ReceivedLsn.Desc()
+	ch <- e.Metrics.WalReceiverMetrics.LatestEndLsn.Desc()
+	ch <- e.Metrics.WalReceiverMetrics.LastMsgReceiptTime.Desc()
 
 	if e.queries != nil {
 		e.queries.Describe(ch)
@@ -281,9 +513,33 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	e.Metrics.SyncReplicas.Collect(ch)
 	ch <- e.Metrics.ReplicaCluster
 	e.Metrics.PgWALArchiveStatus.Collect(ch)
+	ch <- e.Metrics.PgWALArchivingFailing
 	e.Metrics.PgWALDirectory.Collect(ch)
+	e.Metrics.DiskSpace.Collect(ch)
+	ch <- e.Metrics.ServerCertificateExpiry
+	ch <- e.Metrics.DataCorruptions
+	ch <- e.Metrics.LastDataVerification
 	e.Metrics.PgVersion.Collect(ch)
 	e.Metrics.FirstRecoverabilityPoint.Collect(ch)
+	e.Metrics.LastSuccessfulBackup.Collect(ch)
+	e.Metrics.LastFailedBackup.Collect(ch)
+	e.Metrics.AdditionalObjectStores.WALArchivingFailing.Collect(ch)
+	e.Metrics.AdditionalObjectStores.LastSuccessfulBackup.Collect(ch)
+	e.Metrics.AdditionalObjectStores.LastFailedBackup.Collect(ch)
+	e.Metrics.InstanceLocation.Collect(ch)
+	e.Metrics.PgStatStatementsMetrics.Calls.Collect(ch)
+	e.Metrics.PgStatStatementsMetrics.TotalExecTime.Collect(ch)
+	e.Metrics.PgStatStatementsMetrics.Rows.Collect(ch)
+	e.Metrics.WalSenderMetrics.Streaming.Collect(ch)
+	e.Metrics.WalSenderMetrics.Synchronous.Collect(ch)
+	e.Metrics.WalSenderMetrics.SentLsn.Collect(ch)
+	e.Metrics.WalSenderMetrics.WriteLsn.Collect(ch)
+	e.Metrics.WalSenderMetrics.FlushLsn.Collect(ch)
+	e.Metrics.WalSenderMetrics.ReplayLsn.Collect(ch)
+	ch <- e.Metrics.WalReceiverMetrics.Streaming
+	ch <- e.Metrics.WalReceiverMetrics.ReceivedLsn
+	ch <- e.Metrics.WalReceiverMetrics.LatestEndLsn
+	ch <- e.Metrics.WalReceiverMetrics.LastMsgReceiptTime
 
 	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 {
 		e.Metrics.PgStatWalMetrics.WalSync.Collect(ch)
@@ -300,6 +556,12 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 	e.Metrics.CollectionsTotal.Inc()
 	collectionStart := time.Now()
+
+	// The data checksums are verified while the instance is fenced
+	e.collectDataVerificationResult()
+
+	e.collectInstanceLocation()
+
 	if e.instance.IsFenced() {
 		e.Metrics.FencingOn.Set(1)
 		log.Info("metrics collection skipped due to fencing")
@@ -356,7 +618,23 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 		e.collectFromPrimarySynchronousStandbysNumber(db)
 
 		// getting the first point of recoverability
-		e.collectFromPrimaryFirstPointOnTimeRecovery()
+		e.collectFromPrimaryBackupTimestamps()
+
+		e.collectFromPrimaryArchiverStatus()
+	} else {
+		e.Metrics.PgWALArchivingFailing.Set(0)
+	}
+
+	if err := collectWalSenders(e, isPrimary); err != nil {
+		log.Error(err, "while collecting the WAL senders")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.WalSenders").Inc()
+	}
+
+	if err := collectWalReceiver(e, isPrimary); err != nil {
+		log.Error(err, "while collecting the WAL receiver")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.WalReceiver").Inc()
 	}
 
 	if err := collectPGWalArchiveMetric(e); err != nil {
@@ -373,6 +651,19 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 		e.Metrics.PgWALDirectory.Reset()
 	}
 
+	if err := collectDiskSpaceMetric(e); err != nil {
+		log.Error(err, "while collecting disk space metrics")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.DiskSpace").Inc()
+		e.Metrics.DiskSpace.Reset()
+	}
+
+	if err := collectServerCertificateExpiry(e); err != nil {
+		log.Error(err, "while collecting the server certificate expiration")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.ServerCertificateExpiry").Inc()
+	}
+
 	if err := collectPGVersion(e); err != nil {
 		log.Error(err, "while collecting PGVersion metrics")
 		e.Metrics.Error.Set(1)
@@ -387,9 +678,49 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PGWALStat").Inc()
 		}
 	}
+
+	if err := collectPgStatStatements(e); err != nil {
+		log.Error(err, "while collecting pg_stat_statements")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PgStatStatements").Inc()
+	}
+}
+
+// collectDataVerificationResult exports the result
+// of the last verification of the data
+func (e *Exporter) collectDataVerificationResult() {
+	result := e.instance.GetDataVerificationResult()
+	if result == nil {
+		return
+	}
+
+	e.Metrics.DataCorruptions.Set(float64(len(result.Findings)))
+	e.Metrics.LastDataVerification.Set(float64(result.Time.Unix()))
 }
 
-func (e *Exporter) collectFromPrimaryFirstPointOnTimeRecovery() {
+// collectInstanceLocation exports the node and the topology zone
+// where the instance is running, as reported in the cluster status
+func (e *Exporter) collectInstanceLocation() {
+	cluster, err := cache.LoadCluster()
+	if err != nil {
+		// there isn't a cached object yet
+		if !errors.Is(err, cache.ErrCacheMiss) {
+			log.Error(err, "error while retrieving cluster cache object")
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues("Collect.InstanceLocation").Inc()
+		}
+		return
+	}
+
+	e.Metrics.InstanceLocation.Reset()
+	location, ok := cluster.Status.Topology.Locations[apiv1.PodName(e.instance.PodName)]
+	if !ok {
+		return
+	}
+	e.Metrics.InstanceLocation.WithLabelValues(location.Node, location.Zone).Set(1)
+}
+
+func (e *Exporter) collectFromPrimaryBackupTimestamps() {
 	const errorLabel = "Collect.FirstRecoverabilityPoint"
 
 	cluster, err := cache.LoadCluster()
@@ -405,23 +736,76 @@ func (e *Exporter) collectFromPrimaryFirstPointOnTimeRecovery() {
 		// if there is a programmatic error in the cache we should reset any potential data because it cannot be
 		// trusted as still valid
 		e.Metrics.FirstRecoverabilityPoint.Set(0)
+		e.Metrics.LastSuccessfulBackup.Set(0)
+		e.Metrics.LastFailedBackup.Set(0)
+		return
+	}
+
+	e.setTimestampMetric(e.Metrics.FirstRecoverabilityPoint, errorLabel,
+		cluster.Status.FirstRecoverabilityPoint)
+	e.setTimestampMetric(e.Metrics.LastSuccessfulBackup, "Collect.LastSuccessfulBackup",
+		cluster.Status.LastSuccessfulBackup)
+	e.setTimestampMetric(e.Metrics.LastFailedBackup, "Collect.LastFailedBackup",
+		cluster.Status.LastFailedBackup)
+
+	e.collectAdditionalObjectStores(cluster)
+}
+
+// collectAdditionalObjectStores exports the status of the
+// additional object stores, as reported in the cluster status
+func (e *Exporter) collectAdditionalObjectStores(cluster *apiv1.Cluster) {
+	metrics := e.Metrics.AdditionalObjectStores
+	metrics.WALArchivingFailing.Reset()
+	metrics.LastSuccessfulBackup.Reset()
+	metrics.LastFailedBackup.Reset()
+
+	for _, store := range cluster.Status.AdditionalObjectStores {
+		failing := 0.0
+		if store.WALArchivingFailing {
+			failing = 1
+		}
+		metrics.WALArchivingFailing.WithLabelValues(store.Name).Set(failing)
+		e.setTimestampMetric(metrics.LastSuccessfulBackup.WithLabelValues(store.Name),
+			"Collect.AdditionalObjectStoreLastSuccessfulBackup", store.LastSuccessfulBackup)
+		e.setTimestampMetric(metrics.LastFailedBackup.WithLabelValues(store.Name),
+			"Collect.AdditionalObjectStoreLastFailedBackup", store.LastFailedBackup)
+	}
+}
+
+// collectFromPrimaryArchiverStatus checks if the WAL archiving is failing
+func (e *Exporter) collectFromPrimaryArchiverStatus() {
+	status, err := e.instance.GetArchiverStatus()
+	if err != nil {
+		log.Error(err, "while getting the WAL archiver status")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues("Collect.PgWALArchivingFailing").Inc()
+		e.Metrics.PgWALArchivingFailing.Set(0)
 		return
 	}
 
-	ts := cluster.Status.FirstRecoverabilityPoint
-	// means no First recoverability point yet
+	if status.IsFailing {
+		e.Metrics.PgWALArchivingFailing.Set(1)
+	} else {
+		e.Metrics.PgWALArchivingFailing.Set(0)
+	}
+}
+
+// setTimestampMetric sets a gauge to the value of a timestamp stored
+// in the cluster status in RFC3339 format
+func (e *Exporter) setTimestampMetric(gauge prometheus.Gauge, errorLabel, ts string) {
+	// means that the timestamp has not been set yet
 	if ts == "" {
 		return
 	}
 
 	parsedTS, err := time.Parse(time.RFC3339, ts)
 	if err != nil {
-		log.Error(err, "while collecting parsing first recoverability point timestamp")
+		log.Error(err, "while parsing timestamp", "errorLabel", errorLabel)
 		e.Metrics.Error.Set(1)
 		e.Metrics.PgCollectionErrors.WithLabelValues(errorLabel).Inc()
-		// if we cannot parse FirstRecoverabilityPoint we should reset the potential existing value because it cannot be
+		// if we cannot parse the timestamp we should reset the potential existing value because it cannot be
 		// trusted as still valid
-		e.Metrics.FirstRecoverabilityPoint.Set(0)
+		gauge.Set(0)
 		return
 	}
 
@@ -429,7 +813,7 @@ func (e *Exporter) collectFromPrimaryFirstPointOnTimeRecovery() {
 	// exposing timestamps using the relative Unix timestamp
 	// number. See:
 	// https://prometheus.io/docs/practices/instrumentation/#timestamps-not-time-since
-	e.Metrics.FirstRecoverabilityPoint.Set(float64(parsedTS.Unix()))
+	gauge.Set(float64(parsedTS.Unix()))
 }
 
 func (e *Exporter) collectFromPrimarySynchronousStandbysNumber(db *sql.DB) {
@@ -474,6 +858,36 @@ func collectPGWalArchiveMetric(exporter *Exporter) error {
 	return nil
 }
 
+func collectDiskSpaceMetric(exporter *Exporter) error {
+	volumes, err := exporter.instance.GetVolumesUsage()
+	if err != nil {
+		return err
+	}
+
+	for _, volume := range volumes {
+		exporter.Metrics.DiskSpace.WithLabelValues(volume.Name, "total").Set(float64(volume.TotalBytes))
+		exporter.Metrics.DiskSpace.WithLabelValues(volume.Name, "available").Set(float64(volume.AvailableBytes))
+		exporter.Metrics.DiskSpace.WithLabelValues(volume.Name, "used").Set(float64(volume.GetUsedBytes()))
+	}
+	return nil
+}
+
+func collectServerCertificateExpiry(exporter *Exporter) error {
+	certificate, err := os.ReadFile(postgresconf.ServerCertificateLocation)
+	if err != nil {
+		return err
+	}
+
+	keyPair := certs.KeyPair{Certificate: certificate}
+	parsedCertificate, err := keyPair.ParseCertificate()
+	if err != nil {
+		return err
+	}
+
+	exporter.Metrics.ServerCertificateExpiry.Set(float64(parsedCertificate.NotAfter.Unix()))
+	return nil
+}
+
 func collectPGWALStat(e *Exporter) error {
 	walStat, err := e.instance.TryGetPgStatWAL()
 	if walStat == nil || err != nil {
@@ -492,6 +906,107 @@ func collectPGWALStat(e *Exporter) error {
 	return nil
 }
 
+func collectPgStatStatements(e *Exporter) error {
+	statementsMetrics := e.Metrics.PgStatStatementsMetrics
+	// The top queries change over time, so we don't keep the old ones
+	statementsMetrics.Calls.Reset()
+	statementsMetrics.TotalExecTime.Reset()
+	statementsMetrics.Rows.Reset()
+
+	queries, err := e.instance.TryGetPgStatStatementsTopQueries(pgStatStatementsTopQueries)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range queries {
+		statementsMetrics.Calls.WithLabelValues(query.DatabaseName, query.UserName, query.QueryID).
+			Set(float64(query.Calls))
+		statementsMetrics.TotalExecTime.WithLabelValues(query.DatabaseName, query.UserName, query.QueryID).
+			Set(query.TotalExecTimeSeconds)
+		statementsMetrics.Rows.WithLabelValues(query.DatabaseName, query.UserName, query.QueryID).
+			Set(float64(query.Rows))
+	}
+
+	return nil
+}
+
+// collectWalSenders collects the status of the WAL senders of the primary,
+// dropping the ones of the standbys which are not connected anymore
+func collectWalSenders(e *Exporter, isPrimary bool) error {
+	senderMetrics := e.Metrics.WalSenderMetrics
+	senderMetrics.Streaming.Reset()
+	senderMetrics.Synchronous.Reset()
+	senderMetrics.SentLsn.Reset()
+	senderMetrics.WriteLsn.Reset()
+	senderMetrics.FlushLsn.Reset()
+	senderMetrics.ReplayLsn.Reset()
+
+	if !isPrimary {
+		return nil
+	}
+
+	replicationInfo, err := e.instance.GetPgStatReplication()
+	if err != nil {
+		return err
+	}
+
+	for _, sender := range replicationInfo {
+		name := sender.ApplicationName
+		senderMetrics.Streaming.WithLabelValues(name).Set(boolToFloat(sender.State == "streaming"))
+		senderMetrics.Synchronous.WithLabelValues(name).Set(
+			boolToFloat(sender.SyncState == "sync" || sender.SyncState == "quorum"))
+		setLsnMetric(senderMetrics.SentLsn.WithLabelValues(name), sender.SentLsn)
+		setLsnMetric(senderMetrics.WriteLsn.WithLabelValues(name), sender.WriteLsn)
+		setLsnMetric(senderMetrics.FlushLsn.WithLabelValues(name), sender.FlushLsn)
+		setLsnMetric(senderMetrics.ReplayLsn.WithLabelValues(name), sender.ReplayLsn)
+	}
+
+	return nil
+}
+
+// collectWalReceiver collects the status of the WAL receiver of a standby
+func collectWalReceiver(e *Exporter, isPrimary bool) error {
+	receiverMetrics := e.Metrics.WalReceiverMetrics
+	receiverMetrics.Streaming.Set(0)
+	receiverMetrics.ReceivedLsn.Set(0)
+	receiverMetrics.LatestEndLsn.Set(0)
+	receiverMetrics.LastMsgReceiptTime.Set(0)
+
+	if isPrimary {
+		return nil
+	}
+
+	receiver, err := e.instance.GetPgStatWalReceiver()
+	if err != nil || receiver == nil {
+		return err
+	}
+
+	receiverMetrics.Streaming.Set(boolToFloat(receiver.Status == "streaming"))
+	setLsnMetric(receiverMetrics.ReceivedLsn, receiver.ReceivedLsn)
+	setLsnMetric(receiverMetrics.LatestEndLsn, receiver.LatestEndLsn)
+	if receiver.LastMsgReceiptTime != nil {
+		receiverMetrics.LastMsgReceiptTime.Set(float64(receiver.LastMsgReceiptTime.Unix()))
+	}
+
+	return nil
+}
+
+// setLsnMetric sets the value of the gauge to the position of the LSN
+// in bytes, leaving it unchanged if the LSN is not known
+func setLsnMetric(gauge prometheus.Gauge, lsn postgresconf.LSN) {
+	if position, err := lsn.Parse(); err == nil {
+		gauge.Set(float64(position))
+	}
+}
+
+// boolToFloat converts a flag to the value of a gauge
+func boolToFloat(value bool) float64 {
+	if value {
+		return 1
+	}
+	return 0
+}
+
 var regexPGWalFileName = regexp.MustCompile("^[0-9A-F]{24}")
 
 func collectPGWalMetric(exporter *Exporter, db *sql.DB) error {
@@ -567,6 +1082,7 @@ type PgCollector interface {
 
 // SetCustomQueries sets the custom queries from the passed content
 func (e *Exporter) SetCustomQueries(queries *m.QueriesCollector) {
+	queries.InheritCache(e.queries)
 	e.queries = queries
 }
 
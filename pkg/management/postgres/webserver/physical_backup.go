@@ -0,0 +1,99 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// physicalBackupHandler streams a hot physical backup of the instance as a
+// tarball. The WAL positions of the backup are sent as HTTP trailers, as
+// they are known only when the backup is completed
+func physicalBackupHandler(instance *postgres.Instance) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "wrong method used", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		options := postgres.PhysicalBackupOptions{
+			Label: query.Get(url.PhysicalBackupLabelParameter),
+		}
+		if options.Label == "" {
+			options.Label = fmt.Sprintf("cnpg-physical-backup-%s", time.Now().UTC().Format("20060102150405"))
+		}
+		if value := query.Get(url.PhysicalBackupFastParameter); value != "" {
+			var err error
+			options.Fast, err = strconv.ParseBool(value)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid fast parameter: %v", err), http.StatusBadRequest)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/x-tar")
+		w.Header().Set("Trailer", strings.Join([]string{
+			url.PhysicalBackupBeginLSNTrailer,
+			url.PhysicalBackupEndLSNTrailer,
+			url.PhysicalBackupBeginWALTrailer,
+		}, ", "))
+
+		output := &trackingWriter{writer: w}
+		result, err := instance.StreamPhysicalBackup(r.Context(), output, options)
+		if err != nil {
+			log.Warning("Physical backup failed", "label", options.Label, "err", err.Error())
+			if output.written {
+				// The tarball has been partially sent. Aborting the response
+				// prevents the client from mistaking it for a complete backup
+				panic(http.ErrAbortHandler)
+			}
+
+			w.Header().Del("Trailer")
+			status := http.StatusInternalServerError
+			if postgres.IsErrPhysicalBackupInProgress(err) {
+				status = http.StatusConflict
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+
+		w.Header().Set(url.PhysicalBackupBeginLSNTrailer, result.BeginLSN)
+		w.Header().Set(url.PhysicalBackupEndLSNTrailer, result.EndLSN)
+		w.Header().Set(url.PhysicalBackupBeginWALTrailer, result.BeginWAL)
+	}
+}
+
+// trackingWriter is a writer remembering if any data has been written
+type trackingWriter struct {
+	writer  io.Writer
+	written bool
+}
+
+func (t *trackingWriter) Write(p []byte) (int, error) {
+	t.written = true
+	return t.writer.Write(p)
+}
@@ -21,9 +21,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -89,7 +91,22 @@ func (ws *remoteWebserverEndpoints) isServerHealthy(w http.ResponseWriter, r *ht
 
 // This is the readiness probe
 func (ws *remoteWebserverEndpoints) isServerReady(w http.ResponseWriter, r *http.Request) {
-	err := ws.instance.IsServerReady()
+	var err error
+	query := r.URL.Query()
+	switch apiv1.ReadinessProbeType(query.Get(url.ReadinessProbeTypeParameter)) {
+	case apiv1.ReadinessProbeTypeStreaming:
+		var maximumLag int64
+		if value := query.Get(url.ReadinessProbeMaximumLagParameter); value != "" {
+			maximumLag, err = strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				http.Error(w, fmt.Sprintf("invalid maximum lag: %v", err), http.StatusBadRequest)
+				return
+			}
+		}
+		err = ws.instance.IsServerReadyAndStreaming(maximumLag)
+	default:
+		err = ws.instance.IsServerReady()
+	}
 	if err != nil {
 		log.Info("Readiness probe failing", "err", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
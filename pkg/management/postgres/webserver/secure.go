@@ -0,0 +1,179 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+type secureWebserverEndpoints struct {
+	instance *postgres.Instance
+}
+
+// NewSecureWebServer returns a webserver serving the endpoints reserved to
+// the operator and to the other Pods of the cluster: the physical backups
+// and the reload and restart of PostgreSQL. The server uses the certificate
+// of PostgreSQL and only accepts the clients presenting a certificate of
+// the streaming replication user signed by the client CA of the cluster,
+// like the replication connections
+func NewSecureWebServer(instance *postgres.Instance) (*Webserver, error) {
+	endpoints := secureWebserverEndpoints{
+		instance: instance,
+	}
+
+	serveMux := http.NewServeMux()
+	serveMux.HandleFunc(url.PathPgPhysicalBackup, physicalBackupHandler(instance))
+	serveMux.HandleFunc(url.PathPgReload, endpoints.requestReload)
+	serveMux.HandleFunc(url.PathPgRestart, endpoints.requestRestart)
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", url.SecurePort),
+		Handler:           serveMux,
+		ReadHeaderTimeout: DefaultReadHeaderTimeout,
+		ReadTimeout:       DefaultReadTimeout,
+		TLSConfig: &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			// The certificates are loaded at every connection, as they
+			// are written by the instance manager and may be renewed
+			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+				certificate, err := tls.LoadX509KeyPair(
+					postgresSpec.ServerCertificateLocation,
+					postgresSpec.ServerKeyLocation)
+				if err != nil {
+					return nil, err
+				}
+				return &certificate, nil
+			},
+			ClientAuth: tls.RequireAnyClientCert,
+			VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+				caContent, err := os.ReadFile(postgresSpec.ClientCACertificateLocation)
+				if err != nil {
+					return fmt.Errorf("while reading the client CA: %w", err)
+				}
+				certificate, err := verifyCertificateChain(rawCerts, caContent, x509.ExtKeyUsageClientAuth)
+				if err != nil {
+					return err
+				}
+				if certificate.Subject.CommonName != apiv1.StreamingReplicationUser {
+					return fmt.Errorf("the client certificate doesn't belong to %s",
+						apiv1.StreamingReplicationUser)
+				}
+				return nil
+			},
+		},
+	}
+
+	return NewWebServer(instance, server), nil
+}
+
+// This function reloads the PostgreSQL configuration
+func (ws *secureWebserverEndpoints) requestReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if ws.instance.IsFenced() {
+		http.Error(w, "Instance is fenced", http.StatusConflict)
+		return
+	}
+
+	if err := ws.instance.Reload(); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while reloading the configuration: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = fmt.Fprint(w, "OK")
+}
+
+// This function restarts PostgreSQL and waits for it to be up again
+func (ws *secureWebserverEndpoints) requestRestart(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if ws.instance.IsFenced() {
+		http.Error(w, "Instance is fenced", http.StatusConflict)
+		return
+	}
+
+	if err := ws.instance.RequestAndWaitRestartSmartFast(); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while restarting the instance: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = fmt.Fprint(w, "OK")
+}
+
+// verifyCertificateChain verifies the certificate chain presented by a peer
+// against the passed PEM encoded CA, returning the leaf certificate.
+// Like the `verify-ca` SSL mode of PostgreSQL, the host name is not checked
+func verifyCertificateChain(
+	rawCerts [][]byte,
+	caContent []byte,
+	usage x509.ExtKeyUsage,
+) (*x509.Certificate, error) {
+	if len(rawCerts) == 0 {
+		return nil, errors.New("missing peer certificate")
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caContent) {
+		return nil, errors.New("no valid certificate in the CA")
+	}
+
+	certificates := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, rawCert := range rawCerts {
+		certificate, err := x509.ParseCertificate(rawCert)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, certificate := range certificates[1:] {
+		intermediates.AddCert(certificate)
+	}
+
+	if _, err := certificates[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	}); err != nil {
+		return nil, err
+	}
+
+	return certificates[0], nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Client certificates", func() {
+	var ca *certs.KeyPair
+
+	rawCertificate := func(pair *certs.KeyPair) [][]byte {
+		block, _ := pem.Decode(pair.Certificate)
+		Expect(block).ToNot(BeNil())
+		return [][]byte{block.Bytes}
+	}
+
+	BeforeEach(func() {
+		var err error
+		ca, err = certs.CreateRootCA("ca", "cluster-example")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("accepts a certificate signed by the CA", func() {
+		pair, err := ca.CreateAndSignPair("streaming_replica", certs.CertTypeClient, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		certificate, err := verifyCertificateChain(rawCertificate(pair), ca.Certificate, x509.ExtKeyUsageClientAuth)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(certificate.Subject.CommonName).To(Equal("streaming_replica"))
+	})
+
+	It("rejects a certificate with a different usage", func() {
+		pair, err := ca.CreateAndSignPair("cluster-example-rw", certs.CertTypeServer, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = verifyCertificateChain(rawCertificate(pair), ca.Certificate, x509.ExtKeyUsageClientAuth)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects a certificate signed by another CA", func() {
+		otherCA, err := certs.CreateRootCA("other", "cluster-example")
+		Expect(err).ToNot(HaveOccurred())
+		pair, err := otherCA.CreateAndSignPair("streaming_replica", certs.CertTypeClient, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = verifyCertificateChain(rawCertificate(pair), ca.Certificate, x509.ExtKeyUsageClientAuth)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("rejects a connection without certificates", func() {
+		_, err := verifyCertificateChain(nil, ca.Certificate, x509.ExtKeyUsageClientAuth)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -53,7 +53,13 @@ func (ws *Webserver) Start(ctx context.Context) error {
 	go func() {
 		log.Info("Starting webserver", "address", ws.server.Addr)
 
-		err := ws.server.ListenAndServe()
+		var err error
+		if ws.server.TLSConfig != nil {
+			// The certificates are provided by the TLS configuration
+			err = ws.server.ListenAndServeTLS("", "")
+		} else {
+			err = ws.server.ListenAndServe()
+		}
 		if err != nil {
 			errChan <- err
 		}
@@ -0,0 +1,327 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package progress estimates the progress of the long-running copies of
+// the data directory, like the base backups and the restores, sampling
+// either the data read by the process doing the copy or the size of the
+// directory being written
+package progress
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// unknownTotalMilestoneBytes is the amount of data to be copied
+// to reach a new milestone when the total size is unknown
+const unknownTotalMilestoneBytes = 10 * 1024 * 1024 * 1024
+
+// milestonePercentage is the percentage of the total size
+// to be copied to reach a new milestone
+const milestonePercentage = 10
+
+// Snapshot describes the progress of a copy at a given time
+type Snapshot struct {
+	// The number of bytes copied so far
+	BytesCopied int64
+
+	// The total number of bytes to be copied, zero when unknown
+	TotalBytes int64
+
+	// The file being copied, relative to the copied directory
+	CurrentFile string
+
+	// The number of bytes copied per second since the previous snapshot
+	BytesPerSecond int64
+}
+
+// Percentage gets the estimated completion percentage, which is zero when
+// the total size is unknown and never reaches 100, as the total size is
+// just an estimation and the copy may still be running
+func (snapshot *Snapshot) Percentage() int32 {
+	if snapshot.TotalBytes <= 0 {
+		return 0
+	}
+
+	percentage := snapshot.BytesCopied * 100 / snapshot.TotalBytes
+	if percentage > 99 {
+		percentage = 99
+	}
+	return int32(percentage)
+}
+
+// String describes the progress in a human-readable form
+func (snapshot *Snapshot) String() string {
+	var result strings.Builder
+	result.WriteString(FormatBytes(snapshot.BytesCopied))
+	if snapshot.TotalBytes > 0 {
+		result.WriteString(fmt.Sprintf(" of %s (%d%%)", FormatBytes(snapshot.TotalBytes), snapshot.Percentage()))
+	}
+	result.WriteString(fmt.Sprintf(" copied, %s/s", FormatBytes(snapshot.BytesPerSecond)))
+	if snapshot.CurrentFile != "" {
+		result.WriteString(", current file: " + snapshot.CurrentFile)
+	}
+	return result.String()
+}
+
+// FormatBytes formats a number of bytes using binary units
+func FormatBytes(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
+
+// Sampler measures the number of bytes copied so far
+// and the file being copied
+type Sampler func() (int64, string, error)
+
+// ProcessReadSampler samples the number of bytes read by a process, as
+// reported by the `rchar` counter of the proc filesystem mounted in
+// procDir, and the file of the root directory the process is reading
+func ProcessReadSampler(procDir string, pid int, root string) Sampler {
+	processDir := filepath.Join(procDir, strconv.Itoa(pid))
+	return func() (int64, string, error) {
+		bytesRead, err := readProcessCounter(filepath.Join(processDir, "io"), "rchar")
+		if err != nil {
+			return 0, "", err
+		}
+
+		return bytesRead, findOpenFile(filepath.Join(processDir, "fd"), root), nil
+	}
+}
+
+// readProcessCounter reads a counter from a file of the proc filesystem
+// made by lines in the `name: value` format
+func readProcessCounter(fileName, counterName string) (int64, error) {
+	file, err := os.Open(fileName) // #nosec G304
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		name, value, found := strings.Cut(scanner.Text(), ":")
+		if !found || strings.TrimSpace(name) != counterName {
+			continue
+		}
+		return strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("counter %s not found in %s", counterName, fileName)
+}
+
+// findOpenFile finds the first file, in alphabetical order, of the root
+// directory among the ones opened by a process, given the directory
+// containing its file descriptors
+func findOpenFile(fdDir, root string) string {
+	entries, err := os.ReadDir(fdDir)
+	if err != nil {
+		return ""
+	}
+
+	var openFiles []string
+	for _, entry := range entries {
+		target, err := os.Readlink(filepath.Join(fdDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		if relativePath, err := filepath.Rel(root, target); err == nil &&
+			relativePath != "." && !strings.HasPrefix(relativePath, "..") {
+			openFiles = append(openFiles, relativePath)
+		}
+	}
+	if len(openFiles) == 0 {
+		return ""
+	}
+
+	sort.Strings(openFiles)
+	return openFiles[0]
+}
+
+// DirectorySampler samples the size of the files contained in the root
+// directory and the file which has been modified last
+func DirectorySampler(root string) Sampler {
+	return func() (int64, string, error) {
+		var size int64
+		var lastFile string
+		var lastModTime time.Time
+		err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil {
+				// The files can be removed while the copy is running
+				return nil
+			}
+			if !entry.Type().IsRegular() {
+				return nil
+			}
+
+			info, err := entry.Info()
+			if err != nil {
+				return nil
+			}
+			size += info.Size()
+			if info.ModTime().After(lastModTime) {
+				lastModTime = info.ModTime()
+				lastFile = path
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, "", err
+		}
+
+		if lastFile != "" {
+			lastFile, _ = filepath.Rel(root, lastFile)
+		}
+		return size, lastFile, nil
+	}
+}
+
+// DirectorySize gets the size of the files contained in the root directory,
+// skipping the passed subdirectories. Symbolic links are not followed
+func DirectorySize(root string, skippedDirs ...string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && path != root {
+			relativePath, _ := filepath.Rel(root, path)
+			for _, skippedDir := range skippedDirs {
+				if relativePath == skippedDir {
+					return filepath.SkipDir
+				}
+			}
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// Tracker computes the progress of a copy from the consecutive samples
+type Tracker struct {
+	sampler    Sampler
+	totalBytes int64
+
+	// The values of the previous sample
+	lastBytes     int64
+	lastTime      time.Time
+	lastMilestone int64
+}
+
+// NewTracker creates a new tracker for a copy, given the sampler measuring
+// it and the total number of bytes to be copied, which can be zero when
+// unknown
+func NewTracker(sampler Sampler, totalBytes int64) *Tracker {
+	return &Tracker{
+		sampler:    sampler,
+		totalBytes: totalBytes,
+	}
+}
+
+// Sample takes a new snapshot of the progress, returning whether the copy
+// reached a new milestone: every 10% of the total size or, when the
+// total size is unknown, every 10GiB
+func (tracker *Tracker) Sample(now time.Time) (*Snapshot, bool, error) {
+	bytesCopied, currentFile, err := tracker.sampler()
+	if err != nil {
+		return nil, false, err
+	}
+
+	snapshot := &Snapshot{
+		BytesCopied: bytesCopied,
+		TotalBytes:  tracker.totalBytes,
+		CurrentFile: currentFile,
+	}
+	if !tracker.lastTime.IsZero() && now.After(tracker.lastTime) && bytesCopied > tracker.lastBytes {
+		snapshot.BytesPerSecond = int64(float64(bytesCopied-tracker.lastBytes) / now.Sub(tracker.lastTime).Seconds())
+	}
+
+	var milestone int64
+	if tracker.totalBytes > 0 {
+		milestone = int64(snapshot.Percentage()) / milestonePercentage
+	} else {
+		milestone = bytesCopied / unknownTotalMilestoneBytes
+	}
+	milestoneReached := milestone > tracker.lastMilestone
+
+	tracker.lastBytes = bytesCopied
+	tracker.lastTime = now
+	if milestoneReached {
+		tracker.lastMilestone = milestone
+	}
+
+	return snapshot, milestoneReached, nil
+}
+
+// Run samples the progress every period until the context is cancelled,
+// passing every snapshot to the report function together with whether
+// a new milestone has been reached
+func Run(
+	ctx context.Context,
+	tracker *Tracker,
+	period time.Duration,
+	report func(snapshot *Snapshot, milestoneReached bool),
+) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			snapshot, milestoneReached, err := tracker.Sample(now)
+			if err != nil {
+				log.FromContext(ctx).Debug("Cannot sample the progress", "err", err)
+				continue
+			}
+			report(snapshot, milestoneReached)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package progress
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Copy progress", func() {
+	It("estimates the percentage without reaching 100", func() {
+		Expect((&Snapshot{BytesCopied: 50}).Percentage()).To(BeZero())
+		Expect((&Snapshot{BytesCopied: 50, TotalBytes: 200}).Percentage()).To(BeEquivalentTo(25))
+		Expect((&Snapshot{BytesCopied: 300, TotalBytes: 200}).Percentage()).To(BeEquivalentTo(99))
+	})
+
+	It("describes the progress", func() {
+		Expect(FormatBytes(512)).To(Equal("512 B"))
+		Expect(FormatBytes(3 * 1024 * 1024 * 1024 / 2)).To(Equal("1.5 GiB"))
+		Expect((&Snapshot{
+			BytesCopied:    1024,
+			TotalBytes:     4096,
+			BytesPerSecond: 512,
+			CurrentFile:    "base/1/1259",
+		}).String()).To(Equal("1.0 KiB of 4.0 KiB (25%) copied, 512 B/s, current file: base/1/1259"))
+	})
+
+	It("samples the data read by a process", func() {
+		procDir := GinkgoT().TempDir()
+		pgData := GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(procDir, "42", "fd"), 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(procDir, "42", "io"),
+			[]byte("rchar: 123456\nwchar: 42\nread_bytes: 4096\n"), 0o600)).To(Succeed())
+		Expect(os.Symlink("/dev/null", filepath.Join(procDir, "42", "fd", "0"))).To(Succeed())
+		Expect(os.Symlink(filepath.Join(pgData, "base", "1", "1259"),
+			filepath.Join(procDir, "42", "fd", "3"))).To(Succeed())
+
+		bytesRead, currentFile, err := ProcessReadSampler(procDir, 42, pgData)()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(bytesRead).To(BeEquivalentTo(123456))
+		Expect(currentFile).To(Equal(filepath.Join("base", "1", "1259")))
+
+		_, _, err = ProcessReadSampler(procDir, 43, pgData)()
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("samples the size of a directory", func() {
+		root := GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(root, "base", "1"), 0o700)).To(Succeed())
+		Expect(os.MkdirAll(filepath.Join(root, "pg_wal"), 0o700)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(root, "PG_VERSION"), []byte("15\n"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(root, "pg_wal", "000000010000000000000001"),
+			make([]byte, 100), 0o600)).To(Succeed())
+		lastFile := filepath.Join(root, "base", "1", "1259")
+		Expect(os.WriteFile(lastFile, make([]byte, 1000), 0o600)).To(Succeed())
+		Expect(os.Chtimes(lastFile, time.Now().Add(time.Hour), time.Now().Add(time.Hour))).To(Succeed())
+
+		size, currentFile, err := DirectorySampler(root)()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(size).To(BeEquivalentTo(1103))
+		Expect(currentFile).To(Equal(filepath.Join("base", "1", "1259")))
+
+		Expect(DirectorySize(root, "pg_wal")).To(BeEquivalentTo(1003))
+	})
+
+	It("reports the throughput and the milestones", func() {
+		var bytesCopied int64
+		tracker := NewTracker(func() (int64, string, error) {
+			return bytesCopied, "", nil
+		}, 1000)
+		now := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+
+		bytesCopied = 50
+		snapshot, milestoneReached, err := tracker.Sample(now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(milestoneReached).To(BeFalse())
+		Expect(snapshot.BytesPerSecond).To(BeZero())
+
+		bytesCopied = 350
+		snapshot, milestoneReached, err = tracker.Sample(now.Add(10 * time.Second))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(milestoneReached).To(BeTrue())
+		Expect(snapshot.BytesPerSecond).To(BeEquivalentTo(30))
+		Expect(snapshot.Percentage()).To(BeEquivalentTo(35))
+
+		bytesCopied = 390
+		_, milestoneReached, err = tracker.Sample(now.Add(20 * time.Second))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(milestoneReached).To(BeFalse())
+	})
+})
@@ -25,6 +25,12 @@ const (
 	// LocalPort is the port for only available from Postgres.
 	LocalPort int = 8010
 
+	// SecurePort is the port where the instance manager serves the
+	// endpoints reserved to the clients authenticated with the certificate
+	// of the streaming replication user, i.e. the operator and the other
+	// Pods of the cluster (HTTPS)
+	SecurePort int = 8011
+
 	// PostgresMetricsPort is the port for the exporter of PostgreSQL related metrics (HTTP)
 	PostgresMetricsPort int = 9187
 
@@ -37,12 +43,50 @@ const (
 	// PathReady is the URL oath for Ready State
 	PathReady string = "/readyz"
 
+	// ReadinessProbeTypeParameter is the query parameter of the readiness
+	// probe containing the criteria used to consider an instance ready
+	ReadinessProbeTypeParameter string = "type"
+
+	// ReadinessProbeMaximumLagParameter is the query parameter of the readiness
+	// probe containing the maximum replay lag, in bytes, of a ready replica
+	ReadinessProbeMaximumLagParameter string = "maximumLag"
+
 	// PathPgStatus is the URL path for PostgreSQL Status
 	PathPgStatus string = "/pg/status"
 
 	// PathPgBackup is the URL path for PostgreSQL Backup
 	PathPgBackup string = "/pg/backup"
 
+	// PathPgPhysicalBackup is the URL path streaming a hot physical
+	// backup of the instance
+	PathPgPhysicalBackup string = "/pg/physical-backup"
+
+	// PhysicalBackupLabelParameter is the query parameter of the physical
+	// backup containing the label of the backup
+	PhysicalBackupLabelParameter string = "label"
+
+	// PhysicalBackupFastParameter is the query parameter of the physical
+	// backup requesting an immediate checkpoint
+	PhysicalBackupFastParameter string = "fast"
+
+	// PhysicalBackupBeginLSNTrailer is the HTTP trailer of the physical
+	// backup containing the LSN where the backup started
+	PhysicalBackupBeginLSNTrailer string = "X-Cnpg-Backup-Begin-Lsn"
+
+	// PhysicalBackupEndLSNTrailer is the HTTP trailer of the physical
+	// backup containing the LSN where the backup ended
+	PhysicalBackupEndLSNTrailer string = "X-Cnpg-Backup-End-Lsn"
+
+	// PhysicalBackupBeginWALTrailer is the HTTP trailer of the physical
+	// backup containing the first WAL file needed to restore it
+	PhysicalBackupBeginWALTrailer string = "X-Cnpg-Backup-Begin-Wal"
+
+	// PathPgReload is the URL path for PostgreSQL configuration reload
+	PathPgReload string = "/pg/reload"
+
+	// PathPgRestart is the URL path for PostgreSQL restart
+	PathPgRestart string = "/pg/restart"
+
 	// PathMetrics is the URL path for Metrics
 	PathMetrics string = "/metrics"
 
@@ -69,3 +113,12 @@ func Build(hostname, path string, port int) string {
 	}
 	return fmt.Sprintf("http://%s:%d/%s", hostname, port, path)
 }
+
+// BuildSecure builds an HTTPS url given the hostname and the path
+func BuildSecure(hostname, path string, port int) string {
+	// If path already starts with '/' we remove it
+	if path[0] == '/' {
+		path = path[1:]
+	}
+	return fmt.Sprintf("https://%s:%d/%s", hostname, port, path)
+}
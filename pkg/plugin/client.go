@@ -0,0 +1,134 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	// shortCallTimeout is the maximum duration of the calls which are
+	// expected to return immediately, like the request of the metadata
+	shortCallTimeout = 30 * time.Second
+
+	// walCallTimeout is the maximum duration of the archive and of
+	// the restore of a WAL file
+	walCallTimeout = 5 * time.Minute
+)
+
+// Client calls the methods of a plugin
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// Dial connects to a plugin listening on the passed target, for
+// example "unix:///plugins/name.sock" or "host:port". The options
+// must contain the transport credentials of the connection
+func Dial(ctx context.Context, target string, options ...grpc.DialOption) (*Client, error) {
+	options = append(options,
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(jsonCodec{})),
+	)
+	conn, err := grpc.DialContext(ctx, target, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{conn: conn}, nil
+}
+
+// Close closes the connection to the plugin
+func (client *Client) Close() error {
+	return client.conn.Close()
+}
+
+// invoke calls a method of the plugin, failing when the call lasts
+// more than the passed timeout. A zero timeout means that the call
+// is only bounded by the passed context
+func (client *Client) invoke(
+	ctx context.Context,
+	method string,
+	timeout time.Duration,
+	request, result interface{},
+) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return client.conn.Invoke(ctx, "/"+ServiceName+"/"+method, request, result)
+}
+
+// GetMetadata gets the metadata of the plugin
+func (client *Client) GetMetadata(ctx context.Context) (*Metadata, error) {
+	var result Metadata
+	if err := client.invoke(ctx, "GetMetadata", shortCallTimeout, &MetadataRequest{}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ArchiveWAL archives a WAL file
+func (client *Client) ArchiveWAL(ctx context.Context, request *ArchiveWALRequest) (*ArchiveWALResult, error) {
+	var result ArchiveWALResult
+	if err := client.invoke(ctx, "ArchiveWAL", walCallTimeout, request, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RestoreWAL restores a WAL file
+func (client *Client) RestoreWAL(ctx context.Context, request *RestoreWALRequest) (*RestoreWALResult, error) {
+	var result RestoreWALResult
+	if err := client.invoke(ctx, "RestoreWAL", walCallTimeout, request, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Backup takes a base backup of the instance. As its duration depends
+// on the size of the database, it is only bounded by the passed context
+func (client *Client) Backup(ctx context.Context, request *BackupRequest) (*BackupResult, error) {
+	var result BackupResult
+	if err := client.invoke(ctx, "Backup", 0, request, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Restore restores a base backup in the data directory. As its duration
+// depends on the size of the database, it is only bounded by the passed
+// context
+func (client *Client) Restore(ctx context.Context, request *RestoreRequest) (*RestoreResult, error) {
+	var result RestoreResult
+	if err := client.invoke(ctx, "Restore", 0, request, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// MutatePod changes the definition of a Pod
+func (client *Client) MutatePod(ctx context.Context, request *MutatePodRequest) (*MutatePodResult, error) {
+	var result MutatePodResult
+	if err := client.invoke(ctx, "MutatePod", shortCallTimeout, request, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,206 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// SocketDirectory is the directory, shared between the containers of
+	// the instance Pods, where the plugins running as sidecars create
+	// their Unix domain sockets
+	SocketDirectory = "/plugins"
+
+	// SocketVolumeName is the name of the volume mounted in SocketDirectory
+	SocketVolumeName = "plugins"
+)
+
+// GetSocketPath gets the path of the Unix domain socket
+// where a plugin running as a sidecar is listening
+func GetSocketPath(name string) string {
+	return path.Join(SocketDirectory, name+".sock")
+}
+
+// DialLocal connects to a plugin running as a sidecar of the instance.
+// The Unix domain socket is only reachable from inside the Pod, so the
+// connection doesn't use TLS
+func DialLocal(ctx context.Context, name string) (*Client, error) {
+	return Dial(ctx, "unix://"+GetSocketPath(name), grpc.WithTransportCredentials(insecure.NewCredentials()))
+}
+
+// FindLocal finds, between the plugins of a cluster running as sidecars
+// of the instance, the first one implementing a capability. When no plugin
+// implements the capability, a nil client is returned
+func FindLocal(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	capability Capability,
+) (*Client, *apiv1.PluginConfiguration, error) {
+	for idx := range cluster.Spec.Plugins {
+		pluginConfiguration := &cluster.Spec.Plugins[idx]
+		if _, err := os.Stat(GetSocketPath(pluginConfiguration.Name)); err != nil {
+			continue
+		}
+
+		pluginClient, err := DialLocal(ctx, pluginConfiguration.Name)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if hasCapability(ctx, pluginClient, capability) {
+			return pluginClient, pluginConfiguration, nil
+		}
+		_ = pluginClient.Close()
+	}
+
+	return nil, nil, nil
+}
+
+// DialService connects to a plugin exposed by a Service in the operator
+// namespace, returning a nil client when no Service exposes it. The
+// connection uses mutual TLS: the operator issues the certificate of the
+// plugin, and authenticates with a client certificate signed by its CA
+func DialService(ctx context.Context, cli client.Client, name string) (*Client, error) {
+	var services corev1.ServiceList
+	if err := cli.List(ctx, &services, client.InNamespace(configuration.Current().OperatorNamespace)); err != nil {
+		return nil, err
+	}
+
+	for idx := range services.Items {
+		service := &services.Items[idx]
+		if service.Annotations[utils.PluginNameAnnotationName] != name {
+			continue
+		}
+
+		port, err := getServicePort(service)
+		if err != nil {
+			return nil, err
+		}
+
+		serverName := fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace)
+		transportCredentials, err := newServiceCredentials(ctx, cli, service, serverName)
+		if err != nil {
+			return nil, err
+		}
+
+		return Dial(ctx, fmt.Sprintf("%s:%d", serverName, port), grpc.WithTransportCredentials(transportCredentials))
+	}
+
+	return nil, nil
+}
+
+// getServicePort gets the port where a plugin exposed by a Service is listening
+func getServicePort(service *corev1.Service) (int32, error) {
+	if value, ok := service.Annotations[utils.PluginPortAnnotationName]; ok {
+		port, err := strconv.ParseInt(value, 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s annotation in service %s: %w",
+				utils.PluginPortAnnotationName, service.Name, err)
+		}
+		return int32(port), nil
+	}
+
+	if len(service.Spec.Ports) != 1 {
+		return 0, fmt.Errorf("service %s exposes %d ports, the %s annotation is required",
+			service.Name, len(service.Spec.Ports), utils.PluginPortAnnotationName)
+	}
+
+	return service.Spec.Ports[0].Port, nil
+}
+
+// MutatePod passes the definition of a Pod of a cluster to the plugins
+// exposed by a Service which implement the podMutation capability,
+// returning the changed definition
+func MutatePod(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	pod *corev1.Pod,
+) (*corev1.Pod, error) {
+	for idx := range cluster.Spec.Plugins {
+		pluginConfiguration := &cluster.Spec.Plugins[idx]
+		pluginClient, err := DialService(ctx, cli, pluginConfiguration.Name)
+		if err != nil {
+			return nil, err
+		}
+		if pluginClient == nil {
+			continue
+		}
+
+		pod, err = mutatePodWithPlugin(ctx, pluginClient, cluster, pluginConfiguration, pod)
+		_ = pluginClient.Close()
+		if err != nil {
+			return nil, fmt.Errorf("while calling plugin %s: %w", pluginConfiguration.Name, err)
+		}
+	}
+
+	return pod, nil
+}
+
+// mutatePodWithPlugin passes the definition of a Pod to a plugin,
+// when it implements the podMutation capability
+func mutatePodWithPlugin(
+	ctx context.Context,
+	pluginClient *Client,
+	cluster *apiv1.Cluster,
+	pluginConfiguration *apiv1.PluginConfiguration,
+	pod *corev1.Pod,
+) (*corev1.Pod, error) {
+	if !hasCapability(ctx, pluginClient, CapabilityPodMutation) {
+		return pod, nil
+	}
+
+	result, err := pluginClient.MutatePod(ctx, &MutatePodRequest{
+		Cluster:    cluster,
+		Parameters: pluginConfiguration.Parameters,
+		Pod:        pod,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Pod == nil {
+		return pod, nil
+	}
+
+	return result.Pod, nil
+}
+
+// hasCapability checks whether a plugin implements a capability
+func hasCapability(ctx context.Context, pluginClient *Client, capability Capability) bool {
+	metadata, err := pluginClient.GetMetadata(ctx)
+	if err != nil {
+		log.FromContext(ctx).Info("Cannot get the metadata of a plugin", "err", err)
+		return false
+	}
+
+	return metadata.HasCapability(capability)
+}
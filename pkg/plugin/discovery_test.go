@@ -0,0 +1,58 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Plugin discovery", func() {
+	It("places the sockets in the shared directory", func() {
+		Expect(GetSocketPath("backup")).To(Equal("/plugins/backup.sock"))
+	})
+
+	It("uses the only port of a Service", func() {
+		service := &corev1.Service{
+			Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 9090}}},
+		}
+		Expect(getServicePort(service)).To(BeEquivalentTo(9090))
+	})
+
+	It("uses the port in the annotation", func() {
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{utils.PluginPortAnnotationName: "9091"},
+			},
+			Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 9090}, {Port: 9091}}},
+		}
+		Expect(getServicePort(service)).To(BeEquivalentTo(9091))
+	})
+
+	It("requires the annotation when the Service exposes many ports", func() {
+		service := &corev1.Service{
+			Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 9090}, {Port: 9091}}},
+		}
+		_, err := getServicePort(service)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,216 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package plugin contains the API used by the operator and the instance
+// manager to call the plugins at the defined points of the lifecycle of a
+// cluster, together with the client and the server implementing it over
+// gRPC. The messages are encoded in JSON, allowing the plugins to use the
+// same types of the operator API
+package plugin
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// Capability is a feature a plugin can implement
+type Capability string
+
+const (
+	// CapabilityWAL means that the plugin can archive and restore WAL files
+	CapabilityWAL Capability = "wal"
+
+	// CapabilityBackup means that the plugin can take base backups
+	CapabilityBackup Capability = "backup"
+
+	// CapabilityRestore means that the plugin can restore base backups
+	CapabilityRestore Capability = "restore"
+
+	// CapabilityPodMutation means that the plugin can change the
+	// definition of the instance Pods, for example to add a sidecar
+	CapabilityPodMutation Capability = "podMutation"
+)
+
+// MetadataRequest is the request of the plugin metadata
+type MetadataRequest struct{}
+
+// Metadata describes a plugin
+type Metadata struct {
+	// The name of the plugin
+	Name string `json:"name"`
+
+	// The version of the plugin
+	Version string `json:"version"`
+
+	// The capabilities implemented by the plugin
+	Capabilities []Capability `json:"capabilities,omitempty"`
+}
+
+// HasCapability checks whether the plugin implements a capability
+func (metadata Metadata) HasCapability(capability Capability) bool {
+	for _, current := range metadata.Capabilities {
+		if current == capability {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ArchiveWALRequest is the request to archive a WAL file
+type ArchiveWALRequest struct {
+	// The cluster the instance belongs to
+	Cluster *apiv1.Cluster `json:"cluster"`
+
+	// The parameters of the plugin in the cluster definition
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// The path of the WAL file to be archived
+	SourcePath string `json:"sourcePath"`
+}
+
+// ArchiveWALResult is the result of the archiving of a WAL file
+type ArchiveWALResult struct{}
+
+// RestoreWALRequest is the request to restore a WAL file
+type RestoreWALRequest struct {
+	// The cluster the instance belongs to
+	Cluster *apiv1.Cluster `json:"cluster"`
+
+	// The parameters of the plugin in the cluster definition
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// The name of the WAL file to be restored
+	WALName string `json:"walName"`
+
+	// The path where the WAL file is to be restored
+	DestinationPath string `json:"destinationPath"`
+}
+
+// RestoreWALResult is the result of the restore of a WAL file
+type RestoreWALResult struct {
+	// True when the requested WAL file is not in the archive
+	NotFound bool `json:"notFound,omitempty"`
+}
+
+// BackupRequest is the request to take a base backup of an instance
+type BackupRequest struct {
+	// The cluster the instance belongs to
+	Cluster *apiv1.Cluster `json:"cluster"`
+
+	// The backup to be taken
+	Backup *apiv1.Backup `json:"backup"`
+
+	// The parameters of the plugin in the backup definition
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// BackupResult is the result of a base backup
+type BackupResult struct {
+	// The ID of the backup
+	BackupID string `json:"backupID,omitempty"`
+
+	// The first WAL file needed to restore the backup
+	BeginWal string `json:"beginWal,omitempty"`
+
+	// The last WAL file needed to restore the backup
+	EndWal string `json:"endWal,omitempty"`
+
+	// The LSN where the backup started
+	BeginLSN string `json:"beginLSN,omitempty"`
+
+	// The LSN where the backup ended
+	EndLSN string `json:"endLSN,omitempty"`
+
+	// The location of the backup
+	DestinationPath string `json:"destinationPath,omitempty"`
+}
+
+// RestoreRequest is the request to restore a base backup
+// in the data directory of a new instance
+type RestoreRequest struct {
+	// The cluster being bootstrapped
+	Cluster *apiv1.Cluster `json:"cluster"`
+
+	// The parameters of the plugin in the recovery source definition
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// The data directory to be filled
+	PgData string `json:"pgData"`
+}
+
+// RestoreResult is the result of the restore of a base backup
+type RestoreResult struct {
+	// The restore_command used by PostgreSQL to recover the
+	// WAL files needed to complete the restore
+	RestoreCommand string `json:"restoreCommand"`
+}
+
+// MutatePodRequest is the request to change the definition
+// of a Pod or of the template of a Job of an instance
+type MutatePodRequest struct {
+	// The cluster the Pod belongs to
+	Cluster *apiv1.Cluster `json:"cluster"`
+
+	// The parameters of the plugin in the cluster definition
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// The Pod to be changed
+	Pod *corev1.Pod `json:"pod"`
+}
+
+// MutatePodResult contains the changed Pod definition
+type MutatePodResult struct {
+	// The changed Pod
+	Pod *corev1.Pod `json:"pod"`
+}
+
+// Plugin is the interface every plugin must implement. The plugins
+// implement the interfaces corresponding to their capabilities too
+type Plugin interface {
+	// GetMetadata gets the metadata of the plugin
+	GetMetadata(ctx context.Context, request *MetadataRequest) (*Metadata, error)
+}
+
+// WALPlugin is implemented by the plugins with the wal capability
+type WALPlugin interface {
+	// ArchiveWAL archives a WAL file
+	ArchiveWAL(ctx context.Context, request *ArchiveWALRequest) (*ArchiveWALResult, error)
+
+	// RestoreWAL restores a WAL file
+	RestoreWAL(ctx context.Context, request *RestoreWALRequest) (*RestoreWALResult, error)
+}
+
+// BackupPlugin is implemented by the plugins with the backup capability
+type BackupPlugin interface {
+	// Backup takes a base backup of the instance
+	Backup(ctx context.Context, request *BackupRequest) (*BackupResult, error)
+}
+
+// RestorePlugin is implemented by the plugins with the restore capability
+type RestorePlugin interface {
+	// Restore restores a base backup in the data directory
+	Restore(ctx context.Context, request *RestoreRequest) (*RestoreResult, error)
+}
+
+// PodMutationPlugin is implemented by the plugins with the podMutation capability
+type PodMutationPlugin interface {
+	// MutatePod changes the definition of a Pod
+	MutatePod(ctx context.Context, request *MutatePodRequest) (*MutatePodResult, error)
+}
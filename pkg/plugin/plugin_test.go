@@ -0,0 +1,151 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeMetadataPlugin only implements the mandatory methods
+type fakeMetadataPlugin struct{}
+
+func (fakeMetadataPlugin) GetMetadata(context.Context, *MetadataRequest) (*Metadata, error) {
+	return &Metadata{Name: "fake", Version: "1.0.0"}, nil
+}
+
+// fakePlugin implements the WAL and Pod mutation capabilities
+type fakePlugin struct {
+	archived []string
+}
+
+func (fakePlugin) GetMetadata(context.Context, *MetadataRequest) (*Metadata, error) {
+	return &Metadata{
+		Name:         "fake",
+		Version:      "1.0.0",
+		Capabilities: []Capability{CapabilityWAL, CapabilityPodMutation},
+	}, nil
+}
+
+func (p *fakePlugin) ArchiveWAL(_ context.Context, request *ArchiveWALRequest) (*ArchiveWALResult, error) {
+	p.archived = append(p.archived, request.Cluster.Name+":"+request.SourcePath)
+	return &ArchiveWALResult{}, nil
+}
+
+func (p *fakePlugin) RestoreWAL(_ context.Context, request *RestoreWALRequest) (*RestoreWALResult, error) {
+	return &RestoreWALResult{NotFound: request.WALName != "000000010000000000000001"}, nil
+}
+
+func (p *fakePlugin) MutatePod(_ context.Context, request *MutatePodRequest) (*MutatePodResult, error) {
+	pod := request.Pod.DeepCopy()
+	pod.Spec.Containers = append(pod.Spec.Containers, corev1.Container{
+		Name:  "sidecar",
+		Image: request.Parameters["image"],
+	})
+	return &MutatePodResult{Pod: pod}, nil
+}
+
+var _ = Describe("Plugin metadata", func() {
+	It("detects the capabilities of a plugin", func() {
+		metadata := Metadata{Capabilities: []Capability{CapabilityWAL}}
+		Expect(metadata.HasCapability(CapabilityWAL)).To(BeTrue())
+		Expect(metadata.HasCapability(CapabilityBackup)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Plugin client and server", func() {
+	cluster := &apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"}}
+
+	serve := func(ctx context.Context, impl Plugin) *Client {
+		socket := filepath.Join(GinkgoT().TempDir(), "plugin.sock")
+		listener, err := net.Listen("unix", socket)
+		Expect(err).ToNot(HaveOccurred())
+
+		server := NewServer(impl)
+		go func() {
+			_ = server.Serve(listener)
+		}()
+		DeferCleanup(server.Stop)
+
+		client, err := Dial(ctx, "unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(client.Close)
+		return client
+	}
+
+	It("calls the methods implemented by the plugin", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		impl := &fakePlugin{}
+		client := serve(ctx, impl)
+
+		metadata, err := client.GetMetadata(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(metadata.Name).To(Equal("fake"))
+		Expect(metadata.HasCapability(CapabilityPodMutation)).To(BeTrue())
+
+		_, err = client.ArchiveWAL(ctx, &ArchiveWALRequest{
+			Cluster:    cluster,
+			SourcePath: "pg_wal/000000010000000000000001",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(impl.archived).To(ConsistOf("cluster-example:pg_wal/000000010000000000000001"))
+
+		restored, err := client.RestoreWAL(ctx, &RestoreWALRequest{
+			Cluster: cluster,
+			WALName: "000000010000000000000002",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(restored.NotFound).To(BeTrue())
+
+		mutated, err := client.MutatePod(ctx, &MutatePodRequest{
+			Cluster:    cluster,
+			Parameters: map[string]string{"image": "sidecar:latest"},
+			Pod: &corev1.Pod{Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{Name: "postgres"}},
+			}},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(mutated.Pod.Spec.Containers).To(HaveLen(2))
+		Expect(mutated.Pod.Spec.Containers[1].Image).To(Equal("sidecar:latest"))
+	})
+
+	It("reports the methods the plugin doesn't implement", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		client := serve(ctx, fakeMetadataPlugin{})
+
+		_, err := client.Backup(ctx, &BackupRequest{Cluster: cluster})
+		Expect(status.Code(err)).To(Equal(codes.Unimplemented))
+	})
+})
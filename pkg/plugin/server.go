@@ -0,0 +1,207 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ServiceName is the name of the gRPC service implemented by the plugins
+const ServiceName = "cnpg.plugin.v1.Plugin"
+
+// serviceDesc describes the gRPC service implemented by the plugins
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*Plugin)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "GetMetadata", Handler: getMetadataHandler},
+		{MethodName: "ArchiveWAL", Handler: archiveWALHandler},
+		{MethodName: "RestoreWAL", Handler: restoreWALHandler},
+		{MethodName: "Backup", Handler: backupHandler},
+		{MethodName: "Restore", Handler: restoreHandler},
+		{MethodName: "MutatePod", Handler: mutatePodHandler},
+	},
+}
+
+// NewServer creates a gRPC server exposing a plugin
+func NewServer(impl Plugin, options ...grpc.ServerOption) *grpc.Server {
+	server := grpc.NewServer(append(options, grpc.ForceServerCodec(jsonCodec{}))...)
+	server.RegisterService(&serviceDesc, impl)
+	return server
+}
+
+// Serve exposes a plugin on the passed network address, for example
+// a Unix domain socket, until the context is cancelled. The plugins
+// exposed by a Service must pass the credentials created by
+// NewServerCredentials in the options
+func Serve(ctx context.Context, network, address string, impl Plugin, options ...grpc.ServerOption) error {
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return err
+	}
+
+	server := NewServer(impl, options...)
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+
+	return server.Serve(listener)
+}
+
+// unimplemented gets the error returned when a plugin
+// doesn't implement the requested method
+func unimplemented(method string) error {
+	return status.Errorf(codes.Unimplemented, "the plugin doesn't implement %s", method)
+}
+
+// invoke calls a method of the plugin through the interceptors
+func invoke(
+	ctx context.Context,
+	method string,
+	request interface{},
+	interceptor grpc.UnaryServerInterceptor,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if interceptor == nil {
+		return handler(ctx, request)
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/" + ServiceName + "/" + method}
+	return interceptor(ctx, request, info, handler)
+}
+
+func getMetadataHandler(
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var request MetadataRequest
+	if err := dec(&request); err != nil {
+		return nil, err
+	}
+
+	return invoke(ctx, "GetMetadata", &request, interceptor, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(Plugin).GetMetadata(ctx, req.(*MetadataRequest))
+	})
+}
+
+func archiveWALHandler(
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var request ArchiveWALRequest
+	if err := dec(&request); err != nil {
+		return nil, err
+	}
+
+	return invoke(ctx, "ArchiveWAL", &request, interceptor, func(ctx context.Context, req interface{}) (interface{}, error) {
+		impl, ok := srv.(WALPlugin)
+		if !ok {
+			return nil, unimplemented("ArchiveWAL")
+		}
+		return impl.ArchiveWAL(ctx, req.(*ArchiveWALRequest))
+	})
+}
+
+func restoreWALHandler(
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var request RestoreWALRequest
+	if err := dec(&request); err != nil {
+		return nil, err
+	}
+
+	return invoke(ctx, "RestoreWAL", &request, interceptor, func(ctx context.Context, req interface{}) (interface{}, error) {
+		impl, ok := srv.(WALPlugin)
+		if !ok {
+			return nil, unimplemented("RestoreWAL")
+		}
+		return impl.RestoreWAL(ctx, req.(*RestoreWALRequest))
+	})
+}
+
+func backupHandler(
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var request BackupRequest
+	if err := dec(&request); err != nil {
+		return nil, err
+	}
+
+	return invoke(ctx, "Backup", &request, interceptor, func(ctx context.Context, req interface{}) (interface{}, error) {
+		impl, ok := srv.(BackupPlugin)
+		if !ok {
+			return nil, unimplemented("Backup")
+		}
+		return impl.Backup(ctx, req.(*BackupRequest))
+	})
+}
+
+func restoreHandler(
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var request RestoreRequest
+	if err := dec(&request); err != nil {
+		return nil, err
+	}
+
+	return invoke(ctx, "Restore", &request, interceptor, func(ctx context.Context, req interface{}) (interface{}, error) {
+		impl, ok := srv.(RestorePlugin)
+		if !ok {
+			return nil, unimplemented("Restore")
+		}
+		return impl.Restore(ctx, req.(*RestoreRequest))
+	})
+}
+
+func mutatePodHandler(
+	srv interface{},
+	ctx context.Context,
+	dec func(interface{}) error,
+	interceptor grpc.UnaryServerInterceptor,
+) (interface{}, error) {
+	var request MutatePodRequest
+	if err := dec(&request); err != nil {
+		return nil, err
+	}
+
+	return invoke(ctx, "MutatePod", &request, interceptor, func(ctx context.Context, req interface{}) (interface{}, error) {
+		impl, ok := srv.(PodMutationPlugin)
+		if !ok {
+			return nil, unimplemented("MutatePod")
+		}
+		return impl.MutatePod(ctx, req.(*MutatePodRequest))
+	})
+}
@@ -0,0 +1,254 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"google.golang.org/grpc/credentials"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+)
+
+// operatorClientName is the common name of the client certificate
+// used by the operator to connect to the plugins
+const operatorClientName = "cnpg-operator"
+
+// clientCertificate is the certificate used by the operator to
+// authenticate to the plugins exposed by a Service, together with
+// the CA certificate which signed it
+var clientCertificate struct {
+	sync.Mutex
+	pair          *certs.KeyPair
+	caCertificate []byte
+}
+
+// GetServerSecretName gets the name of the Secret, in the operator
+// namespace, containing the certificate issued by the operator to the
+// plugin exposed by a Service
+func GetServerSecretName(serviceName string) string {
+	return serviceName + "-plugin-tls"
+}
+
+// NewServerCredentials creates the transport credentials of a plugin exposed
+// by a Service, given the directory where the Secret containing its
+// certificate is mounted. Only the clients presenting a certificate signed by
+// the operator CA are accepted. The files are read at every handshake, so
+// that the renewed certificates are used without restarting the plugin
+func NewServerCredentials(certificateDir string) (credentials.TransportCredentials, error) {
+	loadConfig := func() (*tls.Config, error) {
+		certificate, err := tls.LoadX509KeyPair(
+			filepath.Join(certificateDir, certs.TLSCertKey),
+			filepath.Join(certificateDir, certs.TLSPrivateKeyKey))
+		if err != nil {
+			return nil, err
+		}
+
+		caCertificate, err := os.ReadFile(filepath.Join(certificateDir, certs.CACertKey)) // #nosec G304
+		if err != nil {
+			return nil, err
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(caCertificate) {
+			return nil, fmt.Errorf("invalid CA certificate in %s", certificateDir)
+		}
+
+		return &tls.Config{
+			Certificates: []tls.Certificate{certificate},
+			ClientCAs:    clientCAs,
+			ClientAuth:   tls.RequireAndVerifyClientCert,
+			MinVersion:   tls.VersionTLS12,
+		}, nil
+	}
+
+	// Fail early when the certificate is not available
+	if _, err := loadConfig(); err != nil {
+		return nil, err
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return loadConfig()
+		},
+	}), nil
+}
+
+// newServiceCredentials creates the transport credentials used by the
+// operator to connect to the plugin exposed by a Service, issuing the
+// certificate of the plugin when needed
+func newServiceCredentials(
+	ctx context.Context,
+	cli client.Client,
+	service *corev1.Service,
+	serverName string,
+) (credentials.TransportCredentials, error) {
+	var caSecret corev1.Secret
+	if err := cli.Get(
+		ctx,
+		types.NamespacedName{Namespace: service.Namespace, Name: certs.OperatorCASecretName},
+		&caSecret,
+	); err != nil {
+		return nil, fmt.Errorf("while getting the operator CA: %w", err)
+	}
+
+	if err := ensureServerSecret(ctx, cli, &caSecret, service, serverName); err != nil {
+		return nil, fmt.Errorf("while issuing the certificate of plugin service %s: %w", service.Name, err)
+	}
+
+	clientPair, err := getClientCertificate(&caSecret)
+	if err != nil {
+		return nil, err
+	}
+	certificate, err := tls.X509KeyPair(clientPair.Certificate, clientPair.Private)
+	if err != nil {
+		return nil, err
+	}
+
+	rootCAs := x509.NewCertPool()
+	if !rootCAs.AppendCertsFromPEM(caSecret.Data[certs.CACertKey]) {
+		return nil, fmt.Errorf("invalid CA certificate in secret %s", caSecret.Name)
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		RootCAs:      rootCAs,
+		ServerName:   serverName,
+		MinVersion:   tls.VersionTLS12,
+	}), nil
+}
+
+// ensureServerSecret creates the Secret containing the certificate issued
+// by the operator to the plugin exposed by a Service, renewing it when it is
+// expiring and reissuing it when the operator CA changes. The Secret contains
+// the operator CA certificate too, which the plugin uses to authenticate the
+// operator
+func ensureServerSecret(
+	ctx context.Context,
+	cli client.Client,
+	caSecret *corev1.Secret,
+	service *corev1.Service,
+	serverName string,
+) error {
+	var secret corev1.Secret
+	err := cli.Get(
+		ctx,
+		types.NamespacedName{Namespace: service.Namespace, Name: GetServerSecretName(service.Name)},
+		&secret,
+	)
+	switch {
+	case apierrors.IsNotFound(err):
+		newSecret, err := newServerSecret(caSecret, service, serverName)
+		if err != nil {
+			return err
+		}
+		return cli.Create(ctx, newSecret)
+
+	case err != nil:
+		return err
+
+	case !bytes.Equal(secret.Data[certs.CACertKey], caSecret.Data[certs.CACertKey]):
+		newSecret, err := newServerSecret(caSecret, service, serverName)
+		if err != nil {
+			return err
+		}
+		secret.Data = newSecret.Data
+		return cli.Update(ctx, &secret)
+
+	default:
+		renewed, err := certs.RenewLeafCertificate(caSecret, &secret)
+		if err != nil || !renewed {
+			return err
+		}
+		return cli.Update(ctx, &secret)
+	}
+}
+
+// newServerSecret creates the Secret containing a new certificate for
+// the plugin exposed by a Service, signed by the operator CA
+func newServerSecret(
+	caSecret *corev1.Secret,
+	service *corev1.Service,
+	serverName string,
+) (*corev1.Secret, error) {
+	caPair, err := certs.ParseCASecret(caSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	pair, err := caPair.CreateAndSignPair(serverName, certs.CertTypeServer, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := pair.GenerateCertificateSecret(service.Namespace, GetServerSecretName(service.Name))
+	secret.Data[certs.CACertKey] = caSecret.Data[certs.CACertKey]
+	secret.OwnerReferences = []metav1.OwnerReference{
+		{
+			APIVersion: "v1",
+			Kind:       "Service",
+			Name:       service.Name,
+			UID:        service.UID,
+		},
+	}
+
+	return secret, nil
+}
+
+// getClientCertificate gets the certificate used by the operator to
+// authenticate to the plugins, creating a new one when the current
+// one is expiring or has been signed by a different CA
+func getClientCertificate(caSecret *corev1.Secret) (*certs.KeyPair, error) {
+	clientCertificate.Lock()
+	defer clientCertificate.Unlock()
+
+	caCertificate := caSecret.Data[certs.CACertKey]
+	if clientCertificate.pair != nil && bytes.Equal(clientCertificate.caCertificate, caCertificate) {
+		expiring, _, err := clientCertificate.pair.IsExpiring()
+		if err != nil {
+			return nil, err
+		}
+		if !expiring {
+			return clientCertificate.pair, nil
+		}
+	}
+
+	caPair, err := certs.ParseCASecret(caSecret)
+	if err != nil {
+		return nil, err
+	}
+	pair, err := caPair.CreateAndSignPair(operatorClientName, certs.CertTypeClient, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	clientCertificate.pair = pair
+	clientCertificate.caCertificate = caCertificate
+	return pair, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Plugin mutual TLS", func() {
+	const (
+		namespace  = "cnpg-system"
+		serverName = "backup-plugin.cnpg-system.svc"
+	)
+
+	var (
+		cli       client.Client
+		caSecret  *corev1.Secret
+		service   *corev1.Service
+		secretKey types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		caPair, err := certs.CreateRootCA("cnpg-ca", namespace)
+		Expect(err).ToNot(HaveOccurred())
+		caSecret = caPair.GenerateCASecret(namespace, certs.OperatorCASecretName)
+		service = &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: "backup-plugin", Namespace: namespace, UID: "uid"},
+		}
+		secretKey = types.NamespacedName{Namespace: namespace, Name: GetServerSecretName(service.Name)}
+		cli = fake.NewClientBuilder().WithObjects(caSecret, service).Build()
+	})
+
+	It("issues the certificate of a plugin once", func() {
+		Expect(ensureServerSecret(context.TODO(), cli, caSecret, service, serverName)).To(Succeed())
+
+		var secret corev1.Secret
+		Expect(cli.Get(context.TODO(), secretKey, &secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKey(certs.TLSCertKey))
+		Expect(secret.Data).To(HaveKey(certs.TLSPrivateKeyKey))
+		Expect(secret.Data[certs.CACertKey]).To(Equal(caSecret.Data[certs.CACertKey]))
+		Expect(secret.OwnerReferences).To(HaveLen(1))
+		Expect(secret.OwnerReferences[0].Name).To(Equal(service.Name))
+
+		Expect(ensureServerSecret(context.TODO(), cli, caSecret, service, serverName)).To(Succeed())
+		var unchanged corev1.Secret
+		Expect(cli.Get(context.TODO(), secretKey, &unchanged)).To(Succeed())
+		Expect(unchanged.ResourceVersion).To(Equal(secret.ResourceVersion))
+	})
+
+	It("reissues the certificate of a plugin when the CA changes", func() {
+		Expect(ensureServerSecret(context.TODO(), cli, caSecret, service, serverName)).To(Succeed())
+
+		newCAPair, err := certs.CreateRootCA("cnpg-ca", namespace)
+		Expect(err).ToNot(HaveOccurred())
+		newCASecret := newCAPair.GenerateCASecret(namespace, certs.OperatorCASecretName)
+		Expect(ensureServerSecret(context.TODO(), cli, newCASecret, service, serverName)).To(Succeed())
+
+		var secret corev1.Secret
+		Expect(cli.Get(context.TODO(), secretKey, &secret)).To(Succeed())
+		Expect(secret.Data[certs.CACertKey]).To(Equal(newCASecret.Data[certs.CACertKey]))
+		pair, err := certs.ParseServerSecret(&secret)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(pair.IsValid(newCAPair, nil)).To(Succeed())
+	})
+
+	It("only accepts the clients authenticated by the operator CA", func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		operatorCredentials, err := newServiceCredentials(ctx, cli, service, serverName)
+		Expect(err).ToNot(HaveOccurred())
+
+		// The plugin mounts the Secret issued by the operator
+		var secret corev1.Secret
+		Expect(cli.Get(ctx, secretKey, &secret)).To(Succeed())
+		certificateDir := GinkgoT().TempDir()
+		for key, value := range secret.Data {
+			Expect(os.WriteFile(filepath.Join(certificateDir, key), value, 0o600)).To(Succeed())
+		}
+		serverCredentials, err := NewServerCredentials(certificateDir)
+		Expect(err).ToNot(HaveOccurred())
+
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		Expect(err).ToNot(HaveOccurred())
+		server := NewServer(fakeMetadataPlugin{}, grpc.Creds(serverCredentials))
+		go func() {
+			_ = server.Serve(listener)
+		}()
+		DeferCleanup(server.Stop)
+
+		dial := func(transportCredentials credentials.TransportCredentials) error {
+			pluginClient, err := Dial(ctx, listener.Addr().String(), grpc.WithTransportCredentials(transportCredentials))
+			Expect(err).ToNot(HaveOccurred())
+			defer func() {
+				_ = pluginClient.Close()
+			}()
+			_, err = pluginClient.GetMetadata(ctx)
+			return err
+		}
+
+		Expect(dial(operatorCredentials)).To(Succeed())
+
+		rootCAs := x509.NewCertPool()
+		Expect(rootCAs.AppendCertsFromPEM(caSecret.Data[certs.CACertKey])).To(BeTrue())
+		anonymousCredentials := credentials.NewTLS(&tls.Config{
+			RootCAs:    rootCAs,
+			ServerName: serverName,
+			MinVersion: tls.VersionTLS12,
+		})
+		Expect(dial(anonymousCredentials)).ToNot(Succeed())
+	})
+})
@@ -187,6 +187,23 @@ func (builder *Builder) WithServiceAccountName(name string, overwrite bool) *Bui
 	return builder
 }
 
+// WithPodAntiAffinity add the provided pod anti-affinity section
+func (builder *Builder) WithPodAntiAffinity(podAntiAffinity *corev1.PodAntiAffinity, overwrite bool) *Builder {
+	if builder.status.Spec.Affinity != nil && builder.status.Spec.Affinity.PodAntiAffinity != nil && !overwrite {
+		return builder
+	}
+
+	// The affinity section may be shared with the original Pod template
+	affinity := builder.status.Spec.Affinity.DeepCopy()
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	}
+	affinity.PodAntiAffinity = podAntiAffinity
+	builder.status.Spec.Affinity = affinity
+
+	return builder
+}
+
 // WithLivenessProbe add the provided liveness probe to a container
 func (builder *Builder) WithLivenessProbe(name string, livenessProbe *corev1.Probe, overwrite bool) *Builder {
 	builder.WithContainer(name)
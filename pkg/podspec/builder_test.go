@@ -266,4 +266,25 @@ var _ = Describe("Pod template builder", func() {
 		Expect(template.Spec.Containers[0].Env[0].Name).To(Equal("one"))
 		Expect(template.Spec.Containers[0].Env[0].Value).To(Equal("two"))
 	})
+
+	It("sets the pod anti-affinity when not set", func() {
+		antiAffinity := &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{Weight: 100}},
+		}
+		template := New().WithPodAntiAffinity(antiAffinity, false).Build()
+		Expect(template.Spec.Affinity.PodAntiAffinity).To(Equal(antiAffinity))
+	})
+
+	It("keeps the pod anti-affinity of the template", func() {
+		existing := &corev1.PodAntiAffinity{}
+		original := &apiv1.PodTemplateSpec{
+			Spec: corev1.PodSpec{
+				Affinity: &corev1.Affinity{PodAntiAffinity: existing},
+			},
+		}
+		template := NewFrom(original).WithPodAntiAffinity(&corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{{Weight: 100}},
+		}, false).Build()
+		Expect(template.Spec.Affinity.PodAntiAffinity).To(BeIdenticalTo(existing))
+	})
 })
@@ -50,16 +50,6 @@ hostssl all cnpg_pooler_pgbouncer all cert
 host all all all {{.DefaultAuthenticationMethod}}
 `
 
-	// fixedConfigurationParameter are the configuration parameters
-	// whose value is managed by the operator and should not be changed
-	// by the user
-	fixedConfigurationParameter = "fixed"
-
-	// blockedConfigurationParameter are the configuration parameters
-	// whose value must not be changed from the default one for the
-	// operator to work correctly
-	blockedConfigurationParameter = "blocked"
-
 	// ScratchDataDirectory is the directory to be used for scratch data
 	ScratchDataDirectory = "/controller"
 
@@ -91,6 +81,16 @@ host all all all {{.DefaultAuthenticationMethod}}
 	// server certificates
 	ServerCACertificateLocation = CertificatesDir + "server-ca.crt"
 
+	// ReplicationSSLModeEnvironmentVariable is the environment variable used
+	// to pass the sslmode of the streaming replication connections to the
+	// instance manager
+	ReplicationSSLModeEnvironmentVariable = "REPLICATION_SSLMODE"
+
+	// ReplicationChannelBindingEnvironmentVariable is the environment variable
+	// used to pass the channel_binding of the streaming replication connections
+	// to the instance manager
+	ReplicationChannelBindingEnvironmentVariable = "REPLICATION_CHANNEL_BINDING"
+
 	// BarmanBackupEndpointCACertificateLocation is the location where the barman endpoint
 	// CA certificate is stored
 	BarmanBackupEndpointCACertificateLocation = CertificatesDir + BarmanBackupEndpointCACertificateFileName
@@ -144,6 +144,10 @@ host all all all {{.DefaultAuthenticationMethod}}
 
 	// SynchronousStandbyNames is the postgresql parameter key for synchronous standbys
 	SynchronousStandbyNames = "synchronous_standby_names"
+
+	// DataEncryptionKeyUnwrapCommand is the parameter containing the command
+	// unwrapping the data encryption key of the Transparent Data Encryption
+	DataEncryptionKeyUnwrapCommand = "data_encryption_key_unwrap_command"
 )
 
 // hbaTemplate is the template used to create the HBA configuration
@@ -222,8 +226,14 @@ type ConfigurationInfo struct {
 	// List of additional sharedPreloadLibraries to be loaded
 	AdditionalSharedPreloadLibraries []string
 
+	// List of the managed extensions explicitly enabled by the user
+	EnabledExtensions []string
+
 	// Is this a replica cluster?
 	IsReplicaCluster bool
+
+	// The flavor of PostgreSQL
+	Flavor Flavor
 }
 
 // ManagedExtension defines all the information about a managed extension
@@ -251,6 +261,82 @@ func (e ManagedExtension) IsUsed(userConfigs map[string]string) bool {
 	return false
 }
 
+// IsEnabled checks whether the extension has been explicitly enabled or
+// is used in the user provided configuration
+func (e ManagedExtension) IsEnabled(userConfigs map[string]string, enabledExtensions []string) bool {
+	for _, name := range enabledExtensions {
+		if name == e.Name {
+			return true
+		}
+	}
+	return e.IsUsed(userConfigs)
+}
+
+// ParameterOverrideLevel tells how much a configuration parameter
+// can be influenced by the user
+type ParameterOverrideLevel string
+
+const (
+	// ParameterManaged is the level of the configuration parameters whose
+	// value is enforced by the operator. The user can't set them to a value
+	// different from the one chosen by the operator
+	ParameterManaged ParameterOverrideLevel = "managed"
+
+	// ParameterBlocked is the level of the configuration parameters
+	// that must not be set by the user at all for the operator to
+	// work correctly
+	ParameterBlocked ParameterOverrideLevel = "blocked"
+)
+
+// The reasons why a configuration parameter can't be changed by the user
+const (
+	reasonFileLocation = "the location of the configuration, data and " +
+		"PID files is managed by the operator"
+	reasonLogging = "the instance manager collects the PostgreSQL logs " +
+		"and needs them to be written in CSV format in a known location"
+	reasonNetwork = "the operator needs PostgreSQL to listen on the " +
+		"standard port and on a known socket directory, which are used by " +
+		"the services, the probes and the instance manager"
+	reasonReplication = "streaming replication and high availability " +
+		"are managed by the operator"
+	reasonArchiving = "WAL archiving is managed by the operator through " +
+		"the backup configuration of the cluster"
+	reasonRecovery = "the recovery of the instances is managed by " +
+		"the operator through the bootstrap and replica configuration " +
+		"of the cluster"
+	reasonTLS = "TLS is managed by the operator through the " +
+		"certificates configuration of the cluster"
+	reasonUnsafe = "the parameter can damage the instance or prevent " +
+		"the operator from managing it"
+	reasonSharedPreloadLibraries = "the shared libraries are loaded by " +
+		"the operator depending on the enabled extensions"
+	reasonSynchronousReplication = "the list of synchronous standbys " +
+		"is managed by the operator through the minSyncReplicas and " +
+		"maxSyncReplicas options"
+	reasonClusterName = "the cluster name is set by the operator to the " +
+		"name of the Cluster resource"
+	reasonDataEncryption = "Transparent Data Encryption is managed by " +
+		"the operator"
+	reasonBonjour  = "service discovery is handled by Kubernetes"
+	reasonPlatform = "the parameter is not supported on the platform " +
+		"where the operator runs"
+)
+
+// FixedParameter describes a configuration parameter which can't be
+// freely set by the user
+type FixedParameter struct {
+	// Level tells how much the parameter can be influenced by the user
+	Level ParameterOverrideLevel
+
+	// Reason explains why the parameter can't be set by the user
+	Reason string
+}
+
+// IsBlocked checks if the parameter can't be set by the user at all
+func (p FixedParameter) IsBlocked() bool {
+	return p.Level == ParameterBlocked
+}
+
 var (
 	// ManagedExtensions contains the list of extensions the operator supports to manage
 	ManagedExtensions = []ManagedExtension{
@@ -273,78 +359,81 @@ var (
 	}
 
 	// FixedConfigurationParameters contains the parameters that can't be
-	// changed by the user
-	FixedConfigurationParameters = map[string]string{
+	// changed by the user, together with the reason why
+	FixedConfigurationParameters = map[string]FixedParameter{
 		// The following parameters need a restart to be applied
-		"allow_system_table_mods":   blockedConfigurationParameter,
-		"archive_mode":              fixedConfigurationParameter,
-		"bonjour":                   blockedConfigurationParameter,
-		"bonjour_name":              blockedConfigurationParameter,
-		"cluster_name":              fixedConfigurationParameter,
-		"config_file":               blockedConfigurationParameter,
-		"data_directory":            blockedConfigurationParameter,
-		"data_sync_retry":           blockedConfigurationParameter,
-		"event_source":              blockedConfigurationParameter,
-		"external_pid_file":         blockedConfigurationParameter,
-		"hba_file":                  blockedConfigurationParameter,
-		"hot_standby":               blockedConfigurationParameter,
-		"ident_file":                blockedConfigurationParameter,
-		"jit_provider":              blockedConfigurationParameter,
-		"listen_addresses":          blockedConfigurationParameter,
-		"logging_collector":         blockedConfigurationParameter,
-		"port":                      fixedConfigurationParameter,
-		"primary_conninfo":          fixedConfigurationParameter,
-		"primary_slot_name":         fixedConfigurationParameter,
-		"recovery_target":           fixedConfigurationParameter,
-		"recovery_target_action":    fixedConfigurationParameter,
-		"recovery_target_inclusive": fixedConfigurationParameter,
-		"recovery_target_lsn":       fixedConfigurationParameter,
-		"recovery_target_name":      fixedConfigurationParameter,
-		"recovery_target_time":      fixedConfigurationParameter,
-		"recovery_target_timeline":  fixedConfigurationParameter,
-		"recovery_target_xid":       fixedConfigurationParameter,
-		"restore_command":           fixedConfigurationParameter,
-		"shared_preload_libraries":  fixedConfigurationParameter,
-		"unix_socket_directories":   blockedConfigurationParameter,
-		"unix_socket_group":         blockedConfigurationParameter,
-		"unix_socket_permissions":   blockedConfigurationParameter,
-		"wal_level":                 fixedConfigurationParameter,
-		"wal_log_hints":             fixedConfigurationParameter,
+		"allow_system_table_mods":   {Level: ParameterBlocked, Reason: reasonUnsafe},
+		"archive_mode":              {Level: ParameterManaged, Reason: reasonArchiving},
+		"bonjour":                   {Level: ParameterBlocked, Reason: reasonBonjour},
+		"bonjour_name":              {Level: ParameterBlocked, Reason: reasonBonjour},
+		"cluster_name":              {Level: ParameterManaged, Reason: reasonClusterName},
+		"config_file":               {Level: ParameterBlocked, Reason: reasonFileLocation},
+		"data_directory":            {Level: ParameterBlocked, Reason: reasonFileLocation},
+		"data_sync_retry":           {Level: ParameterBlocked, Reason: reasonUnsafe},
+		"event_source":              {Level: ParameterBlocked, Reason: reasonPlatform},
+		"external_pid_file":         {Level: ParameterBlocked, Reason: reasonFileLocation},
+		"hba_file":                  {Level: ParameterBlocked, Reason: reasonFileLocation},
+		"hot_standby":               {Level: ParameterManaged, Reason: reasonReplication},
+		"ident_file":                {Level: ParameterBlocked, Reason: reasonFileLocation},
+		"jit_provider":              {Level: ParameterBlocked, Reason: reasonUnsafe},
+		"listen_addresses":          {Level: ParameterBlocked, Reason: reasonNetwork},
+		"logging_collector":         {Level: ParameterBlocked, Reason: reasonLogging},
+		"port":                      {Level: ParameterBlocked, Reason: reasonNetwork},
+		"primary_conninfo":          {Level: ParameterManaged, Reason: reasonReplication},
+		"primary_slot_name":         {Level: ParameterManaged, Reason: reasonReplication},
+		"recovery_target":           {Level: ParameterManaged, Reason: reasonRecovery},
+		"recovery_target_action":    {Level: ParameterManaged, Reason: reasonRecovery},
+		"recovery_target_inclusive": {Level: ParameterManaged, Reason: reasonRecovery},
+		"recovery_target_lsn":       {Level: ParameterManaged, Reason: reasonRecovery},
+		"recovery_target_name":      {Level: ParameterManaged, Reason: reasonRecovery},
+		"recovery_target_time":      {Level: ParameterManaged, Reason: reasonRecovery},
+		"recovery_target_timeline":  {Level: ParameterManaged, Reason: reasonRecovery},
+		"recovery_target_xid":       {Level: ParameterManaged, Reason: reasonRecovery},
+		"restore_command":           {Level: ParameterManaged, Reason: reasonRecovery},
+		"shared_preload_libraries":  {Level: ParameterManaged, Reason: reasonSharedPreloadLibraries},
+		"unix_socket_directories":   {Level: ParameterBlocked, Reason: reasonNetwork},
+		"unix_socket_group":         {Level: ParameterBlocked, Reason: reasonNetwork},
+		"unix_socket_permissions":   {Level: ParameterBlocked, Reason: reasonNetwork},
+		"wal_level":                 {Level: ParameterManaged, Reason: reasonReplication},
+		"wal_log_hints":             {Level: ParameterManaged, Reason: reasonReplication},
+
+		// The Transparent Data Encryption is managed by the operator
+		DataEncryptionKeyUnwrapCommand: {Level: ParameterManaged, Reason: reasonDataEncryption},
 
 		// The following parameters need a reload to be applied
-		"archive_cleanup_command":                blockedConfigurationParameter,
-		"archive_command":                        fixedConfigurationParameter,
-		"full_page_writes":                       fixedConfigurationParameter,
-		"log_destination":                        blockedConfigurationParameter,
-		"log_directory":                          blockedConfigurationParameter,
-		"log_file_mode":                          blockedConfigurationParameter,
-		"log_filename":                           blockedConfigurationParameter,
-		"log_rotation_age":                       blockedConfigurationParameter,
-		"log_rotation_size":                      blockedConfigurationParameter,
-		"log_truncate_on_rotation":               blockedConfigurationParameter,
-		"promote_trigger_file":                   blockedConfigurationParameter,
-		"recovery_end_command":                   blockedConfigurationParameter,
-		"recovery_min_apply_delay":               blockedConfigurationParameter,
-		"restart_after_crash":                    blockedConfigurationParameter,
-		"ssl":                                    fixedConfigurationParameter,
-		"ssl_ca_file":                            fixedConfigurationParameter,
-		"ssl_cert_file":                          fixedConfigurationParameter,
-		"ssl_ciphers":                            fixedConfigurationParameter,
-		"ssl_crl_file":                           fixedConfigurationParameter,
-		"ssl_dh_params_file":                     fixedConfigurationParameter,
-		"ssl_ecdh_curve":                         fixedConfigurationParameter,
-		"ssl_key_file":                           fixedConfigurationParameter,
-		"ssl_max_protocol_version":               fixedConfigurationParameter,
-		"ssl_min_protocol_version":               fixedConfigurationParameter,
-		"ssl_passphrase_command":                 fixedConfigurationParameter,
-		"ssl_passphrase_command_supports_reload": fixedConfigurationParameter,
-		"ssl_prefer_server_ciphers":              fixedConfigurationParameter,
-		"stats_temp_directory":                   blockedConfigurationParameter,
-		"synchronous_standby_names":              fixedConfigurationParameter,
-		"syslog_facility":                        blockedConfigurationParameter,
-		"syslog_ident":                           blockedConfigurationParameter,
-		"syslog_sequence_numbers":                blockedConfigurationParameter,
-		"syslog_split_messages":                  blockedConfigurationParameter,
+		"archive_cleanup_command":                {Level: ParameterBlocked, Reason: reasonRecovery},
+		"archive_command":                        {Level: ParameterManaged, Reason: reasonArchiving},
+		"full_page_writes":                       {Level: ParameterManaged, Reason: reasonReplication},
+		"log_destination":                        {Level: ParameterBlocked, Reason: reasonLogging},
+		"log_directory":                          {Level: ParameterBlocked, Reason: reasonLogging},
+		"log_file_mode":                          {Level: ParameterBlocked, Reason: reasonLogging},
+		"log_filename":                           {Level: ParameterBlocked, Reason: reasonLogging},
+		"log_rotation_age":                       {Level: ParameterBlocked, Reason: reasonLogging},
+		"log_rotation_size":                      {Level: ParameterBlocked, Reason: reasonLogging},
+		"log_truncate_on_rotation":               {Level: ParameterBlocked, Reason: reasonLogging},
+		"promote_trigger_file":                   {Level: ParameterBlocked, Reason: reasonReplication},
+		"recovery_end_command":                   {Level: ParameterBlocked, Reason: reasonRecovery},
+		"recovery_min_apply_delay":               {Level: ParameterBlocked, Reason: reasonRecovery},
+		"restart_after_crash":                    {Level: ParameterBlocked, Reason: reasonReplication},
+		"ssl":                                    {Level: ParameterManaged, Reason: reasonTLS},
+		"ssl_ca_file":                            {Level: ParameterManaged, Reason: reasonTLS},
+		"ssl_cert_file":                          {Level: ParameterManaged, Reason: reasonTLS},
+		"ssl_ciphers":                            {Level: ParameterManaged, Reason: reasonTLS},
+		"ssl_crl_file":                           {Level: ParameterManaged, Reason: reasonTLS},
+		"ssl_dh_params_file":                     {Level: ParameterManaged, Reason: reasonTLS},
+		"ssl_ecdh_curve":                         {Level: ParameterManaged, Reason: reasonTLS},
+		"ssl_key_file":                           {Level: ParameterManaged, Reason: reasonTLS},
+		"ssl_max_protocol_version":               {Level: ParameterManaged, Reason: reasonTLS},
+		"ssl_min_protocol_version":               {Level: ParameterManaged, Reason: reasonTLS},
+		"ssl_passphrase_command":                 {Level: ParameterManaged, Reason: reasonTLS},
+		"ssl_passphrase_command_supports_reload": {Level: ParameterManaged, Reason: reasonTLS},
+		"ssl_prefer_server_ciphers":              {Level: ParameterManaged, Reason: reasonTLS},
+		"stats_temp_directory":                   {Level: ParameterBlocked, Reason: reasonFileLocation},
+		"synchronous_standby_names":              {Level: ParameterManaged, Reason: reasonSynchronousReplication},
+		"syslog_facility":                        {Level: ParameterBlocked, Reason: reasonLogging},
+		"syslog_ident":                           {Level: ParameterBlocked, Reason: reasonLogging},
+		"syslog_sequence_numbers":                {Level: ParameterBlocked, Reason: reasonLogging},
+		"syslog_split_messages":                  {Level: ParameterBlocked, Reason: reasonLogging},
 	}
 
 	// CnpgConfigurationSettings contains the settings that represent the
@@ -452,8 +541,10 @@ func (p *PgConfiguration) AddSharedPreloadLibrary(newLibrary string) {
 	if len(newLibrary) == 0 {
 		return
 	}
-	if strings.Contains(p.configs[SharedPreloadLibraries], newLibrary) {
-		return
+	for _, library := range strings.Split(p.configs[SharedPreloadLibraries], ",") {
+		if library == newLibrary {
+			return
+		}
 	}
 	if libraries, ok := p.configs[SharedPreloadLibraries]; ok &&
 		libraries != "" {
@@ -547,12 +638,21 @@ func setDefaultConfigurations(info ConfigurationInfo, configuration *PgConfigura
 			}
 		}
 	}
+
+	// apply settings relative to the flavor of PostgreSQL
+	for key, value := range info.Flavor.GetInfo().DefaultSettings {
+		configuration.OverwriteConfig(key, value)
+	}
 }
 
 // setManagedSharedPreloadLibraries sets all additional preloaded libraries
 func setManagedSharedPreloadLibraries(info ConfigurationInfo, configuration *PgConfiguration) {
+	for _, library := range info.Flavor.GetInfo().SharedPreloadLibraries {
+		configuration.AddSharedPreloadLibrary(library)
+	}
+
 	for _, extension := range ManagedExtensions {
-		if extension.IsUsed(info.UserSettings) {
+		if extension.IsEnabled(info.UserSettings, info.EnabledExtensions) {
 			for _, library := range extension.SharedPreloadLibraries {
 				configuration.AddSharedPreloadLibrary(library)
 			}
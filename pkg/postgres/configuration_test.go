@@ -225,6 +225,28 @@ var _ = Describe("PostgreSQL configuration creation", func() {
 	})
 })
 
+var _ = Describe("fixed configuration parameters", func() {
+	It("has a level and a reason for every parameter", func() {
+		for name, parameter := range FixedConfigurationParameters {
+			Expect(parameter.Level).To(BeElementOf(ParameterManaged, ParameterBlocked), name)
+			Expect(parameter.Reason).ToNot(BeEmpty(), name)
+		}
+	})
+
+	It("blocks the parameters that would break the operator", func() {
+		for _, name := range []string{"port", "data_directory", "listen_addresses", "logging_collector"} {
+			Expect(FixedConfigurationParameters[name].IsBlocked()).To(BeTrue(), name)
+		}
+	})
+
+	It("manages the parameters enforced by the operator", func() {
+		for _, name := range []string{"archive_mode", "archive_command", "hot_standby", "wal_level"} {
+			Expect(FixedConfigurationParameters).To(HaveKey(name))
+			Expect(FixedConfigurationParameters[name].IsBlocked()).To(BeFalse(), name)
+		}
+	})
+})
+
 var _ = Describe("pg_hba.conf generation", func() {
 	specRules := []string{
 		"one",
@@ -320,4 +342,28 @@ var _ = Describe("pgaudit", func() {
 		Expect(libraries).ToNot(ContainElement(""))
 		Expect(libraries).To(ContainElements("pg_stat_statements", "pgaudit"))
 	})
+	It("adds the explicitly enabled extensions to shared_preload_library", func() {
+		info := ConfigurationInfo{
+			Settings:                        CnpgConfigurationSettings,
+			MajorVersion:                    130000,
+			EnabledExtensions:               []string{"pg_stat_statements", "auto_explain"},
+			IncludingMandatory:              true,
+			IncludingSharedPreloadLibraries: true,
+			SyncReplicas:                    0,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		libraries := strings.Split(config.GetConfig(SharedPreloadLibraries), ",")
+		Expect(libraries).To(ConsistOf("pg_stat_statements", "auto_explain"))
+	})
+	It("is enabled when explicitly requested", func() {
+		Expect(pgaudit.IsEnabled(nil, []string{"pgaudit"})).To(BeTrue())
+		Expect(pgaudit.IsEnabled(nil, []string{"pg_stat_statements"})).To(BeFalse())
+		Expect(pgaudit.IsEnabled(map[string]string{"pgaudit.log": "all"}, nil)).To(BeTrue())
+	})
+	It("doesn't confuse libraries having a common prefix", func() {
+		configuration := &PgConfiguration{configs: map[string]string{SharedPreloadLibraries: "pg_stat_statements"}}
+		configuration.AddSharedPreloadLibrary("pg_stat")
+		configuration.AddSharedPreloadLibrary("pg_stat_statements")
+		Expect(configuration.GetConfig(SharedPreloadLibraries)).To(Equal("pg_stat_statements,pg_stat"))
+	})
 })
@@ -0,0 +1,113 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path"
+	"strings"
+)
+
+// Flavor is a distribution of PostgreSQL which can be managed by the operator
+type Flavor string
+
+const (
+	// FlavorPostgreSQL is the community PostgreSQL
+	FlavorPostgreSQL Flavor = "postgresql"
+
+	// FlavorEPAS is EDB Postgres Advanced Server
+	FlavorEPAS Flavor = "epas"
+
+	// FlavorEnvironmentVariable is the environment variable used to
+	// pass the flavor of the instance to the instance manager
+	FlavorEnvironmentVariable = "POSTGRES_FLAVOR"
+)
+
+// FlavorInfo contains the information depending on the PostgreSQL flavor
+type FlavorInfo struct {
+	// The name of the superuser created by initdb
+	SuperUser string
+
+	// The name of the postmaster executable
+	PostmasterName string
+
+	// These settings are applied to the PostgreSQL default configuration
+	// when the user don't specify something different
+	DefaultSettings SettingsCollection
+
+	// The shared preload libraries which are always loaded
+	SharedPreloadLibraries []string
+}
+
+// flavors contains the information about the supported flavors
+var flavors = map[Flavor]FlavorInfo{
+	FlavorPostgreSQL: {
+		SuperUser:      "postgres",
+		PostmasterName: "postgres",
+	},
+	FlavorEPAS: {
+		SuperUser:      "enterprisedb",
+		PostmasterName: "edb-postgres",
+		// The Oracle compatibility settings used by
+		// initdb when creating a redwood-compatible instance
+		DefaultSettings: SettingsCollection{
+			"db_dialect":          "redwood",
+			"edb_redwood_date":    "on",
+			"edb_redwood_strings": "on",
+		},
+		SharedPreloadLibraries: []string{"dbms_pipe", "edb_gen", "dbms_aq"},
+	},
+}
+
+// GetInfo gets the information about this flavor, falling
+// back to the community PostgreSQL for unknown flavors
+func (f Flavor) GetInfo() FlavorInfo {
+	if info, ok := flavors[f]; ok {
+		return info
+	}
+
+	return flavors[FlavorPostgreSQL]
+}
+
+// GetFlavorFromImage detects the flavor of PostgreSQL contained
+// in an image using the name of its repository
+func GetFlavorFromImage(imageName string) Flavor {
+	repository := imageName
+	if idx := strings.Index(repository, "@"); idx >= 0 {
+		repository = repository[:idx]
+	}
+	repository = path.Base(repository)
+	if idx := strings.Index(repository, ":"); idx >= 0 {
+		repository = repository[:idx]
+	}
+
+	if strings.Contains(repository, "postgres-advanced") {
+		return FlavorEPAS
+	}
+
+	return FlavorPostgreSQL
+}
+
+// GetFlavorFromEnvironment gets the flavor of the instance using
+// the environment variable or, when empty, the default one
+func GetFlavorFromEnvironment() Flavor {
+	if flavor := os.Getenv(FlavorEnvironmentVariable); flavor != "" {
+		return Flavor(flavor)
+	}
+
+	return FlavorPostgreSQL
+}
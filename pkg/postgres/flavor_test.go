@@ -0,0 +1,60 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PostgreSQL flavors", func() {
+	It("detects the flavor from the image repository", func() {
+		Expect(GetFlavorFromImage("ghcr.io/cloudnative-pg/postgresql:16.2")).To(Equal(FlavorPostgreSQL))
+		Expect(GetFlavorFromImage("docker.enterprisedb.com/k8s_enterprise/edb-postgres-advanced:16")).
+			To(Equal(FlavorEPAS))
+		Expect(GetFlavorFromImage("quay.io/enterprisedb/edb-postgres-advanced@sha256:abcdef")).
+			To(Equal(FlavorEPAS))
+		Expect(GetFlavorFromImage("registry.example.com/edb-postgres-advanced/postgresql:16")).
+			To(Equal(FlavorPostgreSQL))
+	})
+
+	It("falls back to the community PostgreSQL for unknown flavors", func() {
+		Expect(Flavor("unknown").GetInfo().SuperUser).To(Equal("postgres"))
+		Expect(FlavorEPAS.GetInfo().SuperUser).To(Equal("enterprisedb"))
+		Expect(FlavorEPAS.GetInfo().PostmasterName).To(Equal("edb-postgres"))
+	})
+
+	It("applies the flavor settings to the configuration", func() {
+		info := ConfigurationInfo{
+			Settings:                         CnpgConfigurationSettings,
+			MajorVersion:                     160000,
+			UserSettings:                     map[string]string{"edb_redwood_date": "off"},
+			IncludingSharedPreloadLibraries:  true,
+			AdditionalSharedPreloadLibraries: []string{"pg_failover_slots"},
+			Flavor:                           FlavorEPAS,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("db_dialect")).To(Equal("redwood"))
+		Expect(config.GetConfig("edb_redwood_date")).To(Equal("off"))
+		Expect(config.GetConfig(SharedPreloadLibraries)).To(Equal("pg_failover_slots,dbms_pipe,edb_gen,dbms_aq"))
+
+		info.Flavor = FlavorPostgreSQL
+		config = CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("db_dialect")).To(BeEmpty())
+		Expect(config.GetConfig(SharedPreloadLibraries)).To(Equal("pg_failover_slots"))
+	})
+})
@@ -19,6 +19,7 @@ package postgres
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -26,6 +27,10 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
+// HealthReasonNotStreaming is the reason reported by a degraded
+// replica which is not streaming WAL from its source
+const HealthReasonNotStreaming = "the replica is not streaming WAL"
+
 // PostgresqlStatus defines a status for every instance in the cluster
 type PostgresqlStatus struct {
 	CurrentLsn                LSN        `json:"currentLsn,omitempty"`
@@ -45,6 +50,15 @@ type PostgresqlStatus struct {
 	// populated when MightBeUnavailable reported a healthy status even if it found an error
 	MightBeUnavailableMaskedError string `json:"mightBeUnavailableMaskedError,omitempty"`
 
+	// The parameters whose change requires a restart to be applied
+	// SELECT name FROM pg_settings WHERE pending_restart
+	PendingRestartParameters []string `json:"pendingRestartParameters,omitempty"`
+
+	// The health of the instance, as classified by the instance manager:
+	// `ready` or `degraded`, and the reason why it is not ready
+	Health       string `json:"health,omitempty"`
+	HealthReason string `json:"healthReason,omitempty"`
+
 	// WAL Status
 	// SELECT
 	//		last_archived_wal,
@@ -68,6 +82,10 @@ type PostgresqlStatus struct {
 	// SELECT timeline_id FROM pg_control_checkpoint()
 	TimeLineID int `json:"timeLineID,omitempty"`
 
+	// The LSN of the latest checkpoint
+	// SELECT checkpoint_lsn FROM pg_control_checkpoint()
+	LatestCheckpointLSN LSN `json:"latestCheckpointLSN,omitempty"`
+
 	// This field is set when there is an error while extracting the
 	// status of a Pod
 	Error   error `json:"-"`
@@ -81,6 +99,20 @@ type PostgresqlStatus struct {
 
 	// contains the PgStatReplication rows content.
 	ReplicationInfo PgStatReplicationList `json:"replicationInfo,omitempty"`
+
+	// contains the PgStatWalReceiver row content, nil when
+	// the WAL receiver is not running
+	WalReceiverInfo *PgStatWalReceiver `json:"walReceiverInfo,omitempty"`
+}
+
+// PgStatWalReceiver contains the status of the WAL receiver of a standby
+type PgStatWalReceiver struct {
+	Status             string     `json:"status,omitempty"`
+	SenderHost         string     `json:"senderHost,omitempty"`
+	SlotName           string     `json:"slotName,omitempty"`
+	ReceivedLsn        LSN        `json:"receivedLsn,omitempty"`
+	LatestEndLsn       LSN        `json:"latestEndLsn,omitempty"`
+	LastMsgReceiptTime *time.Time `json:"lastMsgReceiptTime,omitempty"`
 }
 
 // PgStatReplication contains the replications of replicas as reported by the primary instance
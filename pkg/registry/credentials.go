@@ -0,0 +1,93 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// dockerHubHost is the host of the Docker Hub registry, as found in
+// the image names
+const dockerHubHost = "docker.io"
+
+// Credential is the username and the password used to authenticate
+// to a registry
+type Credential struct {
+	Username string
+	Password string
+}
+
+// Credentials are the credentials used to authenticate to the
+// registries, indexed by the registry host
+type Credentials map[string]Credential
+
+// dockerConfigJSON is the content of a `kubernetes.io/dockerconfigjson` secret
+type dockerConfigJSON struct {
+	Auths map[string]dockerConfigEntry `json:"auths"`
+}
+
+// dockerConfigEntry are the credentials of a registry in a
+// `kubernetes.io/dockerconfigjson` secret
+type dockerConfigEntry struct {
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Auth     string `json:"auth,omitempty"`
+}
+
+// ParseDockerConfigJSON parses the content of a `kubernetes.io/dockerconfigjson`
+// secret, adding the credentials it contains to the passed ones
+func (credentials Credentials) ParseDockerConfigJSON(data []byte) error {
+	var config dockerConfigJSON
+	if err := json.Unmarshal(data, &config); err != nil {
+		return fmt.Errorf("while decoding the docker configuration: %w", err)
+	}
+
+	for registry, entry := range config.Auths {
+		credential := Credential{Username: entry.Username, Password: entry.Password}
+		if entry.Auth != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return fmt.Errorf("while decoding the credentials of %s: %w", registry, err)
+			}
+			username, password, found := strings.Cut(string(decoded), ":")
+			if !found {
+				return fmt.Errorf("invalid credentials for %s", registry)
+			}
+			credential = Credential{Username: username, Password: password}
+		}
+
+		credentials[normalizeRegistryHost(registry)] = credential
+	}
+
+	return nil
+}
+
+// normalizeRegistryHost gets the host of a registry from the keys used in
+// the docker configuration, which can be URLs like `https://index.docker.io/v1/`
+func normalizeRegistryHost(registry string) string {
+	host := strings.TrimPrefix(strings.TrimPrefix(registry, "https://"), "http://")
+	host, _, _ = strings.Cut(host, "/")
+
+	switch host {
+	case "index.docker.io", "registry-1.docker.io":
+		return dockerHubHost
+	}
+	return host
+}
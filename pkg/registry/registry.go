@@ -0,0 +1,352 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package registry contains a minimal client for the container registries,
+// used to inspect the platforms provided by an image
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// The media types of the manifests
+const (
+	mediaTypeDockerManifestList = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeDockerManifest     = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeOCIImageIndex      = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIImageManifest   = "application/vnd.oci.image.manifest.v1+json"
+)
+
+// dockerHubAPIHost is the host serving the API of the Docker Hub registry
+const dockerHubAPIHost = "registry-1.docker.io"
+
+// maxResponseSize is the maximum size of a manifest or of an image
+// configuration read from a registry
+const maxResponseSize = 4 * 1024 * 1024
+
+// ErrUnauthorized is returned when the registry refuses the credentials
+var ErrUnauthorized = errors.New("unauthorized")
+
+// Platform is the operating system and the architecture of an image
+type Platform struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+}
+
+// manifest contains the fields of an image manifest or of an
+// image index needed to detect the platforms of an image
+type manifest struct {
+	MediaType string `json:"mediaType"`
+	Manifests []struct {
+		Platform *Platform `json:"platform"`
+	} `json:"manifests"`
+	Config struct {
+		Digest string `json:"digest"`
+	} `json:"config"`
+}
+
+// Client inspects the images stored in the registries
+type Client struct {
+	httpClient  *http.Client
+	credentials Credentials
+
+	// scheme is the protocol used to connect to the registries
+	scheme string
+}
+
+// NewClient creates a new registry client using the passed HTTP client
+// and credentials
+func NewClient(httpClient *http.Client, credentials Credentials) *Client {
+	return &Client{
+		httpClient:  httpClient,
+		credentials: credentials,
+		scheme:      "https",
+	}
+}
+
+// GetArchitectures gets the sorted list of the Linux architectures provided
+// by an image, reading its manifest list, or the configuration of the
+// image when it is built for a single platform
+func (c *Client) GetArchitectures(ctx context.Context, imageName string) ([]string, error) {
+	platforms, err := c.GetPlatforms(ctx, imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	architectures := make(map[string]bool, len(platforms))
+	for _, platform := range platforms {
+		if platform.OS == "linux" && platform.Architecture != "" {
+			architectures[platform.Architecture] = true
+		}
+	}
+
+	result := make([]string, 0, len(architectures))
+	for architecture := range architectures {
+		result = append(result, architecture)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// GetPlatforms gets the platforms provided by an image
+func (c *Client) GetPlatforms(ctx context.Context, imageName string) ([]Platform, error) {
+	host, repository, reference := parseImageName(imageName)
+
+	var imageManifest manifest
+	contentType, err := c.get(ctx, host, repository, "manifests/"+reference, []string{
+		mediaTypeDockerManifestList,
+		mediaTypeOCIImageIndex,
+		mediaTypeDockerManifest,
+		mediaTypeOCIImageManifest,
+	}, &imageManifest)
+	if err != nil {
+		return nil, err
+	}
+
+	mediaType := imageManifest.MediaType
+	if mediaType == "" {
+		mediaType = contentType
+	}
+
+	switch mediaType {
+	case mediaTypeDockerManifestList, mediaTypeOCIImageIndex:
+		platforms := make([]Platform, 0, len(imageManifest.Manifests))
+		for _, item := range imageManifest.Manifests {
+			// The attestation manifests have an unknown platform
+			if item.Platform != nil && item.Platform.Architecture != "unknown" {
+				platforms = append(platforms, *item.Platform)
+			}
+		}
+		return platforms, nil
+
+	case mediaTypeDockerManifest, mediaTypeOCIImageManifest:
+		if imageManifest.Config.Digest == "" {
+			return nil, fmt.Errorf("the manifest of %s has no configuration", imageName)
+		}
+		var platform Platform
+		if _, err := c.get(ctx, host, repository, "blobs/"+imageManifest.Config.Digest, nil, &platform); err != nil {
+			return nil, err
+		}
+		return []Platform{platform}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported manifest media type %q for %s", mediaType, imageName)
+	}
+}
+
+// parseImageName gets the host of the registry, the repository and the
+// tag or digest of an image
+func parseImageName(imageName string) (host, repository, reference string) {
+	parsed := utils.NewReference(imageName)
+	host, repository, _ = strings.Cut(parsed.Name, "/")
+
+	reference = parsed.Tag
+	if parsed.Digest != "" {
+		reference = "sha256:" + parsed.Digest
+	}
+
+	return host, repository, reference
+}
+
+// get reads a JSON document from the API of a registry, authenticating
+// when requested, and returns its content type
+func (c *Client) get(
+	ctx context.Context,
+	host, repository, path string,
+	accept []string,
+	result interface{},
+) (string, error) {
+	apiHost := host
+	if host == dockerHubHost {
+		apiHost = dockerHubAPIHost
+	}
+	requestURL := fmt.Sprintf("%s://%s/v2/%s/%s", c.scheme, apiHost, repository, path)
+
+	response, err := c.do(ctx, requestURL, accept, "")
+	if err != nil {
+		return "", err
+	}
+
+	if response.StatusCode == http.StatusUnauthorized {
+		challenge := response.Header.Get("WWW-Authenticate")
+		_ = response.Body.Close()
+
+		authorization, err := c.getAuthorization(ctx, host, challenge)
+		if err != nil {
+			return "", err
+		}
+		if response, err = c.do(ctx, requestURL, accept, authorization); err != nil {
+			return "", err
+		}
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return "", fmt.Errorf("while reading %s: %w", requestURL, ErrUnauthorized)
+	default:
+		return "", fmt.Errorf("while reading %s: unexpected status %s", requestURL, response.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(response.Body, maxResponseSize))
+	if err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(body, result); err != nil {
+		return "", fmt.Errorf("while decoding %s: %w", requestURL, err)
+	}
+
+	contentType, _, _ := strings.Cut(response.Header.Get("Content-Type"), ";")
+	return strings.TrimSpace(contentType), nil
+}
+
+// do executes a GET request
+func (c *Client) do(ctx context.Context, requestURL string, accept []string, authorization string) (
+	*http.Response,
+	error,
+) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(accept) > 0 {
+		request.Header.Set("Accept", strings.Join(accept, ", "))
+	}
+	if authorization != "" {
+		request.Header.Set("Authorization", authorization)
+	}
+
+	return c.httpClient.Do(request)
+}
+
+// getAuthorization gets the value of the Authorization header answering
+// the challenge of a registry, requesting a token when needed
+func (c *Client) getAuthorization(ctx context.Context, host, challenge string) (string, error) {
+	credential, hasCredential := c.credentials[host]
+
+	scheme, parameters := parseChallenge(challenge)
+	switch scheme {
+	case "basic":
+		if !hasCredential {
+			return "", fmt.Errorf("no credentials for %s: %w", host, ErrUnauthorized)
+		}
+		request, _ := http.NewRequest(http.MethodGet, "/", nil)
+		request.SetBasicAuth(credential.Username, credential.Password)
+		return request.Header.Get("Authorization"), nil
+
+	case "bearer":
+		token, err := c.getToken(ctx, parameters, credential, hasCredential)
+		if err != nil {
+			return "", err
+		}
+		return "Bearer " + token, nil
+
+	default:
+		return "", fmt.Errorf("unsupported authentication challenge %q from %s", challenge, host)
+	}
+}
+
+// getToken requests a bearer token to the authorization server of a registry
+func (c *Client) getToken(
+	ctx context.Context,
+	parameters map[string]string,
+	credential Credential,
+	hasCredential bool,
+) (string, error) {
+	realm, err := url.Parse(parameters["realm"])
+	if err != nil || realm.Host == "" {
+		return "", fmt.Errorf("invalid authentication realm %q", parameters["realm"])
+	}
+
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if value := parameters[key]; value != "" {
+			query.Set(key, value)
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if hasCredential {
+		request.SetBasicAuth(credential.Username, credential.Password)
+	}
+
+	response, err := c.httpClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("while requesting a token to %s: %w", realm.Host, ErrUnauthorized)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(io.LimitReader(response.Body, maxResponseSize)).Decode(&token); err != nil {
+		return "", fmt.Errorf("while decoding the token from %s: %w", realm.Host, err)
+	}
+
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
+
+// parseChallenge parses the value of a WWW-Authenticate header, like
+// `Bearer realm="https://auth.example.com/token",service="registry"`,
+// returning the lowercase scheme and its parameters
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	parameters := make(map[string]string)
+
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		var key string
+		key, rest, _ = strings.Cut(rest, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+			rest = strings.TrimPrefix(strings.TrimSpace(rest), ",")
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		parameters[key] = strings.TrimSpace(value)
+	}
+
+	return strings.ToLower(scheme), parameters
+}
@@ -0,0 +1,215 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	imageIndex = `{
+  "mediaType": "application/vnd.oci.image.index.v1+json",
+  "manifests": [
+    {"platform": {"os": "linux", "architecture": "arm64"}},
+    {"platform": {"os": "linux", "architecture": "amd64"}},
+    {"platform": {"os": "unknown", "architecture": "unknown"}},
+    {"platform": {"os": "windows", "architecture": "amd64"}},
+    {"platform": {"os": "linux", "architecture": "amd64"}}
+  ]
+}`
+
+	imageManifest = `{
+  "mediaType": "application/vnd.docker.distribution.manifest.v2+json",
+  "config": {"digest": "sha256:0123"}
+}`
+
+	imageConfiguration = `{"os": "linux", "architecture": "ppc64le"}`
+)
+
+// newTestServer creates a registry serving the image index on the
+// `multi` repository and the single platform manifest on the `single` one
+func newTestServer(handler func(w http.ResponseWriter, r *http.Request) bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if handler != nil && !handler(w, r) {
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/multi/manifests/1.0":
+			_, _ = fmt.Fprint(w, imageIndex)
+		case "/v2/single/manifests/1.0":
+			_, _ = fmt.Fprint(w, imageManifest)
+		case "/v2/single/blobs/sha256:0123":
+			_, _ = fmt.Fprint(w, imageConfiguration)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func newTestClient(server *httptest.Server, credentials Credentials) (*Client, string) {
+	client := NewClient(server.Client(), credentials)
+	client.scheme = "http"
+	return client, serverHost(server)
+}
+
+var _ = Describe("image architectures", func() {
+	ctx := context.Background()
+
+	It("reads the architectures from an image index", func() {
+		server := newTestServer(nil)
+		defer server.Close()
+		client, host := newTestClient(server, nil)
+
+		architectures, err := client.GetArchitectures(ctx, host+"/multi:1.0")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(architectures).To(Equal([]string{"amd64", "arm64"}))
+	})
+
+	It("reads the architecture from the configuration of a single platform image", func() {
+		server := newTestServer(nil)
+		defer server.Close()
+		client, host := newTestClient(server, nil)
+
+		architectures, err := client.GetArchitectures(ctx, host+"/single:1.0")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(architectures).To(Equal([]string{"ppc64le"}))
+	})
+
+	It("fails when the image doesn't exist", func() {
+		server := newTestServer(nil)
+		defer server.Close()
+		client, host := newTestClient(server, nil)
+
+		_, err := client.GetArchitectures(ctx, host+"/missing:1.0")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("uses basic authentication when requested", func() {
+		expected := "Basic " + base64.StdEncoding.EncodeToString([]byte("user:secret"))
+		server := newTestServer(func(w http.ResponseWriter, r *http.Request) bool {
+			if r.Header.Get("Authorization") != expected {
+				w.Header().Set("WWW-Authenticate", `Basic realm="registry"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return false
+			}
+			return true
+		})
+		defer server.Close()
+
+		client, host := newTestClient(server, Credentials{serverHost(server): {Username: "user", Password: "secret"}})
+		architectures, err := client.GetArchitectures(ctx, host+"/multi:1.0")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(architectures).To(Equal([]string{"amd64", "arm64"}))
+
+		client, _ = newTestClient(server, nil)
+		_, err = client.GetArchitectures(ctx, host+"/multi:1.0")
+		Expect(err).To(MatchError(ErrUnauthorized))
+	})
+
+	It("requests a bearer token when requested", func() {
+		var server *httptest.Server
+		server = newTestServer(func(w http.ResponseWriter, r *http.Request) bool {
+			if r.URL.Path == "/token" {
+				Expect(r.URL.Query().Get("scope")).To(Equal("repository:multi:pull"))
+				Expect(r.URL.Query().Get("service")).To(Equal("test"))
+				_, _ = fmt.Fprint(w, `{"token": "abc"}`)
+				return false
+			}
+			if r.Header.Get("Authorization") != "Bearer abc" {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+					`Bearer realm="%s/token",service="test",scope="repository:multi:pull"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return false
+			}
+			return true
+		})
+		defer server.Close()
+		client, host := newTestClient(server, nil)
+
+		architectures, err := client.GetArchitectures(ctx, host+"/multi:1.0")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(architectures).To(Equal([]string{"amd64", "arm64"}))
+	})
+})
+
+var _ = Describe("authentication challenges", func() {
+	It("parses bearer challenges", func() {
+		scheme, parameters := parseChallenge(
+			`Bearer realm="https://auth.docker.io/token",service="registry.docker.io",scope="repository:a/b:pull"`)
+		Expect(scheme).To(Equal("bearer"))
+		Expect(parameters).To(Equal(map[string]string{
+			"realm":   "https://auth.docker.io/token",
+			"service": "registry.docker.io",
+			"scope":   "repository:a/b:pull",
+		}))
+	})
+
+	It("parses basic challenges", func() {
+		scheme, parameters := parseChallenge(`Basic realm=registry`)
+		Expect(scheme).To(Equal("basic"))
+		Expect(parameters).To(HaveKeyWithValue("realm", "registry"))
+	})
+})
+
+var _ = Describe("image names", func() {
+	It("maps the images to the registry API", func() {
+		host, repository, reference := parseImageName("postgres:14")
+		Expect(host).To(Equal("docker.io"))
+		Expect(repository).To(Equal("library/postgres"))
+		Expect(reference).To(Equal("14"))
+
+		host, repository, reference = parseImageName("ghcr.io/cloudnative-pg/postgresql@sha256:abcd")
+		Expect(host).To(Equal("ghcr.io"))
+		Expect(repository).To(Equal("cloudnative-pg/postgresql"))
+		Expect(reference).To(Equal("sha256:abcd"))
+	})
+})
+
+var _ = Describe("docker configuration", func() {
+	It("parses the credentials", func() {
+		credentials := make(Credentials)
+		err := credentials.ParseDockerConfigJSON([]byte(fmt.Sprintf(`{"auths": {
+			"https://index.docker.io/v1/": {"auth": "%s"},
+			"quay.io": {"username": "user", "password": "pwd"}
+		}}`, base64.StdEncoding.EncodeToString([]byte("hub:secret")))))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(credentials).To(Equal(Credentials{
+			"docker.io": {Username: "hub", Password: "secret"},
+			"quay.io":   {Username: "user", Password: "pwd"},
+		}))
+	})
+
+	It("rejects invalid content", func() {
+		credentials := make(Credentials)
+		Expect(credentials.ParseDockerConfigJSON([]byte("{"))).ToNot(Succeed())
+		Expect(credentials.ParseDockerConfigJSON([]byte(`{"auths": {"a": {"auth": "bm9jb2xvbg=="}}}`))).
+			ToNot(Succeed())
+	})
+})
+
+func serverHost(server *httptest.Server) string {
+	return strings.TrimPrefix(server.URL, "http://")
+}
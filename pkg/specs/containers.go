@@ -23,6 +23,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // createBootstrapContainer creates the init container bootstrapping the operator
@@ -30,7 +31,7 @@ import (
 func createBootstrapContainer(cluster apiv1.Cluster) corev1.Container {
 	container := corev1.Container{
 		Name:            BootstrapControllerContainerName,
-		Image:           configuration.Current.OperatorImageName,
+		Image:           configuration.Current().OperatorImageName,
 		ImagePullPolicy: cluster.Spec.ImagePullPolicy,
 		Command: []string{
 			"/manager",
@@ -39,7 +40,7 @@ func createBootstrapContainer(cluster apiv1.Cluster) corev1.Container {
 		},
 		VolumeMounts:    createPostgresVolumeMounts(cluster),
 		Resources:       cluster.Spec.Resources,
-		SecurityContext: CreateContainerSecurityContext(),
+		SecurityContext: CreateContainerSecurityContext(cluster.GetSeccompProfile()),
 	}
 
 	addManagerLoggingOptions(cluster, &container)
@@ -56,10 +57,16 @@ func addManagerLoggingOptions(cluster apiv1.Cluster, container *corev1.Container
 }
 
 // CreateContainerSecurityContext initializes container security context
-func CreateContainerSecurityContext() *corev1.SecurityContext {
+func CreateContainerSecurityContext(seccompProfile *corev1.SeccompProfile) *corev1.SecurityContext {
 	trueValue := true
 	falseValue := false
 
+	// Under Openshift the seccomp profile is set by the
+	// restricted security context constraint
+	if utils.HaveSecurityContextConstraints() {
+		seccompProfile = nil
+	}
+
 	return &corev1.SecurityContext{
 		Capabilities: &corev1.Capabilities{
 			Drop: []corev1.Capability{
@@ -70,5 +77,6 @@ func CreateContainerSecurityContext() *corev1.SecurityContext {
 		RunAsNonRoot:             &trueValue,
 		ReadOnlyRootFilesystem:   &trueValue,
 		AllowPrivilegeEscalation: &falseValue,
+		SeccompProfile:           seccompProfile,
 	}
 }
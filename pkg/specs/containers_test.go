@@ -55,10 +55,14 @@ var _ = Describe("Bootstrap Container creation", func() {
 
 var _ = Describe("Container Security Context creation", func() {
 	It("create a Security Context for the Container", func() {
-		securityContext := CreateContainerSecurityContext()
+		seccompProfile := &corev1.SeccompProfile{
+			Type: corev1.SeccompProfileTypeRuntimeDefault,
+		}
+		securityContext := CreateContainerSecurityContext(seccompProfile)
 		Expect(*securityContext.RunAsNonRoot).To(BeTrue())
 		Expect(*securityContext.AllowPrivilegeEscalation).To(BeFalse())
 		Expect(*securityContext.Privileged).To(BeFalse())
 		Expect(*securityContext.ReadOnlyRootFilesystem).To(BeTrue())
+		Expect(securityContext.SeccompProfile).To(Equal(seccompProfile))
 	})
 })
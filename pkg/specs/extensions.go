@@ -0,0 +1,193 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	// ExtensionsVolumeName is the name of the volume containing the
+	// PostgreSQL installation merged with the extension images
+	ExtensionsVolumeName = "extensions"
+
+	// ExtensionsMountPath is where the extensions volume is mounted
+	// in the init containers
+	ExtensionsMountPath = "/extensions"
+
+	// ExtensionsShareDirectory is the directory of the extensions volume
+	// containing the PostgreSQL share directory
+	ExtensionsShareDirectory = "share"
+
+	// ExtensionsLibDirectory is the directory of the extensions volume
+	// containing the PostgreSQL library directory
+	ExtensionsLibDirectory = "lib"
+
+	// ExtensionsSetupContainerName is the name of the init container
+	// copying the PostgreSQL installation into the extensions volume
+	ExtensionsSetupContainerName = "extensions-setup"
+
+	// ExtensionContainerPrefix is the prefix of the names of the init
+	// containers copying the files of each extension image
+	ExtensionContainerPrefix = "extension-"
+)
+
+// GetPostgresInstallationPaths gets the share and library directories
+// of the PostgreSQL installation in the image, following the layout
+// of the Debian based PostgreSQL images
+func GetPostgresInstallationPaths(majorVersion int) (shareDir string, libDir string) {
+	return fmt.Sprintf("/usr/share/postgresql/%d", majorVersion),
+		fmt.Sprintf("/usr/lib/postgresql/%d/lib", majorVersion)
+}
+
+// getExtensionsMajorVersion gets the PostgreSQL major version used to
+// locate the installation to be merged with the extension images, or
+// false if the cluster doesn't use extension images
+func getExtensionsMajorVersion(cluster apiv1.Cluster) (int, bool) {
+	if len(cluster.Spec.PostgresConfiguration.ExtensionImages) == 0 {
+		return 0, false
+	}
+
+	version, err := cluster.GetPostgresqlVersion()
+	if err != nil {
+		return 0, false
+	}
+
+	return version / 10000, true
+}
+
+// createExtensionsInitContainers creates the init containers merging
+// the PostgreSQL installation of the image with the files of the
+// extension images into the extensions volume
+func createExtensionsInitContainers(cluster apiv1.Cluster) []corev1.Container {
+	majorVersion, ok := getExtensionsMajorVersion(cluster)
+	if !ok {
+		return nil
+	}
+
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "scratch-data",
+			MountPath: postgres.ScratchDataDirectory,
+		},
+		{
+			Name:      ExtensionsVolumeName,
+			MountPath: ExtensionsMountPath,
+		},
+	}
+
+	shareDir, libDir := GetPostgresInstallationPaths(majorVersion)
+	containers := []corev1.Container{
+		{
+			Name:            ExtensionsSetupContainerName,
+			Image:           cluster.GetImageName(),
+			ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+			Command: []string{
+				"/controller/manager",
+				"extensions",
+				"setup",
+				"--share-dir", shareDir,
+				"--lib-dir", libDir,
+			},
+			VolumeMounts:    volumeMounts,
+			Resources:       cluster.Spec.Resources,
+			SecurityContext: CreateContainerSecurityContext(cluster.GetSeccompProfile()),
+		},
+	}
+
+	for _, extensionImage := range cluster.Spec.PostgresConfiguration.ExtensionImages {
+		containers = append(containers, corev1.Container{
+			Name:            ExtensionContainerPrefix + extensionImage.Name,
+			Image:           extensionImage.Image,
+			ImagePullPolicy: extensionImage.ImagePullPolicy,
+			Command: []string{
+				"/controller/manager",
+				"extensions",
+				"install",
+			},
+			VolumeMounts:    volumeMounts,
+			Resources:       cluster.Spec.Resources,
+			SecurityContext: CreateContainerSecurityContext(cluster.GetSeccompProfile()),
+		})
+	}
+
+	for idx := range containers {
+		addManagerLoggingOptions(cluster, &containers[idx])
+	}
+
+	return containers
+}
+
+// createExtensionsVolumeMounts creates the volume mounts replacing the
+// PostgreSQL installation of the image with the one in the extensions volume
+func createExtensionsVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
+	majorVersion, ok := getExtensionsMajorVersion(cluster)
+	if !ok {
+		return nil
+	}
+
+	shareDir, libDir := GetPostgresInstallationPaths(majorVersion)
+	return []corev1.VolumeMount{
+		{
+			Name:      ExtensionsVolumeName,
+			MountPath: shareDir,
+			SubPath:   ExtensionsShareDirectory,
+			ReadOnly:  true,
+		},
+		{
+			Name:      ExtensionsVolumeName,
+			MountPath: libDir,
+			SubPath:   ExtensionsLibDirectory,
+			ReadOnly:  true,
+		},
+	}
+}
+
+// createExtensionsVolumes creates the extensions volume
+func createExtensionsVolumes(cluster apiv1.Cluster) []corev1.Volume {
+	if _, ok := getExtensionsMajorVersion(cluster); !ok {
+		return nil
+	}
+
+	return []corev1.Volume{
+		{
+			Name: ExtensionsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+}
+
+// GetExtensionImages gets the images of the init containers copying
+// the extensions of a Pod, indexed by the name of the extension
+func GetExtensionImages(pod corev1.Pod) map[string]string {
+	images := make(map[string]string)
+	for _, container := range pod.Spec.InitContainers {
+		if strings.HasPrefix(container.Name, ExtensionContainerPrefix) {
+			images[strings.TrimPrefix(container.Name, ExtensionContainerPrefix)] = container.Image
+		}
+	}
+
+	return images
+}
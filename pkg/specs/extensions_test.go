@@ -0,0 +1,120 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Extension images", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			ImageName: "ghcr.io/cloudnative-pg/postgresql:16.2",
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				ExtensionImages: []apiv1.ExtensionImage{
+					{Name: "pgvector", Image: "ghcr.io/example/pgvector:0.6.0"},
+					{Name: "postgis", Image: "ghcr.io/example/postgis:3.4"},
+				},
+			},
+		},
+	}
+
+	It("doesn't change the Pods when there are no extension images", func() {
+		cluster := apiv1.Cluster{Spec: apiv1.ClusterSpec{ImageName: "ghcr.io/cloudnative-pg/postgresql:16.2"}}
+		Expect(createExtensionsInitContainers(cluster)).To(BeEmpty())
+		Expect(createExtensionsVolumes(cluster)).To(BeEmpty())
+		Expect(createExtensionsVolumeMounts(cluster)).To(BeEmpty())
+	})
+
+	It("creates the init containers merging the extensions", func() {
+		containers := createExtensionsInitContainers(cluster)
+		Expect(containers).To(HaveLen(3))
+
+		Expect(containers[0].Name).To(Equal(ExtensionsSetupContainerName))
+		Expect(containers[0].Image).To(Equal("ghcr.io/cloudnative-pg/postgresql:16.2"))
+		Expect(containers[0].Command).To(Equal([]string{
+			"/controller/manager", "extensions", "setup",
+			"--share-dir", "/usr/share/postgresql/16",
+			"--lib-dir", "/usr/lib/postgresql/16/lib",
+		}))
+
+		Expect(containers[1].Name).To(Equal("extension-pgvector"))
+		Expect(containers[1].Image).To(Equal("ghcr.io/example/pgvector:0.6.0"))
+		Expect(containers[1].Command).To(Equal([]string{"/controller/manager", "extensions", "install"}))
+		Expect(containers[2].Name).To(Equal("extension-postgis"))
+		Expect(containers[2].Image).To(Equal("ghcr.io/example/postgis:3.4"))
+	})
+
+	It("replaces the PostgreSQL installation with the extensions volume", func() {
+		Expect(createExtensionsVolumes(cluster)).To(HaveLen(1))
+		Expect(createExtensionsVolumeMounts(cluster)).To(ConsistOf(
+			corev1.VolumeMount{
+				Name:      ExtensionsVolumeName,
+				MountPath: "/usr/share/postgresql/16",
+				SubPath:   ExtensionsShareDirectory,
+				ReadOnly:  true,
+			},
+			corev1.VolumeMount{
+				Name:      ExtensionsVolumeName,
+				MountPath: "/usr/lib/postgresql/16/lib",
+				SubPath:   ExtensionsLibDirectory,
+				ReadOnly:  true,
+			},
+		))
+	})
+
+	It("gets the extension images used by a Pod", func() {
+		pod := corev1.Pod{
+			Spec: corev1.PodSpec{
+				InitContainers: createExtensionsInitContainers(cluster),
+			},
+		}
+		Expect(GetExtensionImages(pod)).To(Equal(map[string]string{
+			"pgvector": "ghcr.io/example/pgvector:0.6.0",
+			"postgis":  "ghcr.io/example/postgis:3.4",
+		}))
+	})
+
+	It("installs the extensions in the bootstrap jobs too", func() {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Bootstrap = &apiv1.BootstrapConfiguration{
+			InitDB:       &apiv1.BootstrapInitDB{},
+			Recovery:     &apiv1.BootstrapRecovery{},
+			PgBaseBackup: &apiv1.BootstrapPgBaseBackup{},
+		}
+		jobs := []*batchv1.Job{
+			CreatePrimaryJobViaInitdb(*cluster, 1),
+			CreatePrimaryJobViaRecovery(*cluster, 1, nil),
+			CreatePrimaryJobViaPgBaseBackup(*cluster, 1),
+			JoinReplicaInstance(*cluster, 2),
+		}
+		for _, job := range jobs {
+			podSpec := job.Spec.Template.Spec
+			Expect(GetExtensionImages(corev1.Pod{Spec: podSpec})).To(HaveLen(2))
+			Expect(podSpec.Volumes).To(ContainElement(HaveField("Name", ExtensionsVolumeName)))
+			Expect(podSpec.Containers[0].VolumeMounts).To(ContainElements(createExtensionsVolumeMounts(*cluster)))
+		}
+	})
+})
@@ -34,6 +34,14 @@ const (
 	// postInitApplicationSQLRefsFolder points to the folder of
 	// postInitApplicationSQL files in the primary job with initdb.
 	postInitApplicationSQLRefsFolder = "/etc/post-init-application-sql"
+
+	// postInitSQLRefsFolder points to the folder of
+	// postInitSQL files in the primary job with initdb.
+	postInitSQLRefsFolder = "/etc/post-init-sql"
+
+	// postInitTemplateSQLRefsFolder points to the folder of
+	// postInitTemplateSQL files in the primary job with initdb.
+	postInitTemplateSQLRefsFolder = "/etc/post-init-template-sql"
 )
 
 // CreatePrimaryJobViaInitdb creates a new primary instance in a Pod
@@ -86,6 +94,16 @@ func CreatePrimaryJobViaInitdb(cluster apiv1.Cluster, nodeSerial int) *batchv1.J
 			"--post-init-application-sql-refs-folder", postInitApplicationSQLRefsFolder)
 	}
 
+	if cluster.ShouldInitDBRunPostInitSQLRefs() {
+		initCommand = append(initCommand,
+			"--post-init-sql-refs-folder", postInitSQLRefsFolder)
+	}
+
+	if cluster.ShouldInitDBRunPostInitTemplateSQLRefs() {
+		initCommand = append(initCommand,
+			"--post-init-template-sql-refs-folder", postInitTemplateSQLRefsFolder)
+	}
+
 	return createPrimaryJob(cluster, nodeSerial, "initdb", initCommand)
 }
 
@@ -101,6 +119,7 @@ func buildInitDBFlags(cluster apiv1.Cluster) (initCommand []string) {
 			"namespace", cluster.Namespace)
 
 		options = append(options, config.Options...)
+		options = append(options, buildTDEInitDBFlags(cluster)...)
 		initCommand = append(
 			initCommand,
 			"--initdb-flags",
@@ -120,9 +139,16 @@ func buildInitDBFlags(cluster apiv1.Cluster) (initCommand []string) {
 	if localeCType := config.LocaleCType; localeCType != "" {
 		options = append(options, fmt.Sprintf("--lc-ctype=%s", localeCType))
 	}
+	if localeProvider := config.LocaleProvider; localeProvider != "" {
+		options = append(options, fmt.Sprintf("--locale-provider=%s", localeProvider))
+	}
+	if icuLocale := config.IcuLocale; icuLocale != "" {
+		options = append(options, fmt.Sprintf("--icu-locale=%s", icuLocale))
+	}
 	if walSegmentSize := config.WalSegmentSize; walSegmentSize != 0 && utils.IsPowerOfTwo(walSegmentSize) {
 		options = append(options, fmt.Sprintf("--wal-segsize=%v", walSegmentSize))
 	}
+	options = append(options, buildTDEInitDBFlags(cluster)...)
 	initCommand = append(
 		initCommand,
 		"--initdb-flags",
@@ -131,6 +157,21 @@ func buildInitDBFlags(cluster apiv1.Cluster) (initCommand []string) {
 	return initCommand
 }
 
+// buildTDEInitDBFlags builds the initdb flags encrypting the data
+// files when the Transparent Data Encryption is enabled
+func buildTDEInitDBFlags(cluster apiv1.Cluster) []string {
+	tde := cluster.Spec.PostgresConfiguration.TDE
+	if !tde.IsEnabled() {
+		return nil
+	}
+
+	return []string{
+		"--data-encryption",
+		fmt.Sprintf("--key-wrap-command=%s", tde.GetWrapCommand()),
+		fmt.Sprintf("--key-unwrap-command=%s", tde.GetUnwrapCommand()),
+	}
+}
+
 // CreatePrimaryJobViaRecovery creates a new primary instance in a Pod
 func CreatePrimaryJobViaRecovery(cluster apiv1.Cluster, nodeSerial int, backup *apiv1.Backup) *batchv1.Job {
 	initCommand := []string{
@@ -185,6 +226,20 @@ func CreatePrimaryJobViaPgBaseBackup(cluster apiv1.Cluster, nodeSerial int) *bat
 	return createPrimaryJob(cluster, nodeSerial, "pgbasebackup", initCommand)
 }
 
+// CreatePrimaryJobViaAdopt creates a new primary instance in a Pod,
+// adopting the existing PVCs of the instance with the passed serial
+func CreatePrimaryJobViaAdopt(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job {
+	initCommand := []string{
+		"/controller/manager",
+		"instance",
+		"adopt",
+	}
+
+	initCommand = append(initCommand, buildCommonInitJobFlags(cluster)...)
+
+	return createPrimaryJob(cluster, nodeSerial, "adopt", initCommand)
+}
+
 // JoinReplicaInstance create a new PostgreSQL node, copying the contents from another Pod
 func JoinReplicaInstance(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job {
 	initCommand := []string{
@@ -235,9 +290,9 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role string, initCo
 				Spec: corev1.PodSpec{
 					Hostname:  jobName,
 					Subdomain: cluster.GetServiceAnyName(),
-					InitContainers: []corev1.Container{
-						createBootstrapContainer(cluster),
-					},
+					InitContainers: append(
+						[]corev1.Container{createBootstrapContainer(cluster)},
+						createExtensionsInitContainers(cluster)...),
 					Containers: []corev1.Container{
 						{
 							Name:            role,
@@ -245,15 +300,22 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role string, initCo
 							ImagePullPolicy: cluster.Spec.ImagePullPolicy,
 							Env:             createEnvVarPostgresContainer(cluster, instanceName),
 							Command:         initCommand,
-							VolumeMounts:    createPostgresVolumeMounts(cluster),
+							VolumeMounts: append(createPostgresVolumeMounts(cluster),
+								createExtensionsVolumeMounts(cluster)...),
 							Resources:       cluster.Spec.Resources,
-							SecurityContext: CreateContainerSecurityContext(),
+							SecurityContext: CreateContainerSecurityContext(cluster.GetSeccompProfile()),
 						},
 					},
-					Volumes:            createPostgresVolumes(cluster, instanceName),
-					SecurityContext:    CreatePodSecurityContext(cluster.GetPostgresUID(), cluster.GetPostgresGID()),
-					Affinity:           CreateAffinitySection(cluster.Name, cluster.Spec.Affinity),
+					Volumes: append(createPostgresVolumes(cluster, instanceName),
+						createExtensionsVolumes(cluster)...),
+					SecurityContext: CreatePodSecurityContext(
+						cluster.GetSeccompProfile(),
+						cluster.GetPostgresUID(),
+						cluster.GetPostgresGID(),
+					),
+					Affinity:           CreateClusterAffinitySection(&cluster),
 					Tolerations:        cluster.Spec.Affinity.Tolerations,
+					PriorityClassName:  cluster.GetPriorityClassName(instanceName),
 					ServiceAccountName: cluster.Name,
 					RestartPolicy:      corev1.RestartPolicyNever,
 					NodeSelector:       cluster.Spec.Affinity.NodeSelector,
@@ -270,13 +332,28 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role string, initCo
 	}
 
 	if cluster.ShouldInitDBRunPostInitApplicationSQLRefs() {
-		volumes, volumeMounts := createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
-			cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs,
-		)
-		job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, volumes...)
-		job.Spec.Template.Spec.Containers[0].VolumeMounts = append(
-			job.Spec.Template.Spec.Containers[0].VolumeMounts, volumeMounts...)
+		addSQLRefsToJob(job, postInitApplicationSQLRefsFolder,
+			cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs)
+	}
+
+	if cluster.ShouldInitDBRunPostInitSQLRefs() {
+		addSQLRefsToJob(job, postInitSQLRefsFolder,
+			cluster.Spec.Bootstrap.InitDB.PostInitSQLRefs)
+	}
+
+	if cluster.ShouldInitDBRunPostInitTemplateSQLRefs() {
+		addSQLRefsToJob(job, postInitTemplateSQLRefsFolder,
+			cluster.Spec.Bootstrap.InitDB.PostInitTemplateSQLRefs)
 	}
 
 	return job
 }
+
+// addSQLRefsToJob projects the SQL files referenced in refs into the
+// passed folder of the main container of the job
+func addSQLRefsToJob(job *batchv1.Job, folder string, refs *apiv1.SQLRefs) {
+	volumes, volumeMounts := createVolumesAndVolumeMountsForSQLRefs(folder, refs)
+	job.Spec.Template.Spec.Volumes = append(job.Spec.Template.Spec.Volumes, volumes...)
+	job.Spec.Template.Spec.Containers[0].VolumeMounts = append(
+		job.Spec.Template.Spec.Containers[0].VolumeMounts, volumeMounts...)
+}
@@ -22,6 +22,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -144,7 +145,7 @@ var _ = Describe("Job created via InitDB", func() {
 						PostInitSQL:            []string{"testPostInitSql"},
 						PostInitTemplateSQL:    []string{"testPostInitTemplateSql"},
 						PostInitApplicationSQL: []string{"testPostInitApplicationSql"},
-						PostInitApplicationSQLRefs: &apiv1.PostInitApplicationSQLRefs{
+						PostInitApplicationSQLRefs: &apiv1.SQLRefs{
 							SecretRefs: []apiv1.SecretKeySelector{
 								{
 									Key: "secretKey1",
@@ -164,4 +165,168 @@ var _ = Describe("Job created via InitDB", func() {
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement("testPostInitApplicationSql"))
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement(postInitApplicationSQLRefsFolder))
 	})
+
+	It("mounts the post-init and post-init template SQL references", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						PostInitSQLRefs: &apiv1.SQLRefs{
+							ConfigMapRefs: []apiv1.ConfigMapKeySelector{
+								{
+									Key: "configMapKey1",
+									LocalObjectReference: apiv1.LocalObjectReference{
+										Name: "configMapName1",
+									},
+								},
+							},
+						},
+						PostInitTemplateSQLRefs: &apiv1.SQLRefs{
+							SecretRefs: []apiv1.SecretKeySelector{
+								{
+									Key: "secretKey1",
+									LocalObjectReference: apiv1.LocalObjectReference{
+										Name: "secretName1",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		job := CreatePrimaryJobViaInitdb(cluster, 0)
+		container := job.Spec.Template.Spec.Containers[0]
+		Expect(container.Command).Should(ContainElements(
+			"--post-init-sql-refs-folder", postInitSQLRefsFolder,
+			"--post-init-template-sql-refs-folder", postInitTemplateSQLRefsFolder))
+		Expect(container.Command).ShouldNot(ContainElement("--post-init-application-sql-refs-folder"))
+
+		volumeNames := make([]string, 0, len(job.Spec.Template.Spec.Volumes))
+		for _, volume := range job.Spec.Template.Spec.Volumes {
+			volumeNames = append(volumeNames, volume.Name)
+		}
+		Expect(volumeNames).To(ContainElements("0-post-init-sql", "0-post-init-template-sql"))
+
+		mountPaths := make([]string, 0, len(container.VolumeMounts))
+		for _, volumeMount := range container.VolumeMounts {
+			mountPaths = append(mountPaths, volumeMount.MountPath)
+		}
+		Expect(mountPaths).To(ContainElements(
+			postInitSQLRefsFolder+"/0.sql",
+			postInitTemplateSQLRefsFolder+"/0.sql"))
+	})
+})
+
+var _ = Describe("Job created adopting existing PVCs", func() {
+	It("runs the adopt command on the PVCs of the chosen instance", func() {
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					Adopt: &apiv1.BootstrapAdopt{
+						InstanceSerial: 3,
+					},
+				},
+			},
+		}
+		job := CreatePrimaryJobViaAdopt(cluster, 3)
+		Expect(job.Name).To(Equal("cluster-example-3-adopt"))
+		Expect(job.Spec.Template.Spec.Containers[0].Command).To(Equal([]string{
+			"/controller/manager", "instance", "adopt",
+		}))
+		Expect(IsPodSpecUsingPVCs(job.Spec.Template.Spec, "cluster-example-3")).To(BeTrue())
+	})
+})
+
+var _ = Describe("Job created via InitDB with Transparent Data Encryption", func() {
+	It("encrypts the data files wrapping the key with the passphrase", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ImageName: "quay.io/enterprisedb/edb-postgres-advanced:16",
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{},
+				},
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					TDE: &apiv1.TDEConfiguration{
+						Enabled: true,
+						PassphraseSecret: &apiv1.SecretKeySelector{
+							LocalObjectReference: apiv1.LocalObjectReference{Name: "tde-passphrase"},
+							Key:                  "passphrase",
+						},
+					},
+				},
+			},
+		}
+		job := CreatePrimaryJobViaInitdb(cluster, 0)
+		container := job.Spec.Template.Spec.Containers[0]
+
+		flags := buildTDEInitDBFlags(cluster)
+		Expect(flags).To(HaveLen(3))
+		Expect(flags[0]).To(Equal("--data-encryption"))
+		Expect(flags[1]).To(ContainSubstring("-pass env:PGPASSPHRASE"))
+		Expect(container.Command).To(ContainElement(ContainSubstring("--data-encryption")))
+
+		Expect(container.Env).To(ContainElement(corev1.EnvVar{
+			Name: apiv1.TDEPassphraseEnvironmentVariable,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "tde-passphrase"},
+					Key:                  "passphrase",
+				},
+			},
+		}))
+	})
+
+	It("uses the configured commands to wrap the key", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					TDE: &apiv1.TDEConfiguration{
+						Enabled:       true,
+						WrapCommand:   "kms-wrap --output %p",
+						UnwrapCommand: "kms-unwrap --input %p",
+					},
+				},
+			},
+		}
+		Expect(buildTDEInitDBFlags(cluster)).To(Equal([]string{
+			"--data-encryption",
+			"--key-wrap-command=kms-wrap --output %p",
+			"--key-unwrap-command=kms-unwrap --input %p",
+		}))
+		Expect(createEnvVarPostgresContainer(cluster, "cluster-example-1")).ToNot(
+			ContainElement(HaveField("Name", apiv1.TDEPassphraseEnvironmentVariable)))
+	})
+})
+
+var _ = Describe("replication connection environment", func() {
+	It("doesn't pass the replication settings by default", func() {
+		env := createEnvVarPostgresContainer(apiv1.Cluster{}, "cluster-example-1")
+		Expect(env).ToNot(ContainElement(HaveField("Name", postgres.ReplicationSSLModeEnvironmentVariable)))
+		Expect(env).ToNot(ContainElement(HaveField("Name", postgres.ReplicationChannelBindingEnvironmentVariable)))
+	})
+
+	It("passes the replication settings to the instance manager", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ReplicationConnection: &apiv1.ReplicationConnectionConfiguration{
+					SSLMode:        apiv1.ReplicationSSLModeVerifyFull,
+					ChannelBinding: "prefer",
+				},
+			},
+		}
+		env := createEnvVarPostgresContainer(cluster, "cluster-example-1")
+		Expect(env).To(ContainElement(corev1.EnvVar{
+			Name:  postgres.ReplicationSSLModeEnvironmentVariable,
+			Value: "verify-full",
+		}))
+		Expect(env).To(ContainElement(corev1.EnvVar{
+			Name:  postgres.ReplicationChannelBindingEnvironmentVariable,
+			Value: "prefer",
+		}))
+	})
 })
@@ -36,10 +36,41 @@ var _ = Describe("Extract the used image name", func() {
 	pod := PodWithExistingStorage(cluster, 1)
 
 	It("extract the default image name", func() {
-		Expect(GetPostgresImageName(*pod)).To(Equal(configuration.Current.PostgresImageName))
+		Expect(GetPostgresImageName(*pod)).To(Equal(configuration.Current().PostgresImageName))
 	})
 
 	It("extract the init container image name", func() {
-		Expect(GetBootstrapControllerImageName(*pod)).To(Equal(configuration.Current.OperatorImageName))
+		Expect(GetBootstrapControllerImageName(*pod)).To(Equal(configuration.Current().OperatorImageName))
+	})
+})
+
+var _ = Describe("Pod priority", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			PriorityClassName:        "database",
+			PrimaryPriorityClassName: "database-primary",
+			Bootstrap: &apiv1.BootstrapConfiguration{
+				InitDB: &apiv1.BootstrapInitDB{},
+			},
+		},
+		Status: apiv1.ClusterStatus{
+			TargetPrimary: "cluster-example-1",
+		},
+	}
+
+	It("sets the priority class of the primary and of the replicas", func() {
+		Expect(PodWithExistingStorage(cluster, 1).Spec.PriorityClassName).To(Equal("database-primary"))
+		Expect(PodWithExistingStorage(cluster, 2).Spec.PriorityClassName).To(Equal("database"))
+	})
+
+	It("sets the priority class of the jobs", func() {
+		Expect(CreatePrimaryJobViaInitdb(cluster, 1).Spec.Template.Spec.PriorityClassName).
+			To(Equal("database-primary"))
+		Expect(JoinReplicaInstance(cluster, 2).Spec.Template.Spec.PriorityClassName).
+			To(Equal("database"))
 	})
 })
@@ -74,7 +74,7 @@ func Deployment(pooler *apiv1.Pooler,
 				},
 			},
 		}).
-		WithSecurityContext(specs.CreatePodSecurityContext(998, 996), true).
+		WithSecurityContext(specs.CreatePodSecurityContext(cluster.GetSeccompProfile(), 998, 996), true).
 		WithContainerImage("pgbouncer", DefaultPgbouncerImage, false).
 		WithContainerCommand("pgbouncer", []string{
 			"/controller/manager",
@@ -89,12 +89,12 @@ func Deployment(pooler *apiv1.Pooler,
 			Name:          "metrics",
 			ContainerPort: int32(url.PgBouncerMetricsPort),
 		}).
-		WithInitContainerImage(specs.BootstrapControllerContainerName, config.Current.OperatorImageName, true).
+		WithInitContainerImage(specs.BootstrapControllerContainerName, config.Current().OperatorImageName, true).
 		WithInitContainerCommand(specs.BootstrapControllerContainerName,
 			[]string{"/manager", "bootstrap", "/controller/manager"},
 			true).
 		WithInitContainerSecurityContext(specs.BootstrapControllerContainerName,
-			specs.CreateContainerSecurityContext(),
+			specs.CreateContainerSecurityContext(cluster.GetSeccompProfile()),
 			true).
 		WithVolume(&corev1.Volume{
 			Name: "scratch-data",
@@ -112,7 +112,7 @@ func Deployment(pooler *apiv1.Pooler,
 		}, true).
 		WithContainerEnv("pgbouncer", corev1.EnvVar{Name: "NAMESPACE", Value: pooler.Namespace}, true).
 		WithContainerEnv("pgbouncer", corev1.EnvVar{Name: "POOLER_NAME", Value: pooler.Name}, true).
-		WithContainerSecurityContext("pgbouncer", specs.CreateContainerSecurityContext(), true).
+		WithContainerSecurityContext("pgbouncer", specs.CreateContainerSecurityContext(cluster.GetSeccompProfile()), true).
 		WithServiceAccountName(pooler.Name, true).
 		WithReadinessProbe("pgbouncer", &corev1.Probe{
 			TimeoutSeconds: 5,
@@ -122,6 +122,7 @@ func Deployment(pooler *apiv1.Pooler,
 				},
 			},
 		}, false).
+		WithPodAntiAffinity(PodAntiAffinity(pooler), false).
 		Build()
 
 	return &appsv1.Deployment{
@@ -149,3 +150,46 @@ func Deployment(pooler *apiv1.Pooler,
 		},
 	}, nil
 }
+
+// PodAntiAffinity generates the anti-affinity rules spreading the
+// PgBouncer pods of a pooler across the topology domains, or nil
+// when they are disabled
+func PodAntiAffinity(pooler *apiv1.Pooler) *corev1.PodAntiAffinity {
+	config := apiv1.PoolerAffinityConfiguration{}
+	if pooler.Spec.Affinity != nil {
+		config = *pooler.Spec.Affinity
+	}
+
+	if config.EnablePodAntiAffinity != nil && !*config.EnablePodAntiAffinity {
+		return nil
+	}
+
+	topologyKey := config.TopologyKey
+	if topologyKey == "" {
+		topologyKey = "kubernetes.io/hostname"
+	}
+
+	podAffinityTerm := corev1.PodAffinityTerm{
+		LabelSelector: &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				PgbouncerNameLabel: pooler.Name,
+			},
+		},
+		TopologyKey: topologyKey,
+	}
+
+	if config.PodAntiAffinityType == apiv1.PodAntiAffinityTypeRequired {
+		return &corev1.PodAntiAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{podAffinityTerm},
+		}
+	}
+
+	return &corev1.PodAntiAffinity{
+		PreferredDuringSchedulingIgnoredDuringExecution: []corev1.WeightedPodAffinityTerm{
+			{
+				Weight:          100,
+				PodAffinityTerm: podAffinityTerm,
+			},
+		},
+	}
+}
@@ -0,0 +1,56 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pgbouncer
+
+import (
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// PodDisruptionBudget creates the pod disruption budget of the PgBouncer
+// pods of a pooler, or nil when the pooler doesn't require it
+func PodDisruptionBudget(pooler *apiv1.Pooler) *policyv1beta1.PodDisruptionBudget {
+	if pooler.Spec.PodDisruptionBudget == nil {
+		return nil
+	}
+
+	pdb := &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      pooler.Name,
+			Namespace: pooler.Namespace,
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					PgbouncerNameLabel: pooler.Name,
+				},
+			},
+			MinAvailable:   pooler.Spec.PodDisruptionBudget.MinAvailable,
+			MaxUnavailable: pooler.Spec.PodDisruptionBudget.MaxUnavailable,
+		},
+	}
+
+	if pdb.Spec.MinAvailable == nil && pdb.Spec.MaxUnavailable == nil {
+		one := intstr.FromInt(1)
+		pdb.Spec.MaxUnavailable = &one
+	}
+
+	return pdb
+}
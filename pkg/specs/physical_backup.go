@@ -0,0 +1,204 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	"fmt"
+	"path"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// physicalBackupJobRole is the role of the Jobs taking a backup
+	// with the physical method
+	physicalBackupJobRole = "physical-backup"
+
+	// physicalBackupVolumePath is where the volume storing the
+	// tarball of the backup is mounted
+	physicalBackupVolumePath = "/backup"
+
+	// physicalBackupCertificatePath is where the certificate of the
+	// streaming replication user is mounted
+	physicalBackupCertificatePath = "/etc/replication-certificate"
+
+	// physicalBackupServerCAPath is where the CA verifying the
+	// certificate of the instances is mounted
+	physicalBackupServerCAPath = "/etc/server-ca"
+)
+
+// GetPhysicalBackupJobName gets the name of the Job taking the backup
+// with the passed name with the physical method
+func GetPhysicalBackupJobName(backupName string) string {
+	return fmt.Sprintf("%s-%s", backupName, physicalBackupJobRole)
+}
+
+// CreatePhysicalBackupJob creates the Job taking a backup with the
+// physical method. The Job requests the backup to the instance manager
+// of the passed Pod, authenticating as the streaming replication user,
+// and writes the tarball in the persistent volume claim of the backup
+func CreatePhysicalBackupJob(cluster apiv1.Cluster, backup apiv1.Backup, pod corev1.Pod) *batchv1.Job {
+	jobName := GetPhysicalBackupJobName(backup.Name)
+	backoffLimit := int32(0)
+
+	command := []string{
+		"/controller/manager",
+		"instance",
+		"physical-backup",
+		"--host", pod.Status.PodIP,
+		"--backup-name", backup.Name,
+		"--namespace", backup.Namespace,
+		"--label", backup.Name,
+		"--output", path.Join(physicalBackupVolumePath, backup.Name+".tar"),
+		"--certificate", path.Join(physicalBackupCertificatePath, certs.TLSCertKey),
+		"--key", path.Join(physicalBackupCertificatePath, certs.TLSPrivateKeyKey),
+		"--server-ca", path.Join(physicalBackupServerCAPath, certs.CACertKey),
+	}
+	if backup.Spec.Physical.Fast {
+		command = append(command, "--fast")
+	}
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      jobName,
+			Namespace: backup.Namespace,
+			Labels: map[string]string{
+				utils.ClusterLabelName: cluster.Name,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						utils.ClusterLabelName: cluster.Name,
+					},
+				},
+				Spec: corev1.PodSpec{
+					InitContainers: []corev1.Container{
+						{
+							Name:            BootstrapControllerContainerName,
+							Image:           configuration.Current().OperatorImageName,
+							ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+							Command: []string{
+								"/manager",
+								"bootstrap",
+								"/controller/manager",
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "scratch-data",
+									MountPath: postgres.ScratchDataDirectory,
+								},
+							},
+							SecurityContext: CreateContainerSecurityContext(cluster.GetSeccompProfile()),
+						},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:            physicalBackupJobRole,
+							Image:           cluster.GetImageName(),
+							ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+							Command:         command,
+							VolumeMounts: []corev1.VolumeMount{
+								{
+									Name:      "scratch-data",
+									MountPath: postgres.ScratchDataDirectory,
+								},
+								{
+									Name:      "backup",
+									MountPath: physicalBackupVolumePath,
+								},
+								{
+									Name:      "replication-certificate",
+									MountPath: physicalBackupCertificatePath,
+									ReadOnly:  true,
+								},
+								{
+									Name:      "server-ca",
+									MountPath: physicalBackupServerCAPath,
+									ReadOnly:  true,
+								},
+							},
+							SecurityContext: CreateContainerSecurityContext(cluster.GetSeccompProfile()),
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "scratch-data",
+							VolumeSource: corev1.VolumeSource{
+								EmptyDir: &corev1.EmptyDirVolumeSource{},
+							},
+						},
+						{
+							Name: "backup",
+							VolumeSource: corev1.VolumeSource{
+								PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+									ClaimName: backup.Spec.Physical.VolumeClaimName,
+								},
+							},
+						},
+						{
+							Name: "replication-certificate",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: cluster.GetReplicationSecretName(),
+								},
+							},
+						},
+						{
+							Name: "server-ca",
+							VolumeSource: corev1.VolumeSource{
+								Secret: &corev1.SecretVolumeSource{
+									SecretName: cluster.GetServerCASecretName(),
+									Items: []corev1.KeyToPath{
+										{
+											Key:  certs.CACertKey,
+											Path: certs.CACertKey,
+										},
+									},
+								},
+							},
+						},
+					},
+					SecurityContext: CreatePodSecurityContext(
+						cluster.GetSeccompProfile(),
+						cluster.GetPostgresUID(),
+						cluster.GetPostgresGID(),
+					),
+					Tolerations:        cluster.Spec.Affinity.Tolerations,
+					NodeSelector:       cluster.Spec.Affinity.NodeSelector,
+					ServiceAccountName: cluster.Name,
+					RestartPolicy:      corev1.RestartPolicyNever,
+				},
+			},
+		},
+	}
+
+	utils.LabelJobRole(&job.ObjectMeta, physicalBackupJobRole)
+	addManagerLoggingOptions(cluster, &job.Spec.Template.Spec.Containers[0])
+
+	return job
+}
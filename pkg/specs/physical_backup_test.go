@@ -0,0 +1,84 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Physical backup job", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+	}
+	backup := apiv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "backup-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.BackupSpec{
+			Method: apiv1.BackupMethodPhysical,
+			Physical: &apiv1.PhysicalBackupConfiguration{
+				VolumeClaimName: "backups",
+				Fast:            true,
+			},
+		},
+	}
+	pod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"},
+		Status:     corev1.PodStatus{PodIP: "10.0.0.2"},
+	}
+
+	It("requests the backup to the passed Pod, writing it in the volume claim", func() {
+		job := CreatePhysicalBackupJob(cluster, backup, pod)
+		Expect(job.Name).To(Equal("backup-example-physical-backup"))
+		Expect(job.Namespace).To(Equal("default"))
+
+		command := job.Spec.Template.Spec.Containers[0].Command
+		Expect(command).To(ContainElements("--host", "10.0.0.2", "--fast"))
+		Expect(command).To(ContainElements("--output", "/backup/backup-example.tar"))
+
+		var claims []string
+		var secrets []string
+		for _, volume := range job.Spec.Template.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil {
+				claims = append(claims, volume.PersistentVolumeClaim.ClaimName)
+			}
+			if volume.Secret != nil {
+				secrets = append(secrets, volume.Secret.SecretName)
+			}
+		}
+		Expect(claims).To(Equal([]string{"backups"}))
+		Expect(secrets).To(ConsistOf("cluster-example-replication", "cluster-example-ca"))
+	})
+
+	It("doesn't mount the volumes of the instance", func() {
+		job := CreatePhysicalBackupJob(cluster, backup, pod)
+		for _, volume := range job.Spec.Template.Spec.Volumes {
+			Expect(volume.Name).ToNot(Equal("pgdata"))
+			Expect(volume.Name).ToNot(Equal("superuser-secret"))
+		}
+	})
+})
@@ -18,9 +18,11 @@ package specs
 
 import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -29,6 +31,38 @@ func CreatePodMonitor(cluster *apiv1.Cluster) *monitoringv1.PodMonitor {
 	labels := make(map[string]string)
 	labels[utils.ClusterLabelName] = cluster.Name
 
+	endpoint := monitoringv1.PodMetricsEndpoint{
+		Port: "metrics",
+	}
+
+	if cluster.Spec.Monitoring.IsTLSEnabled() {
+		// The metrics are scraped using the pod IP, so the server
+		// name is set to one of the names in the server certificate
+		endpoint.Scheme = "https"
+		endpoint.TLSConfig = &monitoringv1.PodMetricsEndpointTLSConfig{
+			SafeTLSConfig: monitoringv1.SafeTLSConfig{
+				CA: monitoringv1.SecretOrConfigMap{
+					Secret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{
+							Name: cluster.GetServerCASecretName(),
+						},
+						Key: certs.CACertKey,
+					},
+				},
+				ServerName: cluster.GetServiceReadWriteName(),
+			},
+		}
+	}
+
+	if cluster.Spec.Monitoring != nil && cluster.Spec.Monitoring.BearerTokenSecret != nil {
+		endpoint.BearerTokenSecret = corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: cluster.Spec.Monitoring.BearerTokenSecret.Name,
+			},
+			Key: cluster.Spec.Monitoring.BearerTokenSecret.Key,
+		}
+	}
+
 	return &monitoringv1.PodMonitor{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: cluster.Namespace,
@@ -38,11 +72,7 @@ func CreatePodMonitor(cluster *apiv1.Cluster) *monitoringv1.PodMonitor {
 			Selector: metav1.LabelSelector{
 				MatchLabels: labels,
 			},
-			PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{
-				{
-					Port: "metrics",
-				},
-			},
+			PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{endpoint},
 		},
 	}
 }
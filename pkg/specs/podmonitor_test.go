@@ -0,0 +1,63 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PodMonitor", func() {
+	It("scrapes the metrics over HTTP by default", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		podMonitor := CreatePodMonitor(cluster)
+		Expect(podMonitor.Spec.PodMetricsEndpoints).To(HaveLen(1))
+		endpoint := podMonitor.Spec.PodMetricsEndpoints[0]
+		Expect(endpoint.Port).To(Equal("metrics"))
+		Expect(endpoint.Scheme).To(BeEmpty())
+		Expect(endpoint.TLSConfig).To(BeNil())
+		Expect(endpoint.BearerTokenSecret.Name).To(BeEmpty())
+	})
+
+	It("uses TLS and the bearer token when configured", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Monitoring: &apiv1.MonitoringConfiguration{
+					TLSConfig: &apiv1.ClusterMonitoringTLSConfiguration{Enabled: true},
+					BearerTokenSecret: &apiv1.SecretKeySelector{
+						LocalObjectReference: apiv1.LocalObjectReference{Name: "metrics-token"},
+						Key:                  "token",
+					},
+				},
+			},
+		}
+		endpoint := CreatePodMonitor(cluster).Spec.PodMetricsEndpoints[0]
+		Expect(endpoint.Scheme).To(Equal("https"))
+		Expect(endpoint.TLSConfig).ToNot(BeNil())
+		Expect(endpoint.TLSConfig.CA.Secret.Name).To(Equal("cluster-example-ca"))
+		Expect(endpoint.TLSConfig.ServerName).To(Equal("cluster-example-rw"))
+		Expect(endpoint.BearerTokenSecret.Name).To(Equal("metrics-token"))
+		Expect(endpoint.BearerTokenSecret.Key).To(Equal("token"))
+	})
+})
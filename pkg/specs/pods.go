@@ -20,6 +20,7 @@ package specs
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
@@ -86,7 +87,10 @@ const (
 	PgWalArchiveStatusPath = PgWalPath + "/archive_status"
 
 	// ReadinessProbePeriod is the period set for the postgres instance readiness probe
-	ReadinessProbePeriod = 10
+	ReadinessProbePeriod = apiv1.DefaultProbePeriodSeconds
+
+	// StartupProbePeriod is the period set for the postgres instance startup probe
+	StartupProbePeriod = apiv1.DefaultProbePeriodSeconds
 )
 
 func createEnvVarPostgresContainer(cluster apiv1.Cluster, podName string) []corev1.EnvVar {
@@ -117,9 +121,147 @@ func createEnvVarPostgresContainer(cluster apiv1.Cluster, podName string) []core
 		},
 	}
 
+	if flavor := cluster.GetPostgresFlavor(); flavor != postgres.FlavorPostgreSQL {
+		envVar = append(envVar, corev1.EnvVar{
+			Name:  postgres.FlavorEnvironmentVariable,
+			Value: string(flavor),
+		})
+	}
+
+	if replicationConnection := cluster.Spec.ReplicationConnection; replicationConnection != nil {
+		if replicationConnection.SSLMode != "" {
+			envVar = append(envVar, corev1.EnvVar{
+				Name:  postgres.ReplicationSSLModeEnvironmentVariable,
+				Value: string(replicationConnection.SSLMode),
+			})
+		}
+		if replicationConnection.ChannelBinding != "" {
+			envVar = append(envVar, corev1.EnvVar{
+				Name:  postgres.ReplicationChannelBindingEnvironmentVariable,
+				Value: replicationConnection.ChannelBinding,
+			})
+		}
+	}
+
+	if tde := cluster.Spec.PostgresConfiguration.TDE; tde.IsEnabled() && tde.PassphraseSecret != nil {
+		envVar = append(envVar, corev1.EnvVar{
+			Name: apiv1.TDEPassphraseEnvironmentVariable,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: tde.PassphraseSecret.Name,
+					},
+					Key: tde.PassphraseSecret.Key,
+				},
+			},
+		})
+	}
+
 	return envVar
 }
 
+// getStartupProbeFailureThreshold gets the number of failures of the startup
+// probe needed to cover the passed start delay, expressed in seconds
+func getStartupProbeFailureThreshold(startDelay, period int32) int32 {
+	if startDelay <= period {
+		return 1
+	}
+
+	return int32(math.Ceil(float64(startDelay) / float64(period)))
+}
+
+// newPostgresProbe creates a probe calling the passed path of the
+// instance manager, with the default settings of the operator.
+// Every field is explicitly set, to be able to compare the result
+// with the probes of existing Pods, which are defaulted by Kubernetes
+func newPostgresProbe(path string) *corev1.Probe {
+	return &corev1.Probe{
+		TimeoutSeconds:   apiv1.DefaultProbeTimeoutSeconds,
+		PeriodSeconds:    apiv1.DefaultProbePeriodSeconds,
+		SuccessThreshold: 1,
+		FailureThreshold: apiv1.DefaultProbeFailureThreshold,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path:   path,
+				Port:   intstr.FromInt(url.StatusPort),
+				Scheme: corev1.URISchemeHTTP,
+			},
+		},
+	}
+}
+
+// applyProbeConfiguration overrides the settings of a probe with the
+// ones specified by the user
+func applyProbeConfiguration(probe *corev1.Probe, configuration *apiv1.Probe) {
+	if configuration == nil {
+		return
+	}
+
+	if configuration.InitialDelaySeconds > 0 {
+		probe.InitialDelaySeconds = configuration.InitialDelaySeconds
+	}
+	if configuration.TimeoutSeconds > 0 {
+		probe.TimeoutSeconds = configuration.TimeoutSeconds
+	}
+	if configuration.PeriodSeconds > 0 {
+		probe.PeriodSeconds = configuration.PeriodSeconds
+	}
+	if configuration.FailureThreshold > 0 {
+		probe.FailureThreshold = configuration.FailureThreshold
+	}
+}
+
+// CreateStartupProbe creates the startup probe of the PostgreSQL container.
+// The startup probe gives PostgreSQL up to startDelay seconds to start up,
+// and the liveness probe is only checked after the startup probe succeeded
+func CreateStartupProbe(cluster apiv1.Cluster) *corev1.Probe {
+	probe := newPostgresProbe(url.PathHealth)
+	probe.PeriodSeconds = StartupProbePeriod
+
+	var configuration *apiv1.Probe
+	if cluster.Spec.Probes != nil {
+		configuration = cluster.Spec.Probes.Startup
+	}
+	applyProbeConfiguration(probe, configuration)
+
+	if configuration == nil || configuration.FailureThreshold == 0 {
+		probe.FailureThreshold = getStartupProbeFailureThreshold(cluster.GetMaxStartDelay(), probe.PeriodSeconds)
+	}
+
+	return probe
+}
+
+// CreateLivenessProbe creates the liveness probe of the PostgreSQL container
+func CreateLivenessProbe(cluster apiv1.Cluster) *corev1.Probe {
+	probe := newPostgresProbe(url.PathHealth)
+	if cluster.Spec.Probes != nil {
+		applyProbeConfiguration(probe, cluster.Spec.Probes.Liveness)
+	}
+
+	return probe
+}
+
+// CreateReadinessProbe creates the readiness probe of the PostgreSQL container.
+// The criteria used to consider the instance ready is passed to the
+// instance manager as a query parameter
+func CreateReadinessProbe(cluster apiv1.Cluster) *corev1.Probe {
+	path := url.PathReady
+	if probeType := cluster.GetReadinessProbeType(); probeType != apiv1.ReadinessProbeTypeQuery {
+		path = fmt.Sprintf("%s?%s=%s", url.PathReady, url.ReadinessProbeTypeParameter, probeType)
+		if maximumLag := cluster.Spec.Probes.Readiness.MaximumLag; maximumLag != nil {
+			path = fmt.Sprintf("%s&%s=%d", path, url.ReadinessProbeMaximumLagParameter, maximumLag.Value())
+		}
+	}
+
+	probe := newPostgresProbe(path)
+	probe.PeriodSeconds = ReadinessProbePeriod
+	if cluster.Spec.Probes != nil && cluster.Spec.Probes.Readiness != nil {
+		applyProbeConfiguration(probe, &cluster.Spec.Probes.Readiness.Probe)
+	}
+
+	return probe
+}
+
 // createPostgresContainers create the PostgreSQL containers that are
 // used for every instance
 func createPostgresContainers(
@@ -132,32 +274,10 @@ func createPostgresContainers(
 			Image:           cluster.GetImageName(),
 			ImagePullPolicy: cluster.Spec.ImagePullPolicy,
 			Env:             createEnvVarPostgresContainer(cluster, podName),
-			VolumeMounts:    createPostgresVolumeMounts(cluster),
-			ReadinessProbe: &corev1.Probe{
-				TimeoutSeconds: 5,
-				PeriodSeconds:  ReadinessProbePeriod,
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path: url.PathReady,
-						Port: intstr.FromInt(url.StatusPort),
-					},
-				},
-			},
-			// From K8s 1.17 and newer, startup probes will be available for
-			// all users and not just protected from feature gates. For now
-			// let's use the LivenessProbe. When we will drop support for K8s
-			// 1.16, we'll configure a StartupProbe and this will lead to a
-			// better LivenessProbe (without InitialDelaySeconds).
-			LivenessProbe: &corev1.Probe{
-				InitialDelaySeconds: cluster.GetMaxStartDelay(),
-				TimeoutSeconds:      5,
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path: url.PathHealth,
-						Port: intstr.FromInt(url.StatusPort),
-					},
-				},
-			},
+			VolumeMounts:    append(createPostgresVolumeMounts(cluster), createExtensionsVolumeMounts(cluster)...),
+			ReadinessProbe:  CreateReadinessProbe(cluster),
+			StartupProbe:    CreateStartupProbe(cluster),
+			LivenessProbe:   CreateLivenessProbe(cluster),
 			Command: []string{
 				"/controller/manager",
 				"instance",
@@ -180,8 +300,13 @@ func createPostgresContainers(
 					ContainerPort: int32(url.StatusPort),
 					Protocol:      "TCP",
 				},
+				{
+					Name:          "backup",
+					ContainerPort: int32(url.SecurePort),
+					Protocol:      "TCP",
+				},
 			},
-			SecurityContext: CreateContainerSecurityContext(),
+			SecurityContext: CreateContainerSecurityContext(cluster.GetSeccompProfile()),
 		},
 	}
 
@@ -190,6 +315,38 @@ func createPostgresContainers(
 	return containers
 }
 
+// CreateClusterAffinitySection creates the affinity sections for the Pods
+// of a cluster, restricting them to the nodes having one of the
+// architectures provided by the PostgreSQL image, when known
+func CreateClusterAffinitySection(cluster *apiv1.Cluster) *corev1.Affinity {
+	affinity := CreateAffinitySection(cluster.Name, cluster.Spec.Affinity)
+
+	architectures := cluster.GetImageArchitectures()
+	if len(architectures) == 0 {
+		return affinity
+	}
+
+	if affinity == nil {
+		affinity = &corev1.Affinity{}
+	}
+	affinity.NodeAffinity = &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{
+							Key:      corev1.LabelArchStable,
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   architectures,
+						},
+					},
+				},
+			},
+		},
+	}
+	return affinity
+}
+
 // CreateAffinitySection creates the affinity sections for Pods, given the configuration
 // from the user
 func CreateAffinitySection(clusterName string, config apiv1.AffinityConfiguration) *corev1.Affinity {
@@ -274,7 +431,7 @@ func CreateGeneratedAntiAffinity(clusterName string, config apiv1.AffinityConfig
 }
 
 // CreatePodSecurityContext defines the security context under which the containers are running
-func CreatePodSecurityContext(user, group int64) *corev1.PodSecurityContext {
+func CreatePodSecurityContext(seccompProfile *corev1.SeccompProfile, user, group int64) *corev1.PodSecurityContext {
 	// Under Openshift we inherit SecurityContext from the restricted security context constraint
 	if utils.HaveSecurityContextConstraints() {
 		return nil
@@ -282,16 +439,28 @@ func CreatePodSecurityContext(user, group int64) *corev1.PodSecurityContext {
 
 	trueValue := true
 	return &corev1.PodSecurityContext{
-		RunAsNonRoot: &trueValue,
-		RunAsUser:    &user,
-		RunAsGroup:   &group,
-		FSGroup:      &group,
+		RunAsNonRoot:   &trueValue,
+		RunAsUser:      &user,
+		RunAsGroup:     &group,
+		FSGroup:        &group,
+		SeccompProfile: seccompProfile,
 	}
 }
 
+// instanceNameFormat is the format of the names of the instances,
+// given the name of the cluster and the serial of the instance
+const instanceNameFormat = "%s-%v"
+
+// GetInstanceNamePattern gets a regular expression matching the names of
+// the instances of a cluster. The name of a cluster is a DNS label, and
+// contains no character to be escaped
+func GetInstanceNamePattern(clusterName string) string {
+	return fmt.Sprintf(instanceNameFormat, clusterName, "[0-9]+")
+}
+
 // PodWithExistingStorage create a new instance with an existing storage
 func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
-	podName := fmt.Sprintf("%s-%v", cluster.Name, nodeSerial)
+	podName := fmt.Sprintf(instanceNameFormat, cluster.Name, nodeSerial)
 	gracePeriod := int64(cluster.GetMaxStopDelay())
 
 	pod := &corev1.Pod{
@@ -311,14 +480,19 @@ func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 		Spec: corev1.PodSpec{
 			Hostname:  podName,
 			Subdomain: cluster.GetServiceAnyName(),
-			InitContainers: []corev1.Container{
-				createBootstrapContainer(cluster),
-			},
-			Containers:                    createPostgresContainers(cluster, podName),
-			Volumes:                       createPostgresVolumes(cluster, podName),
-			SecurityContext:               CreatePodSecurityContext(cluster.GetPostgresUID(), cluster.GetPostgresGID()),
-			Affinity:                      CreateAffinitySection(cluster.Name, cluster.Spec.Affinity),
+			InitContainers: append(
+				[]corev1.Container{createBootstrapContainer(cluster)},
+				createExtensionsInitContainers(cluster)...),
+			Containers: createPostgresContainers(cluster, podName),
+			Volumes:    append(createPostgresVolumes(cluster, podName), createExtensionsVolumes(cluster)...),
+			SecurityContext: CreatePodSecurityContext(
+				cluster.GetSeccompProfile(),
+				cluster.GetPostgresUID(),
+				cluster.GetPostgresGID(),
+			),
+			Affinity:                      CreateClusterAffinitySection(&cluster),
 			Tolerations:                   cluster.Spec.Affinity.Tolerations,
+			PriorityClassName:             cluster.GetPriorityClassName(podName),
 			ServiceAccountName:            cluster.Name,
 			NodeSelector:                  cluster.Spec.Affinity.NodeSelector,
 			TerminationGracePeriodSeconds: &gracePeriod,
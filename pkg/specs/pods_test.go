@@ -17,7 +17,10 @@ limitations under the License.
 package specs
 
 import (
+	"regexp"
+
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -47,12 +50,113 @@ var (
 	}
 )
 
+var _ = Describe("The names of the instances", func() {
+	It("can be matched with a regular expression", func() {
+		pattern := regexp.MustCompile("^" + GetInstanceNamePattern("cluster-example") + "$")
+		Expect(pattern.MatchString("cluster-example-12")).To(BeTrue())
+		Expect(pattern.MatchString("cluster-example-rw")).To(BeFalse())
+		Expect(pattern.MatchString("cluster-example-other-1")).To(BeFalse())
+	})
+})
+
 var _ = Describe("The PostgreSQL security context", func() {
-	securityContext := CreatePodSecurityContext(26, 26)
+	seccompProfile := &corev1.SeccompProfile{
+		Type: corev1.SeccompProfileTypeRuntimeDefault,
+	}
+	securityContext := CreatePodSecurityContext(seccompProfile, 26, 26)
 
 	It("allows the container to create its own PGDATA", func() {
 		Expect(securityContext.RunAsUser).To(Equal(securityContext.FSGroup))
 	})
+
+	It("applies the seccomp profile", func() {
+		Expect(securityContext.SeccompProfile).To(Equal(seccompProfile))
+	})
+})
+
+var _ = Describe("The PostgreSQL probes", func() {
+	It("covers the start delay with the startup probe", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				MaxStartDelay: 3600,
+			},
+		}
+		containers := createPostgresContainers(cluster, "cluster-example-1")
+		Expect(containers[0].StartupProbe.PeriodSeconds).To(BeEquivalentTo(StartupProbePeriod))
+		Expect(containers[0].StartupProbe.FailureThreshold).To(BeEquivalentTo(360))
+		Expect(containers[0].LivenessProbe.InitialDelaySeconds).To(BeZero())
+	})
+
+	It("computes the failure threshold of the startup probe", func() {
+		Expect(getStartupProbeFailureThreshold(5, StartupProbePeriod)).To(BeEquivalentTo(1))
+		Expect(getStartupProbeFailureThreshold(10, StartupProbePeriod)).To(BeEquivalentTo(1))
+		Expect(getStartupProbeFailureThreshold(30, StartupProbePeriod)).To(BeEquivalentTo(3))
+		Expect(getStartupProbeFailureThreshold(31, StartupProbePeriod)).To(BeEquivalentTo(4))
+		Expect(getStartupProbeFailureThreshold(30, 5)).To(BeEquivalentTo(6))
+	})
+
+	It("applies the probes configuration of the cluster", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				MaxStartDelay: 60,
+				Probes: &v1.ProbesConfiguration{
+					Startup: &v1.Probe{PeriodSeconds: 5},
+					Liveness: &v1.Probe{
+						TimeoutSeconds:   10,
+						FailureThreshold: 6,
+					},
+					Readiness: &v1.ReadinessProbe{
+						Probe: v1.Probe{PeriodSeconds: 2},
+						Type:  v1.ReadinessProbeTypeStreaming,
+					},
+				},
+			},
+		}
+
+		startupProbe := CreateStartupProbe(cluster)
+		Expect(startupProbe.PeriodSeconds).To(BeEquivalentTo(5))
+		Expect(startupProbe.FailureThreshold).To(BeEquivalentTo(12))
+
+		livenessProbe := CreateLivenessProbe(cluster)
+		Expect(livenessProbe.TimeoutSeconds).To(BeEquivalentTo(10))
+		Expect(livenessProbe.FailureThreshold).To(BeEquivalentTo(6))
+		Expect(livenessProbe.PeriodSeconds).To(BeEquivalentTo(10))
+
+		readinessProbe := CreateReadinessProbe(cluster)
+		Expect(readinessProbe.PeriodSeconds).To(BeEquivalentTo(2))
+		Expect(readinessProbe.HTTPGet.Path).To(Equal("/readyz?type=streaming"))
+	})
+
+	It("uses the explicit failure threshold of the startup probe", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				MaxStartDelay: 3600,
+				Probes: &v1.ProbesConfiguration{
+					Startup: &v1.Probe{FailureThreshold: 20},
+				},
+			},
+		}
+		Expect(CreateStartupProbe(cluster).FailureThreshold).To(BeEquivalentTo(20))
+	})
+
+	It("passes the maximum lag to the readiness probe", func() {
+		maximumLag := resource.MustParse("16Mi")
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				Probes: &v1.ProbesConfiguration{
+					Readiness: &v1.ReadinessProbe{
+						Type:       v1.ReadinessProbeTypeStreaming,
+						MaximumLag: &maximumLag,
+					},
+				},
+			},
+		}
+		Expect(CreateReadinessProbe(cluster).HTTPGet.Path).To(Equal("/readyz?type=streaming&maximumLag=16777216"))
+	})
+
+	It("does not add the readiness criteria to the path by default", func() {
+		Expect(CreateReadinessProbe(v1.Cluster{}).HTTPGet.Path).To(Equal("/readyz"))
+	})
 })
 
 var _ = Describe("Create affinity section", func() {
@@ -247,3 +351,50 @@ var _ = Describe("Create affinity section", func() {
 		})
 	})
 })
+
+var _ = Describe("Create cluster affinity section", func() {
+	cluster := v1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-test"},
+		Spec: v1.ClusterSpec{
+			ImageName: "postgres:15",
+			Affinity: v1.AffinityConfiguration{
+				EnablePodAntiAffinity:      pointerToBool(false),
+				EnableArchitectureAffinity: true,
+			},
+		},
+		Status: v1.ClusterStatus{
+			ImageArchitectures: &v1.ImageArchitecturesStatus{
+				Image:         "postgres:15",
+				Architectures: []string{"amd64", "arm64"},
+			},
+		},
+	}
+
+	It("requires the nodes to have one of the image architectures", func() {
+		affinity := CreateClusterAffinitySection(&cluster)
+		Expect(affinity).ToNot(BeNil())
+		Expect(affinity.PodAntiAffinity).To(BeNil())
+		Expect(affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms).To(Equal(
+			[]corev1.NodeSelectorTerm{
+				{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{
+							Key:      "kubernetes.io/arch",
+							Operator: corev1.NodeSelectorOpIn,
+							Values:   []string{"amd64", "arm64"},
+						},
+					},
+				},
+			}))
+	})
+
+	It("doesn't add a node affinity when the image architectures are unknown", func() {
+		otherImageCluster := cluster.DeepCopy()
+		otherImageCluster.Spec.ImageName = "postgres:16"
+		Expect(CreateClusterAffinitySection(otherImageCluster)).To(BeNil())
+
+		disabledCluster := cluster.DeepCopy()
+		disabledCluster.Spec.Affinity.EnableArchitectureAffinity = false
+		Expect(CreateClusterAffinitySection(disabledCluster)).To(BeNil())
+	})
+})
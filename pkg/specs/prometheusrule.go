@@ -0,0 +1,121 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	"fmt"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+const (
+	// replicationLagAlertThreshold is the replication lag, in seconds,
+	// above which a standby is considered lagging
+	replicationLagAlertThreshold = 300
+
+	// backupAgeAlertThreshold is the age, in seconds, of the last
+	// available backup above which an alert is raised
+	backupAgeAlertThreshold = 2 * 24 * 3600
+
+	// certificateExpirationAlertThreshold is the time, in seconds,
+	// before the expiration of the server certificate when an alert is raised
+	certificateExpirationAlertThreshold = 7 * 24 * 3600
+)
+
+// CreatePrometheusRule creates a new PrometheusRule containing
+// the default alerting rules for a cluster
+func CreatePrometheusRule(cluster *apiv1.Cluster) *monitoringv1.PrometheusRule {
+	return &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cluster.Namespace,
+			Name:      cluster.Name,
+		},
+		Spec: monitoringv1.PrometheusRuleSpec{
+			Groups: []monitoringv1.RuleGroup{
+				{
+					Name:  fmt.Sprintf("cloudnative-pg/%s", cluster.Name),
+					Rules: createAlertingRules(cluster),
+				},
+			},
+		},
+	}
+}
+
+// createAlertingRules creates the default alerting rules for a cluster
+func createAlertingRules(cluster *apiv1.Cluster) []monitoringv1.Rule {
+	// The PodMonitor adds the namespace and the pod labels to the metrics
+	selector := fmt.Sprintf(`namespace=%q, pod=~%q`, cluster.Namespace, GetInstanceNamePattern(cluster.Name))
+
+	rules := []monitoringv1.Rule{
+		newAlertingRule(cluster, "CNPGTooFewReadyInstances", "critical", "5m",
+			fmt.Sprintf(`(count(cnpg_collector_up{%s} == 1) or vector(0)) < %d`, selector, cluster.Spec.Instances),
+			fmt.Sprintf("Less than %d instances of the cluster are up", cluster.Spec.Instances)),
+		newAlertingRule(cluster, "CNPGReplicationLagHigh", "warning", "5m",
+			fmt.Sprintf(`max by (pod) (cnpg_pg_replication_lag{%s}) > %d`, selector, replicationLagAlertThreshold),
+			fmt.Sprintf("The replication lag of {{ $labels.pod }} is higher than %d seconds",
+				replicationLagAlertThreshold)),
+		newAlertingRule(cluster, "CNPGDiskSpaceLow", "warning", "5m",
+			fmt.Sprintf(`max by (pod, volume) (cnpg_collector_disk_space_bytes{%[1]s, value="used"}) * 100 / `+
+				`max by (pod, volume) (cnpg_collector_disk_space_bytes{%[1]s, value="total"}) > %[2]d`,
+				selector, cluster.GetDiskSpaceWarningThreshold()),
+			fmt.Sprintf("The {{ $labels.volume }} volume of {{ $labels.pod }} is more than %d%% full",
+				cluster.GetDiskSpaceWarningThreshold())),
+		newAlertingRule(cluster, "CNPGCertificateExpiring", "warning", "",
+			fmt.Sprintf(`min(cnpg_collector_server_certificate_expiration_timestamp{%s}) - time() < %d`,
+				selector, certificateExpirationAlertThreshold),
+			"The server certificate of the cluster expires in less than 7 days"),
+	}
+
+	if cluster.Spec.Backup != nil && cluster.Spec.Backup.BarmanObjectStore != nil {
+		rules = append(rules,
+			newAlertingRule(cluster, "CNPGArchivingFailing", "critical", "10m",
+				fmt.Sprintf(`max(cnpg_collector_wal_archiving_failing{%s}) > 0`, selector),
+				"WAL archiving is failing"),
+			newAlertingRule(cluster, "CNPGNoRecentBackup", "warning", "",
+				fmt.Sprintf(`time() - max(cnpg_collector_last_successful_backup_timestamp{%s}) > %d`,
+					selector, backupAgeAlertThreshold),
+				"The last successful backup is older than 2 days"),
+		)
+	}
+
+	return rules
+}
+
+// newAlertingRule creates an alerting rule for a cluster
+func newAlertingRule(
+	cluster *apiv1.Cluster,
+	name, severity, duration, expr, description string,
+) monitoringv1.Rule {
+	return monitoringv1.Rule{
+		Alert: name,
+		Expr:  intstr.FromString(expr),
+		For:   duration,
+		Labels: map[string]string{
+			"severity":  severity,
+			"namespace": cluster.Namespace,
+			"cluster":   cluster.Name,
+		},
+		Annotations: map[string]string{
+			"summary":     fmt.Sprintf("%s in cluster %s/%s", name, cluster.Namespace, cluster.Name),
+			"description": description,
+		},
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PrometheusRule", func() {
+	getAlertNames := func(cluster *apiv1.Cluster) []string {
+		rule := CreatePrometheusRule(cluster)
+		Expect(rule.Spec.Groups).To(HaveLen(1))
+
+		var result []string
+		for _, alert := range rule.Spec.Groups[0].Rules {
+			result = append(result, alert.Alert)
+		}
+		return result
+	}
+
+	It("creates the default alerts for the cluster", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec:       apiv1.ClusterSpec{Instances: 3},
+		}
+		rule := CreatePrometheusRule(cluster)
+		Expect(rule.Name).To(Equal("cluster-example"))
+		Expect(rule.Namespace).To(Equal("default"))
+		Expect(getAlertNames(cluster)).To(ConsistOf(
+			"CNPGTooFewReadyInstances",
+			"CNPGReplicationLagHigh",
+			"CNPGDiskSpaceLow",
+			"CNPGCertificateExpiring",
+		))
+
+		alert := rule.Spec.Groups[0].Rules[0]
+		Expect(alert.Expr.String()).To(ContainSubstring(`pod=~"cluster-example-[0-9]+"`))
+		Expect(alert.Expr.String()).To(HaveSuffix("< 3"))
+		Expect(alert.Labels).To(HaveKeyWithValue("cluster", "cluster-example"))
+	})
+
+	It("uses the disk space warning threshold of the cluster", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				DiskSpace: &apiv1.DiskSpaceConfiguration{WarningThreshold: 70},
+			},
+		}
+		rule := CreatePrometheusRule(cluster)
+		Expect(rule.Spec.Groups[0].Rules[2].Alert).To(Equal("CNPGDiskSpaceLow"))
+		Expect(rule.Spec.Groups[0].Rules[2].Expr.String()).To(HaveSuffix("> 70"))
+	})
+
+	It("adds the backup alerts when backups are configured", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{},
+				},
+			},
+		}
+		Expect(getAlertNames(cluster)).To(ContainElements("CNPGArchivingFailing", "CNPGNoRecentBackup"))
+	})
+})
@@ -45,6 +45,17 @@ const (
 
 	// PVCStatusDetached is the annotation value for PVC detached status
 	PVCStatusDetached = "detached"
+
+	// PVCDetachedTimelineAnnotationName is the name of the annotation
+	// containing the timeline of the cluster when the PVC has been
+	// detached by a scale-down
+	PVCDetachedTimelineAnnotationName = MetadataNamespace + "/detachedTimeline"
+
+	// ReattachedAnnotationName is the name of the annotation marking
+	// the PVCs retained by a scale-down and then reattached, and the Pod
+	// created to use them. The instance manager aligns the data directory
+	// with the current timeline only when its Pod has this annotation
+	ReattachedAnnotationName = MetadataNamespace + "/reattached"
 )
 
 // ErrorInvalidSize is raised when the size specified by the
@@ -204,7 +215,7 @@ func DetectPVCs(
 instancesLoop:
 	for serial, pvcs := range instances {
 		instanceName := fmt.Sprintf("%s-%v", cluster.Name, serial)
-		expectedPVCs := getExpectedInstancePVCNames(cluster, instanceName)
+		expectedPVCs := GetExpectedInstancePVCNames(cluster, instanceName)
 		pvcNames := getNamesFromPVCList(pvcs)
 
 		// If we have less PVCs that the expected number, all the instance PVCs are unusable
@@ -317,12 +328,12 @@ func isResizing(pvc corev1.PersistentVolumeClaim) bool {
 
 // DoesPVCBelongToInstance returns a boolean indicating if that given PVC belongs to an instance
 func DoesPVCBelongToInstance(cluster *apiv1.Cluster, instanceName, resourceName string) bool {
-	expectedInstancePVCs := getExpectedInstancePVCNames(cluster, instanceName)
+	expectedInstancePVCs := GetExpectedInstancePVCNames(cluster, instanceName)
 	return slices.Contains(expectedInstancePVCs, resourceName)
 }
 
-// getExpectedInstancePVCNames gets all the PVC names for a given instance
-func getExpectedInstancePVCNames(cluster *apiv1.Cluster, instanceName string) []string {
+// GetExpectedInstancePVCNames gets all the PVC names for a given instance
+func GetExpectedInstancePVCNames(cluster *apiv1.Cluster, instanceName string) []string {
 	names := []string{instanceName}
 
 	if cluster.ShouldCreateWalArchiveVolume() {
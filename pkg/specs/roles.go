@@ -17,6 +17,8 @@ limitations under the License.
 package specs
 
 import (
+	"fmt"
+
 	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -49,11 +51,26 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 		for _, configMapName := range cluster.Spec.Monitoring.CustomQueriesConfigMap {
 			involvedConfigMapNames = append(involvedConfigMapNames, configMapName.Name)
 		}
+
+		// The bearer token protecting the metrics endpoint
+		if cluster.Spec.Monitoring.BearerTokenSecret != nil {
+			involvedSecretNames = append(involvedSecretNames, cluster.Spec.Monitoring.BearerTokenSecret.Name)
+		}
+	}
+
+	// The additional configuration files of PostgreSQL
+	for _, configMapName := range cluster.Spec.PostgresConfiguration.ConfigurationFiles {
+		involvedConfigMapNames = append(involvedConfigMapNames, configMapName.Name)
 	}
 
 	involvedSecretNames = append(involvedSecretNames, backupSecrets(cluster, backupOrigin)...)
 	involvedSecretNames = append(involvedSecretNames, externalClusterSecrets(cluster)...)
 
+	// The instance manager must be granted access to exactly the
+	// objects it needs, so we remove the empty and the duplicated names
+	involvedSecretNames = cleanupResourceNames(involvedSecretNames)
+	involvedConfigMapNames = cleanupResourceNames(involvedConfigMapNames)
+
 	rules := []rbacv1.PolicyRule{
 		{
 			APIGroups: []string{
@@ -91,6 +108,7 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 			Verbs: []string{
 				"get",
 				"list",
+				"patch",
 				"watch",
 			},
 			ResourceNames: []string{
@@ -152,6 +170,37 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 				"patch",
 			},
 		},
+		{
+			// The instance manager of the primary renews the heartbeat
+			// lease, which is created by the operator
+			APIGroups: []string{
+				"coordination.k8s.io",
+			},
+			Resources: []string{
+				"leases",
+			},
+			Verbs: []string{
+				"get",
+				"update",
+			},
+			ResourceNames: []string{
+				cluster.GetPrimaryHeartbeatLeaseName(),
+			},
+		},
+		{
+			// Each instance manager labels its own Pod with its role
+			APIGroups: []string{
+				"",
+			},
+			Resources: []string{
+				"pods",
+			},
+			Verbs: []string{
+				"get",
+				"patch",
+			},
+			ResourceNames: instancePodNames(cluster),
+		},
 	}
 
 	return rbacv1.Role{
@@ -163,6 +212,33 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 	}
 }
 
+// instancePodNames gets the names of the Pods of the instances, including
+// the one of the next instance to be created, so that its instance manager
+// is granted access to its Pod as soon as it starts
+func instancePodNames(cluster apiv1.Cluster) []string {
+	names := make([]string, 0, cluster.Status.LatestGeneratedNode+1)
+	for serial := 1; serial <= cluster.Status.LatestGeneratedNode+1; serial++ {
+		names = append(names, fmt.Sprintf("%s-%v", cluster.Name, serial))
+	}
+	return names
+}
+
+// cleanupResourceNames removes the empty and the duplicated entries
+// from a list of resource names, preserving their order
+func cleanupResourceNames(names []string) []string {
+	result := make([]string, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+
+	return result
+}
+
 func externalClusterSecrets(cluster apiv1.Cluster) []string {
 	var result []string
 
@@ -282,6 +358,14 @@ func s3CredentialsSecrets(s3Credentials *apiv1.S3Credentials) []string {
 		secrets = append(secrets, s3Credentials.SecretAccessKeyReference.Name)
 	}
 
+	if s3Credentials.RegionReference != nil {
+		secrets = append(secrets, s3Credentials.RegionReference.Name)
+	}
+
+	if s3Credentials.SessionToken != nil {
+		secrets = append(secrets, s3Credentials.SessionToken.Name)
+	}
+
 	return secrets
 }
 
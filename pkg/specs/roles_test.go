@@ -78,6 +78,20 @@ var _ = Describe("Roles", func() {
 				},
 			},
 			PostgresConfiguration: apiv1.PostgresConfiguration{
+				ConfigurationFiles: []apiv1.ConfigMapKeySelector{
+					{
+						LocalObjectReference: apiv1.LocalObjectReference{
+							Name: "testConfigurationFile",
+						},
+						Key: "custom.conf",
+					},
+					{
+						LocalObjectReference: apiv1.LocalObjectReference{
+							Name: "testConfigMapKeySelector",
+						},
+						Key: "shared.conf",
+					},
+				},
 				LDAP: &apiv1.LDAPConfig{
 					BindSearchAuth: &apiv1.LDAPBindSearchAuth{
 						BindPassword: &corev1.SecretKeySelector{
@@ -154,14 +168,33 @@ var _ = Describe("Roles", func() {
 		serviceAccount := CreateRole(cluster, nil)
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
-		Expect(len(serviceAccount.Rules)).To(Equal(7))
+		Expect(len(serviceAccount.Rules)).To(Equal(9))
+	})
+
+	It("grants access to the heartbeat lease of the primary", func() {
+		role := CreateRole(cluster, nil)
+		Expect(role.Rules[7].Resources).To(ConsistOf("leases"))
+		Expect(role.Rules[7].Verbs).To(ConsistOf("get", "update"))
+		Expect(role.Rules[7].ResourceNames).To(ConsistOf("thisTest-primary-heartbeat"))
+	})
+
+	It("grants access to the Pods of the instances", func() {
+		clusterWithInstances := cluster.DeepCopy()
+		clusterWithInstances.Status.LatestGeneratedNode = 2
+		role := CreateRole(*clusterWithInstances, nil)
+		Expect(role.Rules[8].Resources).To(ConsistOf("pods"))
+		Expect(role.Rules[8].ResourceNames).To(ConsistOf("thisTest-1", "thisTest-2", "thisTest-3"))
 	})
 
 	It("should contain every secret of the origin backup and backup configuration of every external cluster", func() {
 		serviceAccount := CreateRole(cluster, &backupOrigin)
 		Expect(serviceAccount.Name).To(Equal(cluster.Name))
 		Expect(serviceAccount.Namespace).To(Equal(cluster.Namespace))
-		Expect(serviceAccount.Rules[0].ResourceNames).To(ConsistOf("thisTest", "testConfigMapKeySelector"))
+		Expect(serviceAccount.Rules[0].ResourceNames).To(ConsistOf(
+			"thisTest",
+			"testConfigMapKeySelector",
+			"testConfigurationFile",
+		))
 		Expect(serviceAccount.Rules[1].ResourceNames).To(ConsistOf(
 			"testReplicationTLSSecret",
 			"testClientCASecret",
@@ -219,4 +252,75 @@ var _ = Describe("Secrets", func() {
 		secrets = backupSecrets(cluster, nil)
 		Expect(secrets).To(ConsistOf("test-secret", "test-access", "test-endpoint-ca-name"))
 	})
+
+	It("includes the region and the session token secrets", func() {
+		cluster.Spec = apiv1.ClusterSpec{
+			Backup: &apiv1.BackupConfiguration{
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					BarmanCredentials: apiv1.BarmanCredentials{
+						AWS: &apiv1.S3Credentials{
+							SecretAccessKeyReference: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "test-secret"},
+							},
+							AccessKeyIDReference: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "test-access"},
+							},
+							RegionReference: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "test-region"},
+							},
+							SessionToken: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "test-session-token"},
+							},
+						},
+					},
+				},
+			},
+		}
+		secrets := backupSecrets(cluster, nil)
+		Expect(secrets).To(ConsistOf("test-secret", "test-access", "test-region", "test-session-token"))
+	})
+
+	It("grants access to every secret exactly once", func() {
+		cluster.Spec = apiv1.ClusterSpec{
+			Backup: &apiv1.BackupConfiguration{
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					BarmanCredentials: apiv1.BarmanCredentials{
+						AWS: &apiv1.S3Credentials{
+							SecretAccessKeyReference: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "aws-creds"},
+								Key:                  "ACCESS_SECRET_KEY",
+							},
+							AccessKeyIDReference: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "aws-creds"},
+								Key:                  "ACCESS_KEY_ID",
+							},
+						},
+					},
+				},
+			},
+		}
+		role := CreateRole(cluster, nil)
+		Expect(role.Rules[1].ResourceNames).ToNot(ContainElement(""))
+		Expect(role.Rules[1].ResourceNames).To(ConsistOf(
+			"thisTest-replication",
+			"thisTest-ca",
+			"thisTest-server",
+			"thisTest-app",
+			"thisTest-superuser",
+			"aws-creds",
+		))
+	})
+
+	It("grants access to the secret containing the metrics bearer token", func() {
+		cluster.Spec = apiv1.ClusterSpec{
+			Monitoring: &apiv1.MonitoringConfiguration{
+				BearerTokenSecret: &apiv1.SecretKeySelector{
+					LocalObjectReference: apiv1.LocalObjectReference{Name: "metrics-token"},
+					Key:                  "token",
+				},
+			},
+		}
+		role := CreateRole(cluster, nil)
+		Expect(role.Rules[1].ResourceNames).To(ContainElement("metrics-token"))
+	})
 })
@@ -18,6 +18,8 @@ package specs
 
 import (
 	"fmt"
+	"net/url"
+	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,7 +27,9 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
-// CreateSecret create a secret with the PostgreSQL and the owner passwords
+// CreateSecret create a secret with the PostgreSQL and the owner passwords.
+// Besides the credentials, the secret contains the connection information
+// in the formats most commonly used by the applications
 func CreateSecret(
 	name string,
 	namespace string,
@@ -34,6 +38,13 @@ func CreateSecret(
 	username string,
 	password string,
 ) *corev1.Secret {
+	// The wildcard is only meaningful in the pgpass file, the
+	// connection strings use the default database instead
+	connectionDBName := dbname
+	if connectionDBName == "*" {
+		connectionDBName = "postgres"
+	}
+
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      name,
@@ -44,8 +55,15 @@ func CreateSecret(
 		},
 		Type: corev1.SecretTypeBasicAuth,
 		StringData: map[string]string{
+			// The type and the provider of the Service Binding specification
+			"type":     "postgresql",
+			"provider": "cloudnative-pg",
 			"username": username,
+			"user":     username,
 			"password": password,
+			"host":     hostname,
+			"port":     strconv.Itoa(postgres.ServerPort),
+			"dbname":   connectionDBName,
 			"pgpass": fmt.Sprintf(
 				"%v:%v:%v:%v:%v\n",
 				hostname,
@@ -53,6 +71,36 @@ func CreateSecret(
 				dbname,
 				username,
 				password),
+			"uri":      buildPostgresURI(hostname, connectionDBName, username, password),
+			"jdbc-uri": buildJDBCURI(hostname, connectionDBName, username, password),
 		},
 	}
 }
+
+// buildPostgresURI builds the libpq connection URI
+// corresponding to the passed connection information
+func buildPostgresURI(hostname, dbname, username, password string) string {
+	uri := url.URL{
+		Scheme: "postgresql",
+		User:   url.UserPassword(username, password),
+		Host:   fmt.Sprintf("%s:%d", hostname, postgres.ServerPort),
+		Path:   dbname,
+	}
+	return uri.String()
+}
+
+// buildJDBCURI builds the JDBC URL corresponding to the passed connection
+// information. The JDBC driver requires the credentials to be passed
+// as parameters
+func buildJDBCURI(hostname, dbname, username, password string) string {
+	uri := url.URL{
+		Scheme: "postgresql",
+		Host:   fmt.Sprintf("%s:%d", hostname, postgres.ServerPort),
+		Path:   dbname,
+		RawQuery: url.Values{
+			"user":     []string{username},
+			"password": []string{password},
+		}.Encode(),
+	}
+	return "jdbc:" + uri.String()
+}
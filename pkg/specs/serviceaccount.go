@@ -21,6 +21,9 @@ import (
 	"reflect"
 
 	corev1 "k8s.io/api/core/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 const (
@@ -76,8 +79,13 @@ func CreateManagedSecretsAnnotationValue(imagePullSecretsNames []string) (string
 
 // IsServiceAccountAligned compares the given list of pull secrets with the
 // ones managed by the operator inside the given ServiceAccount and returns
-// true when everything is aligned
-func IsServiceAccountAligned(sa *corev1.ServiceAccount, imagePullSecretsNames []string) (bool, error) {
+// true when everything is aligned. The labels and annotations coming from
+// the ServiceAccount template are checked too
+func IsServiceAccountAligned(
+	sa *corev1.ServiceAccount,
+	imagePullSecretsNames []string,
+	metadata apiv1.Metadata,
+) (bool, error) {
 	// This is an old version of the ServiceAccount, that need to be refreshed to
 	// store the annotation value
 	if sa.Annotations == nil {
@@ -94,5 +102,7 @@ func IsServiceAccountAligned(sa *corev1.ServiceAccount, imagePullSecretsNames []
 		return false, err
 	}
 
-	return reflect.DeepEqual(serviceAccountPullSecrets, imagePullSecretsNames), nil
+	return reflect.DeepEqual(serviceAccountPullSecrets, imagePullSecretsNames) &&
+		utils.IsMapSubset(sa.Labels, metadata.Labels) &&
+		utils.IsMapSubset(sa.Annotations, metadata.Annotations), nil
 }
@@ -19,6 +19,8 @@ package specs
 import (
 	v1 "k8s.io/api/core/v1"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -43,8 +45,8 @@ var _ = Describe("Service accounts", func() {
 			sa := &v1.ServiceAccount{}
 			err := UpdateServiceAccount([]string{"one", "two"}, sa)
 			Expect(err).To(BeNil())
-			Expect(IsServiceAccountAligned(sa, []string{"one", "two"})).To(BeTrue())
-			Expect(IsServiceAccountAligned(sa, []string{"one", "two", "three"})).To(BeFalse())
+			Expect(IsServiceAccountAligned(sa, []string{"one", "two"}, apiv1.Metadata{})).To(BeTrue())
+			Expect(IsServiceAccountAligned(sa, []string{"one", "two", "three"}, apiv1.Metadata{})).To(BeFalse())
 		})
 	})
 
@@ -60,8 +62,30 @@ var _ = Describe("Service accounts", func() {
 			})
 			Expect(err).To(BeNil())
 
-			Expect(IsServiceAccountAligned(sa, []string{"one", "two"})).To(BeTrue())
-			Expect(IsServiceAccountAligned(sa, []string{"one", "two", "three"})).To(BeFalse())
+			Expect(IsServiceAccountAligned(sa, []string{"one", "two"}, apiv1.Metadata{})).To(BeTrue())
+			Expect(IsServiceAccountAligned(sa, []string{"one", "two", "three"}, apiv1.Metadata{})).To(BeFalse())
+		})
+	})
+
+	When("the ServiceAccount template is changed", func() {
+		It("can detect that the ServiceAccount is needing a refresh", func() {
+			template := &apiv1.ServiceAccountTemplate{
+				Metadata: apiv1.Metadata{
+					Annotations: map[string]string{
+						"azure.workload.identity/client-id": "client-id",
+					},
+				},
+			}
+
+			sa := &v1.ServiceAccount{}
+			err := UpdateServiceAccount([]string{"one", "two"}, sa)
+			Expect(err).To(BeNil())
+			Expect(IsServiceAccountAligned(sa, []string{"one", "two"}, template.Metadata)).To(BeFalse())
+
+			template.MergeMetadata(sa)
+			Expect(sa.Annotations).To(HaveKeyWithValue("azure.workload.identity/client-id", "client-id"))
+			Expect(sa.Annotations).To(HaveKey(OperatorManagedSecretsName))
+			Expect(IsServiceAccountAligned(sa, []string{"one", "two"}, template.Metadata)).To(BeTrue())
 		})
 	})
 })
@@ -17,6 +17,8 @@ limitations under the License.
 package specs
 
 import (
+	"strconv"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -25,6 +27,16 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
+const (
+	// ExternalDNSHostnameAnnotationName is the annotation used by
+	// external-dns to publish the hostname of a service
+	ExternalDNSHostnameAnnotationName = "external-dns.alpha.kubernetes.io/hostname"
+
+	// ExternalDNSTTLAnnotationName is the annotation used by external-dns
+	// to set the TTL of the DNS record of a service
+	ExternalDNSTTLAnnotationName = "external-dns.alpha.kubernetes.io/ttl"
+)
+
 // CreateClusterAnyService create a service insisting on all the pods
 func CreateClusterAnyService(cluster apiv1.Cluster) *corev1.Service {
 	return &corev1.Service{
@@ -123,3 +135,46 @@ func CreateClusterReadWriteService(cluster apiv1.Cluster) *corev1.Service {
 		},
 	}
 }
+
+// CreateClusterReadWriteExternalService create a LoadBalancer service insisting
+// on the primary pod, annotated to be published by external-dns, or nil when
+// the cluster doesn't use external-dns
+func CreateClusterReadWriteExternalService(cluster apiv1.Cluster) *corev1.Service {
+	externalDNS := cluster.Spec.ExternalDNS
+	if externalDNS == nil {
+		return nil
+	}
+
+	annotations := make(map[string]string, len(externalDNS.ServiceAnnotations)+2)
+	for key, value := range externalDNS.ServiceAnnotations {
+		annotations[key] = value
+	}
+	annotations[ExternalDNSHostnameAnnotationName] = externalDNS.Hostname
+	if externalDNS.TTL != nil {
+		annotations[ExternalDNSTTLAnnotationName] = strconv.Itoa(int(*externalDNS.TTL))
+	}
+
+	return &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        cluster.GetServiceReadWriteExternalName(),
+			Namespace:   cluster.Namespace,
+			Annotations: annotations,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:                     corev1.ServiceTypeLoadBalancer,
+			LoadBalancerSourceRanges: externalDNS.LoadBalancerSourceRanges,
+			Ports: []corev1.ServicePort{
+				{
+					Name:       "postgres",
+					Protocol:   corev1.ProtocolTCP,
+					TargetPort: intstr.FromInt(postgres.ServerPort),
+					Port:       postgres.ServerPort,
+				},
+			},
+			Selector: map[string]string{
+				"postgresql":         cluster.Name,
+				ClusterRoleLabelName: ClusterRoleLabelPrimary,
+			},
+		},
+	}
+}
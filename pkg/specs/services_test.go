@@ -17,6 +17,7 @@ limitations under the License.
 package specs
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -61,4 +62,30 @@ var _ = Describe("Services specification", func() {
 		Expect(service.Spec.Selector["postgresql"]).To(Equal("clustername"))
 		Expect(service.Spec.Selector[ClusterRoleLabelName]).To(Equal(ClusterRoleLabelPrimary))
 	})
+	It("doesn't create the -rw-external service without external-dns", func() {
+		Expect(CreateClusterReadWriteExternalService(postgresql)).To(BeNil())
+	})
+
+	It("create a configured -rw-external service", func() {
+		ttl := int32(30)
+		cluster := postgresql.DeepCopy()
+		cluster.Spec.ExternalDNS = &apiv1.ExternalDNSConfiguration{
+			Hostname:                 "db.example.com",
+			TTL:                      &ttl,
+			ServiceAnnotations:       map[string]string{"service.beta.kubernetes.io/aws-load-balancer-type": "nlb"},
+			LoadBalancerSourceRanges: []string{"10.0.0.0/8"},
+		}
+
+		service := CreateClusterReadWriteExternalService(*cluster)
+		Expect(service.Name).To(Equal("clustername-rw-external"))
+		Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+		Expect(service.Spec.LoadBalancerSourceRanges).To(Equal([]string{"10.0.0.0/8"}))
+		Expect(service.Spec.Selector["postgresql"]).To(Equal("clustername"))
+		Expect(service.Spec.Selector[ClusterRoleLabelName]).To(Equal(ClusterRoleLabelPrimary))
+		Expect(service.Annotations).To(Equal(map[string]string{
+			ExternalDNSHostnameAnnotationName:                   "db.example.com",
+			ExternalDNSTTLAnnotationName:                        "30",
+			"service.beta.kubernetes.io/aws-load-balancer-type": "nlb",
+		}))
+	})
 })
@@ -18,10 +18,12 @@ package specs
 
 import (
 	"fmt"
+	"path"
 
 	corev1 "k8s.io/api/core/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/plugin"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
@@ -80,6 +82,17 @@ func createPostgresVolumes(cluster apiv1.Cluster, podName string) []corev1.Volum
 		)
 	}
 
+	if len(cluster.Spec.Plugins) > 0 {
+		result = append(result,
+			corev1.Volume{
+				Name: plugin.SocketVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					EmptyDir: &corev1.EmptyDirVolumeSource{},
+				},
+			},
+		)
+	}
+
 	if cluster.ShouldCreateWalArchiveVolume() {
 		result = append(result,
 			corev1.Volume{
@@ -95,17 +108,23 @@ func createPostgresVolumes(cluster apiv1.Cluster, podName string) []corev1.Volum
 	return result
 }
 
-func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
-	refs *apiv1.PostInitApplicationSQLRefs,
+// createVolumesAndVolumeMountsForSQLRefs creates the volumes and the
+// volume mounts needed to project the SQL files referenced in refs
+// into the passed folder. The files are numbered in the order
+// they need to be executed
+func createVolumesAndVolumeMountsForSQLRefs(
+	folder string,
+	refs *apiv1.SQLRefs,
 ) ([]corev1.Volume, []corev1.VolumeMount) {
 	length := len(refs.ConfigMapRefs) + len(refs.SecretRefs)
 	digitsCount := len(fmt.Sprintf("%d", length))
 	volumes := make([]corev1.Volume, 0, length)
 	volumeMounts := make([]corev1.VolumeMount, 0, length)
+	volumeSuffix := path.Base(folder)
 
 	for i := range refs.SecretRefs {
 		volumes = append(volumes, corev1.Volume{
-			Name: fmt.Sprintf("%0*d-post-init-application-sql", digitsCount, i),
+			Name: fmt.Sprintf("%0*d-%s", digitsCount, i, volumeSuffix),
 			VolumeSource: corev1.VolumeSource{
 				Secret: &corev1.SecretVolumeSource{
 					SecretName: refs.SecretRefs[i].Name,
@@ -120,8 +139,8 @@ func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 		})
 
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
-			Name:      fmt.Sprintf("%0*d-post-init-application-sql", digitsCount, i),
-			MountPath: fmt.Sprintf("%s/%0*d.sql", postInitApplicationSQLRefsFolder, digitsCount, i),
+			Name:      fmt.Sprintf("%0*d-%s", digitsCount, i, volumeSuffix),
+			MountPath: fmt.Sprintf("%s/%0*d.sql", folder, digitsCount, i),
 			SubPath:   fmt.Sprintf("%0*d.sql", digitsCount, i),
 			ReadOnly:  true,
 		})
@@ -129,7 +148,7 @@ func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 
 	for i := range refs.ConfigMapRefs {
 		volumes = append(volumes, corev1.Volume{
-			Name: fmt.Sprintf("%0*d-post-init-application-sql", digitsCount, i+len(refs.SecretRefs)),
+			Name: fmt.Sprintf("%0*d-%s", digitsCount, i+len(refs.SecretRefs), volumeSuffix),
 			VolumeSource: corev1.VolumeSource{
 				ConfigMap: &corev1.ConfigMapVolumeSource{
 					LocalObjectReference: corev1.LocalObjectReference{
@@ -146,8 +165,8 @@ func createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(
 		})
 
 		volumeMounts = append(volumeMounts, corev1.VolumeMount{
-			Name:      fmt.Sprintf("%0*d-post-init-application-sql", digitsCount, i+len(refs.SecretRefs)),
-			MountPath: fmt.Sprintf("%s/%0*d.sql", postInitApplicationSQLRefsFolder, digitsCount, i+len(refs.SecretRefs)),
+			Name:      fmt.Sprintf("%0*d-%s", digitsCount, i+len(refs.SecretRefs), volumeSuffix),
+			MountPath: fmt.Sprintf("%s/%0*d.sql", folder, digitsCount, i+len(refs.SecretRefs)),
 			SubPath:   fmt.Sprintf("%0*d.sql", digitsCount, i+len(refs.SecretRefs)),
 			ReadOnly:  true,
 		})
@@ -194,6 +213,15 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 		)
 	}
 
+	if len(cluster.Spec.Plugins) > 0 {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      plugin.SocketVolumeName,
+				MountPath: plugin.SocketDirectory,
+			},
+		)
+	}
+
 	if cluster.ShouldCreateWalArchiveVolume() {
 		volumeMounts = append(volumeMounts,
 			corev1.VolumeMount{
@@ -20,21 +20,22 @@ import (
 	corev1 "k8s.io/api/core/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/plugin"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
-var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs", func() {
+var _ = Describe("test createVolumesAndVolumeMountsForSQLRefs", func() {
 	It("input is empty", func() {
-		input := &apiv1.PostInitApplicationSQLRefs{}
-		volumes, volumeMounts := createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(input)
+		input := &apiv1.SQLRefs{}
+		volumes, volumeMounts := createVolumesAndVolumeMountsForSQLRefs(postInitApplicationSQLRefsFolder, input)
 		Expect(volumes).To(BeEmpty())
 		Expect(volumeMounts).To(BeEmpty())
 	})
 
 	It("we have reference to secrets only", func() {
-		input := &apiv1.PostInitApplicationSQLRefs{
+		input := &apiv1.SQLRefs{
 			SecretRefs: []apiv1.SecretKeySelector{
 				{
 					LocalObjectReference: apiv1.LocalObjectReference{
@@ -50,7 +51,7 @@ var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs
 				},
 			},
 		}
-		volumes, volumeMounts := createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(input)
+		volumes, volumeMounts := createVolumesAndVolumeMountsForSQLRefs(postInitApplicationSQLRefsFolder, input)
 		Expect(volumeMounts).To(Equal([]corev1.VolumeMount{
 			{
 				Name:      "0-post-init-application-sql",
@@ -99,7 +100,7 @@ var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs
 	})
 
 	It("we have reference to configmaps only", func() {
-		input := &apiv1.PostInitApplicationSQLRefs{
+		input := &apiv1.SQLRefs{
 			ConfigMapRefs: []apiv1.ConfigMapKeySelector{
 				{
 					LocalObjectReference: apiv1.LocalObjectReference{
@@ -115,7 +116,7 @@ var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs
 				},
 			},
 		}
-		volumes, volumeMounts := createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(input)
+		volumes, volumeMounts := createVolumesAndVolumeMountsForSQLRefs(postInitApplicationSQLRefsFolder, input)
 		Expect(volumeMounts).To(Equal([]corev1.VolumeMount{
 			{
 				Name:      "0-post-init-application-sql",
@@ -168,7 +169,7 @@ var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs
 	})
 
 	It("we have reference to both configmaps and secrets", func() {
-		input := &apiv1.PostInitApplicationSQLRefs{
+		input := &apiv1.SQLRefs{
 			SecretRefs: []apiv1.SecretKeySelector{
 				{
 					LocalObjectReference: apiv1.LocalObjectReference{
@@ -198,7 +199,7 @@ var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs
 				},
 			},
 		}
-		volumes, volumeMounts := createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(input)
+		volumes, volumeMounts := createVolumesAndVolumeMountsForSQLRefs(postInitApplicationSQLRefsFolder, input)
 		Expect(volumeMounts).To(Equal([]corev1.VolumeMount{
 			{
 				Name:      "0-post-init-application-sql",
@@ -290,3 +291,27 @@ var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs
 		}))
 	})
 })
+
+var _ = Describe("plugins socket volume", func() {
+	It("is not created when the cluster has no plugins", func() {
+		cluster := apiv1.Cluster{}
+		Expect(createPostgresVolumes(cluster, "pod-1")).ToNot(
+			ContainElement(HaveField("Name", plugin.SocketVolumeName)))
+		Expect(createPostgresVolumeMounts(cluster)).ToNot(
+			ContainElement(HaveField("Name", plugin.SocketVolumeName)))
+	})
+
+	It("is shared with the sidecars of the plugins", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Plugins: []apiv1.PluginConfiguration{{Name: "backup"}},
+			},
+		}
+		Expect(createPostgresVolumes(cluster, "pod-1")).To(
+			ContainElement(HaveField("Name", plugin.SocketVolumeName)))
+		Expect(createPostgresVolumeMounts(cluster)).To(ContainElement(corev1.VolumeMount{
+			Name:      plugin.SocketVolumeName,
+			MountPath: plugin.SocketDirectory,
+		}))
+	})
+})
@@ -0,0 +1,148 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ChaosExperimentAnnotationName is the name of the annotation requesting
+// a chaos experiment on an instance of the cluster. Its value is the JSON
+// representation of a ChaosExperiment, e.g.
+// `{"action":"killPostgres","instance":"cluster-example-1","startedAt":"2024-01-01T00:00:00Z","duration":"5m"}`
+const ChaosExperimentAnnotationName = "cnpg.io/chaosExperiment"
+
+// MaxChaosExperimentDuration is the maximum duration of a chaos experiment,
+// after which the simulated failure is automatically reverted
+const MaxChaosExperimentDuration = time.Hour
+
+// ErrorChaosExperimentSyntax is emitted when the chaos experiment annotation
+// has an invalid syntax
+var ErrorChaosExperimentSyntax = errors.New("chaosExperiment annotation has invalid syntax")
+
+// ChaosExperimentAction is the failure simulated by a chaos experiment
+type ChaosExperimentAction string
+
+const (
+	// ChaosExperimentKillPostgres kills the PostgreSQL server of the
+	// instance with SIGKILL, simulating a crash
+	ChaosExperimentKillPostgres ChaosExperimentAction = "killPostgres"
+
+	// ChaosExperimentPauseArchiving makes the WAL archiving of the
+	// instance fail for the duration of the experiment
+	ChaosExperimentPauseArchiving ChaosExperimentAction = "pauseArchiving"
+
+	// ChaosExperimentReplicationLag delays the replay of the WAL on a
+	// replica, using `recovery_min_apply_delay`, for the duration of
+	// the experiment
+	ChaosExperimentReplicationLag ChaosExperimentAction = "replicationLag"
+)
+
+// ChaosExperiment is a failure simulated on an instance, for a limited time
+type ChaosExperiment struct {
+	// The simulated failure
+	Action ChaosExperimentAction `json:"action"`
+
+	// The name of the instance where the failure is simulated
+	Instance string `json:"instance"`
+
+	// The time when the experiment starts
+	StartedAt metav1.Time `json:"startedAt"`
+
+	// The duration of the experiment, after which the failure is reverted
+	Duration metav1.Duration `json:"duration"`
+
+	// The replication lag injected by the `replicationLag` action
+	Delay metav1.Duration `json:"delay,omitempty"`
+}
+
+// Validate checks the chaos experiment
+func (experiment ChaosExperiment) Validate() error {
+	switch experiment.Action {
+	case ChaosExperimentKillPostgres, ChaosExperimentPauseArchiving:
+		if experiment.Delay.Duration != 0 {
+			return fmt.Errorf("the delay can only be set for the %s action", ChaosExperimentReplicationLag)
+		}
+	case ChaosExperimentReplicationLag:
+		if experiment.Delay.Duration <= 0 {
+			return fmt.Errorf("the %s action requires a positive delay", ChaosExperimentReplicationLag)
+		}
+	default:
+		return fmt.Errorf("unknown action %q", experiment.Action)
+	}
+
+	if experiment.Instance == "" {
+		return errors.New("the instance is required")
+	}
+
+	if experiment.StartedAt.IsZero() {
+		return errors.New("the start time is required")
+	}
+
+	if experiment.Duration.Duration <= 0 || experiment.Duration.Duration > MaxChaosExperimentDuration {
+		return fmt.Errorf("the duration must be positive and not greater than %v", MaxChaosExperimentDuration)
+	}
+
+	return nil
+}
+
+// IsActive checks if the experiment is running at the passed time
+func (experiment ChaosExperiment) IsActive(now time.Time) bool {
+	return !now.Before(experiment.StartedAt.Time) &&
+		now.Before(experiment.StartedAt.Add(experiment.Duration.Duration))
+}
+
+// GetChaosExperiment gets the chaos experiment requested in the
+// annotations, returning nil when there is none
+func GetChaosExperiment(annotations map[string]string) (*ChaosExperiment, error) {
+	value, ok := annotations[ChaosExperimentAnnotationName]
+	if !ok {
+		return nil, nil
+	}
+
+	var experiment ChaosExperiment
+	if err := json.Unmarshal([]byte(value), &experiment); err != nil {
+		return nil, ErrorChaosExperimentSyntax
+	}
+
+	return &experiment, nil
+}
+
+// SetChaosExperiment sets the chaos experiment inside the annotations,
+// removing it when nil
+func SetChaosExperiment(object *metav1.ObjectMeta, experiment *ChaosExperiment) error {
+	if experiment == nil {
+		delete(object.Annotations, ChaosExperimentAnnotationName)
+		return nil
+	}
+
+	annotationValue, err := json.Marshal(experiment)
+	if err != nil {
+		return err
+	}
+	if object.Annotations == nil {
+		object.Annotations = make(map[string]string)
+	}
+	object.Annotations[ChaosExperimentAnnotationName] = string(annotationValue)
+
+	return nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Chaos experiment annotation handling", func() {
+	startedAt := metav1.NewTime(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC))
+	experiment := ChaosExperiment{
+		Action:    ChaosExperimentReplicationLag,
+		Instance:  "cluster-example-2",
+		StartedAt: startedAt,
+		Duration:  metav1.Duration{Duration: 5 * time.Minute},
+		Delay:     metav1.Duration{Duration: 30 * time.Second},
+	}
+
+	It("stores and reads the experiment", func() {
+		clusterMeta := metav1.ObjectMeta{}
+		Expect(SetChaosExperiment(&clusterMeta, &experiment)).To(Succeed())
+		Expect(clusterMeta.Annotations).To(HaveKeyWithValue(ChaosExperimentAnnotationName,
+			`{"action":"replicationLag","instance":"cluster-example-2","startedAt":"2024-01-01T10:00:00Z",`+
+				`"duration":"5m0s","delay":"30s"}`))
+
+		result, err := GetChaosExperiment(clusterMeta.Annotations)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result.StartedAt.Equal(&experiment.StartedAt)).To(BeTrue())
+		result.StartedAt = experiment.StartedAt
+		Expect(*result).To(Equal(experiment))
+
+		Expect(SetChaosExperiment(&clusterMeta, nil)).To(Succeed())
+		Expect(clusterMeta.Annotations).ToNot(HaveKey(ChaosExperimentAnnotationName))
+		Expect(GetChaosExperiment(clusterMeta.Annotations)).To(BeNil())
+	})
+
+	It("reports invalid annotations", func() {
+		_, err := GetChaosExperiment(map[string]string{ChaosExperimentAnnotationName: "{"})
+		Expect(err).To(Equal(ErrorChaosExperimentSyntax))
+	})
+
+	It("validates the experiment", func() {
+		Expect(experiment.Validate()).To(Succeed())
+
+		invalid := experiment
+		invalid.Delay = metav1.Duration{}
+		Expect(invalid.Validate()).ToNot(Succeed())
+
+		invalid = experiment
+		invalid.Action = ChaosExperimentKillPostgres
+		Expect(invalid.Validate()).ToNot(Succeed())
+		invalid.Delay = metav1.Duration{}
+		Expect(invalid.Validate()).To(Succeed())
+
+		invalid.Action = "fillDisk"
+		Expect(invalid.Validate()).ToNot(Succeed())
+
+		invalid = experiment
+		invalid.Duration = metav1.Duration{Duration: 2 * time.Hour}
+		Expect(invalid.Validate()).ToNot(Succeed())
+
+		invalid = experiment
+		invalid.Instance = ""
+		Expect(invalid.Validate()).ToNot(Succeed())
+	})
+
+	It("is active only for its duration", func() {
+		Expect(experiment.IsActive(startedAt.Add(-time.Second))).To(BeFalse())
+		Expect(experiment.IsActive(startedAt.Time)).To(BeTrue())
+		Expect(experiment.IsActive(startedAt.Add(4 * time.Minute))).To(BeTrue())
+		Expect(experiment.IsActive(startedAt.Add(5 * time.Minute))).To(BeFalse())
+	})
+})
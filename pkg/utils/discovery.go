@@ -77,6 +77,16 @@ func HaveSecurityContextConstraints() bool {
 	return haveSCC
 }
 
+// PrometheusRuleExist tries to find the PrometheusRule resource in the current cluster
+func PrometheusRuleExist(client *discovery.DiscoveryClient) (bool, error) {
+	exist, err := resourceExist(client, "monitoring.coreos.com/v1", "prometheusrules")
+	if err != nil {
+		return false, err
+	}
+
+	return exist, nil
+}
+
 // PodMonitorExist tries to find the PodMonitor resource in the current cluster
 func PodMonitorExist(client *discovery.DiscoveryClient) (bool, error) {
 	exist, err := resourceExist(client, "monitoring.coreos.com/v1", "podmonitors")
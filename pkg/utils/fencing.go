@@ -53,6 +53,11 @@ const (
 	// FenceAllServers is the wildcard that, if put inside the fenced instances list, will fence every
 	// CNPG instance
 	FenceAllServers = "*"
+
+	// DataVerificationFencedInstanceAnnotation is the annotation containing the name of the instance
+	// which the instance manager fenced to verify the checksums of its data, and which will be
+	// unfenced at the end of the verification
+	DataVerificationFencedInstanceAnnotation = "cnpg.io/dataVerificationFencedInstance"
 )
 
 // GetFencedInstances gets the set of fenced servers from the annotations
@@ -55,6 +55,27 @@ const (
 
 	// ReconciliationDisabledValue it the value that stops the reconciliation loop
 	ReconciliationDisabledValue = "disabled"
+
+	// FailoverApprovalAnnotationName is the name of the annotation approving
+	// a failover when the cluster has a manual failover policy. Its value is
+	// the name of the instance to be promoted, or empty to promote the
+	// recommended one
+	FailoverApprovalAnnotationName = "cnpg.io/approveFailover"
+
+	// PluginNameAnnotationName is the name of the annotation marking
+	// the Services exposing a plugin in the operator namespace. Its
+	// value is the name of the plugin
+	PluginNameAnnotationName = "cnpg.io/pluginName"
+
+	// PluginPortAnnotationName is the name of the annotation containing
+	// the port of the Service where the plugin is listening, when
+	// the Service exposes more than one port
+	PluginPortAnnotationName = "cnpg.io/pluginPort"
+
+	// RetainPVCsFinalizerName is the name of the finalizer added to the
+	// clusters whose PVCs need to be released, instead of being
+	// garbage collected, when the cluster is deleted
+	RetainPVCsFinalizerName = "cnpg.io/retainPVCs"
 )
 
 // PodRole describes the Role of a given pod
@@ -48,8 +48,8 @@ func CollectDifferencesFromMaps(p1 map[string]string, p2 map[string]string) map[
 	return nil
 }
 
-// isMapSubset returns true if mapSubset is a subset of mapSet otherwise false
-func isMapSubset(mapSet map[string]string, mapSubset map[string]string) bool {
+// IsMapSubset returns true if mapSubset is a subset of mapSet otherwise false
+func IsMapSubset(mapSet map[string]string, mapSubset map[string]string) bool {
 	if len(mapSet) < len(mapSubset) {
 		return false
 	}
@@ -110,7 +110,7 @@ func IsLabelSubset(mapSet, clusterLabels, fixedInheritedLabels map[string]string
 		}
 	}
 
-	return isMapSubset(mapSet, mapToEvaluate)
+	return IsMapSubset(mapSet, mapToEvaluate)
 }
 
 // IsAnnotationSubset checks if a collection of annotations is a subset of another
@@ -133,7 +133,7 @@ func IsAnnotationSubset(mapSet, clusterAnnotations, fixedInheritedAnnotations ma
 		}
 	}
 
-	return isMapSubset(mapSet, mapToEvaluate)
+	return IsMapSubset(mapSet, mapToEvaluate)
 }
 
 // IsResourceSubset checks if some resource requirements are a subset of another